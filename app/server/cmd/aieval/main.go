@@ -0,0 +1,117 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat AI Evaluation - Compares two AI strategies over duplicate deals.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+)
+
+// evalConfig holds the evaluation configuration.
+type evalConfig struct {
+	A     string
+	B     string
+	Deals int
+	Seed  int64
+}
+
+// parseFlags parses command-line flags and returns an evalConfig.
+func parseFlags() *evalConfig {
+	cfg := &evalConfig{}
+
+	flag.StringVar(&cfg.A, "a", "strong", "Strategy A (beginner, club, strong, random)")
+	flag.StringVar(&cfg.B, "b", "club", "Strategy B (beginner, club, strong, random)")
+	flag.IntVar(&cfg.Deals, "deals", 1000, "Number of duplicate deals (6 games each)")
+	flag.Int64Var(&cfg.Seed, "seed", 0, "Random seed for deals and AI noise (0 = current time)")
+
+	flag.Parse()
+
+	return cfg
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	if cfg.Deals < 2 {
+		log.Fatalf("Invalid configuration: at least 2 deals are required")
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	a, err := ai.NewByName(cfg.A, rng)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	b, err := ai.NewByName(cfg.B, rng)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	log.Printf("Playing %d duplicate deals of %s against %s (seed %d)", cfg.Deals, cfg.A, cfg.B, cfg.Seed)
+	start := time.Now()
+
+	result, err := ai.PlayMatch(a, b, cfg.Deals, rng)
+	if err != nil {
+		log.Fatalf("Match failed: %v", err)
+	}
+
+	log.Printf("Finished %d games in %s", result.Games, time.Since(start).Round(time.Millisecond))
+	printReport(os.Stdout, cfg, result)
+}
+
+// printReport prints the statistics of both strategies and the difference.
+func printReport(f *os.File, cfg *evalConfig, result *ai.MatchResult) {
+	w := tabwriter.NewWriter(f, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Strategy\tPoints\tPoints/game\tDeclared\tWon\tWin rate\t")
+	for _, side := range []struct {
+		name  string
+		stats ai.MatchSide
+	}{{"A:" + cfg.A, result.A}, {"B:" + cfg.B, result.B}} {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%d\t%d\t%s\t\n",
+			side.name, side.stats.Points, 2*float64(side.stats.Points)/float64(result.Games),
+			side.stats.Declared, side.stats.Won, percent(side.stats.Won, side.stats.Declared))
+	}
+	w.Flush()
+
+	low, high := result.Interval()
+	fmt.Fprintf(f, "\nA - B: %+.1f points per game (95%% CI %+.1f .. %+.1f)\n", result.Mean, low, high)
+	switch {
+	case !result.Significant():
+		fmt.Fprintln(f, "No significant difference, play more deals")
+	case result.Mean > 0:
+		fmt.Fprintf(f, "%s is significantly stronger\n", cfg.A)
+	default:
+		fmt.Fprintf(f, "%s is significantly stronger\n", cfg.B)
+	}
+}
+
+// percent formats a ratio as percentage ("-" if the total is 0).
+func percent(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// factor is a component of the game level of a suit or Grand game.
+type factor struct {
+	name  string
+	value int
+}
+
+// printBreakdown prints the players, the contract and the score breakdown of a game.
+func printBreakdown(out io.Writer, g game, played *skat.Game) {
+	record := g.record
+	fmt.Fprintf(out, "Game %s\n", record.ID)
+	names := make([]string, 0, len(skat.AllPlayers))
+	for _, p := range skat.AllPlayers {
+		names = append(names, fmt.Sprintf("%s %s", p, record.Players[p]))
+	}
+	fmt.Fprintf(out, "Players:      %s\n", strings.Join(names, ", "))
+
+	switch {
+	case played.PassedIn:
+		fmt.Fprintf(out, "Result:       passed in\n")
+		return
+	case played.Contract == nil:
+		fmt.Fprintf(out, "Result:       not finished (%d moves)\n", len(record.Actions))
+		return
+	case played.Contract.GameType.IsRamsch():
+		printRamsch(out, g, played)
+		return
+	}
+
+	declarer := *played.Declarer
+	skatText := "skat picked up"
+	if !played.SkatPickedUp {
+		skatText = "hand game"
+	}
+	fmt.Fprintf(out, "Declarer:     %s %s, bid %d, %s\n", declarer, record.Players[declarer], played.Bidding.FinalBid, skatText)
+	fmt.Fprintf(out, "Contract:     %s (%s)\n", contractName(played.Contract), played.Contract.Code())
+	fmt.Fprintf(out, "Cards:        %s (with the skat)\n", cardCodes(played.DeclarerCards))
+
+	result := played.Result
+	if result == nil {
+		fmt.Fprintf(out, "Result:       not finished (%d of %d tricks)\n", len(played.Tricks), skat.TricksPerGame)
+		return
+	}
+
+	var value int
+	if played.Contract.GameType.IsNull() {
+		value = played.Contract.BaseValue()
+		fmt.Fprintf(out, "Game value:   %d (fixed, no multipliers)\n", value)
+	} else {
+		fmt.Fprintf(out, "Matadors:     %s\n", matadorText(result.Matadors))
+		level := 0
+		for _, f := range factors(played.Contract, result) {
+			fmt.Fprintf(out, "  %-22s +%d\n", f.name, f.value)
+			level += f.value
+		}
+		base := played.Contract.BaseValue()
+		value = base * level
+		fmt.Fprintf(out, "Game value:   %d x %d = %d\n", base, level, value)
+	}
+
+	if result.Overbid {
+		fmt.Fprintf(out, "Bid check:    OVERBID, game value %d < bid %d; lost with %d (lowest multiple of %d reaching the bid)\n",
+			value, result.BidValue, result.GameValue, played.Contract.BaseValue())
+	} else {
+		fmt.Fprintf(out, "Bid check:    ok, game value %d >= bid %d\n", value, result.BidValue)
+	}
+
+	outcome := "lost"
+	if result.DeclarerWon {
+		outcome = "won"
+	}
+	if played.Contract.GameType.IsNull() {
+		fmt.Fprintf(out, "Result:       %s with %d tricks\n", outcome, result.DeclarerTricks)
+	} else {
+		fmt.Fprintf(out, "Result:       %s with %d card points (%d in %d tricks, %d in the skat)%s\n", outcome,
+			result.DeclarerPoints, result.TrickPoints, result.DeclarerTricks, result.SkatPoints, extremes(result))
+	}
+	fmt.Fprintf(out, "Score:        %+d\n", result.Score)
+	printRecorded(out, g, result.Score)
+}
+
+// printRamsch prints the result of a Ramsch game.
+func printRamsch(out io.Writer, g game, played *skat.Game) {
+	fmt.Fprintln(out, "Contract:     Ramsch (all players passed)")
+	result := played.RamschResult
+	if result == nil {
+		fmt.Fprintf(out, "Result:       not finished (%d of %d tricks)\n", len(played.Tricks), skat.TricksPerGame)
+		return
+	}
+	for _, p := range skat.AllPlayers {
+		skatNote := ""
+		if p == result.SkatPlayer {
+			skatNote = fmt.Sprintf(" (%d in the skat)", result.SkatPoints)
+		}
+		fmt.Fprintf(out, "  %-22s %3d card points%s\n", p.String()+" "+g.record.Players[p], result.PlayerPoints[p], skatNote)
+	}
+	if result.Durchmarsch {
+		fmt.Fprintf(out, "Result:       Durchmarsch by %s\n", *result.DurchmarschPlayer)
+		fmt.Fprintf(out, "Score:        %+d\n", result.DurchmarschScore)
+		printRecorded(out, g, result.DurchmarschScore)
+		return
+	}
+	jungfrau := ""
+	if len(result.JungfrauPlayers) > 0 {
+		jungfrau = " (doubled: Jungfrau)"
+	}
+	losers := make([]string, len(result.Losers))
+	for i, p := range result.Losers {
+		losers[i] = p.String()
+	}
+	verb := "loses"
+	if len(losers) > 1 {
+		verb = "lose"
+	}
+	fmt.Fprintf(out, "Result:       %s %s%s\n", strings.Join(losers, " and "), verb, jungfrau)
+	fmt.Fprintf(out, "Score:        %+d\n", result.LoserScore)
+	printRecorded(out, g, result.LoserScore)
+}
+
+// printRecorded compares the score stated in the file with the engine's score.
+func printRecorded(out io.Writer, g game, score int) {
+	if g.recorded == nil {
+		return
+	}
+	if *g.recorded == score {
+		fmt.Fprintf(out, "Recorded:     %+d (matches)\n", *g.recorded)
+	} else {
+		fmt.Fprintf(out, "Recorded:     %+d (DIFFERS from the engine's %+d)\n", *g.recorded, score)
+	}
+}
+
+// factors returns the components of the game level of a suit or Grand game, in the
+// order of the Skat rules.
+func factors(contract *skat.Contract, result *skat.GameResult) []factor {
+	n := result.Matadors
+	if n < 0 {
+		n = -n
+	}
+	list := []factor{{"matadors", n}, {"game", 1}}
+	add := func(ok bool, name string) {
+		if ok {
+			list = append(list, factor{name, 1})
+		}
+	}
+	add(contract.Hand, "hand")
+	add(result.Schneider || contract.Schneider, "schneider")
+	add(contract.Schneider, "schneider announced")
+	add(result.Schwarz || contract.Schwarz, "schwarz")
+	add(contract.Schwarz, "schwarz announced")
+	add(contract.Ouvert, "ouvert")
+	return list
+}
+
+// matadorText describes the matadors, e.g. "with 2" or "without 1".
+func matadorText(matadors int) string {
+	if matadors < 0 {
+		return fmt.Sprintf("without %d", -matadors)
+	}
+	return fmt.Sprintf("with %d", matadors)
+}
+
+// extremes describes Schneider and Schwarz reached by either side.
+func extremes(result *skat.GameResult) string {
+	switch {
+	case result.Schwarz:
+		return " (schwarz)"
+	case result.Schneider:
+		return " (schneider)"
+	default:
+		return ""
+	}
+}
+
+// contractName returns the name of a contract, e.g. "Grand Hand Schneider".
+func contractName(c *skat.Contract) string {
+	name := c.GameType.String()
+	for _, m := range []struct {
+		set  bool
+		name string
+	}{{c.Hand, "Hand"}, {c.Ouvert, "Ouvert"}, {c.Schneider, "Schneider"}, {c.Schwarz, "Schwarz"}} {
+		if m.set {
+			name += " " + m.name
+		}
+	}
+	return name
+}
+
+// cardCodes returns the codes of cards separated by spaces.
+func cardCodes(cards []skat.Card) string {
+	codes := make([]string, len(cards))
+	for i, c := range cards {
+		codes[i] = c.Code()
+	}
+	return strings.Join(codes, " ")
+}
+
+// positions returns the declarer followed by the defenders.
+func positions(played *skat.Game) []skat.Player {
+	if played.Declarer == nil {
+		return skat.AllPlayers
+	}
+	list := []skat.Player{*played.Declarer}
+	for _, p := range skat.AllPlayers {
+		if p != *played.Declarer {
+			list = append(list, p)
+		}
+	}
+	return list
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// breakdown returns the printed breakdown of a record with the recorded score.
+func breakdown(t *testing.T, record *skat.GameRecord, recorded int) string {
+	t.Helper()
+	played, err := record.Replay(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printBreakdown(&out, game{record: record, recorded: &recorded}, played)
+	return out.String()
+}
+
+func TestBreakdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		lose     bool
+		recorded int
+		want     []string
+	}{
+		{"grand", false, 192, []string{
+			"Players:      Forehand anna, Middlehand ben, Rearhand carl",
+			"Declarer:     Forehand anna, bid 18, hand game",
+			"Contract:     Grand Hand (GH)",
+			"Matadors:     with 4",
+			"  matadors               +4\n  game                   +1\n  hand                   +1\n  schneider              +1\n  schwarz                +1\n",
+			"Game value:   24 x 8 = 192",
+			"Bid check:    ok, game value 192 >= bid 18",
+			"Result:       won with 120 card points (120 in 10 tricks, 0 in the skat) (schwarz)",
+			"Score:        +192",
+			"Recorded:     +192 (matches)",
+		}},
+		{"null", true, -35, []string{
+			"Contract:     Null Hand (NH)",
+			"Game value:   35 (fixed, no multipliers)",
+			"Result:       lost with 1 tricks",
+			"Score:        -70",
+			"Recorded:     -35 (DIFFERS from the engine's -70)",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := breakdown(t, recordtest.Grand(t, "g1", recordtest.Start, tt.lose), tt.recorded)
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("no %q in:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakdownUnfinished(t *testing.T) {
+	record := recordtest.Grand(t, "g1", recordtest.Start, false)
+	record.Actions = record.Actions[:8]
+	out := breakdown(t, record, 0)
+	if !strings.Contains(out, "Result:       not finished (1 of 10 tricks)") || strings.Contains(out, "Score:") {
+		t.Errorf("unfinished game printed:\n%s", out)
+	}
+
+	record.Actions = record.Actions[:2]
+	if out := breakdown(t, record, 0); !strings.Contains(out, "Result:       not finished (2 moves)") {
+		t.Errorf("game in the bidding printed:\n%s", out)
+	}
+}
+
+func TestFactors(t *testing.T) {
+	contract := &skat.Contract{GameType: skat.GameClubs, Hand: true, Schneider: true, Ouvert: false}
+	var names []string
+	for _, f := range factors(contract, &skat.GameResult{Matadors: -2}) {
+		names = append(names, f.name)
+	}
+	if got := strings.Join(names, ","); got != "matadors,game,hand,schneider,schneider announced" {
+		t.Errorf("factors() = %s", got)
+	}
+	if matadorText(-2) != "without 2" || matadorText(3) != "with 3" {
+		t.Errorf("matadorText() = %s, %s", matadorText(-2), matadorText(3))
+	}
+}
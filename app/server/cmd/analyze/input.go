@@ -0,0 +1,149 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/notation"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// game is a game to analyze with the score stated in its file, if any.
+type game struct {
+	record *skat.GameRecord
+	// recorded is the score stated in the file (nil = none)
+	recorded *int
+}
+
+// readFile reads all games of a file.
+func readFile(file, format string) ([]game, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+
+	if format == "auto" {
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case strings.EqualFold(filepath.Ext(file), ".json") || strings.HasPrefix(trimmed, "{"):
+			format = "json"
+		case strings.HasPrefix(trimmed, "(;"):
+			format = "iss"
+		default:
+			format = "notation"
+		}
+	}
+
+	var games []game
+	switch format {
+	case "json":
+		r, err := replay.Read(strings.NewReader(text))
+		if err != nil {
+			return nil, err
+		}
+		record, err := r.Record()
+		if err != nil {
+			return nil, err
+		}
+		g := game{record: record}
+		if r.Result != nil {
+			g.recorded = &r.Result.Score
+		} else if r.Ramsch != nil {
+			g.recorded = &r.Ramsch.Score
+		}
+		games = append(games, g)
+	case "iss":
+		games, err = readSummaries(text)
+	case "notation":
+		games, err = readNotation(text)
+	default:
+		return nil, fmt.Errorf("invalid format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	for i := range games {
+		if games[i].record.ID == "" {
+			games[i].record.ID = fmt.Sprintf("%s-%d", base, i+1)
+		}
+	}
+	return games, nil
+}
+
+// readSummaries reads ISS game records, one per line.
+func readSummaries(text string) ([]game, error) {
+	var games []game
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		summary, err := protocol.ParseGameSummary(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		record, err := summary.Record()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		score := summary.Result.Value
+		games = append(games, game{record: record, recorded: &score})
+	}
+	return games, nil
+}
+
+// readNotation reads games in notation. A tag line after moves starts the next game.
+func readNotation(text string) ([]game, error) {
+	var games []game
+	var lines []string
+	inMoves := false
+
+	flush := func() error {
+		if !inMoves {
+			return nil
+		}
+		g, err := notation.Parse(strings.Join(lines, "\n"))
+		if err != nil {
+			return fmt.Errorf("game %d: %w", len(games)+1, err)
+		}
+		games = append(games, game{record: g.Record})
+		lines, inMoves = nil, false
+		return nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		} else if trimmed != "" && !strings.HasPrefix(trimmed, ";") {
+			inMoves = true
+		}
+		lines = append(lines, line)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return games, nil
+}
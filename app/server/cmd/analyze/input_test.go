@@ -0,0 +1,128 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/notation"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// writeFile writes a test file and returns its path.
+func writeFile(t *testing.T, name, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadFile(t *testing.T) {
+	won := recordtest.Grand(t, "g1", recordtest.Start, false)
+	lost := recordtest.Grand(t, "g2", recordtest.Start, true)
+
+	r, err := replay.New(won)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jsonText bytes.Buffer
+	if err := r.Write(&jsonText); err != nil {
+		t.Fatal(err)
+	}
+
+	var summaries []string
+	for _, record := range []*skat.GameRecord{won, lost} {
+		summary, err := protocol.NewGameSummary(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		summaries = append(summaries, summary.Encode())
+	}
+
+	unnamed := *won
+	unnamed.ID = ""
+	anonymous, err := protocol.NewGameSummary(&unnamed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var notationText []string
+	for _, record := range []*skat.GameRecord{won, lost} {
+		g, err := notation.New(record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		notationText = append(notationText, g.String())
+	}
+
+	tests := []struct {
+		name   string
+		text   string
+		ids    []string
+		scores []int // nil = no recorded scores
+	}{
+		{"game.json", jsonText.String(), []string{"g1"}, []int{192}},
+		{"games.txt", strings.Join(summaries, "\n") + "\n\n", []string{"g1", "g2"}, []int{192, -70}},
+		{"games.skat", strings.Join(notationText, "\n"), []string{"g1", "g2"}, nil},
+		// Games without ID are named after the file
+		{"unnamed.txt", anonymous.Encode(), []string{"unnamed-1"}, []int{192}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games, err := readFile(writeFile(t, tt.name, tt.text), "auto")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(games) != len(tt.ids) {
+				t.Fatalf("read %d games, want %d", len(games), len(tt.ids))
+			}
+			for i, g := range games {
+				if g.record.ID != tt.ids[i] {
+					t.Errorf("game %d has the ID %s, want %s", i+1, g.record.ID, tt.ids[i])
+				}
+				if len(g.record.Actions) != len([]*skat.GameRecord{won, lost}[i].Actions) {
+					t.Errorf("game %d has %d moves", i+1, len(g.record.Actions))
+				}
+				switch {
+				case tt.scores == nil && g.recorded != nil:
+					t.Errorf("game %d has the recorded score %d", i+1, *g.recorded)
+				case tt.scores != nil && (g.recorded == nil || *g.recorded != tt.scores[i]):
+					t.Errorf("game %d has the recorded score %v, want %d", i+1, g.recorded, tt.scores[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	if _, err := readFile(writeFile(t, "g.txt", "(;GM[Skat]broken"), "auto"); err == nil || !strings.HasPrefix(err.Error(), "line 1: ") {
+		t.Errorf("invalid ISS record = %v, want an error of line 1", err)
+	}
+	if _, err := readFile(writeFile(t, "g.txt", "x"), "pdf"); err == nil {
+		t.Error("readFile() accepted an invalid format")
+	}
+	if _, err := readFile(filepath.Join(t.TempDir(), "missing.json"), "auto"); err == nil {
+		t.Error("readFile() read a missing file")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Analyze - Replays games through the engine and explains their scores.
+//
+// Games are read from files (FreeSkat JSON replays, ISS game records or notation) or
+// from an archive. For each game the score breakdown is printed: matadors, the game
+// level with all multipliers, the overbid check and the result; optionally followed by
+// the card plays the solver finds to lose points against best play.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/pkg/solver"
+)
+
+// analyzeConfig holds the analyzer configuration.
+type analyzeConfig struct {
+	Format   string
+	Archive  string
+	Mistakes int
+	Args     []string
+}
+
+// parseFlags parses command-line flags and returns an analyzeConfig.
+func parseFlags() *analyzeConfig {
+	cfg := &analyzeConfig{}
+
+	flag.StringVar(&cfg.Format, "format", "auto", "Input format (auto, json, iss, notation)")
+	flag.StringVar(&cfg.Archive, "archive", "", "Read the games with the given IDs from this archive directory instead of files")
+	flag.IntVar(&cfg.Mistakes, "mistakes", 0, "Report card plays losing at least this many card points against best play (0 = no mistake report)")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <file>...\n       %s -archive <dir> [flags] <game id>...\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	cfg.Args = flag.Args()
+
+	return cfg
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	if len(cfg.Args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if cfg.Mistakes < 0 {
+		log.Fatalf("Invalid configuration: invalid mistake threshold %d", cfg.Mistakes)
+	}
+
+	var games []game
+	if cfg.Archive != "" {
+		a, err := archive.Open(cfg.Archive)
+		if err != nil {
+			log.Fatalf("Failed to open archive: %v", err)
+		}
+		for _, id := range cfg.Args {
+			record, err := a.Load(id)
+			if err != nil {
+				log.Fatalf("Game %s: %v", id, err)
+			}
+			games = append(games, game{record: record})
+		}
+	} else {
+		for _, file := range cfg.Args {
+			read, err := readFile(file, cfg.Format)
+			if err != nil {
+				log.Fatalf("%s: %v", file, err)
+			}
+			games = append(games, read...)
+		}
+	}
+
+	failed := 0
+	for i, g := range games {
+		if i > 0 {
+			fmt.Println()
+		}
+		if err := analyze(os.Stdout, g, cfg.Mistakes); err != nil {
+			log.Printf("Game %s: %v", g.record.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// analyze prints the score breakdown of a game and, if minLoss is positive, its mistakes.
+func analyze(out *os.File, g game, minLoss int) error {
+	played, err := g.record.Replay(nil)
+	if err != nil {
+		return err
+	}
+	printBreakdown(out, g, played)
+
+	if minLoss <= 0 || !played.State.IsFinished() {
+		return nil
+	}
+	mistakes, err := solver.Analyze(g.record, minLoss)
+	if errors.Is(err, solver.ErrNotSupported) {
+		fmt.Fprintln(out, "Mistakes:     not analyzed (Ramsch)")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("mistake analysis: %w", err)
+	}
+	fmt.Fprintf(out, "Mistakes:     card plays losing %d+ card points against best play\n", minLoss)
+	for _, p := range positions(played) {
+		for _, line := range solver.Report(mistakes, p) {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// game tracks a single game at a table from the bot's point of view.
+type game struct {
+	player       ai.AIPlayer
+	position     *skat.Player
+	hand         *skat.Hand
+	bidding      *skat.BiddingState
+	declarer     *skat.Player
+	contract     *skat.Contract
+	trick        *skat.Trick
+	awaitingSkat bool
+	announced    bool
+	over         bool
+	// clocks are the remaining thinking times of the players in a timed game (nil if not timed)
+	clocks []time.Duration
+}
+
+// newGame creates a new game tracker using the given AI player.
+func newGame(player ai.AIPlayer) *game {
+	return &game{
+		player:  player,
+		hand:    skat.NewHand(),
+		bidding: skat.NewBiddingState(),
+	}
+}
+
+// apply applies a move received from the server and returns the bot's
+// next move token, or "" if it is not the bot's turn.
+func (g *game) apply(movePlayer skat.MovePlayer, token string) (string, error) {
+	if g.over {
+		return "", nil
+	}
+
+	player, isPlayer := movePlayer.ToPlayer()
+	if !isPlayer {
+		if err := g.applyWorldMove(token); err != nil {
+			return "", err
+		}
+		return g.nextMove(), nil
+	}
+
+	info, err := protocol.ParseMove(token)
+	if err != nil {
+		return "", err
+	}
+
+	switch info.MoveType {
+	case protocol.MoveBid:
+		err = g.bidding.Bid(player, info.BidValue)
+	case protocol.MoveHoldBid:
+		err = g.bidding.Hold(player)
+	case protocol.MovePass:
+		err = g.bidding.Pass(player)
+	case protocol.MoveSkatRequest:
+		// Skat cards follow as world move (visible to the declarer only)
+	case protocol.MoveGameAnnouncement:
+		g.declarer = &player
+		g.contract = &skat.Contract{
+			GameType:  info.GameType,
+			Hand:      info.Hand,
+			Ouvert:    info.Ouvert,
+			Schneider: info.Schneider,
+			Schwarz:   info.Schwarz,
+		}
+		g.trick = skat.NewTrick(skat.Forehand)
+	case protocol.MoveCardPlay:
+		err = g.applyCardPlay(player, *info.Card)
+	default:
+		// Show cards, resign, time out or leave end the game
+		g.over = true
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if g.bidding.Result == skat.BidResultAllPassed {
+		g.over = true
+		return "", nil
+	}
+	return g.nextMove(), nil
+}
+
+// applyWorldMove applies a move of the server (deal or skat cards).
+func (g *game) applyWorldMove(token string) error {
+	if strings.Contains(token, "|") {
+		hands, _, err := protocol.ParseDealCards(token)
+		if err != nil {
+			return err
+		}
+		// The bot's own hand is the only one not hidden
+		for _, p := range skat.AllPlayers {
+			if hands[p].Size() > 0 {
+				position := p
+				g.position = &position
+				g.hand = hands[p]
+			}
+		}
+		if g.position == nil {
+			return fmt.Errorf("no visible hand in deal: %s", token)
+		}
+		return nil
+	}
+
+	if !g.awaitingSkat {
+		return nil
+	}
+
+	skatCards, err := skat.HandFromCode(token)
+	if err != nil {
+		return err
+	}
+	for _, c := range skatCards.Cards {
+		g.hand.Add(c)
+	}
+	g.awaitingSkat = false
+	return nil
+}
+
+// applyCardPlay adds a played card to the current trick.
+func (g *game) applyCardPlay(player skat.Player, card skat.Card) error {
+	if g.trick == nil || g.contract == nil {
+		return fmt.Errorf("card play before game announcement: %s", card.Code())
+	}
+	if err := g.trick.AddCard(card, player); err != nil {
+		return err
+	}
+	if g.isMe(player) {
+		g.hand.Remove(card)
+	}
+
+	if g.trick.IsComplete() {
+		winner, err := g.trick.DetermineWinner(g.contract.GameType)
+		if err != nil {
+			return err
+		}
+		g.trick = skat.NewTrick(winner)
+	}
+	return nil
+}
+
+// isMe returns true if the given player is the bot.
+func (g *game) isMe(player skat.Player) bool {
+	return g.position != nil && *g.position == player
+}
+
+// setClocks takes the remaining thinking times of the players in seconds from a move
+// of a timed game ("play <player> <move> <time0> <time1> <time2>").
+func (g *game) setClocks(times []string) error {
+	if len(times) != len(skat.AllPlayers) {
+		return nil
+	}
+	clocks := make([]time.Duration, len(times))
+	for i, t := range times {
+		seconds, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return fmt.Errorf("invalid thinking time: %s", t)
+		}
+		clocks[i] = time.Duration(seconds * float64(time.Second))
+	}
+	g.clocks = clocks
+	return nil
+}
+
+// nextMove returns the bot's next move token, or "" if it is not the bot's turn. In a
+// timed game, a deadline AI decides within its share of the remaining thinking time.
+func (g *game) nextMove() string {
+	if g.position == nil {
+		return ""
+	}
+	player := g.player
+	if deadline, ok := player.(*ai.DeadlineAI); ok && g.clocks != nil {
+		timed, cancel := deadline.WithClock(g.clocks[g.position.Index()], g.hand.Size())
+		defer cancel()
+		player = timed
+	}
+
+	// Bidding
+	if !g.bidding.IsDone() {
+		if !g.isMe(g.bidding.ActivePlayer) {
+			return ""
+		}
+		return g.decideBid(player)
+	}
+
+	// Skat pickup and announcement
+	if g.contract == nil {
+		if g.bidding.Declarer == nil || !g.isMe(*g.bidding.Declarer) || g.awaitingSkat || g.announced {
+			return ""
+		}
+		if g.hand.Size() == 12 {
+			return g.decideAnnouncement(player)
+		}
+		if player.DecidePickUpSkat(g.hand) {
+			g.awaitingSkat = true
+			return protocol.TokenSkatRequest
+		}
+		g.announced = true
+		return player.DecideAnnouncement(g.hand, g.bidding.FinalBid, true).Code()
+	}
+
+	// Trick playing
+	next := g.trick.NextPlayer()
+	if next == nil || !g.isMe(*next) || g.hand.Size() == 0 {
+		return ""
+	}
+	card := player.SelectCard(g.hand, ai.PlayContext{
+		Player:   *g.position,
+		Declarer: g.declarer,
+		GameType: g.contract.GameType,
+		Trick:    g.trick,
+	})
+	return card.Code()
+}
+
+// decideBid returns the bot's bidding move token.
+func (g *game) decideBid(player ai.AIPlayer) string {
+	decision := player.DecideBid(g.hand, ai.BidContext{
+		Player:     *g.position,
+		CurrentBid: g.bidding.CurrentBid,
+		Bidding:    g.bidding.IsActiveBidding,
+	})
+
+	switch decision.Action {
+	case ai.BidActionBid:
+		if decision.Value > g.bidding.CurrentBid && skat.IsValidBid(decision.Value) {
+			return strconv.Itoa(decision.Value)
+		}
+		return protocol.TokenPass
+	case ai.BidActionHold:
+		if g.bidding.IsActiveBidding {
+			return protocol.TokenPass
+		}
+		return protocol.TokenHoldBid
+	default:
+		return protocol.TokenPass
+	}
+}
+
+// decideAnnouncement discards two cards and returns the announcement token (e.g. "G.C7.C8").
+func (g *game) decideAnnouncement(player ai.AIPlayer) string {
+	gameType := ai.EvaluateHand(g.hand).BestGameType
+	discards := player.SelectDiscards(g.hand, gameType)
+	for _, c := range discards {
+		g.hand.Remove(c)
+	}
+
+	g.announced = true
+	contract := player.DecideAnnouncement(g.hand, g.bidding.FinalBid, false)
+	return fmt.Sprintf("%s.%s.%s", contract.Code(), discards[0].Code(), discards[1].Code())
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Bot - An AI client for ISS-compatible Skat servers.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// botConfig holds the bot configuration.
+type botConfig struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Table      string
+	Difficulty string
+	Engine     string
+	EngineAddr string
+	MoveTime   time.Duration
+	Games      int
+}
+
+// parseFlags parses command-line flags and returns a botConfig.
+func parseFlags() *botConfig {
+	cfg := &botConfig{}
+
+	flag.StringVar(&cfg.Host, "host", "localhost", "Server host to connect to")
+	flag.IntVar(&cfg.Port, "port", 7000, "Server TCP port")
+	flag.StringVar(&cfg.Username, "user", "bot", "Login name")
+	flag.StringVar(&cfg.Password, "password", "bot", "Login password")
+	flag.StringVar(&cfg.Table, "table", "", "Table to join (creates a new table if empty)")
+	flag.StringVar(&cfg.Difficulty, "difficulty", ai.DifficultyClub.String(), "AI difficulty (beginner, club, strong)")
+	flag.StringVar(&cfg.Engine, "engine", "", "External engine command to play with (see docs/AI-BRIDGE.md)")
+	flag.StringVar(&cfg.EngineAddr, "engine-addr", "", "TCP address of an external engine to play with")
+	flag.DurationVar(&cfg.MoveTime, "move-time", 5*time.Second, "Maximum thinking time per decision, a fast heuristic decides afterwards (0 = unlimited; timed tables also limit it to a share of the remaining time)")
+	flag.IntVar(&cfg.Games, "games", 0, "Number of games to play before leaving (0 = unlimited)")
+
+	flag.Parse()
+
+	return cfg
+}
+
+// bot is a single connected bot client.
+type bot struct {
+	config  *botConfig
+	conn    net.Conn
+	writer  *bufio.Writer
+	player  ai.AIPlayer
+	table   string
+	game    *game
+	played  int
+	ready   bool
+	running bool
+}
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+
+	cfg := parseFlags()
+
+	difficulty, err := ai.ParseDifficulty(cfg.Difficulty)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	player, err := newPlayer(cfg, difficulty)
+	if err != nil {
+		log.Fatalf("Failed to start external engine: %v", err)
+	}
+	if external, ok := player.(*ai.ExternalAI); ok {
+		defer external.Close()
+	}
+	// Also without a move time, the bot decides within its share of the clock at timed tables
+	player = ai.NewDeadlineAI(player, cfg.MoveTime)
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", address, err)
+	}
+	log.Printf("FreeSkat Bot '%s' (%s) connected to %s", cfg.Username, player.Name(), address)
+
+	b := &bot{
+		config:  cfg,
+		conn:    conn,
+		writer:  bufio.NewWriter(conn),
+		player:  player,
+		table:   cfg.Table,
+		running: true,
+	}
+
+	// Leave the table on shutdown signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		log.Println("Received shutdown signal")
+		b.leave()
+		conn.Close()
+	}()
+
+	if err := b.run(); err != nil {
+		log.Printf("Connection closed: %v", err)
+	}
+}
+
+// newPlayer creates the AI player: an external engine if configured, otherwise a built-in AI.
+func newPlayer(cfg *botConfig, difficulty ai.Difficulty) (ai.AIPlayer, error) {
+	switch {
+	case cfg.Engine != "":
+		parts := strings.Fields(cfg.Engine)
+		return ai.StartExternalAI(parts[0], parts[1:]...)
+	case cfg.EngineAddr != "":
+		return ai.DialExternalAI(cfg.EngineAddr)
+	default:
+		return ai.New(difficulty, nil), nil
+	}
+}
+
+// run reads and handles server messages until the connection is closed.
+func (b *bot) run() error {
+	reader := bufio.NewReader(b.conn)
+
+	for b.running {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		msg := protocol.ParseMessage(line)
+		if msg.Command == "" {
+			continue
+		}
+
+		if err := b.handleMessage(msg); err != nil {
+			log.Printf("Error handling message '%s': %v", msg.Raw, err)
+		}
+	}
+	return nil
+}
+
+// send writes a single line to the server.
+func (b *bot) send(format string, args ...interface{}) error {
+	line := fmt.Sprintf(format, args...)
+	log.Printf("Sending: %s", line)
+
+	if _, err := b.writer.WriteString(line + "\n"); err != nil {
+		return err
+	}
+	return b.writer.Flush()
+}
+
+// handleMessage processes a single server message.
+func (b *bot) handleMessage(msg *protocol.Message) error {
+	switch msg.Command {
+	case protocol.MsgVersion:
+		return b.send("%s %s %s", protocol.CmdLogin, b.config.Username, b.config.Password)
+	case protocol.MsgPassword:
+		log.Printf("Logged in as '%s'", b.config.Username)
+		if b.table != "" {
+			return b.send("%s %s", protocol.CmdJoin, b.table)
+		}
+		return b.send("%s / 3", protocol.CmdCreate)
+	case protocol.CmdCreate:
+		// create <table> <creator> <size>
+		if len(msg.Args) >= 2 && msg.Args[1] == b.config.Username && b.table == "" {
+			b.table = msg.Args[0]
+			log.Printf("Created table %s", b.table)
+			return b.sendReady()
+		}
+	case protocol.MsgTable:
+		return b.handleTableMessage(msg.Args)
+	case protocol.MsgError:
+		log.Printf("Server error: %s", msg.Raw)
+	}
+	return nil
+}
+
+// handleTableMessage processes "table <name> <login> <action> ..." messages.
+func (b *bot) handleTableMessage(args []string) error {
+	if len(args) < 3 || args[0] != b.table {
+		return nil
+	}
+
+	switch args[2] {
+	case protocol.TableActionState:
+		if b.game == nil && !b.ready {
+			return b.sendReady()
+		}
+	case protocol.TableActionStart:
+		b.game = newGame(b.player)
+		b.ready = false
+	case protocol.TableActionPlay:
+		if b.game == nil || len(args) < 5 {
+			return nil
+		}
+		movePlayer, err := skat.MovePlayerFromCode(args[3])
+		if err != nil {
+			return err
+		}
+		if err := b.game.setClocks(args[5:]); err != nil {
+			return err
+		}
+		move, err := b.game.apply(movePlayer, args[4])
+		if err != nil {
+			return err
+		}
+		if move != "" {
+			return b.sendTable("%s %s", protocol.TableActionPlay, move)
+		}
+	case protocol.TableActionEnd:
+		b.played++
+		b.game = nil
+		log.Printf("Game %d finished", b.played)
+		if b.config.Games > 0 && b.played >= b.config.Games {
+			b.leave()
+			b.running = false
+			return nil
+		}
+		return b.sendReady()
+	case protocol.TableActionDestroy:
+		log.Printf("Table %s destroyed", b.table)
+		b.running = false
+	}
+	return nil
+}
+
+// sendTable sends a table command for the bot's table.
+func (b *bot) sendTable(format string, args ...interface{}) error {
+	return b.send("%s %s %s %s", protocol.MsgTable, b.table, b.config.Username, fmt.Sprintf(format, args...))
+}
+
+// sendReady signals that the bot is ready for the next game.
+func (b *bot) sendReady() error {
+	b.ready = true
+	return b.sendTable(protocol.TableActionReady)
+}
+
+// leave leaves the current table, if any.
+func (b *bot) leave() {
+	if b.table == "" {
+		return
+	}
+	if err := b.sendTable(protocol.TableActionLeave); err != nil {
+		log.Printf("Failed to leave table %s: %v", b.table, err)
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Control - Operates a running FreeSkat server through the admin REST API.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ctlConfig holds the tool configuration.
+type ctlConfig struct {
+	URL   string
+	Token string
+	JSON  bool
+	Watch time.Duration
+}
+
+// parseFlags parses command-line flags and returns a ctlConfig.
+func parseFlags() *ctlConfig {
+	cfg := &ctlConfig{}
+
+	flag.StringVar(&cfg.URL, "url", envOr("FREESKAT_URL", "http://localhost:8080"), "URL of the REST API (env FREESKAT_URL)")
+	flag.StringVar(&cfg.Token, "token", os.Getenv("FREESKAT_ADMIN_TOKEN"), "Admin token of the server (env FREESKAT_ADMIN_TOKEN)")
+	flag.BoolVar(&cfg.JSON, "json", false, "Print the responses as JSON")
+	flag.DurationVar(&cfg.Watch, "watch", 0, "Repeat the metrics command at this interval, e.g. 5s (0 = once)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <command> [arguments]\n\n%s\nFlags:\n", os.Args[0], usageText)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	return cfg
+}
+
+// usageText lists the commands.
+const usageText = `Commands:
+  sessions                  List the connected sessions
+  kick <session id|login>   Disconnect a session or all sessions of a login
+  tables                    List the running tables
+  close <player>            Close the table of a player
+  bans                      List the banned logins
+  ban <login> [reason]      Ban a login and disconnect its sessions
+  unban <login>             Lift the ban of a login
+  mutes                     List the logins muted by the chat moderation
+  unmute <login>            Lift the mute of a login
+  broadcast <text>          Send a message to all logged-in clients
+  backup                    Write a backup of the game archive on the server
+  metrics                   Show the server metrics (tail them with -watch)
+`
+
+// envOr returns the environment variable key, or fallback if it is not set.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	if cfg.Token == "" {
+		log.Fatalf("Invalid configuration: admin token (-token or FREESKAT_ADMIN_TOKEN) required")
+	}
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctl := &ctl{client: newAdminClient(cfg.URL, cfg.Token), json: cfg.JSON, out: os.Stdout}
+	if err := ctl.run(args[0], args[1:], cfg.Watch); err != nil {
+		log.Fatalf("%s: %v", args[0], err)
+	}
+}
+
+// ctl runs the commands.
+type ctl struct {
+	client *adminClient
+	json   bool
+	out    *os.File
+}
+
+// run runs a command with its arguments.
+func (c *ctl) run(command string, args []string, watch time.Duration) error {
+	switch command {
+	case "sessions":
+		return c.sessions()
+	case "kick":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: kick <session id|login>")
+		}
+		return c.kick(args[0])
+	case "tables":
+		return c.tables()
+	case "close":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: close <player>")
+		}
+		return c.done(c.client.call(http.MethodDelete, "/tables/"+escape(args[0]), nil, nil), "Closed the table of %s", args[0])
+	case "bans":
+		return c.bans()
+	case "ban":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: ban <login> [reason]")
+		}
+		body := map[string]string{"reason": strings.Join(args[1:], " ")}
+		return c.done(c.client.call(http.MethodPut, "/bans/"+escape(args[0]), body, nil), "Banned %s", args[0])
+	case "unban":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: unban <login>")
+		}
+		return c.done(c.client.call(http.MethodDelete, "/bans/"+escape(args[0]), nil, nil), "Unbanned %s", args[0])
+	case "mutes":
+		return c.mutes()
+	case "unmute":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: unmute <login>")
+		}
+		return c.done(c.client.call(http.MethodDelete, "/mutes/"+escape(args[0]), nil, nil), "Unmuted %s", args[0])
+	case "broadcast":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: broadcast <text>")
+		}
+		var result struct {
+			Sent int `json:"sent"`
+		}
+		if err := c.client.call(http.MethodPost, "/broadcast", map[string]string{"text": strings.Join(args, " ")}, &result); err != nil {
+			return err
+		}
+		return c.print(result, func() { fmt.Fprintf(c.out, "Sent to %d client(s)\n", result.Sent) })
+	case "backup":
+		var result struct {
+			File  string `json:"file"`
+			Files int    `json:"files"`
+			Bytes int64  `json:"bytes"`
+		}
+		if err := c.client.call(http.MethodPost, "/backup", nil, &result); err != nil {
+			return err
+		}
+		return c.print(result, func() {
+			fmt.Fprintf(c.out, "Backup %s: %d files, %d bytes\n", result.File, result.Files, result.Bytes)
+		})
+	case "metrics":
+		if watch <= 0 {
+			return c.metrics(true)
+		}
+		for first := true; ; first = false {
+			if err := c.metrics(first); err != nil {
+				return err
+			}
+			time.Sleep(watch)
+		}
+	default:
+		return fmt.Errorf("unknown command (see -help)")
+	}
+}
+
+// session is a connected session.
+type session struct {
+	ID        string `json:"id"`
+	Login     string `json:"login"`
+	Remote    string `json:"remote"`
+	Transport string `json:"transport"`
+	Client    *struct {
+		Name     string   `json:"name"`
+		Version  string   `json:"version"`
+		Features []string `json:"features"`
+	} `json:"client"`
+	Traffic    traffic   `json:"traffic"`
+	Connected  time.Time `json:"connected"`
+	LastActive time.Time `json:"lastActive"`
+}
+
+// traffic is the traffic of a session or the server.
+type traffic struct {
+	BytesIn  int64 `json:"bytesIn"`
+	BytesOut int64 `json:"bytesOut"`
+	LinesIn  int64 `json:"linesIn"`
+	LinesOut int64 `json:"linesOut"`
+}
+
+// size formats a number of bytes.
+func size(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fM", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fK", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+// listSessions returns the connected sessions.
+func (c *ctl) listSessions() ([]session, error) {
+	var result struct {
+		Sessions []session `json:"sessions"`
+	}
+	err := c.client.call(http.MethodGet, "/sessions", nil, &result)
+	return result.Sessions, err
+}
+
+// sessions prints the connected sessions.
+func (c *ctl) sessions() error {
+	sessions, err := c.listSessions()
+	if err != nil {
+		return err
+	}
+	return c.print(sessions, func() {
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tLOGIN\tREMOTE\tCLIENT\tIN\tOUT\tCONNECTED\tIDLE")
+		for _, s := range sessions {
+			login := s.Login
+			if login == "" {
+				login = "-"
+			}
+			client := s.Transport
+			if s.Client != nil {
+				client = strings.Join(append([]string{s.Client.Name + "/" + s.Client.Version, client}, s.Client.Features...), " ")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%s\t%d/%s\t%s\t%s\n", s.ID, login, s.Remote, client,
+				s.Traffic.LinesIn, size(s.Traffic.BytesIn), s.Traffic.LinesOut, size(s.Traffic.BytesOut),
+				s.Connected.Local().Format(time.DateTime), time.Since(s.LastActive).Round(time.Second))
+		}
+		w.Flush()
+	})
+}
+
+// kick disconnects a session, or all sessions of a login.
+func (c *ctl) kick(target string) error {
+	sessions, err := c.listSessions()
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, s := range sessions {
+		if s.ID == target || s.Login == target {
+			ids = append(ids, s.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("no session %s", target)
+	}
+	for _, id := range ids {
+		if err := c.client.call(http.MethodDelete, "/sessions/"+escape(id), nil, nil); err != nil {
+			return err
+		}
+		if !c.json {
+			fmt.Fprintf(c.out, "Kicked %s\n", id)
+		}
+	}
+	return nil
+}
+
+// tables prints the running tables.
+func (c *ctl) tables() error {
+	var result struct {
+		Tables []struct {
+			Table     string   `json:"table"`
+			Player    string   `json:"player"`
+			Session   string   `json:"session"`
+			Game      string   `json:"game"`
+			Players   []string `json:"players"`
+			Observers int      `json:"observers"`
+		} `json:"tables"`
+	}
+	if err := c.client.call(http.MethodGet, "/tables", nil, &result); err != nil {
+		return err
+	}
+	return c.print(result.Tables, func() {
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TABLE\tPLAYER\tSESSION\tPLAYERS\tOBSERVERS\tGAME")
+		for _, t := range result.Tables {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", t.Table, t.Player, t.Session, strings.Join(t.Players, ","), t.Observers, t.Game)
+		}
+		w.Flush()
+	})
+}
+
+// bans prints the banned logins.
+func (c *ctl) bans() error {
+	var result struct {
+		Bans []struct {
+			Login  string    `json:"login"`
+			Reason string    `json:"reason"`
+			Time   time.Time `json:"time"`
+		} `json:"bans"`
+	}
+	if err := c.client.call(http.MethodGet, "/bans", nil, &result); err != nil {
+		return err
+	}
+	return c.print(result.Bans, func() {
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "LOGIN\tSINCE\tREASON")
+		for _, b := range result.Bans {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", b.Login, b.Time.Local().Format(time.DateTime), b.Reason)
+		}
+		w.Flush()
+	})
+}
+
+// mutes prints the muted logins.
+func (c *ctl) mutes() error {
+	var result struct {
+		Mutes []struct {
+			Login  string    `json:"login"`
+			Reason string    `json:"reason"`
+			Until  time.Time `json:"until"`
+		} `json:"mutes"`
+	}
+	if err := c.client.call(http.MethodGet, "/mutes", nil, &result); err != nil {
+		return err
+	}
+	return c.print(result.Mutes, func() {
+		w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "LOGIN\tUNTIL\tREASON")
+		for _, m := range result.Mutes {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", m.Login, m.Until.Local().Format(time.DateTime), m.Reason)
+		}
+		w.Flush()
+	})
+}
+
+// metrics prints the server metrics as a line of a table, with the header if header
+// is true, so repeated calls tail them.
+func (c *ctl) metrics(header bool) error {
+	var m struct {
+		Uptime     string  `json:"uptime"`
+		Sessions   int     `json:"sessions"`
+		LoggedIn   int     `json:"loggedIn"`
+		Tables     int     `json:"tables"`
+		Observers  int     `json:"observers"`
+		Games      int     `json:"games"`
+		Goroutines int     `json:"goroutines"`
+		HeapBytes  uint64  `json:"heapBytes"`
+		Traffic    traffic `json:"traffic"`
+	}
+	if err := c.client.call(http.MethodGet, "/metrics", nil, &m); err != nil {
+		return err
+	}
+	return c.print(m, func() {
+		if header {
+			fmt.Fprintf(c.out, "%-8s  %-10s  %8s  %8s  %6s  %9s  %7s  %10s  %8s  %8s  %8s\n",
+				"TIME", "UPTIME", "SESSIONS", "LOGGEDIN", "TABLES", "OBSERVERS", "GAMES", "GOROUTINES", "HEAP", "IN", "OUT")
+		}
+		fmt.Fprintf(c.out, "%-8s  %-10s  %8d  %8d  %6d  %9d  %7d  %10d  %7.1fM  %8s  %8s\n",
+			time.Now().Format(time.TimeOnly), m.Uptime, m.Sessions, m.LoggedIn, m.Tables, m.Observers, m.Games,
+			m.Goroutines, float64(m.HeapBytes)/(1<<20), size(m.Traffic.BytesIn), size(m.Traffic.BytesOut))
+	})
+}
+
+// done prints the confirmation of a successful command without output.
+func (c *ctl) done(err error, format string, args ...any) error {
+	if err == nil && !c.json {
+		fmt.Fprintf(c.out, format+"\n", args...)
+	}
+	return err
+}
+
+// print prints v as JSON line with -json, otherwise as text.
+func (c *ctl) print(v any, text func()) error {
+	if !c.json {
+		text()
+		return nil
+	}
+	return json.NewEncoder(c.out).Encode(v)
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/api"
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/ban"
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	serversession "github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+)
+
+// newTestServer starts the REST API with the admin endpoints of a server where the
+// logins are connected.
+func newTestServer(t *testing.T, token string, logins ...string) (*httptest.Server, *serversession.Manager) {
+	t.Helper()
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := games.Save(recordtest.Played(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	bans, err := ban.Open(filepath.Join(t.TempDir(), "bans.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := serversession.NewManager(context.Background())
+	for _, login := range logins {
+		server, client := net.Pipe()
+		t.Cleanup(func() { client.Close() })
+		go io.Copy(io.Discard, client)
+		m.Login(m.CreateSession(server), login)
+	}
+	a := api.New(games, nil)
+	a.SetAdmin(token, m, protocol.NewHandler(m, nil))
+	a.SetBans(bans)
+	a.SetBackupDir(filepath.Join(t.TempDir(), "backups"))
+	server := httptest.NewServer(a)
+	t.Cleanup(server.Close)
+	return server, m
+}
+
+// runCommand runs a command of the tool and returns its output.
+func runCommand(t *testing.T, client *adminClient, line string) (string, error) {
+	t.Helper()
+	out, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	args := strings.Fields(line)
+	err = (&ctl{client: client, out: out}).run(args[0], args[1:], 0)
+	text, _ := os.ReadFile(out.Name())
+	return string(text), err
+}
+
+func TestCommands(t *testing.T) {
+	server, m := newTestServer(t, "t0ken", "anna", "ben")
+	client := newAdminClient(server.URL+"/", "t0ken")
+
+	commands := []struct {
+		line string
+		want string
+	}{
+		{"sessions", "anna"},
+		{"broadcast Restart at  noon", "Sent to 2 client(s)"},
+		{"ban anna cheating at the table", "Banned anna"},
+		{"bans", "cheating at the table"},
+		{"kick ben", "Kicked session-2"},
+		{"backup", ": 1 files"},
+		{"unban anna", "Unbanned anna"},
+		{"metrics", "0"},
+	}
+	for _, c := range commands {
+		out, err := runCommand(t, client, c.line)
+		if err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !strings.Contains(out, c.want) {
+			t.Errorf("%s printed %q, want %q", c.line, out, c.want)
+		}
+	}
+	// The ban and the kick disconnected both players
+	if sessions := m.List(); len(sessions) != 0 {
+		t.Errorf("%d sessions left", len(sessions))
+	}
+
+	failures := []struct {
+		line string
+		want string
+	}{
+		{"unban anna", "login not banned (404 Not Found)"},
+		{"kick carl", "no session carl"},
+		{"ban", "usage: ban <login> [reason]"},
+		{"shutdown", "unknown command"},
+	}
+	for _, e := range failures {
+		if _, err := runCommand(t, client, e.line); err == nil || !strings.Contains(err.Error(), e.want) {
+			t.Errorf("%s = %v, want %q", e.line, err, e.want)
+		}
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	server, _ := newTestServer(t, "t0ken")
+	if _, err := runCommand(t, newAdminClient(server.URL, "wrong"), "bans"); err == nil || !strings.Contains(err.Error(), "invalid admin token (401") {
+		t.Errorf("bans with a wrong token = %v", err)
+	}
+
+	disabled, _ := newTestServer(t, "")
+	if _, err := runCommand(t, newAdminClient(disabled.URL, "t0ken"), "bans"); err == nil || !strings.Contains(err.Error(), "admin API not enabled") {
+		t.Errorf("bans without admin API = %v", err)
+	}
+}
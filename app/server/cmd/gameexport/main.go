@@ -0,0 +1,295 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Game Export - Exports archived games for websites and analysis tools.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/dataset"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/notation"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/scoresheet"
+	"github.com/mkloubert/freeskat-server/pkg/stats"
+)
+
+// exportConfig holds the export configuration.
+type exportConfig struct {
+	Archive    string
+	ID         string
+	Format     string
+	List       bool
+	CSV        string
+	Prefix     string
+	Player     string
+	Verify     bool
+	Duplicate  string
+	Tournament string
+	Dataset    bool
+	Timestamps bool
+	Comments   bool
+}
+
+// parseFlags parses command-line flags and returns an exportConfig.
+func parseFlags() *exportConfig {
+	cfg := &exportConfig{}
+
+	flag.StringVar(&cfg.Archive, "archive", "archive", "Game archive directory")
+	flag.StringVar(&cfg.ID, "id", "", "ID of the game to export")
+	flag.StringVar(&cfg.Format, "format", "json", "Export format (json, iss, notation)")
+	flag.BoolVar(&cfg.List, "list", false, "List the IDs of all archived games")
+	flag.StringVar(&cfg.CSV, "csv", "", "Export a CSV report (sheet, standings, stats)")
+	flag.StringVar(&cfg.Prefix, "prefix", "", "Only include games whose ID starts with this prefix (sheet, standings)")
+	flag.StringVar(&cfg.Player, "player", "", "Only include games of this player (sheet, standings)")
+	flag.BoolVar(&cfg.Verify, "verify", false, "Verify that the recorded seed reproduces the deal of the game")
+	flag.StringVar(&cfg.Duplicate, "duplicate", "", "Export the comparative scores of the duplicate set defined in this JSON file as CSV")
+
+	flag.StringVar(&cfg.Tournament, "tournament", "", "Export the result list of this tournament in the DSKV format")
+	flag.BoolVar(&cfg.Dataset, "dataset", false, "Export all public games anonymized as JSON lines for research datasets")
+	flag.BoolVar(&cfg.Timestamps, "dataset-timestamps", false, "Include game start and move times in the dataset")
+	flag.BoolVar(&cfg.Comments, "dataset-comments", false, "Include post-game comments in the dataset")
+
+	flag.Parse()
+
+	return cfg
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+
+	games, err := archive.Open(cfg.Archive)
+	if err != nil {
+		log.Fatalf("Failed to open archive: %v", err)
+	}
+
+	if cfg.List {
+		ids, err := games.IDs()
+		if err != nil {
+			log.Fatalf("Failed to list games: %v", err)
+		}
+		for _, id := range ids {
+			fmt.Println(id)
+		}
+		return
+	}
+
+	if cfg.CSV != "" {
+		if err := exportCSV(games, cfg); err != nil {
+			log.Fatalf("Failed to export %s: %v", cfg.CSV, err)
+		}
+		return
+	}
+
+	if cfg.Duplicate != "" {
+		if err := exportDuplicate(games, cfg.Duplicate); err != nil {
+			log.Fatalf("Failed to export duplicate set: %v", err)
+		}
+		return
+	}
+
+	if cfg.Tournament != "" {
+		if err := exportTournament(games, cfg); err != nil {
+			log.Fatalf("Failed to export tournament %s: %v", cfg.Tournament, err)
+		}
+		return
+	}
+
+	if cfg.Dataset {
+		count, err := exportDataset(games, cfg)
+		if err != nil {
+			log.Fatalf("Failed to export dataset: %v", err)
+		}
+		log.Printf("Exported %d games", count)
+		return
+	}
+
+	if cfg.ID == "" {
+		log.Fatalf("Invalid configuration: -id, -list, -csv, -duplicate, -tournament or -dataset is required")
+	}
+	if cfg.Verify {
+		if err := verify(games, cfg.ID); err != nil {
+			log.Fatalf("Game %s: %v", cfg.ID, err)
+		}
+		return
+	}
+	if err := export(games, cfg); err != nil {
+		log.Fatalf("Failed to export game %s: %v", cfg.ID, err)
+	}
+}
+
+// export writes the game in the configured format to stdout.
+func export(games *archive.Archive, cfg *exportConfig) error {
+	switch cfg.Format {
+	case "json":
+		r, err := games.Replay(cfg.ID)
+		if err != nil {
+			return err
+		}
+		return r.Write(os.Stdout)
+	case "iss":
+		record, err := games.Load(cfg.ID)
+		if err != nil {
+			return err
+		}
+		summary, err := protocol.NewGameSummary(record)
+		if err != nil {
+			return err
+		}
+		fmt.Println(summary.Encode())
+		return nil
+	case "notation":
+		record, err := games.Load(cfg.ID)
+		if err != nil {
+			return err
+		}
+		g, err := notation.New(record)
+		if err != nil {
+			return err
+		}
+		return g.Write(os.Stdout)
+	default:
+		return fmt.Errorf("invalid format: %s", cfg.Format)
+	}
+}
+
+// verify checks that the recorded seed of the game reproduces its deal.
+func verify(games *archive.Archive, id string) error {
+	record, err := games.Load(id)
+	if err != nil {
+		return err
+	}
+	if err := record.CheckDeal(); err != nil {
+		return err
+	}
+	fmt.Printf("Game %s: deal reproduced by seed %d (engine version %d)\n", id, record.Shuffle.Seed, record.Engine)
+	return nil
+}
+
+// exportDuplicate writes the comparative scores of the archived games of a duplicate set.
+func exportDuplicate(games *archive.Archive, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	setConfig, err := duplicate.ReadConfig(f)
+	if err != nil {
+		return err
+	}
+	set, err := duplicate.NewSet(setConfig)
+	if err != nil {
+		return err
+	}
+
+	records, err := games.Records(archive.Filter{Prefix: setConfig.Name + "-"})
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := set.Add(record); err != nil && !errors.Is(err, duplicate.ErrUnknownGame) {
+			log.Printf("Skipping game %s: %v", record.ID, err)
+		}
+	}
+
+	scores, err := set.Scores()
+	if err != nil {
+		return err
+	}
+	return duplicate.WriteScoresCSV(os.Stdout, scores)
+}
+
+// exportTournament writes the result list of a tournament in the DSKV format to stdout.
+// The tournaments are read from the "tournaments" directory of the archive.
+func exportTournament(games *archive.Archive, cfg *exportConfig) error {
+	store, err := tournament.Open(filepath.Join(cfg.Archive, "tournaments"))
+	if err != nil {
+		return err
+	}
+	t, err := store.Get(cfg.Tournament)
+	if err != nil {
+		return err
+	}
+	records, err := games.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		return err
+	}
+	rows, err := t.ResultList(records)
+	if err != nil {
+		return err
+	}
+	return scoresheet.WriteList(os.Stdout, rows)
+}
+
+// exportDataset writes all public games anonymized to stdout and returns their number.
+func exportDataset(games *archive.Archive, cfg *exportConfig) (int, error) {
+	ids, err := games.IDs()
+	if err != nil {
+		return 0, err
+	}
+
+	replays := []*replay.Replay{}
+	for _, id := range ids {
+		if games.IsPrivate(id) {
+			continue
+		}
+		r, err := games.Replay(id)
+		if err != nil {
+			return 0, err
+		}
+		replays = append(replays, r)
+	}
+	return dataset.Export(os.Stdout, replays, dataset.Options{Timestamps: cfg.Timestamps, Comments: cfg.Comments})
+}
+
+// exportCSV writes the configured CSV report to stdout. Private games are included.
+func exportCSV(games *archive.Archive, cfg *exportConfig) error {
+	if cfg.CSV == "stats" {
+		collector, err := games.Stats()
+		if err != nil {
+			return err
+		}
+		return stats.WriteCSV(os.Stdout, collector.Reports())
+	}
+
+	records, err := games.Records(archive.Filter{Prefix: cfg.Prefix, Player: cfg.Player})
+	if err != nil {
+		return err
+	}
+	sheet, err := scoresheet.New(records)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.CSV {
+	case "sheet":
+		return sheet.WriteCSV(os.Stdout)
+	case "standings":
+		return scoresheet.WriteStandingsCSV(os.Stdout, sheet.Standings())
+	default:
+		return fmt.Errorf("invalid CSV report: %s", cfg.CSV)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// jskatFile is a JSkat save file: a <jskat> root with one <skatGameData> per game.
+type jskatFile struct {
+	Games []jskatGame `xml:"skatGameData"`
+}
+
+// jskatGame is the XML form of JSkat's SkatGameData.
+type jskatGame struct {
+	ID       string         `xml:"id,attr"`
+	Date     string         `xml:"date,attr"`
+	Players  []jskatPlayer  `xml:"player"`
+	Skat     string         `xml:"skat"`
+	Declarer string         `xml:"declarer"`
+	Contract *jskatContract `xml:"contract"`
+	Discard  string         `xml:"discard"`
+	Tricks   []jskatTrick   `xml:"trick"`
+}
+
+// jskatPlayer is a player with the dealt cards and the values bid or held.
+type jskatPlayer struct {
+	Position string `xml:"position,attr"`
+	Name     string `xml:"name,attr"`
+	Cards    string `xml:"cards"`
+	Bids     string `xml:"bids"`
+}
+
+// jskatContract is JSkat's GameContract with the Kontra and Re flags.
+type jskatContract struct {
+	GameType  string `xml:"gameType,attr"`
+	Hand      bool   `xml:"hand,attr"`
+	Schneider bool   `xml:"schneider,attr"`
+	Schwarz   bool   `xml:"schwarz,attr"`
+	Ouvert    bool   `xml:"ouvert,attr"`
+	Contra    bool   `xml:"contra,attr"`
+	Re        bool   `xml:"re,attr"`
+}
+
+// jskatTrick holds the cards of a trick in the order they were played.
+type jskatTrick struct {
+	Forehand string `xml:"forehand,attr"`
+	Cards    string `xml:",chardata"`
+}
+
+// jskatPositions are the player positions by their JSkat names.
+var jskatPositions = map[string]skat.Player{
+	"FOREHAND":   skat.Forehand,
+	"MIDDLEHAND": skat.Middlehand,
+	"REARHAND":   skat.Rearhand,
+}
+
+// jskatGameTypes are the game types by their JSkat names (PASSED_IN is a thrown-in deal).
+var jskatGameTypes = map[string]skat.GameType{
+	"CLUBS":    skat.GameClubs,
+	"SPADES":   skat.GameSpades,
+	"HEARTS":   skat.GameHearts,
+	"DIAMONDS": skat.GameDiamonds,
+	"GRAND":    skat.GameGrand,
+	"NULL":     skat.GameNull,
+	"RAMSCH":   skat.GameRamsch,
+}
+
+// readJSkat reads the games of a JSkat save file. A file may also hold a single
+// <skatGameData> root.
+func readJSkat(data []byte) ([]*skat.GameRecord, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	var file jskatFile
+	if root.XMLName.Local == "skatGameData" {
+		file.Games = make([]jskatGame, 1)
+		if err := xml.Unmarshal(data, &file.Games[0]); err != nil {
+			return nil, err
+		}
+	} else if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Games) == 0 {
+		return nil, errors.New("no skatGameData elements")
+	}
+
+	records := make([]*skat.GameRecord, 0, len(file.Games))
+	for i, g := range file.Games {
+		record, err := g.record()
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// record replays the game and returns its record, so games with illegal moves are rejected.
+func (g *jskatGame) record() (*skat.GameRecord, error) {
+	var startedAt time.Time
+	if g.Date != "" {
+		var err error
+		if startedAt, err = time.Parse(time.RFC3339, g.Date); err != nil {
+			return nil, fmt.Errorf("invalid date: %w", err)
+		}
+	}
+	if g.Contract == nil {
+		return nil, errors.New("no contract")
+	}
+
+	names := make(map[skat.Player]string)
+	hands := make(map[skat.Player]*skat.Hand)
+	bids := make(map[skat.Player][]int)
+	for _, p := range g.Players {
+		player, ok := jskatPositions[p.Position]
+		if !ok {
+			return nil, fmt.Errorf("invalid player position: %q", p.Position)
+		}
+		cards, err := jskatCards(p.Cards)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", player, err)
+		}
+		for _, field := range strings.Fields(p.Bids) {
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid bid: %s", player, field)
+			}
+			bids[player] = append(bids[player], value)
+		}
+		names[player] = p.Name
+		hands[player] = skat.NewHandFromCards(cards)
+	}
+	skatCards, err := jskatCards(g.Skat)
+	if err != nil {
+		return nil, fmt.Errorf("skat: %w", err)
+	}
+
+	game := skat.NewGame()
+	if g.Contract.GameType == "PASSED_IN" {
+		game.AllPass = skat.AllPassThrowIn
+	}
+	if err := game.Deal(hands, skat.NewHandFromCards(skatCards)); err != nil {
+		return nil, err
+	}
+	if err := jskatBidding(game, bids); err != nil {
+		return nil, err
+	}
+	if err := g.declare(game); err != nil {
+		return nil, err
+	}
+	if err := g.play(game); err != nil {
+		return nil, err
+	}
+	return skat.NewGameRecord(g.ID, startedAt, names, game)
+}
+
+// jskatBidding replays the bidding from the values each player bid or held, in order:
+// the active player bids its next value if it is above the current bid, holds if it is
+// the current bid, and passes otherwise.
+func jskatBidding(game *skat.Game, bids map[skat.Player][]int) error {
+	for game.State == skat.StateBidding {
+		b := game.Bidding
+		player := b.ActivePlayer
+		action := skat.Action{Player: player, Type: skat.ActionPass}
+		if next := bids[player]; len(next) > 0 {
+			if b.IsActiveBidding && next[0] > b.CurrentBid {
+				action = skat.Action{Player: player, Type: skat.ActionBid, Value: next[0]}
+			} else if !b.IsActiveBidding && next[0] == b.CurrentBid {
+				action = skat.Action{Player: player, Type: skat.ActionHold}
+			}
+			if action.Type != skat.ActionPass {
+				bids[player] = next[1:]
+			}
+		}
+		if err := game.Apply(action); err != nil {
+			return fmt.Errorf("bidding: %w", err)
+		}
+	}
+	for _, p := range skat.AllPlayers {
+		if len(bids[p]) > 0 {
+			return fmt.Errorf("bidding: %s cannot bid or hold %v", p, bids[p])
+		}
+	}
+	return nil
+}
+
+// declare replays the skat pickup, the discard, the announcement and Kontra and Re.
+func (g *jskatGame) declare(game *skat.Game) error {
+	c := g.Contract
+	switch c.GameType {
+	case "PASSED_IN":
+		if !game.PassedIn {
+			return errors.New("passed-in game has a declarer")
+		}
+		return nil
+	case "RAMSCH":
+		if game.Contract == nil || !game.Contract.GameType.IsRamsch() {
+			return errors.New("ramsch game has a declarer")
+		}
+		return nil
+	}
+
+	gameType, ok := jskatGameTypes[c.GameType]
+	if !ok {
+		return fmt.Errorf("invalid game type: %q", c.GameType)
+	}
+	declarer, ok := jskatPositions[strings.TrimSpace(g.Declarer)]
+	if !ok {
+		return fmt.Errorf("invalid declarer: %q", g.Declarer)
+	}
+	if game.Declarer == nil || *game.Declarer != declarer {
+		return fmt.Errorf("the bids do not make %s the declarer", declarer)
+	}
+
+	actions := []skat.Action{}
+	if !c.Hand {
+		discards, err := jskatCards(g.Discard)
+		if err != nil {
+			return fmt.Errorf("discard: %w", err)
+		}
+		actions = append(actions,
+			skat.Action{Player: declarer, Type: skat.ActionPickUpSkat},
+			skat.Action{Player: declarer, Type: skat.ActionDiscard, Cards: discards})
+	}
+	contract := &skat.Contract{GameType: gameType, Hand: c.Hand, Schneider: c.Schneider, Schwarz: c.Schwarz, Ouvert: c.Ouvert}
+	actions = append(actions, skat.Action{Player: declarer, Type: skat.ActionAnnounce, Contract: contract})
+	// JSkat does not record which defender said Kontra
+	if c.Contra {
+		actions = append(actions, skat.Action{Player: declarer.LeftNeighbor(), Type: skat.ActionKontra})
+	}
+	if c.Re {
+		actions = append(actions, skat.Action{Player: declarer, Type: skat.ActionRe})
+	}
+
+	for _, action := range actions {
+		if err := game.Apply(action); err != nil {
+			return fmt.Errorf("%s: %w", action.Type, err)
+		}
+	}
+	return nil
+}
+
+// play replays the tricks.
+func (g *jskatGame) play(game *skat.Game) error {
+	for i, trick := range g.Tricks {
+		cards, err := jskatCards(trick.Cards)
+		if err != nil {
+			return fmt.Errorf("trick %d: %w", i+1, err)
+		}
+		if trick.Forehand != "" {
+			forehand, ok := jskatPositions[trick.Forehand]
+			if next := game.ActivePlayer(); !ok || next == nil || *next != forehand {
+				return fmt.Errorf("trick %d: %s does not lead", i+1, trick.Forehand)
+			}
+		}
+		for _, card := range cards {
+			next := game.ActivePlayer()
+			if next == nil {
+				return fmt.Errorf("trick %d: the game is over", i+1)
+			}
+			if err := game.Apply(skat.Action{Player: *next, Type: skat.ActionPlayCard, Cards: []skat.Card{card}}); err != nil {
+				return fmt.Errorf("trick %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// jskatCards parses card codes separated by white space.
+func jskatCards(text string) ([]skat.Card, error) {
+	fields := strings.Fields(text)
+	cards := make([]skat.Card, len(fields))
+	for i, code := range fields {
+		card, err := skat.CardFromCode(code)
+		if err != nil {
+			return nil, err
+		}
+		cards[i] = card
+	}
+	return cards, nil
+}
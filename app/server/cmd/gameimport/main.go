@@ -0,0 +1,213 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Game Import - Imports game files of other Skat programs into the archive.
+//
+// Supported formats are JSkat save files (XML with one <skatGameData> per game), the
+// ISS game records JSkat uses for its ISS games (one "(;GM[Skat]...;)" record per
+// line) and the FreeSkat game notation.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/notation"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// importConfig holds the import configuration.
+type importConfig struct {
+	Archive   string
+	Format    string
+	Prefix    string
+	Overwrite bool
+	Files     []string
+}
+
+// parseFlags parses command-line flags and returns an importConfig.
+func parseFlags() *importConfig {
+	cfg := &importConfig{}
+
+	flag.StringVar(&cfg.Archive, "archive", "archive", "Game archive directory")
+	flag.StringVar(&cfg.Format, "format", "auto", "Input format (auto, jskat, iss, notation)")
+	flag.StringVar(&cfg.Prefix, "prefix", "import-", "Prefix of the archive IDs of imported games")
+	flag.BoolVar(&cfg.Overwrite, "overwrite", false, "Overwrite games already in the archive")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <file>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	cfg.Files = flag.Args()
+
+	return cfg
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	if len(cfg.Files) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	games, err := archive.Open(cfg.Archive)
+	if err != nil {
+		log.Fatalf("Failed to open archive: %v", err)
+	}
+
+	imported, skipped, failed := 0, 0, 0
+	for _, file := range cfg.Files {
+		records, err := readFile(file, cfg.Format)
+		if err != nil {
+			log.Printf("%s: %v", file, err)
+			failed++
+			continue
+		}
+
+		base := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		for i, record := range records {
+			original := record.ID
+			if original == "" {
+				original = fmt.Sprintf("%s-%d", base, i+1)
+			}
+			record.ID = cfg.Prefix + sanitizeID(original)
+
+			if !cfg.Overwrite {
+				if _, err := games.Replay(record.ID); !errors.Is(err, archive.ErrNotFound) {
+					skipped++
+					continue
+				}
+			}
+			if err := games.Save(record); err != nil {
+				log.Printf("%s: game %s: %v", file, original, err)
+				failed++
+				continue
+			}
+			imported++
+		}
+	}
+
+	fmt.Printf("Imported %d games, skipped %d existing, %d failed\n", imported, skipped, failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// readFile reads all games of a file.
+func readFile(file, format string) ([]*skat.GameRecord, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+
+	if format == "auto" {
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case strings.HasPrefix(trimmed, "<"):
+			format = "jskat"
+		case strings.HasPrefix(trimmed, "(;"):
+			format = "iss"
+		default:
+			format = "notation"
+		}
+	}
+
+	switch format {
+	case "jskat":
+		return readJSkat(data)
+	case "iss":
+		return readSummaries(text)
+	case "notation":
+		return readNotation(text)
+	default:
+		return nil, fmt.Errorf("invalid format: %s", format)
+	}
+}
+
+// readSummaries reads ISS game records, one per line.
+func readSummaries(text string) ([]*skat.GameRecord, error) {
+	var records []*skat.GameRecord
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		summary, err := protocol.ParseGameSummary(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		record, err := summary.Record()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readNotation reads games in notation. A tag line after moves starts the next game.
+func readNotation(text string) ([]*skat.GameRecord, error) {
+	var records []*skat.GameRecord
+	var game []string
+	inMoves := false
+
+	flush := func() error {
+		if !inMoves {
+			return nil
+		}
+		g, err := notation.Parse(strings.Join(game, "\n"))
+		if err != nil {
+			return fmt.Errorf("game %d: %w", len(records)+1, err)
+		}
+		records = append(records, g.Record)
+		game, inMoves = nil, false
+		return nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		} else if trimmed != "" && !strings.HasPrefix(trimmed, ";") {
+			inMoves = true
+		}
+		game = append(game, line)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// sanitizeID replaces all characters not allowed in archive IDs with "-".
+func sanitizeID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, id)
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestReadJSkat(t *testing.T) {
+	tests := []struct {
+		id          string
+		declarer    skat.Player
+		contract    string
+		declarerWon bool
+		gameValue   int
+	}{
+		// Kontra doubles the Grand of 120
+		{"series-7-1", skat.Middlehand, "G", true, 240},
+		{"series-7-2", skat.Forehand, "CH", false, 48},
+		// The Null ends with the first trick of the declarer
+		{"series-7-3", skat.Forehand, "N", false, 23},
+	}
+
+	for _, format := range []string{"auto", "jskat"} {
+		records, err := readFile(filepath.Join("testdata", "games.xml"), format)
+		if err != nil {
+			t.Fatalf("readFile(%s): %v", format, err)
+		}
+		if len(records) != len(tests) {
+			t.Fatalf("readFile(%s) = %d games, want %d", format, len(records), len(tests))
+		}
+		for i, tt := range tests {
+			record := records[i]
+			if record.ID != tt.id || record.Players[skat.Middlehand] != "ben" || record.Engine != skat.EngineVersion {
+				t.Errorf("game %d: ID %q, players %v, engine %d", i+1, record.ID, record.Players, record.Engine)
+			}
+			if want := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC); !record.StartedAt.Equal(want) {
+				t.Errorf("%s: StartedAt = %v, want %v", tt.id, record.StartedAt, want)
+			}
+			game, err := record.Replay(nil)
+			if err != nil {
+				t.Fatalf("%s: Replay() = %v", tt.id, err)
+			}
+			r := game.Result
+			if r == nil {
+				t.Fatalf("%s: the game has no result", tt.id)
+			}
+			if r.Declarer != tt.declarer || r.Contract.Code() != tt.contract || r.DeclarerWon != tt.declarerWon || r.GameValue != tt.gameValue {
+				t.Errorf("%s: %s plays %s, won %v, value %d, want %s plays %s, won %v, value %d", tt.id,
+					r.Declarer, r.Contract.Code(), r.DeclarerWon, r.GameValue, tt.declarer, tt.contract, tt.declarerWon, tt.gameValue)
+			}
+		}
+	}
+}
+
+func TestReadJSkatPassedIn(t *testing.T) {
+	records, err := readFile(filepath.Join("testdata", "passed.xml"), "auto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].ID != "series-7-4" {
+		t.Fatalf("readFile() = %d games, want series-7-4", len(records))
+	}
+	record := records[0]
+	if record.AllPass != skat.AllPassThrowIn || len(record.Actions) != 3 {
+		t.Errorf("AllPass = %s with %d actions, want throw-in after 3 passes", record.AllPass, len(record.Actions))
+	}
+	game, err := record.Replay(nil)
+	if err != nil || !game.PassedIn {
+		t.Errorf("Replay() = passed in %v, %v", game != nil && game.PassedIn, err)
+	}
+}
+
+func TestReadJSkatErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+		want string
+	}{
+		{"no games", `<jskat></jskat>`, "no skatGameData"},
+		{"no contract", `<skatGameData id="g1"></skatGameData>`, "no contract"},
+		{"position", `<skatGameData><player position="DEALER"/><contract gameType="GRAND"/></skatGameData>`, "invalid player position"},
+		{"card", `<skatGameData><player position="FOREHAND"><cards>CJ XX</cards></player><contract gameType="GRAND"/></skatGameData>`, "game 1: Forehand"},
+		{"deal", `<skatGameData><player position="FOREHAND"><cards>CJ</cards></player><contract gameType="GRAND"/></skatGameData>`, "game 1"},
+		{"date", `<skatGameData date="yesterday"><contract gameType="GRAND"/></skatGameData>`, "invalid date"},
+		{"xml", `<jskat><skatGameData>`, "EOF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readJSkat([]byte(tt.xml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("readJSkat() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	// Anna does not follow suit in the second trick
+	if _, err := readFile(filepath.Join("testdata", "invalid.xml"), "auto"); err == nil || !strings.Contains(err.Error(), "game 1: trick 2") {
+		t.Errorf("readFile(invalid.xml) = %v, want an error in trick 2", err)
+	}
+}
+
+func TestJSkatBidding(t *testing.T) {
+	records, err := readFile(filepath.Join("testdata", "games.xml"), "jskat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Anna holds up to 40, ben bids 44, anna passes and carl passes without a bid
+	var bidding []string
+	for _, action := range records[0].Actions {
+		switch action.Type {
+		case skat.ActionBid:
+			bidding = append(bidding, fmt.Sprintf("%s %d", action.Player, action.Value))
+		case skat.ActionHold, skat.ActionPass:
+			bidding = append(bidding, fmt.Sprintf("%s %s", action.Player, action.Type))
+		}
+	}
+	want := "Forehand Hold, Middlehand 44, Forehand Pass, Rearhand Pass"
+	if got := strings.Join(bidding[len(bidding)-4:], ", "); got != want {
+		t.Errorf("bidding ends with %q, want %q", got, want)
+	}
+
+	// Bids nobody can make are rejected
+	game := skat.NewGame()
+	deal, _ := records[0].Replay(nil)
+	if err := game.Deal(deal.DealtHands, deal.DealtSkat); err != nil {
+		t.Fatal(err)
+	}
+	err = jskatBidding(game, map[skat.Player][]int{skat.Middlehand: {18}, skat.Rearhand: {18}})
+	if err == nil || !strings.Contains(err.Error(), "Rearhand cannot bid or hold [18]") {
+		t.Errorf("jskatBidding() = %v", err)
+	}
+}
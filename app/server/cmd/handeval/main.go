@@ -0,0 +1,192 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Hand Evaluator - Explains how the AI evaluates a hand: matadors and game
+// values per game type, the suggested maximum bid and the Null safety.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s <hand>...\n\n"+
+			"A hand is a code of 10 cards, or 12 cards with the skat, e.g. CJ.SJ.CA.CT.C9.SA.HT.H8.D7.D8.\n"+
+			"Without arguments, one hand per line is read from the standard input.\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	codes := flag.Args()
+	if len(codes) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				codes = append(codes, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			log.Fatalf("Failed to read hands: %v", err)
+		}
+	}
+	if len(codes) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := 0
+	for i, code := range codes {
+		if i > 0 {
+			fmt.Println()
+		}
+		hand, err := parseHand(code)
+		if err != nil {
+			log.Printf("%s: %v", code, err)
+			failed++
+			continue
+		}
+		printEvaluation(os.Stdout, hand)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// parseHand parses a hand of 10 or 12 different cards.
+func parseHand(code string) (*skat.Hand, error) {
+	hand, err := skat.HandFromCode(strings.ToUpper(code))
+	if err != nil {
+		return nil, err
+	}
+	if n := hand.Size(); n != 10 && n != 12 {
+		return nil, fmt.Errorf("%d cards, want 10 (or 12 with the skat)", n)
+	}
+	seen := make(map[skat.Card]bool)
+	for _, c := range hand.Cards {
+		if seen[c] {
+			return nil, fmt.Errorf("duplicate card %s", c.Code())
+		}
+		seen[c] = true
+	}
+	return hand, nil
+}
+
+// printEvaluation prints the evaluation of a hand.
+func printEvaluation(out io.Writer, hand *skat.Hand) {
+	evaluation := ai.EvaluateHand(hand)
+	fmt.Fprintf(out, "Hand:       %s\n", groupedCards(hand))
+	fmt.Fprintf(out, "Cards:      %d, %d Jacks, %d card points\n", hand.Size(), evaluation.JackCount, evaluation.TotalPoints)
+
+	fmt.Fprintf(out, "\n%-10s %6s  %-11s %5s %5s\n", "Game", "Trumps", "Matadors", "Value", "Hand")
+	for _, gameType := range []skat.GameType{skat.GameDiamonds, skat.GameHearts, skat.GameSpades, skat.GameClubs, skat.GameGrand} {
+		matadors := skat.CountMatadors(hand.Cards, gameType)
+		level := max(matadors, -matadors) + 1
+		fmt.Fprintf(out, "%-10s %6d  %-11s %5d %5d\n", gameType, trumps(hand, gameType), matadorText(matadors),
+			gameType.BaseValue()*level, gameType.BaseValue()*(level+1))
+	}
+	null := skat.NewContract(skat.GameNull)
+	nullHand := &skat.Contract{GameType: skat.GameNull, Hand: true}
+	nullOuvert := &skat.Contract{GameType: skat.GameNull, Ouvert: true}
+	nullHandOuvert := &skat.Contract{GameType: skat.GameNull, Hand: true, Ouvert: true}
+	fmt.Fprintf(out, "%-10s %6s  %-11s %5d %5d  (Ouvert %d, Hand Ouvert %d)\n", skat.GameNull, "-", "-",
+		null.BaseValue(), nullHand.BaseValue(), nullOuvert.BaseValue(), nullHandOuvert.BaseValue())
+
+	fmt.Fprintf(out, "\nEvaluator:  %s, %d trumps, strength %d/100\n", evaluation.BestGameType, evaluation.TrumpCount, evaluation.Strength)
+	fmt.Fprintf(out, "Max bid:    %d\n", evaluation.MaxBid)
+	fmt.Fprintf(out, "Hand game:  %s\n", yesNo(evaluation.RecommendHand))
+
+	nullEvaluation := ai.EvaluateNull(hand)
+	switch {
+	case nullEvaluation.Safe:
+		fmt.Fprintln(out, "Null:       safe, no card can be forced to take a trick (Hand and Ouvert possible)")
+	case nullEvaluation.Playable:
+		fmt.Fprintf(out, "Null:       playable, %d unsafe card(s) to discard after picking up the skat: %s\n",
+			nullEvaluation.Risk, cardCodes(nullEvaluation.UnsafeCards))
+	default:
+		fmt.Fprintf(out, "Null:       not playable, %d unsafe cards: %s\n", nullEvaluation.Risk, cardCodes(nullEvaluation.UnsafeCards))
+	}
+
+	if hand.Size() == 12 {
+		hint := ai.HintDiscards(hand, evaluation.BestGameType)
+		fmt.Fprintf(out, "Discards:   %s (%s)\n", strings.ReplaceAll(hint.Move, ".", " "), hint.Rationale)
+	}
+}
+
+// groupedCards returns the cards sorted for a Grand game, the Jacks and each suit
+// separated by "|".
+func groupedCards(hand *skat.Hand) string {
+	cards := append([]skat.Card(nil), hand.Cards...)
+	skat.SortForGame(cards, skat.GameGrand)
+
+	var groups []string
+	var group []skat.Card
+	for i, c := range cards {
+		if i > 0 && (c.IsJack() != cards[i-1].IsJack() || !c.IsJack() && c.Suit != cards[i-1].Suit) {
+			groups = append(groups, cardCodes(group))
+			group = nil
+		}
+		group = append(group, c)
+	}
+	groups = append(groups, cardCodes(group))
+	return strings.Join(groups, " | ")
+}
+
+// trumps returns the number of trumps of a hand in a suit or Grand game.
+func trumps(hand *skat.Hand, gameType skat.GameType) int {
+	suit, ok := gameType.TrumpSuit()
+	n := 0
+	for _, c := range hand.Cards {
+		if c.IsJack() || ok && c.Suit == suit {
+			n++
+		}
+	}
+	return n
+}
+
+// matadorText describes the matadors, e.g. "with 2" or "without 1".
+func matadorText(matadors int) string {
+	if matadors < 0 {
+		return fmt.Sprintf("without %d", -matadors)
+	}
+	return fmt.Sprintf("with %d", matadors)
+}
+
+// cardCodes returns the codes of cards separated by spaces.
+func cardCodes(cards []skat.Card) string {
+	codes := make([]string, len(cards))
+	for i, c := range cards {
+		codes[i] = c.Code()
+	}
+	return strings.Join(codes, " ")
+}
+
+// yesNo returns "yes" or "no".
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
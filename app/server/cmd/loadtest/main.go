@@ -0,0 +1,145 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Load Test - Many scripted clients playing against a server, measuring
+// connect times, move latencies and error rates.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+)
+
+// loadConfig holds the load test configuration.
+type loadConfig struct {
+	Host       string
+	Port       int
+	Clients    int
+	Mode       string
+	Games      int
+	Ramp       time.Duration
+	Timeout    time.Duration
+	Prefix     string
+	Password   string
+	Difficulty string
+}
+
+// parseFlags parses command-line flags and returns a loadConfig.
+func parseFlags() *loadConfig {
+	cfg := &loadConfig{}
+
+	flag.StringVar(&cfg.Host, "host", "localhost", "Server host to connect to")
+	flag.IntVar(&cfg.Port, "port", 7000, "Server TCP port")
+	flag.IntVar(&cfg.Clients, "clients", 30, "Number of clients")
+	flag.StringVar(&cfg.Mode, "mode", modeDaily, "Test mode: daily (the deal of the day against the server bots) or tables (tables of three clients)")
+	flag.IntVar(&cfg.Games, "games", 1, "Games per client in tables mode")
+	flag.DurationVar(&cfg.Ramp, "ramp", 10*time.Millisecond, "Delay between the starts of two clients")
+	flag.DurationVar(&cfg.Timeout, "timeout", 5*time.Minute, "Maximum duration of the test")
+	flag.StringVar(&cfg.Prefix, "prefix", "", "Login name prefix (default load<unix time>-, so every run has new players)")
+	flag.StringVar(&cfg.Password, "password", "load", "Login password of the clients")
+	flag.StringVar(&cfg.Difficulty, "difficulty", ai.DifficultyBeginner.String(), "AI difficulty of the clients (beginner, club, strong)")
+
+	flag.Parse()
+
+	return cfg
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	difficulty, err := ai.ParseDifficulty(cfg.Difficulty)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	switch {
+	case cfg.Clients < 1:
+		log.Fatalf("Invalid configuration: at least one client required")
+	case cfg.Mode != modeDaily && cfg.Mode != modeTables:
+		log.Fatalf("Invalid configuration: invalid mode %q (want %s or %s)", cfg.Mode, modeDaily, modeTables)
+	case cfg.Mode == modeTables && cfg.Clients%3 != 0:
+		log.Fatalf("Invalid configuration: tables mode requires a multiple of 3 clients")
+	case cfg.Games < 1:
+		log.Fatalf("Invalid configuration: at least one game per client required")
+	}
+	if cfg.Mode == modeDaily {
+		// The deal of the day is played once per player
+		cfg.Games = 1
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = fmt.Sprintf("load%d-", time.Now().Unix())
+	}
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	log.Printf("Starting %d clients (%s mode, logins %s1...) against %s", cfg.Clients, cfg.Mode, cfg.Prefix, address)
+
+	results := newStats()
+	var wg sync.WaitGroup
+	var running atomic.Int32
+	var group chan string
+	started := time.Now()
+	for i := 0; i < cfg.Clients; i++ {
+		p := &player{
+			login: fmt.Sprintf("%s%d", cfg.Prefix, i+1),
+			mode:  cfg.Mode,
+			games: cfg.Games,
+			ai:    ai.New(difficulty, nil),
+			stats: results,
+		}
+		if cfg.Mode == modeTables {
+			// The first client of three creates the table, the others join it
+			if i%3 == 0 {
+				group = make(chan string, 2)
+				p.share = group
+			} else {
+				p.group = group
+			}
+		}
+
+		wg.Add(1)
+		running.Add(1)
+		go func() {
+			defer wg.Done()
+			defer running.Add(-1)
+			p.run(address, cfg.Password)
+		}()
+		time.Sleep(cfg.Ramp)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	timedOut := false
+	select {
+	case <-done:
+	case <-time.After(cfg.Timeout - time.Since(started)):
+		timedOut = true
+	}
+
+	results.report(os.Stdout, cfg.Clients, time.Since(started))
+	if timedOut {
+		log.Fatalf("Timeout after %s: %d clients still running", cfg.Timeout, running.Load())
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Test modes.
+const (
+	// modeDaily plays the deal of the day against the server bots
+	modeDaily = "daily"
+	// modeTables creates tables of three clients and plays games at them
+	modeTables = "tables"
+)
+
+// player is a scripted client playing its games with the AI. Its handlers run in the
+// goroutine of Run, which also sends all moves.
+type player struct {
+	login string
+	mode  string
+	games int
+	ai    ai.AIPlayer
+	stats *stats
+
+	// group receives the table of the creator in tables mode (nil: the player creates it)
+	group chan string
+	// share passes the created table to the other players of the group
+	share chan<- string
+
+	c      *client.Client
+	table  string
+	game   *tracker
+	ready  bool
+	played int
+	sent   time.Time
+}
+
+// run connects, logs in and plays the games until they are finished or the connection
+// is closed.
+func (p *player) run(address, password string) {
+	started := time.Now()
+	c, err := client.Dial(address)
+	if err != nil {
+		p.stats.connectFailed(false)
+		p.abortGroup()
+		return
+	}
+	if err := c.Login(p.login, password); err != nil {
+		c.Close()
+		p.stats.connectFailed(errors.Is(err, client.ErrLogin))
+		p.abortGroup()
+		return
+	}
+	p.stats.connected(time.Since(started))
+	p.c = c
+
+	if err := p.begin(); err != nil {
+		c.Close()
+		p.stats.closed(false, true)
+		return
+	}
+	err = c.Run(client.Handlers{
+		Message: p.answer,
+		Created: p.created,
+		State: func(s client.TableState) {
+			if s.Table == p.table && p.game == nil {
+				p.sendReady()
+			}
+		},
+		Start: p.start,
+		Move:  p.move,
+		End:   p.end,
+		Error: func(text string) {
+			p.stats.serverError(text)
+			if p.table == "" {
+				// Nothing to play, e.g. the daily deal has been played before
+				c.Close()
+			}
+		},
+		Destroyed: func(table string) {
+			if table == p.table {
+				c.Close()
+			}
+		},
+	})
+	p.abortGroup()
+	p.stats.closed(err != nil, p.played < p.games)
+}
+
+// begin starts playing: the daily deal, or creating or joining the table of the group.
+func (p *player) begin() error {
+	switch {
+	case p.mode == modeDaily:
+		return p.command(p.c.Send("daily play"))
+	case p.group == nil:
+		return p.command(p.c.Create(3))
+	default:
+		table, ok := <-p.group
+		if !ok {
+			return errors.New("no table to join")
+		}
+		p.table = table
+		return p.command(p.c.Join(table))
+	}
+}
+
+// abortGroup closes the group of a creator that did not create a table.
+func (p *player) abortGroup() {
+	if p.share != nil {
+		close(p.share)
+	}
+}
+
+// created takes the table created by the player and passes it to the group.
+func (p *player) created(t client.TableCreated) {
+	if t.Creator != p.login || p.table != "" {
+		return
+	}
+	p.table = t.Table
+	for i := 0; i < t.Size-1; i++ {
+		p.share <- t.Table
+	}
+	close(p.share)
+	p.share = nil
+	p.sendReady()
+}
+
+// sendReady signals once per game that the player is ready.
+func (p *player) sendReady() {
+	if !p.ready {
+		p.ready = true
+		p.send(p.c.Ready(p.table))
+	}
+}
+
+// command records the time of a sent command for the latency of its answer.
+func (p *player) command(err error) error {
+	p.sent = time.Now()
+	return err
+}
+
+// send sends a move and counts it; a failed send closes the client.
+func (p *player) send(err error) {
+	p.command(err)
+	p.stats.moved()
+	if err != nil {
+		p.c.Close()
+	}
+}
+
+// answer measures the latency of the first table or error message after a command.
+func (p *player) answer(m client.Message) {
+	if p.sent.IsZero() || (m.Command != "table" && m.Command != "error") {
+		return
+	}
+	p.stats.answered(time.Since(p.sent))
+	p.sent = time.Time{}
+}
+
+// start begins a new game.
+func (p *player) start(s client.GameStart) {
+	if p.mode == modeDaily && p.table == "" {
+		p.table = s.Table
+	}
+	if s.Table == p.table {
+		p.game = newTracker(p.ai)
+		p.ready = false
+	}
+}
+
+// move applies a move and answers with the player's move if it is their turn.
+func (p *player) move(m client.Move) {
+	if m.Table != p.table || p.game == nil {
+		return
+	}
+	token, err := p.game.apply(m)
+	if err != nil {
+		p.stats.serverError(fmt.Sprintf("invalid move %s: %v", m.Token, err))
+		p.c.Close()
+		return
+	}
+	if token != "" {
+		p.send(p.c.Play(p.table, token))
+	}
+}
+
+// end counts a finished game and starts the next one or leaves.
+func (p *player) end(e client.GameEnd) {
+	if e.Table != p.table {
+		return
+	}
+	p.stats.finished()
+	p.played++
+	p.game = nil
+	if p.played >= p.games {
+		if p.mode == modeTables {
+			p.c.Leave(p.table)
+		}
+		p.c.Close()
+		return
+	}
+	p.sendReady()
+}
+
+// tracker tracks a game from the player's point of view and decides the moves.
+type tracker struct {
+	ai           ai.AIPlayer
+	position     *skat.Player
+	hand         *skat.Hand
+	bidding      *skat.BiddingState
+	declarer     *skat.Player
+	contract     *skat.Contract
+	trick        *skat.Trick
+	awaitingSkat bool
+	announced    bool
+}
+
+// newTracker creates a tracker deciding with the AI.
+func newTracker(player ai.AIPlayer) *tracker {
+	return &tracker{ai: player, hand: skat.NewHand(), bidding: skat.NewBiddingState()}
+}
+
+// apply applies a move and returns the player's next move token ("" if it is not
+// their turn).
+func (t *tracker) apply(m client.Move) (string, error) {
+	player, ok := m.Player.ToPlayer()
+	if !ok {
+		if hands, _, ok := m.Deal(); ok {
+			for _, p := range skat.AllPlayers {
+				if hands[p].Size() > 0 {
+					position := p
+					t.position = &position
+					t.hand = hands[p]
+				}
+			}
+		} else if t.awaitingSkat {
+			cards, err := skat.HandFromCode(m.Token)
+			if err != nil {
+				return "", err
+			}
+			for _, c := range cards.Cards {
+				t.hand.Add(c)
+			}
+			t.awaitingSkat = false
+		}
+		return t.next(), nil
+	}
+
+	var err error
+	if value, ok := m.Bid(); ok {
+		err = t.bidding.Bid(player, value)
+	} else if card, ok := m.Card(); ok {
+		err = t.cardPlay(player, card)
+	} else if contract, ok := m.Contract(); ok {
+		t.declarer = &player
+		t.contract = contract
+		t.trick = skat.NewTrick(skat.Forehand)
+	} else {
+		switch m.Token {
+		case client.TokenHoldBid:
+			err = t.bidding.Hold(player)
+		case client.TokenPass:
+			err = t.bidding.Pass(player)
+			if err == nil && t.bidding.Result == skat.BidResultAllPassed {
+				t.contract = skat.NewContract(skat.GameRamsch)
+				t.trick = skat.NewTrick(skat.Forehand)
+			}
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return t.next(), nil
+}
+
+// cardPlay adds a played card to the trick.
+func (t *tracker) cardPlay(player skat.Player, card skat.Card) error {
+	if t.trick == nil {
+		return fmt.Errorf("card play before game announcement: %s", card.Code())
+	}
+	if err := t.trick.AddCard(card, player); err != nil {
+		return err
+	}
+	if t.position != nil && *t.position == player {
+		t.hand.Remove(card)
+	}
+	if t.trick.IsComplete() {
+		winner, err := t.trick.DetermineWinner(t.contract.GameType)
+		if err != nil {
+			return err
+		}
+		t.trick = skat.NewTrick(winner)
+	}
+	return nil
+}
+
+// next returns the player's next move token ("" if it is not their turn).
+func (t *tracker) next() string {
+	if t.position == nil {
+		return ""
+	}
+	me := *t.position
+
+	if !t.bidding.IsDone() {
+		if t.bidding.ActivePlayer != me {
+			return ""
+		}
+		return t.bid()
+	}
+
+	if t.contract == nil {
+		if t.bidding.Declarer == nil || *t.bidding.Declarer != me || t.awaitingSkat || t.announced {
+			return ""
+		}
+		if t.hand.Size() == 12 {
+			gameType := ai.EvaluateHand(t.hand).BestGameType
+			discards := t.ai.SelectDiscards(t.hand, gameType)
+			for _, c := range discards {
+				t.hand.Remove(c)
+			}
+			t.announced = true
+			contract := t.ai.DecideAnnouncement(t.hand, t.bidding.FinalBid, false)
+			return fmt.Sprintf("%s.%s.%s", contract.Code(), discards[0].Code(), discards[1].Code())
+		}
+		if t.ai.DecidePickUpSkat(t.hand) {
+			t.awaitingSkat = true
+			return client.TokenSkatRequest
+		}
+		t.announced = true
+		return t.ai.DecideAnnouncement(t.hand, t.bidding.FinalBid, true).Code()
+	}
+
+	next := t.trick.NextPlayer()
+	if next == nil || *next != me || t.hand.Size() == 0 {
+		return ""
+	}
+	return t.ai.SelectCard(t.hand, ai.PlayContext{
+		Player:   me,
+		Declarer: t.declarer,
+		GameType: t.contract.GameType,
+		Trick:    t.trick,
+	}).Code()
+}
+
+// bid returns the bidding move token.
+func (t *tracker) bid() string {
+	decision := t.ai.DecideBid(t.hand, ai.BidContext{
+		Player:     *t.position,
+		CurrentBid: t.bidding.CurrentBid,
+		Bidding:    t.bidding.IsActiveBidding,
+	})
+	switch {
+	case decision.Action == ai.BidActionBid && decision.Value > t.bidding.CurrentBid && skat.IsValidBid(decision.Value):
+		return strconv.Itoa(decision.Value)
+	case decision.Action == ai.BidActionHold && !t.bidding.IsActiveBidding:
+		return client.TokenHoldBid
+	default:
+		return client.TokenPass
+	}
+}
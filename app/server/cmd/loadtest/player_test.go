@@ -0,0 +1,118 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// applyToken applies a move token of the trackers to the game like the server.
+func applyToken(t *testing.T, game *skat.Game, player skat.Player, token string) {
+	t.Helper()
+	var err error
+	if value, convErr := strconv.Atoi(token); convErr == nil {
+		err = game.Bid(player, value)
+	} else if card, cardErr := skat.CardFromCode(token); cardErr == nil {
+		err = game.PlayCard(player, card)
+	} else {
+		switch token {
+		case client.TokenHoldBid:
+			err = game.Hold(player)
+		case client.TokenPass:
+			err = game.Pass(player)
+		case client.TokenSkatRequest:
+			err = game.PickUpSkat(player)
+		default:
+			parts := strings.Split(token, ".")
+			contract, contractErr := skat.ContractFromCode(parts[0])
+			if contractErr != nil {
+				t.Fatalf("unknown token %q", token)
+			}
+			if len(parts) == 3 {
+				first, _ := skat.CardFromCode(parts[1])
+				second, _ := skat.CardFromCode(parts[2])
+				err = game.Discard(player, first, second)
+			}
+			if err == nil {
+				err = game.Announce(player, contract)
+			}
+		}
+	}
+	if err != nil {
+		t.Fatalf("%s: %s refused: %v", player, token, err)
+	}
+}
+
+func TestTrackersPlayGame(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		deck := skat.NewDeck()
+		deck.ShuffleWith(rng)
+		hands, skatCards, _ := skat.DealCards(deck)
+		game := skat.NewGame()
+		if err := game.Deal(hands, skatCards); err != nil {
+			t.Fatal(err)
+		}
+
+		// Each tracker sees its own hand only
+		trackers := make(map[skat.Player]*tracker)
+		next := make(map[skat.Player]string)
+		for i, p := range skat.AllPlayers {
+			trackers[p] = newTracker(ai.New(ai.DifficultyStrong, rng))
+			deal := []string{"", "", "", "??.??"}
+			deal[i] = hands[p].Code()
+			next[p], _ = trackers[p].apply(client.Move{Player: skat.MoveWorld, Token: strings.Join(deal, "|")})
+		}
+
+		for moves := 0; game.Result == nil && game.RamschResult == nil; moves++ {
+			active := game.ActivePlayer()
+			if active == nil || moves > 60 {
+				t.Fatalf("seed %d: the game is stuck in state %v", seed, game.State)
+			}
+			token := next[*active]
+			for _, p := range skat.AllPlayers {
+				if p != *active && next[p] != "" {
+					t.Fatalf("seed %d: %s wants to move %q at the turn of %s", seed, p, next[p], *active)
+				}
+			}
+			if token == "" {
+				t.Fatalf("seed %d: %s does not move in state %v", seed, *active, game.State)
+			}
+			applyToken(t, game, *active, token)
+
+			mover := skat.MovePlayerFromPlayer(*active)
+			for _, p := range skat.AllPlayers {
+				var err error
+				if next[p], err = trackers[p].apply(client.Move{Player: mover, Token: token}); err != nil {
+					t.Fatalf("seed %d: %s: %v", seed, token, err)
+				}
+			}
+			// The server sends the skat to the declarer only
+			if token == client.TokenSkatRequest {
+				next[*active], _ = trackers[*active].apply(client.Move{Player: skat.MoveWorld, Token: skatCards.Code()})
+			}
+		}
+		if game.Result == nil && game.RamschResult == nil {
+			t.Errorf("seed %d: the game did not finish", seed)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// stats collects the measurements of all clients. It is safe for concurrent use.
+type stats struct {
+	mu sync.Mutex
+
+	connects       []time.Duration
+	connectErrors  int
+	loginErrors    int
+	latencies      []time.Duration
+	moves          int
+	serverErrors   int
+	errorMessages  map[string]int
+	disconnects    int
+	games          int
+	abortedClients int
+}
+
+// newStats creates empty statistics.
+func newStats() *stats {
+	return &stats{errorMessages: make(map[string]int)}
+}
+
+// connected records the time from dialing to the login confirmation.
+func (s *stats) connected(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connects = append(s.connects, d)
+}
+
+// connectFailed records a failed connection (login is false) or login (login is true).
+func (s *stats) connectFailed(login bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if login {
+		s.loginErrors++
+	} else {
+		s.connectErrors++
+	}
+}
+
+// answered records the time from sending a command to the first answer.
+func (s *stats) answered(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies = append(s.latencies, d)
+}
+
+// moved counts a sent move.
+func (s *stats) moved() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.moves++
+}
+
+// serverError counts an error message of the server.
+func (s *stats) serverError(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serverErrors++
+	s.errorMessages[text]++
+}
+
+// finished counts a finished game.
+func (s *stats) finished() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games++
+}
+
+// closed records the end of a client: lost connections and clients that did not
+// finish their games count separately.
+func (s *stats) closed(disconnected, aborted bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if disconnected {
+		s.disconnects++
+	}
+	if aborted {
+		s.abortedClients++
+	}
+}
+
+// report writes the summary of a run with the given number of clients.
+func (s *stats) report(w io.Writer, clients int, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fmt.Fprintf(w, "Clients:        %d in %s\n", clients, elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Connected:      %d (%s)\n", len(s.connects), rate(len(s.connects), clients))
+	fmt.Fprintf(w, "Connect errors: %d, login errors: %d\n", s.connectErrors, s.loginErrors)
+	fmt.Fprintf(w, "Connect time:   %s\n", percentiles(s.connects))
+	fmt.Fprintf(w, "Moves:          %d (%.1f/s)\n", s.moves, float64(s.moves)/elapsed.Seconds())
+	fmt.Fprintf(w, "Move latency:   %s\n", percentiles(s.latencies))
+	fmt.Fprintf(w, "Games:          %d (%.1f/s)\n", s.games, float64(s.games)/elapsed.Seconds())
+	fmt.Fprintf(w, "Server errors:  %d (%s of the moves)\n", s.serverErrors, rate(s.serverErrors, s.moves))
+	fmt.Fprintf(w, "Disconnects:    %d, aborted clients: %d\n", s.disconnects, s.abortedClients)
+
+	messages := make([]string, 0, len(s.errorMessages))
+	for text := range s.errorMessages {
+		messages = append(messages, text)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return s.errorMessages[messages[i]] > s.errorMessages[messages[j]]
+	})
+	for _, text := range messages {
+		fmt.Fprintf(w, "  %5dx %s\n", s.errorMessages[text], text)
+	}
+}
+
+// percentiles formats the median, the 95th and 99th percentile and the maximum.
+func percentiles(durations []time.Duration) string {
+	if len(durations) == 0 {
+		return "-"
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))].Round(time.Microsecond)
+	}
+	return fmt.Sprintf("p50 %s, p95 %s, p99 %s, max %s", at(0.5), at(0.95), at(0.99), sorted[len(sorted)-1].Round(time.Microsecond))
+}
+
+// rate formats n of total as percentage.
+func rate(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsReport(t *testing.T) {
+	s := newStats()
+	for ms := 1; ms <= 100; ms++ {
+		s.answered(time.Duration(ms) * time.Millisecond)
+		s.moved()
+	}
+	s.connected(5 * time.Millisecond)
+	s.connected(7 * time.Millisecond)
+	s.connectFailed(false)
+	s.serverError("Not your turn")
+	s.serverError("Not your turn")
+	s.serverError("Unknown table: t1")
+	s.finished()
+	s.closed(true, true)
+
+	var out bytes.Buffer
+	s.report(&out, 4, 2*time.Second)
+	report := out.String()
+	for _, want := range []string{
+		"Connected:      2 (50.0%)",
+		"Connect errors: 1, login errors: 0",
+		"Moves:          100 (50.0/s)",
+		"Move latency:   p50 50ms, p95 95ms, p99 99ms, max 100ms",
+		"Games:          1 (0.5/s)",
+		"Server errors:  3 (3.0% of the moves)",
+		"Disconnects:    1, aborted clients: 1",
+		"      2x Not your turn\n      1x Unknown table: t1",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report has no %q:\n%s", want, report)
+		}
+	}
+	if percentiles(nil) != "-" || rate(1, 0) != "-" {
+		t.Error("empty measurements are not shown as -")
+	}
+}
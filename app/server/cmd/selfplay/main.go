@@ -0,0 +1,217 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Self-Play - Runs games between AI strategies without networking.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// selfplayConfig holds the self-play configuration.
+type selfplayConfig struct {
+	Games   int
+	Players string
+	Seed    int64
+	Rotate  bool
+	Verbose bool
+	Archive string
+}
+
+// parseFlags parses command-line flags and returns a selfplayConfig.
+func parseFlags() *selfplayConfig {
+	cfg := &selfplayConfig{}
+
+	flag.IntVar(&cfg.Games, "games", 1000, "Number of games to play")
+	flag.StringVar(&cfg.Players, "players", "strong,club,beginner", "Comma-separated strategies of the three seats (beginner, club, strong, random)")
+	flag.Int64Var(&cfg.Seed, "seed", 0, "Random seed for deals and AI noise (0 = current time)")
+	flag.BoolVar(&cfg.Rotate, "rotate", true, "Rotate the strategies through all positions")
+	flag.BoolVar(&cfg.Verbose, "v", false, "Print every game result")
+	flag.StringVar(&cfg.Archive, "archive", "", "Directory to archive the played games in (empty = no archive)")
+
+	flag.Parse()
+
+	return cfg
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+
+	names := strings.Split(cfg.Players, ",")
+	if len(names) != 3 {
+		log.Fatalf("Invalid configuration: expected 3 strategies, got %d", len(names))
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	seats := make([]*seat, len(names))
+	for i, name := range names {
+		player, err := ai.NewByName(strings.TrimSpace(name), rng)
+		if err != nil {
+			log.Fatalf("Invalid configuration: %v", err)
+		}
+		seats[i] = &seat{name: fmt.Sprintf("%d:%s", i+1, strings.TrimSpace(name)), player: player}
+	}
+
+	var games *archive.Archive
+	if cfg.Archive != "" {
+		var err error
+		if games, err = archive.Open(cfg.Archive); err != nil {
+			log.Fatalf("Failed to open archive: %v", err)
+		}
+	}
+
+	log.Printf("Playing %d games (seed %d)", cfg.Games, cfg.Seed)
+	start := time.Now()
+
+	for n := 0; n < cfg.Games; n++ {
+		if err := playGame(n, seats, rng, cfg, games); err != nil {
+			log.Fatalf("Game %d failed: %v", n+1, err)
+		}
+	}
+
+	log.Printf("Finished in %s", time.Since(start).Round(time.Millisecond))
+	printReport(os.Stdout, seats, cfg.Games)
+}
+
+// playGame deals and plays a single game, records the statistics and archives the game.
+func playGame(n int, seats []*seat, rng *rand.Rand, cfg *selfplayConfig, games *archive.Archive) error {
+	// Each deal has its own seed, so single games can be reproduced; a misdeal is
+	// dealt again with the next seed at the same seats
+	var shuffle skat.ShuffleInfo
+	hands, skatCards, err := skat.DealUntilValid(func(int) (map[skat.Player]*skat.Hand, *skat.Hand, error) {
+		deck := skat.NewDeck()
+		shuffle = deck.ShuffleSeed(rng.Int63())
+		return skat.DealCards(deck)
+	}, func(attempt int, err error) {
+		log.Printf("Game %d: misdeal (seed %d): %v, redealing", n+1, shuffle.Seed, err)
+	})
+	if err != nil {
+		return err
+	}
+
+	game := skat.NewGame()
+	if err := game.Deal(hands, skatCards); err != nil {
+		return err
+	}
+
+	// Seat i plays position (i + n) mod 3 when rotating
+	positions := make(map[skat.Player]*seat)
+	players := make(map[skat.Player]ai.AIPlayer)
+	for i, s := range seats {
+		offset := 0
+		if cfg.Rotate {
+			offset = n
+		}
+		position := skat.AllPlayers[(i+offset)%len(skat.AllPlayers)]
+		positions[position] = s
+		players[position] = &timedPlayer{AIPlayer: s.player, seat: s}
+	}
+
+	startedAt := time.Now()
+	if err := ai.PlayGame(game, players); err != nil {
+		return err
+	}
+
+	if games != nil {
+		names := make(map[skat.Player]string)
+		for position, s := range positions {
+			names[position] = s.name
+		}
+		record, err := skat.NewGameRecord(fmt.Sprintf("selfplay-%d-%d", cfg.Seed, n+1), startedAt, names, game)
+		if err != nil {
+			return err
+		}
+		record.Shuffle = shuffle
+		if err := games.Save(record); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range positions {
+		s.games++
+	}
+	if result := game.Result; result != nil {
+		declarer := positions[result.Declarer]
+		declarer.declared++
+		declarer.gameValues += result.GameValue
+		declarer.score += result.Score
+		if result.DeclarerWon {
+			declarer.won++
+		}
+		if cfg.Verbose {
+			log.Printf("Game %d: %s plays %s, %d points, score %d", n+1, declarer.name, result.Contract.Code(), result.DeclarerPoints, result.Score)
+		}
+	} else if result := game.RamschResult; result != nil {
+		for _, p := range skat.AllPlayers {
+			positions[p].score += result.Score(p)
+		}
+		if result.Durchmarsch && cfg.Verbose {
+			log.Printf("Game %d: Ramsch, %s wins %d by Durchmarsch", n+1, positions[*result.DurchmarschPlayer].name, result.DurchmarschScore)
+		}
+		for _, p := range result.Losers {
+			if cfg.Verbose {
+				log.Printf("Game %d: Ramsch, %s loses %d", n+1, positions[p].name, result.LoserScore)
+			}
+		}
+	}
+	return nil
+}
+
+// printReport prints the statistics of all seats.
+func printReport(f *os.File, seats []*seat, games int) {
+	w := tabwriter.NewWriter(f, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Strategy\tGames\tDeclared\tWon\tWin rate\tAvg value\tScore\tScore/game\tAvg move\tMax move\t")
+
+	for _, s := range seats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\t%d\t%.1f\t%s\t%s\t\n",
+			s.name, s.games, s.declared, s.won,
+			percent(s.won, s.declared),
+			average(s.gameValues, s.declared),
+			s.score, float64(s.score)/float64(max(games, 1)),
+			s.averageMove(), s.maxMove.Round(time.Microsecond))
+	}
+	w.Flush()
+}
+
+// percent formats a ratio as percentage ("-" if the total is 0).
+func percent(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
+
+// average formats an average ("-" if the count is 0).
+func average(sum, count int) string {
+	if count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", float64(sum)/float64(count))
+}
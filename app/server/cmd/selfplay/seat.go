@@ -0,0 +1,90 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// seat is a configured strategy and its statistics.
+type seat struct {
+	name       string
+	player     ai.AIPlayer
+	games      int
+	declared   int
+	won        int
+	gameValues int
+	score      int
+	moves      int
+	moveTime   time.Duration
+	maxMove    time.Duration
+}
+
+// track records the duration of a single decision.
+func (s *seat) track(start time.Time) {
+	elapsed := time.Since(start)
+	s.moves++
+	s.moveTime += elapsed
+	if elapsed > s.maxMove {
+		s.maxMove = elapsed
+	}
+}
+
+// averageMove returns the average decision time.
+func (s *seat) averageMove() time.Duration {
+	if s.moves == 0 {
+		return 0
+	}
+	return (s.moveTime / time.Duration(s.moves)).Round(time.Microsecond)
+}
+
+// timedPlayer wraps an AI player and measures the time of every decision.
+type timedPlayer struct {
+	ai.AIPlayer
+	seat *seat
+}
+
+// DecideBid decides the bidding action.
+func (t *timedPlayer) DecideBid(hand *skat.Hand, ctx ai.BidContext) ai.BidDecision {
+	defer t.seat.track(time.Now())
+	return t.AIPlayer.DecideBid(hand, ctx)
+}
+
+// DecidePickUpSkat decides whether to pick up the skat.
+func (t *timedPlayer) DecidePickUpSkat(hand *skat.Hand) bool {
+	defer t.seat.track(time.Now())
+	return t.AIPlayer.DecidePickUpSkat(hand)
+}
+
+// SelectDiscards selects the two cards to discard to the skat.
+func (t *timedPlayer) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	defer t.seat.track(time.Now())
+	return t.AIPlayer.SelectDiscards(hand, gameType)
+}
+
+// DecideAnnouncement decides the contract to announce.
+func (t *timedPlayer) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	defer t.seat.track(time.Now())
+	return t.AIPlayer.DecideAnnouncement(hand, bidValue, handGame)
+}
+
+// SelectCard selects a card to play.
+func (t *timedPlayer) SelectCard(hand *skat.Hand, ctx ai.PlayContext) skat.Card {
+	defer t.seat.track(time.Now())
+	return t.AIPlayer.SelectCard(hand, ctx)
+}
@@ -31,6 +31,9 @@ func main() {
 
 	// Parse configuration
 	cfg := config.ParseFlags()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	// Create and start server
 	srv := server.New(cfg)
@@ -38,6 +41,17 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 
+	// Reload the welcome and MOTD templates on SIGHUP
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			if err := srv.ReloadMessages(); err != nil {
+				log.Printf("Reloading the welcome and MOTD templates failed: %v", err)
+			}
+		}
+	}()
+
 	// Wait for shutdown signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
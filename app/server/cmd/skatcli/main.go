@@ -0,0 +1,303 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat CLI - An interactive terminal client for ISS-compatible Skat servers.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/render"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// cliConfig holds the client configuration.
+type cliConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Daily    bool
+	ASCII    bool
+}
+
+// parseFlags parses command-line flags and returns a cliConfig.
+func parseFlags() *cliConfig {
+	cfg := &cliConfig{}
+
+	flag.StringVar(&cfg.Host, "host", "localhost", "Server host to connect to")
+	flag.IntVar(&cfg.Port, "port", 7000, "Server TCP port")
+	flag.StringVar(&cfg.Username, "user", os.Getenv("USER"), "Login name")
+	flag.StringVar(&cfg.Password, "password", "", "Login password")
+	flag.BoolVar(&cfg.Daily, "daily", false, "Play the deal of the day right away")
+	flag.BoolVar(&cfg.ASCII, "ascii", false, "Show cards as ASCII codes (e.g. CJ) instead of suit symbols")
+
+	flag.Parse()
+
+	return cfg
+}
+
+// helpText lists the commands.
+const helpText = `Commands:
+  daily                        play the deal of the day against bots
+  create [3|4]                 create a table
+  join <table>                 join a table
+  observe <table>              watch a table
+  ready                        ready for the next game
+  leave                        leave the table
+  bid <value>, hold, pass      bidding
+  skat                         pick up the skat
+  announce <game> [card card]  announce the game (C, S, H, D, G, N with H, O, S, Z),
+                               discarding two cards after picking up the skat
+  play <card>                  play a card
+  claim, concede               claim the remaining tricks as declarer, concede them
+                               as defender
+  accept, reject               answer a claim or concession
+  hand                         show your hand and the current trick
+  say <text>                   chat with the observers of your table
+  /<line>                      send a raw protocol line, e.g. "/tournament list"
+  help                         show this help
+  quit                         leave the table and quit
+Cards are given by their number in your hand or their code, e.g. CJ (Clubs Jack) or HT (Hearts 10).`
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	if cfg.Username == "" || cfg.Password == "" {
+		log.Fatalf("Invalid configuration: login name (-user) and password (-password) required")
+	}
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	c, err := client.Dial(address)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", address, err)
+	}
+	if err := c.Login(cfg.Username, cfg.Password); err != nil {
+		log.Fatalf("Failed to log in: %v", err)
+	}
+	fmt.Printf("Connected to %s as %s (protocol %d). Type 'help' for the commands.\n", address, cfg.Username, c.Version())
+
+	style := render.Unicode
+	if cfg.ASCII {
+		style = render.ASCII
+	}
+	v := newView(os.Stdout, style)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(handlers(v))
+	}()
+	if cfg.Daily {
+		if err := c.Send("daily play"); err != nil {
+			log.Fatalf("Connection closed: %v", err)
+		}
+	}
+
+	input := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			input <- scanner.Text()
+		}
+		close(input)
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				log.Fatalf("Connection closed: %v", err)
+			}
+			return
+		case line, ok := <-input:
+			if !ok || isQuit(line) {
+				if table := v.Table(); table != "" {
+					c.Leave(table)
+				}
+				c.Close()
+				return
+			}
+			if err := execute(c, v, line); err != nil {
+				fmt.Printf("! %v\n", err)
+			}
+		}
+	}
+}
+
+// handlers returns the handlers printing the messages of the server.
+func handlers(v *view) client.Handlers {
+	return client.Handlers{
+		Lobby: func(u client.LobbyUpdate) {
+			if len(u.Args) > 0 {
+				v.printf("[%s] %s", u.Kind, strings.Join(u.Args, " "))
+			}
+		},
+		Created: func(t client.TableCreated) {
+			v.printf("Table %s created by %s (%d players)", t.Table, t.Creator, t.Size)
+		},
+		Start: v.start,
+		Move:  v.move,
+		Bot:   v.bot,
+		Open:  v.open,
+		Skat:  v.handSkat,
+		End:   v.end,
+		Destroyed: func(table string) {
+			v.printf("Table %s closed", table)
+		},
+		Chat: func(c client.Chat) {
+			v.printf("<%s> %s", c.Sender, c.Text)
+		},
+		Text: func(text string) {
+			v.printf("%s", text)
+		},
+		Error: func(text string) {
+			v.printf("! %s", text)
+		},
+		Message: func(m client.Message) {
+			// Replies to raw commands, e.g. "tournament ..."
+			switch m.Command {
+			case "clients", "tables", "create", "table", "text", "yell", "error":
+			default:
+				v.printf("%s", m.Raw)
+			}
+		},
+	}
+}
+
+// isQuit returns true for the quit commands.
+func isQuit(line string) bool {
+	switch strings.TrimSpace(line) {
+	case "quit", "exit":
+		return true
+	default:
+		return false
+	}
+}
+
+// execute executes a command line of the user.
+func execute(c *client.Client, v *view, line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	if strings.HasPrefix(line, "/") {
+		return c.Send("%s", line[1:])
+	}
+
+	parts := strings.Fields(line)
+	command, args := parts[0], parts[1:]
+	table := v.Table()
+	switch command {
+	case "help":
+		fmt.Println(helpText)
+		return nil
+	case "daily":
+		return c.Send("daily play")
+	case "create":
+		size := 3
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || (n != 3 && n != 4) {
+				return fmt.Errorf("invalid table size: %s (want 3 or 4)", args[0])
+			}
+			size = n
+		}
+		return c.Create(size)
+	case "join", "observe":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: %s <table>", command)
+		}
+		if command == "join" {
+			return c.Join(args[0])
+		}
+		return c.Observe(args[0])
+	case "hand":
+		v.Hand()
+		return nil
+	}
+
+	if table == "" {
+		return fmt.Errorf("not at a table (type 'help' for the commands)")
+	}
+	switch command {
+	case "ready":
+		return c.Ready(table)
+	case "leave":
+		return c.Leave(table)
+	case "bid":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: bid <value>")
+		}
+		value, err := strconv.Atoi(args[0])
+		if err != nil || !skat.IsValidBid(value) {
+			return fmt.Errorf("invalid bid: %s", args[0])
+		}
+		return c.Bid(table, value)
+	case "hold", "y":
+		return c.Hold(table)
+	case "pass", "p":
+		return c.Pass(table)
+	case "skat", "s":
+		return c.PickUpSkat(table)
+	case "announce":
+		if len(args) != 1 && len(args) != 3 {
+			return fmt.Errorf("usage: announce <game> [card card]")
+		}
+		contract, err := v.Contract(args[0])
+		if err != nil {
+			return err
+		}
+		var discards []skat.Card
+		for _, arg := range args[1:] {
+			card, err := v.Card(arg)
+			if err != nil {
+				return err
+			}
+			discards = append(discards, card)
+		}
+		return c.Announce(table, contract, discards...)
+	case "say":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: say <text>")
+		}
+		return c.Tell(table, strings.Join(args, " "))
+	case "play":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: play <card>")
+		}
+		card, err := v.Card(args[0])
+		if err != nil {
+			return err
+		}
+		return c.PlayCard(table, card)
+	case "claim":
+		return c.Claim(table)
+	case "concede":
+		return c.Resign(table)
+	case "accept":
+		return c.AcceptClaim(table)
+	case "reject":
+		return c.RejectClaim(table)
+	default:
+		return fmt.Errorf("unknown command: %s (type 'help' for the commands)", command)
+	}
+}
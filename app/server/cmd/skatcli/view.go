@@ -0,0 +1,356 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/render"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// view tracks the game at the table from the user's point of view and prints it.
+// The handlers of the client and the input loop use it concurrently.
+type view struct {
+	out   io.Writer
+	style render.Style
+	mu    sync.Mutex
+
+	table        string
+	players      []string
+	position     *skat.Player
+	hand         *skat.Hand
+	bidding      *skat.BiddingState
+	declarer     *skat.Player
+	contract     *skat.Contract
+	trick        *skat.Trick
+	awaitingSkat bool
+}
+
+// newView creates a view printing to out with the card style.
+func newView(out io.Writer, style render.Style) *view {
+	return &view{out: out, style: style}
+}
+
+// printf prints a line.
+func (v *view) printf(format string, args ...interface{}) {
+	fmt.Fprintf(v.out, format+"\n", args...)
+}
+
+// Table returns the current table ("" if none).
+func (v *view) Table() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.table
+}
+
+// start begins a new game at a table.
+func (v *view) start(s client.GameStart) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.table = s.Table
+	v.players = s.Players
+	v.position = nil
+	v.hand = skat.NewHand()
+	v.bidding = skat.NewBiddingState()
+	v.declarer = nil
+	v.contract = nil
+	v.trick = nil
+	v.awaitingSkat = false
+	v.printf("")
+	v.printf("=== New game at %s: %s ===", s.Table, strings.Join(s.Players, ", "))
+}
+
+// name returns the name of the player at a position.
+func (v *view) name(p skat.Player) string {
+	if p.Index() < len(v.players) {
+		return v.players[p.Index()]
+	}
+	return p.String()
+}
+
+// isMe returns true if the position is the user's.
+func (v *view) isMe(p skat.Player) bool {
+	return v.position != nil && *v.position == p
+}
+
+// move applies a move of the table and prints it.
+func (v *view) move(m client.Move) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.bidding == nil || m.Table != v.table {
+		return
+	}
+
+	player, ok := m.Player.ToPlayer()
+	if !ok {
+		v.worldMove(m)
+		v.prompt()
+		return
+	}
+
+	name := v.name(player)
+	if value, ok := m.Bid(); ok {
+		v.bidding.Bid(player, value)
+		v.printf("%s bids %d", name, value)
+	} else if card, ok := m.Card(); ok {
+		v.cardPlay(player, card)
+	} else if contract, ok := m.Contract(); ok {
+		v.declarer = &player
+		v.contract = contract
+		v.trick = skat.NewTrick(skat.Forehand)
+		if v.isMe(player) {
+			// The announcement carries the discarded cards
+			for _, code := range strings.Split(m.Token, ".")[1:] {
+				if card, err := skat.CardFromCode(code); err == nil {
+					v.hand.Remove(card)
+				}
+			}
+		}
+		v.printf("%s plays %s", name, render.Contract(contract))
+	} else {
+		switch m.Token {
+		case client.TokenHoldBid:
+			v.bidding.Hold(player)
+			v.printf("%s holds %d", name, v.bidding.CurrentBid)
+		case client.TokenPass:
+			v.bidding.Pass(player)
+			v.printf("%s passes", name)
+			if v.bidding.Result == skat.BidResultAllPassed {
+				v.contract = skat.NewContract(skat.GameRamsch)
+				v.trick = skat.NewTrick(skat.Forehand)
+				v.printf("All passed: Ramsch")
+			}
+		case client.TokenSkatRequest:
+			v.awaitingSkat = v.isMe(player)
+			v.printf("%s picks up the skat", name)
+		case client.TokenShowCards, client.TokenResign:
+			verb := "claims"
+			if m.Token == client.TokenResign {
+				verb = "concedes"
+			}
+			v.printf("%s %s the remaining tricks", name, verb)
+			if !v.isMe(player) && v.position != nil && (v.declarer == nil || *v.declarer != *v.position) {
+				v.printf("'accept' or 'reject'")
+			}
+		case client.TokenAcceptClaim:
+			v.printf("%s accepts", name)
+		case client.TokenRejectClaim:
+			v.printf("%s rejects, the play continues", name)
+		default:
+			v.printf("%s: %s", name, m.Token)
+		}
+	}
+	v.prompt()
+}
+
+// open prints the open hand of the declarer of an Ouvert game.
+func (v *view) open(o client.OpenHand) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	player, ok := o.Player.ToPlayer()
+	if o.Table != v.table || !ok || v.isMe(player) {
+		return
+	}
+	v.printf("%s plays open: %s", v.name(player), render.Cards(render.Sorted(o.Hand.Cards, v.contract), v.style))
+}
+
+// bot prints a bot of the game with its difficulty.
+func (v *view) bot(b client.BotSeat) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if b.Table != v.table {
+		return
+	}
+	v.printf("%s is a bot (%s)", b.Name, b.Difficulty)
+}
+
+// handSkat prints the untouched skat of a Hand game after the game.
+func (v *view) handSkat(hs client.HandSkat) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if hs.Table != v.table {
+		return
+	}
+	v.printf("Skat: %s", render.Cards(hs.Skat.Cards, v.style))
+}
+
+// worldMove applies the deal or the skat.
+func (v *view) worldMove(m client.Move) {
+	if hands, _, ok := m.Deal(); ok {
+		for _, p := range skat.AllPlayers {
+			if hands[p].Size() > 0 {
+				position := p
+				v.position = &position
+				v.hand = hands[p]
+			}
+		}
+		if v.position != nil {
+			v.printf("You are %s", v.position)
+		}
+		v.printHand()
+		return
+	}
+	if !v.awaitingSkat {
+		return
+	}
+	cards, err := skat.HandFromCode(m.Token)
+	if err != nil {
+		return
+	}
+	v.awaitingSkat = false
+	v.printf("Skat: %s", render.Cards(cards.Cards, v.style))
+	for _, c := range cards.Cards {
+		v.hand.Add(c)
+	}
+	v.printHand()
+}
+
+// cardPlay adds a played card to the trick.
+func (v *view) cardPlay(player skat.Player, card skat.Card) {
+	v.printf("%s plays %s", v.name(player), render.Card(card, v.style))
+	if v.trick == nil || v.contract == nil {
+		return
+	}
+	if err := v.trick.AddCard(card, player); err != nil {
+		return
+	}
+	if v.isMe(player) {
+		v.hand.Remove(card)
+	}
+	if !v.trick.IsComplete() {
+		return
+	}
+	winner, err := v.trick.DetermineWinner(v.contract.GameType)
+	if err != nil {
+		return
+	}
+	v.printf("Trick to %s (%d points)", v.name(winner), v.trick.Points())
+	v.trick = skat.NewTrick(winner)
+}
+
+// end finishes the game.
+func (v *view) end(e client.GameEnd) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	result := e.Summary
+	if i := strings.Index(result, "R["); i >= 0 {
+		if j := strings.Index(result[i:], "]"); j >= 0 {
+			result = result[i+2 : i+j]
+		}
+	}
+	v.printf("Game over: %s", result)
+	v.printf("Type 'ready' for the next game or 'leave' to leave the table")
+	v.bidding = nil
+}
+
+// prompt tells the user what to do if it is their turn.
+func (v *view) prompt() {
+	if v.position == nil || v.bidding == nil {
+		return
+	}
+	me := *v.position
+
+	switch {
+	case !v.bidding.IsDone():
+		if v.bidding.ActivePlayer != me {
+			return
+		}
+		if v.bidding.Phase == skat.BidPhaseForehandDecides {
+			v.printf("Both others passed: 'bid 18' to declare or 'pass'")
+		} else if v.bidding.IsActiveBidding {
+			v.printf("Your bid: 'bid <value>' (at least %d) or 'pass'", v.bidding.MinimumBid())
+		} else {
+			v.printf("%d? 'hold' or 'pass'", v.bidding.CurrentBid)
+		}
+	case v.contract == nil:
+		if v.bidding.Declarer == nil || *v.bidding.Declarer != me || v.awaitingSkat {
+			return
+		}
+		if v.hand.Size() == 10 {
+			v.printf("You won the bidding at %d: 'skat' to pick up the skat or 'announce <game>' for a hand game (C, S, H, D, G, N; e.g. GO)", v.bidding.FinalBid)
+		} else {
+			v.printf("Discard two cards and announce: 'announce <game> <card> <card>', e.g. 'announce G 3 7'")
+		}
+	default:
+		next := v.trick.NextPlayer()
+		if next == nil || *next != me {
+			return
+		}
+		v.printHand()
+		v.printf("Your turn: 'play <card>'")
+	}
+}
+
+// printHand prints the user's hand, numbered for the commands.
+func (v *view) printHand() {
+	if v.hand == nil || v.hand.Size() == 0 {
+		return
+	}
+	v.printf("Hand: %s", render.NumberedCards(v.sorted(), v.style))
+}
+
+// sorted returns the cards of the hand in display order: by the game, or Jacks
+// first and by suit before the announcement.
+func (v *view) sorted() []skat.Card {
+	return render.Sorted(v.hand.Cards, v.contract)
+}
+
+// Hand prints the hand and the current trick.
+func (v *view) Hand() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.trick != nil && len(v.trick.GetCards()) > 0 {
+		cards := v.trick.GetCards()
+		winning := cards[v.trick.WinningIndex(v.contract.GameType)]
+		v.printf("Trick: %s (%s wins)", render.Cards(cards, v.style), render.Card(winning, v.style))
+	}
+	v.printHand()
+}
+
+// Card returns the card of an argument: its number in the displayed hand or its
+// code (e.g. "CJ").
+func (v *view) Card(arg string) (skat.Card, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if n, err := strconv.Atoi(arg); err == nil {
+		cards := v.sorted()
+		if n < 1 || n > len(cards) {
+			return skat.Card{}, fmt.Errorf("no card %d in your hand", n)
+		}
+		return cards[n-1], nil
+	}
+	return skat.CardFromCode(strings.ToUpper(arg))
+}
+
+// Contract returns the contract of an announcement; games without picking up the
+// skat are hand games.
+func (v *view) Contract(code string) (*skat.Contract, error) {
+	contract, err := skat.ContractFromCode(strings.ToUpper(code))
+	if err != nil {
+		return nil, err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.hand != nil && v.hand.Size() == 10 {
+		contract.Hand = true
+	}
+	return contract, nil
+}
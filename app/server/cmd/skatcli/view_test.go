@@ -0,0 +1,101 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/render"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestViewGame(t *testing.T) {
+	var out bytes.Buffer
+	v := newView(&out, render.Unicode)
+	v.start(client.GameStart{Table: "t1", Players: []string{"anna", "ben", "carl"}})
+
+	moves := []struct {
+		player skat.MovePlayer
+		token  string
+		want   string
+	}{
+		{skat.MoveWorld, "CJ.SJ.HJ.DJ.CA.CT.CK.CQ.C9.C8|??.??.??.??.??.??.??.??.??.??|??.??.??.??.??.??.??.??.??.??|??.??",
+			"Hand: 1:♣J  2:♠J  3:♥J  4:♦J  5:♣A  6:♣10  7:♣K  8:♣Q  9:♣9  10:♣8"},
+		{skat.MoveMiddlehand, "18", "18? 'hold' or 'pass'"},
+		{skat.MoveForehand, client.TokenHoldBid, "anna holds 18"},
+		{skat.MoveMiddlehand, client.TokenPass, "ben passes"},
+		{skat.MoveRearhand, client.TokenPass, "You won the bidding at 18"},
+		{skat.MoveForehand, "GH", "Your turn: 'play <card>'"},
+		{skat.MoveForehand, "CJ", "anna plays ♣J"},
+		{skat.MoveMiddlehand, "S7", "ben plays ♠7"},
+		{skat.MoveRearhand, "S8", "Trick to anna (2 points)"},
+	}
+	for _, m := range moves {
+		out.Reset()
+		v.move(client.Move{Table: "t1", Player: m.player, Token: m.token})
+		if !strings.Contains(out.String(), m.want) {
+			t.Errorf("%s: printed %q, want %q", m.token, out.String(), m.want)
+		}
+	}
+	if position := v.position; position == nil || *position != skat.Forehand {
+		t.Errorf("position = %v, want Forehand", position)
+	}
+
+	// Moves of other tables are ignored
+	out.Reset()
+	v.move(client.Move{Table: "t2", Player: skat.MoveForehand, Token: "SJ"})
+	if out.Len() > 0 {
+		t.Errorf("printed %q for another table", out.String())
+	}
+
+	// Cards are chosen by their number in the hand sorted for the Grand or by code
+	if card, err := v.Card("1"); err != nil || card.Code() != "SJ" {
+		t.Errorf("Card(1) = %v, %v, want SJ", card, err)
+	}
+	if card, err := v.Card("ca"); err != nil || card.Code() != "CA" {
+		t.Errorf("Card(ca) = %v, %v, want CA", card, err)
+	}
+	if _, err := v.Card("10"); err == nil {
+		t.Error("Card(10) accepted a number beyond the hand")
+	}
+
+	out.Reset()
+	v.end(client.GameEnd{Table: "t1", Summary: "(;GM[Skat]R[d:0 win v:192 m:4 bidok p:61];)"})
+	if !strings.Contains(out.String(), "Game over: d:0 win v:192 m:4 bidok p:61") {
+		t.Errorf("end printed %q", out.String())
+	}
+}
+
+func TestViewContract(t *testing.T) {
+	v := newView(&bytes.Buffer{}, render.ASCII)
+	v.start(client.GameStart{Table: "t1", Players: []string{"anna", "ben", "carl"}})
+	v.move(client.Move{Table: "t1", Player: skat.MoveWorld, Token: "CJ.SJ.HJ.DJ.CA.CT.CK.CQ.C9.C8|||??.??"})
+
+	// Without picking up the skat the game is a Hand game
+	contract, err := v.Contract("g")
+	if err != nil || contract.GameType != skat.GameGrand || !contract.Hand {
+		t.Errorf("Contract(g) = %+v, %v, want Grand Hand", contract, err)
+	}
+	v.hand.Add(skat.Card{Suit: skat.Spades, Rank: skat.Seven})
+	if contract, _ := v.Contract("c"); contract == nil || contract.Hand {
+		t.Errorf("Contract(c) with the skat = %+v, want no Hand game", contract)
+	}
+	if _, err := v.Contract("x"); err == nil {
+		t.Error("Contract(x) accepted an unknown game")
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Proxy - A debugging proxy between a client and an ISS server. It logs the
+// traffic in both directions with timestamps, records the finished games as JSON
+// replays and can inject faults (delays, dropped lines, disconnects).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"time"
+)
+
+// proxyConfig holds the proxy configuration.
+type proxyConfig struct {
+	Listen     string
+	Server     string
+	Log        string
+	Record     string
+	Delay      time.Duration
+	Jitter     time.Duration
+	Drop       float64
+	Disconnect float64
+	Faults     string
+	Seed       int64
+}
+
+// parseFlags parses command-line flags and returns a proxyConfig.
+func parseFlags() *proxyConfig {
+	cfg := &proxyConfig{}
+
+	flag.StringVar(&cfg.Listen, "listen", ":7001", "Address the proxy accepts clients on")
+	flag.StringVar(&cfg.Server, "server", "localhost:7000", "Address of the ISS server")
+	flag.StringVar(&cfg.Log, "log", "", "Traffic log file, appended to (default standard output)")
+	flag.StringVar(&cfg.Record, "record", "", "Directory for JSON replays of the finished games (see docs/REPLAY-FORMAT.md)")
+	flag.DurationVar(&cfg.Delay, "delay", 0, "Delay added to every forwarded line")
+	flag.DurationVar(&cfg.Jitter, "jitter", 0, "Random extra delay of up to this duration per line")
+	flag.Float64Var(&cfg.Drop, "drop", 0, "Probability of dropping a line (0-1)")
+	flag.Float64Var(&cfg.Disconnect, "disconnect", 0, "Probability of closing the connection instead of forwarding a line (0-1)")
+	flag.StringVar(&cfg.Faults, "faults", directionBoth, "Direction of the injected faults (both, client, server: lines sent by the client or the server)")
+	flag.Int64Var(&cfg.Seed, "seed", 0, "Random seed of the faults (0 = random)")
+
+	flag.Parse()
+
+	return cfg
+}
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	switch {
+	case cfg.Drop < 0 || cfg.Drop > 1 || cfg.Disconnect < 0 || cfg.Disconnect > 1:
+		log.Fatalf("Invalid configuration: probabilities must be between 0 and 1")
+	case cfg.Delay < 0 || cfg.Jitter < 0:
+		log.Fatalf("Invalid configuration: delays must not be negative")
+	case cfg.Faults != directionBoth && cfg.Faults != directionClient && cfg.Faults != directionServer:
+		log.Fatalf("Invalid configuration: invalid fault direction %q (want %s, %s or %s)", cfg.Faults, directionBoth, directionClient, directionServer)
+	}
+
+	var out io.Writer = os.Stdout
+	if cfg.Log != "" {
+		file, err := os.OpenFile(cfg.Log, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatalf("Failed to open log: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	var games *recorder
+	if cfg.Record != "" {
+		if err := os.MkdirAll(cfg.Record, 0o755); err != nil {
+			log.Fatalf("Failed to create record directory: %v", err)
+		}
+		games = newRecorder(cfg.Record)
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	p := &proxy{
+		server: cfg.Server,
+		log:    &trafficLog{out: out},
+		games:  games,
+		faults: &faults{
+			delay:      cfg.Delay,
+			jitter:     cfg.Jitter,
+			drop:       cfg.Drop,
+			disconnect: cfg.Disconnect,
+			direction:  cfg.Faults,
+			rng:        rand.New(rand.NewSource(seed)),
+		},
+	}
+
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", cfg.Listen, err)
+	}
+	fmt.Fprintf(os.Stderr, "FreeSkat Proxy listening on %s, forwarding to %s\n", listener.Addr(), cfg.Server)
+	if p.faults.active() {
+		fmt.Fprintf(os.Stderr, "Injecting faults (%s): delay %s, jitter %s, drop %.2f, disconnect %.2f, seed %d\n",
+			cfg.Faults, cfg.Delay, cfg.Jitter, cfg.Drop, cfg.Disconnect, seed)
+	}
+
+	for id := 1; ; id++ {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Fatalf("Failed to accept connection: %v", err)
+		}
+		go p.serve(id, conn)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault directions.
+const (
+	directionBoth   = "both"
+	directionClient = "client"
+	directionServer = "server"
+)
+
+// logTimeLayout is the timestamp format of the traffic log.
+const logTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// proxy forwards the connections of the clients to the server.
+type proxy struct {
+	server string
+	log    *trafficLog
+	games  *recorder
+	faults *faults
+}
+
+// serve connects a client to the server and forwards the lines in both directions
+// until one side closes its connection.
+func (p *proxy) serve(id int, client net.Conn) {
+	defer client.Close()
+	p.log.event(id, "connected from %s", client.RemoteAddr())
+
+	server, err := net.DialTimeout("tcp", p.server, 10*time.Second)
+	if err != nil {
+		p.log.event(id, "failed to connect to %s: %v", p.server, err)
+		return
+	}
+	defer server.Close()
+
+	var once sync.Once
+	closeBoth := func() {
+		once.Do(func() {
+			client.Close()
+			server.Close()
+		})
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.forward(id, client, server, directionClient, closeBoth)
+	}()
+	p.forward(id, server, client, directionServer, closeBoth)
+	<-done
+	p.log.event(id, "closed")
+}
+
+// forward copies the lines sent by one side (client or server) to the other, logging
+// each line and applying the faults. Closing either connection ends both directions.
+func (p *proxy) forward(id int, from, to net.Conn, sender string, closeBoth func()) {
+	defer closeBoth()
+	reader := bufio.NewReader(from)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			line = strings.TrimRight(line, "\r\n")
+			switch p.faults.decide(sender) {
+			case faultDrop:
+				p.log.line(id, sender, line, "dropped")
+				continue
+			case faultDisconnect:
+				p.log.line(id, sender, line, "disconnect")
+				return
+			}
+			if delay := p.faults.wait(sender); delay > 0 {
+				time.Sleep(delay)
+				p.log.line(id, sender, line, fmt.Sprintf("delayed %s", delay))
+			} else {
+				p.log.line(id, sender, line, "")
+			}
+			if sender == directionServer && p.games != nil {
+				p.games.observe(id, line, p.log)
+			}
+			if _, err := io.WriteString(to, line+"\n"); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// trafficLog writes the forwarded lines with timestamps, one per log line:
+// "<time> #<connection> C> <line>" for lines of the client, "S>" for lines of the
+// server and "--" for events of the connection.
+type trafficLog struct {
+	out io.Writer
+	mu  sync.Mutex
+}
+
+// line logs a forwarded line with an optional note on the applied fault.
+func (l *trafficLog) line(id int, sender, line, note string) {
+	arrow := "C>"
+	if sender == directionServer {
+		arrow = "S>"
+	}
+	if note != "" {
+		line += "    [" + note + "]"
+	}
+	l.write(id, arrow, line)
+}
+
+// event logs an event of a connection.
+func (l *trafficLog) event(id int, format string, args ...any) {
+	l.write(id, "--", fmt.Sprintf(format, args...))
+}
+
+// write writes a log line.
+func (l *trafficLog) write(id int, marker, text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.out, "%s #%d %s %s\n", time.Now().Format(logTimeLayout), id, marker, text)
+}
+
+// Fault decisions for a line.
+const (
+	faultNone = iota
+	faultDrop
+	faultDisconnect
+)
+
+// faults injects delays, dropped lines and disconnects into the lines of one or both
+// directions.
+type faults struct {
+	delay      time.Duration
+	jitter     time.Duration
+	drop       float64
+	disconnect float64
+	direction  string
+	rng        *rand.Rand
+	mu         sync.Mutex
+}
+
+// active returns true if any fault is configured.
+func (f *faults) active() bool {
+	return f.delay > 0 || f.jitter > 0 || f.drop > 0 || f.disconnect > 0
+}
+
+// applies returns true if the faults apply to the lines of the sender.
+func (f *faults) applies(sender string) bool {
+	return f.direction == directionBoth || f.direction == sender
+}
+
+// decide returns whether a line of the sender is dropped, ends the connection or is
+// forwarded.
+func (f *faults) decide(sender string) int {
+	if !f.applies(sender) || (f.drop == 0 && f.disconnect == 0) {
+		return faultNone
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r := f.rng.Float64(); {
+	case r < f.disconnect:
+		return faultDisconnect
+	case r < f.disconnect+f.drop:
+		return faultDrop
+	default:
+		return faultNone
+	}
+}
+
+// wait returns the delay of a line of the sender.
+func (f *faults) wait(sender string) time.Duration {
+	if !f.applies(sender) {
+		return 0
+	}
+	delay := f.delay
+	if f.jitter > 0 {
+		f.mu.Lock()
+		delay += time.Duration(f.rng.Int63n(int64(f.jitter) + 1))
+		f.mu.Unlock()
+	}
+	return delay
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// startServer starts a server answering each line with "echo <line>". On "bye" it
+// sends the end of a game twice, as seen by two players, and closes the connection.
+func startServer(t *testing.T, summary string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				lines := bufio.NewScanner(conn)
+				for lines.Scan() {
+					if lines.Text() == "bye" {
+						fmt.Fprintf(conn, "table t1 anna end %s\ntable t1 ben end %s\n", summary, summary)
+						return
+					}
+					fmt.Fprintf(conn, "echo %s\n", lines.Text())
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// proxyConn connects a client through the proxy and returns the client's end. The
+// returned channel is closed when the proxy closed the connection.
+func proxyConn(p *proxy) (net.Conn, <-chan struct{}) {
+	server, client := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.serve(1, server)
+	}()
+	return client, done
+}
+
+func TestProxy(t *testing.T) {
+	record := recordtest.Played(t, 1)
+	summary, err := protocol.NewGameSummary(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	var traffic bytes.Buffer
+	p := &proxy{
+		server: startServer(t, summary.Encode()),
+		log:    &trafficLog{out: &traffic},
+		games:  newRecorder(dir),
+		faults: &faults{direction: directionBoth, rng: rand.New(rand.NewSource(1))},
+	}
+
+	client, done := proxyConn(p)
+	lines := bufio.NewReader(client)
+	fmt.Fprintf(client, "login anna\r\n")
+	if line, _ := lines.ReadString('\n'); line != "echo login anna\n" {
+		t.Errorf("client received %q", line)
+	}
+	fmt.Fprintf(client, "bye\n")
+	received, _ := io.ReadAll(lines)
+	client.Close()
+	<-done
+
+	if n := strings.Count(string(received), " end "); n != 2 {
+		t.Errorf("client received %d game ends, want 2", n)
+	}
+	log := traffic.String()
+	for _, want := range []string{"#1 -- connected from", "#1 C> login anna\n", "#1 S> echo login anna\n", "#1 C> bye\n", "#1 -- recorded game", "#1 -- closed"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("no %q in the log:\n%s", want, log)
+		}
+	}
+
+	// The game seen by both players is recorded once
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 || filepath.Base(files[0]) != "g1.json" {
+		t.Fatalf("recorded %q, want g1.json", files)
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	game, err := replay.Read(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := replay.New(record)
+	if game.ID != "g1" || len(game.Moves) != len(want.Moves) || game.Contract != want.Contract {
+		t.Errorf("recorded game %+v", game)
+	}
+}
+
+func TestProxyFaults(t *testing.T) {
+	var traffic bytes.Buffer
+	p := &proxy{
+		server: startServer(t, ""),
+		log:    &trafficLog{out: &traffic},
+		faults: &faults{drop: 1, delay: 5 * time.Millisecond, direction: directionServer, rng: rand.New(rand.NewSource(1))},
+	}
+	client, done := proxyConn(p)
+	fmt.Fprintf(client, "login anna\n")
+	fmt.Fprintf(client, "bye\n")
+	client.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	if received, _ := io.ReadAll(client); len(received) > 0 {
+		t.Errorf("client received %q, want all server lines dropped", received)
+	}
+	client.Close()
+	<-done
+	if log := traffic.String(); !strings.Contains(log, "C> login anna\n") || !strings.Contains(log, "S> echo login anna    [dropped]") {
+		t.Errorf("log without the dropped server line:\n%s", log)
+	}
+
+	f := &faults{delay: time.Second, jitter: 10 * time.Millisecond, disconnect: 1, direction: directionClient, rng: rand.New(rand.NewSource(1))}
+	if f.decide(directionClient) != faultDisconnect || f.decide(directionServer) != faultNone {
+		t.Error("the disconnect does not apply to the client lines only")
+	}
+	if delay := f.wait(directionClient); delay < time.Second || delay > time.Second+10*time.Millisecond || f.wait(directionServer) != 0 {
+		t.Errorf("wait() = %s", delay)
+	}
+}
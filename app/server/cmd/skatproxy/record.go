@@ -0,0 +1,104 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// unsafeFileChars are replaced in the file names of recorded games.
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// recorder saves the games ending at the proxied tables as JSON replays, the format of
+// the golden game corpus and the analyzer. A game seen by several proxied players is
+// saved once.
+type recorder struct {
+	dir   string
+	saved map[string]bool
+	count int
+	mu    sync.Mutex
+}
+
+// newRecorder creates a recorder saving to the directory.
+func newRecorder(dir string) *recorder {
+	return &recorder{dir: dir, saved: make(map[string]bool)}
+}
+
+// observe saves the game of a game end message of the server:
+// "table <name> <login> end <summary>".
+func (r *recorder) observe(id int, line string, traffic *trafficLog) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[0] != "table" || fields[3] != "end" {
+		return
+	}
+	file, err := r.save(strings.Join(fields[4:], " "))
+	switch {
+	case err != nil:
+		traffic.event(id, "failed to record game: %v", err)
+	case file != "":
+		traffic.event(id, "recorded game %s", file)
+	}
+}
+
+// save converts a game summary to a replay and writes it. It returns the written file,
+// "" if the game has been saved before.
+func (r *recorder) save(text string) (string, error) {
+	summary, err := protocol.ParseGameSummary(text)
+	if err != nil {
+		return "", err
+	}
+	record, err := summary.Record()
+	if err != nil {
+		return "", err
+	}
+	game, err := replay.New(record)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if summary.ID != "" && r.saved[summary.ID] {
+		return "", nil
+	}
+	r.count++
+	name := unsafeFileChars.ReplaceAllString(summary.ID, "_")
+	if name == "" {
+		name = fmt.Sprintf("game-%d", r.count)
+	}
+	file := filepath.Join(r.dir, name+".json")
+
+	out, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	if err := game.Write(out); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	r.saved[summary.ID] = true
+	return file, nil
+}
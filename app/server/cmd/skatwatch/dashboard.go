@@ -0,0 +1,343 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/render"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// chatLines is the number of chat lines shown.
+const chatLines = 8
+
+// tableInfo is an observable table of the lobby.
+type tableInfo struct {
+	Table   string
+	Player  string
+	Players []string
+}
+
+// dashboard tracks the lobby and the watched table and renders them. The handlers of
+// the client and the input loop use it concurrently.
+type dashboard struct {
+	out   io.Writer
+	style render.Style
+	mu    sync.Mutex
+
+	tables map[string]tableInfo
+
+	player   string
+	table    string
+	players  []string
+	bidding  *skat.BiddingState
+	declarer string
+	contract *skat.Contract
+	trick    *skat.Trick
+	tricks   []render.Trick
+	scores   []render.ScoreLine
+	chat     []string
+	status   string
+}
+
+// newDashboard creates a dashboard rendering to out with the card style.
+func newDashboard(out io.Writer, style render.Style) *dashboard {
+	return &dashboard{out: out, style: style, tables: make(map[string]tableInfo)}
+}
+
+// Watching returns the watched table and its player ("" if none).
+func (d *dashboard) Watching() (string, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.table, d.player
+}
+
+// TablePlayer returns the player of a table by its number in the list or the player's
+// login.
+func (d *dashboard) TablePlayer(arg string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, t := range d.sortedTables() {
+		if arg == fmt.Sprint(i+1) || arg == t.Player {
+			return t.Player, true
+		}
+	}
+	return "", false
+}
+
+// Watch starts watching the table of a player; the table messages follow.
+func (d *dashboard) Watch(player string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.player = player
+	d.table = ""
+	d.scores = nil
+	d.chat = nil
+	d.reset(nil)
+	d.status = "Observing the table of " + player
+	d.render()
+}
+
+// Status shows a status line.
+func (d *dashboard) Status(format string, args ...interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.status = fmt.Sprintf(format, args...)
+	d.render()
+}
+
+// Render redraws the dashboard.
+func (d *dashboard) Render() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.render()
+}
+
+// lobby applies a table list update: "tables add <name> <player> <players...>" or
+// "tables remove <name> <player>".
+func (d *dashboard) lobby(u client.LobbyUpdate) {
+	if u.Kind != "tables" || len(u.Args) < 3 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch u.Args[0] {
+	case "add":
+		d.tables[u.Args[2]] = tableInfo{Table: u.Args[1], Player: u.Args[2], Players: u.Args[3:]}
+	case "remove":
+		delete(d.tables, u.Args[2])
+	default:
+		return
+	}
+	d.render()
+}
+
+// start begins a new game at the watched table.
+func (d *dashboard) start(s client.GameStart) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.player == "" {
+		return
+	}
+	d.table = s.Table
+	d.reset(s.Players)
+	d.render()
+}
+
+// reset clears the game state for a new game.
+func (d *dashboard) reset(players []string) {
+	d.players = players
+	d.bidding = skat.NewBiddingState()
+	d.declarer = ""
+	d.contract = nil
+	d.trick = nil
+	d.tricks = nil
+}
+
+// name returns the name of the player at a position.
+func (d *dashboard) name(p skat.Player) string {
+	if p.Index() < len(d.players) {
+		return d.players[p.Index()]
+	}
+	return p.String()
+}
+
+// move applies a move of the watched table.
+func (d *dashboard) move(m client.Move) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	player, ok := m.Player.ToPlayer()
+	if m.Table != d.table || !ok {
+		return
+	}
+
+	if value, ok := m.Bid(); ok {
+		d.bidding.Bid(player, value)
+	} else if card, ok := m.Card(); ok {
+		d.cardPlay(player, card)
+	} else if contract, ok := m.Contract(); ok {
+		d.declarer = d.name(player)
+		d.contract = contract
+		d.trick = skat.NewTrick(skat.Forehand)
+	} else if m.Token == client.TokenHoldBid {
+		d.bidding.Hold(player)
+	} else if m.Token == client.TokenPass {
+		d.bidding.Pass(player)
+		if d.bidding.Result == skat.BidResultAllPassed {
+			d.contract = skat.NewContract(skat.GameRamsch)
+			d.trick = skat.NewTrick(skat.Forehand)
+		}
+	}
+	d.render()
+}
+
+// cardPlay adds a played card to the trick.
+func (d *dashboard) cardPlay(player skat.Player, card skat.Card) {
+	if d.trick == nil {
+		return
+	}
+	if err := d.trick.AddCard(card, player); err != nil || !d.trick.IsComplete() {
+		return
+	}
+	winner, err := d.trick.DetermineWinner(d.contract.GameType)
+	if err != nil {
+		return
+	}
+	d.tricks = append(d.tricks, render.Trick{
+		Cards:  d.trick.GetCards(),
+		Winner: d.name(winner),
+		Points: d.trick.Points(),
+	})
+	d.trick = skat.NewTrick(winner)
+}
+
+// end adds the finished game to the score sheet.
+func (d *dashboard) end(e client.GameEnd) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if e.Table != d.table {
+		return
+	}
+	summary, err := protocol.ParseGameSummary(e.Summary)
+	if err != nil {
+		d.status = "Invalid game summary: " + err.Error()
+		d.render()
+		return
+	}
+
+	line := render.ScoreLine{Declarer: "-", Game: "passed"}
+	if !summary.Result.Passed {
+		line.Declarer = summary.Players[summary.Result.Declarer.Index()]
+		line.Game = render.Contract(d.contract)
+		line.Value = summary.Result.Value
+	}
+	d.scores = append(d.scores, line)
+	d.status = "Game over"
+	d.render()
+}
+
+// destroyed stops watching a closed table.
+func (d *dashboard) destroyed(table string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if table != d.table {
+		return
+	}
+	d.status = fmt.Sprintf("Table %s of %s closed", d.table, d.player)
+	d.player = ""
+	d.table = ""
+	d.render()
+}
+
+// message adds a chat message.
+func (d *dashboard) message(c client.Chat) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c.Table != d.table {
+		return
+	}
+	d.chat = append(d.chat, fmt.Sprintf("<%s> %s", c.Sender, c.Text))
+	if len(d.chat) > chatLines {
+		d.chat = d.chat[len(d.chat)-chatLines:]
+	}
+	d.render()
+}
+
+// sortedTables returns the tables ordered by name and player.
+func (d *dashboard) sortedTables() []tableInfo {
+	tables := make([]tableInfo, 0, len(d.tables))
+	for _, t := range d.tables {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].Table != tables[j].Table {
+			return tables[i].Table < tables[j].Table
+		}
+		return tables[i].Player < tables[j].Player
+	})
+	return tables
+}
+
+// render redraws the dashboard. The caller must hold the lock.
+func (d *dashboard) render() {
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	b.WriteString("FreeSkat Watch\n\n")
+
+	b.WriteString("Tables:\n")
+	tables := d.sortedTables()
+	if len(tables) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for i, t := range tables {
+		marker := " "
+		if t.Player == d.player {
+			marker = "*"
+		}
+		fmt.Fprintf(&b, " %s%2d. %-20s %s\n", marker, i+1, t.Table, strings.Join(t.Players, ", "))
+	}
+
+	if d.table != "" {
+		d.renderTable(&b)
+	}
+
+	if len(d.chat) > 0 {
+		b.WriteString("\nChat:\n")
+		for _, line := range d.chat {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	if d.status != "" {
+		b.WriteString("\n" + d.status + "\n")
+	}
+	b.WriteString("> ")
+	io.WriteString(d.out, b.String())
+}
+
+// renderTable writes the watched table: the game, the tricks and the score sheet.
+func (d *dashboard) renderTable(b *strings.Builder) {
+	view := &render.View{
+		Table:    d.table,
+		Players:  d.players,
+		Declarer: d.declarer,
+		Contract: d.contract,
+		Tricks:   d.tricks,
+	}
+	if d.bidding != nil {
+		view.Bid = d.bidding.CurrentBid
+	}
+	if d.trick != nil {
+		view.Trick = d.trick.GetCards()
+		view.Winning = d.trick.WinningIndex(d.contract.GameType)
+	}
+	b.WriteString("\n")
+	view.Write(b, d.style)
+
+	if len(d.scores) == 0 {
+		return
+	}
+	b.WriteString("\nScore sheet:\n")
+	render.WriteScores(b, d.scores)
+}
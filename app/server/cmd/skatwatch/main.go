@@ -0,0 +1,196 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// FreeSkat Watch - A terminal dashboard observing the tables of a FreeSkat server.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/render"
+)
+
+// watchConfig holds the dashboard configuration.
+type watchConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Player   string
+	ASCII    bool
+}
+
+// parseFlags parses command-line flags and returns a watchConfig.
+func parseFlags() *watchConfig {
+	cfg := &watchConfig{}
+
+	flag.StringVar(&cfg.Host, "host", "localhost", "Server host to connect to")
+	flag.IntVar(&cfg.Port, "port", 7000, "Server TCP port")
+	flag.StringVar(&cfg.Username, "user", os.Getenv("USER"), "Login name")
+	flag.StringVar(&cfg.Password, "password", "", "Login password")
+	flag.StringVar(&cfg.Player, "player", "", "Observe the table of this player right away")
+	flag.BoolVar(&cfg.ASCII, "ascii", false, "Show cards as ASCII codes (e.g. CJ) instead of suit symbols")
+
+	flag.Parse()
+
+	return cfg
+}
+
+// helpText lists the commands.
+const helpText = "Commands: watch <number|player>, leave, say <text>, help, quit"
+
+func main() {
+	log.SetFlags(0)
+
+	cfg := parseFlags()
+	if cfg.Username == "" || cfg.Password == "" {
+		log.Fatalf("Invalid configuration: login name (-user) and password (-password) required")
+	}
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	c, err := client.Dial(address)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", address, err)
+	}
+	if err := c.Login(cfg.Username, cfg.Password); err != nil {
+		log.Fatalf("Failed to log in: %v", err)
+	}
+
+	style := render.Unicode
+	if cfg.ASCII {
+		style = render.ASCII
+	}
+	d := newDashboard(os.Stdout, style)
+	d.Status("Connected to %s as %s. %s", address, cfg.Username, helpText)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Run(client.Handlers{
+			Lobby:     d.lobby,
+			Start:     d.start,
+			Move:      d.move,
+			End:       d.end,
+			Destroyed: d.destroyed,
+			Chat:      d.message,
+			Text: func(text string) {
+				d.Status("%s", text)
+			},
+			Error: func(text string) {
+				d.Status("! %s", text)
+			},
+		})
+	}()
+	if cfg.Player != "" {
+		d.Watch(cfg.Player)
+		if err := c.ObservePlayer(cfg.Player); err != nil {
+			log.Fatalf("Connection closed: %v", err)
+		}
+	}
+
+	input := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			input <- scanner.Text()
+		}
+		close(input)
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			fmt.Println()
+			if err != nil {
+				log.Fatalf("Connection closed: %v", err)
+			}
+			return
+		case line, ok := <-input:
+			if !ok || isQuit(line) {
+				if table, _ := d.Watching(); table != "" {
+					c.Leave(table)
+				}
+				c.Close()
+				fmt.Println()
+				return
+			}
+			if err := execute(c, d, line); err != nil {
+				d.Status("! %v", err)
+			}
+		}
+	}
+}
+
+// isQuit returns true for the quit commands.
+func isQuit(line string) bool {
+	switch strings.TrimSpace(line) {
+	case "quit", "exit":
+		return true
+	default:
+		return false
+	}
+}
+
+// execute executes a command line of the user.
+func execute(c *client.Client, d *dashboard, line string) error {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		d.Render()
+		return nil
+	}
+
+	command, args := parts[0], parts[1:]
+	table, _ := d.Watching()
+	switch command {
+	case "help":
+		d.Status("%s", helpText)
+		return nil
+	case "watch":
+		if len(args) < 1 {
+			return fmt.Errorf("usage: watch <number|player>")
+		}
+		next, ok := d.TablePlayer(args[0])
+		if !ok {
+			return fmt.Errorf("no table %s", args[0])
+		}
+		if table != "" {
+			if err := c.Leave(table); err != nil {
+				return err
+			}
+		}
+		d.Watch(next)
+		return c.ObservePlayer(next)
+	case "leave":
+		if table == "" {
+			return fmt.Errorf("not watching a table")
+		}
+		return c.Leave(table)
+	case "say":
+		if table == "" {
+			return fmt.Errorf("not watching a table")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("usage: say <text>")
+		}
+		return c.Tell(table, strings.Join(args, " "))
+	default:
+		return fmt.Errorf("unknown command: %s (%s)", command, helpText)
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/ban"
+	"github.com/mkloubert/freeskat-server/internal/moderation"
+	"github.com/mkloubert/freeskat-server/internal/motd"
+	"github.com/mkloubert/freeskat-server/internal/profile"
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// maxAdminRequestSize is the maximum size of an admin request body
+const maxAdminRequestSize = 4096
+
+// admin holds the state of the admin endpoints.
+type admin struct {
+	token    string
+	sessions *session.Manager
+	handler  *protocol.Handler
+	bans     *ban.Store
+	mutes    *moderation.Mutes
+	messages *motd.Messages
+	// backupDir is the directory of the backups ("" = disabled)
+	backupDir string
+	started   time.Time
+}
+
+// adminSession describes a connected session.
+type adminSession struct {
+	ID         string          `json:"id"`
+	Login      string          `json:"login,omitempty"`
+	Remote     string          `json:"remote"`
+	Transport  string          `json:"transport"`
+	Client     *session.Client `json:"client,omitempty"`
+	Traffic    session.Traffic `json:"traffic"`
+	Connected  time.Time       `json:"connected"`
+	LastActive time.Time       `json:"lastActive"`
+}
+
+// adminMetrics are the server metrics.
+type adminMetrics struct {
+	Uptime     string `json:"uptime"`
+	Sessions   int    `json:"sessions"`
+	LoggedIn   int    `json:"loggedIn"`
+	Tables     int    `json:"tables"`
+	Observers  int    `json:"observers"`
+	Games      int    `json:"games"`
+	Goroutines int    `json:"goroutines"`
+	HeapBytes  uint64 `json:"heapBytes"`
+	// Traffic is the traffic of all sessions since the start
+	Traffic session.Traffic `json:"traffic"`
+}
+
+// SetAdmin enables the admin endpoints under /api/admin, authorized with the bearer
+// token. Without a token they answer 404.
+func (a *API) SetAdmin(token string, sessions *session.Manager, handler *protocol.Handler) {
+	a.admin = &admin{token: token, sessions: sessions, handler: handler, started: time.Now()}
+}
+
+// SetBans sets the ban store of the admin endpoints (requires SetAdmin).
+func (a *API) SetBans(store *ban.Store) {
+	a.admin.bans = store
+}
+
+// SetMutes sets the muted logins of the chat moderation (requires SetAdmin).
+func (a *API) SetMutes(store *moderation.Mutes) {
+	a.admin.mutes = store
+}
+
+// SetMessages sets the welcome and MOTD templates reloaded by the admin endpoints
+// (requires SetAdmin).
+func (a *API) SetMessages(messages *motd.Messages) {
+	a.admin.messages = messages
+}
+
+// SetBackupDir enables the backups of the admin endpoints (requires SetAdmin).
+func (a *API) SetBackupDir(dir string) {
+	a.admin.backupDir = dir
+}
+
+// registerAdmin registers the admin endpoints.
+func (a *API) registerAdmin() {
+	a.handle(route{pattern: "GET /api/admin/sessions", handler: a.handleAdminSessions, summary: "Connected sessions, oldest first",
+		response: object{"sessions": []adminSession{}}, admin: true})
+	a.handle(route{pattern: "DELETE /api/admin/sessions/{id}", handler: a.handleAdminKick, summary: "Disconnect a session",
+		status: http.StatusNoContent, admin: true})
+	a.handle(route{pattern: "GET /api/admin/tables", handler: a.handleAdminTables, summary: "Running bot tables",
+		response: object{"tables": []protocol.TableInfo{}}, admin: true})
+	a.handle(route{pattern: "DELETE /api/admin/tables/{player}", handler: a.handleAdminCloseTable, summary: "Close the bot table of a player",
+		status: http.StatusNoContent, admin: true})
+	a.handle(route{pattern: "GET /api/admin/bans", handler: a.handleAdminBans, summary: "Banned logins",
+		response: object{"bans": []ban.Ban{}}, admin: true})
+	a.handle(route{pattern: "PUT /api/admin/bans/{login}", handler: a.handleAdminBan, summary: "Ban a login and disconnect its sessions",
+		body: object{"reason": ""}, response: ban.Ban{}, admin: true})
+	a.handle(route{pattern: "DELETE /api/admin/bans/{login}", handler: a.handleAdminUnban, summary: "Lift the ban of a login",
+		status: http.StatusNoContent, admin: true})
+	a.handle(route{pattern: "PUT /api/admin/profiles/{login}", handler: a.handleAdminProfile, summary: "Replace the profile of a player, an empty one removes it",
+		body: profile.Profile{}, response: profile.Profile{}, admin: true})
+	a.handle(route{pattern: "DELETE /api/admin/profiles/{login}", handler: a.handleAdminDeleteProfile, summary: "Remove the profile of a player",
+		status: http.StatusNoContent, admin: true})
+	a.handle(route{pattern: "GET /api/admin/mutes", handler: a.handleAdminMutes, summary: "Logins muted by the chat moderation",
+		response: object{"mutes": []moderation.Mute{}}, admin: true})
+	a.handle(route{pattern: "DELETE /api/admin/mutes/{login}", handler: a.handleAdminUnmute, summary: "Lift the mute of a login",
+		status: http.StatusNoContent, admin: true})
+	a.handle(route{pattern: "POST /api/admin/broadcast", handler: a.handleAdminBroadcast, summary: "Send a text message to all logged-in clients",
+		body: object{"text": ""}, response: object{"sent": 0}, admin: true})
+	a.handle(route{pattern: "POST /api/admin/motd/reload", handler: a.handleAdminReloadMessages, summary: "Reload the welcome and MOTD templates",
+		status: http.StatusNoContent, admin: true})
+	a.handle(route{pattern: "POST /api/admin/backup", handler: a.handleAdminBackup, summary: "Back up the game archive",
+		status: http.StatusCreated, response: archive.Backup{}, admin: true})
+	a.handle(route{pattern: "GET /api/admin/metrics", handler: a.handleAdminMetrics, summary: "Server metrics",
+		response: adminMetrics{}, admin: true})
+}
+
+// authorized wraps an admin endpoint: 404 if the admin API is disabled, 401 without
+// the bearer token.
+func (a *API) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if a.admin == nil || a.admin.token == "" {
+			http.NotFound(w, r)
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(a.admin.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="freeskat-admin"`)
+			writeError(w, http.StatusUnauthorized, errors.New("invalid admin token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleAdminSessions lists the connected sessions, oldest first.
+func (a *API) handleAdminSessions(w http.ResponseWriter, r *http.Request) {
+	list := a.admin.sessions.List()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+
+	sessions := make([]adminSession, 0, len(list))
+	for _, s := range list {
+		sessions = append(sessions, adminSession{
+			ID:         s.ID(),
+			Login:      s.Username(),
+			Remote:     s.RemoteAddr(),
+			Transport:  s.Transport,
+			Client:     s.Client(),
+			Traffic:    s.Traffic(),
+			Connected:  s.CreatedAt,
+			LastActive: s.LastActive(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string][]adminSession{"sessions": sessions})
+}
+
+// handleAdminKick disconnects a session. Its game is archived as it is.
+func (a *API) handleAdminKick(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !a.admin.sessions.Kick(id) {
+		writeError(w, http.StatusNotFound, errors.New("session not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminTables lists the running bot tables.
+func (a *API) handleAdminTables(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]protocol.TableInfo{"tables": a.admin.handler.Tables()})
+}
+
+// handleAdminCloseTable closes the bot table of a player.
+func (a *API) handleAdminCloseTable(w http.ResponseWriter, r *http.Request) {
+	if !a.admin.handler.CloseTable(r.PathValue("player")) {
+		writeError(w, http.StatusNotFound, errors.New("table not found"))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminBans lists the banned logins.
+func (a *API) handleAdminBans(w http.ResponseWriter, r *http.Request) {
+	bans := []ban.Ban{}
+	if a.admin.bans != nil {
+		bans = a.admin.bans.List()
+	}
+	writeJSON(w, http.StatusOK, map[string][]ban.Ban{"bans": bans})
+}
+
+// handleAdminBan bans a login ({"reason": "..."}, optional) and disconnects its sessions.
+func (a *API) handleAdminBan(w http.ResponseWriter, r *http.Request) {
+	if a.admin.bans == nil {
+		writeError(w, http.StatusNotFound, errors.New("bans not available"))
+		return
+	}
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminRequestSize)).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	login := r.PathValue("login")
+	b, err := a.admin.bans.Ban(login, body.Reason)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, s := range a.admin.sessions.List() {
+		if s.Username() == login {
+			a.admin.sessions.RemoveSession(s.ID())
+		}
+	}
+	writeJSON(w, http.StatusOK, b)
+}
+
+// handleAdminUnban lifts the ban of a login.
+func (a *API) handleAdminUnban(w http.ResponseWriter, r *http.Request) {
+	if a.admin.bans == nil {
+		writeError(w, http.StatusNotFound, ban.ErrNotFound)
+		return
+	}
+	err := a.admin.bans.Unban(r.PathValue("login"))
+	switch {
+	case errors.Is(err, ban.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleAdminProfile replaces the profile of a player (a profile.Profile without login
+// and time), e.g. to correct an offensive display name.
+func (a *API) handleAdminProfile(w http.ResponseWriter, r *http.Request) {
+	if a.profiles == nil {
+		writeError(w, http.StatusNotFound, errors.New("profiles not available"))
+		return
+	}
+	var p profile.Profile
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminRequestSize)).Decode(&p); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	p.Login = r.PathValue("login")
+	p, err := a.profiles.Put(p)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// handleAdminDeleteProfile removes the profile of a player.
+func (a *API) handleAdminDeleteProfile(w http.ResponseWriter, r *http.Request) {
+	if a.profiles == nil {
+		writeError(w, http.StatusNotFound, profile.ErrNotFound)
+		return
+	}
+	err := a.profiles.Delete(r.PathValue("login"))
+	switch {
+	case errors.Is(err, profile.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleAdminMutes lists the muted logins.
+func (a *API) handleAdminMutes(w http.ResponseWriter, r *http.Request) {
+	mutes := []moderation.Mute{}
+	if a.admin.mutes != nil {
+		mutes = a.admin.mutes.List()
+	}
+	writeJSON(w, http.StatusOK, map[string][]moderation.Mute{"mutes": mutes})
+}
+
+// handleAdminUnmute lifts the mute of a login.
+func (a *API) handleAdminUnmute(w http.ResponseWriter, r *http.Request) {
+	if a.admin.mutes == nil {
+		writeError(w, http.StatusNotFound, moderation.ErrNotMuted)
+		return
+	}
+	err := a.admin.mutes.Unmute(r.PathValue("login"))
+	switch {
+	case errors.Is(err, moderation.ErrNotMuted):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// handleAdminBroadcast sends a text message ({"text": "..."}) to all logged-in clients.
+func (a *API) handleAdminBroadcast(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdminRequestSize)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	text := strings.Join(strings.Fields(body.Text), " ")
+	if text == "" {
+		writeError(w, http.StatusBadRequest, errors.New("text required"))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"sent": a.admin.handler.Broadcast(text)})
+}
+
+// handleAdminBackup writes a backup of the game archive to the backup directory.
+func (a *API) handleAdminBackup(w http.ResponseWriter, r *http.Request) {
+	if a.admin.backupDir == "" {
+		writeError(w, http.StatusNotFound, errors.New("backups not enabled"))
+		return
+	}
+	backup, err := a.archive.Backup(a.admin.backupDir)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, backup)
+}
+
+// handleAdminReloadMessages reads the welcome and MOTD templates again.
+func (a *API) handleAdminReloadMessages(w http.ResponseWriter, r *http.Request) {
+	if a.admin.messages == nil {
+		writeError(w, http.StatusNotFound, errors.New("welcome and MOTD templates not enabled"))
+		return
+	}
+	if err := a.admin.messages.Reload(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAdminMetrics returns the server metrics.
+func (a *API) handleAdminMetrics(w http.ResponseWriter, r *http.Request) {
+	ids, err := a.archive.IDs()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	m := adminMetrics{
+		Uptime:     time.Since(a.admin.started).Round(time.Second).String(),
+		Games:      len(ids),
+		Goroutines: runtime.NumGoroutine(),
+		Traffic:    a.admin.sessions.Traffic(),
+	}
+	for _, s := range a.admin.sessions.List() {
+		m.Sessions++
+		if s.Username() != "" {
+			m.LoggedIn++
+		}
+	}
+	for _, t := range a.admin.handler.Tables() {
+		m.Tables++
+		m.Observers += t.Observers
+	}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	m.HeapBytes = mem.HeapAlloc
+	writeJSON(w, http.StatusOK, m)
+}
@@ -0,0 +1,800 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package api provides the HTTP REST API of the server.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/challenge"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/league"
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/internal/profile"
+	"github.com/mkloubert/freeskat-server/internal/season"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/internal/visibility"
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/rating"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/scoresheet"
+	"github.com/mkloubert/freeskat-server/pkg/stats"
+)
+
+const (
+	// keepAliveInterval is the interval of the comments keeping event streams open through proxies.
+	keepAliveInterval = 30 * time.Second
+	// maxPaymentSize is the maximum size of a payment confirmation
+	maxPaymentSize = 4096
+)
+
+// API serves the REST endpoints.
+type API struct {
+	archive     *archive.Archive
+	events      *live.Hub
+	daily       *daily.Schedule
+	duplicate   *duplicate.Set
+	tournaments *tournament.Store
+	leagues     *league.Store
+	seasons     *season.Store
+	profiles    *profile.Store
+	challenges  *challenge.Tracker
+	rating      rating.Algorithm
+	// paymentSecret verifies the payment confirmations ("" = not accepted)
+	paymentSecret string
+	// admin holds the state of the admin endpoints (nil = disabled)
+	admin *admin
+	mux   *http.ServeMux
+	// routes are the registered endpoints for the OpenAPI document
+	routes []route
+}
+
+// New creates the API for the game archive and the live table events.
+func New(games *archive.Archive, events *live.Hub) *API {
+	a := &API{archive: games, events: events, rating: rating.AlgorithmElo, mux: http.NewServeMux()}
+	filter := []param{
+		{"prefix", "Only games whose ID starts with the prefix"},
+		{"player", "Only games of the player"},
+	}
+	since := []param{{"since", "ID of the last received event (alternative to the Last-Event-ID header)"}}
+
+	a.handle(route{pattern: "GET /api/openapi.json", handler: a.handleOpenAPI, summary: "OpenAPI document of the API",
+		response: object{}})
+	a.handle(route{pattern: "GET /api/games", handler: a.handleGames, summary: "IDs of all public archived games",
+		response: object{"games": []string{}}})
+	a.handle(route{pattern: "GET /api/games/{id}", handler: a.handleGame, summary: "JSON replay of a public game",
+		response: replay.Replay{}})
+	a.handle(route{pattern: "GET /api/players/{name}/stats", handler: a.handlePlayerStats, summary: "Statistics of a player",
+		response: stats.Report{}})
+	a.handle(route{pattern: "GET /api/players/{name}/profile", handler: a.handlePlayerProfile, summary: "Public profile of a player",
+		response: profile.Profile{}})
+	a.handle(route{pattern: "GET /api/players/{name}/challenges", handler: a.handlePlayerChallenges, summary: "Progress of a player in the running challenges",
+		response: object{"challenges": []challenge.Progress{}}})
+	a.handle(route{pattern: "GET /api/challenges", handler: a.handleChallenges, summary: "Running daily and weekly challenges, daily first",
+		response: object{"challenges": []challenge.Challenge{}}})
+	a.handle(route{pattern: "GET /api/ratings", handler: a.handleRatings, summary: "Ratings of the current season, best first",
+		response: object{"algorithm": rating.Algorithm(""), "ratings": []rating.Rating{}, "season": 0}})
+	a.handle(route{pattern: "GET /api/seasons", handler: a.handleSeasons, summary: "All seasons without their ratings, newest first",
+		response: object{"seasons": []season.Season{}}})
+	a.handle(route{pattern: "GET /api/seasons/{number}", handler: a.handleSeason, summary: "A season with its ratings",
+		response: object{"season": season.Season{}}})
+	a.handle(route{pattern: "GET /api/export/sheet.csv", handler: a.handleSheetCSV, summary: "Score sheet of the public games",
+		query: filter, content: contentCSV})
+	a.handle(route{pattern: "GET /api/export/standings.csv", handler: a.handleStandingsCSV, summary: "Standings of the public games",
+		query: filter, content: contentCSV})
+	a.handle(route{pattern: "GET /api/export/stats.csv", handler: a.handleStatsCSV, summary: "Statistics of all players",
+		content: contentCSV})
+	a.handle(route{pattern: "GET /api/tables/{name}/events", handler: a.handleTableEvents, summary: "Public events of a table (Server-Sent Events)",
+		query: since, content: contentEvents})
+	a.handle(route{pattern: "GET /api/daily/{date}", handler: a.handleDaily, summary: "Leaderboard of the deal of a day (YYYY-MM-DD or today)",
+		response: object{"date": "", "entries": []daily.Entry{}}})
+	a.handle(route{pattern: "GET /api/tournaments", handler: a.handleTournaments, summary: "All tournaments without their standings",
+		response: object{"tournaments": []tournament.Tournament{}}})
+	a.handle(route{pattern: "GET /api/tournaments/{name}", handler: a.handleTournament, summary: "A tournament with its standings, schedules and results",
+		response: object{
+			"tournament": tournament.Tournament{}, "standings": []tournament.Standing{}, "teams": []tournament.TeamStanding{},
+			"schedules": []tournament.Schedule{}, "results": []tournament.TableResult{}, "profiles": []profile.Profile{},
+		}})
+	a.handle(route{pattern: "GET /api/tournaments/{name}/standings", handler: a.handleTournamentStandings, summary: "Current standings of a tournament",
+		response: object{"tournament": "", "series": 0, "standings": []tournament.Standing{}, "teams": []tournament.TeamStanding{}}})
+	a.handle(route{pattern: "GET /api/tournaments/{name}/events", handler: a.handleTournamentEvents, summary: "Standings of a tournament after every deal (Server-Sent Events)",
+		query: since, content: contentEvents})
+	a.handle(route{pattern: "GET /api/tournaments/{name}/bracket", handler: a.handleTournamentBracket, summary: "Stages of a multi-stage tournament",
+		response: object{"tournament": "", "stages": []tournament.BracketStage{}}})
+	a.handle(route{pattern: "POST /api/tournaments/{name}/payments", handler: a.handleTournamentPayment, summary: "Payment confirmation of the payment service (signed)",
+		body: webhook.Payment{}, status: http.StatusNoContent})
+	a.handle(route{pattern: "GET /api/export/tournaments/{name}/list.csv", handler: a.handleTournamentListCSV, summary: "Result list of a tournament (DSKV format)",
+		content: contentCSV})
+	a.handle(route{pattern: "GET /api/export/tournaments/{name}/series/{series}/tables/{table}/sheet.csv", handler: a.handleTournamentSheetCSV,
+		summary: "Score sheet of a tournament table, Bock deals doubled", content: contentCSV})
+	a.handle(route{pattern: "GET /api/leagues", handler: a.handleLeagues, summary: "All leagues without their season tables",
+		response: object{"leagues": []league.League{}}})
+	a.handle(route{pattern: "GET /api/leagues/{name}", handler: a.handleLeague, summary: "A league with its rounds and season table",
+		response: object{"league": league.League{}, "standings": []tournament.Standing{}, "teams": []league.TeamStanding{}}})
+	a.registerAdmin()
+	return a
+}
+
+// SetDaily sets the schedule of the deal of the day. Daily games of the current day
+// are hidden, since they reveal the deal.
+func (a *API) SetDaily(schedule *daily.Schedule) {
+	a.daily = schedule
+}
+
+// SetDuplicate sets the duplicate set. Its games are hidden until their deal is played
+// at every table.
+func (a *API) SetDuplicate(set *duplicate.Set) {
+	a.duplicate = set
+}
+
+// SetTournaments sets the tournament store.
+func (a *API) SetTournaments(store *tournament.Store) {
+	a.tournaments = store
+}
+
+// SetPaymentSecret enables the payment confirmations of the payment service, signed
+// with the secret.
+func (a *API) SetPaymentSecret(secret string) {
+	a.paymentSecret = secret
+}
+
+// SetLeagues sets the league store.
+func (a *API) SetLeagues(store *league.Store) {
+	a.leagues = store
+}
+
+// SetSeasons sets the season store of the ratings.
+func (a *API) SetSeasons(store *season.Store) {
+	a.seasons = store
+}
+
+// SetProfiles sets the store of the player profiles.
+func (a *API) SetProfiles(store *profile.Store) {
+	a.profiles = store
+}
+
+// SetChallenges sets the tracker of the daily and weekly challenges.
+func (a *API) SetChallenges(tracker *challenge.Tracker) {
+	a.challenges = tracker
+}
+
+// SetRating sets the rating algorithm (default Elo).
+func (a *API) SetRating(algorithm rating.Algorithm) {
+	a.rating = algorithm
+}
+
+// ServeHTTP implements http.Handler.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// handleGames lists the IDs of all public archived games.
+func (a *API) handleGames(w http.ResponseWriter, r *http.Request) {
+	ids, err := a.archive.IDs()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	public := []string{}
+	for _, id := range ids {
+		if !a.hidden(id) {
+			public = append(public, id)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"games": public})
+}
+
+// handleGame returns the JSON replay of a public game.
+func (a *API) handleGame(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if a.hidden(id) {
+		writeError(w, http.StatusNotFound, archive.ErrNotFound)
+		return
+	}
+
+	replay, err := a.archive.Replay(id)
+	switch {
+	case errors.Is(err, archive.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, replay)
+	}
+}
+
+// handlePlayerStats returns the statistics of a player in the games everyone may see.
+func (a *API) handlePlayerStats(w http.ResponseWriter, r *http.Request) {
+	collector, err := a.visibility().Stats(time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	name := r.PathValue("name")
+	s, ok := collector.Player(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("player not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Report(name))
+}
+
+// handlePlayerProfile returns the public profile of a player.
+func (a *API) handlePlayerProfile(w http.ResponseWriter, r *http.Request) {
+	if a.profiles == nil {
+		writeError(w, http.StatusNotFound, profile.ErrNotFound)
+		return
+	}
+	p, ok := a.profiles.Get(r.PathValue("name"))
+	if !ok {
+		writeError(w, http.StatusNotFound, profile.ErrNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// handleChallenges returns the running challenges.
+func (a *API) handleChallenges(w http.ResponseWriter, r *http.Request) {
+	if a.challenges == nil {
+		writeError(w, http.StatusNotFound, errors.New("challenges not available"))
+		return
+	}
+	challenges, err := a.challenges.Current(time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]challenge.Challenge{"challenges": challenges})
+}
+
+// handlePlayerChallenges returns the progress of a player in the running challenges.
+func (a *API) handlePlayerChallenges(w http.ResponseWriter, r *http.Request) {
+	if a.challenges == nil {
+		writeError(w, http.StatusNotFound, errors.New("challenges not available"))
+		return
+	}
+	progress, err := a.challenges.Progress(r.PathValue("name"), time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]challenge.Progress{"challenges": progress})
+}
+
+// handleRatings returns the ratings of all players of public games of the current
+// season, best first.
+func (a *API) handleRatings(w http.ResponseWriter, r *http.Request) {
+	ratings, err := a.currentRatings()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	result := map[string]any{"algorithm": a.rating, "ratings": ratings}
+	if a.seasons != nil {
+		result["season"] = a.seasons.Current().Number
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleSeasons lists all seasons without their ratings, newest first.
+func (a *API) handleSeasons(w http.ResponseWriter, r *http.Request) {
+	list := []*season.Season{}
+	if a.seasons != nil {
+		list = a.seasons.List()
+	}
+	for _, s := range list {
+		s.Ratings = nil
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"seasons": list})
+}
+
+// handleSeason returns a season with its final ratings, or the current ratings of
+// public games for the current season.
+func (a *API) handleSeason(w http.ResponseWriter, r *http.Request) {
+	if a.seasons == nil {
+		writeError(w, http.StatusNotFound, season.ErrNotFound)
+		return
+	}
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid season: %s", r.PathValue("number")))
+		return
+	}
+	s, err := a.seasons.Get(number)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	if !s.Closed() {
+		if s.Ratings, err = a.currentRatings(); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		s.Algorithm = a.rating
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"season": s})
+}
+
+// currentRatings computes the ratings of public games of the current season, or of
+// all public games without seasons.
+func (a *API) currentRatings() ([]rating.Rating, error) {
+	records, err := a.visibility().Records(archive.Filter{}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if a.seasons == nil {
+		return rating.Compute(a.rating, records)
+	}
+	return a.seasons.Ratings(a.rating, records)
+}
+
+// handleDaily returns the leaderboard of the deal of a day ("today" for the current day).
+func (a *API) handleDaily(w http.ResponseWriter, r *http.Request) {
+	date := r.PathValue("date")
+	if date == "today" && a.daily != nil {
+		date = a.daily.Date(time.Now())
+	}
+	if !daily.ValidDate(date) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid date: %s", date))
+		return
+	}
+
+	records, err := a.archive.Records(archive.Filter{Prefix: daily.GamePrefix(date)})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	entries, err := daily.Leaderboard(records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if entries == nil {
+		entries = []daily.Entry{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"date": date, "entries": entries})
+}
+
+// handleTournaments lists all tournaments without their standings.
+func (a *API) handleTournaments(w http.ResponseWriter, r *http.Request) {
+	list := []*tournament.Tournament{}
+	if a.tournaments != nil {
+		list = a.tournaments.List()
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tournaments": list})
+}
+
+// handleTournament returns a tournament with its tables, Seeger-Fabian standings, the
+// Bock and Ramsch schedules and results of the tables of the last started series and
+// the profiles of the registered players.
+func (a *API) handleTournament(w http.ResponseWriter, r *http.Request) {
+	t, standings, ok := a.tournamentStandings(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := a.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	schedules := []*tournament.Schedule{}
+	results := []tournament.TableResult{}
+	if len(t.Series) > 0 {
+		if schedules, err = t.Schedules(len(t.Series), records); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if results, err = t.Results(len(t.Series), records); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	profiles := []profile.Profile{}
+	if a.profiles != nil {
+		profiles = a.profiles.Profiles(append(append(append([]string{}, t.Players...), t.Pending...), t.Waitlist...))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tournament": t, "standings": standings, "teams": t.TeamStandings(standings),
+		"schedules": schedules, "results": results, "profiles": profiles,
+	})
+}
+
+// handleTournamentPayment confirms or declines the pending registration of a player
+// when the payment service reports the payment of the seat fee. The body is a
+// webhook.Payment, signed like the webhook requests.
+func (a *API) handleTournamentPayment(w http.ResponseWriter, r *http.Request) {
+	if a.tournaments == nil || a.paymentSecret == "" {
+		writeError(w, http.StatusNotFound, errors.New("payments not enabled"))
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPaymentSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !webhook.Verify(a.paymentSecret, body, r.Header.Get(webhook.SignatureHeader)) {
+		writeError(w, http.StatusUnauthorized, errors.New("invalid signature"))
+		return
+	}
+	var payment webhook.Payment
+	if err := json.Unmarshal(body, &payment); err != nil || payment.Player == "" {
+		writeError(w, http.StatusBadRequest, errors.New("invalid payment"))
+		return
+	}
+
+	name := r.PathValue("name")
+	if err := a.tournaments.ConfirmPayment(name, payment.Player, payment.Confirmed); err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, tournament.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err)
+		return
+	}
+	log.Printf("[api] Payment of %s for tournament %s confirmed: %t", payment.Player, name, payment.Confirmed)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleLeagues lists all leagues without their season tables.
+func (a *API) handleLeagues(w http.ResponseWriter, r *http.Request) {
+	list := []*league.League{}
+	if a.leagues != nil {
+		list = a.leagues.List()
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"leagues": list})
+}
+
+// handleLeague returns a league with its rounds and season table.
+func (a *API) handleLeague(w http.ResponseWriter, r *http.Request) {
+	if a.leagues == nil {
+		writeError(w, http.StatusNotFound, league.ErrNotFound)
+		return
+	}
+	l, err := a.leagues.Get(r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	records, err := a.archive.Records(archive.Filter{Prefix: l.GamePrefix()})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	standings, teams, err := l.SeasonTable(records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"league": l, "standings": standings, "teams": teams})
+}
+
+// handleSheetCSV exports the score sheet of the public games selected by the
+// "prefix" and "player" query parameters.
+func (a *API) handleSheetCSV(w http.ResponseWriter, r *http.Request) {
+	sheet, err := a.sheet(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	setCSVHeaders(w, "sheet.csv")
+	if err := sheet.WriteCSV(w); err != nil {
+		log.Printf("[api] Failed to write score sheet: %v", err)
+	}
+}
+
+// handleStandingsCSV exports the standings of the public games selected by the
+// "prefix" and "player" query parameters.
+func (a *API) handleStandingsCSV(w http.ResponseWriter, r *http.Request) {
+	sheet, err := a.sheet(r)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	setCSVHeaders(w, "standings.csv")
+	if err := scoresheet.WriteStandingsCSV(w, sheet.Standings()); err != nil {
+		log.Printf("[api] Failed to write standings: %v", err)
+	}
+}
+
+// handleTournamentListCSV exports the result list of a tournament in the format of the
+// DSKV tooling.
+func (a *API) handleTournamentListCSV(w http.ResponseWriter, r *http.Request) {
+	if a.tournaments == nil {
+		writeError(w, http.StatusNotFound, tournament.ErrNotFound)
+		return
+	}
+	t, err := a.tournaments.Get(r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	records, err := a.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	rows, err := t.ResultList(records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	setCSVHeaders(w, t.Name+"-liste.csv")
+	if err := scoresheet.WriteList(w, rows); err != nil {
+		log.Printf("[api] Failed to write result list: %v", err)
+	}
+}
+
+// handleTournamentSheetCSV exports the score sheet of a table of a tournament series.
+func (a *API) handleTournamentSheetCSV(w http.ResponseWriter, r *http.Request) {
+	if a.tournaments == nil {
+		writeError(w, http.StatusNotFound, tournament.ErrNotFound)
+		return
+	}
+	t, err := a.tournaments.Get(r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	series, err1 := strconv.Atoi(r.PathValue("series"))
+	table, err2 := strconv.Atoi(r.PathValue("table"))
+	if err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid table: %s/%s", r.PathValue("series"), r.PathValue("table")))
+		return
+	}
+	if _, ok := t.Table(series, table); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("table %d of series %d not found", table, series))
+		return
+	}
+	records, err := a.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sheet, err := t.Sheet(series, table, records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	setCSVHeaders(w, fmt.Sprintf("%s-%d-%d-sheet.csv", t.Name, series, table))
+	if err := sheet.WriteCSV(w); err != nil {
+		log.Printf("[api] Failed to write score sheet: %v", err)
+	}
+}
+
+// handleStatsCSV exports the statistics of all players in the games everyone may see.
+func (a *API) handleStatsCSV(w http.ResponseWriter, r *http.Request) {
+	collector, err := a.visibility().Stats(time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	setCSVHeaders(w, "stats.csv")
+	if err := stats.WriteCSV(w, collector.Reports()); err != nil {
+		log.Printf("[api] Failed to write statistics: %v", err)
+	}
+}
+
+// handleTableEvents streams the public events of a table as Server-Sent Events.
+// Reconnecting clients continue after the Last-Event-ID header (or the "since" parameter).
+func (a *API) handleTableEvents(w http.ResponseWriter, r *http.Request) {
+	events, cancel := a.events.Watch(r.PathValue("name"), lastEventID(r))
+	defer cancel()
+	streamEvents(w, r, events, nil, func(event live.Event) bool {
+		return !a.hidden(event.Game)
+	})
+}
+
+// handleTournamentStandings returns the current standings of a tournament, updated
+// after every finished deal.
+func (a *API) handleTournamentStandings(w http.ResponseWriter, r *http.Request) {
+	t, standings, ok := a.tournamentStandings(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"tournament": t.Name, "series": len(t.Series), "standings": standings, "teams": t.TeamStandings(standings),
+	})
+}
+
+// handleTournamentEvents streams the standings of a tournament after every finished
+// deal as Server-Sent Events. New watchers first receive the current standings (without
+// ID); reconnecting clients continue after the Last-Event-ID header (or the "since"
+// parameter).
+func (a *API) handleTournamentEvents(w http.ResponseWriter, r *http.Request) {
+	t, standings, ok := a.tournamentStandings(w, r)
+	if !ok {
+		return
+	}
+
+	since := lastEventID(r)
+	var current *live.Event
+	if since == 0 {
+		since = a.events.LastID()
+		event := live.StandingsEvent(t.Name, "", standings, t.TeamStandings(standings))
+		current = &event
+	}
+	events, cancel := a.events.Watch(live.TournamentFeed(t.Name), since)
+	defer cancel()
+	streamEvents(w, r, events, current, nil)
+}
+
+// handleTournamentBracket returns the stages of a multi-stage tournament with the
+// standings and advancing players of their groups.
+func (a *API) handleTournamentBracket(w http.ResponseWriter, r *http.Request) {
+	if a.tournaments == nil {
+		writeError(w, http.StatusNotFound, tournament.ErrNotFound)
+		return
+	}
+	t, err := a.tournaments.Get(r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	records, err := a.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	bracket, err := t.Bracket(records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"tournament": t.Name, "stages": bracket})
+}
+
+// tournamentStandings computes the standings of the tournament of the request. It writes
+// the error response and returns false on failure.
+func (a *API) tournamentStandings(w http.ResponseWriter, r *http.Request) (*tournament.Tournament, []tournament.Standing, bool) {
+	if a.tournaments == nil {
+		writeError(w, http.StatusNotFound, tournament.ErrNotFound)
+		return nil, nil, false
+	}
+	t, err := a.tournaments.Get(r.PathValue("name"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return nil, nil, false
+	}
+	records, err := a.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, nil, false
+	}
+	standings, err := t.Standings(records)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, nil, false
+	}
+	return t, standings, true
+}
+
+// lastEventID returns the ID of the last event a reconnecting client received, from the
+// Last-Event-ID header or the "since" parameter (0 = none).
+func lastEventID(r *http.Request) int64 {
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("since")
+	}
+	since, _ := strconv.ParseInt(lastID, 10, 64)
+	return since
+}
+
+// streamEvents writes the events as Server-Sent Events until the client disconnects or
+// the channel is closed, starting with the first event without ID (nil = none). Events
+// for which visible returns false are skipped (nil = none).
+func streamEvents(w http.ResponseWriter, r *http.Request, events <-chan live.Event, first *live.Event,
+	visible func(live.Event) bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if first != nil {
+		writeEvent(w, *first, false)
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if visible != nil && !visible(event) {
+				continue
+			}
+			writeEvent(w, event, true)
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes an event, with its ID if withID is set.
+func writeEvent(w http.ResponseWriter, event live.Event, withID bool) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[api] Failed to encode event: %v", err)
+		return
+	}
+	if withID {
+		fmt.Fprintf(w, "id: %d\n", event.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
+
+// sheet creates the score sheet of the public games selected by the query parameters.
+func (a *API) sheet(r *http.Request) (*scoresheet.Sheet, error) {
+	query := r.URL.Query()
+	records, err := a.visibility().Records(archive.Filter{Prefix: query.Get("prefix"), Player: query.Get("player")}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return scoresheet.New(records)
+}
+
+// hidden returns true if the game must not be served (see visibility.Rules.Hidden).
+func (a *API) hidden(id string) bool {
+	return a.visibility().Hidden(id, "", time.Now())
+}
+
+// visibility returns the rules deciding which games are served.
+func (a *API) visibility() *visibility.Rules {
+	return visibility.New(a.archive, a.daily, a.duplicate)
+}
+
+// setCSVHeaders sets the headers of a CSV download.
+func setCSVHeaders(w http.ResponseWriter, filename string) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[api] Failed to write response: %v", err)
+	}
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
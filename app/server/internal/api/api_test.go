@@ -0,0 +1,248 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// newTestAPI returns a test server of the API with an empty archive.
+func newTestAPI(t *testing.T, events *live.Hub) (*API, *httptest.Server) {
+	t.Helper()
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := New(games, events)
+	server := httptest.NewServer(a)
+	t.Cleanup(server.Close)
+	return a, server
+}
+
+// readEvents reads n Server-Sent Events ("id", "event" and "data" lines) of a stream.
+func readEvents(t *testing.T, lines *bufio.Scanner, n int) []map[string]string {
+	t.Helper()
+	var events []map[string]string
+	event := map[string]string{}
+	for len(events) < n && lines.Scan() {
+		line := lines.Text()
+		if line == "" {
+			events = append(events, event)
+			event = map[string]string{}
+			continue
+		}
+		if field, value, ok := strings.Cut(line, ": "); ok {
+			event[field] = value
+		}
+	}
+	if len(events) < n {
+		t.Fatalf("read %d events, want %d: %v", len(events), n, lines.Err())
+	}
+	return events
+}
+
+func TestTableEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		since  string
+		header string
+		want   []string // IDs of the events before the published ones
+	}{
+		{"all recent events", "", "", []string{"1"}},
+		{"since", "?since=1", "", nil},
+		{"last event ID", "", "1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := live.NewHub()
+			_, server := newTestAPI(t, hub)
+			hub.Publish(live.Event{Type: live.EventStart, Table: "anna", Game: "g1", Players: []string{"anna", "bot1", "bot2"}})
+			hub.Publish(live.Event{Type: live.EventStart, Table: "ben", Game: "g2"})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/tables/anna/events"+tt.since, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+				t.Errorf("Content-Type = %q, want text/event-stream", got)
+			}
+
+			// Published while watching; the other table is not streamed
+			hub.Publish(live.Event{Type: live.EventMove, Table: "ben", Game: "g2"})
+			hub.Publish(live.Event{Type: live.EventResult, Table: "anna", Game: "g1"})
+			last := hub.LastID()
+
+			lines := bufio.NewScanner(resp.Body)
+			events := readEvents(t, lines, len(tt.want)+1)
+			for i, id := range tt.want {
+				if events[i]["id"] != id || events[i]["event"] != live.EventStart {
+					t.Errorf("event %d = %v, want start %s", i, events[i], id)
+				}
+			}
+			result := events[len(tt.want)]
+			var data live.Event
+			if err := json.Unmarshal([]byte(result["data"]), &data); err != nil {
+				t.Fatal(err)
+			}
+			if result["event"] != live.EventResult || result["id"] != strconv.FormatInt(last, 10) ||
+				data.Table != "anna" || data.Game != "g1" {
+				t.Errorf("event = %v, want the result of anna with ID %d", result, last)
+			}
+		})
+	}
+}
+
+// newSecretAPI returns a test server of the API whose archive holds the public game g1,
+// a daily game of tina of today and the deal of a duplicate set played by dirk, dana and
+// dean but not yet at the table of dora, emil and fritz.
+func newSecretAPI(t *testing.T, hub *live.Hub) (*API, *httptest.Server, *duplicate.Set) {
+	t.Helper()
+	a, server := newTestAPI(t, hub)
+	schedule := daily.NewSchedule("secret", time.UTC)
+	a.SetDaily(schedule)
+	set, err := duplicate.NewSet(&duplicate.Config{
+		Name:   "cup",
+		Seed:   7,
+		Deals:  1,
+		Tables: [][3]string{{"dirk", "dana", "dean"}, {"dora", "emil", "fritz"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.SetDuplicate(set)
+
+	today := recordtest.PlayedBy(t, 2, map[skat.Player]string{skat.Forehand: "tina", skat.Middlehand: "bot1", skat.Rearhand: "bot2"})
+	today.ID = daily.GamePrefix(schedule.Date(time.Now())) + "1"
+	deal := recordtest.Finish(t, set.Record(0, 0, time.Now()))
+	for _, record := range []*skat.GameRecord{recordtest.Played(t, 1), today, deal} {
+		if err := a.archive.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := set.Add(deal); err != nil {
+		t.Fatal(err)
+	}
+	return a, server, set
+}
+
+// get returns the status and body of a GET request.
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestSecretGames(t *testing.T) {
+	_, server, set := newSecretAPI(t, live.NewHub())
+	deal := set.GameID(0, 0)
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		public string // shown content of the public game
+	}{
+		{"games", "/api/games", http.StatusOK, "g1"},
+		{"replay", "/api/games/" + deal, http.StatusNotFound, ""},
+		{"player statistics", "/api/players/dana/stats", http.StatusNotFound, ""},
+		{"ratings", "/api/ratings", http.StatusOK, "anna"},
+		{"score sheet", "/api/export/sheet.csv", http.StatusOK, "anna"},
+		{"standings", "/api/export/standings.csv", http.StatusOK, "anna"},
+		{"statistics", "/api/export/stats.csv", http.StatusOK, "anna"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, server.URL+tt.path)
+			if status != tt.status || !strings.Contains(body, tt.public) {
+				t.Errorf("GET %s = %d %s, want %d with %q", tt.path, status, body, tt.status, tt.public)
+			}
+			for _, secret := range []string{deal, "dana", "daily-", "tina"} {
+				if strings.Contains(body, secret) {
+					t.Errorf("GET %s reveals %s: %s", tt.path, secret, body)
+				}
+			}
+		})
+	}
+
+	// Played at every table, the deal is public
+	if err := set.Add(recordtest.Finish(t, set.Record(1, 0, time.Now()))); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := get(t, server.URL+"/api/games/"+deal); status != http.StatusOK {
+		t.Errorf("GET the revealed deal: status %d, want %d", status, http.StatusOK)
+	}
+	if _, body := get(t, server.URL+"/api/export/stats.csv"); !strings.Contains(body, "dana") {
+		t.Errorf("statistics without the revealed deal: %s", body)
+	}
+}
+
+func TestSecretTableEvents(t *testing.T) {
+	hub := live.NewHub()
+	_, server, set := newSecretAPI(t, hub)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/tables/dana/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// Only the event of the public game is streamed
+	hub.Publish(live.Event{Type: live.EventStart, Table: "dana", Game: set.GameID(0, 0)})
+	hub.Publish(live.Event{Type: live.EventResult, Table: "dana", Game: set.GameID(0, 0)})
+	hub.Publish(live.Event{Type: live.EventStart, Table: "dana", Game: "g1"})
+	events := readEvents(t, bufio.NewScanner(resp.Body), 1)
+	if !strings.Contains(events[0]["data"], `"g1"`) {
+		t.Errorf("event = %v, want the start of g1", events[0])
+	}
+}
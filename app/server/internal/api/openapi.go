@@ -0,0 +1,285 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Content types of the responses.
+const (
+	contentJSON   = "application/json"
+	contentCSV    = "text/csv"
+	contentEvents = "text/event-stream"
+)
+
+// route is an endpoint of the API. The routes register the handlers and describe the
+// endpoints in the OpenAPI document.
+type route struct {
+	// pattern is the ServeMux pattern, e.g. "GET /api/games/{id}"
+	pattern string
+	handler http.HandlerFunc
+	summary string
+	// query are the query parameters
+	query []param
+	// body is a value of the type of the JSON request body (nil = none)
+	body any
+	// status is the status of a successful response (0 = 200 OK)
+	status int
+	// content is the content type of the response ("" = JSON, none for 204)
+	content string
+	// response is a value of the type of the JSON response
+	response any
+	// admin endpoints require the admin token
+	admin bool
+}
+
+// param is a query parameter.
+type param struct {
+	name        string
+	description string
+}
+
+// object is a JSON object response with its fields, for the responses written as maps.
+type object map[string]any
+
+// handle registers a route.
+func (a *API) handle(r route) {
+	handler := r.handler
+	if r.admin {
+		handler = a.authorized(handler)
+	}
+	a.mux.HandleFunc(r.pattern, handler)
+	a.routes = append(a.routes, r)
+}
+
+// handleOpenAPI returns the OpenAPI document of the API.
+func (a *API) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, a.OpenAPI())
+}
+
+// OpenAPI returns the OpenAPI 3.1 document of the registered endpoints, with the
+// schemas of the request and response bodies derived from their Go types.
+func (a *API) OpenAPI() map[string]any {
+	schemas := newSchemas()
+	paths := make(map[string]map[string]any)
+	for _, r := range a.routes {
+		method, path, _ := strings.Cut(r.pattern, " ")
+		if paths[path] == nil {
+			paths[path] = make(map[string]any)
+		}
+		paths[path][strings.ToLower(method)] = a.operation(r, path, schemas)
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "FreeSkat REST API",
+			"version":     "1",
+			"description": "Game archive, statistics, ratings, tournaments, leagues, live events and server administration.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]any{
+				"adminToken": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// operation describes a route.
+func (a *API) operation(r route, path string, schemas *schemas) map[string]any {
+	var parameters []map[string]any
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, "{"); ok {
+			parameters = append(parameters, map[string]any{
+				"name": strings.TrimSuffix(name, "}"), "in": "path", "required": true,
+				"schema": map[string]any{"type": "string"},
+			})
+		}
+	}
+	for _, p := range r.query {
+		parameters = append(parameters, map[string]any{
+			"name": p.name, "in": "query", "description": p.description,
+			"schema": map[string]any{"type": "string"},
+		})
+	}
+
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := map[string]any{"description": http.StatusText(status)}
+	switch {
+	case r.content == contentCSV:
+		response["content"] = map[string]any{contentCSV: map[string]any{"schema": map[string]any{"type": "string"}}}
+	case r.content == contentEvents:
+		response["content"] = map[string]any{contentEvents: map[string]any{"schema": map[string]any{"type": "string"}}}
+	case r.response != nil:
+		response["content"] = jsonContent(schemas.value(r.response))
+	}
+	errorResponse := map[string]any{"description": "Error", "content": jsonContent(schemas.value(object{"error": ""}))}
+
+	op := map[string]any{
+		"summary":     r.summary,
+		"operationId": operationID(r.handler),
+		"tags":        []string{tag(path)},
+		"responses":   map[string]any{strconv.Itoa(status): response, "default": errorResponse},
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+	if r.body != nil {
+		op["requestBody"] = map[string]any{"content": jsonContent(schemas.value(r.body))}
+	}
+	if r.admin {
+		op["security"] = []map[string][]string{{"adminToken": {}}}
+	}
+	return op
+}
+
+// jsonContent returns the JSON content of a schema.
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{contentJSON: map[string]any{"schema": schema}}
+}
+
+// tag returns the tag of a path: its first segment after /api.
+func tag(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/api/"), "/")
+	return segments[0]
+}
+
+// operationID returns the name of the handler method without its "handle" prefix,
+// e.g. "games" for handleGames.
+func operationID(handler http.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	name = name[strings.LastIndex(name, ".")+1:]
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "handle"), "-fm")
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// schemas builds the JSON schemas of Go types. Named structs are components,
+// referenced by "<package>.<type>".
+type schemas struct {
+	components map[string]any
+}
+
+// newSchemas creates an empty schema set.
+func newSchemas() *schemas {
+	return &schemas{components: make(map[string]any)}
+}
+
+// timeType is the type of timestamps (RFC 3339 strings).
+var timeType = reflect.TypeOf(time.Time{})
+
+// value returns the schema of the type of a value, or of the fields of an object.
+func (s *schemas) value(v any) map[string]any {
+	o, ok := v.(object)
+	if !ok {
+		return s.of(reflect.TypeOf(v))
+	}
+	properties := make(map[string]any)
+	for name, field := range o {
+		properties[name] = s.value(field)
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
+
+// of returns the schema of a type.
+func (s *schemas) of(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": "array", "items": s.of(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		name := t.String()
+		if _, ok := s.components[name]; !ok {
+			// Placeholder for recursive types
+			s.components[name] = map[string]any{}
+			s.components[name] = s.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the schema of the JSON encoding of a struct.
+func (s *schemas) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tagValue := field.Tag.Get("json")
+		if tagValue == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tagValue, ",")
+		// Like encoding/json, the fields of embedded structs are promoted even if the
+		// struct type is unexported
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, value := range s.structSchema(embedded)["properties"].(map[string]any) {
+					properties[key] = value
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.of(field.Type)
+	}
+	return map[string]any{"type": "object", "properties": properties}
+}
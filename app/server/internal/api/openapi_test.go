@@ -0,0 +1,157 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// refs collects the schema references of a decoded JSON document.
+func refs(v any, found map[string]bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				found[ref] = true
+			}
+			refs(value, found)
+		}
+	case []any:
+		for _, value := range v {
+			refs(value, found)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	_, server := newTestAPI(t, nil)
+	status, body := get(t, server.URL+"/api/openapi.json")
+	if status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	var doc struct {
+		OpenAPI    string                               `json:"openapi"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %s", doc.OpenAPI)
+	}
+
+	game := doc.Paths["/api/games/{id}"]["get"]
+	if game == nil || game["operationId"] != "game" || !reflect.DeepEqual(game["tags"], []any{"games"}) {
+		t.Fatalf("GET /api/games/{id} = %v", game)
+	}
+	params, _ := game["parameters"].([]any)
+	if len(params) != 1 || params[0].(map[string]any)["name"] != "id" || params[0].(map[string]any)["in"] != "path" {
+		t.Errorf("parameters = %v", params)
+	}
+	if _, ok := game["security"]; ok {
+		t.Error("a public endpoint requires the admin token")
+	}
+	ok := game["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)[contentJSON].(map[string]any)["schema"]
+	if !reflect.DeepEqual(ok, map[string]any{"$ref": "#/components/schemas/replay.Replay"}) {
+		t.Errorf("200 schema = %v", ok)
+	}
+
+	// Admin endpoints are in the document even without admin token, with their security
+	ban := doc.Paths["/api/admin/bans/{login}"]["put"]
+	if ban == nil || ban["security"] == nil || ban["requestBody"] == nil {
+		t.Errorf("PUT /api/admin/bans/{login} = %v", ban)
+	}
+	if backup := doc.Paths["/api/admin/backup"]["post"]; backup == nil || backup["responses"].(map[string]any)["201"] == nil {
+		t.Errorf("POST /api/admin/backup = %v", backup)
+	}
+	csv := doc.Paths["/api/export/stats.csv"]["get"]["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)
+	if _, ok := csv[contentCSV]; !ok {
+		t.Errorf("CSV export content = %v", csv)
+	}
+
+	// Every reference resolves to a component
+	found := make(map[string]bool)
+	refs(doc.Paths, found)
+	refs(doc.Components.Schemas, found)
+	for ref := range found {
+		name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+		if _, defined := doc.Components.Schemas[name]; !ok || !defined {
+			t.Errorf("unresolved reference %s", ref)
+		}
+	}
+}
+
+// schemaNode is a recursive test type.
+type schemaNode struct {
+	Name     string        `json:"name"`
+	Children []*schemaNode `json:"children,omitempty"`
+}
+
+// schemaBase is embedded in schemaTest.
+type schemaBase struct {
+	ID int `json:"id"`
+}
+
+// schemaTest covers the field kinds of the schemas.
+type schemaTest struct {
+	schemaBase
+	When    time.Time         `json:"when"`
+	Score   *float64          `json:"score,omitempty"`
+	Counts  map[string]int    `json:"counts"`
+	Node    schemaNode        `json:"node"`
+	Inline  struct{ Ok bool } `json:"inline"`
+	Skipped string            `json:"-"`
+	Plain   []byte
+	hidden  int
+}
+
+func TestSchemas(t *testing.T) {
+	s := newSchemas()
+	got := s.value(schemaTest{})
+	if !reflect.DeepEqual(got, map[string]any{"$ref": "#/components/schemas/api.schemaTest"}) {
+		t.Fatalf("value() = %v", got)
+	}
+	want := map[string]any{"type": "object", "properties": map[string]any{
+		"id":     map[string]any{"type": "integer"},
+		"when":   map[string]any{"type": "string", "format": "date-time"},
+		"score":  map[string]any{"type": "number"},
+		"counts": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "integer"}},
+		"node":   map[string]any{"$ref": "#/components/schemas/api.schemaNode"},
+		"inline": map[string]any{"type": "object", "properties": map[string]any{"Ok": map[string]any{"type": "boolean"}}},
+		"Plain":  map[string]any{"type": "string", "contentEncoding": "base64"},
+	}}
+	if !reflect.DeepEqual(s.components["api.schemaTest"], want) {
+		t.Errorf("schema = %v\nwant %v", s.components["api.schemaTest"], want)
+	}
+	node := s.components["api.schemaNode"].(map[string]any)["properties"].(map[string]any)["children"]
+	if !reflect.DeepEqual(node, map[string]any{"type": "array", "items": map[string]any{"$ref": "#/components/schemas/api.schemaNode"}}) {
+		t.Errorf("recursive schema = %v", node)
+	}
+
+	fields := s.value(object{"games": []string{}})
+	if !reflect.DeepEqual(fields, map[string]any{"type": "object", "properties": map[string]any{
+		"games": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+	}}) {
+		t.Errorf("object schema = %v", fields)
+	}
+}
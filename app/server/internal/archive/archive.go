@@ -0,0 +1,446 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package archive stores finished games as JSON replay files.
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+	"github.com/mkloubert/freeskat-server/pkg/solver"
+	"github.com/mkloubert/freeskat-server/pkg/stats"
+)
+
+// ErrNotFound is returned if a game is not in the archive.
+var ErrNotFound = errors.New("game not found")
+
+// fileExt is the extension of the replay files.
+const fileExt = ".json"
+
+// privateExt is the extension of the marker files of private games.
+const privateExt = ".private"
+
+// adjournedExt is the extension of the marker files of adjourned games.
+const adjournedExt = ".adjourned"
+
+// Archive stores one replay file per game in a directory.
+type Archive struct {
+	dir string
+	mu  sync.RWMutex
+
+	// stats are the cached player statistics (built on first use)
+	stats   *stats.Collector
+	statsMu sync.Mutex
+
+	// onSave is called with every saved game (nil = none)
+	onSave func(r *replay.Replay)
+}
+
+// Open opens the archive in dir, creating the directory if needed.
+func Open(dir string) (*Archive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Archive{dir: dir}, nil
+}
+
+// Save stores a game record.
+func (a *Archive) Save(record *skat.GameRecord) error {
+	if !validID(record.ID) {
+		return fmt.Errorf("invalid game ID: %q", record.ID)
+	}
+	r, err := replay.New(record)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	if err := a.write(r); err != nil {
+		a.mu.Unlock()
+		return err
+	}
+	a.updateStats(record)
+	a.mu.Unlock()
+
+	if a.onSave != nil {
+		a.onSave(r)
+	}
+	return nil
+}
+
+// OnSave sets a function called with every saved game, e.g. to notify webhooks.
+// It must be set before the archive is used.
+func (a *Archive) OnSave(fn func(r *replay.Replay)) {
+	a.onSave = fn
+}
+
+// write writes a replay file. The caller must hold the write lock.
+func (a *Archive) write(r *replay.Replay) error {
+	// Write to a temporary file first so readers never see partial replays
+	tmp := a.path(r.ID) + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, a.path(r.ID))
+}
+
+// AddComment adds a comment to an archived game.
+func (a *Archive) AddComment(id string, comment skat.Comment) error {
+	return a.update(id, func(r *replay.Replay) error {
+		if comment.Move < 0 || comment.Move > len(r.Moves) {
+			return fmt.Errorf("invalid move: %d", comment.Move)
+		}
+		if comment.Time.IsZero() {
+			comment.Time = time.Now()
+		}
+		r.Comments = append(r.Comments, replay.Comment{Move: comment.Move, Author: comment.Author, Text: comment.Text, Time: comment.Time})
+		return nil
+	})
+}
+
+// SetMistakes stores the result of the mistake analysis of an archived game.
+func (a *Archive) SetMistakes(id string, mistakes []solver.Mistake) error {
+	return a.update(id, func(r *replay.Replay) error {
+		r.Mistakes = nil
+		for _, m := range mistakes {
+			if m.Move < 1 || m.Move > len(r.Moves) {
+				return fmt.Errorf("invalid move: %d", m.Move)
+			}
+			r.Mistakes = append(r.Mistakes, replay.Mistake{
+				Move:     m.Move,
+				Player:   m.Player.Index(),
+				Card:     m.Card.Code(),
+				Best:     m.Best.Code(),
+				Loss:     m.Loss,
+				GameLost: m.GameLost,
+			})
+		}
+		return nil
+	})
+}
+
+// update loads the replay of a game, changes it with fn and writes it back.
+func (a *Archive) update(id string, fn func(r *replay.Replay) error) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	f, err := os.Open(a.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	r, err := replay.Read(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("game %s: %w", id, err)
+	}
+
+	if err := fn(r); err != nil {
+		return err
+	}
+	return a.write(r)
+}
+
+// Replay loads the replay of a game.
+func (a *Archive) Replay(id string) (*replay.Replay, error) {
+	if !validID(id) {
+		return nil, ErrNotFound
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	f, err := os.Open(a.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := replay.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("game %s: %w", id, err)
+	}
+	return r, nil
+}
+
+// Load loads the record of a game.
+func (a *Archive) Load(id string) (*skat.GameRecord, error) {
+	r, err := a.Replay(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Record()
+}
+
+// IDs returns the IDs of all archived games in sorted order.
+func (a *Archive) IDs() ([]string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasSuffix(name, fileExt) {
+			ids = append(ids, strings.TrimSuffix(name, fileExt))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Games returns the replays of the games of a player, most recent first (at most limit, 0 = all).
+func (a *Archive) Games(player string, limit int) ([]*replay.Replay, error) {
+	ids, err := a.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	games := []*replay.Replay{}
+	for _, id := range ids {
+		r, err := a.Replay(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range r.Players {
+			if p.Name == player {
+				games = append(games, r)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(games, func(i, j int) bool {
+		return games[i].StartedAt.After(games[j].StartedAt)
+	})
+	if limit > 0 && len(games) > limit {
+		games = games[:limit]
+	}
+	return games, nil
+}
+
+// Stats returns the statistics of all archived games. They are computed from all games
+// on the first call and updated with every saved game afterwards.
+func (a *Archive) Stats() (*stats.Collector, error) {
+	a.statsMu.Lock()
+	defer a.statsMu.Unlock()
+
+	if a.stats != nil {
+		return a.stats, nil
+	}
+
+	ids, err := a.IDs()
+	if err != nil {
+		return nil, err
+	}
+	collector := stats.NewCollector()
+	for _, id := range ids {
+		// Adjourned games are added when they are saved again after resuming
+		if a.IsAdjourned(id) {
+			continue
+		}
+		record, err := a.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		if err := collector.Add(record); err != nil {
+			return nil, fmt.Errorf("game %s: %w", id, err)
+		}
+	}
+	a.stats = collector
+	return collector, nil
+}
+
+// updateStats adds a saved game to the cached statistics.
+func (a *Archive) updateStats(record *skat.GameRecord) {
+	a.statsMu.Lock()
+	defer a.statsMu.Unlock()
+
+	if a.stats != nil {
+		// The record was replayed when saving, so it cannot fail here
+		a.stats.Add(record)
+	}
+}
+
+// Filter selects archived games.
+type Filter struct {
+	// Prefix selects games whose ID starts with it (e.g. the games of one table series)
+	Prefix string
+	// Player selects the games of a player
+	Player string
+	// Public excludes private games
+	Public bool
+}
+
+// Records returns the records of all games matching the filter, oldest first.
+func (a *Archive) Records(filter Filter) ([]*skat.GameRecord, error) {
+	ids, err := a.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	records := []*skat.GameRecord{}
+	for _, id := range ids {
+		if !strings.HasPrefix(id, filter.Prefix) || filter.Public && a.IsPrivate(id) {
+			continue
+		}
+		record, err := a.Load(id)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Player == "" || hasPlayer(record, filter.Player) {
+			records = append(records, record)
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].StartedAt.Before(records[j].StartedAt)
+	})
+	return records, nil
+}
+
+// hasPlayer returns true if the player played in the game.
+func hasPlayer(record *skat.GameRecord, player string) bool {
+	for _, name := range record.Players {
+		if name == player {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrivate returns true if the game is marked as private.
+func (a *Archive) IsPrivate(id string) bool {
+	return a.hasMarker(id, privateExt)
+}
+
+// SetPrivate marks a game as private or public. Private games are only visible to their players.
+func (a *Archive) SetPrivate(id string, private bool) error {
+	return a.setMarker(id, privateExt, private)
+}
+
+// IsAdjourned returns true if the game is marked as adjourned.
+func (a *Archive) IsAdjourned(id string) bool {
+	return a.hasMarker(id, adjournedExt)
+}
+
+// SetAdjourned marks an unfinished game as adjourned, so it can be resumed after a
+// server restart, or clears the mark.
+func (a *Archive) SetAdjourned(id string, adjourned bool) error {
+	return a.setMarker(id, adjournedExt, adjourned)
+}
+
+// Adjourned returns the IDs of all adjourned games in sorted order.
+func (a *Archive) Adjourned() ([]string, error) {
+	ids, err := a.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	adjourned := []string{}
+	for _, id := range ids {
+		if a.IsAdjourned(id) {
+			adjourned = append(adjourned, id)
+		}
+	}
+	return adjourned, nil
+}
+
+// hasMarker returns true if the marker file with the extension exists for the game.
+func (a *Archive) hasMarker(id, ext string) bool {
+	if !validID(id) {
+		return false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	_, err := os.Stat(a.markerPath(id, ext))
+	return err == nil
+}
+
+// setMarker creates or removes the marker file with the extension for an archived game.
+func (a *Archive) setMarker(id, ext string, set bool) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, err := os.Stat(a.path(id)); errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	if !set {
+		if err := os.Remove(a.markerPath(id, ext)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(a.markerPath(id, ext), nil, 0o644)
+}
+
+// path returns the file path of a game.
+func (a *Archive) path(id string) string {
+	return filepath.Join(a.dir, id+fileExt)
+}
+
+// markerPath returns the path of a marker file of a game.
+func (a *Archive) markerPath(id, ext string) string {
+	return filepath.Join(a.dir, id+ext)
+}
+
+// validID returns true if the ID can be used as file name (letters, digits, "-" and "_").
+func validID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
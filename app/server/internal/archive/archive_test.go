@@ -0,0 +1,178 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// openTestArchive opens an archive with the games g1 to g<n> of anna, ben and carl.
+func openTestArchive(t *testing.T, n int64) *Archive {
+	t.Helper()
+	a, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(1); seed <= n; seed++ {
+		if err := a.Save(recordtest.Played(t, seed)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return a
+}
+
+func TestSaveLoad(t *testing.T) {
+	a := openTestArchive(t, 1)
+	want := recordtest.Played(t, 1)
+	record, err := a.Load("g1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.ID != "g1" || len(record.Actions) != len(want.Actions) || record.Players[skat.Forehand] != "anna" {
+		t.Errorf("Load() = %s with %d actions by %v", record.ID, len(record.Actions), record.Players)
+	}
+
+	if _, err := a.Load("g2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(g2) = %v, want ErrNotFound", err)
+	}
+	if _, err := a.Load("../g1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Load(../g1) = %v, want ErrNotFound", err)
+	}
+	invalid := recordtest.Played(t, 2)
+	invalid.ID = "g 2"
+	if err := a.Save(invalid); err == nil {
+		t.Error("Save() accepted an invalid ID")
+	}
+}
+
+func TestGames(t *testing.T) {
+	a := openTestArchive(t, 3)
+	dora := recordtest.PlayedBy(t, 4, map[skat.Player]string{skat.Forehand: "dora", skat.Middlehand: "ben", skat.Rearhand: "carl"})
+	if err := a.Save(dora); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		player string
+		limit  int
+		want   []string
+	}{
+		{"anna", 0, []string{"g3", "g2", "g1"}},
+		{"anna", 2, []string{"g3", "g2"}},
+		{"ben", 0, []string{"g4", "g3", "g2", "g1"}},
+		{"dora", 10, []string{"g4"}},
+		{"emil", 0, nil},
+	}
+	for _, tt := range tests {
+		games, err := a.Games(tt.player, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range games {
+			ids = append(ids, r.ID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("Games(%s, %d) = %q, want %q", tt.player, tt.limit, ids, tt.want)
+		}
+	}
+}
+
+func TestPrivate(t *testing.T) {
+	a := openTestArchive(t, 2)
+	if err := a.SetPrivate("g1", true); err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsPrivate("g1") || a.IsPrivate("g2") {
+		t.Errorf("IsPrivate() = %v, %v, want g1 only", a.IsPrivate("g1"), a.IsPrivate("g2"))
+	}
+
+	public, err := a.Records(Filter{Player: "anna", Public: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(public) != 1 || public[0].ID != "g2" {
+		t.Errorf("public records = %d, want g2", len(public))
+	}
+	// The player's history still lists the private game
+	if games, _ := a.Games("anna", 0); len(games) != 2 {
+		t.Errorf("Games() = %d, want both", len(games))
+	}
+
+	if err := a.SetPrivate("g1", false); err != nil || a.IsPrivate("g1") {
+		t.Errorf("SetPrivate(false) = %v, private %v", err, a.IsPrivate("g1"))
+	}
+	// Clearing twice is fine, unknown games are not found
+	if err := a.SetPrivate("g1", false); err != nil {
+		t.Error(err)
+	}
+	if err := a.SetPrivate("g9", true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SetPrivate(g9) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOnSave(t *testing.T) {
+	a := openTestArchive(t, 0)
+	var saved []string
+	a.OnSave(func(r *replay.Replay) { saved = append(saved, r.ID) })
+	for seed := int64(1); seed <= 2; seed++ {
+		if err := a.Save(recordtest.Played(t, seed)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	invalid := recordtest.Played(t, 3)
+	invalid.ID = ""
+	a.Save(invalid)
+	if !slices.Equal(saved, []string{"g1", "g2"}) {
+		t.Errorf("OnSave() called with %q, want g1 and g2", saved)
+	}
+}
+
+func TestAdjourned(t *testing.T) {
+	a := openTestArchive(t, 3)
+	for _, id := range []string{"g3", "g1"} {
+		if err := a.SetAdjourned(id, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ids, err := a.Adjourned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ids, []string{"g1", "g3"}) {
+		t.Errorf("Adjourned() = %q, want g1 and g3", ids)
+	}
+	// Adjourned games are not counted in the statistics
+	collector, err := a.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anna, ok := collector.Player("anna"); !ok || anna.Games != 1 {
+		t.Errorf("anna is in the statistics with %+v, want 1 game", anna)
+	}
+
+	if err := a.SetAdjourned("g1", false); err != nil || a.IsAdjourned("g1") || !a.IsAdjourned("g3") {
+		t.Errorf("SetAdjourned(false) = %v, adjourned %v", err, a.IsAdjourned("g1"))
+	}
+	if err := a.SetAdjourned("g9", true); !errors.Is(err, ErrNotFound) {
+		t.Errorf("SetAdjourned(g9) = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// backupExt is the extension of the backup files.
+const backupExt = ".tar.gz"
+
+// Backup is a written backup of the archive directory.
+type Backup struct {
+	// File is the path of the backup file
+	File string `json:"file"`
+	// Files is the number of archived files, including the stores next to the games
+	Files int `json:"files"`
+	// Bytes is the size of the backup file
+	Bytes int64 `json:"bytes"`
+}
+
+// Backup writes the archive directory, with the seasons, tournaments and other
+// stores kept in it, as "freeskat-<time>.tar.gz" to dir. Games are not saved while
+// the backup is written.
+func (a *Archive) Backup(dir string) (*Backup, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "freeskat-"+time.Now().UTC().Format("20060102-150405")+backupExt)
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	files, err := a.writeBackup(f)
+	a.mu.RUnlock()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Backup{File: path, Files: files, Bytes: info.Size()}, nil
+}
+
+// writeBackup writes the files of the archive directory as gzipped tar and returns
+// their number. Temporary files are left out.
+func (a *Archive) writeBackup(w io.Writer) (int, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	files := 0
+	err := filepath.WalkDir(a.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(a.dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, src); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	return files, gz.Close()
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	a := openTestArchive(t, 2)
+	if err := a.SetPrivate("g1", true); err != nil {
+		t.Fatal(err)
+	}
+	// Stores next to the games are backed up, temporary files are not
+	if err := os.MkdirAll(filepath.Join(a.dir, "seasons"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"seasons/2025.json", "bans.json.tmp"} {
+		if err := os.WriteFile(filepath.Join(a.dir, name), []byte("{}"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "backups")
+	backup, err := a.Backup(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(backup.File) != dir || !strings.HasPrefix(filepath.Base(backup.File), "freeskat-") || !strings.HasSuffix(backup.File, ".tar.gz") {
+		t.Errorf("backup file = %s", backup.File)
+	}
+
+	f, err := os.Open(backup.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if info, _ := f.Stat(); info.Size() != backup.Bytes {
+		t.Errorf("Bytes = %d, want the file size %d", backup.Bytes, info.Size())
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for tr := tar.NewReader(gz); ; {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+	slices.Sort(names)
+	want := []string{"g1.json", "g1.private", "g2.json", "seasons/2025.json"}
+	if backup.Files != len(want) || !slices.Equal(names, want) {
+		t.Errorf("backup of %d files contains %q, want %q", backup.Files, names, want)
+	}
+}
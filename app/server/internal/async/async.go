@@ -0,0 +1,388 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package async keeps the correspondence games, in which the players make their moves
+// hours or days apart, in a JSON file.
+package async
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// GamePrefix starts the IDs of correspondence games, which are also their table names.
+const GamePrefix = "async-"
+
+const (
+	// MinMoveTime and MaxMoveTime limit the time a player has per move
+	MinMoveTime = time.Hour
+	MaxMoveTime = 14 * 24 * time.Hour
+	// MaxGames is the maximum number of open and running games of a player
+	MaxGames = 20
+)
+
+var (
+	// ErrNotFound is returned for unknown games.
+	ErrNotFound = errors.New("game not found")
+	// ErrFull is returned when joining a game whose seats are taken.
+	ErrFull = errors.New("the game is full")
+	// ErrStarted is returned when leaving a game that has been dealt.
+	ErrStarted = errors.New("the game has started")
+	// ErrNotPlaying is returned for players who did not join a game.
+	ErrNotPlaying = errors.New("not playing in this game")
+)
+
+// IsGame returns true if the ID or table name belongs to a correspondence game.
+func IsGame(id string) bool {
+	return strings.HasPrefix(id, GamePrefix)
+}
+
+// Game is a correspondence game. It is open until three players joined, then it is
+// dealt and the player to move has the move time for each move.
+type Game struct {
+	ID string `json:"id"`
+	// Players are the logins in the order they joined: forehand, middlehand, rearhand
+	Players  []string      `json:"players"`
+	MoveTime time.Duration `json:"moveTime"`
+	// Rules is the rule profile of the game, which sets the vacation days of its players
+	Rules   string    `json:"rules"`
+	Created time.Time `json:"created"`
+	// Replay holds the deal and the moves so far (nil while the game is open)
+	Replay *replay.Replay `json:"replay,omitempty"`
+	// Turn is the login of the player to move, who has to move before the Deadline
+	Turn     string    `json:"turn,omitempty"`
+	Deadline time.Time `json:"deadline,omitzero"`
+}
+
+// Running returns true if the game has been dealt.
+func (g *Game) Running() bool {
+	return g.Replay != nil
+}
+
+// Position returns the position of a player in the game.
+func (g *Game) Position(login string) (skat.Player, bool) {
+	for i, player := range g.Players {
+		if player == login {
+			return skat.AllPlayers[i], true
+		}
+	}
+	return 0, false
+}
+
+// Record returns the record of a running game.
+func (g *Game) Record() (*skat.GameRecord, error) {
+	if g.Replay == nil {
+		return nil, errors.New("the game has not been dealt")
+	}
+	return g.Replay.Record()
+}
+
+// Store keeps the open and running correspondence games. Finished games belong into
+// the game archive. It is safe for concurrent use.
+type Store struct {
+	path  string
+	games map[string]Game
+	mu    sync.Mutex
+}
+
+// Open opens the store in the file at path, which is created with the first game.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, games: make(map[string]Game)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var games []Game
+	if err := json.Unmarshal(data, &games); err != nil {
+		return nil, err
+	}
+	for _, g := range games {
+		s.games[g.ID] = g
+	}
+	return s, nil
+}
+
+// Get returns a game.
+func (s *Store) Get(id string) (Game, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g, ok := s.games[id]
+	return g, ok
+}
+
+// Games returns the open and running games of a player, oldest first.
+func (s *Store) Games(login string) []Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(func(g Game) bool {
+		_, ok := g.Position(login)
+		return ok
+	})
+}
+
+// Joinable returns the open games a player can join, oldest first.
+func (s *Store) Joinable(login string) []Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(func(g Game) bool {
+		_, ok := g.Position(login)
+		return !ok && !g.Running()
+	})
+}
+
+// Due returns the running games whose player to move is past the deadline.
+func (s *Store) Due(now time.Time) []Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(func(g Game) bool {
+		return g.Running() && now.After(g.Deadline)
+	})
+}
+
+// Create opens a new game with the player in the first seat.
+func (s *Store) Create(login string, moveTime time.Duration, rules string) (Game, error) {
+	if moveTime < MinMoveTime || moveTime > MaxMoveTime {
+		return Game{}, fmt.Errorf("invalid move time: %s (want %s to %s)", moveTime, MinMoveTime, MaxMoveTime)
+	}
+	id, err := newID()
+	if err != nil {
+		return Game{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkLimit(login); err != nil {
+		return Game{}, err
+	}
+	g := Game{ID: id, Players: []string{login}, MoveTime: moveTime, Rules: rules, Created: time.Now()}
+	return g, s.put(g)
+}
+
+// Join adds a player to an open game. The third player starts the game: the cards are
+// dealt and the move time of the first player to move starts.
+func (s *Store) Join(id, login string, now time.Time) (Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[id]
+	if !ok {
+		return Game{}, ErrNotFound
+	}
+	if _, ok := g.Position(login); ok {
+		return Game{}, fmt.Errorf("already playing in game %s", id)
+	}
+	if g.Running() {
+		return Game{}, ErrFull
+	}
+	if err := s.checkLimit(login); err != nil {
+		return Game{}, err
+	}
+
+	g.Players = append(append([]string(nil), g.Players...), login)
+	if len(g.Players) == len(skat.AllPlayers) {
+		record, err := deal(g, now)
+		if err != nil {
+			return Game{}, err
+		}
+		if err := g.advance(record, now); err != nil {
+			return Game{}, err
+		}
+	}
+	return g, s.put(g)
+}
+
+// Leave removes a player from an open game; the game is removed with its last player.
+func (s *Store) Leave(id, login string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[id]
+	if !ok {
+		return ErrNotFound
+	}
+	position, ok := g.Position(login)
+	if !ok {
+		return ErrNotPlaying
+	}
+	if g.Running() {
+		return ErrStarted
+	}
+	g.Players = append(append([]string(nil), g.Players[:position]...), g.Players[position+1:]...)
+	if len(g.Players) == 0 {
+		return s.remove(id)
+	}
+	return s.put(g)
+}
+
+// Update stores the moves of a running game and starts the move time of the next
+// player. A game that is over is removed from the store and returned without Turn.
+func (s *Store) Update(id string, record *skat.GameRecord, now time.Time) (Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[id]
+	if !ok || !g.Running() {
+		return Game{}, ErrNotFound
+	}
+	if err := g.advance(record, now); err != nil {
+		return Game{}, err
+	}
+	if g.Turn == "" {
+		return g, s.remove(id)
+	}
+	return g, s.put(g)
+}
+
+// Remove removes a game, e.g. one that ended without result.
+func (s *Store) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.games[id]; !ok {
+		return ErrNotFound
+	}
+	return s.remove(id)
+}
+
+// advance sets the moves of the game and the player to move ("" if the game is over).
+func (g *Game) advance(record *skat.GameRecord, now time.Time) error {
+	r, err := replay.New(record)
+	if err != nil {
+		return err
+	}
+	game, err := record.Replay(nil)
+	if err != nil {
+		return err
+	}
+	g.Replay = r
+	g.Turn, g.Deadline = "", time.Time{}
+	if active := game.ActivePlayer(); active != nil && game.State != skat.StateGameOver {
+		g.Turn = record.Players[*active]
+		g.Deadline = now.Add(g.MoveTime)
+	}
+	return nil
+}
+
+// deal shuffles and deals the cards of a game whose seats are taken.
+func deal(g Game, now time.Time) (*skat.GameRecord, error) {
+	deck := skat.NewDeck()
+	shuffle, err := deck.ShuffleCrypto()
+	if err != nil {
+		return nil, err
+	}
+	hands, skatCards, err := skat.DealCards(deck)
+	if err != nil {
+		return nil, err
+	}
+	record := &skat.GameRecord{
+		ID:        g.ID,
+		StartedAt: now,
+		Players:   make(map[skat.Player]string),
+		Hands:     hands,
+		Skat:      skatCards,
+		Shuffle:   shuffle,
+		Engine:    skat.EngineVersion,
+	}
+	for i, p := range skat.AllPlayers {
+		record.Players[p] = g.Players[i]
+	}
+	return record, nil
+}
+
+// newID returns a random game ID.
+func newID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return GamePrefix + hex.EncodeToString(b), nil
+}
+
+// checkLimit returns an error if the player has MaxGames games. The caller must hold
+// the lock.
+func (s *Store) checkLimit(login string) error {
+	n := 0
+	for _, g := range s.games {
+		if _, ok := g.Position(login); ok {
+			n++
+		}
+	}
+	if n >= MaxGames {
+		return fmt.Errorf("at most %d correspondence games per player", MaxGames)
+	}
+	return nil
+}
+
+// put stores a game. The caller must hold the lock.
+func (s *Store) put(g Game) error {
+	previous, existed := s.games[g.ID]
+	s.games[g.ID] = g
+	if err := s.write(); err != nil {
+		if existed {
+			s.games[g.ID] = previous
+		} else {
+			delete(s.games, g.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// remove removes a game. The caller must hold the lock.
+func (s *Store) remove(id string) error {
+	g := s.games[id]
+	delete(s.games, id)
+	if err := s.write(); err != nil {
+		s.games[id] = g
+		return err
+	}
+	return nil
+}
+
+// list returns the games matching the filter, oldest first. The caller must hold the lock.
+func (s *Store) list(match func(g Game) bool) []Game {
+	games := []Game{}
+	for _, g := range s.games {
+		if match(g) {
+			games = append(games, g)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if !games[i].Created.Equal(games[j].Created) {
+			return games[i].Created.Before(games[j].Created)
+		}
+		return games[i].ID < games[j].ID
+	})
+	return games
+}
+
+// write writes the games file. The caller must hold the lock.
+func (s *Store) write() error {
+	return jsonfile.Write(s.path, s.list(func(Game) bool { return true }))
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// openTestStore opens a store in a temporary directory.
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "async.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// startTestGame creates a game of anna, ben and carl dealt at the time.
+func startTestGame(t *testing.T, s *Store, moveTime time.Duration, at time.Time) Game {
+	t.Helper()
+	g, err := s.Create("anna", moveTime, "isko")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, login := range []string{"ben", "carl"} {
+		if g, err = s.Join(g.ID, login, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestDue(t *testing.T) {
+	s := openTestStore(t)
+	now := time.Now()
+	open, err := s.Create("dora", time.Hour, "isko")
+	if err != nil {
+		t.Fatal(err)
+	}
+	late := startTestGame(t, s, time.Hour, now.Add(-2*time.Hour))
+	running := startTestGame(t, s, 2*time.Hour, now.Add(-time.Hour))
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{"now", now, []string{late.ID}},
+		{"at the deadline", late.Deadline, nil},
+		{"after both deadlines", running.Deadline.Add(time.Second), []string{late.ID, running.ID}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, g := range s.Due(tt.now) {
+			if g.ID == open.ID {
+				t.Errorf("%s: open game is due", tt.name)
+			}
+			got = append(got, g.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: Due() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckLimit(t *testing.T) {
+	s := openTestStore(t)
+	for i := 0; i < MaxGames-1; i++ {
+		if _, err := s.Create("anna", time.Hour, "isko"); err != nil {
+			t.Fatalf("game %d: %v", i+1, err)
+		}
+	}
+	other, err := s.Create("ben", time.Hour, "isko")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Join(other.ID, "anna", time.Now()); err != nil {
+		t.Fatalf("joining game %d: %v", MaxGames, err)
+	}
+
+	open, err := s.Create("carl", time.Hour, "isko")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		action func() error
+	}{
+		{"create", func() error { _, err := s.Create("anna", time.Hour, "isko"); return err }},
+		{"join", func() error { _, err := s.Join(open.ID, "anna", time.Now()); return err }},
+	}
+	for _, tt := range tests {
+		if err := tt.action(); err == nil || !strings.Contains(err.Error(), "at most") {
+			t.Errorf("%s: error %v, want the limit of %d games", tt.name, err, MaxGames)
+		}
+	}
+	if got := len(s.Games("anna")); got != MaxGames {
+		t.Errorf("len(Games()) = %d, want %d", got, MaxGames)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+)
+
+// day is the unit of vacations.
+const day = 24 * time.Hour
+
+// Vacation is a period in which the move time of a player does not run.
+type Vacation struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Days returns the days the vacation counts, begun days included.
+func (v Vacation) Days() int {
+	return int((v.End.Sub(v.Start) + day - 1) / day)
+}
+
+// Vacations keeps the vacations of the players of the current and the last year.
+// It is safe for concurrent use.
+type Vacations struct {
+	path      string
+	vacations map[string][]Vacation
+	mu        sync.Mutex
+}
+
+// OpenVacations opens the vacations in the file at path, which is created with the
+// first vacation.
+func OpenVacations(path string) (*Vacations, error) {
+	v := &Vacations{path: path, vacations: make(map[string][]Vacation)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &v.vacations); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Current returns the vacation of a player at the time.
+func (v *Vacations) Current(login string, now time.Time) (Vacation, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return current(v.vacations[login], now)
+}
+
+// Taken returns the vacation days a player has taken in the year of the time (UTC),
+// including the days of the current vacation.
+func (v *Vacations) Taken(login string, now time.Time) int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return taken(v.vacations[login], now.UTC().Year())
+}
+
+// Start starts a vacation of the player for the days if they fit into the days per
+// year the player is allowed.
+func (v *Vacations) Start(login string, days, allowed int, now time.Time) (Vacation, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	vacations := v.vacations[login]
+	if c, ok := current(vacations, now); ok {
+		return Vacation{}, fmt.Errorf("on vacation until %s", c.End.UTC().Format(time.RFC3339))
+	}
+	if left := allowed - taken(vacations, now.UTC().Year()); days < 1 || days > left {
+		return Vacation{}, fmt.Errorf("invalid vacation: %d days (%d of %d days left this year)", days, max(left, 0), allowed)
+	}
+
+	vacation := Vacation{Start: now, End: now.Add(time.Duration(days) * day)}
+	v.vacations[login] = append(prune(vacations, now), vacation)
+	if err := v.write(); err != nil {
+		v.vacations[login] = vacations
+		return Vacation{}, err
+	}
+	return vacation, nil
+}
+
+// End ends the current vacation of a player early; only the begun days count.
+func (v *Vacations) End(login string, now time.Time) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	vacations := v.vacations[login]
+	if _, ok := current(vacations, now); !ok {
+		return errors.New("not on vacation")
+	}
+	ended := append([]Vacation(nil), vacations...)
+	ended[len(ended)-1].End = now
+	v.vacations[login] = ended
+	if err := v.write(); err != nil {
+		v.vacations[login] = vacations
+		return err
+	}
+	return nil
+}
+
+// Deadline returns the deadline of a move of the player that started at since with
+// the move time, extended by the vacations of the player since then.
+func (v *Vacations) Deadline(login string, since time.Time, moveTime time.Duration) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	deadline := since.Add(moveTime)
+	for _, vacation := range v.vacations[login] {
+		start := vacation.Start
+		if start.Before(since) {
+			start = since
+		}
+		if start.Before(deadline) && vacation.End.After(start) {
+			deadline = deadline.Add(vacation.End.Sub(start))
+		}
+	}
+	return deadline
+}
+
+// current returns the vacation running at the time, which is always the last one.
+func current(vacations []Vacation, now time.Time) (Vacation, bool) {
+	if n := len(vacations); n > 0 && !now.Before(vacations[n-1].Start) && now.Before(vacations[n-1].End) {
+		return vacations[n-1], true
+	}
+	return Vacation{}, false
+}
+
+// taken returns the vacation days that started in the year (UTC).
+func taken(vacations []Vacation, year int) int {
+	days := 0
+	for _, vacation := range vacations {
+		if vacation.Start.UTC().Year() == year {
+			days += vacation.Days()
+		}
+	}
+	return days
+}
+
+// prune returns the vacations without those that ended before the last year.
+func prune(vacations []Vacation, now time.Time) []Vacation {
+	kept := []Vacation{}
+	for _, vacation := range vacations {
+		if vacation.End.UTC().Year() >= now.UTC().Year()-1 {
+			kept = append(kept, vacation)
+		}
+	}
+	return kept
+}
+
+// write writes the vacations file. The caller must hold the lock.
+func (v *Vacations) write() error {
+	return jsonfile.Write(v.path, v.vacations)
+}
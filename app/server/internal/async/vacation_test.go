@@ -0,0 +1,163 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+
+func TestVacationDays(t *testing.T) {
+	tests := []struct {
+		length time.Duration
+		want   int
+	}{
+		{0, 0},
+		{time.Hour, 1},
+		{day, 1},
+		{day + time.Minute, 2},
+		{14 * day, 14},
+	}
+	for _, tt := range tests {
+		if got := (Vacation{Start: t0, End: t0.Add(tt.length)}).Days(); got != tt.want {
+			t.Errorf("Days() of %s = %d, want %d", tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	moveTime := 24 * time.Hour
+	vacation := func(start, end time.Duration) Vacation {
+		return Vacation{Start: t0.Add(start), End: t0.Add(end)}
+	}
+
+	tests := []struct {
+		name      string
+		vacations []Vacation
+		want      time.Duration // after the move start t0
+	}{
+		{"no vacation", nil, moveTime},
+		{"during the move", []Vacation{vacation(2*time.Hour, 5*time.Hour)}, moveTime + 3*time.Hour},
+		{"overlapping the move start", []Vacation{vacation(-2*day, 2*time.Hour)}, moveTime + 2*time.Hour},
+		{"ended before the move", []Vacation{vacation(-3*day, -day)}, moveTime},
+		{"after the deadline", []Vacation{vacation(moveTime, moveTime+day)}, moveTime},
+		{"ended early", []Vacation{vacation(time.Hour, 90*time.Minute)}, moveTime + 30*time.Minute},
+		{"running past the deadline", []Vacation{vacation(12*time.Hour, 12*time.Hour+3*day)}, moveTime + 3*day},
+		{
+			// The second vacation starts after the deadline of the move, but before
+			// the deadline extended by the first
+			"after an extended deadline",
+			[]Vacation{vacation(time.Hour, 11*time.Hour), vacation(moveTime+5*time.Hour, moveTime+7*time.Hour)},
+			moveTime + 12*time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Vacations{vacations: map[string][]Vacation{"anna": tt.vacations}}
+			if got := v.Deadline("anna", t0, moveTime); !got.Equal(t0.Add(tt.want)) {
+				t.Errorf("Deadline() = %s, want %s", got, t0.Add(tt.want))
+			}
+			if got := v.Deadline("ben", t0, moveTime); !got.Equal(t0.Add(moveTime)) {
+				t.Errorf("Deadline() of another player = %s, want %s", got, t0.Add(moveTime))
+			}
+		})
+	}
+}
+
+func TestStartEnd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vacations.json")
+	v, err := OpenVacations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name    string
+		action  func() error
+		wantErr bool
+		taken   int
+		away    bool
+	}{
+		{"no days", func() error { _, err := v.Start("anna", 0, 14, t0); return err }, true, 0, false},
+		{"more than allowed", func() error { _, err := v.Start("anna", 15, 14, t0); return err }, true, 0, false},
+		{"not on vacation", func() error { return v.End("anna", t0) }, true, 0, false},
+		{"start", func() error { _, err := v.Start("anna", 10, 14, t0); return err }, false, 10, true},
+		{"already on vacation", func() error { _, err := v.Start("anna", 1, 14, t0.Add(day)); return err }, true, 10, true},
+		// Ended after a day and a half: two begun days count
+		{"end early", func() error { return v.End("anna", t0.Add(36*time.Hour)) }, false, 2, false},
+		{"more than left", func() error { _, err := v.Start("anna", 13, 14, t0.Add(2*day)); return err }, true, 2, false},
+		{"the rest", func() error { _, err := v.Start("anna", 12, 14, t0.Add(2*day)); return err }, false, 14, true},
+	}
+
+	for _, step := range steps {
+		if err := step.action(); (err != nil) != step.wantErr {
+			t.Fatalf("%s: error %v, want error %v", step.name, err, step.wantErr)
+		}
+		now := t0.Add(2 * day)
+		if taken := v.Taken("anna", now); taken != step.taken {
+			t.Errorf("%s: Taken() = %d, want %d", step.name, taken, step.taken)
+		}
+		if _, away := v.Current("anna", now); away != step.away {
+			t.Errorf("%s: on vacation %v, want %v", step.name, away, step.away)
+		}
+	}
+
+	// The vacations are kept in the file
+	reopened, err := OpenVacations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if taken := reopened.Taken("anna", t0); taken != 14 {
+		t.Errorf("Taken() after reopening = %d, want 14", taken)
+	}
+	if vacation, ok := reopened.Current("anna", t0.Add(3*day)); !ok || !vacation.End.Equal(t0.Add(14*day)) {
+		t.Errorf("Current() after reopening = %v, %v, want until %s", vacation, ok, t0.Add(14*day))
+	}
+}
+
+func TestTakenAndPrune(t *testing.T) {
+	year := func(y int) Vacation {
+		start := time.Date(y, 12, 30, 0, 0, 0, 0, time.UTC)
+		return Vacation{Start: start, End: start.Add(5 * day)}
+	}
+	vacations := []Vacation{year(2022), year(2023), year(2024), year(2025)}
+
+	tests := []struct {
+		year int
+		want int
+	}{
+		{2022, 5},
+		{2024, 5},
+		{2025, 5},
+		// The vacation over new year counts for the year it started
+		{2026, 0},
+	}
+	for _, tt := range tests {
+		if got := taken(vacations, tt.year); got != tt.want {
+			t.Errorf("taken(%d) = %d, want %d", tt.year, got, tt.want)
+		}
+	}
+
+	// In 2025 the vacations that ended before 2024 are dropped; the one of 2023
+	// ended in 2024
+	kept := prune(vacations, t0)
+	if len(kept) != 3 || !kept[0].Start.Equal(year(2023).Start) {
+		t.Errorf("prune() kept %v, want the vacations since 2023", kept)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ban keeps the logins banned by the operators in a JSON file.
+package ban
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+)
+
+// ErrNotFound is returned for logins that are not banned.
+var ErrNotFound = errors.New("login not banned")
+
+// Ban is a banned login.
+type Ban struct {
+	Login  string    `json:"login"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// Store keeps the bans. It is safe for concurrent use.
+type Store struct {
+	path string
+	bans map[string]Ban
+	mu   sync.Mutex
+}
+
+// Open opens the store in the file at path, which is created with the first ban.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, bans: make(map[string]Ban)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bans []Ban
+	if err := json.Unmarshal(data, &bans); err != nil {
+		return nil, err
+	}
+	for _, b := range bans {
+		s.bans[b.Login] = b
+	}
+	return s, nil
+}
+
+// Ban bans a login; banning it again replaces the reason.
+func (s *Store) Ban(login, reason string) (Ban, error) {
+	if login == "" {
+		return Ban{}, errors.New("login required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := Ban{Login: login, Reason: reason, Time: time.Now()}
+	previous, banned := s.bans[login]
+	s.bans[login] = b
+	if err := s.write(); err != nil {
+		if banned {
+			s.bans[login] = previous
+		} else {
+			delete(s.bans, login)
+		}
+		return Ban{}, err
+	}
+	return b, nil
+}
+
+// Unban lifts the ban of a login.
+func (s *Store) Unban(login string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.bans[login]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.bans, login)
+	if err := s.write(); err != nil {
+		s.bans[login] = b
+		return err
+	}
+	return nil
+}
+
+// IsBanned returns true if a login is banned.
+func (s *Store) IsBanned(login string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.bans[login]
+	return ok
+}
+
+// List returns the bans ordered by login.
+func (s *Store) List() []Ban {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list()
+}
+
+// list returns the bans ordered by login. The caller must hold the lock.
+func (s *Store) list() []Ban {
+	bans := make([]Ban, 0, len(s.bans))
+	for _, b := range s.bans {
+		bans = append(bans, b)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Login < bans[j].Login
+	})
+	return bans
+}
+
+// write writes the bans file. The caller must hold the lock.
+func (s *Store) write() error {
+	return jsonfile.Write(s.path, s.list())
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ban
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, login := range []string{"emil", "anna"} {
+		if _, err := s.Ban(login, "spam"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Banning again replaces the reason
+	if b, err := s.Ban("anna", "cheating"); err != nil || b.Reason != "cheating" {
+		t.Errorf("Ban(anna) = %+v, %v", b, err)
+	}
+	if _, err := s.Ban("", "spam"); err == nil {
+		t.Error("Ban() accepted an empty login")
+	}
+	if !s.IsBanned("anna") || s.IsBanned("ben") {
+		t.Errorf("IsBanned() = %v, %v, want anna only", s.IsBanned("anna"), s.IsBanned("ben"))
+	}
+
+	// The bans are kept in the file
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bans := reopened.List()
+	if len(bans) != 2 || bans[0].Login != "anna" || bans[0].Reason != "cheating" || bans[1].Login != "emil" {
+		t.Errorf("List() after reopening = %+v", bans)
+	}
+
+	if err := reopened.Unban("anna"); err != nil || reopened.IsBanned("anna") {
+		t.Errorf("Unban(anna) = %v, banned %v", err, reopened.IsBanned("anna"))
+	}
+	if err := reopened.Unban("anna"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Unban(anna) again = %v, want ErrNotFound", err)
+	}
+	if again, _ := Open(path); len(again.List()) != 1 {
+		t.Errorf("%d bans in the file, want 1", len(again.List()))
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package botpool manages the server-side bot identities used to fill table seats.
+package botpool
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+)
+
+var (
+	// ErrNoBotAvailable is returned if all bot identities are seated.
+	ErrNoBotAvailable = errors.New("no bot available")
+	// ErrGameLimitReached is returned if the maximum number of concurrent bot games is reached.
+	ErrGameLimitReached = errors.New("concurrent bot game limit reached")
+)
+
+// Bot is a bot identity of the pool.
+type Bot struct {
+	// Name is the login name of the bot
+	Name string
+	// Difficulty is the AI difficulty of the bot
+	Difficulty ai.Difficulty
+	// Player is the AI making the bot's decisions
+	Player ai.AIPlayer
+	// Table is the table the bot is seated at ("" if idle)
+	Table string
+}
+
+// Pool manages a fixed set of bot identities.
+type Pool struct {
+	bots     []*Bot
+	maxGames int
+	mu       sync.Mutex
+}
+
+// New creates a pool of count bots named "<prefix>1".."<prefix>N" that play at most
+// maxGames tables at the same time (0 = no limit).
+func New(prefix string, count int, maxGames int, difficulty ai.Difficulty) *Pool {
+	p := &Pool{
+		bots:     make([]*Bot, count),
+		maxGames: maxGames,
+	}
+	for i := range p.bots {
+		p.bots[i] = &Bot{
+			Name:       fmt.Sprintf("%s%d", prefix, i+1),
+			Difficulty: difficulty,
+			Player:     ai.New(difficulty, nil),
+		}
+	}
+	return p
+}
+
+// Acquire seats an idle bot at the given table.
+func (p *Pool) Acquire(table string) (*Bot, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.maxGames > 0 && !p.hasTable(table) && len(p.tables()) >= p.maxGames {
+		return nil, ErrGameLimitReached
+	}
+
+	for _, bot := range p.bots {
+		if bot.Table == "" {
+			bot.Table = table
+			log.Printf("[botpool] %s seated at table %s", bot.Name, table)
+			return bot, nil
+		}
+	}
+	return nil, ErrNoBotAvailable
+}
+
+// Release returns the named bot to the pool.
+func (p *Pool) Release(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, bot := range p.bots {
+		if bot.Name == name && bot.Table != "" {
+			log.Printf("[botpool] %s released from table %s", bot.Name, bot.Table)
+			bot.Table = ""
+		}
+	}
+}
+
+// ReleaseSeat releases one bot seated at the table so a human can take the seat.
+// Returns false if no bot is seated at the table.
+func (p *Pool) ReleaseSeat(table string) (*Bot, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, bot := range p.bots {
+		if bot.Table == table {
+			log.Printf("[botpool] %s gives up its seat at table %s", bot.Name, table)
+			bot.Table = ""
+			return bot, true
+		}
+	}
+	return nil, false
+}
+
+// ReleaseTable returns all bots seated at the table to the pool.
+func (p *Pool) ReleaseTable(table string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, bot := range p.bots {
+		if bot.Table == table {
+			bot.Table = ""
+		}
+	}
+}
+
+// IsBot returns true if the name belongs to a bot of the pool.
+func (p *Pool) IsBot(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, bot := range p.bots {
+		if bot.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Idle returns the number of bots not seated at a table.
+func (p *Pool) Idle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idle := 0
+	for _, bot := range p.bots {
+		if bot.Table == "" {
+			idle++
+		}
+	}
+	return idle
+}
+
+// ActiveGames returns the number of tables with at least one bot.
+func (p *Pool) ActiveGames() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.tables())
+}
+
+// hasTable returns true if a bot is seated at the table (lock must be held).
+func (p *Pool) hasTable(table string) bool {
+	for _, bot := range p.bots {
+		if bot.Table == table {
+			return true
+		}
+	}
+	return false
+}
+
+// tables returns the tables with at least one bot (lock must be held).
+func (p *Pool) tables() map[string]bool {
+	tables := make(map[string]bool)
+	for _, bot := range p.bots {
+		if bot.Table != "" {
+			tables[bot.Table] = true
+		}
+	}
+	return tables
+}
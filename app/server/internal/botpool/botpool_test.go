@@ -0,0 +1,123 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package botpool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+)
+
+func TestAcquire(t *testing.T) {
+	tests := []struct {
+		name     string
+		bots     int
+		maxGames int
+		tables   []string // tables a bot is acquired for, in order
+		wantErr  error    // of the last acquisition
+	}{
+		{"first bot", 2, 0, []string{"t1"}, nil},
+		{"same table", 2, 1, []string{"t1", "t1"}, nil},
+		{"exhausted", 2, 0, []string{"t1", "t1", "t2"}, ErrNoBotAvailable},
+		{"game limit", 4, 1, []string{"t1", "t2"}, ErrGameLimitReached},
+		{"below the game limit", 4, 2, []string{"t1", "t2"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := New("bot", tt.bots, tt.maxGames, ai.DifficultyClub)
+			var err error
+			var bot *Bot
+			for _, table := range tt.tables {
+				if bot, err = pool.Acquire(table); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Acquire() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if bot.Table != tt.tables[len(tt.tables)-1] {
+				t.Errorf("Table = %q, want %q", bot.Table, tt.tables[len(tt.tables)-1])
+			}
+			if got, want := pool.Idle(), tt.bots-len(tt.tables); got != want {
+				t.Errorf("Idle() = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestRelease(t *testing.T) {
+	pool := New("bot", 3, 0, ai.DifficultyClub)
+	first, err := pool.Acquire("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Acquire("t1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Acquire("t2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Acquire("t3"); !errors.Is(err, ErrNoBotAvailable) {
+		t.Fatalf("Acquire() of a full pool error = %v, want ErrNoBotAvailable", err)
+	}
+	if got := pool.ActiveGames(); got != 2 {
+		t.Errorf("ActiveGames() = %d, want 2", got)
+	}
+
+	// A released bot can be seated again
+	pool.Release(first.Name)
+	if first.Table != "" || pool.Idle() != 1 {
+		t.Errorf("after Release() Table = %q, Idle() = %d, want \"\" and 1", first.Table, pool.Idle())
+	}
+	bot, err := pool.Acquire("t3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot != first {
+		t.Errorf("Acquire() = %s, want the released %s", bot.Name, first.Name)
+	}
+
+	// A human takes the seat of the bot at t2
+	seat, ok := pool.ReleaseSeat("t2")
+	if !ok || seat.Table != "" {
+		t.Fatalf("ReleaseSeat() = %v, %v, want an idle bot", seat, ok)
+	}
+	if _, ok := pool.ReleaseSeat("t2"); ok {
+		t.Error("ReleaseSeat() of a table without bots = true, want false")
+	}
+	if got := pool.ActiveGames(); got != 2 {
+		t.Errorf("ActiveGames() = %d, want 2", got)
+	}
+
+	pool.ReleaseTable("t1")
+	pool.ReleaseTable("t3")
+	if pool.Idle() != 3 || pool.ActiveGames() != 0 {
+		t.Errorf("after ReleaseTable() Idle() = %d, ActiveGames() = %d, want 3 and 0", pool.Idle(), pool.ActiveGames())
+	}
+}
+
+func TestIsBot(t *testing.T) {
+	pool := New("bot", 2, 0, ai.DifficultyClub)
+	for name, want := range map[string]bool{"bot1": true, "bot2": true, "bot3": false, "anna": false} {
+		if got := pool.IsBot(name); got != want {
+			t.Errorf("IsBot(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package challenge provides rotating daily and weekly challenges, e.g. "Win 2 suit
+// games as declarer": tasks generated per day and week and tracked against the
+// archived games to give casual players recurring goals.
+package challenge
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Period is how long a challenge runs.
+type Period string
+
+const (
+	// Daily challenges run from midnight to midnight
+	Daily Period = "daily"
+	// Weekly challenges run from Monday to Sunday
+	Weekly Period = "weekly"
+)
+
+// Periods contains all periods in the order they are listed.
+var Periods = []Period{Daily, Weekly}
+
+// PerPeriod is the number of challenges of a period.
+const PerPeriod = 3
+
+// weeklyFactor multiplies the targets of the weekly challenges.
+const weeklyFactor = 4
+
+// Kind is the task of a challenge.
+type Kind string
+
+const (
+	// KindPlay - finish games
+	KindPlay Kind = "play"
+	// KindWin - win games as declarer
+	KindWin Kind = "win"
+	// KindSuit - win suit games as declarer
+	KindSuit Kind = "suit"
+	// KindGrand - win Grand games as declarer
+	KindGrand Kind = "grand"
+	// KindNull - win Null games as declarer
+	KindNull Kind = "null"
+	// KindHand - win Hand games as declarer
+	KindHand Kind = "hand"
+	// KindSchneider - win games Schneider as declarer
+	KindSchneider Kind = "schneider"
+	// KindDefend - defeat the declarer as defender
+	KindDefend Kind = "defend"
+)
+
+// task is the daily target range of a kind.
+type task struct {
+	kind     Kind
+	min, max int
+}
+
+// tasks are the kinds challenges are drawn from with their daily targets.
+var tasks = []task{
+	{KindPlay, 3, 5},
+	{KindWin, 1, 3},
+	{KindSuit, 1, 3},
+	{KindGrand, 1, 2},
+	{KindNull, 1, 1},
+	{KindHand, 1, 1},
+	{KindSchneider, 1, 1},
+	{KindDefend, 1, 3},
+}
+
+// descriptions are the English descriptions of the kinds; %d is the target.
+var descriptions = map[Kind]string{
+	KindPlay:      "Play %d games",
+	KindWin:       "Win %d games as declarer",
+	KindSuit:      "Win %d suit games as declarer",
+	KindGrand:     "Win %d Grand games as declarer",
+	KindNull:      "Win %d Null games as declarer",
+	KindHand:      "Win %d Hand games as declarer",
+	KindSchneider: "Win %d games Schneider as declarer",
+	KindDefend:    "Defeat the declarer %d times as defender",
+}
+
+// Challenge is a task of a period.
+type Challenge struct {
+	// ID is "<period key>-<number>", e.g. "2025-03-14-1" or "2025-W11-2"
+	ID     string `json:"id"`
+	Period Period `json:"period"`
+	Kind   Kind   `json:"kind"`
+	Target int    `json:"target"`
+	// Ends is the end of the period
+	Ends time.Time `json:"ends"`
+}
+
+// Description returns the English description of the task, a format string whose
+// %d is the target (e.g. to translate it).
+func (c Challenge) Description() string {
+	return descriptions[c.Kind]
+}
+
+// String returns the English description with the target.
+func (c Challenge) String() string {
+	return fmt.Sprintf(c.Description(), c.Target)
+}
+
+// Counts returns true if a finished game counts for the challenge for the player at
+// a position.
+func (c Challenge) Counts(r *replay.Replay, position int) bool {
+	if r.Result == nil && r.Ramsch == nil {
+		return false
+	}
+	if c.Kind == KindPlay {
+		return true
+	}
+	if r.Result == nil || r.Declarer == nil {
+		return false
+	}
+	if c.Kind == KindDefend {
+		return *r.Declarer != position && !r.Result.DeclarerWon
+	}
+	if *r.Declarer != position || !r.Result.DeclarerWon {
+		return false
+	}
+	contract, err := skat.ContractFromCode(r.Contract)
+	if err != nil {
+		return false
+	}
+	switch c.Kind {
+	case KindSuit:
+		return contract.GameType.IsSuitGame()
+	case KindGrand:
+		return contract.GameType.IsGrand()
+	case KindNull:
+		return contract.GameType.IsNull()
+	case KindHand:
+		return contract.Hand
+	case KindSchneider:
+		return r.Result.Schneider
+	}
+	return true
+}
+
+// periodKey returns the key of the period at time t in a location: the date of a day
+// or the ISO week ("2025-W11"), and the end of the period.
+func periodKey(p Period, t time.Time, location *time.Location) (string, time.Time) {
+	local := t.In(location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	if p == Daily {
+		return day.Format("2006-01-02"), day.AddDate(0, 0, 1)
+	}
+	year, week := local.ISOWeek()
+	// Days since Monday
+	offset := (int(day.Weekday()) + 6) % 7
+	return fmt.Sprintf("%04d-W%02d", year, week), day.AddDate(0, 0, 7-offset)
+}
+
+// generate draws the challenges of a period from a seed derived from the secret and
+// the key, so the same secret gives the same challenges.
+func generate(secret string, p Period, key string, ends time.Time) []Challenge {
+	sum := sha256.Sum256([]byte(secret + "\x00challenge\x00" + key))
+	rng := rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+
+	challenges := make([]Challenge, 0, PerPeriod)
+	for i, n := range rng.Perm(len(tasks))[:PerPeriod] {
+		t := tasks[n]
+		target := t.min + rng.Intn(t.max-t.min+1)
+		if p == Weekly {
+			target *= weeklyFactor
+		}
+		challenges = append(challenges, Challenge{
+			ID: fmt.Sprintf("%s-%d", key, i+1), Period: p, Kind: t.kind, Target: target, Ends: ends,
+		})
+	}
+	return challenges
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package challenge
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// grandGame returns the replay of the Grand Hand of anna at Forehand, or of her lost
+// Null with lose.
+func grandGame(t *testing.T, id string, lose bool) *replay.Replay {
+	t.Helper()
+	r, err := replay.New(recordtest.Grand(t, id, time.Date(2025, 3, 14, 18, 0, 0, 0, time.UTC), lose))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestPeriodKey(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	// Sunday evening in UTC is Monday in Berlin
+	sunday := time.Date(2025, 3, 16, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		period   Period
+		location *time.Location
+		key      string
+		ends     time.Time
+	}{
+		{Daily, time.UTC, "2025-03-16", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{Weekly, time.UTC, "2025-W11", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{Daily, berlin, "2025-03-17", time.Date(2025, 3, 17, 23, 0, 0, 0, time.UTC)},
+		{Weekly, berlin, "2025-W12", time.Date(2025, 3, 23, 23, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		key, ends := periodKey(tt.period, sunday, tt.location)
+		if key != tt.key || !ends.Equal(tt.ends) {
+			t.Errorf("periodKey(%s, %s) = %s, %v, want %s, %v", tt.period, tt.location, key, ends, tt.key, tt.ends)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ends := time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)
+	daily := generate("secret", Daily, "2025-03-16", ends)
+	if !reflect.DeepEqual(daily, generate("secret", Daily, "2025-03-16", ends)) {
+		t.Error("the challenges changed with the same secret")
+	}
+	if reflect.DeepEqual(daily, generate("secret", Daily, "2025-03-17", ends)) || reflect.DeepEqual(daily, generate("other", Daily, "2025-03-16", ends)) {
+		t.Error("the challenges do not depend on the period and the secret")
+	}
+	if len(daily) != PerPeriod {
+		t.Fatalf("generate() = %d challenges, want %d", len(daily), PerPeriod)
+	}
+	kinds := make(map[Kind]bool)
+	for i, c := range daily {
+		if c.ID != fmt.Sprintf("2025-03-16-%d", i+1) || kinds[c.Kind] || c.Target < 1 || c.String() == "" {
+			t.Errorf("challenge %d = %+v", i, c)
+		}
+		kinds[c.Kind] = true
+	}
+	for _, c := range generate("secret", Weekly, "2025-W11", ends) {
+		if c.Target%weeklyFactor != 0 {
+			t.Errorf("weekly target %d of %s is not multiplied", c.Target, c.Kind)
+		}
+	}
+}
+
+func TestCounts(t *testing.T) {
+	won, lost := grandGame(t, "g1", false), grandGame(t, "g2", true)
+	unfinished := grandGame(t, "g3", false)
+	unfinished.Result = nil
+
+	tests := []struct {
+		kind Kind
+		// Counts for anna, ben in the won Grand and in the lost Null
+		won, lost [2]bool
+	}{
+		{KindPlay, [2]bool{true, true}, [2]bool{true, true}},
+		{KindWin, [2]bool{true, false}, [2]bool{false, false}},
+		{KindSuit, [2]bool{false, false}, [2]bool{false, false}},
+		{KindGrand, [2]bool{true, false}, [2]bool{false, false}},
+		{KindNull, [2]bool{false, false}, [2]bool{false, false}},
+		{KindHand, [2]bool{true, false}, [2]bool{false, false}},
+		{KindSchneider, [2]bool{true, false}, [2]bool{false, false}},
+		{KindDefend, [2]bool{false, false}, [2]bool{false, true}},
+	}
+	for _, tt := range tests {
+		c := Challenge{Kind: tt.kind, Target: 1}
+		got := [2][2]bool{
+			{c.Counts(won, 0), c.Counts(won, 1)},
+			{c.Counts(lost, 0), c.Counts(lost, 1)},
+		}
+		if got != [2][2]bool{tt.won, tt.lost} {
+			t.Errorf("%s counts %v, want %v and %v", tt.kind, got, tt.won, tt.lost)
+		}
+		if c.Counts(unfinished, 0) {
+			t.Errorf("%s counts an unfinished game", tt.kind)
+		}
+	}
+}
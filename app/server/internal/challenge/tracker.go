@@ -0,0 +1,206 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package challenge
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// Progress is the progress of a player in a challenge.
+type Progress struct {
+	Challenge
+	Progress  int  `json:"progress"`
+	Completed bool `json:"completed"`
+}
+
+// Completion is a challenge a player has just completed.
+type Completion struct {
+	Login     string
+	Challenge Challenge
+}
+
+// period is the state of a running period.
+type period struct {
+	Key        string      `json:"key"`
+	Challenges []Challenge `json:"challenges"`
+	// Games are the IDs of the counted games
+	Games []string `json:"games"`
+	// Progress is the progress of the players per challenge
+	Progress map[string][]int `json:"progress"`
+}
+
+// Tracker tracks the progress of the players in the challenges of the running periods,
+// in a JSON file if it has a path. The challenges of a period are stored when they are
+// generated, so they stay the same after a restart. It is safe for concurrent use.
+type Tracker struct {
+	path     string
+	secret   string
+	location *time.Location
+	// Ignore returns true for logins that do not take part, e.g. bots (nil = none)
+	Ignore  func(login string) bool
+	periods map[Period]*period
+	mu      sync.Mutex
+}
+
+// Open opens the tracker in the file at path ("" = memory only). The challenges are
+// derived from the secret; the periods start at midnight in the location.
+func Open(path, secret string, location *time.Location) (*Tracker, error) {
+	t := &Tracker{path: path, secret: secret, location: location, periods: make(map[Period]*period)}
+	if path == "" {
+		return t, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var periods []*period
+	if err := json.Unmarshal(data, &periods); err != nil {
+		return nil, err
+	}
+	for _, p := range periods {
+		if len(p.Challenges) > 0 {
+			t.periods[p.Challenges[0].Period] = p
+		}
+	}
+	return t, nil
+}
+
+// Current returns the challenges running at time now, daily first.
+func (t *Tracker) Current(now time.Time) ([]Challenge, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.rotate(now); err != nil {
+		return nil, err
+	}
+	var challenges []Challenge
+	for _, p := range Periods {
+		challenges = append(challenges, t.periods[p].Challenges...)
+	}
+	return challenges, nil
+}
+
+// Progress returns the progress of a player in the challenges running at time now.
+func (t *Tracker) Progress(login string, now time.Time) ([]Progress, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.rotate(now); err != nil {
+		return nil, err
+	}
+	var progress []Progress
+	for _, p := range Periods {
+		state := t.periods[p]
+		counts := state.Progress[login]
+		for i, c := range state.Challenges {
+			n := 0
+			if i < len(counts) {
+				n = counts[i]
+			}
+			progress = append(progress, Progress{Challenge: c, Progress: min(n, c.Target), Completed: n >= c.Target})
+		}
+	}
+	return progress, nil
+}
+
+// Record counts a finished game at time now for the challenges of its players and
+// returns the challenges they have just completed. Unfinished games and games counted
+// before are skipped.
+func (t *Tracker) Record(r *replay.Replay, now time.Time) ([]Completion, error) {
+	if r.Result == nil && r.Ramsch == nil {
+		return nil, nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.rotate(now); err != nil {
+		return nil, err
+	}
+
+	var completions []Completion
+	changed := false
+	for _, p := range Periods {
+		state := t.periods[p]
+		if slices.Contains(state.Games, r.ID) {
+			continue
+		}
+		state.Games = append(state.Games, r.ID)
+		changed = true
+		for _, player := range r.Players {
+			if t.Ignore != nil && t.Ignore(player.Name) {
+				continue
+			}
+			counts := state.Progress[player.Name]
+			if len(counts) < len(state.Challenges) {
+				counts = append(counts, make([]int, len(state.Challenges)-len(counts))...)
+			}
+			for i, c := range state.Challenges {
+				if counts[i] >= c.Target || !c.Counts(r, player.Position) {
+					continue
+				}
+				counts[i]++
+				if counts[i] == c.Target {
+					completions = append(completions, Completion{Login: player.Name, Challenge: c})
+				}
+			}
+			state.Progress[player.Name] = counts
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+	return completions, t.write()
+}
+
+// rotate replaces the states of the periods that are over at time now by new ones.
+// The caller must hold the lock.
+func (t *Tracker) rotate(now time.Time) error {
+	changed := false
+	for _, p := range Periods {
+		key, ends := periodKey(p, now, t.location)
+		if state := t.periods[p]; state != nil && state.Key == key {
+			continue
+		}
+		t.periods[p] = &period{Key: key, Challenges: generate(t.secret, p, key, ends), Games: []string{},
+			Progress: make(map[string][]int)}
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return t.write()
+}
+
+// write writes the tracker file. The caller must hold the lock.
+func (t *Tracker) write() error {
+	if t.path == "" {
+		return nil
+	}
+	periods := make([]*period, 0, len(Periods))
+	for _, p := range Periods {
+		if state := t.periods[p]; state != nil {
+			periods = append(periods, state)
+		}
+	}
+	return jsonfile.Write(t.path, periods)
+}
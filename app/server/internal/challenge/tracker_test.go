@@ -0,0 +1,107 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package challenge
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "challenges.json")
+	tr, err := Open(path, "secret", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Ignore = func(login string) bool { return login == "carl" }
+	now := time.Date(2025, 3, 14, 18, 0, 0, 0, time.UTC)
+	current, err := tr.Current(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(current) != 2*PerPeriod || current[0].Period != Daily || current[PerPeriod].Period != Weekly {
+		t.Fatalf("Current() = %+v, want the daily and weekly challenges", current)
+	}
+
+	// Fixed daily challenges
+	key, ends := periodKey(Daily, now, time.UTC)
+	tr.periods[Daily] = &period{Key: key, Games: []string{}, Progress: make(map[string][]int), Challenges: []Challenge{
+		{ID: key + "-1", Period: Daily, Kind: KindGrand, Target: 2, Ends: ends},
+		{ID: key + "-2", Period: Daily, Kind: KindDefend, Target: 1, Ends: ends},
+		{ID: key + "-3", Period: Daily, Kind: KindPlay, Target: 3, Ends: ends},
+	}}
+	var completed []string
+	for i, game := range []struct {
+		id   string
+		lose bool
+	}{{"g1", false}, {"g2", true}, {"g2", true}, {"g3", false}} {
+		completions, err := tr.Record(grandGame(t, game.id, game.lose), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range completions {
+			if c.Challenge.Period == Daily {
+				completed = append(completed, c.Login+" "+c.Challenge.ID)
+			}
+		}
+		if i == 2 && len(completions) != 0 {
+			t.Errorf("the game counted twice: %+v", completions)
+		}
+	}
+	want := []string{"ben 2025-03-14-2", "anna 2025-03-14-1", "anna 2025-03-14-3", "ben 2025-03-14-3"}
+	if !reflect.DeepEqual(completed, want) {
+		t.Errorf("completed %q, want %q", completed, want)
+	}
+
+	// The progress is kept in the file
+	tr, err = Open(path, "secret", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for login, want := range map[string][]int{"anna": {2, 0, 3}, "ben": {0, 1, 3}, "carl": {0, 0, 0}} {
+		progress, err := tr.Progress(login, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int
+		for _, p := range progress[:PerPeriod] {
+			got = append(got, p.Progress)
+			if p.Completed != (p.Progress == p.Target) {
+				t.Errorf("%s: %+v", login, p)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("daily progress of %s = %v, want %v", login, got, want)
+		}
+	}
+
+	// A new day starts new challenges, the week goes on
+	next, err := tr.Progress("anna", now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next[0].ID != "2025-03-15-1" || next[0].Progress != 0 {
+		t.Errorf("the next day starts with %+v", next[0])
+	}
+	var weekly int
+	for _, p := range next[PerPeriod:] {
+		weekly += p.Progress
+	}
+	if weekly == 0 {
+		t.Error("the weekly progress was reset")
+	}
+}
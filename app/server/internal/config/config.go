@@ -18,6 +18,21 @@ package config
 import (
 	"flag"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/async"
+	"github.com/mkloubert/freeskat-server/internal/discord"
+	"github.com/mkloubert/freeskat-server/internal/i18n"
+	"github.com/mkloubert/freeskat-server/internal/moderation"
+	"github.com/mkloubert/freeskat-server/internal/notify"
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/rating"
 )
 
 // Config holds the server configuration.
@@ -30,14 +45,191 @@ type Config struct {
 
 	// MaxConnections is the maximum number of concurrent connections.
 	MaxConnections int
+
+	// ReadTimeout, WriteTimeout and IdleTimeout are the timeouts of the client sessions.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+
+	// WaitingTimeout is the read timeout while a client waits on other players
+	// (observing a table, watching live standings).
+	WaitingTimeout time.Duration
+
+	// MaxLineLength is the maximum length of a client line in bytes (0 = no limit).
+	MaxLineLength int
+
+	// MaxViolations is the number of protocol violations of a client (lines too long or
+	// not UTF-8, illegal moves) after which it is disconnected (0 = never).
+	MaxViolations int
+
+	// MaxNameLength is the maximum length of a login name in characters (0 = no limit).
+	MaxNameLength int
+
+	// ProfanityFile is a file with words (one per line) masked in chat messages and
+	// rejected in login names ("" = no filter).
+	ProfanityFile string
+
+	// KeepAlive is the time after which quiet sessions get a keepalive (an empty line,
+	// a ping for WebSocket clients), e.g. for observers of slow games behind
+	// middleboxes dropping idle connections (0 = disabled).
+	KeepAlive time.Duration
+
+	// TCPKeepAlive is the TCP keepalive period of client connections (0 = system
+	// default, negative = disabled).
+	TCPKeepAlive time.Duration
+
+	// WSReadTimeout, WSWriteTimeout and WSIdleTimeout override the timeouts for
+	// WebSocket clients (0 = same as TCP clients).
+	WSReadTimeout  time.Duration
+	WSWriteTimeout time.Duration
+	WSIdleTimeout  time.Duration
+
+	// BotCount is the number of bot identities available to fill table seats.
+	BotCount int
+
+	// MaxBotGames is the maximum number of tables bots play at concurrently (0 = no limit).
+	MaxBotGames int
+
+	// BotDifficulty is the AI difficulty of the bots (beginner, club, strong).
+	BotDifficulty string
+
+	// ArchiveDir is the directory finished games are archived in ("" = no archive).
+	ArchiveDir string
+
+	// HTTPAddress is the address of the REST API and the WebSocket transport ("" = disabled).
+	HTTPAddress string
+
+	// DailySecret is the secret the deals of the day are derived from ("" = random per start).
+	DailySecret string
+
+	// DailyTimezone is the time zone in which the days of the daily deal start.
+	DailyTimezone string
+
+	// DailyClock is the thinking time of the player of a daily deal in seconds (0 = untimed).
+	DailyClock int
+
+	// DailyForfeit is the grace period after which the daily game of a player who
+	// disconnected during the trick play is forfeited (0 = archived unfinished at once).
+	DailyForfeit time.Duration
+
+	// Webhooks are the URLs finished games and series are posted to (comma-separated).
+	Webhooks string
+
+	// WebhookSecret signs the webhook requests ("" = unsigned).
+	WebhookSecret string
+
+	// PaymentURL is the URL of the payment service that collects the seat fees of
+	// tournament registrations ("" = fees are confirmed by the directors).
+	PaymentURL string
+
+	// PaymentSecret signs the payment requests and verifies the confirmations.
+	PaymentSecret string
+
+	// MistakeAnalysis is the minimum number of card points a card play must lose against
+	// best play to be reported by the post-game mistake analysis (0 = disabled).
+	MistakeAnalysis int
+
+	// Rating is the rating algorithm of the player ratings (elo, glicko2).
+	Rating string
+
+	// Bock are the triggers scheduling Bock and Ramsch rounds at tournament and league
+	// tables (comma-separated: split, grandhand, schneider; "" = disabled).
+	Bock string
+
+	// BockRounds are the rounds a trigger schedules (bock, ramsch or bock,ramsch).
+	BockRounds string
+
+	// Admins are the logins of the server admins (comma-separated).
+	Admins string
+
+	// AdminToken is the bearer token of the admin REST API ("" = disabled).
+	AdminToken string
+
+	// BackupDir is the directory the admin REST API writes archive backups to
+	// ("" = backups disabled).
+	BackupDir string
+
+	// DataDir is the directory of the server stores, e.g. the bans
+	// ("" = the data directory in the archive).
+	DataDir string
+
+	// DuplicateSet is the JSON file of a duplicate set whose deals stay secret until
+	// they are played at every table ("" = none).
+	DuplicateSet string
+
+	// DiscordWebhook is the URL of the Discord webhook events are relayed to ("" = disabled).
+	DiscordWebhook string
+
+	// DiscordEvents are the relayed events (comma-separated: tables, tournaments, results).
+	DiscordEvents string
+
+	// DiscordMinValue is the minimum game value of a won game relayed as notable result.
+	DiscordMinValue int
+
+	// Lang is the language of users whose client negotiates none (e.g. "en", "de").
+	Lang string
+
+	// MessagesDir is a directory of additional message catalogs ("<lang>.json", "" = none).
+	MessagesDir string
+
+	// WelcomeFile is a Go template of the welcome line ("" = "Welcome to ISS").
+	WelcomeFile string
+
+	// MOTDFile is a Go template of the message of the day sent after the login ("" = none).
+	MOTDFile string
+
+	// ModerationURL is the URL of an external chat moderation service ("" = word list only).
+	ModerationURL string
+
+	// ModerationSecret signs the requests to the moderation service ("" = unsigned).
+	ModerationSecret string
+
+	// ModerationPolicy maps the severities of chat messages to actions
+	// (e.g. "low=mask,medium=drop,high=mute").
+	ModerationPolicy string
+
+	// MuteDuration is the time a login muted by the chat moderation cannot chat.
+	MuteDuration time.Duration
+
+	// TurnNotifications is the default time a turn waits before the player is notified
+	// via the service of their notify settings (0 = no turn notifications).
+	TurnNotifications time.Duration
+
+	// AsyncMoveTime is the default time per move of correspondence games
+	// (0 = no correspondence games).
+	AsyncMoveTime time.Duration
+
+	// DiscordBotToken and DiscordChannel bridge the lobby chat with a Discord channel
+	// ("" = no chat bridge).
+	DiscordBotToken string
+	DiscordChannel  string
 }
 
 // DefaultConfig returns a Config with default values.
 func DefaultConfig() *Config {
 	return &Config{
-		Host:           "0.0.0.0",
-		Port:           7000,
-		MaxConnections: 100,
+		Host:             "0.0.0.0",
+		Port:             7000,
+		MaxConnections:   100,
+		ReadTimeout:      session.DefaultReadTimeout,
+		WriteTimeout:     session.DefaultWriteTimeout,
+		IdleTimeout:      session.DefaultIdleTimeout,
+		WaitingTimeout:   session.DefaultWaitingTimeout,
+		MaxLineLength:    session.DefaultMaxLineLength,
+		MaxViolations:    session.DefaultMaxViolations,
+		MaxNameLength:    sanitize.DefaultMaxName,
+		BotCount:         6,
+		MaxBotGames:      2,
+		BotDifficulty:    ai.DifficultyClub.String(),
+		DailyTimezone:    "UTC",
+		Rating:           string(rating.AlgorithmElo),
+		BockRounds:       string(tournament.ModeBock),
+		DiscordEvents:    discord.EventTournaments + "," + discord.EventResults,
+		DiscordMinValue:  96,
+		Lang:             i18n.English,
+		ModerationPolicy: moderation.DefaultPolicy,
+		MuteDuration:     moderation.DefaultMuteDuration,
+		AsyncMoveTime:    24 * time.Hour,
 	}
 }
 
@@ -48,6 +240,59 @@ func ParseFlags() *Config {
 	flag.StringVar(&cfg.Host, "host", cfg.Host, "Host address to bind to")
 	flag.IntVar(&cfg.Port, "port", cfg.Port, "TCP port to listen on")
 	flag.IntVar(&cfg.MaxConnections, "max-connections", cfg.MaxConnections, "Maximum concurrent connections")
+	flag.DurationVar(&cfg.ReadTimeout, "read-timeout", cfg.ReadTimeout, "Time a client may stay silent before it is disconnected (0 = no limit)")
+	flag.DurationVar(&cfg.WriteTimeout, "write-timeout", cfg.WriteTimeout, "Time a write to a client may take (0 = no limit)")
+	flag.DurationVar(&cfg.IdleTimeout, "idle-timeout", cfg.IdleTimeout, "Time without reads or writes after which a client is disconnected (0 = no limit)")
+	flag.DurationVar(&cfg.WaitingTimeout, "waiting-timeout", cfg.WaitingTimeout, "Read timeout while a client waits on other players, e.g. observing a table (0 = read timeout)")
+	flag.IntVar(&cfg.MaxLineLength, "max-line-length", cfg.MaxLineLength, "Maximum length of a client line in bytes (0 = no limit)")
+	flag.IntVar(&cfg.MaxViolations, "max-violations", cfg.MaxViolations, "Protocol violations of a client (malformed lines, illegal moves) before disconnecting (0 = never)")
+	flag.IntVar(&cfg.MaxNameLength, "max-name-length", cfg.MaxNameLength, "Maximum length of a login name in characters (0 = no limit)")
+	flag.StringVar(&cfg.ProfanityFile, "profanity-file", cfg.ProfanityFile, "File with words (one per line) masked in chat messages and rejected in login names")
+	flag.DurationVar(&cfg.KeepAlive, "keepalive", cfg.KeepAlive, "Send keepalives to sessions without output for this time, e.g. 60s (0 = disabled)")
+	flag.DurationVar(&cfg.TCPKeepAlive, "tcp-keepalive", cfg.TCPKeepAlive, "TCP keepalive period of client connections (0 = system default, negative = disabled)")
+	flag.DurationVar(&cfg.WSReadTimeout, "ws-read-timeout", cfg.WSReadTimeout, "Read timeout of WebSocket clients (0 = -read-timeout)")
+	flag.DurationVar(&cfg.WSWriteTimeout, "ws-write-timeout", cfg.WSWriteTimeout, "Write timeout of WebSocket clients (0 = -write-timeout)")
+	flag.DurationVar(&cfg.WSIdleTimeout, "ws-idle-timeout", cfg.WSIdleTimeout, "Idle timeout of WebSocket clients (0 = -idle-timeout)")
+	flag.IntVar(&cfg.BotCount, "bots", cfg.BotCount, "Number of bot identities available to fill table seats")
+	flag.IntVar(&cfg.MaxBotGames, "max-bot-games", cfg.MaxBotGames, "Maximum concurrent tables with bots (0 = no limit)")
+	flag.StringVar(&cfg.BotDifficulty, "bot-difficulty", cfg.BotDifficulty, "AI difficulty of the bots (beginner, club, strong)")
+	flag.StringVar(&cfg.ArchiveDir, "archive", cfg.ArchiveDir, "Directory to archive finished games in (empty = no archive)")
+	flag.StringVar(&cfg.HTTPAddress, "http", cfg.HTTPAddress, "Address of the REST API and the WebSocket transport, e.g. :8080 (empty = disabled)")
+	flag.StringVar(&cfg.DailySecret, "daily-secret", cfg.DailySecret, "Secret the deals of the day are derived from (empty = random per start)")
+	flag.StringVar(&cfg.DailyTimezone, "daily-timezone", cfg.DailyTimezone, "Time zone in which the days of the daily deal start")
+	flag.IntVar(&cfg.DailyClock, "daily-clock", cfg.DailyClock, "Thinking time of every player of a daily deal in seconds (0 = untimed)")
+	flag.DurationVar(&cfg.DailyForfeit, "daily-forfeit", cfg.DailyForfeit, "Time a player who disconnected during the trick play of a daily deal has to log in again before forfeiting the game (0 = archived unfinished at once)")
+	flag.StringVar(&cfg.Webhooks, "webhooks", cfg.Webhooks, "Comma-separated URLs finished games and series are posted to")
+	flag.StringVar(&cfg.WebhookSecret, "webhook-secret", cfg.WebhookSecret, "Secret to sign the webhook requests with (empty = unsigned)")
+	flag.StringVar(&cfg.PaymentURL, "payment-url", cfg.PaymentURL, "URL of the payment service collecting tournament seat fees (empty = confirmed by the directors)")
+	flag.StringVar(&cfg.PaymentSecret, "payment-secret", cfg.PaymentSecret, "Secret to sign the payment requests and verify the confirmations with")
+	flag.IntVar(&cfg.MistakeAnalysis, "mistake-analysis", cfg.MistakeAnalysis, "Report card plays losing at least this many card points after each game (0 = disabled)")
+	flag.StringVar(&cfg.Rating, "rating", cfg.Rating, "Rating algorithm of the player ratings (elo, glicko2)")
+	flag.StringVar(&cfg.Bock, "bock", cfg.Bock, "Comma-separated triggers of Bock/Ramsch rounds at tournament and league tables (split, grandhand, schneider, kontra, announced, hirsch)")
+	flag.StringVar(&cfg.BockRounds, "bock-rounds", cfg.BockRounds, "Rounds scheduled by the Bock triggers (bock, ramsch or bock,ramsch)")
+	flag.StringVar(&cfg.Admins, "admins", cfg.Admins, "Comma-separated logins of the server admins, e.g. to close rating seasons")
+	flag.StringVar(&cfg.AdminToken, "admin-token", cfg.AdminToken, "Bearer token of the admin REST API (empty = disabled)")
+	flag.StringVar(&cfg.BackupDir, "backup-dir", cfg.BackupDir, "Directory to write archive backups to (empty = backups disabled)")
+	flag.StringVar(&cfg.DataDir, "data-dir", cfg.DataDir, "Directory of the server stores such as bans and profiles (empty = data in the archive directory)")
+	flag.StringVar(&cfg.DuplicateSet, "duplicate-set", cfg.DuplicateSet, "JSON file of a duplicate set whose deals stay secret until played at every table (empty = none)")
+	flag.StringVar(&cfg.DiscordWebhook, "discord-webhook", cfg.DiscordWebhook, "URL of the Discord webhook events are relayed to (empty = disabled)")
+	flag.StringVar(&cfg.DiscordEvents, "discord-events", cfg.DiscordEvents, "Comma-separated events relayed to Discord (tables, tournaments, results)")
+	flag.IntVar(&cfg.DiscordMinValue, "discord-min-value", cfg.DiscordMinValue, "Minimum game value of won games relayed to Discord (Schwarz and Durchmarsch always)")
+	flag.StringVar(&cfg.DiscordBotToken, "discord-bot-token", cfg.DiscordBotToken, "Discord bot token to bridge the lobby chat with -discord-channel (empty = no chat bridge)")
+	flag.StringVar(&cfg.DiscordChannel, "discord-channel", cfg.DiscordChannel, "ID of the Discord channel bridged with the lobby chat")
+
+	flag.StringVar(&cfg.Lang, "lang", cfg.Lang, "Language of users whose client negotiates none (en, de or a language of -messages)")
+	flag.StringVar(&cfg.MessagesDir, "messages", cfg.MessagesDir, "Directory of additional message catalogs, one <lang>.json per language (empty = none)")
+
+	flag.StringVar(&cfg.WelcomeFile, "welcome", cfg.WelcomeFile, "File with a Go template of the welcome line, reloaded on SIGHUP (empty = \"Welcome to ISS\")")
+	flag.StringVar(&cfg.MOTDFile, "motd", cfg.MOTDFile, "File with a Go template of the message of the day sent after the login, reloaded on SIGHUP (empty = none)")
+
+	flag.StringVar(&cfg.ModerationURL, "moderation-url", cfg.ModerationURL, "URL of an external chat moderation service (empty = -profanity-file only)")
+	flag.StringVar(&cfg.ModerationSecret, "moderation-secret", cfg.ModerationSecret, "Secret to sign the requests to the moderation service with (empty = unsigned)")
+	flag.StringVar(&cfg.ModerationPolicy, "moderation-policy", cfg.ModerationPolicy, "Actions of the chat moderation per severity (low, medium, high = allow, mask, warn, drop, mute)")
+	flag.DurationVar(&cfg.MuteDuration, "mute-duration", cfg.MuteDuration, "Time a login muted by the chat moderation cannot chat")
+	flag.DurationVar(&cfg.AsyncMoveTime, "async-move-time", cfg.AsyncMoveTime, "Default time per move of correspondence games, in hours (0 = disabled, requires -archive)")
+	flag.DurationVar(&cfg.TurnNotifications, "turn-notifications", cfg.TurnNotifications, "Default time a turn waits before the player is notified via notify settings, e.g. 15m (0 = disabled, requires -archive)")
 
 	flag.Parse()
 
@@ -58,3 +303,198 @@ func ParseFlags() *Config {
 func (c *Config) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// WebhookURLs returns the configured webhook URLs.
+func (c *Config) WebhookURLs() []string {
+	var urls []string
+	for _, u := range strings.Split(c.Webhooks, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// Timeouts returns the session timeouts of TCP clients.
+func (c *Config) Timeouts() session.Timeouts {
+	return session.Timeouts{Read: c.ReadTimeout, Write: c.WriteTimeout, Idle: c.IdleTimeout, Waiting: c.WaitingTimeout}
+}
+
+// WebSocketTimeouts returns the session timeouts of WebSocket clients.
+func (c *Config) WebSocketTimeouts() session.Timeouts {
+	t := c.Timeouts()
+	if c.WSReadTimeout > 0 {
+		t.Read = c.WSReadTimeout
+	}
+	if c.WSWriteTimeout > 0 {
+		t.Write = c.WSWriteTimeout
+	}
+	if c.WSIdleTimeout > 0 {
+		t.Idle = c.WSIdleTimeout
+	}
+	return t
+}
+
+// DiscordEventList returns the events relayed to Discord.
+func (c *Config) DiscordEventList() []string {
+	var list []string
+	for _, e := range strings.Split(c.DiscordEvents, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// AdminLogins returns the configured admin logins.
+func (c *Config) AdminLogins() []string {
+	var logins []string
+	for _, login := range strings.Split(c.Admins, ",") {
+		if login = strings.TrimSpace(login); login != "" {
+			logins = append(logins, login)
+		}
+	}
+	return logins
+}
+
+// StoreDir returns the directory of the server stores.
+func (c *Config) StoreDir() string {
+	if c.DataDir != "" {
+		return c.DataDir
+	}
+	return filepath.Join(c.ArchiveDir, "data")
+}
+
+// Validate checks the configuration for invalid values.
+func (c *Config) Validate() error {
+	if c.BotCount < 0 {
+		return fmt.Errorf("invalid bot count: %d", c.BotCount)
+	}
+	if c.MaxBotGames < 0 {
+		return fmt.Errorf("invalid max bot games: %d", c.MaxBotGames)
+	}
+	if c.MaxLineLength < 0 {
+		return fmt.Errorf("invalid max line length: %d", c.MaxLineLength)
+	}
+	if c.MaxViolations < 0 {
+		return fmt.Errorf("invalid max violations: %d", c.MaxViolations)
+	}
+	if c.MaxNameLength < 0 {
+		return fmt.Errorf("invalid max name length: %d", c.MaxNameLength)
+	}
+	for _, timeout := range []time.Duration{c.ReadTimeout, c.WriteTimeout, c.IdleTimeout, c.WaitingTimeout, c.KeepAlive, c.WSReadTimeout, c.WSWriteTimeout, c.WSIdleTimeout} {
+		if timeout < 0 {
+			return fmt.Errorf("invalid timeout: %s", timeout)
+		}
+	}
+	if _, err := ai.ParseDifficulty(c.BotDifficulty); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(c.DailyTimezone); err != nil {
+		return fmt.Errorf("invalid daily time zone: %w", err)
+	}
+	if c.DailyClock < 0 {
+		return fmt.Errorf("invalid daily clock: %d", c.DailyClock)
+	}
+	if c.DailyForfeit < 0 {
+		return fmt.Errorf("invalid daily forfeit grace period: %s", c.DailyForfeit)
+	}
+	if c.HTTPAddress != "" && c.ArchiveDir == "" {
+		return fmt.Errorf("the REST API requires a game archive (-archive)")
+	}
+	for _, u := range c.WebhookURLs() {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid webhook URL: %s", u)
+		}
+	}
+	if len(c.WebhookURLs()) > 0 && c.ArchiveDir == "" {
+		return fmt.Errorf("webhooks require a game archive (-archive)")
+	}
+	if c.PaymentURL != "" {
+		if parsed, err := url.Parse(c.PaymentURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid payment URL: %s", c.PaymentURL)
+		}
+		if c.PaymentSecret == "" {
+			return fmt.Errorf("the payment service requires a secret (-payment-secret)")
+		}
+		if c.HTTPAddress == "" {
+			return fmt.Errorf("the payment service requires the REST API (-http)")
+		}
+	}
+	if c.MistakeAnalysis < 0 {
+		return fmt.Errorf("invalid mistake analysis threshold: %d", c.MistakeAnalysis)
+	}
+	if c.MistakeAnalysis > 0 && c.ArchiveDir == "" {
+		return fmt.Errorf("the mistake analysis requires a game archive (-archive)")
+	}
+	if c.AdminToken != "" && c.HTTPAddress == "" {
+		return fmt.Errorf("the admin API requires the REST API (-http)")
+	}
+	if c.DataDir != "" && c.ArchiveDir == "" {
+		return fmt.Errorf("the data directory requires a game archive (-archive)")
+	}
+	if c.DuplicateSet != "" && c.ArchiveDir == "" {
+		return fmt.Errorf("the duplicate set requires a game archive (-archive)")
+	}
+	if c.BackupDir != "" {
+		if c.AdminToken == "" {
+			return fmt.Errorf("backups require the admin API (-admin-token)")
+		}
+		if rel, err := filepath.Rel(c.ArchiveDir, c.BackupDir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("the backup directory must not be inside the game archive")
+		}
+	}
+	if _, err := rating.ParseAlgorithm(c.Rating); err != nil {
+		return err
+	}
+	if c.ModerationURL != "" {
+		if parsed, err := url.Parse(c.ModerationURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid moderation URL: %s", c.ModerationURL)
+		}
+	}
+	if _, err := moderation.ParsePolicy(c.ModerationPolicy); err != nil {
+		return err
+	}
+	if c.MuteDuration <= 0 {
+		return fmt.Errorf("invalid mute duration: %s", c.MuteDuration)
+	}
+	if c.AsyncMoveTime != 0 && (c.AsyncMoveTime < async.MinMoveTime || c.AsyncMoveTime > async.MaxMoveTime || c.AsyncMoveTime%time.Hour != 0) {
+		return fmt.Errorf("invalid correspondence move time: %s (want whole hours from %s to %s)", c.AsyncMoveTime, async.MinMoveTime, async.MaxMoveTime)
+	}
+	if c.TurnNotifications != 0 && (c.TurnNotifications < notify.MinAfter || c.TurnNotifications > notify.MaxAfter) {
+		return fmt.Errorf("invalid turn notification delay: %s (want %s to %s)", c.TurnNotifications, notify.MinAfter, notify.MaxAfter)
+	}
+	if _, err := tournament.ParseRules(c.Bock, c.BockRounds); err != nil {
+		return err
+	}
+	return c.validateDiscord()
+}
+
+// validateDiscord checks the Discord settings.
+func (c *Config) validateDiscord() error {
+	if c.DiscordWebhook == "" {
+		if c.DiscordBotToken != "" || c.DiscordChannel != "" {
+			return fmt.Errorf("the Discord chat bridge requires a webhook (-discord-webhook)")
+		}
+		return nil
+	}
+	if parsed, err := url.Parse(c.DiscordWebhook); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid Discord webhook URL: %s", c.DiscordWebhook)
+	}
+	for _, e := range c.DiscordEventList() {
+		known := false
+		for _, name := range discord.AllEvents {
+			known = known || e == name
+		}
+		if !known {
+			return fmt.Errorf("invalid Discord event: %s (use %s)", e, strings.Join(discord.AllEvents, ", "))
+		}
+	}
+	if c.DiscordMinValue < 0 {
+		return fmt.Errorf("invalid Discord minimum game value: %d", c.DiscordMinValue)
+	}
+	if (c.DiscordBotToken == "") != (c.DiscordChannel == "") {
+		return fmt.Errorf("the Discord chat bridge requires a bot token and a channel")
+	}
+	return nil
+}
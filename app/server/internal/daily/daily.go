@@ -0,0 +1,331 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package daily provides the deal of the day: every player who opts in plays the
+// same deal at the same position against bots, and a leaderboard per day compares
+// the outcomes.
+package daily
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// DateLayout is the format of deal dates.
+const DateLayout = "2006-01-02"
+
+// gamePrefix is the prefix of the archive IDs of daily games ("daily-<date>-...").
+const gamePrefix = "daily-"
+
+// BotNames are the names of the bots playing the other two positions.
+var BotNames = [2]string{"dailybot1", "dailybot2"}
+
+// BotDifficulty is the difficulty of the bots seeded with the deal.
+const BotDifficulty = ai.DifficultyStrong
+
+// Deal is the deal of a day.
+type Deal struct {
+	// Date is the day of the deal (DateLayout)
+	Date string
+	// Position is the position all players play
+	Position skat.Player
+	// Shuffle is the seeded shuffle of the deal
+	Shuffle skat.ShuffleInfo
+	// Hands and Skat are the dealt cards
+	Hands map[skat.Player]*skat.Hand
+	Skat  *skat.Hand
+	// Opponents play the other two positions (nil = strong bots seeded with the deal)
+	Opponents map[skat.Player]ai.AIPlayer
+}
+
+// Schedule generates the daily deals. Deals are derived from a secret and the date,
+// so they are the same after a restart and cannot be predicted without the secret.
+type Schedule struct {
+	secret   string
+	location *time.Location
+	// fixed is dealt every day instead of the derived deals (nil = derived)
+	fixed *Deal
+}
+
+// NewSchedule creates a schedule whose days start at midnight in the given location.
+func NewSchedule(secret string, location *time.Location) *Schedule {
+	return &Schedule{secret: secret, location: location}
+}
+
+// NewFixedSchedule creates a schedule dealing the same deal every day, e.g. to test
+// clients against scripted opponents. The Date of the deal is ignored.
+func NewFixedSchedule(deal Deal, location *time.Location) *Schedule {
+	return &Schedule{location: location, fixed: &deal}
+}
+
+// Date returns the deal date at time t.
+func (s *Schedule) Date(t time.Time) string {
+	return t.In(s.location).Format(DateLayout)
+}
+
+// NextStart returns the start of the day following time t.
+func (s *Schedule) NextStart(t time.Time) time.Time {
+	local := t.In(s.location)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, s.location)
+}
+
+// Deal returns the deal of a date. Misdeals are voided, logged and dealt again with
+// a seed derived for the redeal, so all players still get the same deal; a misdealt
+// fixed deal is replaced by the derived deal at the same position.
+func (s *Schedule) Deal(date string) (*Deal, error) {
+	if !ValidDate(date) {
+		return nil, fmt.Errorf("invalid date: %s", date)
+	}
+	if s.fixed != nil {
+		deal := *s.fixed
+		deal.Date = date
+		err := skat.ValidateDeal(deal.Hands, deal.Skat)
+		if err == nil {
+			return &deal, nil
+		}
+		log.Printf("Misdeal of the fixed deal of %s: %v, redealing", date, err)
+		derived, err := s.derive(date)
+		if err != nil {
+			return nil, err
+		}
+		deal.Shuffle, deal.Hands, deal.Skat = derived.Shuffle, derived.Hands, derived.Skat
+		return &deal, nil
+	}
+	return s.derive(date)
+}
+
+// derive deals the cards of a date from the secret. Redeals after a misdeal shuffle
+// with a seed derived from the date and the number of the redeal.
+func (s *Schedule) derive(date string) (*Deal, error) {
+	sum := sha256.Sum256([]byte(s.secret + "\x00" + date))
+
+	var shuffle skat.ShuffleInfo
+	hands, skatCards, err := skat.DealUntilValid(func(attempt int) (map[skat.Player]*skat.Hand, *skat.Hand, error) {
+		seedSum := sum
+		if attempt > 0 {
+			seedSum = sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%d", s.secret, date, attempt))
+		}
+		deck := skat.NewDeck()
+		shuffle = deck.ShuffleSeed(int64(binary.BigEndian.Uint64(seedSum[:8]) >> 1))
+		return skat.DealCards(deck)
+	}, func(attempt int, err error) {
+		log.Printf("Misdeal of the daily deal of %s (deal %d): %v, redealing", date, attempt+1, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Deal{
+		Date:     date,
+		Position: skat.AllPlayers[sum[8]%3],
+		Shuffle:  shuffle,
+		Hands:    hands,
+		Skat:     skatCards,
+	}, nil
+}
+
+// Today returns the deal of the current day.
+func (s *Schedule) Today() (*Deal, error) {
+	return s.Deal(s.Date(time.Now()))
+}
+
+// Run calls onDeal with the current deal and again at the start of every day
+// until the context is canceled.
+func (s *Schedule) Run(ctx context.Context, onDeal func(*Deal)) {
+	for {
+		deal, err := s.Today()
+		if err != nil {
+			log.Printf("[daily] Failed to generate the deal of the day: %v", err)
+		} else {
+			onDeal(deal)
+		}
+
+		timer := time.NewTimer(time.Until(s.NextStart(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Record returns the dealt record of a player's daily game.
+func (d *Deal) Record(login string, startedAt time.Time) *skat.GameRecord {
+	record := &skat.GameRecord{
+		ID:        fmt.Sprintf("%s%d", GamePrefix(d.Date), startedAt.UnixNano()),
+		StartedAt: startedAt,
+		Players:   make(map[skat.Player]string),
+		Hands:     d.Hands,
+		Skat:      d.Skat,
+		Shuffle:   d.Shuffle,
+		Engine:    skat.EngineVersion,
+	}
+	bot := 0
+	for _, p := range skat.AllPlayers {
+		if p == d.Position {
+			record.Players[p] = login
+			continue
+		}
+		record.Players[p] = BotNames[bot]
+		bot++
+	}
+	return record
+}
+
+// Bots returns the bots of a player's daily game: the Opponents if set, otherwise bots
+// seeded with the deal, so they answer the same moves the same way for every player.
+func (d *Deal) Bots() map[skat.Player]ai.AIPlayer {
+	if d.Opponents != nil {
+		return d.Opponents
+	}
+	bots := make(map[skat.Player]ai.AIPlayer)
+	for _, p := range skat.AllPlayers {
+		if p != d.Position {
+			bots[p] = ai.New(BotDifficulty, rand.New(rand.NewSource(d.Shuffle.Seed+int64(p.Index()))))
+		}
+	}
+	return bots
+}
+
+// Difficulties returns the difficulties of the bots of Bots (nil for Opponents,
+// whose difficulty is unknown).
+func (d *Deal) Difficulties() map[skat.Player]ai.Difficulty {
+	if d.Opponents != nil {
+		return nil
+	}
+	difficulties := make(map[skat.Player]ai.Difficulty)
+	for _, p := range skat.AllPlayers {
+		if p != d.Position {
+			difficulties[p] = BotDifficulty
+		}
+	}
+	return difficulties
+}
+
+// GamePrefix returns the archive ID prefix of the daily games of a date.
+func GamePrefix(date string) string {
+	return gamePrefix + date + "-"
+}
+
+// Hidden returns true if the archive ID is a daily game of a day that is not over
+// at time now. Such games must not be shown to other players, since they reveal the deal.
+func (s *Schedule) Hidden(id string, now time.Time) bool {
+	date, ok := GameDate(id)
+	return ok && date >= s.Date(now)
+}
+
+// GameDate returns the date of a daily game from its archive ID.
+func GameDate(id string) (string, bool) {
+	if !strings.HasPrefix(id, gamePrefix) || len(id) < len(gamePrefix)+len(DateLayout) {
+		return "", false
+	}
+	date := id[len(gamePrefix) : len(gamePrefix)+len(DateLayout)]
+	return date, ValidDate(date)
+}
+
+// IsBot returns true if the name is reserved for the daily bots.
+func IsBot(name string) bool {
+	return name == BotNames[0] || name == BotNames[1]
+}
+
+// Entry is a leaderboard entry.
+type Entry struct {
+	Rank   int    `json:"rank"`
+	Player string `json:"player"`
+	Game   string `json:"game"`
+	// Declarer is true if the player declared the game
+	Declarer bool   `json:"declarer"`
+	Contract string `json:"contract,omitempty"`
+	// Score is the game score if the player declared, otherwise 0
+	Score int `json:"score"`
+	// Points are the card points of the player's side
+	Points int `json:"points"`
+}
+
+// Leaderboard ranks the daily games of a date by score, then by the card points of the
+// player's side. Earlier games rank first on ties. Only the first game of a player counts.
+func Leaderboard(records []*skat.GameRecord) ([]Entry, error) {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].StartedAt.Before(records[j].StartedAt) })
+
+	var entries []Entry
+	seen := make(map[string]bool)
+	for _, record := range records {
+		position, ok := PlayerPosition(record)
+		if !ok || seen[record.Players[position]] {
+			continue
+		}
+		game, err := record.Replay(nil)
+		if err != nil {
+			return nil, fmt.Errorf("game %s: %w", record.ID, err)
+		}
+		if game.State != skat.StateGameOver {
+			continue
+		}
+		seen[record.Players[position]] = true
+
+		entry := Entry{Player: record.Players[position], Game: record.ID}
+		if game.Contract != nil {
+			entry.Contract = game.Contract.Code()
+		}
+		if result := game.Result; result != nil {
+			entry.Declarer = result.Declarer == position
+			if entry.Declarer {
+				entry.Score, entry.Points = result.Score, result.DeclarerPoints
+			} else {
+				entry.Points = 120 - result.DeclarerPoints
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].Points > entries[j].Points
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Score == entries[i-1].Score && entries[i].Points == entries[i-1].Points {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries, nil
+}
+
+// PlayerPosition returns the position of the human player of a daily game.
+func PlayerPosition(record *skat.GameRecord) (skat.Player, bool) {
+	for _, p := range skat.AllPlayers {
+		if name := record.Players[p]; name != "" && !IsBot(name) {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// ValidDate returns true if the date has the DateLayout format.
+func ValidDate(date string) bool {
+	_, err := time.Parse(DateLayout, date)
+	return err == nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daily
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestScheduleDeal(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	s := NewSchedule("secret", berlin)
+
+	first, err := s.Deal("2025-03-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The same deal after a restart
+	again, _ := NewSchedule("secret", berlin).Deal("2025-03-01")
+	if !reflect.DeepEqual(first, again) {
+		t.Error("the deal of a date changed with a new schedule")
+	}
+	if err := skat.ValidateDeal(first.Hands, first.Skat); err != nil {
+		t.Errorf("invalid deal: %v", err)
+	}
+	next, _ := s.Deal("2025-03-02")
+	other, _ := NewSchedule("other", berlin).Deal("2025-03-01")
+	if reflect.DeepEqual(first.Hands, next.Hands) || reflect.DeepEqual(first.Hands, other.Hands) {
+		t.Error("the deal does not depend on the date and the secret")
+	}
+	if _, err := s.Deal("2025-3-1"); err == nil {
+		t.Error("Deal() accepted an invalid date")
+	}
+
+	// Days start at midnight in Berlin
+	evening := time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC)
+	if date := s.Date(evening); date != "2025-03-02" {
+		t.Errorf("Date() = %s, want 2025-03-02", date)
+	}
+	if start := s.NextStart(evening); !start.Equal(time.Date(2025, 3, 2, 23, 0, 0, 0, time.UTC)) {
+		t.Errorf("NextStart() = %v", start)
+	}
+}
+
+func TestFixedSchedule(t *testing.T) {
+	deal, _ := NewSchedule("secret", time.UTC).Deal("2025-03-01")
+	s := NewFixedSchedule(*deal, time.UTC)
+	fixed, err := s.Deal("2025-04-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixed.Date != "2025-04-01" || !reflect.DeepEqual(fixed.Hands, deal.Hands) {
+		t.Error("the fixed deal is not dealt every day")
+	}
+
+	// A misdealt fixed deal is replaced at the same position
+	misdeal := *deal
+	misdeal.Skat = skat.NewHand()
+	redealt, err := NewFixedSchedule(misdeal, time.UTC).Deal("2025-04-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redealt.Position != deal.Position || skat.ValidateDeal(redealt.Hands, redealt.Skat) != nil {
+		t.Errorf("redealt at %s, want a valid deal at %s", redealt.Position, deal.Position)
+	}
+}
+
+func TestRecordAndHidden(t *testing.T) {
+	s := NewSchedule("secret", time.UTC)
+	deal, _ := s.Deal("2025-03-01")
+	record := deal.Record("anna", time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC))
+
+	if !strings.HasPrefix(record.ID, "daily-2025-03-01-") || record.Players[deal.Position] != "anna" {
+		t.Errorf("Record() = %s with %v", record.ID, record.Players)
+	}
+	if position, ok := PlayerPosition(record); !ok || position != deal.Position {
+		t.Errorf("PlayerPosition() = %s, %v", position, ok)
+	}
+	if len(deal.Bots()) != 2 || deal.Bots()[deal.Position] != nil || len(deal.Difficulties()) != 2 {
+		t.Error("the bots do not play the other positions")
+	}
+	if date, ok := GameDate(record.ID); !ok || date != "2025-03-01" {
+		t.Errorf("GameDate() = %s, %v", date, ok)
+	}
+
+	// Games are hidden until the day is over
+	if !s.Hidden(record.ID, time.Date(2025, 3, 1, 23, 59, 0, 0, time.UTC)) {
+		t.Error("the game is visible on its day")
+	}
+	if s.Hidden(record.ID, time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)) || s.Hidden("g1", time.Now()) {
+		t.Error("games are hidden after the day or without a date")
+	}
+}
+
+// dailyGame returns a Grand Hand record (won, or lost as a Null) with the player at
+// the position and the daily bots at the others.
+func dailyGame(t *testing.T, player string, position skat.Player, minute int, lose bool) *skat.GameRecord {
+	t.Helper()
+	start := time.Date(2025, 3, 1, 18, minute, 0, 0, time.UTC)
+	record := recordtest.Grand(t, fmt.Sprintf("daily-2025-03-01-%d", minute), start, lose)
+	bot := 0
+	for _, p := range skat.AllPlayers {
+		if p == position {
+			record.Players[p] = player
+			continue
+		}
+		record.Players[p] = BotNames[bot]
+		bot++
+	}
+	return record
+}
+
+func TestLeaderboard(t *testing.T) {
+	deal, _ := NewSchedule("secret", time.UTC).Deal("2025-03-01")
+	records := []*skat.GameRecord{
+		dailyGame(t, "emil", skat.Forehand, 5, false),
+		dailyGame(t, "ben", skat.Forehand, 1, true),
+		dailyGame(t, "anna", skat.Forehand, 0, false),
+		dailyGame(t, "carl", skat.Middlehand, 2, false),
+		// Only the first game of a player counts, unfinished games do not
+		dailyGame(t, "anna", skat.Forehand, 3, true),
+		deal.Record("dora", time.Date(2025, 3, 1, 18, 4, 0, 0, time.UTC)),
+	}
+	entries, err := Leaderboard(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%d %s %v %s %d", e.Rank, e.Player, e.Declarer, e.Contract, e.Score))
+	}
+	want := []string{"1 anna true GH 192", "1 emil true GH 192", "3 carl false GH 0", "4 ben true NH -70"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Leaderboard() = %q, want %q", got, want)
+	}
+	if carl := entries[2]; carl.Points != 120-entries[0].Points {
+		t.Errorf("carl has %d points, want the defenders' %d", carl.Points, 120-entries[0].Points)
+	}
+}
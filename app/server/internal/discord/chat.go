@@ -0,0 +1,137 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// channelMessage is a message of the chat channel as returned by the Discord API.
+type channelMessage struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	Author  struct {
+		Username string `json:"username"`
+		Bot      bool   `json:"bot"`
+	} `json:"author"`
+	// WebhookID is set for messages posted through webhooks, e.g. by the bridge itself
+	WebhookID string `json:"webhook_id"`
+}
+
+// ReadChat reads the new messages of the chat channel until ctx is done and sends them
+// to the lobby as "<name>@discord". Messages of bots and webhooks are skipped, so the
+// relayed lobby messages do not come back. Without a chat bridge it returns at once.
+func (b *Bridge) ReadChat(ctx context.Context, yell func(sender, text string)) {
+	if b.config.BotToken == "" || b.config.Channel == "" {
+		return
+	}
+
+	after := ""
+	ticker := time.NewTicker(b.poll)
+	defer ticker.Stop()
+	for {
+		messages, err := b.channelMessages(ctx, after)
+		switch {
+		case err != nil:
+			log.Printf("[discord] Failed to read channel %s: %v", b.config.Channel, err)
+		case after == "":
+			// Start after the latest message, earlier ones are not relayed
+			after = "0"
+			if len(messages) > 0 {
+				after = messages[len(messages)-1].ID
+			}
+		default:
+			for _, m := range messages {
+				after = m.ID
+				if m.Author.Bot || m.WebhookID != "" {
+					continue
+				}
+				if text := chatText(m.Content); text != "" {
+					yell(chatSender(m.Author.Username), text)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// channelMessages returns the messages of the channel after the message ID, oldest
+// first. Without ID only the latest message is returned.
+func (b *Bridge) channelMessages(ctx context.Context, after string) ([]channelMessage, error) {
+	url := fmt.Sprintf("%s/channels/%s/messages?limit=", b.api, b.config.Channel)
+	if after == "" {
+		url += "1"
+	} else {
+		url += "50&after=" + after
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bot "+b.config.BotToken)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+	var messages []channelMessage
+	if err := json.NewDecoder(resp.Body).Decode(&messages); err != nil {
+		return nil, err
+	}
+	// Snowflake IDs increase with time
+	sort.Slice(messages, func(i, j int) bool {
+		a, _ := strconv.ParseUint(messages[i].ID, 10, 64)
+		b, _ := strconv.ParseUint(messages[j].ID, 10, 64)
+		return a < b
+	})
+	return messages, nil
+}
+
+// chatSender returns the lobby name of a Discord user: a single token ending in
+// "@discord", so it cannot be mistaken for a login.
+func chatSender(username string) string {
+	name := strings.Join(strings.Fields(username), "_")
+	if name == "" {
+		name = "unknown"
+	}
+	return name + "@discord"
+}
+
+// chatText returns the text of a channel message as a single line of at most
+// maxChatLength characters.
+func chatText(content string) string {
+	text := []rune(strings.Join(strings.Fields(content), " "))
+	if len(text) > maxChatLength {
+		text = append(text[:maxChatLength-1], '…')
+	}
+	return string(text)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discord
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadChat(t *testing.T) {
+	requests := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/channels/c1/messages" || r.Header.Get("Authorization") != "Bot s3cret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		requests <- r.URL.RawQuery
+		switch r.URL.Query().Get("after") {
+		case "":
+			fmt.Fprint(w, `[{"id":"10","content":"before the start","author":{"username":"emil"}}]`)
+		case "10":
+			// Newest first, with a bot and a relayed lobby message
+			fmt.Fprint(w, `[
+				{"id":"14","content":"  see you\n tomorrow ","author":{"username":"Dora K"}},
+				{"id":"13","content":"anna: hello","author":{"username":"bridge"},"webhook_id":"99"},
+				{"id":"12","content":"beep","author":{"username":"helper","bot":true}},
+				{"id":"11","content":"who plays?","author":{"username":"fritz"}}
+			]`)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	b := New(Config{BotToken: "s3cret", Channel: "c1"})
+	defer b.Close()
+	b.api = server.URL
+	b.poll = 10 * time.Millisecond
+
+	var yells []string
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.ReadChat(ctx, func(sender, text string) { yells = append(yells, sender+" "+text) })
+	}()
+	want := []string{"limit=1", "limit=50&after=10", "limit=50&after=14"}
+	for _, query := range want {
+		select {
+		case got := <-requests:
+			if got != query {
+				t.Errorf("request %q, want %q", got, query)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no request %q", query)
+		}
+	}
+	cancel()
+	<-done
+
+	if !reflect.DeepEqual(yells, []string{"fritz@discord who plays?", "Dora_K@discord see you tomorrow"}) {
+		t.Errorf("yelled %q", yells)
+	}
+}
+
+func TestReadChatDisabled(t *testing.T) {
+	b := New(Config{Channel: "c1"})
+	defer b.Close()
+	// Returns at once without a bot token
+	b.ReadChat(context.Background(), func(sender, text string) { t.Errorf("yelled %s %s", sender, text) })
+}
+
+func TestChatText(t *testing.T) {
+	if got := chatSender(" \t"); got != "unknown@discord" {
+		t.Errorf("chatSender() = %s", got)
+	}
+	long := chatText(strings.Repeat("ä", maxChatLength+5))
+	if runes := []rune(long); len(runes) != maxChatLength || runes[maxChatLength-1] != '…' {
+		t.Errorf("chatText() has %d characters", len(runes))
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package discord relays server events (tables, tournaments, notable results) to a
+// Discord webhook and optionally bridges the lobby chat with a Discord channel: lobby
+// messages are posted through the webhook, channel messages are read with a bot token
+// and sent to the lobby.
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/events"
+	"github.com/mkloubert/freeskat-server/pkg/render"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Relayed events.
+const (
+	EventTables      = "tables"
+	EventTournaments = "tournaments"
+	EventResults     = "results"
+)
+
+// AllEvents are the events that can be relayed.
+var AllEvents = []string{EventTables, EventTournaments, EventResults}
+
+const (
+	// queueSize is the number of messages waiting for delivery; further messages are dropped
+	queueSize = 256
+	// attempts is the number of delivery attempts per message
+	attempts = 3
+	// timeout is the timeout of a single request
+	timeout = 10 * time.Second
+	// pollInterval is the interval of reading new messages of the chat channel
+	pollInterval = 5 * time.Second
+	// maxChatLength is the maximum length of a chat message relayed to the lobby
+	maxChatLength = 300
+	// apiURL is the base URL of the Discord API
+	apiURL = "https://discord.com/api/v10"
+)
+
+// Config configures a bridge.
+type Config struct {
+	// WebhookURL is the URL of the Discord webhook messages are posted to
+	WebhookURL string
+	// Events are the relayed events (see AllEvents)
+	Events []string
+	// MinValue is the minimum game value of a won game to be a notable result;
+	// Schwarz games and Durchmarsch are always notable
+	MinValue int
+	// BotToken and Channel bridge the lobby chat with a channel ("" = no chat bridge)
+	BotToken string
+	Channel  string
+}
+
+// message is a message of the webhook.
+type message struct {
+	Content  string `json:"content"`
+	Username string `json:"username,omitempty"`
+	// AllowedMentions keeps chat messages from pinging users or roles
+	AllowedMentions struct {
+		Parse []string `json:"parse"`
+	} `json:"allowed_mentions"`
+}
+
+// Bridge delivers messages to the webhook in the background.
+type Bridge struct {
+	config Config
+	events map[string]bool
+	client *http.Client
+	api    string
+	poll   time.Duration
+	queue  chan *message
+	done   chan struct{}
+	once   sync.Once
+}
+
+// New creates a bridge.
+func New(config Config) *Bridge {
+	b := &Bridge{
+		config: config,
+		events: make(map[string]bool),
+		client: &http.Client{Timeout: timeout},
+		api:    apiURL,
+		poll:   pollInterval,
+		queue:  make(chan *message, queueSize),
+		done:   make(chan struct{}),
+	}
+	for _, e := range config.Events {
+		b.events[e] = true
+	}
+	go b.run()
+	return b
+}
+
+// Event relays a game event: started tables (EventTables) and notable results
+// (EventResults). Other events are ignored.
+func (b *Bridge) Event(event events.Event) {
+	switch e := event.(type) {
+	case events.GameStarted:
+		if b.events[EventTables] {
+			b.post("", fmt.Sprintf("New table **%s**: %s", e.Table, players(e.Players)))
+		}
+	case events.GameFinished:
+		if text, ok := b.notable(e); ok && b.events[EventResults] {
+			b.post("", text)
+		}
+	}
+}
+
+// notable returns the message of a notable result.
+func (b *Bridge) notable(e events.GameFinished) (string, bool) {
+	if r := e.Ramsch; r != nil {
+		if !r.Durchmarsch || r.DurchmarschPlayer == nil {
+			return "", false
+		}
+		return fmt.Sprintf("**%s** made a Durchmarsch in Ramsch at %s!", e.Record.Players[*r.DurchmarschPlayer], e.Table), true
+	}
+	r := e.Result
+	if r == nil || !r.DeclarerWon || (r.GameValue < b.config.MinValue && !r.Schwarz) {
+		return "", false
+	}
+	contract := r.Contract
+	text := fmt.Sprintf("**%s** won %s for %d points at %s", e.Record.Players[r.Declarer], render.Contract(&contract), r.Score, e.Table)
+	if r.Schwarz {
+		text += " (Schwarz!)"
+	}
+	return text, true
+}
+
+// SeriesStarted relays the start of a tournament series (EventTournaments).
+func (b *Bridge) SeriesStarted(name string, series tournament.Series) {
+	if !b.events[EventTournaments] {
+		return
+	}
+	if series.Number == 1 {
+		b.post("", fmt.Sprintf("Tournament **%s** started with %d tables", name, len(series.Tables)))
+		return
+	}
+	b.post("", fmt.Sprintf("Tournament **%s**: series %d started", name, series.Number))
+}
+
+// Chat posts a lobby chat message of a player (only with a chat bridge).
+func (b *Bridge) Chat(login, text string) {
+	if b.config.BotToken == "" {
+		return
+	}
+	b.post(login, text)
+}
+
+// Close stops the bridge after the queued messages have been delivered.
+func (b *Bridge) Close() {
+	b.once.Do(func() { close(b.queue) })
+	<-b.done
+}
+
+// players returns the player names by position.
+func players(names map[skat.Player]string) string {
+	list := make([]string, 0, len(names))
+	for _, p := range skat.AllPlayers {
+		list = append(list, names[p])
+	}
+	return strings.Join(list, ", ")
+}
+
+// post queues a message.
+func (b *Bridge) post(username, content string) {
+	m := &message{Content: content, Username: username}
+	m.AllowedMentions.Parse = []string{}
+	select {
+	case b.queue <- m:
+	default:
+		log.Printf("[discord] Queue full, dropping message")
+	}
+}
+
+// run delivers the queued messages.
+func (b *Bridge) run() {
+	defer close(b.done)
+
+	for m := range b.queue {
+		body, err := json.Marshal(m)
+		if err != nil {
+			log.Printf("[discord] Failed to encode message: %v", err)
+			continue
+		}
+		if err := b.deliver(body); err != nil {
+			log.Printf("[discord] Failed to deliver message: %v", err)
+		}
+	}
+}
+
+// deliver posts a message to the webhook, retrying with increasing delays or the
+// delay Discord asks for when rate limited.
+func (b *Bridge) deliver(body []byte) error {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		var wait time.Duration
+		if wait, err = b.postWebhook(body); err == nil {
+			return nil
+		}
+		if wait == 0 {
+			wait = time.Duration(attempt+1) * time.Second
+		}
+		time.Sleep(wait)
+	}
+	return err
+}
+
+// postWebhook posts a message once and returns the delay asked for on rate limits.
+func (b *Bridge) postWebhook(body []byte) (time.Duration, error) {
+	resp, err := b.client.Post(b.config.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusTooManyRequests {
+		seconds, _ := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64)
+		return time.Duration(seconds * float64(time.Second)), fmt.Errorf("status %s", resp.Status)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("status %s", resp.Status)
+	}
+	return 0, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/events"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// webhook collects the messages posted to a test webhook. The first request is rate
+// limited.
+type webhook struct {
+	mu       sync.Mutex
+	limited  bool
+	messages []message
+}
+
+func (wh *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	if !wh.limited {
+		wh.limited = true
+		w.Header().Set("Retry-After", "0.01")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	var m message
+	json.NewDecoder(r.Body).Decode(&m)
+	wh.messages = append(wh.messages, m)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// finished returns the finished event of the Grand Hand of anna, won or lost as Null.
+func finished(t *testing.T, lose bool) events.GameFinished {
+	t.Helper()
+	record := recordtest.Grand(t, "g1", recordtest.Start, lose)
+	game, err := record.Replay(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events.GameFinished{Table: "t1", Record: record, Result: game.Result}
+}
+
+func TestBridge(t *testing.T) {
+	wh := &webhook{}
+	server := httptest.NewServer(wh)
+	defer server.Close()
+
+	b := New(Config{WebhookURL: server.URL, Events: []string{EventTables, EventResults}, MinValue: 100})
+	b.Event(events.GameStarted{Table: "t1", Players: recordtest.Names()})
+	b.Event(finished(t, false))
+	// Lost games, Ramsch games without Durchmarsch and tournaments are not relayed
+	b.Event(finished(t, true))
+	b.Event(events.GameFinished{Table: "t1", Record: recordtest.Grand(t, "g2", recordtest.Start, false), Ramsch: &skat.RamschResult{}})
+	b.SeriesStarted("cup", tournament.Series{Number: 1})
+	// The chat is bridged with a bot token only
+	b.Chat("anna", "hello")
+	b.Close()
+
+	want := []string{"New table **t1**: anna, ben, carl", "**anna** won Grand Hand for 192 points at t1 (Schwarz!)"}
+	var got []string
+	for _, m := range wh.messages {
+		got = append(got, m.Content)
+		if m.AllowedMentions.Parse == nil || len(m.AllowedMentions.Parse) != 0 {
+			t.Errorf("message %q allows mentions", m.Content)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("posted %q, want %q", got, want)
+	}
+}
+
+func TestNotable(t *testing.T) {
+	b := &Bridge{config: Config{MinValue: 200}}
+	// Schwarz games are notable below the minimum value
+	if text, ok := b.notable(finished(t, false)); !ok || text == "" {
+		t.Error("the Schwarz game is not notable")
+	}
+	game := finished(t, false)
+	result := *game.Result
+	result.Schwarz = false
+	game.Result = &result
+	if _, ok := b.notable(game); ok {
+		t.Error("a game below the minimum value is notable")
+	}
+
+	ben := skat.Middlehand
+	durchmarsch := events.GameFinished{Table: "t2", Record: game.Record, Ramsch: &skat.RamschResult{Durchmarsch: true, DurchmarschPlayer: &ben}}
+	if text, ok := b.notable(durchmarsch); !ok || text != "**ben** made a Durchmarsch in Ramsch at t2!" {
+		t.Errorf("notable(Durchmarsch) = %q, %v", text, ok)
+	}
+}
+
+func TestSeriesStarted(t *testing.T) {
+	wh := &webhook{limited: true}
+	server := httptest.NewServer(wh)
+	defer server.Close()
+
+	b := New(Config{WebhookURL: server.URL, Events: []string{EventTournaments}, BotToken: "bot"})
+	b.SeriesStarted("cup", tournament.Series{Number: 1, Tables: make([]tournament.Table, 4)})
+	b.SeriesStarted("cup", tournament.Series{Number: 2})
+	b.Chat("anna", "good luck")
+	b.Close()
+
+	if len(wh.messages) != 3 {
+		t.Fatalf("posted %d messages, want 3", len(wh.messages))
+	}
+	if wh.messages[0].Content != "Tournament **cup** started with 4 tables" || wh.messages[1].Content != "Tournament **cup**: series 2 started" {
+		t.Errorf("posted %q and %q", wh.messages[0].Content, wh.messages[1].Content)
+	}
+	if chat := wh.messages[2]; chat.Username != "anna" || chat.Content != "good luck" {
+		t.Errorf("chat message %+v", chat)
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+// german are the German texts of the server.
+var german = map[string]string{
+	// Connection, login and commands
+	"Malformed input: %v":                                          "Ungültige Eingabe: %v",
+	"Too many protocol violations, disconnecting":                  "Zu viele Protokollverstöße, Verbindung wird getrennt",
+	"Unknown command: %s":                                          "Unbekannter Befehl: %s",
+	"Invalid login format":                                         "Ungültiges Login-Format",
+	"Login name %q is not allowed: %v":                             "Der Login-Name %q ist nicht erlaubt: %v",
+	"Login name '%s' is reserved":                                  "Der Login-Name '%s' ist reserviert",
+	"Login name '%s' is banned":                                    "Der Login-Name '%s' ist gesperrt",
+	"Login required":                                               "Anmeldung erforderlich",
+	"Invalid client format":                                        "Ungültiges Client-Format",
+	"Client already identified":                                    "Der Client hat sich bereits identifiziert",
+	"Invalid lang format":                                          "Ungültiges lang-Format",
+	"Unknown language: %s":                                         "Unbekannte Sprache: %s",
+	"Table %s was closed by an operator":                           "Tisch %s wurde von einem Betreiber geschlossen",
+	"Server restarts, game %s is adjourned until you log in again": "Der Server startet neu, Spiel %s ist bis zu deiner nächsten Anmeldung unterbrochen",
+	"Resuming adjourned game %s":                                   "Unterbrochenes Spiel %s wird fortgesetzt",
+	"Adjourned game failed":                                        "Unterbrochenes Spiel fehlgeschlagen",
+
+	// Games, replays and tables
+	"No game archive available":                 "Kein Spielarchiv verfügbar",
+	"Invalid replay format":                     "Ungültiges replay-Format",
+	"Unknown game: %s":                          "Unbekanntes Spiel: %s",
+	"Game %s cannot be replayed":                "Spiel %s kann nicht wiedergegeben werden",
+	"Invalid table command":                     "Ungültiger table-Befehl",
+	"Unknown table: %s":                         "Unbekannter Tisch: %s",
+	"Invalid replay action: %s":                 "Ungültige replay-Aktion: %s",
+	"Invalid table action: %s":                  "Ungültige Tisch-Aktion: %s",
+	"Invalid move":                              "Ungültiger Zug",
+	"Invalid move: %s":                          "Ungültiger Zug: %s",
+	"Already playing at table %s":               "Du spielst bereits an Tisch %s",
+	"No observers at table %s":                  "Keine Zuschauer an Tisch %s",
+	"Invalid history format":                    "Ungültiges history-Format",
+	"Invalid history count: %s":                 "Ungültige Anzahl: %s",
+	"Game history not available":                "Spielverlauf nicht verfügbar",
+	"Game %s cannot be changed":                 "Spiel %s kann nicht geändert werden",
+	"Invalid comment format":                    "Ungültiges comment-Format",
+	"Game %s cannot be commented":               "Spiel %s kann nicht kommentiert werden",
+	"Statistics not available":                  "Statistik nicht verfügbar",
+	"No games of %s":                            "Keine Spiele von %s",
+	"Invalid count: %s":                         "Ungültige Anzahl: %s",
+	"Ratings not available":                     "Wertungen nicht verfügbar",
+	"Analysis of game %s:":                      "Analyse von Spiel %s:",
+	"%d card points":                            "%d Augen",
+	"loses the game":                            "verliert das Spiel",
+	"move %d: %s played %s, %s was better (%s)": "Zug %d: %s spielte %s, %s war besser (%s)",
+	"no clear mistakes of %s":                   "keine klaren Fehler von %s",
+
+	// Observers and chat
+	"Invalid observe format":                               "Ungültiges observe-Format",
+	"Already observing table %s of %s":                     "Du schaust bereits Tisch %s von %s zu",
+	"No table of %s":                                       "Kein Tisch von %s",
+	"Table %s cannot be observed":                          "Tisch %s kann nicht beobachtet werden",
+	"Table %s can only be observed after playing its deal": "Tisch %s kann erst nach dem Spielen seines Blatts beobachtet werden",
+	"Invalid observer action: %s":                          "Ungültige Zuschauer-Aktion: %s",
+	"Invalid tell format":                                  "Ungültiges tell-Format",
+	"Too slow to observe table %s, observation ended":      "Zu langsam, um Tisch %s zuzuschauen, Beobachtung beendet",
+	"Empty message":                                        "Leere Nachricht",
+	"Message too long (max %d characters)":                 "Nachricht zu lang (höchstens %d Zeichen)",
+	"Message rejected: %v":                                 "Nachricht abgelehnt: %v",
+	"Message rejected: %s":                                 "Nachricht abgelehnt: %s",
+	"Message rejected, you are muted until %s: %s":         "Nachricht abgelehnt, du bist stummgeschaltet bis %s: %s",
+	"You are muted until %s":                               "Du bist stummgeschaltet bis %s",
+	"Warning: %s":                                          "Warnung: %s",
+	"inappropriate words":                                  "unangemessene Wörter",
+
+	// Daily deal
+	"No daily deal available":         "Kein Tagesspiel verfügbar",
+	"Invalid daily action: %s":        "Ungültige daily-Aktion: %s",
+	"Daily deal of %s already played": "Das Tagesspiel vom %s wurde bereits gespielt",
+	"Daily game failed":               "Tagesspiel fehlgeschlagen",
+	"Invalid date: %s":                "Ungültiges Datum: %s",
+	"Leaderboard not available":       "Bestenliste nicht verfügbar",
+
+	// Practice games
+	"Practice game failed":                        "Übungsspiel fehlgeschlagen",
+	"Invalid practice format":                     "Ungültiges practice-Format",
+	"Invalid difficulty: %s":                      "Ungültige Spielstärke: %s",
+	"Hints are only available at practice tables": "Tipps gibt es nur an Übungstischen",
+	"No hint available: %v":                       "Kein Tipp verfügbar: %v",
+	"No bot available":                            "Kein Bot verfügbar",
+	"Too many bot games, try again later":         "Zu viele Spiele mit Bots, versuche es später noch einmal",
+	"Seats can only be taken at practice tables":  "Plätze können nur an Übungstischen übernommen werden",
+	"No free seat at table %s":                    "Kein freier Platz an Tisch %s",
+	"%s left the game":                            "%s hat das Spiel verlassen",
+	"Game failed":                                 "Spiel fehlgeschlagen",
+
+	// Duplicate sets
+	"No duplicate set available":           "Keine Duplicate-Serie verfügbar",
+	"Invalid duplicate action: %s":         "Ungültige duplicate-Aktion: %s",
+	"You do not play in duplicate set %s":  "Du spielst nicht in der Duplicate-Serie %s",
+	"All deals of duplicate set %s played": "Alle Spiele der Duplicate-Serie %s sind gespielt",
+	"Duplicate game failed":                "Duplicate-Spiel fehlgeschlagen",
+	"%s dealt deal %d of duplicate set %s, take your seat with 'duplicate play'":         "%s hat Spiel %d der Duplicate-Serie %s gegeben, übernimm deinen Platz mit 'duplicate play'",
+	"Deal %d of duplicate set %s is already dealt, take your seat with 'duplicate play'": "Spiel %d der Duplicate-Serie %s ist bereits gegeben, übernimm deinen Platz mit 'duplicate play'",
+
+	// Tournaments
+	"No tournaments available":                              "Keine Turniere verfügbar",
+	"Invalid tournament format":                             "Ungültiges tournament-Format",
+	"Invalid tournament action: %s":                         "Ungültige Turnier-Aktion: %s",
+	"Invalid number of series: %s":                          "Ungültige Anzahl Serien: %s",
+	"Cannot create tournament: %v":                          "Turnier kann nicht angelegt werden: %v",
+	"Tournament %s created":                                 "Turnier %s angelegt",
+	"Invalid series: %s":                                    "Ungültige Serie: %s",
+	"Invalid stakes: %s":                                    "Ungültiger Einsatz: %s",
+	"Cannot set stakes: %v":                                 "Einsatz kann nicht gesetzt werden: %v",
+	"Stakes of tournament %s set to %d cents per point":     "Einsatz von Turnier %s auf %d Cent pro Punkt gesetzt",
+	"Cannot set tie-breaks: %v":                             "Feinwertungen können nicht gesetzt werden: %v",
+	"Tie-breaks of tournament %s set to %s":                 "Feinwertungen von Turnier %s auf %s gesetzt",
+	"Already watching tournament %s":                        "Du verfolgst Turnier %s bereits",
+	"Not watching tournament %s":                            "Du verfolgst Turnier %s nicht",
+	"Stopped watching tournament %s":                        "Turnier %s wird nicht mehr verfolgt",
+	"Cannot set team: %v":                                   "Mannschaft kann nicht gesetzt werden: %v",
+	"Team %s of tournament %s set to %d players":            "Mannschaft %s von Turnier %s auf %d Spieler gesetzt",
+	"Invalid number of players: %s":                         "Ungültige Anzahl Spieler: %s",
+	"Cannot set counted players: %v":                        "Gewertete Spieler können nicht gesetzt werden: %v",
+	"Counted players per team of tournament %s set to %d":   "Gewertete Spieler pro Mannschaft von Turnier %s auf %d gesetzt",
+	"Cannot set stages: %v":                                 "Turnierphasen können nicht gesetzt werden: %v",
+	"Tournament %s has %d stages":                           "Turnier %s hat %d Phasen",
+	"Cannot set rule profile: %v":                           "Regelprofil kann nicht gesetzt werden: %v",
+	"Cannot set pairing: %v":                                "Auslosung kann nicht gesetzt werden: %v",
+	"Pairing of tournament %s set to %s":                    "Auslosung von Turnier %s auf %s gesetzt",
+	"Cannot start series: %v":                               "Serie kann nicht gestartet werden: %v",
+	"Standings not available":                               "Tabelle nicht verfügbar",
+	"Live standings not available":                          "Live-Tabelle nicht verfügbar",
+	"Tournament %s finished":                                "Turnier %s beendet",
+	"Series %d of tournament %s not started":                "Serie %d von Turnier %s nicht gestartet",
+	"Tables not available":                                  "Tische nicht verfügbar",
+	"Result not available":                                  "Ergebnis nicht verfügbar",
+	"Cannot %s: %v":                                         "%s nicht möglich: %v",
+	"Tournament %s: %s done":                                "Turnier %s: %s erledigt",
+	"Only the tournament directors can see the audit trail": "Nur die Turnierleitung kann das Protokoll einsehen",
+	"Cannot register: %v":                                   "Anmeldung nicht möglich: %v",
+	"Registration for tournament %s: %s":                    "Anmeldung zu Turnier %s: %s",
+	"Cannot unregister: %v":                                 "Abmeldung nicht möglich: %v",
+	"Unregistered from tournament %s":                       "Von Turnier %s abgemeldet",
+	"Invalid number: %s":                                    "Ungültige Zahl: %s",
+	"Cannot set capacity: %v":                               "Teilnehmerzahl kann nicht gesetzt werden: %v",
+	"Capacity of tournament %s set to %d players":           "Teilnehmerzahl von Turnier %s auf %d Spieler gesetzt",
+	"Cannot set fee: %v":                                    "Startgeld kann nicht gesetzt werden: %v",
+	"Seat fee of tournament %s set to %d cents":             "Startgeld von Turnier %s auf %d Cent gesetzt",
+	"Cannot confirm payment: %v":                            "Zahlung kann nicht bestätigt werden: %v",
+	"Registration of %s for tournament %s declined":         "Anmeldung von %s zu Turnier %s abgelehnt",
+	"Registration of %s for tournament %s confirmed":        "Anmeldung von %s zu Turnier %s bestätigt",
+
+	// Leagues and seasons
+	"No leagues available":                    "Keine Ligen verfügbar",
+	"Invalid league format":                   "Ungültiges league-Format",
+	"Cannot create league: %v":                "Liga kann nicht angelegt werden: %v",
+	"League %s created":                       "Liga %s angelegt",
+	"Cannot add player: %v":                   "Spieler kann nicht hinzugefügt werden: %v",
+	"%s added to league %s":                   "%s zu Liga %s hinzugefügt",
+	"Cannot remove player: %v":                "Spieler kann nicht entfernt werden: %v",
+	"%s removed from league %s":               "%s aus Liga %s entfernt",
+	"Invalid time: %s":                        "Ungültige Zeit: %s",
+	"Cannot schedule round: %v":               "Spieltag kann nicht angesetzt werden: %v",
+	"Round %d of league %s scheduled for %s":  "Spieltag %d von Liga %s angesetzt für %s",
+	"Invalid round: %s":                       "Ungültiger Spieltag: %s",
+	"Cannot reschedule round: %v":             "Spieltag kann nicht verlegt werden: %v",
+	"Round %d of league %s rescheduled to %s": "Spieltag %d von Liga %s verlegt auf %s",
+	"Invalid league action: %s":               "Ungültige Liga-Aktion: %s",
+	"Rounds not available":                    "Spieltage nicht verfügbar",
+	"Season table not available":              "Saisontabelle nicht verfügbar",
+	"No seasons available":                    "Keine Saisons verfügbar",
+	"Invalid season format":                   "Ungültiges season-Format",
+	"Invalid season: %s":                      "Ungültige Saison: %s",
+	"Only admins can close a season":          "Nur Administratoren können eine Saison abschließen",
+	"Invalid reset: %s":                       "Ungültiger Reset: %s",
+	"Invalid season action: %s":               "Ungültige Saison-Aktion: %s",
+	"Cannot close season: %v":                 "Saison kann nicht abgeschlossen werden: %v",
+	"Season %d closed, season %d started":     "Saison %d abgeschlossen, Saison %d begonnen",
+
+	// Narration of game events
+	"Invalid narrate format":             "Ungültiges narrate-Format",
+	"You are %s at table %s.":            "Du bist %s an Tisch %s.",
+	"Your cards: %s.":                    "Deine Karten: %s.",
+	"%s bids %d.":                        "%s reizt %d.",
+	"%s holds.":                          "%s hält.",
+	"%s passes.":                         "%s passt.",
+	"%s picks up the skat.":              "%s nimmt den Skat auf.",
+	"You discard %s.":                    "Du drückst %s.",
+	"%s declares %s.":                    "%s sagt %s an.",
+	"%s leads the first trick.":          "%s spielt zum ersten Stich aus.",
+	"%s plays %s.":                       "%s spielt %s.",
+	"%s takes the trick with %d points.": "%s nimmt den Stich mit %d Augen.",
+	"%s takes the trick with %d points and leads the next one.": "%s nimmt den Stich mit %d Augen und spielt aus.",
+	"%s announces Kontra.":                                   "%s sagt Kontra.",
+	"%s announces Re.":                                       "%s sagt Re.",
+	"%s claims the remaining tricks.":                        "%s beansprucht die restlichen Stiche.",
+	"%s concedes the remaining tricks.":                      "%s gibt die restlichen Stiche ab.",
+	"%s accepts.":                                            "%s nimmt an.",
+	"%s rejects.":                                            "%s lehnt ab.",
+	"%s leaves the game.":                                    "%s verlässt das Spiel.",
+	"%s replays the deal.":                                   "%s lässt neu geben.",
+	"All players passed, the deal is thrown in.":             "Alle haben gepasst, das Spiel wird eingepasst.",
+	"%s takes all tricks (Durchmarsch) and scores %d.":       "%s macht alle Stiche (Durchmarsch) und erhält %d.",
+	"%s loses the Ramsch with %d card points and scores %d.": "%s verliert den Ramsch mit %d Augen und erhält %d Punkte.",
+	"%s wins %s with %d card points and scores %d.":          "%s gewinnt %s mit %d Augen und erhält %d Punkte.",
+	"%s loses %s, overbid at %d, and scores %d.":             "%s verliert %s, überreizt bei %d, und erhält %d Punkte.",
+	"%s loses %s with %d card points and scores %d.":         "%s verliert %s mit %d Augen und erhält %d Punkte.",
+	"The game ends without a result.":                        "Das Spiel endet ohne Ergebnis.",
+	"%s (you)":                                               "%s (du)",
+	"%s (declarer)":                                          "%s (Alleinspieler)",
+	"the %s of %s":                                           "%[2]s %[1]s",
+
+	// Quick chat
+	"Invalid quick format":          "Ungültiges quick-Format",
+	"Unknown quick chat phrase: %s": "Unbekannte Schnellnachricht: %s",
+	"Hello":                         "Hallo",
+	"Good luck":                     "Viel Glück",
+	"Good game":                     "Gutes Spiel",
+	"Well played":                   "Gut gespielt",
+	"Nice hand":                     "Schönes Blatt",
+	"Sorry, misclick":               "Entschuldigung, verklickt",
+	"Oops":                          "Hoppla",
+	"Thanks":                        "Danke",
+	"Please play faster":            "Bitte etwas schneller spielen",
+	"I have to go, bye":             "Ich muss los, tschüss",
+
+	// Player profiles
+	"No profiles available":  "Keine Profile verfügbar",
+	"Invalid profile format": "Ungültiges profile-Format",
+	"Cannot set profile: %v": "Profil kann nicht gesetzt werden: %v",
+
+	// Challenges
+	"No challenges available":                  "Keine Herausforderungen verfügbar",
+	"Invalid challenge format":                 "Ungültiges challenge-Format",
+	"Play %d games":                            "Spiele %d Spiele",
+	"Win %d games as declarer":                 "Gewinne %d Spiele als Alleinspieler",
+	"Win %d suit games as declarer":            "Gewinne %d Farbspiele als Alleinspieler",
+	"Win %d Grand games as declarer":           "Gewinne %d Grand-Spiele als Alleinspieler",
+	"Win %d Null games as declarer":            "Gewinne %d Null-Spiele als Alleinspieler",
+	"Win %d Hand games as declarer":            "Gewinne %d Handspiele als Alleinspieler",
+	"Win %d games Schneider as declarer":       "Gewinne %d Spiele Schneider als Alleinspieler",
+	"Defeat the declarer %d times as defender": "Bringe den Alleinspieler %d-mal als Gegenspieler zu Fall",
+
+	// Turn notifications
+	"No notifications available":             "Keine Benachrichtigungen verfügbar",
+	"Invalid notify format":                  "Ungültiges notify-Format",
+	"Invalid duration: %s":                   "Ungültige Dauer: %s",
+	"Cannot set notifications: %v":           "Benachrichtigungen können nicht gesetzt werden: %v",
+	"Cannot send notification: %v":           "Benachrichtigung kann nicht gesendet werden: %v",
+	"This is a test notification":            "Dies ist eine Testbenachrichtigung",
+	"Test notification sent":                 "Testbenachrichtigung gesendet",
+	"It is your turn in game %s at table %s": "Du bist am Zug in Spiel %s an Tisch %s",
+
+	// Correspondence games
+	"No correspondence games available":         "Keine Fernpartien verfügbar",
+	"Invalid async format":                      "Ungültiges async-Format",
+	"Invalid async action: %s":                  "Ungültige async-Aktion: %s",
+	"Invalid move time: %s":                     "Ungültige Bedenkzeit: %s",
+	"Cannot open game: %v":                      "Partie kann nicht eröffnet werden: %v",
+	"Cannot join game %s: %v":                   "Der Partie %s kann nicht beigetreten werden: %v",
+	"Cannot leave game %s: %v":                  "Partie %s kann nicht verlassen werden: %v",
+	"Left game %s":                              "Partie %s verlassen",
+	"Game %s has not started":                   "Partie %s hat noch nicht begonnen",
+	"Game %s not available":                     "Partie %s nicht verfügbar",
+	"It is your turn in correspondence game %s": "Du bist am Zug in Fernpartie %s",
+	"Move time of %s in game %s is over":        "Die Bedenkzeit von %s in Partie %s ist abgelaufen",
+	"Cannot set vacation: %v":                   "Urlaub kann nicht gesetzt werden: %v",
+	"Game %s ends without result":               "Partie %s endet ohne Ergebnis",
+
+	// Errors of the game and the stores
+	"not your turn":                                 "du bist nicht am Zug",
+	"card not in hand":                              "die Karte ist nicht auf der Hand",
+	"illegal card play":                             "unzulässige Karte",
+	"exactly one card must be played":               "es muss genau eine Karte gespielt werden",
+	"exactly two different cards must be discarded": "es müssen genau zwei verschiedene Karten gedrückt werden",
+	"invalid contract":                              "ungültige Spielansage",
+	"game cannot reach the bid":                     "das Spiel kann den Reizwert nicht erreichen",
+	"only a Grand Hand can replace the Ramsch":      "nur ein Grand Hand kann den Ramsch ersetzen",
+	"too late to announce":                          "zu spät für die Ansage",
+	"bid too low to announce kontra":                "zu niedrig gereizt für Kontra",
+	"kontra and re are not allowed":                 "Kontra und Re sind nicht erlaubt",
+	"claim pending":                                 "ein Anspruch ist offen",
+	"game is over":                                  "das Spiel ist vorbei",
+	"no hint for this decision":                     "kein Tipp für diese Entscheidung",
+	"no claim pending":                              "kein Anspruch offen",
+	"forfeit pending":                               "eine Aufgabe ist offen",
+	"no bot available":                              "kein Bot verfügbar",
+	"concurrent bot game limit reached":             "Höchstzahl gleichzeitiger Bot-Spiele erreicht",
+	"name contains invalid characters":              "der Name enthält ungültige Zeichen",
+	"text is empty":                                 "der Text ist leer",
+	"text is too long":                              "der Text ist zu lang",
+	"text rejected":                                 "der Text wurde abgelehnt",
+	"notifications not set":                         "keine Benachrichtigungen gesetzt",
+	"the game is full":                              "die Partie ist voll",
+	"the game has started":                          "die Partie hat begonnen",
+	"not playing in this game":                      "nicht in dieser Partie",
+	"not on vacation":                               "nicht im Urlaub",
+	"profile not found":                             "Profil nicht gefunden",
+	"tournament not found":                          "Turnier nicht gefunden",
+	"tournament already exists":                     "das Turnier existiert bereits",
+	"tournament is finished":                        "das Turnier ist beendet",
+	"the tournament has started":                    "das Turnier hat begonnen",
+	"only the tournament directors can do this":     "das darf nur die Turnierleitung",
+	"already registered":                            "bereits angemeldet",
+	"not registered":                                "nicht angemeldet",
+	"registration is closed":                        "die Anmeldung ist geschlossen",
+	"already a director":                            "bereits in der Turnierleitung",
+	"table is paused":                               "der Tisch ist angehalten",
+	"table is already paused":                       "der Tisch ist bereits angehalten",
+	"table is not paused":                           "der Tisch ist nicht angehalten",
+	"the adjustment must not be 0":                  "die Korrektur darf nicht 0 sein",
+	"league not found":                              "Liga nicht gefunden",
+	"league already exists":                         "die Liga existiert bereits",
+	"only the league admin can do this":             "das darf nur die Ligaleitung",
+	"season not found":                              "Saison nicht gefunden",
+	"the season has started":                        "die Saison hat begonnen",
+	"the season has already been closed":            "die Saison wurde bereits abgeschlossen",
+	"game not found":                                "Spiel nicht gefunden",
+}
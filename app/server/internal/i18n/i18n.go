@@ -0,0 +1,163 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n translates the texts the server sends to users: error messages, notices
+// and game texts. The English format strings of the server are the keys of the
+// message catalogs; a missing translation falls back to English.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// English is the language of the message keys, which needs no catalog.
+const English = "en"
+
+// German is the German language.
+const German = "de"
+
+// validLang matches the language codes of the catalogs (ISO 639-1 or 639-2).
+var validLang = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// shipped are the catalogs compiled into the server by language.
+var shipped = map[string]map[string]string{
+	German: german,
+}
+
+// Catalog holds the translations of the server texts by language.
+// It is safe for concurrent use.
+type Catalog struct {
+	mu        sync.RWMutex
+	languages map[string]map[string]string
+}
+
+// New creates a catalog with English, the shipped languages and the languages of the
+// card and game names of package skat.
+func New() *Catalog {
+	c := &Catalog{languages: map[string]map[string]string{English: {}}}
+	for _, lang := range skat.NameLanguages() {
+		if lang != English {
+			c.Add(lang, skat.Names(lang))
+		}
+	}
+	for lang, messages := range shipped {
+		c.Add(lang, messages)
+	}
+	return c
+}
+
+// Add adds translations of a language, replacing existing ones of the same messages.
+func (c *Catalog) Add(lang string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	catalog := c.languages[lang]
+	if catalog == nil {
+		catalog = make(map[string]string, len(messages))
+		c.languages[lang] = catalog
+	}
+	for key, text := range messages {
+		catalog[key] = text
+	}
+}
+
+// LoadDir adds the catalogs of a directory: one JSON object "<lang>.json" per language
+// that maps the English texts to their translations.
+func (c *Catalog) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		lang := strings.TrimSuffix(filepath.Base(file), ".json")
+		if !validLang.MatchString(lang) {
+			return fmt.Errorf("invalid language of catalog %s", file)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("catalog %s: %w", file, err)
+		}
+		c.Add(lang, messages)
+	}
+	return nil
+}
+
+// Has reports whether the catalog has a language.
+func (c *Catalog) Has(lang string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.languages[lang]
+	return ok
+}
+
+// Languages returns the languages of the catalog in sorted order.
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	langs := make([]string, 0, len(c.languages))
+	for lang := range c.languages {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Translate returns the translation of an English text (the text itself if there is none).
+func (c *Catalog) Translate(lang, text string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if translated, ok := c.languages[lang][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// Sprintf formats the translation of an English format string.
+func (c *Catalog) Sprintf(lang, format string, args ...any) string {
+	return fmt.Sprintf(c.Translate(lang, format), args...)
+}
+
+// Negotiate returns the language of the catalog the client prefers most in an
+// Accept-Language header ("de-DE,de;q=0.9,en;q=0.8"), or "" if it has none of them.
+func (c *Catalog) Negotiate(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ && c.Has(lang) {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonfile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	for _, want := range []map[string]int{{"a": 1}, {"b": 2}} {
+		if err := Write(path, want); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]int
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got["a"] != want["a"] || got["b"] != want["b"] {
+			t.Errorf("file = %v, want %v", got, want)
+		}
+		if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+			t.Errorf("temporary file left: %v", err)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "store.json")
+
+	if err := Write(path, func() {}); err == nil {
+		t.Error("Write of a function succeeded")
+	}
+	if err := Write(filepath.Join(dir, "missing", "store.json"), 1); err == nil {
+		t.Error("Write into a missing directory succeeded")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file written despite error: %v", err)
+	}
+}
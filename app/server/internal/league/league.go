@@ -0,0 +1,598 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package league manages Skat leagues: a fixed roster of players (optionally in teams)
+// meets on scheduled match days. At the scheduled time the tables of a round are
+// reserved, and a season table sums the Seeger-Fabian results of all rounds.
+//
+// The games of a league are archived games whose IDs follow the scheme
+// "<name>-r<round>-t<table>-g<deal>", so the season table is always computed from the archive.
+package league
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// TimeLayout is the format of the scheduled times of rounds.
+const TimeLayout = time.RFC3339
+
+// fileExt is the extension of the league files.
+const fileExt = ".json"
+
+var (
+	// ErrNotFound is returned for unknown leagues.
+	ErrNotFound = errors.New("league not found")
+	// ErrExists is returned when creating a league whose name is taken.
+	ErrExists = errors.New("league already exists")
+	// ErrNotAdmin is returned when someone else than the admin manages a league.
+	ErrNotAdmin = errors.New("only the league admin can do this")
+	// ErrStarted is returned when changing the roster after the first round started.
+	ErrStarted = errors.New("the season has started")
+)
+
+// League is a league: its roster and the scheduled rounds.
+type League struct {
+	Name string `json:"name"`
+	// Admin is the login who created the league and manages it
+	Admin     string    `json:"admin"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Players are the roster in order of addition
+	Players []Member `json:"players"`
+	// Rounds are the match days in order of their number
+	Rounds []Round `json:"rounds"`
+	// Bock are the rules scheduling Bock and Ramsch rounds at the tables
+	Bock tournament.Rules `json:"bock"`
+}
+
+// Member is a player of the roster.
+type Member struct {
+	Name string `json:"name"`
+	// Team is the team of the player ("" in individual leagues)
+	Team string `json:"team,omitempty"`
+}
+
+// Round is a match day.
+type Round struct {
+	// Number is the round number (starting at 1)
+	Number int `json:"number"`
+	// Start is the scheduled time
+	Start time.Time `json:"start"`
+	// Started is true once the tables have been reserved
+	Started bool `json:"started"`
+	// Cancelled is set with the reason if the tables could not be reserved
+	Cancelled string `json:"cancelled,omitempty"`
+	// Tables are the reserved tables (empty until the round starts)
+	Tables []tournament.Table `json:"tables"`
+}
+
+// GameID returns the archive ID of a deal at a table of a round (all starting at 1).
+func (l *League) GameID(round, table, deal int) string {
+	return fmt.Sprintf("%s-r%d-t%d-g%d", l.Name, round, table, deal)
+}
+
+// GamePrefix returns the prefix of the archive IDs of the league's games.
+func (l *League) GamePrefix() string {
+	return l.Name + "-r"
+}
+
+// Teams returns the team names in order of their first player.
+func (l *League) Teams() []string {
+	var teams []string
+	seen := make(map[string]bool)
+	for _, m := range l.Players {
+		if m.Team != "" && !seen[m.Team] {
+			seen[m.Team] = true
+			teams = append(teams, m.Team)
+		}
+	}
+	return teams
+}
+
+// started returns true if a round has started, which fixes the roster.
+func (l *League) started() bool {
+	for _, r := range l.Rounds {
+		if r.Started {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGameID returns round, table and deal of a game ID of the league.
+func (l *League) parseGameID(id string) (int, int, int, bool) {
+	if !strings.HasPrefix(id, l.GamePrefix()) {
+		return 0, 0, 0, false
+	}
+	var round, table, deal int
+	if _, err := fmt.Sscanf(id[len(l.Name):], "-r%d-t%d-g%d", &round, &table, &deal); err != nil {
+		return 0, 0, 0, false
+	}
+	if l.GameID(round, table, deal) != id {
+		return 0, 0, 0, false
+	}
+	return round, table, deal, true
+}
+
+// seating returns the roster in the order the players are seated at a round: the
+// players of each team are shuffled and the teams interleaved, so team mates sit at
+// different tables whenever possible.
+func (l *League) seating() []string {
+	var teams [][]string
+	index := make(map[string]int)
+	for _, m := range l.Players {
+		key := m.Team
+		if key == "" {
+			// Every player of an individual league is a team of one
+			key = "player:" + m.Name
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(teams)
+			index[key] = i
+			teams = append(teams, nil)
+		}
+		teams[i] = append(teams[i], m.Name)
+	}
+	rand.Shuffle(len(teams), func(i, j int) {
+		teams[i], teams[j] = teams[j], teams[i]
+	})
+	for _, team := range teams {
+		rand.Shuffle(len(team), func(i, j int) {
+			team[i], team[j] = team[j], team[i]
+		})
+	}
+
+	var players []string
+	for len(players) < len(l.Players) {
+		for i, team := range teams {
+			if len(team) > 0 {
+				players = append(players, team[0])
+				teams[i] = team[1:]
+			}
+		}
+	}
+	return players
+}
+
+// TeamStanding is the result of a team in the season table.
+type TeamStanding struct {
+	Rank int    `json:"rank"`
+	Team string `json:"team"`
+	// Score is the sum of the Seeger-Fabian totals of the team's players
+	Score int `json:"score"`
+}
+
+// SeasonTable returns the season table from the league's finished games: the players ranked
+// by their Seeger-Fabian totals (Series are the totals per round) and, in team leagues,
+// the teams ranked by the sum of their players' totals.
+func (l *League) SeasonTable(records []*skat.GameRecord) ([]tournament.Standing, []TeamStanding, error) {
+	standings := make([]tournament.Standing, len(l.Players))
+	index := make(map[string]int)
+	for i, m := range l.Players {
+		standings[i] = tournament.Standing{Player: m.Name, Series: make([]int, len(l.Rounds))}
+		index[m.Name] = i
+	}
+
+	byTable := l.tableRecords(records)
+	for _, r := range l.Rounds {
+		for _, table := range r.Tables {
+			_, points, err := table.Evaluate(l.Bock, byTable[[2]int{r.Number, table.Number}])
+			if err != nil {
+				return nil, nil, err
+			}
+			for name, p := range points {
+				if i, ok := index[name]; ok {
+					standings[i].Add(p)
+					standings[i].Series[r.Number-1] += p.Score
+				}
+			}
+		}
+	}
+
+	teams := []TeamStanding{}
+	for _, team := range l.Teams() {
+		ts := TeamStanding{Team: team}
+		for i, m := range l.Players {
+			if m.Team == team {
+				ts.Score += standings[i].Score
+			}
+		}
+		teams = append(teams, ts)
+	}
+	sort.SliceStable(teams, func(i, j int) bool {
+		return teams[i].Score > teams[j].Score
+	})
+	for i := range teams {
+		teams[i].Rank = i + 1
+		if i > 0 && teams[i].Score == teams[i-1].Score {
+			teams[i].Rank = teams[i-1].Rank
+		}
+	}
+
+	tournament.SortStandings(standings)
+	return standings, teams, nil
+}
+
+// Schedules returns the Bock and Ramsch schedules of the tables of a round from the
+// league's games.
+func (l *League) Schedules(round int, records []*skat.GameRecord) ([]*tournament.Schedule, error) {
+	if round < 1 || round > len(l.Rounds) {
+		return nil, fmt.Errorf("round %d not scheduled", round)
+	}
+	byTable := l.tableRecords(records)
+	var schedules []*tournament.Schedule
+	for _, table := range l.Rounds[round-1].Tables {
+		schedule, _, err := table.Evaluate(l.Bock, byTable[[2]int{round, table.Number}])
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// tableRecords groups the league's records by round and table, and by deal.
+func (l *League) tableRecords(records []*skat.GameRecord) map[[2]int]map[int]*skat.GameRecord {
+	byTable := make(map[[2]int]map[int]*skat.GameRecord)
+	for _, record := range records {
+		round, table, deal, ok := l.parseGameID(record.ID)
+		if !ok {
+			continue
+		}
+		key := [2]int{round, table}
+		if byTable[key] == nil {
+			byTable[key] = make(map[int]*skat.GameRecord)
+		}
+		byTable[key][deal] = record
+	}
+	return byTable
+}
+
+// Store keeps the leagues, one JSON file per league in a directory, and reserves the
+// tables of the rounds at their scheduled time. It is safe for concurrent use.
+type Store struct {
+	dir     string
+	leagues map[string]*League
+	// wake interrupts Run when the schedule changes
+	wake chan struct{}
+	// bock are the Bock and Ramsch rules of new leagues
+	bock tournament.Rules
+	mu   sync.Mutex
+}
+
+// Open opens the store in dir, creating the directory if needed, and loads all leagues.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{dir: dir, leagues: make(map[string]*League), wake: make(chan struct{}, 1)}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		l := &League{}
+		if err := json.Unmarshal(data, l); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		s.leagues[l.Name] = l
+	}
+	return s, nil
+}
+
+// SetBock sets the Bock and Ramsch rules of leagues created from now on.
+// Existing leagues keep their rules.
+func (s *Store) SetBock(rules tournament.Rules) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bock = rules.Copy()
+}
+
+// Create creates a league without players and rounds.
+func (s *Store) Create(name, admin string) (*League, error) {
+	if !validName(name) {
+		return nil, fmt.Errorf("invalid league name: %q", name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.leagues[name] != nil {
+		return nil, ErrExists
+	}
+	l := &League{Name: name, Admin: admin, CreatedAt: time.Now(), Players: []Member{}, Rounds: []Round{}, Bock: s.bock.Copy()}
+	if err := s.write(l); err != nil {
+		return nil, err
+	}
+	s.leagues[name] = l
+	return copyLeague(l), nil
+}
+
+// Get returns a copy of a league.
+func (s *Store) Get(name string) (*League, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l := s.leagues[name]
+	if l == nil {
+		return nil, ErrNotFound
+	}
+	return copyLeague(l), nil
+}
+
+// List returns copies of all leagues in order of their names.
+func (s *Store) List() []*League {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]*League, 0, len(s.leagues))
+	for _, l := range s.leagues {
+		list = append(list, copyLeague(l))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// AddPlayer adds a player to the roster, in a team or individually (team ""). The
+// roster is fixed once the first round has started.
+func (s *Store) AddPlayer(name, login, player, team string) error {
+	if team != "" && !validName(team) {
+		return fmt.Errorf("invalid team name: %q", team)
+	}
+	return s.update(name, login, func(l *League) error {
+		if l.started() {
+			return ErrStarted
+		}
+		for _, m := range l.Players {
+			if m.Name == player {
+				return fmt.Errorf("%s is already on the roster", player)
+			}
+		}
+		l.Players = append(l.Players, Member{Name: player, Team: team})
+		return nil
+	})
+}
+
+// RemovePlayer removes a player from the roster until the first round has started.
+func (s *Store) RemovePlayer(name, login, player string) error {
+	return s.update(name, login, func(l *League) error {
+		if l.started() {
+			return ErrStarted
+		}
+		for i, m := range l.Players {
+			if m.Name == player {
+				l.Players = append(l.Players[:i], l.Players[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not on the roster", player)
+	})
+}
+
+// Schedule adds a round at the given time and returns its number.
+func (s *Store) Schedule(name, login string, start time.Time) (int, error) {
+	number := 0
+	err := s.update(name, login, func(l *League) error {
+		number = len(l.Rounds) + 1
+		l.Rounds = append(l.Rounds, Round{Number: number, Start: start, Tables: []tournament.Table{}})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.notify()
+	return number, nil
+}
+
+// Reschedule moves a round that has not started to another time.
+func (s *Store) Reschedule(name, login string, round int, start time.Time) error {
+	err := s.update(name, login, func(l *League) error {
+		if round < 1 || round > len(l.Rounds) {
+			return fmt.Errorf("no round %d", round)
+		}
+		r := &l.Rounds[round-1]
+		if r.Started {
+			return fmt.Errorf("round %d has started", round)
+		}
+		r.Start = start
+		r.Cancelled = ""
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// Run reserves the tables of every round at its scheduled time until ctx is done and
+// calls fn with the league and the started round. Rounds whose roster cannot be
+// seated are cancelled and can be rescheduled after fixing the roster.
+func (s *Store) Run(ctx context.Context, fn func(l *League, round *Round)) {
+	for {
+		now := time.Now()
+		for _, started := range s.startDue(now) {
+			fn(started.league, started.round)
+		}
+
+		wait := time.Hour
+		if next, ok := s.nextStart(); ok && next.Sub(now) < wait {
+			wait = time.Until(next)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// startedRound is a round started by startDue.
+type startedRound struct {
+	league *League
+	round  *Round
+}
+
+// startDue reserves the tables of all due rounds and returns them.
+func (s *Store) startDue(now time.Time) []startedRound {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var started []startedRound
+	for _, current := range s.leagues {
+		l := copyLeague(current)
+		changed := false
+		for i := range l.Rounds {
+			r := &l.Rounds[i]
+			if r.Started || r.Cancelled != "" || r.Start.After(now) {
+				continue
+			}
+			changed = true
+			tables, err := tournament.Assign(l.seating())
+			if err != nil {
+				r.Cancelled = err.Error()
+				log.Printf("League %s: round %d cancelled: %v", l.Name, r.Number, err)
+				continue
+			}
+			r.Tables = tables
+			r.Started = true
+		}
+		if !changed {
+			continue
+		}
+		if err := s.write(l); err != nil {
+			log.Printf("League %s: failed to save: %v", l.Name, err)
+			continue
+		}
+		s.leagues[l.Name] = l
+		for i := range l.Rounds {
+			if r := &l.Rounds[i]; r.Started && !current.Rounds[i].Started {
+				round := *r
+				started = append(started, startedRound{league: copyLeague(l), round: &round})
+			}
+		}
+	}
+	return started
+}
+
+// nextStart returns the earliest scheduled time of a round that has not started.
+func (s *Store) nextStart() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next time.Time
+	found := false
+	for _, l := range s.leagues {
+		for _, r := range l.Rounds {
+			if !r.Started && r.Cancelled == "" && (!found || r.Start.Before(next)) {
+				next = r.Start
+				found = true
+			}
+		}
+	}
+	return next, found
+}
+
+// notify wakes Run after a schedule change.
+func (s *Store) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// update applies fn to a league managed by login and writes it. Nothing is changed if fn fails.
+func (s *Store) update(name, login string, fn func(l *League) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.leagues[name]
+	if current == nil {
+		return ErrNotFound
+	}
+	if current.Admin != login {
+		return ErrNotAdmin
+	}
+	l := copyLeague(current)
+	if err := fn(l); err != nil {
+		return err
+	}
+	if err := s.write(l); err != nil {
+		return err
+	}
+	s.leagues[name] = l
+	return nil
+}
+
+// write writes a league file. The caller must hold the lock.
+func (s *Store) write(l *League) error {
+	return jsonfile.Write(filepath.Join(s.dir, l.Name+fileExt), l)
+}
+
+// copyLeague returns a deep copy of a league.
+func copyLeague(l *League) *League {
+	c := *l
+	c.Players = append([]Member{}, l.Players...)
+	c.Bock = l.Bock.Copy()
+	c.Rounds = make([]Round, len(l.Rounds))
+	for i, r := range l.Rounds {
+		r.Tables = append([]tournament.Table{}, r.Tables...)
+		for j := range r.Tables {
+			r.Tables[j].Players = append([]string(nil), r.Tables[j].Players...)
+		}
+		c.Rounds[i] = r
+	}
+	return &c
+}
+
+// validName returns true if the name can be used as file name and game ID prefix
+// (letters, digits and "_"; "-" separates the parts of the game IDs).
+func validName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
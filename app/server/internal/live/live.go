@@ -0,0 +1,247 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package live distributes the public events of running tables (moves, tricks, results)
+// and the live tournament standings to watchers like the Server-Sent Events endpoints
+// of the REST API.
+package live
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Event types.
+const (
+	EventStart  = "start"
+	EventMove   = "move"
+	EventTrick  = "trick"
+	EventResult = "result"
+	// EventStandings - the standings of a tournament after a finished deal
+	EventStandings = "standings"
+)
+
+const (
+	// historySize is the number of recent events kept per table for late watchers
+	historySize = 100
+	// bufferSize is the channel buffer of a watcher; events are dropped for watchers that fall behind
+	bufferSize = 64
+)
+
+// Event is a public table event. Hidden information (hands, skat, discards) is never included.
+type Event struct {
+	// ID is the sequence number of the event (per hub, increasing)
+	ID    int64     `json:"id"`
+	Type  string    `json:"type"`
+	Table string    `json:"table"`
+	Time  time.Time `json:"time"`
+	// Players are the player names by position (start only)
+	Players []string `json:"players,omitempty"`
+	// Move is the move (move only; discards without cards)
+	Move *replay.Move `json:"move,omitempty"`
+	// Trick is the completed trick (trick only)
+	Trick *Trick `json:"trick,omitempty"`
+	// Result and Ramsch are the game result (result only)
+	Result *replay.Result      `json:"result,omitempty"`
+	Ramsch *replay.RamschScore `json:"ramsch,omitempty"`
+	// Game is the ID of the game of a table event, or of the finished game (standings)
+	Game string `json:"game,omitempty"`
+	// Standings are the tournament standings (standings only)
+	Standings []tournament.Standing `json:"standings,omitempty"`
+	// Teams are the team standings of team tournaments (standings only)
+	Teams []tournament.TeamStanding `json:"teams,omitempty"`
+}
+
+// Trick is a completed trick.
+type Trick struct {
+	Leader int      `json:"leader"`
+	Cards  []string `json:"cards"`
+	Winner int      `json:"winner"`
+}
+
+// Hub distributes events by table. It is safe for concurrent use.
+type Hub struct {
+	mu       sync.Mutex
+	nextID   int64
+	history  map[string][]Event
+	watchers map[string]map[chan Event]bool
+	closed   bool
+}
+
+// NewHub creates an empty hub.
+func NewHub() *Hub {
+	return &Hub{
+		history:  make(map[string][]Event),
+		watchers: make(map[string]map[chan Event]bool),
+	}
+}
+
+// Publish sends an event to all watchers of its table. ID and Time are set by the hub.
+func (h *Hub) Publish(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	h.nextID++
+	event.ID = h.nextID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	history := append(h.history[event.Table], event)
+	if len(history) > historySize {
+		history = history[len(history)-historySize:]
+	}
+	h.history[event.Table] = history
+
+	for ch := range h.watchers[event.Table] {
+		select {
+		case ch <- event:
+		default:
+			// Slow watcher, drop the event
+		}
+	}
+}
+
+// LastID returns the ID of the last published event (0 = none), so watchers can skip
+// the recent events.
+func (h *Hub) LastID() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.nextID
+}
+
+// Watch returns a channel with the events of a table, starting with the recent events
+// after lastID (0 = all recent events). The channel is closed by cancel or Close.
+func (h *Hub) Watch(table string, lastID int64) (<-chan Event, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan Event, bufferSize+historySize)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	for _, event := range h.history[table] {
+		if event.ID > lastID {
+			ch <- event
+		}
+	}
+	if h.watchers[table] == nil {
+		h.watchers[table] = make(map[chan Event]bool)
+	}
+	h.watchers[table][ch] = true
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.watchers[table][ch] {
+			delete(h.watchers[table], ch)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+// Forget removes the recent events of a table (e.g. when the table is closed).
+func (h *Hub) Forget(table string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.history, table)
+}
+
+// Close closes all watcher channels. Events published afterwards are ignored.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for table, watchers := range h.watchers {
+		for ch := range watchers {
+			close(ch)
+		}
+		delete(h.watchers, table)
+	}
+}
+
+// StartEvent returns the start event of a game.
+func StartEvent(table, game string, players map[skat.Player]string) Event {
+	event := Event{Type: EventStart, Table: table, Game: game}
+	for _, p := range skat.AllPlayers {
+		event.Players = append(event.Players, players[p])
+	}
+	return event
+}
+
+// ActionEvents returns the public events of the action at index of a game: the move
+// and, if the action completed a trick, the trick.
+func ActionEvents(table, id string, game *skat.Game, index int) []Event {
+	action := game.Actions[index]
+	move := replay.NewMove(index+1, action)
+	if action.Type == skat.ActionDiscard {
+		move.Cards = nil
+	}
+	events := []Event{{Type: EventMove, Table: table, Game: id, Time: action.Time, Move: &move}}
+	if action.Type != skat.ActionPlayCard {
+		return events
+	}
+
+	played := 0
+	for _, a := range game.Actions[:index+1] {
+		if a.Type == skat.ActionPlayCard {
+			played++
+		}
+	}
+	if played%3 != 0 || played/3 > len(game.Tricks) {
+		return events
+	}
+	last := game.Tricks[played/3-1]
+	if last.Winner == nil {
+		return events
+	}
+	trick := &Trick{Leader: last.Forehand.Index(), Winner: last.Winner.Index()}
+	for _, tc := range last.Cards {
+		trick.Cards = append(trick.Cards, tc.Card.Code())
+	}
+	return append(events, Event{Type: EventTrick, Table: table, Game: id, Time: action.Time, Trick: trick})
+}
+
+// ResultEvent returns the result event of a finished game.
+func ResultEvent(table, id string, game *skat.Game) Event {
+	event := Event{Type: EventResult, Table: table, Game: id}
+	if result := game.Result; result != nil {
+		event.Result = replay.NewResult(result)
+	}
+	if result := game.RamschResult; result != nil {
+		event.Ramsch = replay.NewRamschScore(result)
+	}
+	return event
+}
+
+// TournamentFeed returns the hub key of the standings feed of a tournament. Table
+// names never contain "/", so the feeds do not clash with tables.
+func TournamentFeed(name string) string {
+	return "tournament/" + name
+}
+
+// StandingsEvent returns the standings event of a tournament after a finished game.
+func StandingsEvent(name, game string, standings []tournament.Standing, teams []tournament.TeamStanding) Event {
+	return Event{Type: EventStandings, Table: TournamentFeed(name), Game: game, Standings: standings, Teams: teams}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package live
+
+import (
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestHubWatch(t *testing.T) {
+	hub := NewHub()
+	hub.Publish(Event{Type: EventStart, Table: "anna"})
+	hub.Publish(Event{Type: EventStart, Table: "ben"})
+	hub.Publish(Event{Type: EventMove, Table: "anna"})
+
+	// Late watchers receive the recent events of their table
+	events, cancel := hub.Watch("anna", 0)
+	for _, want := range []int64{1, 3} {
+		if event := <-events; event.ID != want || event.Table != "anna" || event.Time.IsZero() {
+			t.Errorf("event = %+v, want ID %d of anna with a time", event, want)
+		}
+	}
+	// Reconnecting watchers continue after the last received event
+	resumed, cancelResumed := hub.Watch("anna", 1)
+	if event := <-resumed; event.ID != 3 {
+		t.Errorf("event after 1 = %d, want 3", event.ID)
+	}
+	cancelResumed()
+
+	hub.Publish(Event{Type: EventResult, Table: "anna"})
+	if event := <-events; event.ID != 4 || event.Type != EventResult {
+		t.Errorf("event = %+v, want the result with ID 4", event)
+	}
+	if got := hub.LastID(); got != 4 {
+		t.Errorf("LastID() = %d, want 4", got)
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("channel open after cancel")
+	}
+	if _, ok := <-resumed; ok {
+		t.Error("channel open after cancel")
+	}
+	cancel()
+}
+
+func TestHubForgetAndClose(t *testing.T) {
+	hub := NewHub()
+	hub.Publish(Event{Type: EventStart, Table: "anna"})
+	hub.Forget("anna")
+	events, _ := hub.Watch("anna", 0)
+	select {
+	case event := <-events:
+		t.Errorf("event after Forget() = %+v, want none", event)
+	default:
+	}
+
+	hub.Close()
+	if _, ok := <-events; ok {
+		t.Error("channel open after Close()")
+	}
+	hub.Publish(Event{Type: EventMove, Table: "anna"})
+	if got := hub.LastID(); got != 1 {
+		t.Errorf("LastID() after Close() = %d, want 1", got)
+	}
+	if _, ok := <-func() <-chan Event { ch, _ := hub.Watch("anna", 0); return ch }(); ok {
+		t.Error("Watch() after Close() returned an open channel")
+	}
+}
+
+func TestHubSlowWatcher(t *testing.T) {
+	hub := NewHub()
+	events, cancel := hub.Watch("anna", 0)
+	defer cancel()
+	// Events beyond the buffer of a watcher that does not read are dropped, and the
+	// hub does not block
+	for i := 0; i < bufferSize+historySize+10; i++ {
+		hub.Publish(Event{Type: EventMove, Table: "anna"})
+	}
+	if got := len(events); got != bufferSize+historySize {
+		t.Errorf("buffered events = %d, want %d", got, bufferSize+historySize)
+	}
+}
+
+func TestActionEvents(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		record := recordtest.Played(t, seed)
+		game, err := record.Replay(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var moves, tricks int
+		for i, action := range game.Actions {
+			events := ActionEvents("anna", record.ID, game, i)
+			move := events[0]
+			if move.Type != EventMove || move.Move.Index != i+1 || move.Game != record.ID || move.Table != "anna" {
+				t.Fatalf("seed %d: event %d = %+v, want move %d", seed, i, move, i+1)
+			}
+			if action.Type == skat.ActionDiscard && move.Move.Cards != nil {
+				t.Errorf("seed %d: discard shows %v", seed, move.Move.Cards)
+			}
+			moves++
+			for _, event := range events[1:] {
+				if event.Type != EventTrick {
+					t.Fatalf("seed %d: event %+v after the move, want a trick", seed, event)
+				}
+				trick := game.Tricks[tricks]
+				if event.Trick.Winner != trick.Winner.Index() || event.Trick.Cards[2] != action.Cards[0].Code() {
+					t.Errorf("seed %d: trick %d = %+v, want %+v", seed, tricks+1, event.Trick, trick)
+				}
+				tricks++
+			}
+		}
+		if moves != len(game.Actions) {
+			t.Errorf("seed %d: %d moves, want %d", seed, moves, len(game.Actions))
+		}
+		played := 0
+		for _, trick := range game.Tricks {
+			if !trick.Claimed {
+				played++
+			}
+		}
+		if tricks != played {
+			t.Errorf("seed %d: %d tricks, want %d", seed, tricks, played)
+		}
+
+		result := ResultEvent("anna", record.ID, game)
+		if result.Type != EventResult || result.Result == nil && result.Ramsch == nil {
+			t.Errorf("seed %d: ResultEvent() = %+v, want a result", seed, result)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package moderation checks chat messages before the server sends them: moderators
+// (a word list, an external service) rate a message by severity and a policy maps the
+// severity to an action: mask, warn, drop or mute.
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+)
+
+// DefaultMuteDuration is the default time a muted login cannot chat.
+const DefaultMuteDuration = time.Hour
+
+// DefaultPolicy is the default policy: masked words are masked, the external service
+// decides about dropping and muting.
+const DefaultPolicy = "low=mask,medium=drop,high=mute"
+
+// Severity is the rating of a chat message by a moderator.
+type Severity string
+
+const (
+	// SeverityNone - nothing to object
+	SeverityNone Severity = "none"
+	// SeverityLow - e.g. profanity, which can be masked
+	SeverityLow Severity = "low"
+	// SeverityMedium - e.g. insults
+	SeverityMedium Severity = "medium"
+	// SeverityHigh - e.g. threats, spam or hate speech
+	SeverityHigh Severity = "high"
+)
+
+// Severities contains all severities in ascending order.
+var Severities = []Severity{SeverityNone, SeverityLow, SeverityMedium, SeverityHigh}
+
+// level returns the order of the severity (0 for unknown severities).
+func (s Severity) level() int {
+	for i, severity := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return 0
+}
+
+// Action is what happens to a chat message of a severity.
+type Action string
+
+const (
+	// ActionAllow sends the message unchanged
+	ActionAllow Action = "allow"
+	// ActionMask sends the message with the objected words masked
+	ActionMask Action = "mask"
+	// ActionWarn sends the masked message and warns the sender
+	ActionWarn Action = "warn"
+	// ActionDrop rejects the message
+	ActionDrop Action = "drop"
+	// ActionMute rejects the message and mutes the sender for the mute duration
+	ActionMute Action = "mute"
+)
+
+// Verdict is the rating of a chat message.
+type Verdict struct {
+	Severity Severity `json:"severity"`
+	// Text is the message to send with objected words masked ("" = unchanged)
+	Text string `json:"text,omitempty"`
+	// Reason tells the sender why the message was objected to
+	Reason string `json:"reason,omitempty"`
+}
+
+// Moderator rates chat messages.
+type Moderator interface {
+	Moderate(ctx context.Context, login, text string) (Verdict, error)
+}
+
+// Policy maps severities to actions. Severities without an action are allowed.
+type Policy map[Severity]Action
+
+// ParsePolicy parses a policy like "low=mask,medium=drop,high=mute".
+func ParsePolicy(s string) (Policy, error) {
+	policy := make(Policy)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		severity, action, ok := strings.Cut(entry, "=")
+		if !ok || Severity(severity).level() == 0 {
+			return nil, fmt.Errorf("invalid moderation policy entry: %s", entry)
+		}
+		switch a := Action(action); a {
+		case ActionAllow, ActionMask, ActionWarn, ActionDrop, ActionMute:
+			policy[Severity(severity)] = a
+		default:
+			return nil, fmt.Errorf("invalid moderation action: %s", action)
+		}
+	}
+	return policy, nil
+}
+
+// Decision is the outcome of the moderation of a chat message.
+type Decision struct {
+	Action Action
+	// Text is the text to send (ActionAllow, ActionMask, ActionWarn)
+	Text string
+	// Reason is the reason of the verdict
+	Reason string
+}
+
+// Moderation runs the moderators on chat messages and applies the policy.
+type Moderation struct {
+	moderators []Moderator
+	policy     Policy
+	// Mutes are the muted logins
+	Mutes *Mutes
+	// MuteDuration is the time a muted login cannot chat
+	MuteDuration time.Duration
+}
+
+// New creates a moderation with a policy, a mute store and the moderators, which run in
+// order on the text masked by the previous ones.
+func New(policy Policy, mutes *Mutes, moderators ...Moderator) *Moderation {
+	return &Moderation{moderators: moderators, policy: policy, Mutes: mutes, MuteDuration: DefaultMuteDuration}
+}
+
+// Check rates a sanitized chat message of a login. Moderators that fail are skipped,
+// so an unavailable service does not stop the chat. A mute is stored by Check.
+func (m *Moderation) Check(ctx context.Context, login, text string) Decision {
+	original := text
+	worst := Verdict{Severity: SeverityNone}
+	for _, moderator := range m.moderators {
+		v, err := moderator.Moderate(ctx, login, text)
+		if err != nil {
+			log.Printf("Chat moderation failed: %v", err)
+			continue
+		}
+		if v.Text != "" {
+			text = v.Text
+		}
+		if v.Severity.level() > worst.Severity.level() {
+			worst = v
+		}
+	}
+
+	action := m.policy[worst.Severity]
+	if action == "" || worst.Severity == SeverityNone {
+		action = ActionAllow
+	}
+	if action == ActionAllow {
+		text = original
+	}
+	if action == ActionMute && login != "" && m.Mutes != nil {
+		if _, err := m.Mutes.Mute(login, worst.Reason, time.Now().Add(m.MuteDuration)); err != nil {
+			log.Printf("Muting %s failed: %v", login, err)
+		}
+	}
+	return Decision{Action: action, Text: text, Reason: worst.Reason}
+}
+
+// Muted returns the mute of a login if it cannot chat.
+func (m *Moderation) Muted(login string) (Mute, bool) {
+	if m.Mutes == nil {
+		return Mute{}, false
+	}
+	return m.Mutes.Muted(login)
+}
+
+// WordList is a Moderator rating messages with words of a list as SeverityLow and
+// masking them, e.g. profanity.
+type WordList struct {
+	words *sanitize.WordList
+}
+
+// NewWordList creates a moderator for a word list.
+func NewWordList(words *sanitize.WordList) *WordList {
+	return &WordList{words: words}
+}
+
+// Moderate masks the words of the list.
+func (w *WordList) Moderate(ctx context.Context, login, text string) (Verdict, error) {
+	masked, err := w.words.Filter(text)
+	if err != nil {
+		return Verdict{}, err
+	}
+	if masked == text {
+		return Verdict{Severity: SeverityNone}, nil
+	}
+	return Verdict{Severity: SeverityLow, Text: masked, Reason: "inappropriate words"}, nil
+}
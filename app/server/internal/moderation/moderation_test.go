@@ -0,0 +1,152 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+)
+
+// keyword is a Moderator rating messages containing a word with a severity.
+type keyword struct {
+	word     string
+	severity Severity
+}
+
+func (k keyword) Moderate(ctx context.Context, login, text string) (Verdict, error) {
+	if !strings.Contains(text, k.word) {
+		return Verdict{Severity: SeverityNone}, nil
+	}
+	return Verdict{Severity: k.severity, Reason: k.word}, nil
+}
+
+// broken is a Moderator that always fails.
+type broken struct{}
+
+func (broken) Moderate(context.Context, string, string) (Verdict, error) {
+	return Verdict{}, errors.New("unavailable")
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy(" low=warn, high=mute,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Policy{SeverityLow: ActionWarn, SeverityHigh: ActionMute}); !reflect.DeepEqual(policy, want) {
+		t.Errorf("ParsePolicy() = %v, want %v", policy, want)
+	}
+	if policy, err := ParsePolicy(DefaultPolicy); err != nil || len(policy) != 3 {
+		t.Errorf("ParsePolicy(DefaultPolicy) = %v, %v", policy, err)
+	}
+	for _, s := range []string{"low", "none=drop", "severe=drop", "low=ban"} {
+		if _, err := ParsePolicy(s); err == nil {
+			t.Errorf("ParsePolicy(%q) accepted", s)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	policy, _ := ParsePolicy(DefaultPolicy)
+	policy[SeverityMedium] = ActionWarn
+	m := New(policy, NewMutes(),
+		NewWordList(sanitize.NewWordList([]string{"darn"})),
+		broken{},
+		keyword{"idiot", SeverityMedium},
+		keyword{"spam", SeverityHigh})
+	m.MuteDuration = time.Minute
+
+	tests := []struct {
+		text string
+		want Decision
+	}{
+		{"good game", Decision{Action: ActionAllow, Text: "good game"}},
+		{"Darn it", Decision{Action: ActionMask, Text: "**** it", Reason: "inappropriate words"}},
+		// The worst verdict decides, the text stays masked
+		{"darn idiot", Decision{Action: ActionWarn, Text: "**** idiot", Reason: "idiot"}},
+		{"buy spam", Decision{Action: ActionMute, Text: "buy spam", Reason: "spam"}},
+	}
+	for _, tt := range tests {
+		if d := m.Check(context.Background(), "anna", tt.text); d != tt.want {
+			t.Errorf("Check(%q) = %+v, want %+v", tt.text, d, tt.want)
+		}
+	}
+
+	mute, ok := m.Muted("anna")
+	if !ok || mute.Reason != "spam" || mute.Until.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Muted() = %+v, %v, want a mute of a minute", mute, ok)
+	}
+	if _, ok := New(policy, nil).Muted("anna"); ok {
+		t.Error("muted without a mute store")
+	}
+	// Without moderators and with allowed severities the message is unchanged
+	if d := New(Policy{SeverityLow: ActionAllow}, nil, keyword{"darn", SeverityLow}).Check(context.Background(), "", "darn"); d.Action != ActionAllow || d.Text != "darn" {
+		t.Errorf("Check() with an allowed severity = %+v", d)
+	}
+}
+
+func TestService(t *testing.T) {
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify("s3cret", body, r.Header.Get(webhook.SignatureHeader)) {
+			t.Errorf("invalid signature %q", r.Header.Get(webhook.SignatureHeader))
+		}
+		var request map[string]string
+		if err := json.Unmarshal(body, &request); err != nil || request["login"] != "anna" {
+			t.Errorf("request = %s", body)
+		}
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		switch request["text"] {
+		case "hello":
+			io.WriteString(w, `{}`)
+		case "you idiot":
+			io.WriteString(w, `{"severity":"medium","text":"you *****","reason":"insult"}`)
+		default:
+			io.WriteString(w, `not json`)
+		}
+	}))
+	defer server.Close()
+	s := NewService(server.URL, "s3cret")
+
+	status = http.StatusOK
+	if v, err := s.Moderate(context.Background(), "anna", "hello"); err != nil || v.Severity != SeverityNone {
+		t.Errorf("Moderate(hello) = %+v, %v", v, err)
+	}
+	want := Verdict{Severity: SeverityMedium, Text: "you *****", Reason: "insult"}
+	if v, err := s.Moderate(context.Background(), "anna", "you idiot"); err != nil || v != want {
+		t.Errorf("Moderate(you idiot) = %+v, %v, want %+v", v, err, want)
+	}
+	if _, err := s.Moderate(context.Background(), "anna", "?"); err == nil {
+		t.Error("Moderate() accepted an invalid answer")
+	}
+	status = http.StatusInternalServerError
+	if _, err := s.Moderate(context.Background(), "anna", "hello"); err == nil {
+		t.Error("Moderate() accepted an error status")
+	}
+}
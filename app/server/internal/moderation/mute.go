@@ -0,0 +1,157 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+)
+
+// ErrNotMuted is returned for logins that are not muted.
+var ErrNotMuted = errors.New("login not muted")
+
+// Mute is a muted login.
+type Mute struct {
+	Login  string    `json:"login"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+	// Until is when the login can chat again
+	Until time.Time `json:"until"`
+}
+
+// Mutes keeps the muted logins, in a JSON file if it has a path. Expired mutes are
+// removed when the store is written. It is safe for concurrent use.
+type Mutes struct {
+	path  string
+	mutes map[string]Mute
+	mu    sync.Mutex
+}
+
+// NewMutes creates a store kept in memory only.
+func NewMutes() *Mutes {
+	return &Mutes{mutes: make(map[string]Mute)}
+}
+
+// OpenMutes opens the store in the file at path, which is created with the first mute.
+func OpenMutes(path string) (*Mutes, error) {
+	s := &Mutes{path: path, mutes: make(map[string]Mute)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var mutes []Mute
+	if err := json.Unmarshal(data, &mutes); err != nil {
+		return nil, err
+	}
+	for _, m := range mutes {
+		s.mutes[m.Login] = m
+	}
+	return s, nil
+}
+
+// Mute mutes a login until a time; muting it again replaces the mute.
+func (s *Mutes) Mute(login, reason string, until time.Time) (Mute, error) {
+	if login == "" {
+		return Mute{}, errors.New("login required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := Mute{Login: login, Reason: reason, Time: time.Now(), Until: until}
+	previous, muted := s.mutes[login]
+	s.mutes[login] = m
+	if err := s.write(); err != nil {
+		if muted {
+			s.mutes[login] = previous
+		} else {
+			delete(s.mutes, login)
+		}
+		return Mute{}, err
+	}
+	return m, nil
+}
+
+// Unmute lifts the mute of a login.
+func (s *Mutes) Unmute(login string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.mutes[login]
+	if !ok || !m.Until.After(time.Now()) {
+		return ErrNotMuted
+	}
+	delete(s.mutes, login)
+	if err := s.write(); err != nil {
+		s.mutes[login] = m
+		return err
+	}
+	return nil
+}
+
+// Muted returns the mute of a login if it is muted.
+func (s *Mutes) Muted(login string) (Mute, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.mutes[login]
+	if !ok || !m.Until.After(time.Now()) {
+		return Mute{}, false
+	}
+	return m, true
+}
+
+// List returns the current mutes ordered by login.
+func (s *Mutes) List() []Mute {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list()
+}
+
+// list returns the current mutes ordered by login. The caller must hold the lock.
+func (s *Mutes) list() []Mute {
+	now := time.Now()
+	mutes := make([]Mute, 0, len(s.mutes))
+	for _, m := range s.mutes {
+		if m.Until.After(now) {
+			mutes = append(mutes, m)
+		}
+	}
+	sort.Slice(mutes, func(i, j int) bool {
+		return mutes[i].Login < mutes[j].Login
+	})
+	return mutes
+}
+
+// write writes the mutes file without the expired mutes. The caller must hold the lock.
+func (s *Mutes) write() error {
+	list := s.list()
+	s.mutes = make(map[string]Mute, len(list))
+	for _, m := range list {
+		s.mutes[m.Login] = m
+	}
+	if s.path == "" {
+		return nil
+	}
+	return jsonfile.Write(s.path, list)
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moderation
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mutes.json")
+	s, err := OpenMutes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, m := range []Mute{
+		{Login: "carl", Reason: "spam", Until: now.Add(time.Hour)},
+		{Login: "anna", Until: now.Add(time.Hour)},
+		{Login: "ben", Until: now.Add(-time.Second)},
+	} {
+		if _, err := s.Mute(m.Login, m.Reason, m.Until); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Mute("", "", now.Add(time.Hour)); err == nil {
+		t.Error("Mute() accepted an empty login")
+	}
+
+	// Expired mutes are gone, the others are kept in the file
+	s, err = OpenMutes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := s.List()
+	if len(list) != 2 || list[0].Login != "anna" || list[1].Login != "carl" || list[1].Reason != "spam" {
+		t.Errorf("List() = %+v, want anna and carl", list)
+	}
+	if _, ok := s.Muted("ben"); ok {
+		t.Error("ben is still muted")
+	}
+	if err := s.Unmute("ben"); !errors.Is(err, ErrNotMuted) {
+		t.Errorf("Unmute(ben) = %v, want ErrNotMuted", err)
+	}
+	if err := s.Unmute("anna"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Muted("anna"); ok {
+		t.Error("anna is still muted")
+	}
+	if s, _ := OpenMutes(path); len(s.List()) != 1 {
+		t.Errorf("the file keeps %d mutes, want carl", len(s.List()))
+	}
+}
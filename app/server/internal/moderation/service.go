@@ -0,0 +1,80 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+)
+
+// serviceTimeout is the time the moderation service has to answer.
+const serviceTimeout = 2 * time.Second
+
+// maxServiceResponse is the maximum size of an answer of the moderation service.
+const maxServiceResponse = 64 * 1024
+
+// Service is a Moderator asking an external HTTP service: it posts
+// {"login": "...", "text": "..."} and expects a Verdict as JSON. With a secret the
+// request is signed like the webhooks (X-FreeSkat-Signature).
+type Service struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// NewService creates a moderator for the service at url.
+func NewService(url, secret string) *Service {
+	return &Service{url: url, secret: secret, client: &http.Client{Timeout: serviceTimeout}}
+}
+
+// Moderate asks the service for the verdict of a message.
+func (s *Service) Moderate(ctx context.Context, login, text string) (Verdict, error) {
+	body, err := json.Marshal(map[string]string{"login": login, "text": text})
+	if err != nil {
+		return Verdict{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		req.Header.Set(webhook.SignatureHeader, "sha256="+webhook.Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Verdict{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Verdict{}, fmt.Errorf("moderation service: %s", resp.Status)
+	}
+	var v Verdict
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxServiceResponse)).Decode(&v); err != nil {
+		return Verdict{}, fmt.Errorf("moderation service: %w", err)
+	}
+	if v.Severity == "" {
+		v.Severity = SeverityNone
+	}
+	return v, nil
+}
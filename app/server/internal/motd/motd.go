@@ -0,0 +1,161 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package motd renders the welcome message and the message of the day (MOTD) from Go
+// templates (text/template) the operator keeps in files, reloadable at runtime.
+package motd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// DefaultWelcome is the text of the ISS welcome line without a welcome template.
+const DefaultWelcome = "to ISS"
+
+// Version is the version of the server, set at build time with
+// -ldflags "-X github.com/mkloubert/freeskat-server/internal/motd.Version=<version>";
+// otherwise the module version of the build is used.
+var Version = ""
+
+// ServerVersion returns the version of the server ("(devel)" for local builds).
+func ServerVersion() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// Vars are the variables of the templates, e.g. {{.Username}}.
+type Vars struct {
+	// Username is the login of the user ("" in the welcome, which is sent before the login)
+	Username string
+	// Online is the number of logged-in users
+	Online int
+	// NextTournament is the oldest tournament open for registration ("" = none)
+	NextTournament string
+	// Version is the version of the server
+	Version string
+	// Protocol is the ISS protocol version
+	Protocol int
+}
+
+// Messages holds the welcome and MOTD templates. It is safe for concurrent use.
+type Messages struct {
+	welcomeFile string
+	motdFile    string
+
+	mu      sync.RWMutex
+	welcome *template.Template
+	motd    *template.Template
+}
+
+// New reads the templates of the welcome line and the MOTD ("" = none).
+func New(welcomeFile, motdFile string) (*Messages, error) {
+	m := &Messages{welcomeFile: welcomeFile, motdFile: motdFile}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the template files again. On an error the current templates stay.
+func (m *Messages) Reload() error {
+	welcome, err := parse("welcome", m.welcomeFile)
+	if err != nil {
+		return err
+	}
+	motd, err := parse("motd", m.motdFile)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.welcome, m.motd = welcome, motd
+	return nil
+}
+
+// parse reads a template file (nil for no file).
+func parse(name, file string) (*template.Template, error) {
+	if file == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(name).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s template: %w", name, err)
+	}
+	return t, nil
+}
+
+// Welcome returns the text of the welcome line: the welcome template on one line, or
+// DefaultWelcome.
+func (m *Messages) Welcome(vars Vars) (string, error) {
+	m.mu.RLock()
+	t := m.welcome
+	m.mu.RUnlock()
+	if t == nil {
+		return DefaultWelcome, nil
+	}
+	text, err := execute(t, vars)
+	if err != nil {
+		return DefaultWelcome, err
+	}
+	if text = strings.Join(strings.Fields(text), " "); text == "" {
+		return DefaultWelcome, nil
+	}
+	return text, nil
+}
+
+// MOTD returns the non-empty lines of the MOTD (none without a MOTD template).
+func (m *Messages) MOTD(vars Vars) ([]string, error) {
+	m.mu.RLock()
+	t := m.motd
+	m.mu.RUnlock()
+	if t == nil {
+		return nil, nil
+	}
+	text, err := execute(t, vars)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// execute renders a template.
+func execute(t *template.Template, vars Vars) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
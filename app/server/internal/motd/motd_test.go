@@ -0,0 +1,106 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package motd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTemplate writes a template file.
+func writeTemplate(t *testing.T, path, text string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMessages(t *testing.T) {
+	dir := t.TempDir()
+	welcome, motd := filepath.Join(dir, "welcome.tmpl"), filepath.Join(dir, "motd.tmpl")
+	writeTemplate(t, welcome, "to {{.Version}}\n  (protocol {{.Protocol}})\n")
+	writeTemplate(t, motd, "Hello {{.Username}}!\n\n  {{.Online}} online\n{{with .NextTournament}}Register for {{.}}{{end}}\n")
+	m, err := New(welcome, motd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars := Vars{Username: "anna", Online: 3, Version: "v1.2.0", Protocol: 14}
+	if text, err := m.Welcome(vars); err != nil || text != "to v1.2.0 (protocol 14)" {
+		t.Errorf("Welcome() = %q, %v", text, err)
+	}
+	if lines, err := m.MOTD(vars); err != nil || !reflect.DeepEqual(lines, []string{"Hello anna!", "3 online"}) {
+		t.Errorf("MOTD() = %q, %v", lines, err)
+	}
+	vars.NextTournament = "cup"
+	if lines, _ := m.MOTD(vars); len(lines) != 3 || lines[2] != "Register for cup" {
+		t.Errorf("MOTD() with a tournament = %q", lines)
+	}
+
+	// Reloading reads the changed files, invalid templates keep the current ones
+	writeTemplate(t, motd, "Welcome back, {{.Username}}")
+	if err := m.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if lines, _ := m.MOTD(vars); !reflect.DeepEqual(lines, []string{"Welcome back, anna"}) {
+		t.Errorf("MOTD() after reloading = %q", lines)
+	}
+	writeTemplate(t, welcome, "to {{.Version")
+	if err := m.Reload(); err == nil {
+		t.Error("Reload() accepted an invalid template")
+	}
+	if text, _ := m.Welcome(vars); text != "to v1.2.0 (protocol 14)" {
+		t.Errorf("Welcome() after a failed reload = %q", text)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	m, err := New("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, err := m.Welcome(Vars{}); err != nil || text != DefaultWelcome {
+		t.Errorf("Welcome() = %q, %v, want the default", text, err)
+	}
+	if lines, err := m.MOTD(Vars{}); err != nil || lines != nil {
+		t.Errorf("MOTD() = %q, %v, want none", lines, err)
+	}
+
+	// Empty and failing welcome templates fall back to the default
+	dir := t.TempDir()
+	empty, failing := filepath.Join(dir, "empty.tmpl"), filepath.Join(dir, "failing.tmpl")
+	writeTemplate(t, empty, "{{if .Username}}hi{{end}}")
+	writeTemplate(t, failing, "{{.Rating}}")
+	for _, file := range []string{empty, failing} {
+		m, err := New(file, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text, _ := m.Welcome(Vars{}); text != DefaultWelcome {
+			t.Errorf("Welcome() of %s = %q, want the default", filepath.Base(file), text)
+		}
+	}
+	if _, err := New(filepath.Join(dir, "missing.tmpl"), ""); err == nil {
+		t.Error("New() accepted a missing file")
+	}
+
+	Version = "v9"
+	defer func() { Version = "" }()
+	if ServerVersion() != "v9" {
+		t.Errorf("ServerVersion() = %s", ServerVersion())
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify keeps the notification settings of the players in a JSON file and
+// sends them a notification when a game has been waiting for their move: to their
+// own webhook, an ntfy topic or a Gotify server.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+)
+
+// ErrNotFound is returned for logins without notification settings.
+var ErrNotFound = errors.New("notifications not set")
+
+const (
+	// MinAfter and MaxAfter limit the time a turn waits before the player is notified
+	MinAfter = time.Minute
+	MaxAfter = 7 * 24 * time.Hour
+	// maxURL is the maximum length of a notification URL
+	maxURL = 512
+	// timeout is the timeout of a notification request
+	timeout = 10 * time.Second
+	// title is the title of ntfy and Gotify notifications
+	title = "FreeSkat"
+)
+
+// Service is how a player is notified.
+type Service string
+
+const (
+	// ServiceWebhook posts a turn.waiting event as JSON (see webhook.Event)
+	ServiceWebhook Service = "webhook"
+	// ServiceNtfy publishes the text to an ntfy topic URL
+	ServiceNtfy Service = "ntfy"
+	// ServiceGotify posts the text as a Gotify message (URL with ?token=<app token>)
+	ServiceGotify Service = "gotify"
+)
+
+// Services contains all services.
+var Services = []Service{ServiceWebhook, ServiceNtfy, ServiceGotify}
+
+// ParseService returns the service of a name.
+func ParseService(name string) (Service, error) {
+	for _, s := range Services {
+		if string(s) == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+	names := make([]string, len(Services))
+	for i, s := range Services {
+		names[i] = string(s)
+	}
+	return "", fmt.Errorf("invalid notification service: %s (want %s)", name, strings.Join(names, ", "))
+}
+
+// Settings are the notification settings of a player.
+type Settings struct {
+	Login   string  `json:"login"`
+	Service Service `json:"service"`
+	URL     string  `json:"url"`
+	// After is the time in seconds a turn waits before the player is notified
+	// (0 = the server default)
+	After int `json:"after,omitempty"`
+}
+
+// Delay returns the time a turn waits before the player is notified.
+func (s Settings) Delay(fallback time.Duration) time.Duration {
+	if s.After > 0 {
+		return time.Duration(s.After) * time.Second
+	}
+	return fallback
+}
+
+// Validate checks the service, the URL and the delay.
+func (s Settings) Validate() error {
+	if s.Login == "" {
+		return errors.New("login required")
+	}
+	if _, err := ParseService(string(s.Service)); err != nil {
+		return err
+	}
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || len(s.URL) > maxURL {
+		return fmt.Errorf("invalid notification URL: %s", s.URL)
+	}
+	if after := time.Duration(s.After) * time.Second; s.After != 0 && (after < MinAfter || after > MaxAfter) {
+		return fmt.Errorf("invalid notification delay: %s (want %s to %s)", after, MinAfter, MaxAfter)
+	}
+	return nil
+}
+
+// Store keeps the notification settings. It is safe for concurrent use.
+type Store struct {
+	path     string
+	settings map[string]Settings
+	mu       sync.Mutex
+}
+
+// Open opens the store in the file at path, which is created with the first settings.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, settings: make(map[string]Settings)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Settings
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, settings := range list {
+		s.settings[settings.Login] = settings
+	}
+	return s, nil
+}
+
+// Get returns the settings of a login.
+func (s *Store) Get(login string) (Settings, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settings, ok := s.settings[login]
+	return settings, ok
+}
+
+// Set validates and replaces the settings of a login.
+func (s *Store) Set(settings Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.settings[settings.Login]
+	s.settings[settings.Login] = settings
+	if err := s.write(); err != nil {
+		if existed {
+			s.settings[settings.Login] = previous
+		} else {
+			delete(s.settings, settings.Login)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes the settings of a login.
+func (s *Store) Delete(login string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings, ok := s.settings[login]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.settings, login)
+	if err := s.write(); err != nil {
+		s.settings[login] = settings
+		return err
+	}
+	return nil
+}
+
+// write writes the settings file ordered by login. The caller must hold the lock.
+func (s *Store) write() error {
+	list := make([]Settings, 0, len(s.settings))
+	for _, settings := range s.settings {
+		list = append(list, settings)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Login < list[j].Login
+	})
+	return jsonfile.Write(s.path, list)
+}
+
+// Sender sends the notifications.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a sender.
+func NewSender() *Sender {
+	return &Sender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send notifies a player of a waiting turn; text describes it for ntfy and Gotify.
+func (s *Sender) Send(ctx context.Context, settings Settings, turn webhook.Turn, text string) error {
+	var body []byte
+	contentType := "application/json"
+	var err error
+	switch settings.Service {
+	case ServiceWebhook:
+		body, err = json.Marshal(webhook.Event{Type: webhook.EventTurnWaiting, Time: time.Now(), Turn: &turn})
+	case ServiceGotify:
+		body, err = json.Marshal(map[string]any{"title": title, "message": text, "priority": 5})
+	case ServiceNtfy:
+		body, contentType = []byte(text), "text/plain; charset=utf-8"
+	default:
+		return fmt.Errorf("invalid notification service: %s", settings.Service)
+	}
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if settings.Service == ServiceNtfy {
+		req.Header.Set("Title", title)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification: %s", resp.Status)
+	}
+	return nil
+}
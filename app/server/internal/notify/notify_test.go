@@ -0,0 +1,136 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+)
+
+func TestSettings(t *testing.T) {
+	valid := Settings{Login: "anna", Service: ServiceNtfy, URL: "https://ntfy.sh/anna-skat"}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if valid.Delay(time.Hour) != time.Hour {
+		t.Errorf("Delay() = %s, want the fallback", valid.Delay(time.Hour))
+	}
+	valid.After = 1800
+	if err := valid.Validate(); err != nil || valid.Delay(time.Hour) != 30*time.Minute {
+		t.Errorf("Delay() = %s, %v, want 30m", valid.Delay(time.Hour), err)
+	}
+
+	for _, invalid := range []Settings{
+		{Service: ServiceNtfy, URL: "https://ntfy.sh/anna"},
+		{Login: "anna", Service: "mail", URL: "https://ntfy.sh/anna"},
+		{Login: "anna", Service: ServiceWebhook, URL: "mailto:anna@example.com"},
+		{Login: "anna", Service: ServiceGotify, URL: "https://"},
+		{Login: "anna", Service: ServiceNtfy, URL: "https://ntfy.sh/anna", After: 30},
+		{Login: "anna", Service: ServiceNtfy, URL: "https://ntfy.sh/anna", After: 8 * 24 * 3600},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Validate(%+v) accepted", invalid)
+		}
+	}
+	if s, err := ParseService("Gotify"); err != nil || s != ServiceGotify {
+		t.Errorf("ParseService(Gotify) = %s, %v", s, err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	anna := Settings{Login: "anna", Service: ServiceWebhook, URL: "https://example.com/hook", After: 600}
+	if err := s.Set(anna); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(Settings{Login: "ben", Service: ServiceNtfy, URL: "ntfy.sh/ben"}); err == nil {
+		t.Error("Set() accepted an invalid URL")
+	}
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := s.Get("anna"); !ok || got != anna {
+		t.Errorf("Get(anna) = %+v, %v, want %+v", got, ok, anna)
+	}
+	if _, ok := s.Get("ben"); ok {
+		t.Error("ben has settings")
+	}
+	if err := s.Delete("anna"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("anna"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(anna) again = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSender(t *testing.T) {
+	type request struct {
+		contentType, title string
+		body               []byte
+	}
+	requests := make(chan request, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{r.Header.Get("Content-Type"), r.Header.Get("Title"), body}
+		if r.URL.Path == "/gone" {
+			http.Error(w, "gone", http.StatusGone)
+		}
+	}))
+	defer server.Close()
+
+	s := NewSender()
+	turn := webhook.Turn{Player: "anna", Table: "daily-2025-03-14", Game: "g1", Since: time.Date(2025, 3, 14, 18, 0, 0, 0, time.UTC)}
+	text := "It is your turn"
+
+	if err := s.Send(context.Background(), Settings{Service: ServiceWebhook, URL: server.URL}, turn, text); err != nil {
+		t.Fatal(err)
+	}
+	r := <-requests
+	var event webhook.Event
+	if err := json.Unmarshal(r.body, &event); err != nil || r.contentType != "application/json" ||
+		event.Type != webhook.EventTurnWaiting || event.Turn == nil || *event.Turn != turn {
+		t.Errorf("webhook request = %s, %s", r.contentType, r.body)
+	}
+
+	if err := s.Send(context.Background(), Settings{Service: ServiceNtfy, URL: server.URL}, turn, text); err != nil {
+		t.Fatal(err)
+	}
+	if r := <-requests; string(r.body) != text || r.title != title || r.contentType != "text/plain; charset=utf-8" {
+		t.Errorf("ntfy request = %s, %q, %s", r.contentType, r.title, r.body)
+	}
+
+	if err := s.Send(context.Background(), Settings{Service: ServiceGotify, URL: server.URL + "/gone"}, turn, text); err == nil {
+		t.Error("Send() accepted an error status")
+	}
+	var message map[string]any
+	if r := <-requests; json.Unmarshal(r.body, &message) != nil || message["message"] != text || message["title"] != title {
+		t.Errorf("Gotify request = %s", r.body)
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profile keeps the public profiles of the players in a JSON file.
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+)
+
+// ErrNotFound is returned for logins without a profile.
+var ErrNotFound = errors.New("profile not found")
+
+// Maximum lengths of the fields in characters.
+const (
+	MaxName   = 32
+	MaxClub   = 64
+	MaxAvatar = 256
+)
+
+// Fields of a profile.
+const (
+	FieldName    = "name"
+	FieldClub    = "club"
+	FieldCountry = "country"
+	FieldRules   = "rules"
+	FieldAvatar  = "avatar"
+)
+
+// Fields contains all fields in the order they are listed.
+var Fields = []string{FieldName, FieldClub, FieldCountry, FieldRules, FieldAvatar}
+
+// Profile is the public metadata of a player.
+type Profile struct {
+	Login string `json:"login"`
+	// Name is the display name
+	Name string `json:"name,omitempty"`
+	Club string `json:"club,omitempty"`
+	// Country is the ISO 3166-1 alpha-2 code, e.g. DE
+	Country string `json:"country,omitempty"`
+	// Rules is the preferred rule profile, e.g. isko (see tournament.Profiles)
+	Rules string `json:"rules,omitempty"`
+	// Avatar is the URL of the avatar image
+	Avatar  string    `json:"avatar,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// Get returns the value of a field ("" if it is not set or unknown).
+func (p *Profile) Get(field string) string {
+	switch field {
+	case FieldName:
+		return p.Name
+	case FieldClub:
+		return p.Club
+	case FieldCountry:
+		return p.Country
+	case FieldRules:
+		return p.Rules
+	case FieldAvatar:
+		return p.Avatar
+	}
+	return ""
+}
+
+// Set validates and sets a field; an empty value clears it.
+func (p *Profile) Set(field, value string) error {
+	value = strings.TrimSpace(value)
+	switch field {
+	case FieldName, FieldClub:
+		max := MaxName
+		if field == FieldClub {
+			max = MaxClub
+		}
+		if value != "" {
+			cleaned, err := sanitize.Clean(value, max)
+			if err != nil {
+				return err
+			}
+			value = cleaned
+		}
+		if field == FieldName {
+			p.Name = value
+		} else {
+			p.Club = value
+		}
+	case FieldCountry:
+		value = strings.ToUpper(value)
+		if value != "" && (len(value) != 2 || value[0] < 'A' || value[0] > 'Z' || value[1] < 'A' || value[1] > 'Z') {
+			return fmt.Errorf("invalid country: %s (want a two-letter code, e.g. DE)", value)
+		}
+		p.Country = value
+	case FieldRules:
+		if value != "" {
+			rules, err := tournament.ParseProfile(value)
+			if err != nil {
+				return err
+			}
+			value = rules.Name
+		}
+		p.Rules = value
+	case FieldAvatar:
+		if value != "" {
+			parsed, err := url.Parse(value)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+				len(value) > MaxAvatar || strings.ContainsAny(value, " \t") {
+				return fmt.Errorf("invalid avatar URL: %s", value)
+			}
+		}
+		p.Avatar = value
+	default:
+		return fmt.Errorf("invalid profile field: %s (want %s)", field, strings.Join(Fields, ", "))
+	}
+	return nil
+}
+
+// Validate validates and normalizes all fields.
+func (p *Profile) Validate() error {
+	for _, field := range Fields {
+		if err := p.Set(field, p.Get(field)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// empty returns true if no field is set.
+func (p *Profile) empty() bool {
+	for _, field := range Fields {
+		if p.Get(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Store keeps the profiles. It is safe for concurrent use.
+type Store struct {
+	path     string
+	profiles map[string]Profile
+	mu       sync.Mutex
+}
+
+// Open opens the store in the file at path, which is created with the first profile.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, profiles: make(map[string]Profile)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		s.profiles[p.Login] = p
+	}
+	return s, nil
+}
+
+// Get returns the profile of a login.
+func (s *Store) Get(login string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.profiles[login]
+	return p, ok
+}
+
+// Profiles returns the profiles of the logins that have one, in the order of the logins.
+func (s *Store) Profiles(logins []string) []Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profiles := []Profile{}
+	for _, login := range logins {
+		if p, ok := s.profiles[login]; ok {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
+// SetField validates and sets a field of the profile of a login, which is created if
+// needed; an empty value clears the field.
+func (s *Store) SetField(login, field, value string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.profiles[login]
+	p.Login = login
+	if err := p.Set(field, value); err != nil {
+		return Profile{}, err
+	}
+	return s.put(p)
+}
+
+// Put validates and replaces the profile of a login; a profile without fields is removed.
+func (s *Store) Put(p Profile) (Profile, error) {
+	if p.Login == "" {
+		return Profile{}, errors.New("login required")
+	}
+	if err := p.Validate(); err != nil {
+		return Profile{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.put(p)
+}
+
+// Delete removes the profile of a login.
+func (s *Store) Delete(login string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.profiles[login]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.profiles, login)
+	if err := s.write(); err != nil {
+		s.profiles[login] = p
+		return err
+	}
+	return nil
+}
+
+// put stores a validated profile, or removes it if it has no fields. The caller must
+// hold the lock.
+func (s *Store) put(p Profile) (Profile, error) {
+	previous, existed := s.profiles[p.Login]
+	p.Updated = time.Now()
+	if p.empty() {
+		delete(s.profiles, p.Login)
+	} else {
+		s.profiles[p.Login] = p
+	}
+	if err := s.write(); err != nil {
+		if existed {
+			s.profiles[p.Login] = previous
+		} else {
+			delete(s.profiles, p.Login)
+		}
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// list returns the profiles ordered by login. The caller must hold the lock.
+func (s *Store) list() []Profile {
+	profiles := make([]Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Login < profiles[j].Login
+	})
+	return profiles
+}
+
+// write writes the profiles file. The caller must hold the lock.
+func (s *Store) write() error {
+	return jsonfile.Write(s.path, s.list())
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		field, value string
+		want         string // "" = invalid
+	}{
+		{FieldName, "  Anna Schmidt ", "Anna Schmidt"},
+		{FieldName, strings.Repeat("a", MaxName+1), ""},
+		{FieldClub, "Skatclub Grand Hand e.V.", "Skatclub Grand Hand e.V."},
+		{FieldCountry, "de", "DE"},
+		{FieldCountry, "DEU", ""},
+		{FieldCountry, "d1", ""},
+		{FieldRules, "ISKO", "isko"},
+		{FieldRules, "pub", ""},
+		{FieldAvatar, "https://example.com/anna.png", "https://example.com/anna.png"},
+		{FieldAvatar, "ftp://example.com/anna.png", ""},
+		{FieldAvatar, "https:///anna.png", ""},
+		{"rating", "2000", ""},
+	}
+	for _, tt := range tests {
+		var p Profile
+		err := p.Set(tt.field, tt.value)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Set(%s, %q) accepted", tt.field, tt.value)
+			}
+			continue
+		}
+		if err != nil || p.Get(tt.field) != tt.want {
+			t.Errorf("Set(%s, %q) = %q, %v, want %q", tt.field, tt.value, p.Get(tt.field), err, tt.want)
+		}
+		// An empty value clears the field
+		if err := p.Set(tt.field, ""); err != nil || p.Get(tt.field) != "" {
+			t.Errorf("clearing %s = %q, %v", tt.field, p.Get(tt.field), err)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetField("carl", FieldCountry, "at"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SetField("carl", FieldCountry, "Austria"); err == nil {
+		t.Error("SetField() accepted an invalid country")
+	}
+	anna, err := s.Put(Profile{Login: "anna", Name: " Anna ", Rules: "Club"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anna.Name != "Anna" || anna.Rules != "club" || anna.Updated.IsZero() {
+		t.Errorf("Put() = %+v, want the normalized profile", anna)
+	}
+	for _, invalid := range []Profile{{Name: "nobody"}, {Login: "ben", Avatar: "anna.png"}} {
+		if _, err := s.Put(invalid); err == nil {
+			t.Errorf("Put(%+v) accepted", invalid)
+		}
+	}
+
+	// The profiles are kept in the file
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles := s.Profiles([]string{"carl", "ben", "anna"})
+	if len(profiles) != 2 || profiles[0].Country != "AT" || profiles[1].Name != "Anna" {
+		t.Errorf("Profiles() = %+v, want carl and anna", profiles)
+	}
+
+	// Clearing the last field removes the profile
+	if _, err := s.SetField("carl", FieldCountry, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get("carl"); ok {
+		t.Error("carl still has a profile")
+	}
+	if err := s.Delete("anna"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("anna"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(anna) again = %v, want ErrNotFound", err)
+	}
+	if s, _ := Open(path); len(s.Profiles([]string{"anna", "carl"})) != 0 {
+		t.Error("the file still has profiles")
+	}
+}
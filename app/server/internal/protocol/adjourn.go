@@ -0,0 +1,221 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// Adjourn archives the games of all running bot tables as adjourned and removes the
+// tables, so the games can be resumed after a restart. Practice games end without
+// being archived. It is called on shutdown.
+func (h *Handler) Adjourn() {
+	h.mu.Lock()
+	tables := h.tables
+	h.tables = make(map[string]*BotTable)
+	h.mu.Unlock()
+
+	for id, table := range tables {
+		h.closeAudience(id)
+		if table.Practice {
+			h.botPool.ReleaseTable(table.record.ID)
+			delete(tables, id)
+		}
+	}
+
+	if h.archive == nil {
+		return
+	}
+	for id, table := range tables {
+		record, err := table.Record()
+		if err == nil {
+			err = h.archive.Save(record)
+		}
+		if err == nil {
+			err = h.archive.SetAdjourned(record.ID, true)
+		}
+		if err != nil {
+			log.Printf("[%s] Failed to adjourn game of table %s: %v", id, table.Table, err)
+			continue
+		}
+		log.Printf("[%s] Adjourned game %s of table %s", id, record.ID, table.Table)
+
+		if sess := h.sessionManager.GetSession(id); sess != nil {
+			h.SendText(sess, "Server restarts, game %s is adjourned until you log in again", record.ID)
+		}
+	}
+}
+
+// RestoreAdjourned recreates the tables of all adjourned games from the archive. They
+// wait in adjourned status until their player logs in. It returns the number of tables.
+func (h *Handler) RestoreAdjourned() (int, error) {
+	if h.archive == nil {
+		return 0, nil
+	}
+	ids, err := h.archive.Adjourned()
+	if err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	for _, id := range ids {
+		table, err := h.restoreTable(id)
+		if err != nil {
+			log.Printf("Failed to restore adjourned game %s: %v", id, err)
+			continue
+		}
+
+		h.mu.Lock()
+		h.adjourned[table.Login] = table
+		h.mu.Unlock()
+		if h.notifications != nil {
+			h.trackTurn(table)
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// restoreTable recreates the bot table of an adjourned game. Only daily games are
+// played at bot tables.
+func (h *Handler) restoreTable(id string) (*BotTable, error) {
+	date, ok := daily.GameDate(id)
+	if !ok || h.daily == nil {
+		return nil, fmt.Errorf("no table for game %s", id)
+	}
+	record, err := h.archive.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	position, ok := daily.PlayerPosition(record)
+	if !ok {
+		return nil, fmt.Errorf("no player in game %s", id)
+	}
+	deal, err := h.daily.Deal(date)
+	if err != nil {
+		return nil, err
+	}
+	table, err := RestoreBotTable("daily-"+date, record, position, deal.Bots())
+	if err != nil {
+		return nil, err
+	}
+	// The clocks are not archived: the resumed game starts with full thinking times
+	if h.dailyClock > 0 {
+		table.SetClocks(h.dailyClock)
+	}
+	table.SetDifficulties(deal.Difficulties())
+	return table, nil
+}
+
+// resumeAdjourned continues the adjourned game of a client that logged in: it sends
+// "text Resuming adjourned game <id>", the table start, the deal and all moves so far.
+func (h *Handler) resumeAdjourned(sess *session.Session) error {
+	h.mu.Lock()
+	table := h.adjourned[sess.Username()]
+	if table == nil || h.tables[sess.ID()] != nil {
+		h.mu.Unlock()
+		return nil
+	}
+	delete(h.adjourned, sess.Username())
+	h.tables[sess.ID()] = table
+	h.mu.Unlock()
+
+	log.Printf("[%s] Resuming adjourned game of table %s", sess.ID(), table.Table)
+	if err := h.SendText(sess, "Resuming adjourned game %s", table.record.ID); err != nil {
+		return err
+	}
+	messages, err := table.Resume()
+	if err != nil {
+		log.Printf("[%s] Adjourned game failed: %v", sess.ID(), err)
+		h.leaveBotTable(sess)
+		return h.SendError(sess, "Adjourned game failed")
+	}
+	return h.sendBotMessages(sess, table, messages)
+}
+
+// disconnectBotTable handles the bot table of a disconnected client. With a forfeit
+// grace period (SetDailyForfeit) a game in the trick play is adjourned until the
+// client logs in again and forfeited when the period expires; other games are
+// archived as they are. Practice games and games shared with other clients end.
+func (h *Handler) disconnectBotTable(sess *session.Session) {
+	table := h.botTable(sess)
+	if table != nil {
+		table.Lock()
+		defer table.Unlock()
+	}
+	if table == nil || table.Practice || table.Shared() || h.dailyForfeit <= 0 || h.archive == nil ||
+		!table.Forfeitable() {
+		h.leaveBotTable(sess)
+		return
+	}
+
+	h.mu.Lock()
+	delete(h.tables, sess.ID())
+	h.adjourned[table.Login] = table
+	h.mu.Unlock()
+	h.closeAudience(sess.ID())
+
+	record, err := table.Record()
+	if err == nil {
+		err = h.archive.Save(record)
+	}
+	if err == nil {
+		err = h.archive.SetAdjourned(record.ID, true)
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to adjourn game of table %s: %v", sess.ID(), table.Table, err)
+	}
+	log.Printf("[%s] Game of table %s waits %s for '%s'", sess.ID(), table.Table, h.dailyForfeit, table.Login)
+	time.AfterFunc(h.dailyForfeit, func() { h.forfeitTable(table) })
+}
+
+// forfeitTable ends the game of a client who did not log in again within the grace
+// period: the client abandons it (see BotTable.Forfeit) and the game is archived.
+func (h *Handler) forfeitTable(table *BotTable) {
+	h.mu.Lock()
+	if h.adjourned[table.Login] != table {
+		// Resumed in time
+		h.mu.Unlock()
+		return
+	}
+	delete(h.adjourned, table.Login)
+	delete(h.turns, table.Login)
+	h.mu.Unlock()
+
+	if _, err := table.Forfeit(); err != nil {
+		log.Printf("Failed to forfeit game of table %s: %v", table.Table, err)
+	}
+	table.TakePublic()
+	h.stream.Publish(table.TakeEvents()...)
+	h.publishLive(table)
+
+	record, err := table.Record()
+	if err == nil {
+		err = h.archive.Save(record)
+	}
+	if err == nil {
+		err = h.archive.SetAdjourned(record.ID, false)
+	}
+	if err != nil {
+		log.Printf("Failed to archive forfeited game of table %s: %v", table.Table, err)
+		return
+	}
+	log.Printf("'%s' forfeited game %s of table %s: %s", table.Login, record.ID, table.Table, table.Game().Forfeit)
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+func TestAdjournAndRestore(t *testing.T) {
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := daily.NewSchedule("secret", time.UTC)
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	h.SetArchive(games)
+	h.SetDaily(schedule)
+	anna := newTestClient(t, m, "anna")
+
+	if err := h.handleMessage(anna.sess, "daily play"); err != nil {
+		t.Fatal(err)
+	}
+	table := h.botTable(anna.sess)
+	if table == nil {
+		t.Fatal("no daily table")
+	}
+	id := table.record.ID
+	played, err := table.Record()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Shutdown: the game is archived as adjourned
+	h.Adjourn()
+	if h.botTable(anna.sess) != nil {
+		t.Error("the table still exists after adjourning")
+	}
+	if !anna.received("Server restarts, game " + id + " is adjourned") {
+		t.Error("anna was not told about the adjourned game")
+	}
+	if !games.IsAdjourned(id) {
+		t.Fatalf("game %s is not marked as adjourned", id)
+	}
+
+	// Restart: the table waits until anna logs in again
+	m = session.NewManager(context.Background())
+	h = NewHandler(m, nil)
+	h.SetArchive(games)
+	h.SetDaily(schedule)
+	if n, err := h.RestoreAdjourned(); err != nil || n != 1 {
+		t.Fatalf("RestoreAdjourned() = %d, %v, want 1", n, err)
+	}
+	ben := newTestClient(t, m, "ben")
+	if err := h.resumeAdjourned(ben.sess); err != nil || h.botTable(ben.sess) != nil {
+		t.Errorf("ben resumed the game of anna: %v", err)
+	}
+
+	anna = newTestClient(t, m, "anna")
+	if err := h.resumeAdjourned(anna.sess); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("Resuming adjourned game "+id) || !anna.received("table "+table.Table+" anna start") {
+		t.Error("anna did not get the adjourned game")
+	}
+	resumed := h.botTable(anna.sess)
+	if resumed == nil || resumed.record.ID != id {
+		t.Fatal("the adjourned game is not resumed")
+	}
+	// The moves the bots made before anna's turn are kept
+	if len(resumed.record.Actions) != len(played.Actions) {
+		t.Errorf("resumed with %d moves, want %d", len(resumed.record.Actions), len(played.Actions))
+	}
+	// It is resumed only once
+	if err := h.resumeAdjourned(newTestClient(t, m, "anna").sess); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(h.adjourned); n != 0 {
+		t.Errorf("%d adjourned tables left", n)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"sort"
+
+	"github.com/mkloubert/freeskat-server/internal/ban"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// TableInfo describes a running bot table for the operators.
+type TableInfo struct {
+	// Table is the name of the table
+	Table string `json:"table"`
+	// Player is the login of the playing client
+	Player string `json:"player"`
+	// Session is the ID of the player's session
+	Session string `json:"session"`
+	// Game is the ID the game is archived under
+	Game string `json:"game"`
+	// Players are the players in the order forehand, middlehand, rearhand
+	Players []string `json:"players"`
+	// Observers is the number of observers
+	Observers int `json:"observers"`
+}
+
+// SetBans sets the store of the banned logins, which are rejected at login.
+func (h *Handler) SetBans(store *ban.Store) {
+	h.bans = store
+}
+
+// Tables returns the running bot tables, ordered by player.
+func (h *Handler) Tables() []TableInfo {
+	h.mu.Lock()
+	tables := make([]TableInfo, 0, len(h.tables))
+	for id, table := range h.tables {
+		info := TableInfo{Table: table.Table, Player: table.Login, Session: id, Game: table.record.ID}
+		for _, p := range skat.AllPlayers {
+			info.Players = append(info.Players, table.record.Players[p])
+		}
+		if a := h.audiences[id]; a != nil {
+			info.Observers = a.observers.Len()
+		}
+		tables = append(tables, info)
+	}
+	h.mu.Unlock()
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Player < tables[j].Player
+	})
+	return tables
+}
+
+// CloseTable closes the bot table of a player: the game is archived as it is, and the
+// player and the observers receive "table <name> <player> destroy". It returns false if
+// the player has no table.
+func (h *Handler) CloseTable(player string) bool {
+	h.mu.Lock()
+	var id string
+	var table *BotTable
+	for candidate, t := range h.tables {
+		if t.Login == player {
+			id, table = candidate, t
+		}
+	}
+	h.mu.Unlock()
+	if table == nil {
+		return false
+	}
+	sess := h.sessionManager.GetSession(id)
+	if sess == nil {
+		return false
+	}
+
+	table.Lock()
+	h.leaveBotTable(sess)
+	table.Unlock()
+	if err := h.SendText(sess, "Table %s was closed by an operator", table.Table); err != nil {
+		log.Printf("[%s] Failed to close table %s: %v", sess.ID(), table.Table, err)
+	}
+	if err := sess.WriteLine("%s %s %s %s", MsgTable, table.Table, table.Login, TableActionDestroy); err != nil {
+		log.Printf("[%s] Failed to close table %s: %v", sess.ID(), table.Table, err)
+	}
+	log.Printf("[%s] Table %s closed by an operator", sess.ID(), table.Table)
+	return true
+}
+
+// Broadcast sends "text <message>" to all logged-in clients and returns their number.
+func (h *Handler) Broadcast(message string) int {
+	sent := 0
+	for _, sess := range h.sessionManager.List() {
+		if sess.Username() == "" {
+			continue
+		}
+		if err := sess.WriteLine("%s %s", MsgText, message); err != nil {
+			log.Printf("[%s] Failed to send broadcast: %v", sess.ID(), err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"log"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+	"github.com/mkloubert/freeskat-server/pkg/solver"
+)
+
+// analyzeGame runs the mistake analysis of a finished game, stores it with the archived
+// game and sends the mistakes of the client's position as text messages:
+// "text Analysis of game <id>:" followed by one "text <mistake>" line per mistake.
+// Ramsch games are not analyzed.
+func (h *Handler) analyzeGame(sess *session.Session, record *skat.GameRecord, position skat.Player) {
+	mistakes, err := solver.Analyze(record, h.mistakeLoss)
+	if errors.Is(err, solver.ErrNotSupported) {
+		return
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to analyze game %s: %v", sess.ID(), record.ID, err)
+		return
+	}
+	if err := h.archive.SetMistakes(record.ID, mistakes); err != nil {
+		log.Printf("[%s] Failed to store the analysis of game %s: %v", sess.ID(), record.ID, err)
+	}
+
+	lines := []string{MsgText + " " + h.text(sess, "Analysis of game %s:", record.ID)}
+	for _, line := range h.mistakeReport(sess, mistakes, position) {
+		lines = append(lines, MsgText+" "+line)
+	}
+	if err := h.sendLines(sess, lines); err != nil {
+		log.Printf("[%s] Failed to send the analysis of game %s: %v", sess.ID(), record.ID, err)
+	}
+}
+
+// mistakeReport describes the mistakes of a player in the language of the client,
+// like solver.Report.
+func (h *Handler) mistakeReport(sess *session.Session, mistakes []solver.Mistake, player skat.Player) []string {
+	var lines []string
+	for _, m := range mistakes {
+		if m.Player != player {
+			continue
+		}
+		loss := h.text(sess, "%d card points", m.Loss)
+		if m.GameLost {
+			loss = h.text(sess, "loses the game")
+		}
+		lines = append(lines, h.text(sess, "move %d: %s played %s, %s was better (%s)",
+			m.Move, h.text(sess, m.Player.String()), m.Card.Code(), m.Best.Code(), loss))
+	}
+	if len(lines) == 0 {
+		return []string{h.text(sess, "no clear mistakes of %s", h.text(sess, player.String()))}
+	}
+	return lines
+}
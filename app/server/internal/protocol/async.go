@@ -0,0 +1,538 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/async"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// defaultAsyncRules is the rule profile of correspondence games opened without one.
+const defaultAsyncRules = "club"
+
+// SetAsync enables the correspondence games with the store of the open and running
+// games and the vacations of the players (requires an archive). moveTime is the
+// default time per move of new games.
+func (h *Handler) SetAsync(games *async.Store, vacations *async.Vacations, moveTime time.Duration) {
+	h.games = games
+	h.vacations = vacations
+	h.moveTime = moveTime
+}
+
+// handleAsync processes the correspondence game commands:
+//
+//	async [list]                lists the own games, then the open games of others
+//	async new [hours] [rules]   opens a game with the move time in hours and a rule profile
+//	async join <id>             joins an open game; the third player starts it
+//	async leave <id>            leaves an open game
+//	async show <id>             sends the table messages of a running game so far
+//	async vacation [days]       shows the vacation days or starts a vacation
+//	async vacation off          ends the vacation early
+//
+// The players send their moves as "table <id> <login> play <move>" whenever they like
+// before the deadline of their move.
+func (h *Handler) handleAsync(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.games == nil {
+		return h.SendError(sess, "No correspondence games available")
+	}
+
+	action := AsyncActionList
+	if len(parts) >= 2 {
+		action = parts[1]
+	}
+	switch action {
+	case AsyncActionList:
+		return h.sendAsyncGames(sess, h.games.Games(sess.Username()), h.games.Joinable(sess.Username()))
+	case AsyncActionNew:
+		moveTime, rules := h.moveTime, defaultAsyncRules
+		if len(parts) >= 3 {
+			hours, err := strconv.Atoi(parts[2])
+			if err != nil || hours <= 0 {
+				return h.SendError(sess, "Invalid move time: %s", parts[2])
+			}
+			moveTime = time.Duration(hours) * time.Hour
+		}
+		if len(parts) >= 4 {
+			profile, err := tournament.ParseProfile(parts[3])
+			if err != nil {
+				return h.SendError(sess, "Cannot open game: %v", err)
+			}
+			rules = profile.Name
+		}
+		g, err := h.games.Create(sess.Username(), moveTime, rules)
+		if err != nil {
+			return h.SendError(sess, "Cannot open game: %v", err)
+		}
+		log.Printf("[%s] Opened correspondence game %s", sess.ID(), g.ID)
+		return h.sendAsyncGame(sess, g)
+	case AsyncActionVacation:
+		return h.handleVacation(sess, parts)
+	case AsyncActionJoin, AsyncActionLeave, AsyncActionShow:
+		if len(parts) != 3 {
+			return h.SendError(sess, "Invalid async format")
+		}
+	default:
+		return h.SendError(sess, "Invalid async action: %s", action)
+	}
+
+	id := parts[2]
+	switch action {
+	case AsyncActionJoin:
+		h.gamesMu.Lock()
+		g, err := h.games.Join(id, sess.Username(), time.Now())
+		h.gamesMu.Unlock()
+		if err != nil {
+			return h.SendError(sess, "Cannot join game %s: %v", id, err)
+		}
+		log.Printf("[%s] Joined correspondence game %s", sess.ID(), id)
+		h.asyncChanged(g)
+		return nil
+	case AsyncActionLeave:
+		h.gamesMu.Lock()
+		err := h.games.Leave(id, sess.Username())
+		h.gamesMu.Unlock()
+		if err != nil {
+			return h.SendError(sess, "Cannot leave game %s: %v", id, err)
+		}
+		log.Printf("[%s] Left correspondence game %s", sess.ID(), id)
+		if g, ok := h.games.Get(id); ok {
+			h.asyncChanged(g)
+		}
+		return h.SendText(sess, "Left game %s", id)
+	default:
+		g, ok := h.games.Get(id)
+		position, playing := g.Position(sess.Username())
+		if !ok || !playing {
+			return h.SendError(sess, "Unknown table: %s", id)
+		}
+		if !g.Running() {
+			return h.SendError(sess, "Game %s has not started", id)
+		}
+		record, err := g.Record()
+		if err != nil {
+			log.Printf("[%s] Failed to load correspondence game %s: %v", sess.ID(), id, err)
+			return h.SendError(sess, "Game %s not available", id)
+		}
+		view, err := newGameView(id, record, position)
+		if err == nil {
+			var messages []string
+			if messages, err = view.Resume(); err == nil {
+				return h.sendLines(sess, messages)
+			}
+		}
+		log.Printf("[%s] Failed to show correspondence game %s: %v", sess.ID(), id, err)
+		return h.SendError(sess, "Game %s not available", id)
+	}
+}
+
+// handleAsyncTable processes the table messages of a correspondence game: moves of
+// its players and "leave", which only closes the table of the client.
+func (h *Handler) handleAsyncTable(sess *session.Session, parts []string) error {
+	id := parts[1]
+	g, ok := h.games.Get(id)
+	position, playing := g.Position(sess.Username())
+	if !ok || !playing || parts[2] != sess.Username() {
+		return h.SendError(sess, "Unknown table: %s", id)
+	}
+
+	switch parts[3] {
+	case TableActionPlay:
+		if len(parts) < 5 {
+			return h.SendError(sess, "Invalid move")
+		}
+	case TableActionLeave:
+		return sess.WriteLine("%s %s %s %s", MsgTable, id, sess.Username(), TableActionDestroy)
+	case TableActionHint:
+		// Correspondence games are rated
+		return h.SendError(sess, "Hints are only available at practice tables")
+	default:
+		return h.SendError(sess, "Invalid table action: %s", parts[3])
+	}
+
+	h.gamesMu.Lock()
+	defer h.gamesMu.Unlock()
+	// Reload the game, it may have changed before the lock
+	if g, ok = h.games.Get(id); !ok || !g.Running() {
+		return h.SendError(sess, "Game %s has not started", id)
+	}
+	record, err := g.Record()
+	var view *BotTable
+	if err == nil {
+		view, err = newGameView(id, record, position)
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to load correspondence game %s: %v", sess.ID(), id, err)
+		return h.SendError(sess, "Game %s not available", id)
+	}
+
+	applied := len(record.Actions)
+	messages, err := view.Play(strings.Join(parts[4:], " "))
+	if err != nil {
+		// Rejected moves leave the game unchanged but count as protocol violations
+		log.Printf("[%s] Rejected move in correspondence game %s: %v", sess.ID(), id, err)
+		if err := sess.WriteLine("%s %s %s %s %s %s", MsgTable, id, sess.Username(), TableActionError,
+			moveErrorCode(err), err); err != nil {
+			return err
+		}
+		return sess.Violation()
+	}
+	if err := h.asyncMoved(g, view, applied, sess, messages); err != nil {
+		log.Printf("[%s] Failed to save correspondence game %s: %v", sess.ID(), id, err)
+		return h.SendError(sess, "Game %s not available", id)
+	}
+	return nil
+}
+
+// asyncMoved stores the actions of a correspondence game applied to the view since
+// index applied and archives the game when it is over. The messages of the view go to
+// the session of the moving player (nil after a timeout), the actions as seen by the
+// players to their other logged-in sessions. The caller must hold gamesMu.
+func (h *Handler) asyncMoved(g async.Game, view *BotTable, applied int, sess *session.Session, messages []string) error {
+	record, err := view.Record()
+	if err != nil {
+		return err
+	}
+	if view.Finished() {
+		if err := h.archive.Save(record); err != nil {
+			return err
+		}
+	}
+	updated, err := h.games.Update(g.ID, record, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if sess != nil {
+		if err := h.sendLines(sess, messages); err != nil {
+			log.Printf("[%s] Failed to send correspondence game %s: %v", sess.ID(), g.ID, err)
+		}
+	}
+	for _, other := range h.sessionManager.List() {
+		position, ok := g.Position(other.Username())
+		if !ok || other == sess {
+			continue
+		}
+		messages, err := asyncMessages(record, position, applied)
+		if err == nil {
+			err = h.sendLines(other, messages)
+		}
+		if err != nil {
+			log.Printf("[%s] Failed to send correspondence game %s: %v", other.ID(), g.ID, err)
+		}
+	}
+	if updated.Turn != "" {
+		h.asyncChanged(updated)
+	} else {
+		log.Printf("Correspondence game %s is over", g.ID)
+	}
+	return nil
+}
+
+// asyncMessages returns the table messages of the actions of a correspondence game
+// since index from as seen by the player at position, with the end of the game.
+func asyncMessages(record *skat.GameRecord, position skat.Player, from int) ([]string, error) {
+	view, err := newGameView(record.ID, record, position)
+	if err != nil {
+		return nil, err
+	}
+	return view.continueGame(view.messages(from))
+}
+
+// asyncChanged sends the game line of a correspondence game to the logged-in
+// sessions of its players. The player to move is notified via their notify settings
+// at once if they are not logged in.
+func (h *Handler) asyncChanged(g async.Game) {
+	online := false
+	for _, other := range h.sessionManager.List() {
+		if _, ok := g.Position(other.Username()); !ok {
+			continue
+		}
+		online = online || other.Username() == g.Turn
+		if err := h.sendAsyncGame(other, g); err != nil {
+			log.Printf("[%s] Failed to send correspondence game %s: %v", other.ID(), g.ID, err)
+		}
+	}
+	if online || g.Turn == "" || h.notifications == nil {
+		return
+	}
+	// Players on vacation are not disturbed
+	if _, away := h.vacations.Current(g.Turn, time.Now()); away {
+		return
+	}
+	settings, ok := h.notifications.Get(g.Turn)
+	if !ok {
+		return
+	}
+	go func() {
+		text := h.catalog.Sprintf(h.language, "It is your turn in correspondence game %s", g.ID)
+		t := webhook.Turn{Player: g.Turn, Table: g.ID, Game: g.ID, Since: time.Now()}
+		if err := h.notifier.Send(context.Background(), settings, t, text); err != nil {
+			log.Printf("Failed to notify %s of game %s: %v", g.Turn, g.ID, err)
+		}
+	}()
+}
+
+// sendAsyncGames sends the game lines of the games, then "async away <login> <until>"
+// per player of the games on vacation and "async end".
+func (h *Handler) sendAsyncGames(sess *session.Session, lists ...[]async.Game) error {
+	now := time.Now()
+	away := make(map[string]bool)
+	for _, games := range lists {
+		for _, g := range games {
+			if err := h.sendAsyncGame(sess, g); err != nil {
+				return err
+			}
+			for _, login := range g.Players {
+				if _, ok := h.vacations.Current(login, now); ok {
+					away[login] = true
+				}
+			}
+		}
+	}
+	logins := make([]string, 0, len(away))
+	for login := range away {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	for _, login := range logins {
+		if err := h.sendAway(sess, login, now); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s", MsgAsync, AsyncActionEnd)
+}
+
+// sendPendingGames sends the open and running correspondence games of a client that
+// logged in, if there are any.
+func (h *Handler) sendPendingGames(sess *session.Session) error {
+	if h.games == nil {
+		return nil
+	}
+	games := h.games.Games(sess.Username())
+	if len(games) == 0 {
+		return nil
+	}
+	return h.sendAsyncGames(sess, games)
+}
+
+// sendAsyncGame sends "async game <id> <state> <hours> <rules> <deadline> <player>...",
+// the deadline of the move as RFC 3339 ("-" for open games).
+func (h *Handler) sendAsyncGame(sess *session.Session, g async.Game) error {
+	state, deadline := AsyncOpen, "-"
+	if g.Running() {
+		state, deadline = AsyncWait, h.deadline(g).UTC().Format(time.RFC3339)
+		if g.Turn == sess.Username() {
+			state = AsyncTurn
+		}
+	}
+	return sess.WriteLine("%s %s %s %s %d %s %s %s", MsgAsync, AsyncActionGame, g.ID, state,
+		int(g.MoveTime/time.Hour), g.Rules, deadline, strings.Join(g.Players, " "))
+}
+
+// deadline returns the deadline of the player to move of a running game, extended by
+// the vacations of the player during the move.
+func (h *Handler) deadline(g async.Game) time.Time {
+	return h.vacations.Deadline(g.Turn, g.Deadline.Add(-g.MoveTime), g.MoveTime)
+}
+
+// RunAsyncClocks makes the moves of the players of correspondence games who missed
+// their deadline, checking every interval until the context is canceled.
+func (h *Handler) RunAsyncClocks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.expireMoves(now)
+		}
+	}
+}
+
+// expireMoves makes the moves of the players who missed their deadline.
+func (h *Handler) expireMoves(now time.Time) {
+	h.gamesMu.Lock()
+	defer h.gamesMu.Unlock()
+	for _, g := range h.games.Due(now) {
+		if now.Before(h.deadline(g)) {
+			// Paused by a vacation
+			continue
+		}
+		if err := h.expireMove(g); err != nil {
+			log.Printf("Failed to end the move of '%s' in game %s: %v", g.Turn, g.ID, err)
+		}
+	}
+}
+
+// expireMove makes the move of the player of a correspondence game who missed the
+// deadline (see timeOut). A game that cannot go on is archived without result. The
+// caller must hold gamesMu.
+func (h *Handler) expireMove(g async.Game) error {
+	record, err := g.Record()
+	if err != nil {
+		return err
+	}
+	position, _ := g.Position(g.Turn)
+	view, err := newGameView(g.ID, record, position)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Move time of '%s' in game %s is over", g.Turn, g.ID)
+	h.asyncText(g, "Move time of %s in game %s is over", g.Turn, g.ID)
+	applied := len(record.Actions)
+	if err := timeOut(view.Game(), position); err != nil {
+		log.Printf("Correspondence game %s ends without result: %v", g.ID, err)
+		if err := h.archive.Save(record); err != nil {
+			return err
+		}
+		h.asyncText(g, "Game %s ends without result", g.ID)
+		return h.games.Remove(g.ID)
+	}
+	return h.asyncMoved(g, view, applied, nil, nil)
+}
+
+// timeOut makes the move of a player who missed the deadline: a pass in the bidding,
+// the answer to a claim the solver confirms or rejects, the choice of a declarer whose
+// defender left (see settleForfeit) or leaving the trick play, which loses the game
+// for a declarer. Before the trick play the declarer has no move to make for them.
+func timeOut(game *skat.Game, player skat.Player) error {
+	switch {
+	case game.State == skat.StateBidding || game.GrandHandOffer:
+		return game.Pass(player)
+	case game.State != skat.StateTrickPlaying:
+		return errors.New("the declarer has not announced a game")
+	case game.PendingClaim != nil:
+		return answerClaim(game, player)
+	case game.Abandoned != nil:
+		return settleForfeit(game, player)
+	default:
+		return game.Abandon(player)
+	}
+}
+
+// asyncText sends a text message to the logged-in sessions of the players of a
+// correspondence game.
+func (h *Handler) asyncText(g async.Game, format string, args ...interface{}) {
+	for _, other := range h.sessionManager.List() {
+		if _, ok := g.Position(other.Username()); !ok {
+			continue
+		}
+		if err := h.SendText(other, format, args...); err != nil {
+			log.Printf("[%s] Failed to send text: %v", other.ID(), err)
+		}
+	}
+}
+
+// handleVacation processes "async vacation [days|off]": a vacation starts now and
+// pauses the move time of the player in all correspondence games. The days per year
+// are the fewest of the rule profiles of the player's games. The server answers
+// "async vacation <taken> <days> <until>" ("-" if not on vacation) and sends
+// "async away <login> <until>" to the logged-in players of the games.
+func (h *Handler) handleVacation(sess *session.Session, parts []string) error {
+	if len(parts) > 3 {
+		return h.SendError(sess, "Invalid async format")
+	}
+	now := time.Now()
+	if len(parts) == 3 {
+		var err error
+		if parts[2] == AsyncVacationOff {
+			err = h.vacations.End(sess.Username(), now)
+		} else {
+			var days int
+			if days, err = strconv.Atoi(parts[2]); err != nil {
+				return h.SendError(sess, "Invalid async format")
+			}
+			_, err = h.vacations.Start(sess.Username(), days, h.vacationDays(sess.Username()), now)
+		}
+		if err != nil {
+			return h.SendError(sess, "Cannot set vacation: %v", err)
+		}
+		log.Printf("[%s] Vacation of %s: %s", sess.ID(), sess.Username(), parts[2])
+		h.vacationChanged(sess.Username(), now)
+	}
+
+	until := "-"
+	if vacation, ok := h.vacations.Current(sess.Username(), now); ok {
+		until = vacation.End.UTC().Format(time.RFC3339)
+	}
+	return sess.WriteLine("%s %s %d %d %s", MsgAsync, AsyncActionVacation, h.vacations.Taken(sess.Username(), now),
+		h.vacationDays(sess.Username()), until)
+}
+
+// vacationDays returns the vacation days per year of a player: the fewest of the
+// rule profiles of the player's open and running games (0 without games).
+func (h *Handler) vacationDays(login string) int {
+	days := -1
+	for _, g := range h.games.Games(login) {
+		profile, err := tournament.ParseProfile(g.Rules)
+		if err != nil {
+			profile.VacationDays = 0
+		}
+		if days < 0 || profile.VacationDays < days {
+			days = profile.VacationDays
+		}
+	}
+	return max(days, 0)
+}
+
+// vacationChanged sends the vacation status of a player and the game lines with the
+// new deadlines to the logged-in players of the player's games.
+func (h *Handler) vacationChanged(login string, now time.Time) {
+	games := h.games.Games(login)
+	for _, other := range h.sessionManager.List() {
+		playing := false
+		for _, g := range games {
+			if _, ok := g.Position(other.Username()); !ok {
+				continue
+			}
+			playing = true
+			if err := h.sendAsyncGame(other, g); err != nil {
+				log.Printf("[%s] Failed to send correspondence game %s: %v", other.ID(), g.ID, err)
+			}
+		}
+		if !playing {
+			continue
+		}
+		if err := h.sendAway(other, login, now); err != nil {
+			log.Printf("[%s] Failed to send vacation: %v", other.ID(), err)
+		}
+	}
+}
+
+// sendAway sends "async away <login> <until>", the end of the vacation of a player
+// ("-" if the player is not on vacation).
+func (h *Handler) sendAway(sess *session.Session, login string, now time.Time) error {
+	until := "-"
+	if vacation, ok := h.vacations.Current(login, now); ok {
+		until = vacation.End.UTC().Format(time.RFC3339)
+	}
+	return sess.WriteLine("%s %s %s %s", MsgAsync, AsyncActionAway, login, until)
+}
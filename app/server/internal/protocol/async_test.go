@@ -0,0 +1,150 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/async"
+	"github.com/mkloubert/freeskat-server/internal/botpool"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+)
+
+// newAsyncHandler returns a handler with correspondence games in a temporary directory.
+func newAsyncHandler(t *testing.T) *Handler {
+	t.Helper()
+	dir := t.TempDir()
+	games, err := archive.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := async.Open(filepath.Join(dir, "async.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vacations, err := async.OpenVacations(filepath.Join(dir, "vacations.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(session.NewManager(context.Background()), botpool.New("bot", 0, 0, ai.DifficultyBeginner))
+	h.SetArchive(games)
+	h.SetAsync(store, vacations, 24*time.Hour)
+	return h
+}
+
+// startAsyncGame starts a correspondence game of anna, ben and carl dealt at the time.
+func startAsyncGame(t *testing.T, h *Handler, moveTime time.Duration, at time.Time) async.Game {
+	t.Helper()
+	g, err := h.games.Create("anna", moveTime, "isko")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, login := range []string{"ben", "carl"} {
+		if g, err = h.games.Join(g.ID, login, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func TestExpireMoves(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		dealt     time.Duration // before now
+		vacation  time.Duration // of the player to move since the deal, 0 = none
+		endAfter  time.Duration // ends the vacation early after the time, 0 = not
+		wantMoved bool
+	}{
+		{"before the deadline", 30 * time.Minute, 0, 0, false},
+		{"after the deadline", 2 * time.Hour, 0, 0, true},
+		{"paused by a vacation", 2 * time.Hour, 24 * time.Hour, 0, false},
+		{"vacation ended early", 2 * time.Hour, 24 * time.Hour, 30 * time.Minute, true},
+		{"vacation ended too early", 2 * time.Hour, 24 * time.Hour, 90 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newAsyncHandler(t)
+			dealt := now.Add(-tt.dealt)
+			g := startAsyncGame(t, h, time.Hour, dealt)
+			if tt.vacation > 0 {
+				if _, err := h.vacations.Start(g.Turn, int(tt.vacation/(24*time.Hour)), 14, dealt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.endAfter > 0 {
+				if err := h.vacations.End(g.Turn, dealt.Add(tt.endAfter)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			h.expireMoves(now)
+
+			updated, ok := h.games.Get(g.ID)
+			if !ok {
+				t.Fatalf("game %s removed", g.ID)
+			}
+			moved := len(updated.Replay.Moves) > len(g.Replay.Moves)
+			if moved != tt.wantMoved {
+				t.Fatalf("moved %v, want %v", moved, tt.wantMoved)
+			}
+			if !moved {
+				return
+			}
+			// The player who missed the deadline passed, the next player's move time runs
+			if updated.Turn == g.Turn || !updated.Deadline.After(now) {
+				t.Errorf("turn %s until %s after the timeout of %s", updated.Turn, updated.Deadline, g.Turn)
+			}
+		})
+	}
+}
+
+func TestVacationDays(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		want  int
+	}{
+		{"no games", nil, 0},
+		{"isko", []string{"isko"}, 14},
+		{"club", []string{"club"}, 30},
+		{"the fewest", []string{"club", "isko", "club"}, 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newAsyncHandler(t)
+			for _, rules := range tt.rules {
+				if _, err := h.games.Create("anna", time.Hour, rules); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Games of others do not count
+			if _, err := h.games.Create("ben", time.Hour, "isko"); err != nil {
+				t.Fatal(err)
+			}
+			if got := h.vacationDays("anna"); got != tt.want {
+				t.Errorf("vacationDays() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
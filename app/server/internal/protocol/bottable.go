@@ -0,0 +1,594 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/events"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+	"github.com/mkloubert/freeskat-server/pkg/solver"
+)
+
+// BotTable is a virtual table where a client plays a fixed deal against server bots.
+//
+// Like replays, it uses the normal table messages: the client sends its moves as
+// "table <name> <login> play <move>" and receives all moves as
+// "table <name> <login> play <player> <move>". Cards of the bots stay hidden.
+// Observers receive the same messages with all cards hidden until the game end.
+type BotTable struct {
+	// Table is the name of the virtual table
+	Table string
+	// Login is the name of the playing client
+	Login string
+	// Position is the position of the client
+	Position skat.Player
+	// Practice is true for unrated games whose bots come from the bot pool; they are
+	// not archived (see handlePractice)
+	Practice bool
+
+	// host is the table of the client who started the game if a client took the seat
+	// of a bot (see Seat); the tables share the game
+	host *BotTable
+	// guests is the number of clients who took the seat of a bot
+	guests int
+	// mu serializes the moves of the clients sharing the game (see Lock)
+	mu sync.Mutex
+
+	record *skat.GameRecord
+	game   *skat.Game
+	bots   map[skat.Player]ai.AIPlayer
+	// difficulties are the difficulties of the bots (nil = unknown, e.g. scripted bots)
+	difficulties map[skat.Player]ai.Difficulty
+	discards     []skat.Card
+	public       []string
+	events       []events.Event
+	// live are the public events for the watchers of the table (see TakeLive)
+	live []live.Event
+	// narrated is the number of narrated actions; introduced and ended are true once
+	// the deal and the result are narrated
+	narrated   int
+	introduced bool
+	ended      bool
+}
+
+// NewBotTable creates a bot table for the dealt record (ID, players, deal and shuffle;
+// actions are ignored). The bots play all positions except the client's.
+func NewBotTable(table string, record *skat.GameRecord, position skat.Player, bots map[skat.Player]ai.AIPlayer) (*BotTable, error) {
+	game := skat.NewGame()
+	if err := game.Deal(record.Hands, record.Skat); err != nil {
+		return nil, err
+	}
+	for _, p := range skat.AllPlayers {
+		if p != position && bots[p] == nil {
+			return nil, fmt.Errorf("no bot for %s", p)
+		}
+	}
+	return &BotTable{
+		Table:    table,
+		Login:    record.Players[position],
+		Position: position,
+		record:   record,
+		game:     game,
+		bots:     bots,
+	}, nil
+}
+
+// RestoreBotTable recreates a bot table from the record of an unfinished game,
+// e.g. an adjourned game after a server restart. The recorded actions are applied.
+func RestoreBotTable(table string, record *skat.GameRecord, position skat.Player, bots map[skat.Player]ai.AIPlayer) (*BotTable, error) {
+	t, err := NewBotTable(table, record, position, bots)
+	if err != nil {
+		return nil, err
+	}
+	for i, action := range record.Actions {
+		if err := t.game.Apply(action); err != nil {
+			return nil, fmt.Errorf("action %d: %w", i+1, err)
+		}
+	}
+	return t, nil
+}
+
+// newGameView creates the table of a game between clients as seen by the client at
+// position, with the recorded actions applied. No bots move: the game waits whenever
+// it is the turn of a client.
+func newGameView(table string, record *skat.GameRecord, position skat.Player) (*BotTable, error) {
+	game := skat.NewGame()
+	if err := game.Deal(record.Hands, record.Skat); err != nil {
+		return nil, err
+	}
+	t := &BotTable{
+		Table:    table,
+		Login:    record.Players[position],
+		Position: position,
+		record:   record,
+		game:     game,
+	}
+	for i, action := range record.Actions {
+		if err := t.game.Apply(action); err != nil {
+			return nil, fmt.Errorf("action %d: %w", i+1, err)
+		}
+	}
+	return t, nil
+}
+
+// SetClocks makes the game timed with the thinking time of every player for the deal.
+// The bots decide within a share of their remaining time (see ai.ClockBudget). It
+// must be called before Start or Resume.
+func (t *BotTable) SetClocks(clock time.Duration) {
+	t.game.Clocks = make(map[skat.Player]time.Duration)
+	for _, p := range skat.AllPlayers {
+		t.game.Clocks[p] = clock
+	}
+	bots := make(map[skat.Player]ai.AIPlayer)
+	for p, bot := range t.bots {
+		bots[p] = ai.NewDeadlineAI(bot, 0)
+	}
+	t.bots = bots
+}
+
+// SetDifficulties sets the difficulties of the bots, which the table announces after
+// the start. It must be called before Start or Resume.
+func (t *BotTable) SetDifficulties(difficulties map[skat.Player]ai.Difficulty) {
+	t.difficulties = difficulties
+}
+
+// Seat lets a client take the seat of the bot at position. It returns the table of the
+// client, which shares the game: the bot no longer moves, and the game waits for the
+// moves of the client at the returned table. The caller must hold the lock.
+func (t *BotTable) Seat(position skat.Player, login string) (*BotTable, error) {
+	if t.Finished() {
+		return nil, errGameOver
+	}
+	if t.host != nil || t.bots[position] == nil {
+		return nil, fmt.Errorf("no bot at %s", position)
+	}
+	delete(t.bots, position)
+	delete(t.difficulties, position)
+	t.record.Players[position] = login
+	t.guests++
+	t.public = append(t.public, t.message("%s %s %s", TableActionSit, skat.MovePlayerFromPlayer(position), login))
+	return &BotTable{
+		Table:        t.Table,
+		Login:        login,
+		Position:     position,
+		Practice:     t.Practice,
+		host:         t,
+		record:       t.record,
+		game:         t.game,
+		bots:         t.bots,
+		difficulties: t.difficulties,
+	}, nil
+}
+
+// Lock locks the game of the table, which is shared with the tables of clients who
+// took a seat (see Seat).
+func (t *BotTable) Lock() {
+	t.lock().Lock()
+}
+
+// Unlock unlocks the game of the table.
+func (t *BotTable) Unlock() {
+	t.lock().Unlock()
+}
+
+// lock returns the lock of the shared game, held by the table of the host.
+func (t *BotTable) lock() *sync.Mutex {
+	if t.host != nil {
+		return &t.host.mu
+	}
+	return &t.mu
+}
+
+// Guest returns true for the table of a client who took the seat of a bot.
+func (t *BotTable) Guest() bool {
+	return t.host != nil
+}
+
+// Shared returns true if several clients play the game of the table.
+func (t *BotTable) Shared() bool {
+	return t.host != nil || t.guests > 0
+}
+
+// Follow returns the messages of the moves of the other players since index from, as
+// seen at this table, with the end of the game.
+func (t *BotTable) Follow(from int) ([]string, error) {
+	return t.continueGame(t.messages(from))
+}
+
+// Start returns the messages starting the game: table start, the deal (only the
+// client's hand visible) and the bot moves until it is the client's turn.
+func (t *BotTable) Start() ([]string, error) {
+	return t.continueGame(t.startMessages())
+}
+
+// Resume returns the messages continuing a restored game: table start, the deal,
+// all moves played so far and the bot moves until it is the client's turn.
+func (t *BotTable) Resume() ([]string, error) {
+	// Replay the moves from the deal, so every message shows the state at its move
+	actions := t.game.Actions
+	t.rollback(0)
+	messages := t.startMessages()
+	started, watched := len(t.events), len(t.live)
+	for i, action := range actions {
+		if err := t.game.Apply(action); err != nil {
+			return messages, fmt.Errorf("action %d: %w", i+1, err)
+		}
+		messages = append(messages, t.messages(i)...)
+	}
+	// The cards played before the adjournment were already published
+	t.events = t.events[:started]
+	t.live = t.live[:watched]
+	return t.continueGame(messages)
+}
+
+// startMessages returns the table start messages: the start, the bots with their
+// difficulties ("bot <player> <name> <difficulty>") and the deal.
+func (t *BotTable) startMessages() []string {
+	var players []string
+	for _, p := range skat.AllPlayers {
+		players = append(players, t.record.Players[p])
+	}
+
+	deal := make([]string, 0, 4)
+	hidden := make([]string, 0, 4)
+	for _, p := range skat.AllPlayers {
+		if p == t.Position {
+			deal = append(deal, t.game.Hands[p].Code())
+		} else {
+			deal = append(deal, encodeHiddenHand(t.game.Hands[p].Size()))
+		}
+		hidden = append(hidden, encodeHiddenHand(t.game.Hands[p].Size()))
+	}
+	deal = append(deal, encodeHiddenHand(t.game.Skat.Size()))
+	hidden = append(hidden, encodeHiddenHand(t.game.Skat.Size()))
+
+	now := time.Now()
+	seats := make(map[skat.Player]string)
+	for _, p := range skat.AllPlayers {
+		seats[p] = t.record.Players[p]
+		t.events = append(t.events, events.PlayerJoined{Time: now, Table: t.Table, Player: seats[p], Position: p})
+	}
+	t.events = append(t.events, events.GameStarted{Time: now, Table: t.Table, Game: t.record.ID, Players: seats})
+	t.live = append(t.live, live.StartEvent(t.Login, t.record.ID, seats))
+
+	messages := []string{t.message("%s %s", TableActionStart, strings.Join(players, " "))}
+	for _, p := range skat.AllPlayers {
+		if difficulty, ok := t.difficulties[p]; ok && p != t.Position {
+			messages = append(messages, t.message("%s %s %s %s", TableActionBot, skat.MovePlayerFromPlayer(p),
+				t.record.Players[p], difficulty))
+		}
+	}
+	t.public = append(t.public, messages...)
+	t.public = append(t.public, t.play(skat.MoveWorld, strings.Join(hidden, "|")))
+	return append(messages, t.play(skat.MoveWorld, strings.Join(deal, "|")))
+}
+
+// Play applies a move of the client and returns the resulting messages, including
+// the bot moves until it is the client's turn again and the game end.
+func (t *BotTable) Play(token string) ([]string, error) {
+	if t.Finished() {
+		return nil, errGameOver
+	}
+	if active := t.game.ActivePlayer(); active == nil || *active != t.Position {
+		return nil, skat.ErrNotYourTurn
+	}
+
+	actions, err := summaryActions(t.Position, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("move not supported: %s", token)
+	}
+
+	applied := len(t.game.Actions)
+	for _, action := range actions {
+		if err := t.game.Apply(action); err != nil {
+			t.rollback(applied)
+			t.game.StartClock(time.Now())
+			return nil, err
+		}
+	}
+	return t.continueGame(t.messages(applied))
+}
+
+// Hint suggests the next move of the client (see ai.HintTurn).
+func (t *BotTable) Hint() (ai.Hint, error) {
+	if t.Finished() {
+		return ai.Hint{}, errGameOver
+	}
+	if active := t.game.ActivePlayer(); active == nil || *active != t.Position {
+		return ai.Hint{}, skat.ErrNotYourTurn
+	}
+	return ai.HintTurn(t.game)
+}
+
+// errGameOver is returned for moves after the end of the game.
+var errGameOver = errors.New("game is over")
+
+// moveErrorCode returns the error code of a move rejected by Play.
+func moveErrorCode(err error) string {
+	switch {
+	case errors.Is(err, skat.ErrNotYourTurn):
+		return MoveErrorNotYourTurn
+	case errors.Is(err, skat.ErrCardNotInHand):
+		return MoveErrorNotInHand
+	case errors.Is(err, skat.ErrIllegalCard):
+		return MoveErrorIllegalCard
+	case errors.Is(err, skat.ErrOverbid):
+		return MoveErrorOverbid
+	case errors.Is(err, skat.ErrClaimPending):
+		return MoveErrorClaimPending
+	case errors.Is(err, errGameOver):
+		return MoveErrorGameOver
+	default:
+		return MoveErrorInvalid
+	}
+}
+
+// Finished returns true if the game is over.
+func (t *BotTable) Finished() bool {
+	return t.game.State == skat.StateGameOver
+}
+
+// Game returns the game (read only).
+func (t *BotTable) Game() *skat.Game {
+	return t.game
+}
+
+// Record returns the record of the finished game.
+func (t *BotTable) Record() (*skat.GameRecord, error) {
+	record, err := skat.NewGameRecord(t.record.ID, t.record.StartedAt, t.record.Players, t.game)
+	if err != nil {
+		return nil, err
+	}
+	record.Shuffle = t.record.Shuffle
+	return record, nil
+}
+
+// continueGame lets the bots move until it is the turn of a client or the game is over,
+// then appends the end message, after the untouched skat of a Hand game. A deal that
+// all players passed and that was thrown in ends without tricks.
+func (t *BotTable) continueGame(messages []string) ([]string, error) {
+	for !t.game.State.IsFinished() {
+		active := t.game.ActivePlayer()
+		if active == nil {
+			return messages, fmt.Errorf("no active player in state %s", t.game.State)
+		}
+		// The clock of the client runs once it has the messages
+		t.game.StartClock(time.Now())
+		if *active == t.Position || t.bots[*active] == nil {
+			return messages, nil
+		}
+
+		applied := len(t.game.Actions)
+		var err error
+		switch {
+		case t.game.PendingClaim != nil:
+			err = answerClaim(t.game, *active)
+		case t.game.Abandoned != nil:
+			err = settleForfeit(t.game, *active)
+		default:
+			err = t.botTurn(*active)
+		}
+		if err != nil {
+			return messages, fmt.Errorf("%s: %w", *active, err)
+		}
+		messages = append(messages, t.messages(applied)...)
+	}
+
+	record, err := t.Record()
+	if err != nil {
+		return messages, err
+	}
+	summary, err := NewGameSummary(record)
+	if err != nil {
+		return messages, err
+	}
+	if skatCards := t.game.HandSkat(); skatCards != nil {
+		reveal := t.message("%s %s", TableActionSkat, skatCards.Code())
+		messages = append(messages, reveal)
+		t.public = append(t.public, reveal)
+	}
+	end := t.message("%s %s", TableActionEnd, summary.Encode())
+	t.public = append(t.public, end)
+	t.events = append(t.events, events.GameFinished{Time: time.Now(), Table: t.Table, Record: record,
+		Result: t.game.Result, Ramsch: t.game.RamschResult})
+	t.live = append(t.live, live.ResultEvent(t.Login, t.record.ID, t.game))
+	return append(messages, end), nil
+}
+
+// botTurn lets the bot at the active position decide, within its share of the
+// remaining thinking time in a timed game.
+func (t *BotTable) botTurn(active skat.Player) error {
+	bot := t.bots[active]
+	if deadline, ok := bot.(*ai.DeadlineAI); ok && t.game.Clocks != nil {
+		timed, cancel := deadline.WithClock(t.game.Remaining(active, time.Now()), t.game.Hands[active].Size())
+		defer cancel()
+		return ai.PlayTurn(t.game, timed)
+	}
+	return ai.PlayTurn(t.game, bot)
+}
+
+// answerClaim lets a bot accept the claim or concession of the client if the solver
+// confirms it; otherwise the bot rejects it and the play continues.
+func answerClaim(game *skat.Game, player skat.Player) error {
+	if holds, err := solver.ClaimHolds(game); err == nil && holds {
+		return game.AcceptClaim(player)
+	}
+	return game.RejectClaim(player)
+}
+
+// settleForfeit lets the bot declarer of a game the client abandoned claim the
+// remaining tricks if the solver confirms them; otherwise the deal is replayed.
+func settleForfeit(game *skat.Game, player skat.Player) error {
+	if holds, err := solver.ClaimHolds(game); err == nil && holds {
+		return game.Claim(player)
+	}
+	return game.Redeal(player)
+}
+
+// Forfeitable returns true if leaving forfeits the game: during the trick play of a
+// game with a declarer.
+func (t *BotTable) Forfeitable() bool {
+	g := t.game
+	return g.State == skat.StateTrickPlaying && g.Declarer != nil && g.Abandoned == nil
+}
+
+// Forfeit lets the client abandon the game and returns the resulting messages: a lost
+// game for a declarer, otherwise the bot declarer claims the rest or replays the deal.
+func (t *BotTable) Forfeit() ([]string, error) {
+	applied := len(t.game.Actions)
+	if err := t.game.Abandon(t.Position); err != nil {
+		return nil, err
+	}
+	return t.continueGame(t.messages(applied))
+}
+
+// TakePublic returns the messages for observers since the last call: the messages of
+// the client with the deal, the skat and the discarded cards hidden.
+func (t *BotTable) TakePublic() []string {
+	public := t.public
+	t.public = nil
+	return public
+}
+
+// TakeEvents returns the events of the table since the last call.
+func (t *BotTable) TakeEvents() []events.Event {
+	taken := t.events
+	t.events = nil
+	return taken
+}
+
+// TakeLive returns the public events for the watchers of the table since the last
+// call. The events of a bot table are published under the login of its client.
+func (t *BotTable) TakeLive() []live.Event {
+	taken := t.live
+	t.live = nil
+	return taken
+}
+
+// messages returns the table messages of the actions applied since index from and
+// adds their observer messages and events. The skat and discarded cards are only
+// shown to the client if it is the declarer.
+func (t *BotTable) messages(from int) []string {
+	var messages []string
+	played := 0
+	for _, action := range t.game.Actions[:from] {
+		if action.Type == skat.ActionPlayCard {
+			played++
+		}
+	}
+	for i, action := range t.game.Actions[from:] {
+		t.live = append(t.live, live.ActionEvents(t.Login, t.record.ID, t.game, from+i)...)
+		player := skat.MovePlayerFromPlayer(action.Player)
+		own := action.Player == t.Position
+
+		var token string
+		switch action.Type {
+		case skat.ActionBid:
+			token = strconv.Itoa(action.Value)
+		case skat.ActionHold:
+			token = TokenHoldBid
+		case skat.ActionPass:
+			token = TokenPass
+		case skat.ActionPickUpSkat:
+			skatCards := encodeHiddenHand(t.game.DealtSkat.Size())
+			if own {
+				skatCards = t.game.DealtSkat.Code()
+			}
+			request := t.play(player, TokenSkatRequest)
+			hidden := t.play(skat.MoveWorld, encodeHiddenHand(t.game.DealtSkat.Size()))
+			messages = append(messages, request, t.play(skat.MoveWorld, skatCards))
+			t.public = append(t.public, request, hidden)
+			continue
+		case skat.ActionDiscard:
+			// Sent with the announcement
+			t.discards = action.Cards
+			continue
+		case skat.ActionAnnounce:
+			var discards []skat.Card
+			if own {
+				discards = t.discards
+			}
+			token = announcementToken(action, discards, t.game.Hands[action.Player])
+			t.public = append(t.public, t.play(player, announcementToken(action, nil, t.game.Hands[action.Player])))
+			messages = append(messages, t.play(player, token))
+			// The open hand is shown before the first card, also to later observers
+			if open := t.game.OpenHand(); open != nil {
+				reveal := t.message("%s %s %s", TableActionOuvert, player, open.Code())
+				messages = append(messages, reveal)
+				t.public = append(t.public, reveal)
+			}
+			continue
+		case skat.ActionClaim, skat.ActionConcede, skat.ActionAcceptClaim, skat.ActionRejectClaim,
+			skat.ActionAbandon, skat.ActionRedeal:
+			token = claimTokens[action.Type]
+		case skat.ActionPlayCard:
+			token = action.Cards[0].Code()
+			played++
+			t.events = append(t.events, events.CardPlayed{Time: action.Time, Table: t.Table,
+				Player: t.record.Players[action.Player], Position: action.Player, Card: action.Cards[0],
+				Trick: (played + 2) / 3})
+		}
+		message := t.play(player, token)
+		messages = append(messages, message)
+		t.public = append(t.public, message)
+	}
+	return messages
+}
+
+// rollback restores the game state after the first n actions (after a partly applied move).
+func (t *BotTable) rollback(n int) {
+	game := skat.NewGame()
+	if err := game.Deal(t.record.Hands, t.record.Skat); err != nil {
+		return
+	}
+	game.Clocks = t.game.Clocks
+	for _, action := range t.game.Actions[:n] {
+		if err := game.Apply(action); err != nil {
+			return
+		}
+	}
+	// In place, since the tables of a shared game point to it
+	*t.game = *game
+}
+
+// play formats a move message. Moves of a timed game carry the remaining thinking
+// times of the players in seconds: "play <player> <move> <time0> <time1> <time2>".
+func (t *BotTable) play(player skat.MovePlayer, token string) string {
+	message := t.message("%s %s %s", TableActionPlay, player, token)
+	if t.game.Clocks == nil {
+		return message
+	}
+	now := time.Now()
+	for _, p := range skat.AllPlayers {
+		message += fmt.Sprintf(" %.1f", t.game.Remaining(p, now).Seconds())
+	}
+	return message
+}
+
+// message formats a table message of the bot table.
+func (t *BotTable) message(format string, args ...interface{}) string {
+	return fmt.Sprintf("%s %s %s %s", MsgTable, t.Table, t.Login, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/challenge"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// SetChallenges sets the tracker of the daily and weekly challenges (requires an archive).
+func (h *Handler) SetChallenges(tracker *challenge.Tracker) {
+	h.challenges = tracker
+}
+
+// handleChallenge lists the running challenges with the progress of the client:
+// "challenge task <id> <period> <progress>/<target> <ends> <text>" per challenge, daily
+// first, followed by "challenge end". <ends> is the end of the period (RFC 3339).
+func (h *Handler) handleChallenge(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.challenges == nil {
+		return h.SendError(sess, "No challenges available")
+	}
+	if len(parts) > 1 {
+		return h.SendError(sess, "Invalid challenge format")
+	}
+
+	progress, err := h.challenges.Progress(sess.Username(), time.Now())
+	if err != nil {
+		log.Printf("[%s] Failed to load challenges: %v", sess.ID(), err)
+		return h.SendError(sess, "No challenges available")
+	}
+	for _, p := range progress {
+		if err := sess.WriteLine("%s %s %s %s %d/%d %s %s", MsgChallenge, ChallengeActionTask, p.ID, p.Period,
+			p.Progress, p.Target, p.Ends.UTC().Format(time.RFC3339), h.text(sess, p.Description(), p.Target)); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s", MsgChallenge, ChallengeActionEnd)
+}
+
+// ChallengesCompleted notifies the logged-in sessions of the players of completed
+// challenges: "challenge completed <id> <text>".
+func (h *Handler) ChallengesCompleted(completions []challenge.Completion) {
+	for _, c := range completions {
+		log.Printf("Challenge %s completed by %s", c.Challenge.ID, c.Login)
+		for _, other := range h.sessionManager.List() {
+			if other.Username() != c.Login {
+				continue
+			}
+			if err := other.WriteLine("%s %s %s %s", MsgChallenge, ChallengeActionCompleted, c.Challenge.ID,
+				h.text(other, c.Challenge.Description(), c.Challenge.Target)); err != nil {
+				log.Printf("[%s] Failed to send challenge: %v", other.ID(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/challenge"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+func TestChallengeCommand(t *testing.T) {
+	tracker, err := challenge.Open("", "secret", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	anna, ben, guest := newTestClient(t, m, "anna"), newTestClient(t, m, "ben"), newTestClient(t, m, "")
+
+	if err := h.handleMessage(anna.sess, "challenge"); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("No challenges available") {
+		t.Error("challenges without a tracker")
+	}
+	h.SetChallenges(tracker)
+	commands := []struct {
+		client *testClient
+		line   string
+		want   string
+	}{
+		{guest, "challenge", "Login required"},
+		{anna, "challenge all", "Invalid challenge format"},
+		{anna, "challenge", "challenge end"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(c.client.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !c.client.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+
+	current, _ := tracker.Current(time.Now())
+	anna.mu.Lock()
+	var tasks []string
+	for _, line := range anna.lines {
+		if strings.HasPrefix(line, "challenge task ") {
+			tasks = append(tasks, line)
+		}
+	}
+	anna.mu.Unlock()
+	if len(tasks) != len(current) {
+		t.Fatalf("received %d tasks, want %d", len(tasks), len(current))
+	}
+	first := current[0]
+	want := fmt.Sprintf("challenge task %s daily 0/%d %s %s", first.ID, first.Target, first.Ends.UTC().Format(time.RFC3339), first)
+	if tasks[0] != want {
+		t.Errorf("first task = %q, want %q", tasks[0], want)
+	}
+
+	// Completions go to the player only, in its language
+	anna.sess.SetLang("de")
+	h.ChallengesCompleted([]challenge.Completion{{Login: "anna", Challenge: challenge.Challenge{ID: "2025-03-14-1", Kind: challenge.KindPlay, Target: 3}}})
+	if !anna.received("challenge completed 2025-03-14-1 Spiele 3 Spiele") {
+		t.Error("anna was not told about the completed challenge")
+	}
+	ben.mu.Lock()
+	defer ben.mu.Unlock()
+	if len(ben.lines) != 0 {
+		t.Errorf("ben received %q", ben.lines)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/ws"
+)
+
+// maxClientToken is the maximum length of the client name and version.
+const maxClientToken = 32
+
+// handleClient processes the self-identification of a client after login:
+// "client <name> <version> [feature...]". The server answers "client [feature...]" with
+// the features it enabled and switches the line stream of TCP clients after the answer:
+// deflate compresses it, json encodes it as JSON messages (see WEBSOCKET-JSON.md).
+// Unknown features are ignored.
+func (h *Handler) handleClient(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if len(parts) < 3 || len(parts[1]) > maxClientToken || len(parts[2]) > maxClientToken {
+		return h.SendError(sess, "Invalid client format")
+	}
+	if sess.Client() != nil {
+		return h.SendError(sess, "Client already identified")
+	}
+
+	client := &session.Client{Name: parts[1], Version: parts[2]}
+	for _, feature := range parts[3:] {
+		if supportsFeature(sess, feature) && !client.Has(feature) {
+			client.Features = append(client.Features, feature)
+		}
+	}
+	sess.SetClient(client)
+	log.Printf("[%s] Client %s %s (features: %s)", sess.ID(), client.Name, client.Version, strings.Join(client.Features, ", "))
+
+	if err := sess.WriteLine("%s", strings.Join(append([]string{MsgClient}, client.Features...), " ")); err != nil {
+		return err
+	}
+	if client.Has(ClientFeatureDeflate) {
+		if err := sess.Wrap(session.Deflate); err != nil {
+			return err
+		}
+	}
+	if client.Has(ClientFeatureJSON) {
+		return sess.Wrap(ws.JSONStream)
+	}
+	return nil
+}
+
+// supportsFeature reports whether a client feature can be enabled for the session. The
+// WebSocket transport negotiates its encoding with the subprotocol.
+func supportsFeature(sess *session.Session, feature string) bool {
+	switch feature {
+	case ClientFeatureJSON, ClientFeatureDeflate:
+		return sess.Transport == session.TransportTCP
+	}
+	return false
+}
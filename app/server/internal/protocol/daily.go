@@ -0,0 +1,262 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// handleDaily processes the deal of the day commands:
+//
+//	daily [play]                  plays today's deal against bots (once per day)
+//	daily leaderboard [date]      lists the leaderboard of a day (default today)
+func (h *Handler) handleDaily(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.daily == nil || h.archive == nil {
+		return h.SendError(sess, "No daily deal available")
+	}
+
+	action := DailyActionPlay
+	if len(parts) >= 2 {
+		action = parts[1]
+	}
+	switch action {
+	case DailyActionPlay:
+		return h.startDaily(sess)
+	case DailyActionLeaderboard:
+		date := h.daily.Date(time.Now())
+		if len(parts) >= 3 {
+			date = parts[2]
+		}
+		return h.sendLeaderboard(sess, date)
+	default:
+		return h.SendError(sess, "Invalid daily action: %s", action)
+	}
+}
+
+// startDaily starts the client's game of today's deal at a bot table "daily-<date>".
+func (h *Handler) startDaily(sess *session.Session) error {
+	deal, err := h.daily.Today()
+	if err != nil {
+		log.Printf("[%s] Failed to generate the daily deal: %v", sess.ID(), err)
+		return h.SendError(sess, "No daily deal available")
+	}
+
+	played, err := h.archive.Records(archive.Filter{Prefix: daily.GamePrefix(deal.Date), Player: sess.Username()})
+	if err != nil {
+		log.Printf("[%s] Failed to load daily games: %v", sess.ID(), err)
+		return h.SendError(sess, "No daily deal available")
+	}
+	if len(played) > 0 {
+		return h.SendError(sess, "Daily deal of %s already played", deal.Date)
+	}
+	if table := h.botTable(sess); table != nil {
+		return h.SendError(sess, "Already playing at table %s", table.Table)
+	}
+
+	record := deal.Record(sess.Username(), time.Now())
+	table, err := NewBotTable("daily-"+deal.Date, record, deal.Position, deal.Bots())
+	if err != nil {
+		log.Printf("[%s] Failed to create the daily table: %v", sess.ID(), err)
+		return h.SendError(sess, "No daily deal available")
+	}
+	if h.dailyClock > 0 {
+		table.SetClocks(h.dailyClock)
+	}
+	table.SetDifficulties(deal.Difficulties())
+	h.setBotTable(sess, table)
+
+	log.Printf("[%s] Playing the daily deal of %s", sess.ID(), deal.Date)
+	messages, err := table.Start()
+	if err != nil {
+		log.Printf("[%s] Daily game failed: %v", sess.ID(), err)
+		h.leaveBotTable(sess)
+		return h.SendError(sess, "Daily game failed")
+	}
+	return h.sendBotMessages(sess, table, messages)
+}
+
+// sendLeaderboard sends the leaderboard of a day:
+// "daily entry <date> <rank> <player> <score> <points> <contract> <declarer|defender>"
+// per player, followed by "daily end <date>".
+func (h *Handler) sendLeaderboard(sess *session.Session, date string) error {
+	if !daily.ValidDate(date) {
+		return h.SendError(sess, "Invalid date: %s", date)
+	}
+
+	records, err := h.archive.Records(archive.Filter{Prefix: daily.GamePrefix(date)})
+	if err != nil {
+		log.Printf("[%s] Failed to load daily games: %v", sess.ID(), err)
+		return h.SendError(sess, "Leaderboard not available")
+	}
+	entries, err := daily.Leaderboard(records)
+	if err != nil {
+		log.Printf("[%s] Failed to rank daily games: %v", sess.ID(), err)
+		return h.SendError(sess, "Leaderboard not available")
+	}
+
+	for _, e := range entries {
+		contract, role := "-", "defender"
+		if e.Contract != "" {
+			contract = e.Contract
+		}
+		if e.Declarer {
+			role = "declarer"
+		}
+		if err := sess.WriteLine("%s %s %s %d %s %d %d %s %s", MsgDaily, DailyActionEntry, date,
+			e.Rank, e.Player, e.Score, e.Points, contract, role); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgDaily, DailyActionEnd, date)
+}
+
+// handleBotTable processes "table <name> <login> play <move>", "... hint",
+// "... tell <text>", "... quick <number>" and "... leave" at a bot table.
+func (h *Handler) handleBotTable(sess *session.Session, table *BotTable, parts []string) error {
+	switch parts[3] {
+	case TableActionPlay:
+		if len(parts) < 5 {
+			return h.SendError(sess, "Invalid move")
+		}
+		// Clients who took a seat at the table share the game
+		table.Lock()
+		defer table.Unlock()
+		applied := len(table.game.Actions)
+		messages, err := table.Play(strings.Join(parts[4:], " "))
+		if err != nil {
+			// Rejected moves leave the game unchanged but count as protocol violations
+			log.Printf("[%s] Rejected move at table %s: %v", sess.ID(), table.Table, err)
+			if err := sess.WriteLine("%s %s %s %s %s %s", MsgTable, table.Table, table.Login, TableActionError,
+				moveErrorCode(err), err); err != nil {
+				return err
+			}
+			return sess.Violation()
+		}
+		h.sendSeated(sess, table, applied)
+		return h.sendBotMessages(sess, table, messages)
+	case TableActionHint:
+		table.Lock()
+		defer table.Unlock()
+		return h.sendHint(sess, table)
+	case TableActionTell, TableActionQuick:
+		h.mu.Lock()
+		a := h.audiences[sess.ID()]
+		h.mu.Unlock()
+		if a == nil {
+			return h.SendError(sess, "No observers at table %s", table.Table)
+		}
+		if parts[3] == TableActionQuick {
+			return h.quickTell(sess, a, parts)
+		}
+		return h.tell(sess, a, parts)
+	case TableActionLeave:
+		table.Lock()
+		h.leaveBotTable(sess)
+		table.Unlock()
+		return sess.WriteLine("%s %s %s %s", MsgTable, table.Table, table.Login, TableActionDestroy)
+	default:
+		return h.SendError(sess, "Invalid table action: %s", parts[3])
+	}
+}
+
+// sendBotMessages sends the messages of a bot table, publishes them to the observers
+// and the event stream and archives the game when it is over. The game then waits for
+// the move of the player (see trackTurn).
+func (h *Handler) sendBotMessages(sess *session.Session, table *BotTable, messages []string) error {
+	if h.notifications != nil {
+		h.trackTurn(table)
+	}
+	if table.Guest() {
+		// The observers and the event stream follow the table of the host
+		table.TakePublic()
+		table.TakeEvents()
+		table.TakeLive()
+	} else {
+		h.publish(sess, table)
+		h.stream.Publish(table.TakeEvents()...)
+		h.publishLive(table)
+	}
+	if table.Finished() {
+		h.leaveBotTable(sess)
+	}
+	if err := h.sendLines(sess, messages); err != nil {
+		return err
+	}
+	return h.sendNarration(sess, table)
+}
+
+// leaveBotTable removes the bot table of the session and archives its game. Unfinished
+// games are archived too, so a deal cannot be restarted by leaving. The other clients
+// playing the game leave it too (see endShared). Practice games are not archived; their
+// bots return to the pool.
+func (h *Handler) leaveBotTable(sess *session.Session) {
+	h.mu.Lock()
+	table := h.tables[sess.ID()]
+	delete(h.tables, sess.ID())
+	h.mu.Unlock()
+
+	h.closeAudience(sess.ID())
+	if table == nil {
+		return
+	}
+	h.forgetTurn(table.Login)
+	if table.Shared() {
+		h.endShared(table)
+	}
+	if table.Practice {
+		h.botPool.ReleaseTable(table.record.ID)
+		return
+	}
+	if h.archive == nil {
+		return
+	}
+	record, err := table.Record()
+	if err == nil {
+		err = h.archive.Save(record)
+	}
+	if err == nil && h.archive.IsAdjourned(record.ID) {
+		err = h.archive.SetAdjourned(record.ID, false)
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to archive game of table %s: %v", sess.ID(), table.Table, err)
+		return
+	}
+	if h.mistakeLoss > 0 && table.Finished() {
+		go h.analyzeGame(sess, record, table.Position)
+	}
+}
+
+// botTable returns the bot table of the session.
+func (h *Handler) botTable(sess *session.Session) *BotTable {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.tables[sess.ID()]
+}
+
+// setBotTable sets the bot table of the session.
+func (h *Handler) setBotTable(sess *session.Session, table *BotTable) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tables[sess.ID()] = table
+}
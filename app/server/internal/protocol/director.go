@@ -0,0 +1,125 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// handleTournamentDirector processes the director commands of a tournament:
+//
+//	tournament assist <name> <login>                              designates an assistant director (director only)
+//	tournament pause <name> <series> <table>                      pauses a table
+//	tournament resume <name> <series> <table>                     resumes a paused table
+//	tournament adjust <name> <series> <player> <score> <reason>   corrects the score of a player in a series
+//	tournament substitute <name> <series> <table> <deal> <out> <in>  seats a substitute from a deal on
+//	tournament extend <name> <series> <table> <seconds>           extends the thinking time of a table
+//	tournament disqualify <name> <player> <reason>                disqualifies a player
+//	tournament audit <name>                                       lists the director actions
+//
+// The actions are recorded in the audit trail of the tournament.
+func (h *Handler) handleTournamentDirector(sess *session.Session, action, name string, args []string) error {
+	// numbers parses the first n arguments as numbers
+	numbers := func(n int) ([]int, bool) {
+		if len(args) < n {
+			return nil, false
+		}
+		values := make([]int, n)
+		for i := range values {
+			v, err := strconv.Atoi(args[i])
+			if err != nil {
+				return nil, false
+			}
+			values[i] = v
+		}
+		return values, true
+	}
+
+	var err error
+	switch action {
+	case TournamentActionAssist:
+		if len(args) < 1 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		err = h.tournaments.AddAssistant(name, sess.Username(), args[0])
+	case TournamentActionPause, TournamentActionResume:
+		n, ok := numbers(2)
+		if !ok {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		err = h.tournaments.PauseTable(name, sess.Username(), n[0], n[1], action == TournamentActionPause)
+	case TournamentActionAdjust:
+		if len(args) < 4 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		series, err1 := strconv.Atoi(args[0])
+		score, err2 := strconv.Atoi(args[2])
+		if err1 != nil || err2 != nil {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		err = h.tournaments.Adjust(name, sess.Username(), series, args[1], score, strings.Join(args[3:], " "))
+	case TournamentActionSubstitute:
+		n, ok := numbers(3)
+		if !ok || len(args) < 5 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		err = h.tournaments.Substitute(name, sess.Username(), n[0], n[1], n[2], args[3], args[4])
+	case TournamentActionExtend:
+		n, ok := numbers(3)
+		if !ok {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		err = h.tournaments.ExtendClock(name, sess.Username(), n[0], n[1], n[2])
+	case TournamentActionDisqualify:
+		if len(args) < 2 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		err = h.tournaments.Disqualify(name, sess.Username(), args[0], strings.Join(args[1:], " "))
+	case TournamentActionAudit:
+		return h.sendTournamentAudit(sess, name)
+	}
+	if err != nil {
+		return h.SendError(sess, "Cannot %s: %v", action, err)
+	}
+
+	log.Printf("[%s] Tournament %s: %s %s", sess.ID(), name, action, strings.Join(args, " "))
+	return h.SendText(sess, "Tournament %s: %s done", name, action)
+}
+
+// sendTournamentAudit sends the audit trail of a tournament to its directors:
+// "tournament audit <name> <time> <director> <action> <details>" per action, oldest
+// first, followed by "tournament end <name>".
+func (h *Handler) sendTournamentAudit(sess *session.Session, name string) error {
+	t, err := h.tournaments.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	if !t.IsDirector(sess.Username()) {
+		return h.SendError(sess, "Only the tournament directors can see the audit trail")
+	}
+
+	for _, entry := range t.Audit {
+		if err := sess.WriteLine("%s %s %s %s %s %s %s", MsgTournament, TournamentActionAudit,
+			name, entry.Time.UTC().Format(time.RFC3339), entry.Director, entry.Action, entry.Details); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgTournament, TournamentActionEnd, name)
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// handleDuplicate processes the commands of the duplicate set (see SetDuplicate):
+//
+//	duplicate play   plays the next deal of the client's table of the set
+//
+// The first player of a table to play a deal deals it at the table "<set>". Bots stand
+// in for the other players of the table until they take their seats with "duplicate
+// play" while the deal runs.
+func (h *Handler) handleDuplicate(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.duplicate == nil || h.archive == nil {
+		return h.SendError(sess, "No duplicate set available")
+	}
+
+	action := DuplicateActionPlay
+	if len(parts) >= 2 {
+		action = parts[1]
+	}
+	switch action {
+	case DuplicateActionPlay:
+		return h.playDuplicate(sess)
+	default:
+		return h.SendError(sess, "Invalid duplicate action: %s", action)
+	}
+}
+
+// playDuplicate seats the client at the running deal of its table of the duplicate
+// set or deals the next deal not yet played at the table.
+func (h *Handler) playDuplicate(sess *session.Session) error {
+	if table := h.botTable(sess); table != nil {
+		return h.SendError(sess, "Already playing at table %s", table.Table)
+	}
+	cfg := h.duplicate.Config()
+	table := -1
+	for i, players := range cfg.Tables {
+		for _, name := range players {
+			if name == sess.Username() {
+				table = i
+			}
+		}
+	}
+	if table < 0 {
+		return h.SendError(sess, "You do not play in duplicate set %s", cfg.Name)
+	}
+
+	for deal := 0; deal < cfg.Deals; deal++ {
+		id := h.duplicate.GameID(table, deal)
+		if host := h.runningGame(id); host != nil {
+			return h.joinDuplicate(sess, host)
+		}
+		_, err := h.archive.Load(id)
+		if errors.Is(err, archive.ErrNotFound) {
+			return h.startDuplicate(sess, table, deal)
+		}
+		if err != nil {
+			log.Printf("[%s] Failed to load duplicate game %s: %v", sess.ID(), id, err)
+			return h.SendError(sess, "No duplicate set available")
+		}
+	}
+	return h.SendError(sess, "All deals of duplicate set %s played", cfg.Name)
+}
+
+// runningGame returns the table of the client who started the running game (nil if
+// none).
+func (h *Handler) runningGame(id string) *BotTable {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, table := range h.tables {
+		if table.record.ID == id && !table.Guest() {
+			return table
+		}
+	}
+	return nil
+}
+
+// startDuplicate deals a deal of the duplicate set at the client's table. The bots
+// standing in for the other players are seeded with the deal, so they answer the same
+// moves the same way at every table. The other players of the table who are online
+// are told how to take their seats.
+func (h *Handler) startDuplicate(sess *session.Session, table, deal int) error {
+	record := h.duplicate.Record(table, deal, time.Now())
+	position := skat.Player(-1)
+	bots := make(map[skat.Player]ai.AIPlayer)
+	difficulties := make(map[skat.Player]ai.Difficulty)
+	for _, p := range skat.AllPlayers {
+		if record.Players[p] == sess.Username() {
+			position = p
+			continue
+		}
+		bots[p] = ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(record.Shuffle.Seed+int64(p.Index()))))
+		difficulties[p] = ai.DifficultyStrong
+	}
+
+	name := h.duplicate.Config().Name
+	t, err := NewBotTable(name, record, position, bots)
+	if err != nil {
+		log.Printf("[%s] Failed to deal duplicate game %s: %v", sess.ID(), record.ID, err)
+		return h.SendError(sess, "Duplicate game failed")
+	}
+	t.SetDifficulties(difficulties)
+	// The other players may take their seats once the table is registered
+	t.Lock()
+	defer t.Unlock()
+	h.mu.Lock()
+	for _, other := range h.tables {
+		if other.record.ID == record.ID {
+			// Dealt by another player of the table in the meantime
+			h.mu.Unlock()
+			return h.SendError(sess, "Deal %d of duplicate set %s is already dealt, take your seat with 'duplicate play'",
+				deal+1, name)
+		}
+	}
+	h.tables[sess.ID()] = t
+	h.mu.Unlock()
+
+	log.Printf("[%s] Playing duplicate game %s", sess.ID(), record.ID)
+	for _, p := range skat.AllPlayers {
+		if p == position {
+			continue
+		}
+		for _, other := range h.sessionManager.List() {
+			if other.Username() == record.Players[p] {
+				if err := h.SendText(other, "%s dealt deal %d of duplicate set %s, take your seat with 'duplicate play'",
+					sess.Username(), deal+1, name); err != nil {
+					log.Printf("[%s] Failed to announce duplicate game %s: %v", other.ID(), record.ID, err)
+				}
+			}
+		}
+	}
+
+	messages, err := t.Start()
+	if err != nil {
+		log.Printf("[%s] Duplicate game failed: %v", sess.ID(), err)
+		h.leaveBotTable(sess)
+		return h.SendError(sess, "Duplicate game failed")
+	}
+	return h.sendBotMessages(sess, t, messages)
+}
+
+// joinDuplicate lets the client take its seat at the running deal of its table, which
+// a bot plays until then (see seatClient).
+func (h *Handler) joinDuplicate(sess *session.Session, host *BotTable) error {
+	host.Lock()
+	defer host.Unlock()
+	if host.Finished() {
+		return h.SendError(sess, "No free seat at table %s", host.Table)
+	}
+	for _, p := range skat.AllPlayers {
+		if host.record.Players[p] == sess.Username() && host.bots[p] != nil {
+			log.Printf("[%s] Taking the seat at duplicate game %s", sess.ID(), host.record.ID)
+			return h.seatClient(sess, host, p)
+		}
+	}
+	return h.SendError(sess, "No free seat at table %s", host.Table)
+}
@@ -0,0 +1,199 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// testClient is a logged-in session whose messages are collected.
+type testClient struct {
+	sess  *session.Session
+	mu    sync.Mutex
+	lines []string
+}
+
+// newTestClient logs in a session of the manager over a pipe ("" = not logged in).
+func newTestClient(t *testing.T, m *session.Manager, login string) *testClient {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	c := &testClient{sess: m.CreateSession(server)}
+	if login != "" {
+		m.Login(c.sess, login)
+	}
+	go func() {
+		lines := bufio.NewScanner(client)
+		for lines.Scan() {
+			c.mu.Lock()
+			c.lines = append(c.lines, lines.Text())
+			c.mu.Unlock()
+		}
+	}()
+	return c
+}
+
+// received returns true once the client received a line containing text.
+func (c *testClient) received(text string) bool {
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		c.mu.Lock()
+		for _, line := range c.lines {
+			if strings.Contains(line, text) {
+				c.mu.Unlock()
+				return true
+			}
+		}
+		c.mu.Unlock()
+	}
+	return false
+}
+
+func newDuplicateHandler(t *testing.T) (*Handler, *session.Manager, *duplicate.Set) {
+	t.Helper()
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := duplicate.NewSet(&duplicate.Config{
+		Name:   "cup",
+		Seed:   7,
+		Deals:  1,
+		Tables: [][3]string{{"anna", "ben", "carl"}, {"dora", "emil", "fritz"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	h.SetArchive(games)
+	h.SetDuplicate(set)
+	return h, m, set
+}
+
+func TestDuplicatePlay(t *testing.T) {
+	h, m, set := newDuplicateHandler(t)
+	anna := newTestClient(t, m, "anna")
+	ben := newTestClient(t, m, "ben")
+	gerd := newTestClient(t, m, "gerd")
+
+	if err := h.handleDuplicate(gerd.sess, []string{CmdDuplicate, DuplicateActionPlay}); err != nil {
+		t.Fatal(err)
+	}
+	if !gerd.received("You do not play in duplicate set cup") {
+		t.Error("gerd was not refused")
+	}
+
+	// anna deals the deal of her table, ben takes his seat from the bot standing in
+	if err := h.handleDuplicate(anna.sess, []string{CmdDuplicate, DuplicateActionPlay}); err != nil {
+		t.Fatal(err)
+	}
+	if !ben.received("anna dealt deal 1 of duplicate set cup") {
+		t.Error("ben was not told about the deal")
+	}
+	if err := h.handleDuplicate(ben.sess, []string{CmdDuplicate, DuplicateActionPlay}); err != nil {
+		t.Fatal(err)
+	}
+	host, guest := h.botTable(anna.sess), h.botTable(ben.sess)
+	if host == nil || guest == nil || guest.record != host.record || host.record.ID != set.GameID(0, 0) {
+		t.Fatalf("tables = %v, %v, want the shared game %s", host, guest, set.GameID(0, 0))
+	}
+	if !anna.received(fmt.Sprintf("table cup anna sit %s ben", skat.MovePlayerFromPlayer(guest.Position))) {
+		t.Error("anna was not told about the seat of ben")
+	}
+
+	// Both follow their hints; carl's bot plays along
+	for moves := 0; !host.Finished(); moves++ {
+		client, table := anna, host
+		if p := host.Game().ActivePlayer(); p != nil && *p == guest.Position {
+			client, table = ben, guest
+		}
+		table.Lock()
+		hint, err := table.Hint()
+		table.Unlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts := []string{MsgTable, table.Table, table.Login, TableActionPlay, hint.Move}
+		if err := h.handleTable(client.sess, parts); err != nil {
+			t.Fatal(err)
+		}
+		if moves > 40 {
+			t.Fatalf("not finished after %d moves", moves)
+		}
+	}
+	if h.botTable(anna.sess) != nil || h.botTable(ben.sess) != nil {
+		t.Error("tables left after the game")
+	}
+	if !anna.received("table cup anna end") || !ben.received("table cup ben end") {
+		t.Error("the end of the game was not sent to both players")
+	}
+	record, err := h.archive.Load(set.GameID(0, 0))
+	if err != nil {
+		t.Fatalf("game not archived: %v", err)
+	}
+
+	// The deal is secret until it is played at the other table
+	if !h.visibility().Hidden(record.ID, "dora", time.Now()) || h.visibility().Hidden(record.ID, "ben", time.Now()) {
+		t.Error("the game is visible to dora or hidden from ben")
+	}
+	if err := h.handleDuplicate(anna.sess, []string{CmdDuplicate, DuplicateActionPlay}); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("All deals of duplicate set cup played") {
+		t.Error("anna could deal the played deal again")
+	}
+}
+
+func TestDuplicateLeave(t *testing.T) {
+	h, m, set := newDuplicateHandler(t)
+	anna := newTestClient(t, m, "anna")
+	carl := newTestClient(t, m, "carl")
+
+	for _, c := range []*testClient{anna, carl} {
+		if err := h.handleDuplicate(c.sess, []string{CmdDuplicate, DuplicateActionPlay}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	guest := h.botTable(carl.sess)
+	if guest == nil || !guest.Guest() {
+		t.Fatal("carl did not take his seat")
+	}
+	if err := h.handleTable(carl.sess, []string{MsgTable, "cup", "carl", TableActionLeave}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The game ends for anna and is archived as it is, so it cannot be dealt again
+	if !anna.received("carl left the game") || !anna.received("table cup anna destroy") {
+		t.Error("anna was not told that carl left")
+	}
+	if h.botTable(anna.sess) != nil {
+		t.Error("anna still plays")
+	}
+	if _, err := h.archive.Load(set.GameID(0, 0)); err != nil {
+		t.Errorf("game not archived: %v", err)
+	}
+}
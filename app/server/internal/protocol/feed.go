@@ -0,0 +1,94 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// watchTournament subscribes the client to the live standings of a tournament: the
+// current standings are sent at once and again after every finished deal, in the
+// format of "tournament standings".
+func (h *Handler) watchTournament(sess *session.Session, name string) error {
+	if h.events == nil {
+		return h.SendError(sess, "Live standings not available")
+	}
+	if _, err := h.tournaments.Get(name); err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+
+	h.mu.Lock()
+	if h.feeds[sess.ID()][name] != nil {
+		h.mu.Unlock()
+		return h.SendError(sess, "Already watching tournament %s", name)
+	}
+	// Only standings published from now on; the current ones are sent below
+	events, cancel := h.events.Watch(live.TournamentFeed(name), h.events.LastID())
+	if h.feeds[sess.ID()] == nil {
+		h.feeds[sess.ID()] = make(map[string]func())
+	}
+	h.feeds[sess.ID()][name] = cancel
+	h.mu.Unlock()
+	h.updateWaiting(sess)
+
+	if err := h.sendTournamentStandings(sess, name); err != nil {
+		return err
+	}
+	go func() {
+		for event := range events {
+			if err := h.writeStandings(sess, name, event.Standings, event.Teams); err != nil {
+				log.Printf("[%s] Failed to send live standings: %v", sess.ID(), err)
+				h.unwatchTournament(sess, name)
+			}
+		}
+	}()
+	log.Printf("[%s] Watching tournament %s", sess.ID(), name)
+	return nil
+}
+
+// unwatchTournament ends the live standings of a tournament for the client. It
+// returns false if the client did not watch the tournament.
+func (h *Handler) unwatchTournament(sess *session.Session, name string) bool {
+	h.mu.Lock()
+	cancel := h.feeds[sess.ID()][name]
+	delete(h.feeds[sess.ID()], name)
+	if len(h.feeds[sess.ID()]) == 0 {
+		delete(h.feeds, sess.ID())
+	}
+	h.mu.Unlock()
+
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	h.updateWaiting(sess)
+	return true
+}
+
+// unwatchAll ends all live standings of the client (on disconnect).
+func (h *Handler) unwatchAll(sess *session.Session) {
+	h.mu.Lock()
+	feeds := h.feeds[sess.ID()]
+	delete(h.feeds, sess.ID())
+	h.mu.Unlock()
+
+	for _, cancel := range feeds {
+		cancel()
+	}
+	h.updateWaiting(sess)
+}
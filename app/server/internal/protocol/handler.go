@@ -15,57 +15,240 @@
 package protocol
 
 import (
-	"fmt"
+	"errors"
 	"log"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/async"
+	"github.com/mkloubert/freeskat-server/internal/ban"
+	"github.com/mkloubert/freeskat-server/internal/botpool"
+	"github.com/mkloubert/freeskat-server/internal/challenge"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/i18n"
+	"github.com/mkloubert/freeskat-server/internal/league"
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/internal/moderation"
+	"github.com/mkloubert/freeskat-server/internal/motd"
+	"github.com/mkloubert/freeskat-server/internal/notify"
+	"github.com/mkloubert/freeskat-server/internal/profile"
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/season"
 	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/events"
+	"github.com/mkloubert/freeskat-server/pkg/rating"
 )
 
 // Handler processes ISS protocol messages.
 type Handler struct {
 	sessionManager *session.Manager
+	botPool        *botpool.Pool
+	archive        *archive.Archive
+	daily          *daily.Schedule
+	duplicate      *duplicate.Set
+	dailyClock     time.Duration
+	dailyForfeit   time.Duration
+	tournaments    *tournament.Store
+	leagues        *league.Store
+	seasons        *season.Store
+	events         *live.Hub
+	stream         *events.Stream
+	onYell         func(login, text string)
+	admins         map[string]bool
+	bans           *ban.Store
+	profiles       *profile.Store
+	challenges     *challenge.Tracker
+	notifications  *notify.Store
+	notifier       *notify.Sender
+	notifyAfter    time.Duration
+	turns          map[string]*turn
+	games          *async.Store
+	vacations      *async.Vacations
+	moveTime       time.Duration
+	gamesMu        sync.Mutex
+	sanitizer      *sanitize.Sanitizer
+	moderation     *moderation.Moderation
+	mistakeLoss    int
+	rating         rating.Algorithm
+	catalog        *i18n.Catalog
+	language       string
+	messages       *motd.Messages
+	replays        map[string]*Replay
+	tables         map[string]*BotTable
+	adjourned      map[string]*BotTable
+	held           map[string]*session.Session
+	audiences      map[string]*audience
+	observing      map[string]*audience
+	feeds          map[string]map[string]func()
+	mu             sync.Mutex
 }
 
 // NewHandler creates a new protocol handler.
-func NewHandler(sessionManager *session.Manager) *Handler {
+func NewHandler(sessionManager *session.Manager, botPool *botpool.Pool) *Handler {
 	return &Handler{
 		sessionManager: sessionManager,
+		botPool:        botPool,
+		rating:         rating.AlgorithmElo,
+		sanitizer:      sanitize.New(),
+		catalog:        i18n.New(),
+		language:       i18n.English,
+		replays:        make(map[string]*Replay),
+		tables:         make(map[string]*BotTable),
+		adjourned:      make(map[string]*BotTable),
+		held:           make(map[string]*session.Session),
+		turns:          make(map[string]*turn),
+		audiences:      make(map[string]*audience),
+		observing:      make(map[string]*audience),
+		feeds:          make(map[string]map[string]func()),
 	}
 }
 
+// SetArchive sets the game archive used for replays.
+func (h *Handler) SetArchive(games *archive.Archive) {
+	h.archive = games
+}
+
+// SetDaily sets the schedule of the deal of the day (requires an archive).
+func (h *Handler) SetDaily(schedule *daily.Schedule) {
+	h.daily = schedule
+}
+
+// SetDuplicate sets the duplicate set whose deals stay secret from the players of
+// other tables until they are played at every table (see duplicate.Set.CanView).
+func (h *Handler) SetDuplicate(set *duplicate.Set) {
+	h.duplicate = set
+}
+
+// SetDailyClock times the games of the daily deal with the thinking time of every
+// player (0 = untimed).
+func (h *Handler) SetDailyClock(clock time.Duration) {
+	h.dailyClock = clock
+}
+
+// SetDailyForfeit sets the grace period after which the daily game of a client who
+// disconnected during the trick play is forfeited (0 = archived unfinished at once).
+func (h *Handler) SetDailyForfeit(grace time.Duration) {
+	h.dailyForfeit = grace
+}
+
+// SetTournaments sets the tournament store (requires an archive).
+func (h *Handler) SetTournaments(store *tournament.Store) {
+	h.tournaments = store
+}
+
+// SetLeagues sets the league store (requires an archive).
+func (h *Handler) SetLeagues(store *league.Store) {
+	h.leagues = store
+}
+
+// SetSeasons sets the season store of the ratings (requires an archive).
+func (h *Handler) SetSeasons(store *season.Store) {
+	h.seasons = store
+}
+
+// SetEvents sets the live event hub with the tournament standings feeds and the events
+// of the bot tables.
+func (h *Handler) SetEvents(events *live.Hub) {
+	h.events = events
+}
+
+// SetStream sets the typed event stream for embedding applications.
+func (h *Handler) SetStream(stream *events.Stream) {
+	h.stream = stream
+}
+
+// SetAdmins sets the logins of the server admins.
+func (h *Handler) SetAdmins(logins []string) {
+	h.admins = make(map[string]bool)
+	for _, login := range logins {
+		h.admins[login] = true
+	}
+}
+
+// SetRating sets the rating algorithm (default Elo).
+func (h *Handler) SetRating(algorithm rating.Algorithm) {
+	h.rating = algorithm
+}
+
+// SetSanitizer sets the sanitizer of chat messages, comments and login names.
+func (h *Handler) SetSanitizer(sanitizer *sanitize.Sanitizer) {
+	h.sanitizer = sanitizer
+}
+
+// SetMistakeAnalysis enables the post-game mistake analysis of bot table games
+// (requires an archive). Card plays losing at least minLoss card points are reported.
+func (h *Handler) SetMistakeAnalysis(minLoss int) {
+	h.mistakeLoss = minLoss
+}
+
+// SetCatalog sets the message catalog of the texts sent to the clients and the
+// language of clients that did not choose one.
+func (h *Handler) SetCatalog(catalog *i18n.Catalog, lang string) {
+	h.catalog = catalog
+	h.language = lang
+}
+
 // HandleConnection handles a new client connection.
 func (h *Handler) HandleConnection(sess *session.Session) {
+	defer h.setReplay(sess, nil)
+	defer h.detachBotTable(sess)
+	defer h.unobserve(sess)
+	defer h.unwatchAll(sess)
+
 	// Send welcome message
 	if err := h.sendWelcome(sess); err != nil {
-		log.Printf("[%s] Failed to send welcome: %v", sess.ID, err)
+		log.Printf("[%s] Failed to send welcome: %v", sess.ID(), err)
 		return
 	}
 
 	// Main message loop
 	for {
 		line, err := sess.ReadLine()
-		if err != nil {
-			log.Printf("[%s] Connection closed: %v", sess.ID, err)
+		switch {
+		case errors.Is(err, session.ErrLineTooLong), errors.Is(err, session.ErrInvalidUTF8):
+			log.Printf("[%s] Malformed input: %v", sess.ID(), err)
+			if err := h.SendError(sess, "Malformed input: %v", err); err != nil {
+				return
+			}
+			continue
+		case errors.Is(err, session.ErrTooManyViolations):
+			h.disconnectViolations(sess)
 			return
-		}
-
-		if line == "" {
+		case err != nil:
+			log.Printf("[%s] Connection closed: %v", sess.ID(), err)
+			return
+		case line == "":
 			continue
 		}
 
-		log.Printf("[%s] Received: %s", sess.ID, line)
+		log.Printf("[%s] Received: %s", sess.ID(), line)
 
-		if err := h.handleMessage(sess, line); err != nil {
-			log.Printf("[%s] Error handling message: %v", sess.ID, err)
+		err = h.handleMessage(sess, line)
+		if errors.Is(err, session.ErrTooManyViolations) {
+			h.disconnectViolations(sess)
+			return
+		}
+		if err != nil {
+			log.Printf("[%s] Error handling message: %v", sess.ID(), err)
 		}
 	}
 }
 
+// disconnectViolations tells a client with too many protocol violations (see
+// Session.Violation) that it is disconnected.
+func (h *Handler) disconnectViolations(sess *session.Session) {
+	log.Printf("[%s] Disconnecting: %v", sess.ID(), session.ErrTooManyViolations)
+	h.SendError(sess, "Too many protocol violations, disconnecting")
+}
+
 // sendWelcome sends the initial welcome and version messages.
 func (h *Handler) sendWelcome(sess *session.Session) error {
 	// Send Welcome message
-	if err := sess.WriteLine("%s to ISS", MsgWelcome); err != nil {
+	if err := sess.WriteLine("%s %s", MsgWelcome, h.welcomeText(sess)); err != nil {
 		return err
 	}
 
@@ -74,7 +257,7 @@ func (h *Handler) sendWelcome(sess *session.Session) error {
 		return err
 	}
 
-	log.Printf("[%s] Sent welcome messages (protocol v%d)", sess.ID, ProtocolVersion)
+	log.Printf("[%s] Sent welcome messages (protocol v%d)", sess.ID(), ProtocolVersion)
 	return nil
 }
 
@@ -90,45 +273,234 @@ func (h *Handler) handleMessage(sess *session.Session, message string) error {
 	switch command {
 	case CmdLogin:
 		return h.handleLogin(sess, parts)
+	case CmdResume:
+		return h.handleResume(sess, parts)
+	case CmdReplay:
+		return h.handleReplay(sess, parts)
+	case CmdHistory:
+		return h.handleHistory(sess, parts)
+	case CmdStats:
+		return h.handleStats(sess, parts)
+	case CmdRating:
+		return h.handleRating(sess, parts)
+	case CmdSeason:
+		return h.handleSeason(sess, parts)
+	case CmdComment:
+		return h.handleComment(sess, parts)
+	case CmdDaily:
+		return h.handleDaily(sess, parts)
+	case CmdPractice:
+		return h.handlePractice(sess, parts)
+	case CmdDuplicate:
+		return h.handleDuplicate(sess, parts)
+	case CmdTournament:
+		return h.handleTournament(sess, parts)
+	case CmdLeague:
+		return h.handleLeague(sess, parts)
+	case CmdObserve:
+		return h.handleObserve(sess, parts)
+	case CmdClient:
+		return h.handleClient(sess, parts)
+	case CmdLang:
+		return h.handleLang(sess, parts)
+	case CmdNarrate:
+		return h.handleNarrate(sess, parts)
+	case CmdQuick:
+		return h.handleQuick(sess, parts)
+	case CmdProfile:
+		return h.handleProfile(sess, parts)
+	case CmdChallenge:
+		return h.handleChallenge(sess, parts)
+	case CmdNotify:
+		return h.handleNotify(sess, parts)
+	case CmdAsync:
+		return h.handleAsync(sess, parts)
+	case CmdYell:
+		return h.handleYell(sess, parts)
+	case MsgTable:
+		return h.handleTable(sess, parts)
 	default:
-		log.Printf("[%s] Unknown command: %s", sess.ID, command)
-		return sess.WriteLine("%s Unknown command: %s", MsgError, command)
+		log.Printf("[%s] Unknown command: %s", sess.ID(), command)
+		return h.SendError(sess, "Unknown command: %s", command)
 	}
 }
 
 // handleLogin processes a login command.
 func (h *Handler) handleLogin(sess *session.Session, parts []string) error {
 	if len(parts) < 3 {
-		return sess.WriteLine("%s Invalid login format", MsgError)
+		return h.SendError(sess, "Invalid login format")
 	}
 
 	username := parts[1]
 	// password := parts[2] // For now, accept any password
 
-	sess.Username = username
+	if _, err := h.sanitizer.Name(username); err != nil {
+		log.Printf("[%s] Rejected login %q: %v", sess.ID(), username, err)
+		return h.SendError(sess, "Login name %q is not allowed: %v", username, err)
+	}
+
+	// Bot identities are reserved for the bot pool and the daily deal
+	if h.botPool.IsBot(username) || daily.IsBot(username) {
+		return h.SendError(sess, "Login name '%s' is reserved", username)
+	}
+	if h.bans != nil && h.bans.IsBanned(username) {
+		log.Printf("[%s] Rejected banned login '%s'", sess.ID(), username)
+		return h.SendError(sess, "Login name '%s' is banned", username)
+	}
+
+	token := h.sessionManager.Login(sess, username)
 
-	// Send password confirmation
+	// Send password confirmation and the token to resume the session
 	if err := sess.WriteLine(MsgPassword); err != nil {
 		return err
 	}
+	if err := sess.WriteLine("%s %s", MsgResume, token); err != nil {
+		return err
+	}
 
 	// Send empty client list (for now)
 	if err := sess.WriteLine("%s", MsgClients); err != nil {
 		return err
 	}
 
-	// Send empty table list (for now)
+	// Send the table list, followed by the observable bot tables
 	if err := sess.WriteLine("%s", MsgTables); err != nil {
 		return err
 	}
+	if err := h.sendObservableTables(sess); err != nil {
+		return err
+	}
+	if err := h.sendMOTD(sess); err != nil {
+		return err
+	}
+
+	if err := h.sendPendingGames(sess); err != nil {
+		return err
+	}
+
+	log.Printf("[%s] User '%s' logged in", sess.ID(), username)
+
+	return h.resumeAdjourned(sess)
+}
+
+// handleReplay starts the replay of an archived game: "replay <game id>".
+func (h *Handler) handleReplay(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if len(parts) < 2 {
+		return h.SendError(sess, "Invalid replay format")
+	}
+	if h.archive == nil {
+		return h.SendError(sess, "No game archive available")
+	}
+
+	record, err := h.archive.Load(parts[1])
+	if errors.Is(err, archive.ErrNotFound) || err == nil && !h.canView(sess, record) {
+		return h.SendError(sess, "Unknown game: %s", parts[1])
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to load game %s: %v", sess.ID(), parts[1], err)
+		return h.SendError(sess, "Game %s cannot be replayed", parts[1])
+	}
+
+	replay, err := NewReplay(record, sess.Username())
+	if err != nil {
+		log.Printf("[%s] Failed to replay game %s: %v", sess.ID(), parts[1], err)
+		return h.SendError(sess, "Game %s cannot be replayed", parts[1])
+	}
+	h.setReplay(sess, replay)
+
+	log.Printf("[%s] Replaying game %s", sess.ID(), parts[1])
+	return h.sendLines(sess, replay.Start())
+}
+
+// handleTable processes "table <name> <login> <action>" commands (replay, bot and
+// observed tables only for now).
+func (h *Handler) handleTable(sess *session.Session, parts []string) error {
+	if len(parts) < 4 {
+		return h.SendError(sess, "Invalid table command")
+	}
+	if table := h.botTable(sess); table != nil && table.Table == parts[1] {
+		return h.handleBotTable(sess, table, parts)
+	}
+	if h.games != nil && async.IsGame(parts[1]) {
+		return h.handleAsyncTable(sess, parts)
+	}
+	if a := h.observation(sess); a != nil && a.table.Table == parts[1] {
+		return h.handleObserverTable(sess, a, parts)
+	}
+
+	replay := h.replay(sess)
+	if replay == nil || replay.Table != parts[1] {
+		return h.SendError(sess, "Unknown table: %s", parts[1])
+	}
+
+	switch parts[3] {
+	case TableActionNext:
+		return h.sendLines(sess, replay.Next())
+	case TableActionPrevious:
+		return h.sendLines(sess, replay.Previous())
+	case TableActionLeave:
+		h.setReplay(sess, nil)
+		return sess.WriteLine("%s %s %s %s", MsgTable, replay.Table, replay.Login, TableActionDestroy)
+	default:
+		return h.SendError(sess, "Invalid replay action: %s", parts[3])
+	}
+}
 
-	log.Printf("[%s] User '%s' logged in", sess.ID, username)
+// replay returns the active replay of the session.
+func (h *Handler) replay(sess *session.Session) *Replay {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.replays[sess.ID()]
+}
+
+// setReplay sets (or removes, if nil) the active replay of the session.
+func (h *Handler) setReplay(sess *session.Session, replay *Replay) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if replay == nil {
+		delete(h.replays, sess.ID())
+		return
+	}
+	h.replays[sess.ID()] = replay
+}
 
+// sendLines sends multiple lines to the client.
+func (h *Handler) sendLines(sess *session.Session, lines []string) error {
+	for _, line := range lines {
+		if err := sess.WriteLine("%s", line); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// SendError sends an error message to the client.
+// SendError sends an error message to the client in its language. Error arguments
+// are translated by their text.
 func (h *Handler) SendError(sess *session.Session, format string, args ...interface{}) error {
-	message := fmt.Sprintf(format, args...)
-	return sess.WriteLine("%s %s", MsgError, message)
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			args[i] = h.text(sess, err.Error())
+		}
+	}
+	return sess.WriteLine("%s %s", MsgError, h.text(sess, format, args...))
+}
+
+// SendText sends a notice to the client in its language.
+func (h *Handler) SendText(sess *session.Session, format string, args ...interface{}) error {
+	return sess.WriteLine("%s %s", MsgText, h.text(sess, format, args...))
+}
+
+// text formats the translation of an English text in the language of the client.
+func (h *Handler) text(sess *session.Session, format string, args ...interface{}) string {
+	lang := sess.Lang()
+	if lang == "" {
+		lang = h.language
+	}
+	if len(args) == 0 {
+		return h.catalog.Translate(lang, format)
+	}
+	return h.catalog.Sprintf(lang, format, args...)
 }
@@ -0,0 +1,207 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/visibility"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+const (
+	// defaultHistoryCount is the number of games listed by "history"
+	defaultHistoryCount = 20
+	// maxHistoryCount is the maximum number of games listed by "history <count>"
+	maxHistoryCount = 100
+	// maxCommentLength is the maximum length of a game comment
+	maxCommentLength = 500
+)
+
+// handleHistory processes the game history commands of the logged-in player:
+//
+//	history [count]         lists the most recent games
+//	history private <id>    hides a game from other players
+//	history public <id>     makes a game visible again
+//
+// Listed games can be replayed with "replay <id>".
+func (h *Handler) handleHistory(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.archive == nil {
+		return h.SendError(sess, "No game archive available")
+	}
+
+	if len(parts) >= 2 && (parts[1] == HistoryActionPrivate || parts[1] == HistoryActionPublic) {
+		if len(parts) < 3 {
+			return h.SendError(sess, "Invalid history format")
+		}
+		return h.setGamePrivate(sess, parts[2], parts[1] == HistoryActionPrivate)
+	}
+
+	count := defaultHistoryCount
+	if len(parts) >= 2 {
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 1 {
+			return h.SendError(sess, "Invalid history count: %s", parts[1])
+		}
+		count = min(n, maxHistoryCount)
+	}
+	return h.sendHistory(sess, count)
+}
+
+// sendHistory sends the most recent games of the player:
+// "history game <id> <date> <position> <declarer> <contract> <result> <score> <visibility>"
+// per game, followed by "history end".
+func (h *Handler) sendHistory(sess *session.Session, count int) error {
+	games, err := h.archive.Games(sess.Username(), count)
+	if err != nil {
+		log.Printf("[%s] Failed to load game history: %v", sess.ID(), err)
+		return h.SendError(sess, "Game history not available")
+	}
+
+	for _, r := range games {
+		declarer, contract, result, score := "-", "-", "-", 0
+		if r.Declarer != nil {
+			declarer = strconv.Itoa(*r.Declarer)
+		}
+		if r.Contract != "" {
+			contract = r.Contract
+		}
+		switch {
+		case r.Result != nil && r.Result.DeclarerWon:
+			result, score = "won", r.Result.Score
+		case r.Result != nil:
+			result, score = "lost", r.Result.Score
+		case r.Ramsch != nil:
+			result, score = "ramsch", r.Ramsch.Score
+		}
+		visibility := HistoryActionPublic
+		if h.archive.IsPrivate(r.ID) {
+			visibility = HistoryActionPrivate
+		}
+
+		if err := sess.WriteLine("%s %s %s %s %d %s %s %s %d %s", MsgHistory, HistoryActionGame, r.ID,
+			r.StartedAt.UTC().Format(time.RFC3339), replayPosition(r, sess.Username()),
+			declarer, contract, result, score, visibility); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s", MsgHistory, HistoryActionEnd)
+}
+
+// setGamePrivate marks one of the player's games as private or public.
+func (h *Handler) setGamePrivate(sess *session.Session, id string, private bool) error {
+	record, err := h.archive.Load(id)
+	if errors.Is(err, archive.ErrNotFound) || err == nil && !isPlayer(record, sess.Username()) {
+		return h.SendError(sess, "Unknown game: %s", id)
+	}
+	if err == nil {
+		err = h.archive.SetPrivate(id, private)
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to change visibility of game %s: %v", sess.ID(), id, err)
+		return h.SendError(sess, "Game %s cannot be changed", id)
+	}
+
+	visibility := HistoryActionPublic
+	if private {
+		visibility = HistoryActionPrivate
+	}
+	return sess.WriteLine("%s %s %s", MsgHistory, visibility, id)
+}
+
+// handleComment adds a comment to an archived game: "comment <game id> <move> <text>".
+// Move 0 comments the whole game, otherwise the move with this index in the game's replay.
+// Everyone who can replay the game can comment it.
+func (h *Handler) handleComment(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.archive == nil {
+		return h.SendError(sess, "No game archive available")
+	}
+	if len(parts) < 4 {
+		return h.SendError(sess, "Invalid comment format")
+	}
+
+	id := parts[1]
+	record, err := h.archive.Load(id)
+	if errors.Is(err, archive.ErrNotFound) || err == nil && !h.canView(sess, record) {
+		return h.SendError(sess, "Unknown game: %s", id)
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to load game %s: %v", sess.ID(), id, err)
+		return h.SendError(sess, "Game %s cannot be commented", id)
+	}
+
+	move, err := strconv.Atoi(parts[2])
+	if err != nil || move < 0 || move > len(record.Actions) {
+		return h.SendError(sess, "Invalid move: %s", parts[2])
+	}
+	text, ok, err := h.chatText(sess, strings.Join(parts[3:], " "), maxCommentLength)
+	if !ok {
+		return err
+	}
+
+	if err := h.archive.AddComment(id, skat.Comment{Move: move, Author: sess.Username(), Text: text}); err != nil {
+		log.Printf("[%s] Failed to comment game %s: %v", sess.ID(), id, err)
+		return h.SendError(sess, "Game %s cannot be commented", id)
+	}
+	return sess.WriteLine("%s %s %d %s %s", MsgComment, id, move, sess.Username(), text)
+}
+
+// canView returns true if the client may replay the game (private games, daily games
+// of the current day and duplicate games whose deal is not played at every table only
+// by their players; duplicate games also by the other players of their table).
+func (h *Handler) canView(sess *session.Session, record *skat.GameRecord) bool {
+	if isPlayer(record, sess.Username()) {
+		return true
+	}
+	return !h.visibility().Hidden(record.ID, sess.Username(), time.Now())
+}
+
+// visibility returns the rules deciding which games are shown to other players.
+func (h *Handler) visibility() *visibility.Rules {
+	return visibility.New(h.archive, h.daily, h.duplicate)
+}
+
+// isPlayer returns true if the login played in the game.
+func isPlayer(record *skat.GameRecord, login string) bool {
+	for _, name := range record.Players {
+		if name == login {
+			return true
+		}
+	}
+	return false
+}
+
+// replayPosition returns the position of the login in the game (-1 if not a player).
+func replayPosition(r *replay.Replay, login string) int {
+	for _, p := range r.Players {
+		if p.Name == login {
+			return p.Position
+		}
+	}
+	return -1
+}
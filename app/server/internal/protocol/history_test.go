@@ -0,0 +1,105 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// historyIDs returns the game IDs of the "history game" lines received so far.
+func (c *testClient) historyIDs() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var ids []string
+	for _, line := range c.lines {
+		if fields := strings.Fields(line); len(fields) > 2 && fields[0] == MsgHistory && fields[1] == HistoryActionGame {
+			ids = append(ids, fields[2])
+		}
+	}
+	return ids
+}
+
+func TestHistory(t *testing.T) {
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(1); seed <= 3; seed++ {
+		if err := games.Save(recordtest.Played(t, seed)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := recordtest.PlayedBy(t, 4, map[skat.Player]string{skat.Forehand: "dora", skat.Middlehand: "ben", skat.Rearhand: "carl"})
+	if err := games.Save(other); err != nil {
+		t.Fatal(err)
+	}
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	h.SetArchive(games)
+	anna, dora := newTestClient(t, m, "anna"), newTestClient(t, m, "dora")
+
+	// The most recent games of anna, without the game of dora
+	if err := h.handleMessage(anna.sess, "history 2"); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("history end") {
+		t.Fatal("no history end")
+	}
+	if ids := anna.historyIDs(); !slices.Equal(ids, []string{"g3", "g2"}) {
+		t.Errorf("history 2 = %q, want g3 and g2", ids)
+	}
+
+	commands := []struct {
+		client *testClient
+		line   string
+		want   string
+	}{
+		{anna, "history 0", "Invalid history count: 0"},
+		{anna, "history private", "Invalid history format"},
+		{anna, "history private g4", "Unknown game: g4"},
+		{anna, "history private g1", "history private g1"},
+		// Private games are replayed by their players only
+		{dora, "replay g1", "Unknown game: g1"},
+		{anna, "replay g1", "table replay-g1 anna start"},
+		{anna, "history", "history game g1 2025-03-01T18:01:00Z 0 "},
+		{anna, "history public g1", "history public g1"},
+		{dora, "replay g1", "table replay-g1 dora start"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(c.client.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !c.client.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+	if games.IsPrivate("g1") {
+		t.Error("g1 is still private")
+	}
+	anna.mu.Lock()
+	defer anna.mu.Unlock()
+	listed := slices.IndexFunc(anna.lines, func(line string) bool { return strings.HasPrefix(line, "history game g1 ") })
+	if listed < 0 || !strings.HasSuffix(anna.lines[listed], " "+HistoryActionPrivate) {
+		t.Errorf("g1 is not listed as private: %q", anna.lines)
+	}
+}
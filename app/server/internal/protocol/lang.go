@@ -0,0 +1,44 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// handleLang chooses the language of the error messages and notices: "lang [code]".
+// The server answers "lang <code> <language>..." with the language of the session and
+// all languages of its catalog. The language can be chosen before the login;
+// WebSocket clients start with the language negotiated from Accept-Language.
+func (h *Handler) handleLang(sess *session.Session, parts []string) error {
+	if len(parts) > 2 {
+		return h.SendError(sess, "Invalid lang format")
+	}
+	if len(parts) == 2 {
+		lang := strings.ToLower(parts[1])
+		if !h.catalog.Has(lang) {
+			return h.SendError(sess, "Unknown language: %s", parts[1])
+		}
+		sess.SetLang(lang)
+	}
+
+	lang := sess.Lang()
+	if lang == "" {
+		lang = h.language
+	}
+	return sess.WriteLine("%s %s %s", MsgLang, lang, strings.Join(h.catalog.Languages(), " "))
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/league"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// handleLeague processes the league commands:
+//
+//	league list                               lists all leagues
+//	league create <name>                      creates a league administered by the client
+//	league add <name> <player> [team]         adds a player to the roster (admin only)
+//	league remove <name> <player>             removes a player from the roster (admin only)
+//	league schedule <name> <time>             schedules a round (admin only, RFC 3339 time)
+//	league reschedule <name> <round> <time>   moves a round that has not started (admin only)
+//	league rounds <name>                      lists the rounds and their reserved tables
+//	league standings <name>                   lists the season table
+func (h *Handler) handleLeague(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.leagues == nil || h.archive == nil {
+		return h.SendError(sess, "No leagues available")
+	}
+	if len(parts) < 2 {
+		return h.SendError(sess, "Invalid league format")
+	}
+
+	action := parts[1]
+	if action == LeagueActionList {
+		return h.sendLeagues(sess)
+	}
+	if len(parts) < 3 {
+		return h.SendError(sess, "Invalid league format")
+	}
+	name := parts[2]
+
+	switch action {
+	case LeagueActionCreate:
+		if _, err := h.leagues.Create(name, sess.Username()); err != nil {
+			return h.SendError(sess, "Cannot create league: %v", err)
+		}
+		log.Printf("[%s] Created league %s", sess.ID(), name)
+		return h.SendText(sess, "League %s created", name)
+	case LeagueActionAdd:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid league format")
+		}
+		team := ""
+		if len(parts) >= 5 {
+			team = parts[4]
+		}
+		if err := h.leagues.AddPlayer(name, sess.Username(), parts[3], team); err != nil {
+			return h.SendError(sess, "Cannot add player: %v", err)
+		}
+		return h.SendText(sess, "%s added to league %s", parts[3], name)
+	case LeagueActionRemove:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid league format")
+		}
+		if err := h.leagues.RemovePlayer(name, sess.Username(), parts[3]); err != nil {
+			return h.SendError(sess, "Cannot remove player: %v", err)
+		}
+		return h.SendText(sess, "%s removed from league %s", parts[3], name)
+	case LeagueActionSchedule:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid league format")
+		}
+		start, err := time.Parse(league.TimeLayout, parts[3])
+		if err != nil {
+			return h.SendError(sess, "Invalid time: %s", parts[3])
+		}
+		round, err := h.leagues.Schedule(name, sess.Username(), start)
+		if err != nil {
+			return h.SendError(sess, "Cannot schedule round: %v", err)
+		}
+		log.Printf("[%s] Scheduled round %d of league %s for %s", sess.ID(), round, name, parts[3])
+		return h.SendText(sess, "Round %d of league %s scheduled for %s", round, name, parts[3])
+	case LeagueActionReschedule:
+		if len(parts) < 5 {
+			return h.SendError(sess, "Invalid league format")
+		}
+		round, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return h.SendError(sess, "Invalid round: %s", parts[3])
+		}
+		start, err := time.Parse(league.TimeLayout, parts[4])
+		if err != nil {
+			return h.SendError(sess, "Invalid time: %s", parts[4])
+		}
+		if err := h.leagues.Reschedule(name, sess.Username(), round, start); err != nil {
+			return h.SendError(sess, "Cannot reschedule round: %v", err)
+		}
+		log.Printf("[%s] Rescheduled round %d of league %s to %s", sess.ID(), round, name, parts[4])
+		return h.SendText(sess, "Round %d of league %s rescheduled to %s", round, name, parts[4])
+	case LeagueActionRounds:
+		return h.sendLeagueRounds(sess, name)
+	case LeagueActionStandings:
+		return h.sendSeasonTable(sess, name)
+	default:
+		return h.SendError(sess, "Invalid league action: %s", action)
+	}
+}
+
+// sendLeagues sends "league info <name> <admin> <players> <teams> <rounds>" per league,
+// followed by "league end".
+func (h *Handler) sendLeagues(sess *session.Session) error {
+	for _, l := range h.leagues.List() {
+		if err := sess.WriteLine("%s %s %s %s %d %d %d", MsgLeague, LeagueActionInfo,
+			l.Name, l.Admin, len(l.Players), len(l.Teams()), len(l.Rounds)); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s", MsgLeague, LeagueActionEnd)
+}
+
+// sendLeagueRounds sends "league round <name> <round> <time> <scheduled|started|cancelled>"
+// per round, each started round followed by
+// "league table <name> <round> <table> <deals> <player>..." and
+// "league deal <name> <round> <table> <next deal> <mode> <bock deals> <ramsch deals>"
+// per reserved table, and finally "league end <name>".
+func (h *Handler) sendLeagueRounds(sess *session.Session, name string) error {
+	l, err := h.leagues.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	records, err := h.archive.Records(archive.Filter{Prefix: l.GamePrefix()})
+	if err != nil {
+		log.Printf("[%s] Failed to load league games: %v", sess.ID(), err)
+		return h.SendError(sess, "Rounds not available")
+	}
+
+	for _, r := range l.Rounds {
+		status := "scheduled"
+		switch {
+		case r.Started:
+			status = "started"
+		case r.Cancelled != "":
+			status = "cancelled"
+		}
+		if err := sess.WriteLine("%s %s %s %d %s %s", MsgLeague, LeagueActionRound,
+			name, r.Number, r.Start.Format(league.TimeLayout), status); err != nil {
+			return err
+		}
+		schedules, err := l.Schedules(r.Number, records)
+		if err != nil {
+			log.Printf("[%s] Failed to schedule league %s: %v", sess.ID(), name, err)
+			return h.SendError(sess, "Rounds not available")
+		}
+		for i, table := range r.Tables {
+			if err := sess.WriteLine("%s %s %s %d %d %d %s", MsgLeague, LeagueActionTable,
+				name, r.Number, table.Number, table.Deals, strings.Join(table.Players, " ")); err != nil {
+				return err
+			}
+			if err := sess.WriteLine("%s %s %s %d %d %s", MsgLeague, LeagueActionDeal,
+				name, r.Number, table.Number, encodeSchedule(schedules[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgLeague, LeagueActionEnd, name)
+}
+
+// sendSeasonTable sends the season table of a league:
+// "league entry <name> <rank> <player> <won> <lost> <opponent> <score>" per player,
+// "league team <name> <rank> <team> <score>" per team and "league end <name>".
+func (h *Handler) sendSeasonTable(sess *session.Session, name string) error {
+	l, err := h.leagues.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	records, err := h.archive.Records(archive.Filter{Prefix: l.GamePrefix()})
+	if err != nil {
+		log.Printf("[%s] Failed to load league games: %v", sess.ID(), err)
+		return h.SendError(sess, "Season table not available")
+	}
+	standings, teams, err := l.SeasonTable(records)
+	if err != nil {
+		log.Printf("[%s] Failed to rank league %s: %v", sess.ID(), name, err)
+		return h.SendError(sess, "Season table not available")
+	}
+
+	for _, st := range standings {
+		if err := sess.WriteLine("%s %s %s %d %s %d %d %d %d", MsgLeague, LeagueActionEntry,
+			name, st.Rank, st.Player, st.Won, st.Lost, st.Opponent, st.Score); err != nil {
+			return err
+		}
+	}
+	for _, team := range teams {
+		if err := sess.WriteLine("%s %s %s %d %s %d", MsgLeague, LeagueActionTeam,
+			name, team.Rank, team.Team, team.Score); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgLeague, LeagueActionEnd, name)
+}
@@ -20,23 +20,273 @@ const ProtocolVersion = 14
 
 // Message types for the ISS protocol.
 const (
-	MsgWelcome  = "Welcome"
-	MsgVersion  = "Version"
-	MsgPassword = "password:"
-	MsgClients  = "clients"
-	MsgTables   = "tables"
-	MsgTable    = "table"
-	MsgError    = "error"
-	MsgText     = "text"
-	MsgYell     = "yell"
+	MsgWelcome    = "Welcome"
+	MsgVersion    = "Version"
+	MsgPassword   = "password:"
+	MsgResume     = "resume"
+	MsgClients    = "clients"
+	MsgClient     = "client"
+	MsgTables     = "tables"
+	MsgTable      = "table"
+	MsgError      = "error"
+	MsgText       = "text"
+	MsgYell       = "yell"
+	MsgHistory    = "history"
+	MsgStats      = "stats"
+	MsgComment    = "comment"
+	MsgDaily      = "daily"
+	MsgTournament = "tournament"
+	MsgLeague     = "league"
+	MsgRating     = "rating"
+	MsgSeason     = "season"
+	MsgLang       = "lang"
+	MsgNarrate    = "narrate"
+	MsgQuick      = "quick"
+	MsgProfile    = "profile"
+	MsgChallenge  = "challenge"
+	MsgNotify     = "notify"
+	MsgAsync      = "async"
 )
 
 // Client command types.
 const (
-	CmdLogin   = "login"
-	CmdCreate  = "create"
-	CmdJoin    = "join"
-	CmdObserve = "observe"
-	CmdInvite  = "invite"
-	CmdLeave   = "leave"
+	CmdLogin      = "login"
+	CmdResume     = "resume"
+	CmdCreate     = "create"
+	CmdJoin       = "join"
+	CmdObserve    = "observe"
+	CmdInvite     = "invite"
+	CmdLeave      = "leave"
+	CmdReplay     = "replay"
+	CmdHistory    = "history"
+	CmdStats      = "stats"
+	CmdComment    = "comment"
+	CmdDaily      = "daily"
+	CmdPractice   = "practice"
+	CmdDuplicate  = "duplicate"
+	CmdTournament = "tournament"
+	CmdLeague     = "league"
+	CmdRating     = "rating"
+	CmdSeason     = "season"
+	CmdYell       = "yell"
+	CmdClient     = "client"
+	CmdLang       = "lang"
+	CmdNarrate    = "narrate"
+	CmdQuick      = "quick"
+	CmdProfile    = "profile"
+	CmdChallenge  = "challenge"
+	CmdNotify     = "notify"
+	CmdAsync      = "async"
+)
+
+// Client features ("client <name> <version> [feature...]").
+const (
+	ClientFeatureJSON    = "json"
+	ClientFeatureDeflate = "deflate"
+)
+
+// Narration states ("narrate [on|off]").
+const (
+	NarrateOn  = "on"
+	NarrateOff = "off"
+)
+
+// Quick chat responses ("quick phrase <number> <text>", "quick end").
+const (
+	QuickActionPhrase = "phrase"
+	QuickActionEnd    = "end"
+)
+
+// Profile subcommands and responses ("profile <action> ..."). The fields of a profile
+// are sent as "profile <field> <login> <value>" (see profile.Fields).
+const (
+	ProfileActionShow  = "show"
+	ProfileActionSet   = "set"
+	ProfileActionClear = "clear"
+	ProfileActionEnd   = "end"
+)
+
+// Challenge responses ("challenge <action> ...").
+const (
+	ChallengeActionTask      = "task"
+	ChallengeActionCompleted = "completed"
+	ChallengeActionEnd       = "end"
+)
+
+// Turn notification subcommands and states ("notify <action> ...").
+const (
+	NotifyActionSet   = "set"
+	NotifyActionAfter = "after"
+	NotifyActionTest  = "test"
+	NotifyOn          = "on"
+	NotifyOff         = "off"
+)
+
+// Correspondence game subcommands, responses and states ("async <action> ...").
+// Games are listed as "async game <id> <state> <hours> <rules> <deadline> <player>...".
+const (
+	AsyncActionList     = "list"
+	AsyncActionNew      = "new"
+	AsyncActionJoin     = "join"
+	AsyncActionLeave    = "leave"
+	AsyncActionShow     = "show"
+	AsyncActionVacation = "vacation"
+	AsyncActionGame     = "game"
+	AsyncActionAway     = "away"
+	AsyncActionEnd      = "end"
+	AsyncVacationOff    = "off"
+	// AsyncOpen games wait for players, AsyncTurn games for the move of the client
+	// and AsyncWait games for the move of another player
+	AsyncOpen = "open"
+	AsyncTurn = "turn"
+	AsyncWait = "wait"
+)
+
+// History subcommands and responses ("history <action> ...").
+const (
+	HistoryActionGame    = "game"
+	HistoryActionEnd     = "end"
+	HistoryActionPrivate = "private"
+	HistoryActionPublic  = "public"
+)
+
+// Daily deal subcommands and responses ("daily <action> ...").
+const (
+	DailyActionPlay        = "play"
+	DailyActionLeaderboard = "leaderboard"
+	DailyActionEntry       = "entry"
+	DailyActionEnd         = "end"
+)
+
+// Duplicate set subcommands ("duplicate <action> ...").
+const (
+	DuplicateActionPlay = "play"
+)
+
+// Rating responses ("rating <action> ...").
+const (
+	RatingActionEntry = "entry"
+	RatingActionEnd   = "end"
+)
+
+// Season subcommands and responses ("season <action> ...").
+const (
+	SeasonActionList  = "list"
+	SeasonActionInfo  = "info"
+	SeasonActionShow  = "show"
+	SeasonActionClose = "close"
+	SeasonActionEntry = "entry"
+	SeasonActionEnd   = "end"
+)
+
+// Tournament subcommands and responses ("tournament <action> ...").
+const (
+	TournamentActionCreate        = "create"
+	TournamentActionRegister      = "register"
+	TournamentActionUnregister    = "unregister"
+	TournamentActionNext          = "next"
+	TournamentActionList          = "list"
+	TournamentActionInfo          = "info"
+	TournamentActionTables        = "tables"
+	TournamentActionTable         = "table"
+	TournamentActionDeal          = "deal"
+	TournamentActionStandings     = "standings"
+	TournamentActionEntry         = "entry"
+	TournamentActionStakes        = "stakes"
+	TournamentActionResult        = "result"
+	TournamentActionTransfer      = "transfer"
+	TournamentActionTieBreaks     = "tiebreaks"
+	TournamentActionAssist        = "assist"
+	TournamentActionPause         = "pause"
+	TournamentActionResume        = "resume"
+	TournamentActionAdjust        = "adjust"
+	TournamentActionSubstitute    = "substitute"
+	TournamentActionExtend        = "extend"
+	TournamentActionDisqualify    = "disqualify"
+	TournamentActionAudit         = "audit"
+	TournamentActionStatus        = "status"
+	TournamentActionPairing       = "pairing"
+	TournamentActionWatch         = "watch"
+	TournamentActionUnwatch       = "unwatch"
+	TournamentActionRoster        = "roster"
+	TournamentActionTeamBest      = "teambest"
+	TournamentActionTeam          = "team"
+	TournamentActionStages        = "stages"
+	TournamentActionQualified     = "qualified"
+	TournamentActionCapacity      = "capacity"
+	TournamentActionFee           = "fee"
+	TournamentActionConfirm       = "confirm"
+	TournamentActionDecline       = "decline"
+	TournamentActionRegistrations = "registrations"
+	TournamentActionRegistration  = "registration"
+	TournamentActionProfile       = "profile"
+	TournamentActionEnd           = "end"
+)
+
+// League subcommands and responses ("league <action> ...").
+const (
+	LeagueActionCreate     = "create"
+	LeagueActionAdd        = "add"
+	LeagueActionRemove     = "remove"
+	LeagueActionSchedule   = "schedule"
+	LeagueActionReschedule = "reschedule"
+	LeagueActionList       = "list"
+	LeagueActionInfo       = "info"
+	LeagueActionRounds     = "rounds"
+	LeagueActionRound      = "round"
+	LeagueActionTable      = "table"
+	LeagueActionDeal       = "deal"
+	LeagueActionStandings  = "standings"
+	LeagueActionEntry      = "entry"
+	LeagueActionTeam       = "team"
+	LeagueActionEnd        = "end"
+)
+
+// Table actions (third token after "table <name> <login>").
+const (
+	TableActionState   = "state"
+	TableActionStart   = "start"
+	TableActionPlay    = "play"
+	TableActionEnd     = "end"
+	TableActionReady   = "ready"
+	TableActionLeave   = "leave"
+	TableActionDestroy = "destroy"
+	TableActionError   = "error"
+	// TableActionNext and TableActionPrevious step through a replay
+	TableActionNext     = "next"
+	TableActionPrevious = "prev"
+	// TableActionComment is a post-game comment in a replay
+	TableActionComment = "comment"
+	// TableActionTell is a chat message of a player or an observer
+	TableActionTell = "tell"
+	// TableActionQuick is a preset chat phrase of a player or an observer by its number
+	TableActionQuick = "quick"
+	// TableActionOuvert shows the open hand of the declarer of an Ouvert game
+	TableActionOuvert = "ouvert"
+	// TableActionSkat shows the untouched skat of a Hand game after the game
+	TableActionSkat = "skat"
+	// TableActionBot shows the name and the difficulty of a bot after the start
+	TableActionBot = "bot"
+	// TableActionHint asks for and answers with the suggested move at practice tables
+	TableActionHint = "hint"
+	// TableActionSit lets an observer take the seat of a bot at a practice table and
+	// tells the table who took it
+	TableActionSit = "sit"
+)
+
+// Error codes of rejected moves ("table <name> <login> error <code> <text>").
+const (
+	MoveErrorNotYourTurn  = "not-your-turn"
+	MoveErrorNotInHand    = "not-in-hand"
+	MoveErrorIllegalCard  = "illegal-card"
+	MoveErrorGameOver     = "game-over"
+	MoveErrorOverbid      = "overbid"
+	MoveErrorClaimPending = "claim-pending"
+	MoveErrorInvalid      = "invalid-move"
+)
+
+// Table list updates ("tables <action> ...").
+const (
+	TablesActionAdd    = "add"
+	TablesActionRemove = "remove"
 )
@@ -0,0 +1,68 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/moderation"
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// SetModeration sets the moderation of chat messages and comments, which replaces the
+// filter of the sanitizer for them (nil = sanitizer only).
+func (h *Handler) SetModeration(m *moderation.Moderation) {
+	h.moderation = m
+}
+
+// chatText sanitizes and moderates a chat message or comment of at most max characters.
+// It returns the text to send, or false if the message was rejected and the client
+// was told why.
+func (h *Handler) chatText(sess *session.Session, text string, max int) (string, bool, error) {
+	if h.moderation == nil {
+		text, err := h.sanitizer.Text(text, max)
+		if err != nil {
+			return "", false, h.sendTextError(sess, err, max)
+		}
+		return text, true, nil
+	}
+
+	if m, ok := h.moderation.Muted(sess.Username()); ok {
+		return "", false, h.SendError(sess, "You are muted until %s", m.Until.UTC().Format(time.RFC3339))
+	}
+	text, err := sanitize.Clean(text, max)
+	if err != nil {
+		return "", false, h.sendTextError(sess, err, max)
+	}
+
+	d := h.moderation.Check(sess.Context(), sess.Username(), text)
+	switch d.Action {
+	case moderation.ActionDrop:
+		return "", false, h.SendError(sess, "Message rejected: %s", h.text(sess, d.Reason))
+	case moderation.ActionMute:
+		until := time.Now().Add(h.moderation.MuteDuration)
+		if m, ok := h.moderation.Muted(sess.Username()); ok {
+			until = m.Until
+		}
+		return "", false, h.SendError(sess, "Message rejected, you are muted until %s: %s",
+			until.UTC().Format(time.RFC3339), h.text(sess, d.Reason))
+	case moderation.ActionWarn:
+		if err := h.SendText(sess, "Warning: %s", h.text(sess, d.Reason)); err != nil {
+			return "", false, err
+		}
+	}
+	return d.Text, true, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/moderation"
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+func TestModeratedYell(t *testing.T) {
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	policy, _ := moderation.ParsePolicy("low=warn,high=mute")
+	h.SetModeration(moderation.New(policy, moderation.NewMutes(),
+		moderation.NewWordList(sanitize.NewWordList([]string{"darn"}))))
+	anna, ben := newTestClient(t, m, "anna"), newTestClient(t, m, "ben")
+
+	commands := []struct {
+		line string
+		want string
+	}{
+		{"yell good game", "yell anna good game"},
+		{"yell darn it", "text Warning: inappropriate words"},
+		{"yell darn it", "yell anna **** it"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(anna.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !anna.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+
+	// Muted logins cannot chat
+	if _, err := h.moderation.Mutes.Mute("ben", "spam", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.handleMessage(ben.sess, "yell hello"); err != nil {
+		t.Fatal(err)
+	}
+	if !ben.received("You are muted until") {
+		t.Error("ben was not told about the mute")
+	}
+	anna.mu.Lock()
+	defer anna.mu.Unlock()
+	for _, line := range anna.lines {
+		if strings.HasPrefix(line, "yell ben") || strings.Contains(line, "darn") {
+			t.Errorf("anna received %q", line)
+		}
+	}
+}
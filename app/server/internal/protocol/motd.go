@@ -0,0 +1,78 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+
+	"github.com/mkloubert/freeskat-server/internal/motd"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+)
+
+// SetMessages sets the templates of the welcome line and the message of the day.
+func (h *Handler) SetMessages(messages *motd.Messages) {
+	h.messages = messages
+}
+
+// messageVars returns the variables of the welcome and MOTD templates.
+func (h *Handler) messageVars(username string) motd.Vars {
+	vars := motd.Vars{Username: username, Version: motd.ServerVersion(), Protocol: ProtocolVersion}
+	for _, s := range h.sessionManager.List() {
+		if s.Username() != "" {
+			vars.Online++
+		}
+	}
+	if h.tournaments != nil {
+		// List is newest first: the oldest open tournament starts next
+		for _, t := range h.tournaments.List() {
+			if t.Status == tournament.StatusRegistration {
+				vars.NextTournament = t.Name
+			}
+		}
+	}
+	return vars
+}
+
+// welcomeText returns the text of the welcome line.
+func (h *Handler) welcomeText(sess *session.Session) string {
+	if h.messages == nil {
+		return motd.DefaultWelcome
+	}
+	text, err := h.messages.Welcome(h.messageVars(""))
+	if err != nil {
+		log.Printf("[%s] Welcome template failed: %v", sess.ID(), err)
+	}
+	return text
+}
+
+// sendMOTD sends the message of the day to a user who logged in, one text message per
+// line.
+func (h *Handler) sendMOTD(sess *session.Session) error {
+	if h.messages == nil {
+		return nil
+	}
+	lines, err := h.messages.MOTD(h.messageVars(sess.Username()))
+	if err != nil {
+		log.Printf("[%s] MOTD template failed: %v", sess.ID(), err)
+		return nil
+	}
+	for _, line := range lines {
+		if err := sess.WriteLine("%s %s", MsgText, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
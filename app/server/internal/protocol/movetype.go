@@ -36,14 +36,20 @@ const (
 	MoveGameAnnouncement
 	// MoveCardPlay - Play a card
 	MoveCardPlay
-	// MoveShowCards - Show cards (resign)
+	// MoveShowCards - The declarer shows the cards and claims the remaining tricks ("SC")
 	MoveShowCards
-	// MoveResign - Resign game ("RE")
+	// MoveResign - Resign game: a defender concedes the remaining tricks ("RE")
 	MoveResign
 	// MoveTimeOut - Player timeout
 	MoveTimeOut
 	// MoveLeaveTable - Player left table
 	MoveLeaveTable
+	// MoveAcceptClaim - Accept a claim or concession ("AC")
+	MoveAcceptClaim
+	// MoveRejectClaim - Reject a claim or concession ("RC")
+	MoveRejectClaim
+	// MoveRedeal - The declarer replays a deal abandoned by a defender ("RD")
+	MoveRedeal
 )
 
 // String returns the string representation of the move type.
@@ -73,6 +79,12 @@ func (m MoveType) String() string {
 		return "TimeOut"
 	case MoveLeaveTable:
 		return "LeaveTable"
+	case MoveAcceptClaim:
+		return "AcceptClaim"
+	case MoveRejectClaim:
+		return "RejectClaim"
+	case MoveRedeal:
+		return "Redeal"
 	default:
 		return fmt.Sprintf("MoveType(%d)", m)
 	}
@@ -87,4 +99,7 @@ const (
 	TokenResign      = "RE"
 	TokenTimeOut     = "TI"
 	TokenLeaveTable  = "LE"
+	TokenAcceptClaim = "AC"
+	TokenRejectClaim = "RC"
+	TokenRedeal      = "RD"
 )
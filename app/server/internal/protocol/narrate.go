@@ -0,0 +1,231 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// handleNarrate switches the narration of the game events on or off: "narrate [on|off]".
+// The server answers "narrate on" or "narrate off". With narration the server follows
+// the messages of a bot table with text messages describing each event in full
+// sentences in the language of the session, e.g. for screen readers.
+func (h *Handler) handleNarrate(sess *session.Session, parts []string) error {
+	if len(parts) > 2 {
+		return h.SendError(sess, "Invalid narrate format")
+	}
+	if len(parts) == 2 {
+		switch parts[1] {
+		case NarrateOn:
+			sess.SetNarrate(true)
+		case NarrateOff:
+			sess.SetNarrate(false)
+		default:
+			return h.SendError(sess, "Invalid narrate format")
+		}
+	}
+
+	state := NarrateOff
+	if sess.Narrate() {
+		state = NarrateOn
+	}
+	return sess.WriteLine("%s %s", MsgNarrate, state)
+}
+
+// sendNarration sends the narration of the events of a bot table since the last call.
+// Without narration the events are skipped, so switching it on mid-game does not
+// narrate the past.
+func (h *Handler) sendNarration(sess *session.Session, table *BotTable) error {
+	if !sess.Narrate() {
+		table.narrated = len(table.game.Actions)
+		table.introduced = true
+		table.ended = table.Finished()
+		return nil
+	}
+	for _, line := range h.narrate(sess, table) {
+		if err := sess.WriteLine("%s %s", MsgText, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// narrate returns the sentences describing the events of a bot table since the last
+// call: the deal, every action and the result.
+func (h *Handler) narrate(sess *session.Session, t *BotTable) []string {
+	game := t.game
+	var lines []string
+	if !t.introduced {
+		t.introduced = true
+		lines = append(lines,
+			h.text(sess, "You are %s at table %s.", h.text(sess, t.Position.String()), t.Table),
+			h.text(sess, "Your cards: %s.", h.cardList(sess, t.record.Hands[t.Position].Cards)))
+	}
+
+	from := min(t.narrated, len(game.Actions))
+	played, lastPlay := 0, -1
+	for i, action := range game.Actions {
+		if action.Type != skat.ActionPlayCard {
+			continue
+		}
+		if i < from {
+			played++
+		}
+		lastPlay = i
+	}
+
+	for i := from; i < len(game.Actions); i++ {
+		action := game.Actions[i]
+		who := h.narratedPlayer(sess, t, action.Player)
+		switch action.Type {
+		case skat.ActionBid:
+			lines = append(lines, h.text(sess, "%s bids %d.", who, action.Value))
+		case skat.ActionHold:
+			lines = append(lines, h.text(sess, "%s holds.", who))
+		case skat.ActionPass:
+			lines = append(lines, h.text(sess, "%s passes.", who))
+		case skat.ActionPickUpSkat:
+			lines = append(lines, h.text(sess, "%s picks up the skat.", who))
+		case skat.ActionDiscard:
+			// The discarded cards are only known to the declarer
+			if action.Player == t.Position {
+				lines = append(lines, h.text(sess, "You discard %s.", h.cardList(sess, action.Cards)))
+			}
+		case skat.ActionAnnounce:
+			lines = append(lines,
+				h.text(sess, "%s declares %s.", who, h.contractName(sess, action.Contract)),
+				h.text(sess, "%s leads the first trick.", h.narratedPlayer(sess, t, skat.Forehand)))
+		case skat.ActionPlayCard:
+			lines = append(lines, h.text(sess, "%s plays %s.", who, h.cardName(sess, action.Cards[0])))
+			played++
+			if played%3 != 0 || len(game.Tricks) < played/3 {
+				continue
+			}
+			trick := game.Tricks[played/3-1]
+			if trick.Winner == nil {
+				continue
+			}
+			winner := h.narratedPlayer(sess, t, *trick.Winner)
+			if played == 30 || (t.Finished() && i == lastPlay) {
+				lines = append(lines, h.text(sess, "%s takes the trick with %d points.", winner, trick.Points()))
+			} else {
+				lines = append(lines, h.text(sess, "%s takes the trick with %d points and leads the next one.", winner, trick.Points()))
+			}
+		case skat.ActionKontra:
+			lines = append(lines, h.text(sess, "%s announces Kontra.", who))
+		case skat.ActionRe:
+			lines = append(lines, h.text(sess, "%s announces Re.", who))
+		case skat.ActionClaim:
+			lines = append(lines, h.text(sess, "%s claims the remaining tricks.", who))
+		case skat.ActionConcede:
+			lines = append(lines, h.text(sess, "%s concedes the remaining tricks.", who))
+		case skat.ActionAcceptClaim:
+			lines = append(lines, h.text(sess, "%s accepts.", who))
+		case skat.ActionRejectClaim:
+			lines = append(lines, h.text(sess, "%s rejects.", who))
+		case skat.ActionAbandon:
+			lines = append(lines, h.text(sess, "%s leaves the game.", who))
+		case skat.ActionRedeal:
+			lines = append(lines, h.text(sess, "%s replays the deal.", who))
+		}
+	}
+	t.narrated = len(game.Actions)
+
+	if t.Finished() && !t.ended {
+		t.ended = true
+		lines = append(lines, h.narrateEnd(sess, t)...)
+	}
+	return lines
+}
+
+// narrateEnd returns the sentences describing the result of a finished game.
+func (h *Handler) narrateEnd(sess *session.Session, t *BotTable) []string {
+	game := t.game
+	switch {
+	case game.PassedIn:
+		return []string{h.text(sess, "All players passed, the deal is thrown in.")}
+	case game.RamschResult != nil:
+		r := game.RamschResult
+		if r.Durchmarsch && r.DurchmarschPlayer != nil {
+			return []string{h.text(sess, "%s takes all tricks (Durchmarsch) and scores %d.",
+				h.narratedPlayer(sess, t, *r.DurchmarschPlayer), r.DurchmarschScore)}
+		}
+		var sentences []string
+		for _, p := range r.Losers {
+			sentences = append(sentences, h.text(sess, "%s loses the Ramsch with %d card points and scores %d.",
+				h.narratedPlayer(sess, t, p), r.PlayerPoints[p], r.LoserScore))
+		}
+		return sentences
+	case game.Result != nil:
+		r := game.Result
+		declarer := h.narratedPlayer(sess, t, r.Declarer)
+		contract := h.contractName(sess, &r.Contract)
+		if r.DeclarerWon {
+			return []string{h.text(sess, "%s wins %s with %d card points and scores %d.", declarer, contract, r.DeclarerPoints, r.Score)}
+		}
+		if r.Overbid {
+			return []string{h.text(sess, "%s loses %s, overbid at %d, and scores %d.", declarer, contract, r.BidValue, r.Score)}
+		}
+		return []string{h.text(sess, "%s loses %s with %d card points and scores %d.", declarer, contract, r.DeclarerPoints, r.Score)}
+	}
+	return []string{h.text(sess, "The game ends without a result.")}
+}
+
+// narratedPlayer returns the name of a position in a narration, marking the client and
+// the declarer.
+func (h *Handler) narratedPlayer(sess *session.Session, t *BotTable, player skat.Player) string {
+	name := h.text(sess, player.String())
+	if player == t.Position {
+		return h.text(sess, "%s (you)", name)
+	}
+	if t.game.Declarer != nil && *t.game.Declarer == player {
+		return h.text(sess, "%s (declarer)", name)
+	}
+	return name
+}
+
+// cardName returns the name of a card in a narration ("the Ten of Hearts").
+func (h *Handler) cardName(sess *session.Session, card skat.Card) string {
+	return h.text(sess, "the %s of %s", h.text(sess, card.Rank.String()), h.text(sess, card.Suit.String()))
+}
+
+// cardList returns the names of cards in a narration, separated by commas.
+func (h *Handler) cardList(sess *session.Session, cards []skat.Card) string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = h.cardName(sess, card)
+	}
+	return strings.Join(names, ", ")
+}
+
+// contractName returns the name of a contract in a narration ("Hearts Hand Schneider").
+func (h *Handler) contractName(sess *session.Session, contract *skat.Contract) string {
+	words := []string{h.text(sess, contract.GameType.String())}
+	if contract.Hand {
+		words = append(words, "Hand")
+	}
+	if contract.Ouvert {
+		words = append(words, "Ouvert")
+	}
+	if contract.Schwarz {
+		words = append(words, "Schwarz")
+	} else if contract.Schneider {
+		words = append(words, "Schneider")
+	}
+	return strings.Join(words, " ")
+}
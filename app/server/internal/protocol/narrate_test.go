@@ -0,0 +1,192 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestNarrateCommand(t *testing.T) {
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	anna := newTestClient(t, m, "anna")
+
+	commands := []struct {
+		line string
+		want string
+	}{
+		{"narrate", "narrate off"},
+		{"narrate on", "narrate on"},
+		{"narrate loud", "Invalid narrate format"},
+		{"narrate on now", "Invalid narrate format"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(anna.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !anna.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+	if !anna.sess.Narrate() {
+		t.Error("narration is off after narrate on")
+	}
+}
+
+// narratedGame plays a practice game of anna at the position by following the hints and
+// returns the narration in the language.
+func narratedGame(t *testing.T, h *Handler, sess *session.Session, seat int) []string {
+	t.Helper()
+	position := skat.AllPlayers[seat%3]
+	record, err := practiceRecord("anna", time.Date(2025, 6, 1, 12, 0, seat, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bots := make(map[skat.Player]ai.AIPlayer)
+	for _, p := range skat.AllPlayers {
+		if p != position {
+			bots[p] = ai.New(ai.DifficultyStrong, nil)
+		}
+	}
+	table, err := NewBotTable(practiceTable, record, position, bots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.Start(); err != nil {
+		t.Fatal(err)
+	}
+	lines := h.narrate(sess, table)
+	for !table.Finished() {
+		hint, err := table.Hint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := table.Play(hint.Move); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, h.narrate(sess, table)...)
+	}
+	// Everything is narrated once
+	if more := h.narrate(sess, table); len(more) != 0 {
+		t.Errorf("narrated again: %q", more)
+	}
+	return lines
+}
+
+func TestNarrate(t *testing.T) {
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	anna := newTestClient(t, m, "anna")
+
+	played := false
+	for seat := 0; seat < 6; seat++ {
+		lines := narratedGame(t, h, anna.sess, seat)
+		you := skat.AllPlayers[seat%3].String()
+		if lines[0] != "You are "+you+" at table practice." {
+			t.Errorf("first line = %q", lines[0])
+		}
+		if cards := strings.TrimPrefix(lines[1], "Your cards: "); strings.Count(cards, " of ") != 10 {
+			t.Errorf("second line = %q, want the ten cards", lines[1])
+		}
+		if !strings.Contains(strings.Join(lines, "\n"), you+" (you)") {
+			t.Errorf("anna is never marked: %q", lines)
+		}
+
+		last := lines[len(lines)-1]
+		var plays, tricks int
+		for _, line := range lines {
+			if strings.Contains(line, " plays the ") {
+				plays++
+			}
+			if strings.Contains(line, " takes the trick with ") {
+				tricks++
+			}
+		}
+		if strings.HasPrefix(last, "All players passed") {
+			continue
+		}
+		played = true
+		if plays == 0 || tricks != plays/3 {
+			t.Errorf("%d cards played in %d tricks", plays, tricks)
+		}
+		if !strings.Contains(last, " and scores ") {
+			t.Errorf("last line = %q, want the result", last)
+		}
+	}
+	if !played {
+		t.Error("no game was played")
+	}
+
+	// The session language
+	anna.sess.SetLang("de")
+	lines := narratedGame(t, h, anna.sess, 1)
+	if lines[0] != "Du bist Mittelhand an Tisch practice." {
+		t.Errorf("first German line = %q", lines[0])
+	}
+	for _, english := range []string{" of ", "Spades", "Seven", "Jack"} {
+		if strings.Contains(lines[1], english) {
+			t.Errorf("German cards = %q", lines[1])
+		}
+	}
+}
+
+func TestSendNarration(t *testing.T) {
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	anna := newTestClient(t, m, "anna")
+	record, err := practiceRecord("anna", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := NewBotTable(practiceTable, record, skat.Forehand, map[skat.Player]ai.AIPlayer{
+		skat.Middlehand: ai.New(ai.DifficultyStrong, nil),
+		skat.Rearhand:   ai.New(ai.DifficultyStrong, nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without narration the past events are skipped
+	if err := h.sendNarration(anna.sess, table); err != nil {
+		t.Fatal(err)
+	}
+	anna.sess.SetNarrate(true)
+	if lines := h.narrate(anna.sess, table); len(lines) != 0 {
+		t.Errorf("narrated the skipped events: %q", lines)
+	}
+	hint, err := table.Hint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := table.Play(hint.Move); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.sendNarration(anna.sess, table); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("text Forehand (you) ") {
+		t.Error("anna's move was not narrated")
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/notify"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+)
+
+// turn is a bot table game waiting for the move of its player.
+type turn struct {
+	table string
+	game  string
+	// since is when the turn began
+	since time.Time
+	// notified is true once the player has been notified
+	notified bool
+}
+
+// SetNotifications enables the turn notifications with the store of the settings of
+// the players (requires an archive). after is the default time a turn waits before
+// the player is notified.
+func (h *Handler) SetNotifications(store *notify.Store, after time.Duration) {
+	h.notifications = store
+	h.notifier = notify.NewSender()
+	h.notifyAfter = after
+}
+
+// handleNotify processes the turn notification commands:
+//
+//	notify                          shows the settings
+//	notify set <service> <url>      notifies the client via webhook, ntfy or gotify
+//	notify after <duration>         sets the time a turn waits before, e.g. 30m
+//	notify off                      removes the settings
+//	notify test                     sends a test notification
+//
+// The server answers "notify on <service> <after> <url>" or "notify off".
+func (h *Handler) handleNotify(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.notifications == nil {
+		return h.SendError(sess, "No notifications available")
+	}
+	if len(parts) == 1 {
+		return h.sendNotifySettings(sess)
+	}
+
+	settings, ok := h.notifications.Get(sess.Username())
+	switch parts[1] {
+	case NotifyActionSet:
+		if len(parts) != 4 {
+			return h.SendError(sess, "Invalid notify format")
+		}
+		service, err := notify.ParseService(parts[2])
+		if err != nil {
+			return h.SendError(sess, "Cannot set notifications: %v", err)
+		}
+		settings.Login, settings.Service, settings.URL = sess.Username(), service, parts[3]
+	case NotifyActionAfter:
+		if len(parts) != 3 {
+			return h.SendError(sess, "Invalid notify format")
+		}
+		if !ok {
+			return h.SendError(sess, "Cannot set notifications: %v", notify.ErrNotFound)
+		}
+		after, err := time.ParseDuration(parts[2])
+		if err != nil {
+			return h.SendError(sess, "Invalid duration: %s", parts[2])
+		}
+		settings.After = int(after / time.Second)
+	case NotifyOff:
+		if err := h.notifications.Delete(sess.Username()); err != nil && !errors.Is(err, notify.ErrNotFound) {
+			log.Printf("[%s] Failed to remove notifications: %v", sess.ID(), err)
+			return h.SendError(sess, "Cannot set notifications: %v", err)
+		}
+		return h.sendNotifySettings(sess)
+	case NotifyActionTest:
+		if !ok {
+			return h.SendError(sess, "Cannot send notification: %v", notify.ErrNotFound)
+		}
+		t := webhook.Turn{Player: sess.Username(), Table: "test", Game: "test", Since: time.Now()}
+		if err := h.notifier.Send(sess.Context(), settings, t, h.text(sess, "This is a test notification")); err != nil {
+			return h.SendError(sess, "Cannot send notification: %v", err)
+		}
+		return h.SendText(sess, "Test notification sent")
+	default:
+		return h.SendError(sess, "Invalid notify format")
+	}
+
+	if err := h.notifications.Set(settings); err != nil {
+		return h.SendError(sess, "Cannot set notifications: %v", err)
+	}
+	log.Printf("[%s] Set turn notifications of %s via %s", sess.ID(), sess.Username(), settings.Service)
+	return h.sendNotifySettings(sess)
+}
+
+// sendNotifySettings sends "notify on <service> <after> <url>" or "notify off".
+func (h *Handler) sendNotifySettings(sess *session.Session) error {
+	settings, ok := h.notifications.Get(sess.Username())
+	if !ok {
+		return sess.WriteLine("%s %s", MsgNotify, NotifyOff)
+	}
+	return sess.WriteLine("%s %s %s %s %s", MsgNotify, NotifyOn, settings.Service,
+		settings.Delay(h.notifyAfter), settings.URL)
+}
+
+// trackTurn notes that the game of a bot table waits for its player from now on, or
+// forgets the turn if the game is over.
+func (h *Handler) trackTurn(table *BotTable) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if table.Finished() {
+		delete(h.turns, table.Login)
+		return
+	}
+	h.turns[table.Login] = &turn{table: table.Table, game: table.record.ID, since: time.Now()}
+}
+
+// forgetTurn forgets the turn of a player whose table is closed.
+func (h *Handler) forgetTurn(login string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.turns, login)
+}
+
+// RunTurnNotifications notifies the players whose turn has been waiting longer than
+// their delay, once per turn, checking every interval until the context is canceled.
+func (h *Handler) RunTurnNotifications(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.notifyTurns(ctx, now)
+		}
+	}
+}
+
+// notifyTurns notifies the players whose turn has been waiting longer than their delay.
+func (h *Handler) notifyTurns(ctx context.Context, now time.Time) {
+	type pending struct {
+		login string
+		turn  turn
+	}
+	var due []pending
+	h.mu.Lock()
+	for login, t := range h.turns {
+		if t.notified {
+			continue
+		}
+		settings, ok := h.notifications.Get(login)
+		if !ok || now.Sub(t.since) < settings.Delay(h.notifyAfter) {
+			continue
+		}
+		t.notified = true
+		due = append(due, pending{login, *t})
+	}
+	h.mu.Unlock()
+
+	for _, p := range due {
+		settings, ok := h.notifications.Get(p.login)
+		if !ok {
+			continue
+		}
+		text := h.catalog.Sprintf(h.language, "It is your turn in game %s at table %s", p.turn.game, p.turn.table)
+		t := webhook.Turn{Player: p.login, Table: p.turn.table, Game: p.turn.game, Since: p.turn.since}
+		if err := h.notifier.Send(ctx, settings, t, text); err != nil {
+			log.Printf("Failed to notify %s of game %s: %v", p.login, p.turn.game, err)
+			continue
+		}
+		log.Printf("Notified %s of game %s via %s", p.login, p.turn.game, settings.Service)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/notify"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+func TestNotifyTurns(t *testing.T) {
+	texts := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		texts <- string(body)
+	}))
+	defer server.Close()
+	store, err := notify.Open(filepath.Join(t.TempDir(), "notifications.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	anna, ben := newTestClient(t, m, "anna"), newTestClient(t, m, "ben")
+
+	if err := h.handleMessage(anna.sess, "notify"); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("No notifications available") {
+		t.Error("notifications without a store")
+	}
+	h.SetNotifications(store, time.Hour)
+
+	commands := []struct {
+		client *testClient
+		line   string
+		want   string
+	}{
+		{anna, "notify", "notify off"},
+		{anna, "notify after 30m", "Cannot set notifications: notifications not set"},
+		{anna, "notify test", "Cannot send notification: notifications not set"},
+		{anna, "notify set mail " + server.URL, "Cannot set notifications: invalid notification service: mail"},
+		{anna, "notify set ntfy " + server.URL, "notify on ntfy 1h0m0s " + server.URL},
+		{anna, "notify after 30m", "notify on ntfy 30m0s " + server.URL},
+		{anna, "notify after 10s", "Cannot set notifications: invalid notification delay: 10s"},
+		{anna, "notify after soon", "Invalid duration: soon"},
+		{anna, "notify test", "Test notification sent"},
+		{anna, "notify set ntfy", "Invalid notify format"},
+		{ben, "notify set webhook " + server.URL, "notify on webhook 1h0m0s "},
+		{ben, "notify off", "notify off"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(c.client.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !c.client.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+	if text := <-texts; text != "This is a test notification" {
+		t.Errorf("test notification = %q", text)
+	}
+
+	// anna is notified once after her delay, ben has no settings
+	now := time.Now()
+	h.mu.Lock()
+	h.turns["anna"] = &turn{table: "daily-2025-03-14", game: "g1", since: now.Add(-20 * time.Minute)}
+	h.turns["ben"] = &turn{table: "daily-2025-03-14", game: "g2", since: now.Add(-2 * time.Hour)}
+	h.mu.Unlock()
+	h.notifyTurns(context.Background(), now)
+	if len(texts) != 0 {
+		t.Errorf("notified before the delay: %q", <-texts)
+	}
+	h.notifyTurns(context.Background(), now.Add(10*time.Minute))
+	if text := <-texts; text != "It is your turn in game g1 at table daily-2025-03-14" {
+		t.Errorf("notification = %q", text)
+	}
+	h.notifyTurns(context.Background(), now.Add(time.Hour))
+	if len(texts) != 0 {
+		t.Errorf("notified twice: %q", <-texts)
+	}
+
+	// Closed tables are forgotten
+	h.forgetTurn("anna")
+	h.notifyTurns(context.Background(), now.Add(time.Hour))
+	if len(texts) != 0 {
+		t.Errorf("notified of a forgotten turn: %q", <-texts)
+	}
+}
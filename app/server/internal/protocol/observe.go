@@ -0,0 +1,307 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// audience is the player and the observers of a bot table with the observer messages
+// of its game so far.
+type audience struct {
+	table     *BotTable
+	player    *session.Session
+	log       []string
+	observers *session.Broadcast
+	closed    bool
+	mu        sync.Mutex
+}
+
+// handleObserve starts observing the bot table of a player: "observe <player>". The
+// observer receives the table messages of the game so far and all further messages,
+// addressed to the player, with the cards hidden until the game end. Observers leave
+// with "table <name> <login> leave".
+func (h *Handler) handleObserve(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if len(parts) < 2 {
+		return h.SendError(sess, "Invalid observe format")
+	}
+	if table := h.botTable(sess); table != nil {
+		return h.SendError(sess, "Already playing at table %s", table.Table)
+	}
+	if a := h.observation(sess); a != nil {
+		return h.SendError(sess, "Already observing table %s of %s", a.table.Table, a.table.Login)
+	}
+
+	player := parts[1]
+	h.mu.Lock()
+	var a *audience
+	for _, candidate := range h.audiences {
+		if candidate.table.Login == player {
+			a = candidate
+		}
+	}
+	h.mu.Unlock()
+	if a == nil {
+		return h.SendError(sess, "No table of %s", player)
+	}
+	if ok, err := h.mayObserve(sess, a.table); err != nil {
+		log.Printf("[%s] Failed to load daily games: %v", sess.ID(), err)
+		return h.SendError(sess, "Table %s cannot be observed", a.table.Table)
+	} else if !ok {
+		return h.SendError(sess, "Table %s can only be observed after playing its deal", a.table.Table)
+	}
+
+	// The game so far is sent before the following messages
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed || !a.observers.Subscribe(sess, a.log...) {
+		return h.SendError(sess, "No table of %s", player)
+	}
+	h.mu.Lock()
+	h.observing[sess.ID()] = a
+	h.mu.Unlock()
+	h.updateWaiting(sess)
+
+	log.Printf("[%s] Observing table %s of %s", sess.ID(), a.table.Table, player)
+	return nil
+}
+
+// mayObserve returns true if the client may observe a table: daily tables only after
+// playing the deal of their day and duplicate games only if the client may see their
+// deal, so the moves of others do not give the deal away.
+func (h *Handler) mayObserve(sess *session.Session, table *BotTable) (bool, error) {
+	if h.duplicate != nil && !h.duplicate.CanView(table.record.ID, sess.Username()) {
+		return false, nil
+	}
+	date, ok := strings.CutPrefix(table.Table, "daily-")
+	if !ok || h.archive == nil {
+		return true, nil
+	}
+	played, err := h.archive.Records(archive.Filter{Prefix: daily.GamePrefix(date), Player: sess.Username()})
+	return len(played) > 0, err
+}
+
+// handleObserverTable processes "table <name> <login> leave", "... tell <text>",
+// "... quick <number>" and "... sit" of an observer.
+func (h *Handler) handleObserverTable(sess *session.Session, a *audience, parts []string) error {
+	switch parts[3] {
+	case TableActionLeave:
+		h.unobserve(sess)
+		return sess.WriteLine("%s %s %s %s", MsgTable, a.table.Table, a.table.Login, TableActionDestroy)
+	case TableActionTell:
+		return h.tell(sess, a, parts)
+	case TableActionQuick:
+		return h.quickTell(sess, a, parts)
+	case TableActionSit:
+		return h.takeSeat(sess, a)
+	default:
+		return h.SendError(sess, "Invalid observer action: %s", parts[3])
+	}
+}
+
+// tell sends a chat message to the player and the observers of a table:
+// "table <name> <player> tell <sender> <text>".
+func (h *Handler) tell(sess *session.Session, a *audience, parts []string) error {
+	if len(parts) < 5 {
+		return h.SendError(sess, "Invalid tell format")
+	}
+	text, ok, err := h.chatText(sess, strings.Join(parts[4:], " "), maxYellLength)
+	if !ok {
+		return err
+	}
+	line := fmt.Sprintf("%s %s %s %s %s %s", MsgTable, a.table.Table, a.table.Login, TableActionTell,
+		sess.Username(), text)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.player.WriteLine("%s", line); err != nil {
+		log.Printf("[%s] Failed to send chat: %v", a.player.ID(), err)
+	}
+	a.observers.Send(line)
+	return nil
+}
+
+// publish sends the new observer messages of the bot table of a session to its
+// observers. The first messages open the audience and announce the table in the lobby.
+func (h *Handler) publish(sess *session.Session, table *BotTable) {
+	lines := table.TakePublic()
+
+	h.mu.Lock()
+	a := h.audiences[sess.ID()]
+	opened := a == nil
+	if opened {
+		a = &audience{table: table, player: sess, observers: session.NewBroadcast(session.DefaultBroadcastQueue, h.detachObserver)}
+		h.audiences[sess.ID()] = a
+	}
+	h.mu.Unlock()
+	if opened {
+		h.announceTable(TablesActionAdd, table)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.log = append(a.log, lines...)
+	a.observers.Send(lines...)
+}
+
+// publishLive publishes the new events of a bot table to the watchers of its live
+// events. Games whose deal is still secret are not published (see watchable).
+func (h *Handler) publishLive(table *BotTable) {
+	events := table.TakeLive()
+	if h.events == nil || !h.watchable(table.record.ID) {
+		return
+	}
+	for _, event := range events {
+		if event.Type == live.EventStart {
+			// The recent events of the previous game are not replayed to new watchers
+			h.events.Forget(event.Table)
+		}
+		h.events.Publish(event)
+	}
+}
+
+// watchable returns true if everyone may watch the game (see visibility.Rules.Hidden).
+func (h *Handler) watchable(id string) bool {
+	return !h.visibility().Hidden(id, "", time.Now())
+}
+
+// closeAudience closes the audience of the bot table of a session: the observers
+// receive "table <name> <player> destroy" and the table is removed from the lobby.
+func (h *Handler) closeAudience(id string) {
+	h.mu.Lock()
+	a := h.audiences[id]
+	delete(h.audiences, id)
+	for observer, observed := range h.observing {
+		if observed == a {
+			delete(h.observing, observer)
+		}
+	}
+	h.mu.Unlock()
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	a.closed = true
+	a.observers.Send(fmt.Sprintf("%s %s %s %s", MsgTable, a.table.Table, a.table.Login, TableActionDestroy))
+	a.observers.Close()
+	a.mu.Unlock()
+
+	h.announceTable(TablesActionRemove, a.table)
+}
+
+// observation returns the audience the session observes (nil if none).
+func (h *Handler) observation(sess *session.Session) *audience {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.observing[sess.ID()]
+}
+
+// unobserve ends the observation of the session (on leave and disconnect).
+func (h *Handler) unobserve(sess *session.Session) {
+	h.mu.Lock()
+	a := h.observing[sess.ID()]
+	delete(h.observing, sess.ID())
+	h.mu.Unlock()
+
+	if a == nil {
+		return
+	}
+	a.observers.Unsubscribe(sess)
+	h.updateWaiting(sess)
+}
+
+// detachObserver ends the observation of an observer too slow for the messages of the
+// table.
+func (h *Handler) detachObserver(sess *session.Session) {
+	h.mu.Lock()
+	a := h.observing[sess.ID()]
+	delete(h.observing, sess.ID())
+	h.mu.Unlock()
+	if a == nil {
+		return
+	}
+	h.updateWaiting(sess)
+
+	if err := h.SendText(sess, "Too slow to observe table %s, observation ended", a.table.Table); err != nil {
+		log.Printf("[%s] Failed to end observation: %v", sess.ID(), err)
+		return
+	}
+	if err := sess.WriteLine("%s %s %s %s", MsgTable, a.table.Table, a.table.Login, TableActionDestroy); err != nil {
+		log.Printf("[%s] Failed to end observation: %v", sess.ID(), err)
+	}
+}
+
+// updateWaiting marks the session as waiting on other players while it observes a
+// table or watches live standings, so it reads with the longer waiting timeout.
+func (h *Handler) updateWaiting(sess *session.Session) {
+	h.mu.Lock()
+	waiting := h.observing[sess.ID()] != nil || len(h.feeds[sess.ID()]) > 0
+	h.mu.Unlock()
+	sess.SetWaiting(waiting)
+}
+
+// announceTable sends a change of the observable tables to all logged-in clients:
+// "tables add <name> <player> <forehand> <middlehand> <rearhand>" or
+// "tables remove <name> <player>".
+func (h *Handler) announceTable(action string, table *BotTable) {
+	if h.sessionManager == nil {
+		return
+	}
+	line := tablesLine(action, table)
+	for _, other := range h.sessionManager.List() {
+		if other.Username() == "" {
+			continue
+		}
+		if err := other.WriteLine("%s", line); err != nil {
+			log.Printf("[%s] Failed to send table list: %v", other.ID(), err)
+		}
+	}
+}
+
+// sendObservableTables sends a "tables add ..." line per observable table.
+func (h *Handler) sendObservableTables(sess *session.Session) error {
+	h.mu.Lock()
+	var lines []string
+	for _, a := range h.audiences {
+		lines = append(lines, tablesLine(TablesActionAdd, a.table))
+	}
+	h.mu.Unlock()
+	return h.sendLines(sess, lines)
+}
+
+// tablesLine formats a change of the observable tables.
+func tablesLine(action string, table *BotTable) string {
+	line := fmt.Sprintf("%s %s %s %s", MsgTables, action, table.Table, table.Login)
+	if action == TablesActionAdd {
+		for _, p := range skat.AllPlayers {
+			line += " " + table.record.Players[p]
+		}
+	}
+	return line
+}
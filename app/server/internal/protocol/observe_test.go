@@ -0,0 +1,172 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestDuplicateSecrecy(t *testing.T) {
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := duplicate.NewSet(&duplicate.Config{
+		Name:   "cup",
+		Seed:   7,
+		Deals:  1,
+		Tables: [][3]string{{"anna", "ben", "carl"}, {"dora", "emil", "fritz"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	h.SetArchive(games)
+	h.SetDuplicate(set)
+
+	record := set.Record(0, 0, time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC))
+	table, err := NewBotTable("cup", record, skat.Forehand, map[skat.Player]ai.AIPlayer{
+		skat.Middlehand: ai.New(ai.DifficultyStrong, nil),
+		skat.Rearhand:   ai.New(ai.DifficultyStrong, nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Before the deal is played at every table, only its players see the game
+	tests := []struct {
+		login string
+		want  bool
+	}{
+		{"anna", true},
+		{"dora", false},
+		{"gerd", false},
+	}
+	for _, tt := range tests {
+		sess := newTestClient(t, m, tt.login).sess
+		if got := h.canView(sess, record); got != tt.want {
+			t.Errorf("canView(%s) = %v, want %v", tt.login, got, tt.want)
+		}
+		if got, err := h.mayObserve(sess, table); err != nil || got != tt.want {
+			t.Errorf("mayObserve(%s) = %v, %v, want %v", tt.login, got, err, tt.want)
+		}
+	}
+}
+
+func TestPublishLive(t *testing.T) {
+	set, err := duplicate.NewSet(&duplicate.Config{
+		Name:   "cup",
+		Seed:   7,
+		Deals:  1,
+		Tables: [][3]string{{"anna", "ben", "carl"}, {"dora", "emil", "fritz"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := daily.NewSchedule("secret", time.UTC)
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		id   func(record *skat.GameRecord) *skat.GameRecord
+		want bool
+	}{
+		{"practice game", func(record *skat.GameRecord) *skat.GameRecord { return record }, true},
+		{"daily game of yesterday", func(record *skat.GameRecord) *skat.GameRecord {
+			record.ID = daily.GamePrefix(schedule.Date(now.AddDate(0, 0, -1))) + "1"
+			return record
+		}, true},
+		{"daily game of today", func(record *skat.GameRecord) *skat.GameRecord {
+			record.ID = daily.GamePrefix(schedule.Date(now)) + "1"
+			return record
+		}, false},
+		{"unrevealed duplicate game", func(*skat.GameRecord) *skat.GameRecord {
+			return set.Record(0, 0, now)
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(session.NewManager(context.Background()), nil)
+			h.SetDaily(schedule)
+			h.SetDuplicate(set)
+			hub := live.NewHub()
+			h.SetEvents(hub)
+
+			record, err := practiceRecord("anna", now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			record = tt.id(record)
+			table, err := NewBotTable(practiceTable, record, skat.Forehand, map[skat.Player]ai.AIPlayer{
+				skat.Middlehand: ai.New(ai.DifficultyStrong, nil),
+				skat.Rearhand:   ai.New(ai.DifficultyStrong, nil),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := table.Start(); err != nil {
+				t.Fatal(err)
+			}
+			h.publishLive(table)
+			for moves := 0; !table.Finished(); moves++ {
+				hint, err := table.Hint()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := table.Play(hint.Move); err != nil {
+					t.Fatal(err)
+				}
+				h.publishLive(table)
+				if moves > 60 {
+					t.Fatalf("not finished after %d moves", moves)
+				}
+			}
+
+			events, cancel := hub.Watch("anna", 0)
+			cancel()
+			var types []string
+			for event := range events {
+				if event.Game != record.ID {
+					t.Errorf("event %+v, want game %s", event, record.ID)
+				}
+				types = append(types, event.Type)
+			}
+			if !tt.want {
+				if len(types) > 0 {
+					t.Errorf("published %v, want nothing", types)
+				}
+				return
+			}
+			if len(types) < 3 || types[0] != live.EventStart || types[len(types)-1] != live.EventResult {
+				t.Errorf("published %v, want the start, the moves and the result", types)
+			}
+			if moves := len(table.Game().Actions); strings.Count(strings.Join(types, " "), live.EventMove) != moves {
+				t.Errorf("published %v, want %d moves", types, moves)
+			}
+		})
+	}
+}
@@ -78,6 +78,15 @@ func ParseMove(token string) (*MoveInfo, error) {
 	case TokenResign:
 		info.MoveType = MoveResign
 		return info, nil
+	case TokenAcceptClaim:
+		info.MoveType = MoveAcceptClaim
+		return info, nil
+	case TokenRejectClaim:
+		info.MoveType = MoveRejectClaim
+		return info, nil
+	case TokenRedeal:
+		info.MoveType = MoveRedeal
+		return info, nil
 	}
 
 	// Check for prefixed tokens
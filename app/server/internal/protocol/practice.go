@@ -0,0 +1,325 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/botpool"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// practiceTable is the name of the practice tables. Like daily tables, they are
+// observed by their player.
+const practiceTable = "practice"
+
+// practicePrefix is the prefix of the IDs of practice games ("practice-<n>").
+const practicePrefix = "practice-"
+
+// handlePractice starts an unrated practice game against two bots of the bot pool:
+//
+//	practice [difficulty] [difficulty]   deals a new game at the table "practice"
+//
+// The difficulties (beginner, club, strong) select the play of the bots in seat
+// order; bots without one play at the difficulty of the pool. Practice games are not
+// archived, so they count for no rating, statistics or challenge. The bots return to
+// the pool when the game ends or the client leaves.
+func (h *Handler) handlePractice(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if len(parts) > 3 {
+		return h.SendError(sess, "Invalid practice format")
+	}
+	var levels []ai.Difficulty
+	for _, name := range parts[1:] {
+		level, err := ai.ParseDifficulty(name)
+		if err != nil {
+			return h.SendError(sess, "Invalid difficulty: %s", name)
+		}
+		levels = append(levels, level)
+	}
+	if table := h.botTable(sess); table != nil {
+		return h.SendError(sess, "Already playing at table %s", table.Table)
+	}
+
+	record, err := practiceRecord(sess.Username(), time.Now())
+	if err != nil {
+		log.Printf("[%s] Failed to deal a practice game: %v", sess.ID(), err)
+		return h.SendError(sess, "Practice game failed")
+	}
+	position := skat.AllPlayers[rand.Intn(len(skat.AllPlayers))]
+	bots, difficulties, err := h.acquireBots(record, position, levels)
+	if err != nil {
+		log.Printf("[%s] No bots for a practice game: %v", sess.ID(), err)
+		if errors.Is(err, botpool.ErrGameLimitReached) {
+			return h.SendError(sess, "Too many bot games, try again later")
+		}
+		return h.SendError(sess, "No bot available")
+	}
+
+	table, err := NewBotTable(practiceTable, record, position, bots)
+	if err != nil {
+		h.botPool.ReleaseTable(record.ID)
+		log.Printf("[%s] Failed to create the practice table: %v", sess.ID(), err)
+		return h.SendError(sess, "Practice game failed")
+	}
+	table.Practice = true
+	table.SetDifficulties(difficulties)
+	h.setBotTable(sess, table)
+
+	log.Printf("[%s] Playing practice game %s", sess.ID(), record.ID)
+	messages, err := table.Start()
+	if err != nil {
+		log.Printf("[%s] Practice game failed: %v", sess.ID(), err)
+		h.leaveBotTable(sess)
+		return h.SendError(sess, "Practice game failed")
+	}
+	return h.sendBotMessages(sess, table, messages)
+}
+
+// sendHint sends the suggested move of the client at a practice table with a one-line
+// rationale: "table <name> <login> hint <move> <rationale>". Rated tables give no hints.
+func (h *Handler) sendHint(sess *session.Session, table *BotTable) error {
+	if !table.Practice {
+		return h.SendError(sess, "Hints are only available at practice tables")
+	}
+	hint, err := table.Hint()
+	if err != nil {
+		return h.SendError(sess, "No hint available: %v", err)
+	}
+	return sess.WriteLine("%s %s %s %s %s %s", MsgTable, table.Table, table.Login, TableActionHint,
+		hint.Move, hint.Rationale)
+}
+
+// takeSeat lets an observer of a practice table take the seat of a bot:
+// "table practice <player> sit". The bot returns to the pool (see
+// botpool.Pool.ReleaseSeat), and the observer plays its hand from the current position
+// (see seatClient).
+func (h *Handler) takeSeat(sess *session.Session, a *audience) error {
+	host := a.table
+	if !host.Practice {
+		return h.SendError(sess, "Seats can only be taken at practice tables")
+	}
+	host.Lock()
+	defer host.Unlock()
+	if host.Finished() {
+		return h.SendError(sess, "No free seat at table %s", host.Table)
+	}
+	bot, ok := h.botPool.ReleaseSeat(host.record.ID)
+	if !ok {
+		return h.SendError(sess, "No free seat at table %s", host.Table)
+	}
+	position := skat.Player(-1)
+	for _, p := range skat.AllPlayers {
+		if host.record.Players[p] == bot.Name {
+			position = p
+		}
+	}
+	log.Printf("[%s] Taking the seat of %s at table %s of %s", sess.ID(), bot.Name, host.Table, host.Login)
+	return h.seatClient(sess, host, position)
+}
+
+// seatClient lets the client take the seat of the bot at position of the host's table
+// and plays its hand from the current position at the table of its own login
+// ("table <name> <login> ..."). The player and the observers of the host's table
+// receive "table <name> <player> sit <position> <login>". The caller must hold the
+// lock of the host's table.
+func (h *Handler) seatClient(sess *session.Session, host *BotTable, position skat.Player) error {
+	table, err := host.Seat(position, sess.Username())
+	if err != nil {
+		log.Printf("[%s] Failed to take seat %s at table %s: %v", sess.ID(), position, host.Table, err)
+		return h.SendError(sess, "No free seat at table %s", host.Table)
+	}
+
+	h.mu.Lock()
+	observed := h.observing[sess.ID()]
+	h.mu.Unlock()
+	if observed != nil {
+		h.unobserve(sess)
+		if err := sess.WriteLine("%s %s %s %s", MsgTable, observed.table.Table, observed.table.Login,
+			TableActionDestroy); err != nil {
+			return err
+		}
+	}
+	h.setBotTable(sess, table)
+
+	if player := h.hostSession(host); player != nil {
+		if err := player.WriteLine("%s %s %s %s %s %s", MsgTable, host.Table, host.Login, TableActionSit,
+			skat.MovePlayerFromPlayer(position), sess.Username()); err != nil {
+			log.Printf("[%s] Failed to send the seat: %v", player.ID(), err)
+		}
+		h.publish(player, host)
+	}
+
+	messages, err := table.Resume()
+	if err != nil {
+		log.Printf("[%s] Failed to show table %s: %v", sess.ID(), host.Table, err)
+		h.leaveBotTable(sess)
+		return h.SendError(sess, "Game failed")
+	}
+	return h.sendBotMessages(sess, table, messages)
+}
+
+// hostSession returns the session playing at the host's table (nil if none).
+func (h *Handler) hostSession(host *BotTable) *session.Session {
+	h.mu.Lock()
+	var id string
+	for sessID, table := range h.tables {
+		if table == host {
+			id = sessID
+		}
+	}
+	h.mu.Unlock()
+	if id == "" {
+		return nil
+	}
+	return h.sessionManager.GetSession(id)
+}
+
+// sendSeated sends the moves since index applied to the other clients playing the
+// game of the table (see takeSeat), each as seen at its own table. The caller must
+// hold the lock of the table.
+func (h *Handler) sendSeated(sess *session.Session, table *BotTable, applied int) {
+	h.mu.Lock()
+	others := make(map[string]*BotTable)
+	for id, other := range h.tables {
+		if id != sess.ID() && other.record == table.record {
+			others[id] = other
+		}
+	}
+	h.mu.Unlock()
+
+	for id, other := range others {
+		s := h.sessionManager.GetSession(id)
+		if s == nil {
+			continue
+		}
+		messages, err := other.Follow(applied)
+		if err != nil {
+			log.Printf("[%s] Failed to follow table %s: %v", id, other.Table, err)
+			continue
+		}
+		if other.Guest() {
+			other.TakePublic()
+			other.TakeEvents()
+			other.TakeLive()
+		} else {
+			h.publish(s, other)
+			h.stream.Publish(other.TakeEvents()...)
+			h.publishLive(other)
+		}
+		if err := h.sendLines(s, messages); err != nil {
+			log.Printf("[%s] Failed to send table %s: %v", id, other.Table, err)
+			continue
+		}
+		if err := h.sendNarration(s, other); err != nil {
+			log.Printf("[%s] Failed to narrate table %s: %v", id, other.Table, err)
+		}
+	}
+}
+
+// endShared ends the game of a table shared with other clients (see takeSeat) for
+// them after its client left. Before the end of the game, they receive
+// "table <name> <login> destroy".
+func (h *Handler) endShared(table *BotTable) {
+	h.mu.Lock()
+	others := make(map[string]*BotTable)
+	for id, other := range h.tables {
+		if other.record == table.record {
+			others[id] = other
+			delete(h.tables, id)
+		}
+	}
+	h.mu.Unlock()
+
+	for id, other := range others {
+		h.closeAudience(id)
+		h.forgetTurn(other.Login)
+		s := h.sessionManager.GetSession(id)
+		if s == nil || other.Finished() {
+			continue
+		}
+		if err := h.SendText(s, "%s left the game", table.Login); err != nil {
+			log.Printf("[%s] Failed to end table %s: %v", id, other.Table, err)
+		}
+		if err := s.WriteLine("%s %s %s %s", MsgTable, other.Table, other.Login, TableActionDestroy); err != nil {
+			log.Printf("[%s] Failed to end table %s: %v", id, other.Table, err)
+		}
+	}
+}
+
+// acquireBots seats bots of the pool at all positions of the record except the
+// client's and returns them with their difficulties. The i-th bot plays at levels[i]
+// if given, otherwise at the difficulty of the pool. The bots are seated at the game
+// ID; on errors, all of them are released.
+func (h *Handler) acquireBots(record *skat.GameRecord, position skat.Player, levels []ai.Difficulty) (map[skat.Player]ai.AIPlayer, map[skat.Player]ai.Difficulty, error) {
+	bots := make(map[skat.Player]ai.AIPlayer)
+	difficulties := make(map[skat.Player]ai.Difficulty)
+	for _, p := range skat.AllPlayers {
+		if p == position {
+			continue
+		}
+		bot, err := h.botPool.Acquire(record.ID)
+		if err != nil {
+			h.botPool.ReleaseTable(record.ID)
+			return nil, nil, err
+		}
+		record.Players[p] = bot.Name
+		bots[p], difficulties[p] = bot.Player, bot.Difficulty
+		if i := len(bots) - 1; i < len(levels) && levels[i] != bot.Difficulty {
+			bots[p], difficulties[p] = ai.New(levels[i], nil), levels[i]
+		}
+	}
+	return bots, difficulties, nil
+}
+
+// practiceRecord deals the record of a practice game of the client. The bot seats
+// are named by acquireBots.
+func practiceRecord(login string, startedAt time.Time) (*skat.GameRecord, error) {
+	var shuffle skat.ShuffleInfo
+	hands, skatCards, err := skat.DealUntilValid(func(int) (map[skat.Player]*skat.Hand, *skat.Hand, error) {
+		deck := skat.NewDeck()
+		var err error
+		if shuffle, err = deck.ShuffleCrypto(); err != nil {
+			return nil, nil, err
+		}
+		return skat.DealCards(deck)
+	}, func(attempt int, err error) {
+		log.Printf("Misdeal of a practice game of '%s' (deal %d): %v, redealing", login, attempt+1, err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	record := &skat.GameRecord{
+		ID:        fmt.Sprintf("%s%d", practicePrefix, startedAt.UnixNano()),
+		StartedAt: startedAt,
+		Players:   make(map[skat.Player]string),
+		Hands:     hands,
+		Skat:      skatCards,
+		Shuffle:   shuffle,
+		Engine:    skat.EngineVersion,
+	}
+	for _, p := range skat.AllPlayers {
+		record.Players[p] = login
+	}
+	return record, nil
+}
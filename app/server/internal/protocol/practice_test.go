@@ -0,0 +1,295 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/botpool"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestAcquireBots(t *testing.T) {
+	tests := []struct {
+		name     string
+		bots     int
+		maxGames int
+		games    int // practice games started before
+		levels   []ai.Difficulty
+		want     [2]ai.Difficulty // of the forehand and rearhand bots
+		wantErr  error
+	}{
+		{"first game", 2, 1, 0, nil, [2]ai.Difficulty{ai.DifficultyClub, ai.DifficultyClub}, nil},
+		{"game limit", 4, 1, 1, nil, [2]ai.Difficulty{}, botpool.ErrGameLimitReached},
+		{"one bot left", 3, 0, 1, nil, [2]ai.Difficulty{}, botpool.ErrNoBotAvailable},
+		{"no limit", 4, 0, 1, nil, [2]ai.Difficulty{ai.DifficultyClub, ai.DifficultyClub}, nil},
+		{"first bot selected", 2, 0, 0, []ai.Difficulty{ai.DifficultyStrong},
+			[2]ai.Difficulty{ai.DifficultyStrong, ai.DifficultyClub}, nil},
+		{"both bots selected", 2, 0, 0, []ai.Difficulty{ai.DifficultyBeginner, ai.DifficultyStrong},
+			[2]ai.Difficulty{ai.DifficultyBeginner, ai.DifficultyStrong}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := botpool.New("bot", tt.bots, tt.maxGames, ai.DifficultyClub)
+			h := NewHandler(session.NewManager(context.Background()), pool)
+			start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+			for i := 0; i < tt.games; i++ {
+				record, err := practiceRecord("ben", start.Add(time.Duration(i)*time.Second))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, _, err := h.acquireBots(record, skat.Forehand, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			idle := pool.Idle()
+
+			record, err := practiceRecord("anna", start.Add(time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			bots, difficulties, err := h.acquireBots(record, skat.Middlehand, tt.levels)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("acquireBots() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				// A partly seated game returns its bots
+				if got := pool.Idle(); got != idle {
+					t.Errorf("Idle() = %d, want %d", got, idle)
+				}
+				return
+			}
+
+			if len(bots) != 2 || bots[skat.Middlehand] != nil {
+				t.Errorf("acquireBots() seated %d bots, want 2 without the client's position", len(bots))
+			}
+			for i, p := range []skat.Player{skat.Forehand, skat.Rearhand} {
+				if !pool.IsBot(record.Players[p]) {
+					t.Errorf("player %s = %q, want a bot", p, record.Players[p])
+				}
+				if difficulties[p] != tt.want[i] {
+					t.Errorf("difficulty of %s = %s, want %s", p, difficulties[p], tt.want[i])
+				}
+			}
+			if record.Players[skat.Middlehand] != "anna" {
+				t.Errorf("player %s = %q, want anna", skat.Middlehand, record.Players[skat.Middlehand])
+			}
+			pool.ReleaseTable(record.ID)
+			if got := pool.Idle(); got != idle {
+				t.Errorf("Idle() after the game = %d, want %d", got, idle)
+			}
+		})
+	}
+}
+
+func TestBotTableDifficulties(t *testing.T) {
+	record, err := practiceRecord("anna", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Players[skat.Forehand], record.Players[skat.Rearhand] = "bot1", "bot2"
+	bots := map[skat.Player]ai.AIPlayer{
+		skat.Forehand: ai.New(ai.DifficultyBeginner, nil),
+		skat.Rearhand: ai.New(ai.DifficultyStrong, nil),
+	}
+	table, err := NewBotTable(practiceTable, record, skat.Middlehand, bots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.SetDifficulties(map[skat.Player]ai.Difficulty{
+		skat.Forehand: ai.DifficultyBeginner,
+		skat.Rearhand: ai.DifficultyStrong,
+	})
+
+	messages, err := table.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"table practice anna start bot1 anna bot2",
+		"table practice anna bot 0 bot1 beginner",
+		"table practice anna bot 2 bot2 strong",
+	}
+	if len(messages) < len(want) {
+		t.Fatalf("Start() = %q, want it to begin with %q", messages, want)
+	}
+	for i, line := range want {
+		if messages[i] != line {
+			t.Errorf("Start()[%d] = %q, want %q", i, messages[i], line)
+		}
+	}
+	// Observers see the bots too
+	public := table.TakePublic()
+	if len(public) < len(want) || public[1] != want[1] || public[2] != want[2] {
+		t.Errorf("TakePublic() = %q, want the bots after the start", public)
+	}
+}
+
+func TestBotTableHint(t *testing.T) {
+	for i := 0; i < 30; i++ {
+		position := skat.AllPlayers[i%3]
+		record, err := practiceRecord("anna", time.Date(2025, 6, 1, 12, 0, i, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bots := make(map[skat.Player]ai.AIPlayer)
+		for _, p := range skat.AllPlayers {
+			if p != position {
+				bots[p] = ai.New(ai.DifficultyStrong, nil)
+			}
+		}
+		table, err := NewBotTable(practiceTable, record, position, bots)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := table.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Following the hints plays the game to the end
+		for moves := 0; !table.Finished(); moves++ {
+			hint, err := table.Hint()
+			if err != nil {
+				t.Fatalf("game %d: Hint() error: %v", i, err)
+			}
+			if hint.Rationale == "" {
+				t.Errorf("game %d: Hint() = %s, want a rationale", i, hint)
+			}
+			if _, err := table.Play(hint.Move); err != nil {
+				t.Fatalf("game %d: Play(%s) error: %v (state %s)", i, hint.Move, err, table.Game().State)
+			}
+			if moves > 30 {
+				t.Fatalf("game %d: not finished after %d hints", i, moves)
+			}
+		}
+		if _, err := table.Hint(); !errors.Is(err, errGameOver) {
+			t.Errorf("game %d: Hint() after the game error = %v, want errGameOver", i, err)
+		}
+	}
+}
+
+func TestBotTableSeat(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		record, err := practiceRecord("anna", time.Date(2025, 6, 1, 12, 0, i, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		record.Players[skat.Middlehand], record.Players[skat.Rearhand] = "bot1", "bot2"
+		host, err := NewBotTable(practiceTable, record, skat.Forehand, map[skat.Player]ai.AIPlayer{
+			skat.Middlehand: ai.New(ai.DifficultyStrong, nil),
+			skat.Rearhand:   ai.New(ai.DifficultyStrong, nil),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		host.Practice = true
+		if _, err := host.Start(); err != nil {
+			t.Fatal(err)
+		}
+		host.TakePublic()
+
+		guest, err := host.Seat(skat.Middlehand, "ben")
+		if err != nil {
+			t.Fatalf("game %d: Seat() error: %v", i, err)
+		}
+		if !guest.Guest() || host.Guest() || guest.Login != "ben" || record.Players[skat.Middlehand] != "ben" {
+			t.Fatalf("game %d: Seat() = %+v, want the table of ben at middlehand", i, guest)
+		}
+		if public := host.TakePublic(); len(public) != 1 || public[0] != "table practice anna sit 1 ben" {
+			t.Errorf("game %d: TakePublic() = %q, want the seat", i, public)
+		}
+		if _, err := host.Seat(skat.Middlehand, "carl"); err == nil {
+			t.Errorf("game %d: Seat() of a taken seat succeeded", i)
+		}
+		if _, err := guest.Seat(skat.Rearhand, "carl"); err == nil {
+			t.Errorf("game %d: Seat() at the table of a guest succeeded", i)
+		}
+		if _, err := guest.Resume(); err != nil {
+			t.Fatalf("game %d: Resume() error: %v", i, err)
+		}
+
+		// Both clients follow their hints; the other table follows each move
+		for moves := 0; !host.Finished(); moves++ {
+			table, other := host, guest
+			if p := host.Game().ActivePlayer(); p != nil && *p != skat.Forehand {
+				table, other = guest, host
+			}
+			hint, err := table.Hint()
+			if err != nil {
+				t.Fatalf("game %d: Hint() of %s error: %v", i, table.Login, err)
+			}
+			applied := len(table.Game().Actions)
+			if _, err := table.Play(hint.Move); err != nil {
+				t.Fatalf("game %d: Play(%s) of %s error: %v", i, hint.Move, table.Login, err)
+			}
+			if _, err := other.Follow(applied); err != nil {
+				t.Fatalf("game %d: Follow() of %s error: %v", i, other.Login, err)
+			}
+			if moves > 60 {
+				t.Fatalf("game %d: not finished after %d moves", i, moves)
+			}
+		}
+		if !guest.Finished() {
+			t.Errorf("game %d: the table of the guest is not finished", i)
+		}
+	}
+}
+
+// slowBot is a strong bot that needs too long for every bid.
+type slowBot struct {
+	ai.AIPlayer
+}
+
+func (b slowBot) DecideBid(hand *skat.Hand, ctx ai.BidContext) ai.BidDecision {
+	time.Sleep(time.Second)
+	return b.AIPlayer.DecideBid(hand, ctx)
+}
+
+func TestBotTableClocks(t *testing.T) {
+	record, err := practiceRecord("anna", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record.Players = map[skat.Player]string{skat.Forehand: "bot1", skat.Middlehand: "bot2", skat.Rearhand: "anna"}
+	bots := map[skat.Player]ai.AIPlayer{
+		skat.Forehand:   slowBot{ai.New(ai.DifficultyStrong, nil)},
+		skat.Middlehand: slowBot{ai.New(ai.DifficultyStrong, nil)},
+	}
+	table, err := NewBotTable(practiceTable, record, skat.Rearhand, bots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.SetClocks(2 * time.Second)
+
+	// The bots bid within their share of the clock instead of running it down
+	start := time.Now()
+	if _, err := table.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Start() took %s, want the bots to bid within their share of the clock", elapsed)
+	}
+	for _, p := range []skat.Player{skat.Forehand, skat.Middlehand} {
+		if remaining := table.Game().Remaining(p, time.Now()); remaining <= 0 {
+			t.Errorf("%s has no time left", p)
+		}
+	}
+}
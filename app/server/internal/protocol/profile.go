@@ -0,0 +1,112 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/profile"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// SetProfiles sets the store of the player profiles (requires an archive).
+func (h *Handler) SetProfiles(store *profile.Store) {
+	h.profiles = store
+}
+
+// handleProfile processes the profile commands:
+//
+//	profile [show] [login]          shows the profile of a player (default the client)
+//	profile set <field> <value>     sets a field of the own profile
+//	profile clear <field>           clears a field of the own profile
+//
+// The fields are name, club, country (e.g. DE), rules (isko or club) and avatar (URL).
+// The server answers "profile <field> <login> <value>" per field that is set, followed
+// by "profile end <login>".
+func (h *Handler) handleProfile(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.profiles == nil {
+		return h.SendError(sess, "No profiles available")
+	}
+
+	action := ProfileActionShow
+	if len(parts) >= 2 {
+		action = parts[1]
+	}
+	switch action {
+	case ProfileActionShow:
+		login := sess.Username()
+		if len(parts) >= 3 {
+			login = parts[2]
+		}
+		return h.sendPlayerProfile(sess, login)
+	case ProfileActionSet, ProfileActionClear:
+		if len(parts) < 3 || (action == ProfileActionSet && len(parts) < 4) {
+			return h.SendError(sess, "Invalid profile format")
+		}
+		field := parts[2]
+		value := ""
+		if action == ProfileActionSet {
+			value = strings.Join(parts[3:], " ")
+		}
+		if value != "" && (field == profile.FieldName || field == profile.FieldClub) {
+			// Display names and clubs pass the word filter like chat messages
+			max := profile.MaxName
+			if field == profile.FieldClub {
+				max = profile.MaxClub
+			}
+			var err error
+			if value, err = h.sanitizer.Text(value, max); err != nil {
+				return h.SendError(sess, "Cannot set profile: %v", err)
+			}
+		}
+		if _, err := h.profiles.SetField(sess.Username(), field, value); err != nil {
+			return h.SendError(sess, "Cannot set profile: %v", err)
+		}
+		log.Printf("[%s] Set profile %s of %s", sess.ID(), field, sess.Username())
+		return h.sendPlayerProfile(sess, sess.Username())
+	default:
+		if len(parts) > 2 {
+			return h.SendError(sess, "Invalid profile format")
+		}
+		return h.sendPlayerProfile(sess, parts[1])
+	}
+}
+
+// sendPlayerProfile sends the profile of a player followed by "profile end <login>".
+// A player without a profile is sent as "profile end <login>" only.
+func (h *Handler) sendPlayerProfile(sess *session.Session, login string) error {
+	if p, ok := h.profiles.Get(login); ok {
+		if err := writeProfile(sess, p); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgProfile, ProfileActionEnd, login)
+}
+
+// writeProfile sends "profile <field> <login> <value>" per field of a profile that is set.
+func writeProfile(sess *session.Session, p profile.Profile) error {
+	for _, field := range profile.Fields {
+		if value := p.Get(field); value != "" {
+			if err := sess.WriteLine("%s %s %s %s", MsgProfile, field, p.Login, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/profile"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+func TestProfileCommand(t *testing.T) {
+	store, err := profile.Open(filepath.Join(t.TempDir(), "profiles.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	anna, ben, guest := newTestClient(t, m, "anna"), newTestClient(t, m, "ben"), newTestClient(t, m, "")
+
+	if err := h.handleMessage(anna.sess, "profile"); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("No profiles available") {
+		t.Error("profiles without a store")
+	}
+	h.SetProfiles(store)
+
+	commands := []struct {
+		client *testClient
+		line   string
+		want   string
+	}{
+		{guest, "profile", "Login required"},
+		{anna, "profile set name Anna Schmidt", "profile name anna Anna Schmidt"},
+		{anna, "profile set country de", "profile country anna DE"},
+		{anna, "profile set country Germany", "Cannot set profile: invalid country: GERMANY"},
+		{anna, "profile set rating 2000", "Cannot set profile: invalid profile field: rating"},
+		{anna, "profile set club", "Invalid profile format"},
+		{ben, "profile anna", "profile country anna DE"},
+		{ben, "profile show anna", "profile end anna"},
+		{ben, "profile", "profile end ben"},
+		{ben, "profile show carl", "profile end carl"},
+		{anna, "profile clear name", "profile end anna"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(c.client.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !c.client.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+	if p, _ := store.Get("anna"); p.Name != "" || p.Country != "DE" {
+		t.Errorf("profile of anna = %+v, want the country only", p)
+	}
+}
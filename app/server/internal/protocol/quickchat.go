@@ -0,0 +1,79 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// quickPhrases are the preset table chat phrases, numbered from 1. They are sent in the
+// language of each recipient and need no moderation. New phrases are appended so the
+// numbers of the clients stay valid.
+var quickPhrases = []string{
+	"Hello",
+	"Good luck",
+	"Good game",
+	"Well played",
+	"Nice hand",
+	"Sorry, misclick",
+	"Oops",
+	"Thanks",
+	"Please play faster",
+	"I have to go, bye",
+}
+
+// handleQuick lists the preset chat phrases in the language of the session:
+// "quick phrase <number> <text>" per phrase, then "quick end".
+func (h *Handler) handleQuick(sess *session.Session, parts []string) error {
+	if len(parts) > 1 {
+		return h.SendError(sess, "Invalid quick format")
+	}
+	for i, phrase := range quickPhrases {
+		if err := sess.WriteLine("%s %s %d %s", MsgQuick, QuickActionPhrase, i+1, h.text(sess, phrase)); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s", MsgQuick, QuickActionEnd)
+}
+
+// quickTell sends a preset chat phrase to the player and the observers of a table,
+// each in its language: "table <name> <player> quick <number>" is received as
+// "table <name> <player> tell <sender> <text>".
+func (h *Handler) quickTell(sess *session.Session, a *audience, parts []string) error {
+	if len(parts) != 5 {
+		return h.SendError(sess, "Invalid quick format")
+	}
+	n, err := strconv.Atoi(parts[4])
+	if err != nil || n < 1 || n > len(quickPhrases) {
+		return h.SendError(sess, "Unknown quick chat phrase: %s", parts[4])
+	}
+	phrase := quickPhrases[n-1]
+	line := func(recipient *session.Session) []string {
+		return []string{fmt.Sprintf("%s %s %s %s %s %s", MsgTable, a.table.Table, a.table.Login, TableActionTell,
+			sess.Username(), h.text(recipient, phrase))}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.player.WriteLine("%s", line(a.player)[0]); err != nil {
+		log.Printf("[%s] Failed to send chat: %v", a.player.ID(), err)
+	}
+	a.observers.SendFunc(line)
+	return nil
+}
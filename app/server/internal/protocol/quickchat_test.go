@@ -0,0 +1,60 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/botpool"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+)
+
+func TestQuickChat(t *testing.T) {
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, botpool.New("bot", 2, 0, ai.DifficultyClub))
+	anna, ben := newTestClient(t, m, "anna"), newTestClient(t, m, "ben")
+	ben.sess.SetLang("de")
+
+	commands := []struct {
+		client *testClient
+		line   string
+		want   string
+	}{
+		{ben, "quick", "quick phrase 2 Viel Glück"},
+		{ben, "quick", "quick end"},
+		{anna, "quick all", "Invalid quick format"},
+		{anna, "practice", "table practice anna start"},
+		{ben, "observe anna", "table practice anna start"},
+		// Each recipient gets the phrase in its language
+		{anna, "table practice anna quick 2", "table practice anna tell anna Good luck"},
+		{ben, "table practice anna quick 1", "table practice anna tell ben Hallo"},
+		{anna, "table practice anna quick 0", "Unknown quick chat phrase: 0"},
+		{anna, "table practice anna quick 11", "Unknown quick chat phrase: 11"},
+		{anna, "table practice anna quick 1 2", "Invalid quick format"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(c.client.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !c.client.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+	if !ben.received("table practice anna tell anna Viel Glück") || !anna.received("table practice anna tell ben Hello") {
+		t.Error("the other recipient did not get the phrase in its language")
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/rating"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// defaultRatingCount is the number of ratings sent without a count.
+const defaultRatingCount = 20
+
+// handleRating sends the best rated players of the current season: "rating [count]"
+// (default 20). With a login instead of the count, only the rating of that player is sent.
+//
+// Response: "rating entry <rank> <player> <rating> <deviation> <games>" per player
+// (deviation 0 for Elo), then "rating end <algorithm>".
+func (h *Handler) handleRating(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.archive == nil {
+		return h.SendError(sess, "No game archive available")
+	}
+
+	count, login := defaultRatingCount, ""
+	if len(parts) >= 2 {
+		n, err := strconv.Atoi(parts[1])
+		switch {
+		case err != nil:
+			login = parts[1]
+		case n < 1:
+			return h.SendError(sess, "Invalid count: %s", parts[1])
+		default:
+			count = n
+		}
+	}
+
+	records, err := h.archive.Records(archive.Filter{})
+	if err != nil {
+		log.Printf("[%s] Failed to load games: %v", sess.ID(), err)
+		return h.SendError(sess, "Ratings not available")
+	}
+	ratings, err := h.ratings(records)
+	if err != nil {
+		log.Printf("[%s] Failed to compute ratings: %v", sess.ID(), err)
+		return h.SendError(sess, "Ratings not available")
+	}
+
+	sent := 0
+	for _, r := range ratings {
+		if login != "" && r.Player != login || login == "" && sent == count {
+			continue
+		}
+		if err := sess.WriteLine("%s %s %d %s %.0f %.0f %d", MsgRating, RatingActionEntry,
+			r.Rank, r.Player, r.Rating, r.Deviation, r.Games); err != nil {
+			return err
+		}
+		sent++
+	}
+	return sess.WriteLine("%s %s %s", MsgRating, RatingActionEnd, h.rating)
+}
+
+// ratings computes the ratings of the current season, or of all games without seasons.
+func (h *Handler) ratings(records []*skat.GameRecord) ([]rating.Rating, error) {
+	if h.seasons == nil {
+		return rating.Compute(h.rating, records)
+	}
+	return h.seasons.Ratings(h.rating, records)
+}
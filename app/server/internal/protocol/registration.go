@@ -0,0 +1,143 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+)
+
+// handleTournamentRegistration processes the registration commands of a tournament:
+//
+//	tournament register <name>                  registers the client (registered, pending or waitlisted)
+//	tournament unregister <name>                withdraws the registration
+//	tournament registrations <name>             lists the registrations
+//	tournament capacity <name> <players>        limits the seats, 0 unlimited (director only)
+//	tournament fee <name> <cents>               sets the seat fee, 0 free (director only)
+//	tournament confirm <name> <player>          confirms the payment of a pending player (director only)
+//	tournament decline <name> <player>          declines a pending registration (director only)
+//
+// Every change of a registration is sent to the player (see RegistrationChanged).
+func (h *Handler) handleTournamentRegistration(sess *session.Session, action, name string, args []string) error {
+	switch action {
+	case TournamentActionRegister:
+		status, err := h.tournaments.Register(name, sess.Username())
+		if err != nil {
+			return h.SendError(sess, "Cannot register: %v", err)
+		}
+		return h.SendText(sess, "Registration for tournament %s: %s", name, status)
+	case TournamentActionUnregister:
+		if err := h.tournaments.Unregister(name, sess.Username()); err != nil {
+			return h.SendError(sess, "Cannot unregister: %v", err)
+		}
+		return h.SendText(sess, "Unregistered from tournament %s", name)
+	case TournamentActionRegistrations:
+		return h.sendRegistrations(sess, name)
+	case TournamentActionCapacity, TournamentActionFee:
+		if len(args) < 1 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return h.SendError(sess, "Invalid number: %s", args[0])
+		}
+		if action == TournamentActionCapacity {
+			if err := h.tournaments.SetCapacity(name, sess.Username(), n); err != nil {
+				return h.SendError(sess, "Cannot set capacity: %v", err)
+			}
+			return h.SendText(sess, "Capacity of tournament %s set to %d players", name, n)
+		}
+		if err := h.tournaments.SetFee(name, sess.Username(), n); err != nil {
+			return h.SendError(sess, "Cannot set fee: %v", err)
+		}
+		return h.SendText(sess, "Seat fee of tournament %s set to %d cents", name, n)
+	default:
+		if len(args) < 1 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		t, err := h.tournaments.Get(name)
+		if err != nil {
+			return h.SendError(sess, "Cannot confirm payment: %v", err)
+		}
+		if !t.IsDirector(sess.Username()) {
+			return h.SendError(sess, "Cannot confirm payment: %v", tournament.ErrNotDirector)
+		}
+		paid := action == TournamentActionConfirm
+		if err := h.tournaments.ConfirmPayment(name, args[0], paid); err != nil {
+			return h.SendError(sess, "Cannot confirm payment: %v", err)
+		}
+		log.Printf("[%s] Payment of %s for tournament %s confirmed: %t", sess.ID(), args[0], name, paid)
+		if !paid {
+			return h.SendText(sess, "Registration of %s for tournament %s declined", args[0], name)
+		}
+		return h.SendText(sess, "Registration of %s for tournament %s confirmed", args[0], name)
+	}
+}
+
+// sendRegistrations sends "tournament registration <name> <player> <status>" per
+// registered, pending and waitlisted player (the waitlist in order) and the profiles
+// of these players (see writeProfile), followed by "tournament end <name>".
+func (h *Handler) sendRegistrations(sess *session.Session, name string) error {
+	t, err := h.tournaments.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	lists := []struct {
+		players []string
+		status  tournament.Registration
+	}{
+		{t.Players, tournament.RegistrationConfirmed},
+		{t.Pending, tournament.RegistrationPending},
+		{t.Waitlist, tournament.RegistrationWaitlisted},
+	}
+	var players []string
+	for _, list := range lists {
+		for _, p := range list.players {
+			if err := sess.WriteLine("%s %s %s %s %s", MsgTournament, TournamentActionRegistration,
+				name, p, list.status); err != nil {
+				return err
+			}
+		}
+		players = append(players, list.players...)
+	}
+	if h.profiles != nil {
+		for _, p := range h.profiles.Profiles(players) {
+			if err := writeProfile(sess, p); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgTournament, TournamentActionEnd, name)
+}
+
+// RegistrationChanged notifies the logged-in sessions of a player of a changed
+// registration: "tournament registration <name> <player> <status>".
+func (h *Handler) RegistrationChanged(name, player string, status tournament.Registration) {
+	if h.sessionManager == nil {
+		return
+	}
+	for _, other := range h.sessionManager.List() {
+		if other.Username() != player {
+			continue
+		}
+		if err := other.WriteLine("%s %s %s %s %s", MsgTournament, TournamentActionRegistration,
+			name, player, status); err != nil {
+			log.Printf("[%s] Failed to send registration: %v", other.ID(), err)
+		}
+	}
+}
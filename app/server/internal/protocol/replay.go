@@ -0,0 +1,130 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// replayTablePrefix is the prefix of the virtual table names of replays.
+const replayTablePrefix = "replay-"
+
+// Replay streams an archived game to a client as if it were played at a table.
+//
+// All moves use the table message encoding ("table <name> <login> play <player> <move>"),
+// so ISS clients can display them. Comments follow the commented move as
+// "table <name> <login> comment <author> <text>". Stepping back restarts the stream and
+// sends all moves up to the previous one, since clients cannot undo moves.
+type Replay struct {
+	// Table is the name of the virtual replay table
+	Table string
+	// Login is the name of the watching client
+	Login string
+
+	summary  *GameSummary
+	moves    []string
+	comments map[int][]string
+	position int
+}
+
+// NewReplay creates a replay of a game record for the client.
+func NewReplay(record *skat.GameRecord, login string) (*Replay, error) {
+	summary, err := NewGameSummary(record)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replay{Table: replayTablePrefix + record.ID, Login: login, summary: summary, comments: make(map[int][]string)}
+	for i, move := range summary.Moves {
+		token := move.Token
+		if i == 0 {
+			// The deal uses the table format with all hands visible
+			token = EncodeDealCards(record.Hands, record.Skat, false)
+		}
+		r.moves = append(r.moves, fmt.Sprintf("%s %s %s", TableActionPlay, move.Player, token))
+	}
+
+	positions := summaryPositions(record.Actions)
+	for _, c := range record.Comments {
+		if c.Move < 0 || c.Move >= len(positions) {
+			continue
+		}
+		position := positions[c.Move]
+		r.comments[position] = append(r.comments[position], fmt.Sprintf("%s %s %s", TableActionComment, c.Author, c.Text))
+	}
+	return r, nil
+}
+
+// summaryPositions returns the summary move index completing each action (index 0 = the deal).
+func summaryPositions(actions []skat.Action) []int {
+	positions := []int{0}
+	position := 0
+	for _, action := range actions {
+		switch action.Type {
+		case skat.ActionPickUpSkat:
+			// "s" and the skat revealed to the declarer
+			position += 2
+		case skat.ActionDiscard:
+			// Part of the announcement move
+			positions = append(positions, position+1)
+			continue
+		default:
+			position++
+		}
+		positions = append(positions, position)
+	}
+	return positions
+}
+
+// Start returns the messages starting the replay (table start and the deal).
+func (r *Replay) Start() []string {
+	r.position = 0
+	messages := []string{r.message("%s %s", TableActionStart, strings.Join(r.summary.Players[:], " "))}
+	return append(messages, r.Next()...)
+}
+
+// Next returns the messages of the next move (and the game end after the last move).
+func (r *Replay) Next() []string {
+	if r.position >= len(r.moves) {
+		return nil
+	}
+	messages := []string{r.message("%s", r.moves[r.position])}
+	for _, comment := range r.comments[r.position] {
+		messages = append(messages, r.message("%s", comment))
+	}
+	r.position++
+	if r.position == len(r.moves) {
+		messages = append(messages, r.message("%s %s", TableActionEnd, r.summary.Encode()))
+	}
+	return messages
+}
+
+// Previous returns the messages restarting the replay up to the previous move.
+func (r *Replay) Previous() []string {
+	target := max(r.position-1, 1)
+	messages := r.Start()
+	for r.position < target {
+		messages = append(messages, r.Next()...)
+	}
+	return messages
+}
+
+// message formats a table message of the replay table.
+func (r *Replay) message(format string, args ...interface{}) string {
+	return fmt.Sprintf("%s %s %s %s", MsgTable, r.Table, r.Login, fmt.Sprintf(format, args...))
+}
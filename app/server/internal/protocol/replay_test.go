@@ -0,0 +1,113 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestReplaySteps(t *testing.T) {
+	record := recordtest.Played(t, 1)
+	record.Comments = []skat.Comment{{Move: 0, Author: "ben", Text: "nice deal"}, {Move: 1, Author: "anna", Text: "why 18?"}}
+	r, err := NewReplay(record, "dora")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := r.Start()
+	want := []string{
+		"table replay-g1 dora start anna ben carl",
+		"table replay-g1 dora play w " + EncodeDealCards(record.Hands, record.Skat, false),
+		"table replay-g1 dora comment ben nice deal",
+	}
+	if !slices.Equal(start, want) {
+		t.Errorf("Start() = %q, want %q", start, want)
+	}
+	if next := r.Next(); len(next) != 2 || next[0] != "table replay-g1 dora play 1 18" || next[1] != "table replay-g1 dora comment anna why 18?" {
+		t.Errorf("Next() = %q, want the first bid with its comment", next)
+	}
+
+	// Stepping back sends the stream again up to the previous move
+	r.Next()
+	if prev := r.Previous(); !slices.Equal(prev, append(start, "table replay-g1 dora play 1 18", "table replay-g1 dora comment anna why 18?")) {
+		t.Errorf("Previous() = %q", prev)
+	}
+
+	var last []string
+	for next := r.Next(); next != nil; next = r.Next() {
+		last = next
+	}
+	summary, _ := NewGameSummary(record)
+	if end := last[len(last)-1]; end != "table replay-g1 dora end "+summary.Encode() {
+		t.Errorf("last message = %q, want the game end", end)
+	}
+	if r.Next() != nil {
+		t.Error("Next() after the end returned messages")
+	}
+}
+
+func TestReplayCommand(t *testing.T) {
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := games.Save(recordtest.Played(t, 2)); err != nil {
+		t.Fatal(err)
+	}
+	m := session.NewManager(context.Background())
+	h := NewHandler(m, nil)
+	h.SetArchive(games)
+	anna := newTestClient(t, m, "anna")
+	guest := newTestClient(t, m, "")
+
+	commands := []struct {
+		client *testClient
+		line   string
+		want   string
+	}{
+		{guest, "replay g2", "Login required"},
+		{anna, "replay", "Invalid replay format"},
+		{anna, "replay g9", "Unknown game: g9"},
+		{anna, "replay g2", "table replay-g2 anna start anna ben carl"},
+		{anna, "table replay-g2 anna next", "table replay-g2 anna play 1 18"},
+		{anna, "table replay-g2 anna jump", "Invalid replay action: jump"},
+		{anna, "table replay-g1 anna next", "Unknown table: replay-g1"},
+		{anna, "table replay-g2 anna leave", "table replay-g2 anna destroy"},
+	}
+	for _, c := range commands {
+		if err := h.handleMessage(c.client.sess, c.line); err != nil {
+			t.Fatalf("%s: %v", c.line, err)
+		}
+		if !c.client.received(c.want) {
+			t.Errorf("%s: no %q", c.line, c.want)
+		}
+	}
+	if h.replay(anna.sess) != nil {
+		t.Error("the replay is still active after leaving")
+	}
+	if err := h.handleMessage(anna.sess, "table replay-g2 anna next"); err != nil {
+		t.Fatal(err)
+	}
+	if !anna.received("Unknown table: replay-g2") {
+		t.Error("the replay table still exists after leaving")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// handleResume reattaches a new connection to a disconnected session: "resume <token>"
+// with the token of the login. The client receives "password:", the new token
+// ("resume <token>") and the game of its table like a resumed adjourned game.
+func (h *Handler) handleResume(sess *session.Session, parts []string) error {
+	if len(parts) < 2 {
+		return h.SendError(sess, "Invalid resume format")
+	}
+	if sess.Username() != "" {
+		return h.SendError(sess, "Already logged in")
+	}
+	token, err := h.sessionManager.Resume(sess, parts[1])
+	if err != nil {
+		return h.SendError(sess, "Cannot resume session: %v", err)
+	}
+
+	h.mu.Lock()
+	delete(h.held, sess.ID())
+	h.mu.Unlock()
+
+	if err := sess.WriteLine(MsgPassword); err != nil {
+		return err
+	}
+	if err := sess.WriteLine("%s %s", MsgResume, token); err != nil {
+		return err
+	}
+	log.Printf("[%s] User '%s' resumed the session", sess.ID(), sess.Username())
+	return h.resumeBotTable(sess)
+}
+
+// resumeBotTable sends the game of the bot table kept for a resumed session: "text
+// Resuming game <id>", the table start, the deal and all moves so far.
+func (h *Handler) resumeBotTable(sess *session.Session) error {
+	table := h.botTable(sess)
+	if table == nil {
+		return nil
+	}
+	table.Lock()
+	defer table.Unlock()
+
+	if err := h.SendText(sess, "Resuming game %s", table.record.ID); err != nil {
+		return err
+	}
+	messages, err := table.Resume()
+	if err != nil {
+		log.Printf("[%s] Resumed game failed: %v", sess.ID(), err)
+		h.leaveBotTable(sess)
+		return h.SendError(sess, "Resumed game failed")
+	}
+	return h.sendBotMessages(sess, table, messages)
+}
+
+// detachBotTable handles the bot table of a disconnected client. The table of a
+// session that can be resumed is kept for the resume window of the session manager,
+// then it is disconnected like the tables of other sessions (see disconnectBotTable).
+// Games shared with other clients end at once.
+func (h *Handler) detachBotTable(sess *session.Session) {
+	table := h.botTable(sess)
+	if table == nil || table.Shared() || !h.sessionManager.Resumable(sess) {
+		h.disconnectBotTable(sess)
+		return
+	}
+
+	id := sess.ID()
+	h.mu.Lock()
+	h.held[id] = sess
+	h.mu.Unlock()
+	h.closeAudience(id)
+	log.Printf("[%s] Table %s waits %s for '%s' to resume", id, table.Table, h.sessionManager.ResumeWindow, table.Login)
+
+	time.AfterFunc(h.sessionManager.ResumeWindow, func() {
+		h.mu.Lock()
+		if h.held[id] != sess {
+			// Resumed in time
+			h.mu.Unlock()
+			return
+		}
+		delete(h.held, id)
+		h.mu.Unlock()
+		h.disconnectBotTable(sess)
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+// dropTable deals anna her duplicate deal and disconnects her; it returns her resume
+// token.
+func dropTable(t *testing.T, h *Handler, anna *testClient) string {
+	t.Helper()
+	if err := h.handleDuplicate(anna.sess, []string{CmdDuplicate, DuplicateActionPlay}); err != nil {
+		t.Fatal(err)
+	}
+	if h.botTable(anna.sess) == nil {
+		t.Fatal("anna has no table")
+	}
+	token := anna.sess.ResumeToken()
+	h.detachBotTable(anna.sess)
+	h.sessionManager.RemoveSession(anna.sess.ID())
+	return token
+}
+
+func TestResumeTable(t *testing.T) {
+	h, m, set := newDuplicateHandler(t)
+	anna := newTestClient(t, m, "anna")
+	token := dropTable(t, h, anna)
+	table := h.botTable(anna.sess)
+	if table == nil {
+		t.Fatal("the table was not kept for the resume window")
+	}
+
+	// The new connection takes the seat and receives the game so far
+	back := newTestClient(t, m, "")
+	if err := h.handleResume(back.sess, []string{CmdResume, token}); err != nil {
+		t.Fatal(err)
+	}
+	if !back.received(MsgPassword) || !back.received(MsgResume+" ") {
+		t.Error("the resume was not confirmed with a new token")
+	}
+	if !back.received("Resuming game "+set.GameID(0, 0)) || !back.received("table cup anna start") {
+		t.Error("the game was not sent again")
+	}
+	if h.botTable(back.sess) != table || back.sess.Username() != "anna" {
+		t.Errorf("resumed session %q has table %v, want anna at %v", back.sess.Username(), h.botTable(back.sess), table)
+	}
+
+	// Tokens can be used once
+	again := newTestClient(t, m, "")
+	if err := h.handleResume(again.sess, []string{CmdResume, token}); err != nil {
+		t.Fatal(err)
+	}
+	if !again.received("Cannot resume session") {
+		t.Error("the token was accepted twice")
+	}
+}
+
+func TestResumeTableExpired(t *testing.T) {
+	h, m, set := newDuplicateHandler(t)
+	m.ResumeWindow = 10 * time.Millisecond
+	anna := newTestClient(t, m, "anna")
+	dropTable(t, h, anna)
+
+	// After the resume window, the table is disconnected and the game archived
+	for deadline := time.Now().Add(2 * time.Second); h.botTable(anna.sess) != nil; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the table was kept after the resume window")
+		}
+	}
+	if _, err := h.archive.Load(set.GameID(0, 0)); err != nil {
+		t.Errorf("game not archived: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// handleSeason processes the season commands:
+//
+//	season list                  lists all seasons
+//	season show <number> [count] lists the best rated players of a season (default 20)
+//	season close [reset]         closes the current season and starts the next one with
+//	                             a soft reset of the ratings from 0 to 1 (admins only)
+func (h *Handler) handleSeason(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.seasons == nil || h.archive == nil {
+		return h.SendError(sess, "No seasons available")
+	}
+	if len(parts) < 2 {
+		return h.SendError(sess, "Invalid season format")
+	}
+
+	switch parts[1] {
+	case SeasonActionList:
+		return h.sendSeasons(sess)
+	case SeasonActionShow:
+		if len(parts) < 3 {
+			return h.SendError(sess, "Invalid season format")
+		}
+		number, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return h.SendError(sess, "Invalid season: %s", parts[2])
+		}
+		count := defaultRatingCount
+		if len(parts) >= 4 {
+			if count, err = strconv.Atoi(parts[3]); err != nil || count < 1 {
+				return h.SendError(sess, "Invalid count: %s", parts[3])
+			}
+		}
+		return h.sendSeason(sess, number, count)
+	case SeasonActionClose:
+		if !h.admins[sess.Username()] {
+			return h.SendError(sess, "Only admins can close a season")
+		}
+		reset := 0.0
+		if len(parts) >= 3 {
+			var err error
+			if reset, err = strconv.ParseFloat(parts[2], 64); err != nil {
+				return h.SendError(sess, "Invalid reset: %s", parts[2])
+			}
+		}
+		return h.closeSeason(sess, reset)
+	default:
+		return h.SendError(sess, "Invalid season action: %s", parts[1])
+	}
+}
+
+// sendSeasons sends "season info <number> <start> <end> <reset> <players>" per season,
+// newest first, followed by "season end". Times are RFC 3339 ("-" for the start of the
+// first season and the end of the current season); players is the number of rated
+// players of closed seasons.
+func (h *Handler) sendSeasons(sess *session.Session) error {
+	for _, s := range h.seasons.List() {
+		start, end := "-", "-"
+		if !s.Start.IsZero() {
+			start = s.Start.UTC().Format(time.RFC3339)
+		}
+		if s.Closed() {
+			end = s.End.UTC().Format(time.RFC3339)
+		}
+		if err := sess.WriteLine("%s %s %d %s %s %g %d", MsgSeason, SeasonActionInfo,
+			s.Number, start, end, s.Reset, len(s.Ratings)); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s", MsgSeason, SeasonActionEnd)
+}
+
+// sendSeason sends the best rated players of a season: the frozen final ratings of a
+// closed season or the current ratings of the current season, as
+// "season entry <number> <rank> <player> <rating> <deviation> <games>" per player,
+// followed by "season end <number> <algorithm>".
+func (h *Handler) sendSeason(sess *session.Session, number, count int) error {
+	s, err := h.seasons.Get(number)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	ratings, algorithm := s.Ratings, s.Algorithm
+	if !s.Closed() {
+		records, err := h.archive.Records(archive.Filter{})
+		if err != nil {
+			log.Printf("[%s] Failed to load games: %v", sess.ID(), err)
+			return h.SendError(sess, "Ratings not available")
+		}
+		if ratings, err = h.seasons.Ratings(h.rating, records); err != nil {
+			log.Printf("[%s] Failed to compute ratings: %v", sess.ID(), err)
+			return h.SendError(sess, "Ratings not available")
+		}
+		algorithm = h.rating
+	}
+
+	for i, r := range ratings {
+		if i == count {
+			break
+		}
+		if err := sess.WriteLine("%s %s %d %d %s %.0f %.0f %d", MsgSeason, SeasonActionEntry,
+			number, r.Rank, r.Player, r.Rating, r.Deviation, r.Games); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s %d %s", MsgSeason, SeasonActionEnd, number, algorithm)
+}
+
+// closeSeason freezes the final ratings of the current season and starts the next one.
+func (h *Handler) closeSeason(sess *session.Session, reset float64) error {
+	records, err := h.archive.Records(archive.Filter{})
+	if err != nil {
+		log.Printf("[%s] Failed to load games: %v", sess.ID(), err)
+		return h.SendError(sess, "Ratings not available")
+	}
+	closed, err := h.seasons.Close(sess.Username(), h.rating, records, reset)
+	if err != nil {
+		return h.SendError(sess, "Cannot close season: %v", err)
+	}
+	log.Printf("[%s] Closed season %d with %d rated players, reset %g", sess.ID(), closed.Number, len(closed.Ratings), reset)
+	return h.SendText(sess, "Season %d closed, season %d started", closed.Number, closed.Number+1)
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/stats"
+)
+
+// handleStats sends the statistics of a player: "stats [login]" (default: the own login).
+//
+// Response: "stats <login> games=<n> declarer=<n> winrate=<rate> value=<avg> bid=<avg>
+// passrate=<rate> overbids=<n> kontra=<n> re=<n> kontras=<n> ramsch=<n> ramschlost=<n>
+// [<game type>=<games>/<won> ...]".
+func (h *Handler) handleStats(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.archive == nil {
+		return h.SendError(sess, "No game archive available")
+	}
+
+	login := sess.Username()
+	if len(parts) >= 2 {
+		login = parts[1]
+	}
+
+	var collector *stats.Collector
+	var err error
+	if login == sess.Username() {
+		collector, err = h.archive.Stats()
+	} else {
+		// Of other players only the games everyone may see
+		collector, err = h.visibility().Stats(time.Now())
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to compute statistics: %v", sess.ID(), err)
+		return h.SendError(sess, "Statistics not available")
+	}
+	s, ok := collector.Player(login)
+	if !ok {
+		return h.SendError(sess, "No games of %s", login)
+	}
+	r := s.Report(login)
+
+	fields := []string{
+		fmt.Sprintf("games=%d", r.Games),
+		fmt.Sprintf("declarer=%d", r.DeclarerGames),
+		fmt.Sprintf("winrate=%.2f", r.DeclarerWinRate),
+		fmt.Sprintf("value=%.1f", r.AverageGameValue),
+		fmt.Sprintf("bid=%.1f", r.AverageBid),
+		fmt.Sprintf("passrate=%.2f", r.PassRate),
+		fmt.Sprintf("overbids=%d", r.Overbids),
+		fmt.Sprintf("kontra=%d", r.KontraGames),
+		fmt.Sprintf("re=%d", r.ReGames),
+		fmt.Sprintf("kontras=%d", r.Kontras),
+		fmt.Sprintf("ramsch=%d", r.RamschGames),
+		fmt.Sprintf("ramschlost=%d", r.RamschLosses),
+	}
+	types := make([]string, 0, len(s.ByGameType))
+	for name := range s.ByGameType {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	for _, name := range types {
+		fields = append(fields, fmt.Sprintf("%s=%d/%d", name, s.ByGameType[name].Games, s.ByGameType[name].Won))
+	}
+	return sess.WriteLine("%s %s %s", MsgStats, login, strings.Join(fields, " "))
+}
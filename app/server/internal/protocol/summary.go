@@ -0,0 +1,477 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// summaryDateLayout is the date format of the DT field ("2008-01-11/14:05:11/UTC").
+const summaryDateLayout = "2006-01-02/15:04:05/MST"
+
+// summaryServer is the server name written to the PC field.
+const summaryServer = "FreeSkat"
+
+// GameSummary is an ISS game record: the one-line text format ISS emits when a game ends
+// and uses in its archives, e.g.
+//
+//	(;GM[Skat]PC[International Skat Server]CO[]SE[]ID[1234]DT[2008-01-11/14:05:11/UTC]
+//	P0[a]P1[b]P2[c]R0[]R1[]R2[]MV[w <deal> 1 18 0 y ...]R[d:1 win v:48 ...] ;)
+type GameSummary struct {
+	// ID is the game ID
+	ID string
+	// Date is the time the game was dealt
+	Date time.Time
+	// Players are the player names by position (P0 = Forehand)
+	Players [3]string
+	// Moves are all moves including the deal and the skat revealed to the declarer
+	Moves []SummaryMove
+	// Result is the game result
+	Result SummaryResult
+}
+
+// SummaryMove is a single move of a game summary.
+type SummaryMove struct {
+	// Player is the player making the move (world for deal and skat)
+	Player skat.MovePlayer
+	// Token is the ISS move token
+	Token string
+}
+
+// SummaryResult is the result field of a game summary.
+// The ISS fields p0-p2, l, to and r are written with their neutral values.
+type SummaryResult struct {
+	// Passed is true if all players passed
+	Passed bool
+	// Declarer is the position of the declarer
+	Declarer skat.Player
+	// Won is true if the declarer won
+	Won bool
+	// Value is the score of the game (negative if lost)
+	Value int
+	// Matadors is the matador count (positive = with, negative = without)
+	Matadors int
+	// BidOK is false if the declarer overbid
+	BidOK bool
+	// Points are the declarer's card points
+	Points int
+	// Tricks is the number of tricks won by the declarer
+	Tricks int
+	// Schneider is true if Schneider was reached
+	Schneider bool
+	// Schwarz is true if Schwarz was reached
+	Schwarz bool
+	// Left is the position of the player who abandoned the game (nil if none)
+	Left *skat.Player
+	// Multiplier is the factor of Kontra and Re included in Value (1, 2 or 4; 0 = 1)
+	Multiplier int
+}
+
+// NewGameSummary creates the summary of a finished game record.
+func NewGameSummary(record *skat.GameRecord) (*GameSummary, error) {
+	summary := &GameSummary{ID: record.ID, Date: record.StartedAt}
+	for _, p := range skat.AllPlayers {
+		summary.Players[p.Index()] = record.Players[p]
+	}
+
+	deal := make([]string, 0, 4)
+	for _, p := range skat.AllPlayers {
+		deal = append(deal, record.Hands[p].Code())
+	}
+	deal = append(deal, record.Skat.Code())
+	summary.add(skat.MoveWorld, strings.Join(deal, "."))
+
+	var discards []skat.Card
+	game, err := record.Replay(func(game *skat.Game, action skat.Action) {
+		player := skat.MovePlayerFromPlayer(action.Player)
+		switch action.Type {
+		case skat.ActionBid:
+			summary.add(player, strconv.Itoa(action.Value))
+		case skat.ActionHold:
+			summary.add(player, TokenHoldBid)
+		case skat.ActionPass:
+			summary.add(player, TokenPass)
+		case skat.ActionPickUpSkat:
+			summary.add(player, TokenSkatRequest)
+			summary.add(skat.MoveWorld, game.Skat.Code())
+		case skat.ActionDiscard:
+			discards = action.Cards
+		case skat.ActionAnnounce:
+			summary.add(player, announcementToken(action, discards, game.Hands[action.Player]))
+		case skat.ActionPlayCard:
+			summary.add(player, action.Cards[0].Code())
+		case skat.ActionClaim, skat.ActionConcede, skat.ActionAcceptClaim, skat.ActionRejectClaim,
+			skat.ActionAbandon, skat.ActionRedeal:
+			summary.add(player, claimTokens[action.Type])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if game.State != skat.StateGameOver {
+		return nil, errors.New("game is not finished")
+	}
+
+	if result := game.Result; result != nil {
+		summary.Result = SummaryResult{
+			Declarer:   result.Declarer,
+			Won:        result.DeclarerWon,
+			Value:      result.Score,
+			Matadors:   result.Matadors,
+			BidOK:      !result.Overbid,
+			Points:     result.DeclarerPoints,
+			Tricks:     result.DeclarerTricks,
+			Schneider:  result.Schneider,
+			Schwarz:    result.Schwarz,
+			Multiplier: result.Multiplier,
+		}
+	} else {
+		summary.Result.Passed = true
+	}
+	summary.Result.Left = game.Abandoned
+	return summary, nil
+}
+
+// announcementToken encodes a game announcement: the contract, the discarded cards
+// and, in Ouvert games, the declarer's hand ("G.S8.DA", "NHO.C7.C8...").
+func announcementToken(action skat.Action, discards []skat.Card, hand *skat.Hand) string {
+	parts := []string{action.Contract.Code()}
+	if !action.Contract.Hand {
+		for _, c := range discards {
+			parts = append(parts, c.Code())
+		}
+	}
+	if action.Contract.Ouvert {
+		for _, c := range hand.Cards {
+			parts = append(parts, c.Code())
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// add appends a move.
+func (s *GameSummary) add(player skat.MovePlayer, token string) {
+	s.Moves = append(s.Moves, SummaryMove{Player: player, Token: token})
+}
+
+// Encode returns the summary in ISS format.
+func (s *GameSummary) Encode() string {
+	var b strings.Builder
+	b.WriteString("(;GM[Skat]PC[" + summaryServer + "]CO[]SE[]")
+	fmt.Fprintf(&b, "ID[%s]DT[%s]", s.ID, s.Date.UTC().Format(summaryDateLayout))
+	for i, name := range s.Players {
+		fmt.Fprintf(&b, "P%d[%s]", i, name)
+	}
+	b.WriteString("R0[]R1[]R2[]MV[")
+	for i, move := range s.Moves {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(move.Player.String() + " " + move.Token)
+	}
+	b.WriteString("]R[" + s.Result.Encode() + "] ;)")
+	return b.String()
+}
+
+// Encode returns the result in ISS format. A deal replayed after a defender left
+// is passed with the leaving player ("passed l:1"); games doubled by Kontra and Re
+// end with their multiplier ("k:2", "k:4").
+func (r SummaryResult) Encode() string {
+	left := -1
+	if r.Left != nil {
+		left = r.Left.Index()
+	}
+	if r.Passed && left >= 0 {
+		return fmt.Sprintf("passed l:%d", left)
+	}
+	if r.Passed {
+		return "passed"
+	}
+	won, bidOK := "loss", "overbid"
+	if r.Won {
+		won = "win"
+	}
+	if r.BidOK {
+		bidOK = "bidok"
+	}
+	encoded := fmt.Sprintf("d:%d %s v:%d m:%d %s p:%d t:%d s:%d z:%d p0:0 p1:0 p2:0 l:%d to:-1 r:0",
+		r.Declarer.Index(), won, r.Value, r.Matadors, bidOK, r.Points, r.Tricks,
+		boolToInt(r.Schneider), boolToInt(r.Schwarz), left)
+	if r.Multiplier > 1 {
+		encoded += fmt.Sprintf(" k:%d", r.Multiplier)
+	}
+	return encoded
+}
+
+// boolToInt returns 1 for true and 0 for false.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ParseGameSummary parses a game summary in ISS format.
+func ParseGameSummary(line string) (*GameSummary, error) {
+	fields, err := parseSummaryFields(line)
+	if err != nil {
+		return nil, err
+	}
+	if fields["GM"] != "Skat" {
+		return nil, fmt.Errorf("invalid game summary: unsupported game %q", fields["GM"])
+	}
+
+	summary := &GameSummary{ID: fields["ID"]}
+	if dt := fields["DT"]; dt != "" {
+		if summary.Date, err = time.Parse(summaryDateLayout, dt); err != nil {
+			return nil, fmt.Errorf("invalid game summary date: %s", dt)
+		}
+	}
+	for i := range summary.Players {
+		summary.Players[i] = fields[fmt.Sprintf("P%d", i)]
+	}
+
+	tokens := strings.Fields(fields["MV"])
+	if len(tokens)%2 != 0 {
+		return nil, errors.New("invalid game summary: incomplete move list")
+	}
+	for i := 0; i < len(tokens); i += 2 {
+		player, err := skat.MovePlayerFromCode(tokens[i])
+		if err != nil {
+			return nil, err
+		}
+		summary.add(player, tokens[i+1])
+	}
+
+	if summary.Result, err = ParseSummaryResult(fields["R"]); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// parseSummaryFields splits a summary into its "KEY[value]" fields.
+func parseSummaryFields(line string) (map[string]string, error) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "(;") || !strings.HasSuffix(line, ";)") {
+		return nil, errors.New("invalid game summary: missing (; ... ;)")
+	}
+	rest := strings.TrimSpace(line[2 : len(line)-2])
+
+	fields := make(map[string]string)
+	for rest != "" {
+		open := strings.IndexByte(rest, '[')
+		end := strings.IndexByte(rest, ']')
+		if open <= 0 || end < open {
+			return nil, fmt.Errorf("invalid game summary field: %s", rest)
+		}
+		fields[rest[:open]] = rest[open+1 : end]
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	return fields, nil
+}
+
+// ParseSummaryResult parses the result field of a game summary.
+func ParseSummaryResult(value string) (SummaryResult, error) {
+	result := SummaryResult{}
+	for _, part := range strings.Fields(value) {
+		key, val, hasValue := strings.Cut(part, ":")
+		if !hasValue {
+			switch key {
+			case "passed":
+				result.Passed = true
+			case "win":
+				result.Won = true
+			case "bidok":
+				result.BidOK = true
+			}
+			continue
+		}
+
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return result, fmt.Errorf("invalid game summary result: %s", part)
+		}
+		switch key {
+		case "d":
+			if result.Declarer, err = skat.PlayerFromIndex(n); err != nil {
+				return result, err
+			}
+		case "v":
+			result.Value = n
+		case "m":
+			result.Matadors = n
+		case "p":
+			result.Points = n
+		case "t":
+			result.Tricks = n
+		case "s":
+			result.Schneider = n == 1
+		case "z":
+			result.Schwarz = n == 1
+		case "k":
+			result.Multiplier = n
+		case "l":
+			if n >= 0 {
+				player, err := skat.PlayerFromIndex(n)
+				if err != nil {
+					return result, err
+				}
+				result.Left = &player
+			}
+		}
+	}
+	return result, nil
+}
+
+// Record converts the summary to a game record that can be replayed.
+// Moves the game engine cannot express (timeouts) end the conversion; the outcome of
+// such games is only available from Result.
+func (s *GameSummary) Record() (*skat.GameRecord, error) {
+	if len(s.Moves) == 0 || s.Moves[0].Player != skat.MoveWorld {
+		return nil, errors.New("game summary has no deal")
+	}
+	cards := strings.Split(s.Moves[0].Token, ".")
+	if len(cards) != 32 {
+		return nil, fmt.Errorf("invalid deal: expected 32 cards, got %d", len(cards))
+	}
+
+	record := &skat.GameRecord{
+		ID:        s.ID,
+		StartedAt: s.Date,
+		Players:   make(map[skat.Player]string),
+		Hands:     make(map[skat.Player]*skat.Hand),
+	}
+	for _, p := range skat.AllPlayers {
+		record.Players[p] = s.Players[p.Index()]
+		hand, err := skat.HandFromCode(strings.Join(cards[p.Index()*10:p.Index()*10+10], "."))
+		if err != nil {
+			return nil, err
+		}
+		record.Hands[p] = hand
+	}
+	skatCards, err := skat.HandFromCode(strings.Join(cards[30:], "."))
+	if err != nil {
+		return nil, err
+	}
+	record.Skat = skatCards
+
+	for _, move := range s.Moves[1:] {
+		player, ok := move.Player.ToPlayer()
+		if !ok {
+			continue // skat revealed to the declarer
+		}
+		actions, err := summaryActions(player, move.Token)
+		if err != nil {
+			return nil, err
+		}
+		if actions == nil {
+			break
+		}
+		record.Actions = append(record.Actions, actions...)
+	}
+
+	// ISS throws in deals that all players passed; a Ramsch would have card plays
+	if s.Result.Passed && s.Result.Left == nil {
+		record.AllPass = skat.AllPassThrowIn
+		for _, action := range record.Actions {
+			if action.Type == skat.ActionPlayCard {
+				record.AllPass = skat.AllPassRamsch
+				break
+			}
+		}
+	}
+	return record, nil
+}
+
+// summaryActions converts a move token to game actions (nil if the engine cannot express it).
+func summaryActions(player skat.Player, token string) ([]skat.Action, error) {
+	info, err := ParseMove(token)
+	if err != nil {
+		return nil, err
+	}
+
+	switch info.MoveType {
+	case MoveBid:
+		return []skat.Action{{Player: player, Type: skat.ActionBid, Value: info.BidValue}}, nil
+	case MoveHoldBid:
+		return []skat.Action{{Player: player, Type: skat.ActionHold}}, nil
+	case MovePass:
+		return []skat.Action{{Player: player, Type: skat.ActionPass}}, nil
+	case MoveSkatRequest:
+		return []skat.Action{{Player: player, Type: skat.ActionPickUpSkat}}, nil
+	case MoveCardPlay:
+		return []skat.Action{{Player: player, Type: skat.ActionPlayCard, Cards: []skat.Card{*info.Card}}}, nil
+	case MoveGameAnnouncement:
+		return announcementActions(player, token)
+	case MoveShowCards:
+		return []skat.Action{{Player: player, Type: skat.ActionClaim}}, nil
+	case MoveResign:
+		return []skat.Action{{Player: player, Type: skat.ActionConcede}}, nil
+	case MoveAcceptClaim:
+		return []skat.Action{{Player: player, Type: skat.ActionAcceptClaim}}, nil
+	case MoveRejectClaim:
+		return []skat.Action{{Player: player, Type: skat.ActionRejectClaim}}, nil
+	case MoveLeaveTable:
+		return []skat.Action{{Player: player, Type: skat.ActionAbandon}}, nil
+	case MoveRedeal:
+		return []skat.Action{{Player: player, Type: skat.ActionRedeal}}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// claimTokens are the move tokens of claims, concessions and their answers, and of
+// abandoned games.
+var claimTokens = map[skat.ActionType]string{
+	skat.ActionClaim:       TokenShowCards,
+	skat.ActionConcede:     TokenResign,
+	skat.ActionAcceptClaim: TokenAcceptClaim,
+	skat.ActionRejectClaim: TokenRejectClaim,
+	skat.ActionAbandon:     TokenLeaveTable,
+	skat.ActionRedeal:      TokenRedeal,
+}
+
+// announcementActions converts an announcement token to the discard (unless Hand) and
+// announce actions. Ouvert cards following the discards are not needed for replays.
+func announcementActions(player skat.Player, token string) ([]skat.Action, error) {
+	parts := strings.Split(token, ".")
+	contract, err := skat.ContractFromCode(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	announce := skat.Action{Player: player, Type: skat.ActionAnnounce, Contract: contract}
+	if contract.Hand {
+		return []skat.Action{announce}, nil
+	}
+
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("announcement without discarded cards: %s", token)
+	}
+	discards := make([]skat.Card, 2)
+	for i := range discards {
+		if discards[i], err = skat.CardFromCode(parts[i+1]); err != nil {
+			return nil, err
+		}
+	}
+	discard := skat.Action{Player: player, Type: skat.ActionDiscard, Cards: discards}
+	return []skat.Action{discard, announce}, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestGameSummary(t *testing.T) {
+	for _, seed := range []int64{1, 2, 3, 4, 5} {
+		record := recordtest.Played(t, seed)
+		summary, err := NewGameSummary(record)
+		if err != nil {
+			t.Fatalf("g%d: NewGameSummary() error: %v", seed, err)
+		}
+		line := summary.Encode()
+		if !strings.HasPrefix(line, "(;GM[Skat]PC[FreeSkat]") || !strings.HasSuffix(line, ";)") || strings.Contains(line, "\n") {
+			t.Fatalf("g%d: Encode() = %q", seed, line)
+		}
+
+		parsed, err := ParseGameSummary(line)
+		if err != nil {
+			t.Fatalf("g%d: ParseGameSummary() error: %v", seed, err)
+		}
+		// A multiplier of 1 is not written
+		if summary.Result.Multiplier == 1 {
+			summary.Result.Multiplier = 0
+		}
+		if !reflect.DeepEqual(parsed, summary) {
+			t.Errorf("g%d: parsed summary differs\n got %+v\nwant %+v", seed, parsed, summary)
+		}
+
+		// The imported record replays to the original result
+		imported, err := parsed.Record()
+		if err != nil {
+			t.Fatalf("g%d: Record() error: %v", seed, err)
+		}
+		want, _ := record.Replay(nil)
+		got, err := imported.Replay(nil)
+		if err != nil {
+			t.Fatalf("g%d: Replay() error: %v", seed, err)
+		}
+		if imported.ID != record.ID || !imported.StartedAt.Equal(record.StartedAt) || imported.Players[skat.Rearhand] != "carl" {
+			t.Errorf("g%d: imported %s at %v by %v", seed, imported.ID, imported.StartedAt, imported.Players)
+		}
+		if !reflect.DeepEqual(got.Result, want.Result) || !reflect.DeepEqual(got.RamschResult, want.RamschResult) {
+			t.Errorf("g%d: imported result %+v, want %+v", seed, got.Result, want.Result)
+		}
+	}
+}
+
+func TestParseGameSummary(t *testing.T) {
+	line := "(;GM[Skat]PC[International Skat Server]CO[]SE[]ID[1234]DT[2008-01-11/14:05:11/UTC]" +
+		"P0[anna]P1[ben]P2[carl]R0[]R1[]R2[]MV[w CJ.SJ.HJ.DJ.CA.SA.HA.DA.CT.ST.HT.DT.CK.SK.HK.DK.CQ.SQ.HQ.DQ.C9.S9.H9.D9.C8.S8.H8.D8.C7.S7.H7.D7 " +
+		"1 18 0 y 2 p 1 p 0 GH]R[d:0 win v:120 m:4 bidok p:0 t:0 s:0 z:0 p0:0 p1:0 p2:0 l:-1 to:-1 r:0 k:2] ;)"
+	summary, err := ParseGameSummary(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.ID != "1234" || summary.Date.Year() != 2008 || summary.Players != [3]string{"anna", "ben", "carl"} || len(summary.Moves) != 6 {
+		t.Errorf("summary = %+v", summary)
+	}
+	want := SummaryResult{Declarer: skat.Forehand, Won: true, Value: 120, Matadors: 4, BidOK: true, Multiplier: 2}
+	if summary.Result != want {
+		t.Errorf("Result = %+v, want %+v", summary.Result, want)
+	}
+
+	record, err := summary.Record()
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make([]skat.ActionType, len(record.Actions))
+	for i, action := range record.Actions {
+		types[i] = action.Type
+	}
+	if want := []skat.ActionType{skat.ActionBid, skat.ActionHold, skat.ActionPass, skat.ActionPass, skat.ActionAnnounce}; !reflect.DeepEqual(types, want) {
+		t.Errorf("actions = %v, want %v", types, want)
+	}
+
+	for _, invalid := range []string{
+		"GM[Skat]",
+		"(;GM[Chess];)",
+		"(;GM[Skat]DT[yesterday];)",
+		"(;GM[Skat]MV[w];)",
+		"(;GM[Skat]R[d:x];)",
+		"(;GM[Skat]ID[1;)",
+	} {
+		if _, err := ParseGameSummary(invalid); err == nil {
+			t.Errorf("ParseGameSummary(%q): expected error", invalid)
+		}
+	}
+}
+
+func TestSummaryResultEncode(t *testing.T) {
+	left := skat.Middlehand
+	tests := []struct {
+		result SummaryResult
+		want   string
+	}{
+		{SummaryResult{Passed: true}, "passed"},
+		{SummaryResult{Passed: true, Left: &left}, "passed l:1"},
+		{SummaryResult{Declarer: skat.Rearhand, Value: -96, Matadors: -2, Points: 45, Tricks: 4},
+			"d:2 loss v:-96 m:-2 overbid p:45 t:4 s:0 z:0 p0:0 p1:0 p2:0 l:-1 to:-1 r:0"},
+		{SummaryResult{Won: true, BidOK: true, Value: 192, Points: 120, Tricks: 10, Schneider: true, Schwarz: true, Multiplier: 4},
+			"d:0 win v:192 m:0 bidok p:120 t:10 s:1 z:1 p0:0 p1:0 p2:0 l:-1 to:-1 r:0 k:4"},
+	}
+	for _, tt := range tests {
+		if got := tt.result.Encode(); got != tt.want {
+			t.Errorf("Encode() = %q, want %q", got, tt.want)
+		}
+		parsed, err := ParseSummaryResult(tt.want)
+		if err != nil || !reflect.DeepEqual(parsed, tt.result) {
+			t.Errorf("ParseSummaryResult(%q) = %+v, %v, want %+v", tt.want, parsed, err, tt.result)
+		}
+	}
+}
@@ -0,0 +1,512 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/pkg/scoresheet"
+)
+
+// handleTournament processes the tournament commands:
+//
+//	tournament list                             lists all tournaments
+//	tournament create <name> [series]           creates a tournament directed by the client
+//	tournament register <name>                  registers the client for a tournament
+//	tournament unregister <name>                withdraws the registration
+//	tournament next <name>                      starts the next series (director only)
+//	tournament tables <name> [series]           lists the tables of a series (default the last)
+//	tournament standings <name>                 lists the Seeger-Fabian standings
+//	tournament watch <name>                     sends the standings now and after every finished deal
+//	tournament unwatch <name>                   stops the live standings
+//	tournament stakes <name> <cents> [variant]  makes the tables money games (director only)
+//	tournament result <name> [series]           lists the table results of a series (default the last)
+//	tournament tiebreaks <name> <tie-breaks>    sets the tie-breaks, e.g. "won,lost,points" (director only)
+//	tournament pairing <name> <pairing>         sets the pairing: auto, swiss or roundrobin (director only)
+//	tournament roster <name> <team> [player...] sets the players of a team, none removes it (director only)
+//	tournament teambest <name> <n>              counts the best n players per team and series, 0 all (director only)
+//	tournament stages <name> <stages>           sets the stages, e.g. "qualification:2:4:3,final:1:1:0" (director only)
+//	tournament profile <name> [profile]         shows or binds the rule profile: isko, club or none (director only)
+//
+// the director commands (see handleTournamentDirector) and the registration commands
+// (see handleTournamentRegistration).
+func (h *Handler) handleTournament(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	if h.tournaments == nil || h.archive == nil {
+		return h.SendError(sess, "No tournaments available")
+	}
+	if len(parts) < 2 {
+		return h.SendError(sess, "Invalid tournament format")
+	}
+
+	action := parts[1]
+	if action == TournamentActionList {
+		return h.sendTournaments(sess)
+	}
+	if len(parts) < 3 {
+		return h.SendError(sess, "Invalid tournament format")
+	}
+	name := parts[2]
+
+	switch action {
+	case TournamentActionCreate:
+		series := tournament.DefaultSeries
+		if len(parts) >= 4 {
+			n, err := strconv.Atoi(parts[3])
+			if err != nil {
+				return h.SendError(sess, "Invalid number of series: %s", parts[3])
+			}
+			series = n
+		}
+		if _, err := h.tournaments.Create(name, sess.Username(), series); err != nil {
+			return h.SendError(sess, "Cannot create tournament: %v", err)
+		}
+		log.Printf("[%s] Created tournament %s with %d series", sess.ID(), name, series)
+		return h.SendText(sess, "Tournament %s created", name)
+	case TournamentActionRegister, TournamentActionUnregister, TournamentActionCapacity, TournamentActionFee,
+		TournamentActionConfirm, TournamentActionDecline, TournamentActionRegistrations:
+		return h.handleTournamentRegistration(sess, action, name, parts[3:])
+	case TournamentActionNext:
+		return h.nextSeries(sess, name)
+	case TournamentActionTables:
+		series := 0
+		if len(parts) >= 4 {
+			n, err := strconv.Atoi(parts[3])
+			if err != nil {
+				return h.SendError(sess, "Invalid series: %s", parts[3])
+			}
+			series = n
+		}
+		return h.sendTournamentTables(sess, name, series)
+	case TournamentActionStandings:
+		return h.sendTournamentStandings(sess, name)
+	case TournamentActionStakes:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		cents, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return h.SendError(sess, "Invalid stakes: %s", parts[3])
+		}
+		stakes := scoresheet.Stakes{Cents: cents, Variant: scoresheet.VariantEveryone}
+		if len(parts) >= 5 {
+			if stakes.Variant, err = scoresheet.ParseVariant(parts[4]); err != nil {
+				return h.SendError(sess, "%v", err)
+			}
+		}
+		if err := h.tournaments.SetStakes(name, sess.Username(), stakes); err != nil {
+			return h.SendError(sess, "Cannot set stakes: %v", err)
+		}
+		log.Printf("[%s] Set stakes of tournament %s to %d cents per point (%s)", sess.ID(), name, cents, stakes.Variant)
+		return h.SendText(sess, "Stakes of tournament %s set to %d cents per point", name, cents)
+	case TournamentActionTieBreaks:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		tieBreaks, err := tournament.ParseTieBreaks(parts[3])
+		if err != nil {
+			return h.SendError(sess, "%v", err)
+		}
+		if err := h.tournaments.SetTieBreaks(name, sess.Username(), tieBreaks); err != nil {
+			return h.SendError(sess, "Cannot set tie-breaks: %v", err)
+		}
+		return h.SendText(sess, "Tie-breaks of tournament %s set to %s", name, parts[3])
+	case TournamentActionWatch:
+		return h.watchTournament(sess, name)
+	case TournamentActionUnwatch:
+		if !h.unwatchTournament(sess, name) {
+			return h.SendError(sess, "Not watching tournament %s", name)
+		}
+		return h.SendText(sess, "Stopped watching tournament %s", name)
+	case TournamentActionRoster:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		if err := h.tournaments.SetTeam(name, sess.Username(), parts[3], parts[4:]); err != nil {
+			return h.SendError(sess, "Cannot set team: %v", err)
+		}
+		return h.SendText(sess, "Team %s of tournament %s set to %d players", parts[3], name, len(parts[4:]))
+	case TournamentActionTeamBest:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		best, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return h.SendError(sess, "Invalid number of players: %s", parts[3])
+		}
+		if err := h.tournaments.SetTeamBest(name, sess.Username(), best); err != nil {
+			return h.SendError(sess, "Cannot set counted players: %v", err)
+		}
+		return h.SendText(sess, "Counted players per team of tournament %s set to %d", name, best)
+	case TournamentActionStages:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		stages, err := tournament.ParseStages(parts[3])
+		if err != nil {
+			return h.SendError(sess, "%v", err)
+		}
+		if err := h.tournaments.SetStages(name, sess.Username(), stages); err != nil {
+			return h.SendError(sess, "Cannot set stages: %v", err)
+		}
+		return h.SendText(sess, "Tournament %s has %d stages", name, len(stages))
+	case TournamentActionProfile:
+		if len(parts) < 4 {
+			return h.sendProfile(sess, name)
+		}
+		var profile *tournament.Profile
+		if parts[3] != "none" {
+			p, err := tournament.ParseProfile(parts[3])
+			if err != nil {
+				return h.SendError(sess, "%v", err)
+			}
+			profile = &p
+		}
+		if err := h.tournaments.SetProfile(name, sess.Username(), profile); err != nil {
+			return h.SendError(sess, "Cannot set rule profile: %v", err)
+		}
+		log.Printf("[%s] Set rule profile of tournament %s to %s", sess.ID(), name, parts[3])
+		return h.sendProfile(sess, name)
+	case TournamentActionPairing:
+		if len(parts) < 4 {
+			return h.SendError(sess, "Invalid tournament format")
+		}
+		pairing, err := tournament.ParsePairing(parts[3])
+		if err != nil {
+			return h.SendError(sess, "%v", err)
+		}
+		if err := h.tournaments.SetPairing(name, sess.Username(), pairing); err != nil {
+			return h.SendError(sess, "Cannot set pairing: %v", err)
+		}
+		return h.SendText(sess, "Pairing of tournament %s set to %s", name, pairing)
+	case TournamentActionAssist, TournamentActionPause, TournamentActionResume, TournamentActionAdjust,
+		TournamentActionSubstitute, TournamentActionExtend, TournamentActionDisqualify, TournamentActionAudit:
+		return h.handleTournamentDirector(sess, action, name, parts[3:])
+	case TournamentActionResult:
+		series := 0
+		if len(parts) >= 4 {
+			n, err := strconv.Atoi(parts[3])
+			if err != nil {
+				return h.SendError(sess, "Invalid series: %s", parts[3])
+			}
+			series = n
+		}
+		return h.sendSeriesResult(sess, name, series)
+	default:
+		return h.SendError(sess, "Invalid tournament action: %s", action)
+	}
+}
+
+// sendTournaments sends
+// "tournament info <name> <status> <director> <players> <started series>/<series>"
+// per tournament, followed by "tournament end".
+func (h *Handler) sendTournaments(sess *session.Session) error {
+	for _, t := range h.tournaments.List() {
+		if err := sess.WriteLine("%s %s %s %s %s %d %d/%d", MsgTournament, TournamentActionInfo,
+			t.Name, t.Status, t.Director, len(t.Players), len(t.Series), t.SeriesCount); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s", MsgTournament, TournamentActionEnd)
+}
+
+// sendProfile sends the rule profile of a tournament:
+// "tournament profile <name> <profile> kontra=<on|off> ramsch=<on|off> bock=<on|off> clock=<seconds>",
+// or "tournament profile <name> -" without profile.
+func (h *Handler) sendProfile(sess *session.Session, name string) error {
+	t, err := h.tournaments.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	profile := "-"
+	if t.Profile != nil {
+		profile = t.Profile.String()
+	}
+	return sess.WriteLine("%s %s %s %s", MsgTournament, TournamentActionProfile, name, profile)
+}
+
+// nextSeries starts the next series of a tournament, seated by the current standings,
+// and sends the result of the finished series and the new tables. After the last
+// series the tournament is finished.
+func (h *Handler) nextSeries(sess *session.Session, name string) error {
+	t, err := h.tournaments.Get(name)
+	if err != nil {
+		return h.SendError(sess, "Cannot start series: %v", err)
+	}
+	records, err := h.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		log.Printf("[%s] Failed to load tournament games: %v", sess.ID(), err)
+		return h.SendError(sess, "Standings not available")
+	}
+
+	series, err := h.tournaments.NextSeries(name, sess.Username(), records)
+	if err != nil {
+		return h.SendError(sess, "Cannot start series: %v", err)
+	}
+	if len(t.Series) > 0 {
+		if err := h.sendSeriesResult(sess, name, len(t.Series)); err != nil {
+			return err
+		}
+	}
+	if series == nil {
+		log.Printf("[%s] Finished tournament %s", sess.ID(), name)
+		return h.SendText(sess, "Tournament %s finished", name)
+	}
+	log.Printf("[%s] Started series %d of tournament %s", sess.ID(), series.Number, name)
+	h.announcePairings(sess, name, series)
+	if stage, first := t.StageOf(series.Number); stage > 1 && first {
+		h.announceQualified(name, stage)
+	}
+	return h.sendTournamentTables(sess, name, series.Number)
+}
+
+// announcePairings sends the pairings of a started series to the other logged-in
+// participants and directors of the tournament (the tournament lobby):
+// "tournament pairing <name> <series> <table> <player>..." per table, followed by
+// "tournament end <name>".
+func (h *Handler) announcePairings(sess *session.Session, name string, series *tournament.Series) {
+	t, err := h.tournaments.Get(name)
+	if err != nil || h.sessionManager == nil {
+		return
+	}
+	for _, other := range h.sessionManager.List() {
+		if other.ID() == sess.ID() || other.Username() == "" ||
+			!(t.IsDirector(other.Username()) || containsLogin(t.Players, other.Username())) {
+			continue
+		}
+		if err := sendPairings(other, name, series); err != nil {
+			log.Printf("[%s] Failed to send pairings: %v", other.ID(), err)
+		}
+	}
+}
+
+// sendPairings sends the pairings of a series to a session.
+func sendPairings(sess *session.Session, name string, series *tournament.Series) error {
+	for _, table := range series.Tables {
+		if err := sess.WriteLine("%s %s %s %d %d %s", MsgTournament, TournamentActionPairing,
+			name, series.Number, table.Number, strings.Join(table.Players, " ")); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgTournament, TournamentActionEnd, name)
+}
+
+// announceQualified notifies the logged-in players who advanced to a stage that has
+// just started: "tournament qualified <name> <stage> <stage name> <group>".
+func (h *Handler) announceQualified(name string, stage int) {
+	t, err := h.tournaments.Get(name)
+	if err != nil || h.sessionManager == nil {
+		return
+	}
+	groups := make(map[string]int)
+	for g, players := range t.Stages[stage-1].Assigned {
+		for _, p := range players {
+			groups[p] = g + 1
+		}
+	}
+	for _, other := range h.sessionManager.List() {
+		group, ok := groups[other.Username()]
+		if !ok {
+			continue
+		}
+		if err := other.WriteLine("%s %s %s %d %s %d", MsgTournament, TournamentActionQualified,
+			name, stage, t.Stages[stage-1].Name, group); err != nil {
+			log.Printf("[%s] Failed to send qualification: %v", other.ID(), err)
+		}
+	}
+}
+
+// containsLogin returns true if the logins contain the login.
+func containsLogin(logins []string, login string) bool {
+	for _, l := range logins {
+		if l == login {
+			return true
+		}
+	}
+	return false
+}
+
+// sendTournamentTables sends the tables of a series (0 = the last started):
+// "tournament table <name> <series> <table> <deals> <player>..." per table, each
+// followed by its Bock and Ramsch state
+// "tournament deal <name> <series> <table> <next deal> <mode> <bock deals> <ramsch deals>",
+// its director state "tournament status <name> <series> <table> <running|paused> <extension seconds>"
+// and its substitutes "tournament substitute <name> <series> <table> <deal> <out> <in>",
+// and finally "tournament end <name>".
+func (h *Handler) sendTournamentTables(sess *session.Session, name string, series int) error {
+	t, err := h.tournaments.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	if series == 0 {
+		series = len(t.Series)
+	}
+	if series < 1 || series > len(t.Series) {
+		return h.SendError(sess, "Series %d of tournament %s not started", series, name)
+	}
+	records, err := h.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		log.Printf("[%s] Failed to load tournament games: %v", sess.ID(), err)
+		return h.SendError(sess, "Tables not available")
+	}
+	schedules, err := t.Schedules(series, records)
+	if err != nil {
+		log.Printf("[%s] Failed to schedule tournament %s: %v", sess.ID(), name, err)
+		return h.SendError(sess, "Tables not available")
+	}
+
+	for i, table := range t.Series[series-1].Tables {
+		if err := sess.WriteLine("%s %s %s %d %d %d %s", MsgTournament, TournamentActionTable,
+			name, series, table.Number, table.Deals, strings.Join(table.Players, " ")); err != nil {
+			return err
+		}
+		if err := sess.WriteLine("%s %s %s %d %d %s", MsgTournament, TournamentActionDeal,
+			name, series, table.Number, encodeSchedule(schedules[i])); err != nil {
+			return err
+		}
+		status := "running"
+		if table.Paused {
+			status = "paused"
+		}
+		if err := sess.WriteLine("%s %s %s %d %d %s %d", MsgTournament, TournamentActionStatus,
+			name, series, table.Number, status, table.Extension); err != nil {
+			return err
+		}
+		for _, sub := range table.Substitutes {
+			if err := sess.WriteLine("%s %s %s %d %d %d %s %s", MsgTournament, TournamentActionSubstitute,
+				name, series, table.Number, sub.Deal, sub.Out, sub.In); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgTournament, TournamentActionEnd, name)
+}
+
+// encodeSchedule encodes the Bock and Ramsch state of a table as
+// "<next deal> <mode> <bock deals> <ramsch deals>".
+func encodeSchedule(s *tournament.Schedule) string {
+	return fmt.Sprintf("%d %s %d %d", s.Next, s.Mode(s.Next),
+		s.Count(tournament.ModeBock), s.Count(tournament.ModeRamsch))
+}
+
+// sendSeriesResult sends the result of a series (0 = the last started) per table:
+// "tournament result <name> <series> <table> <player> <score> <amount>" per player, best
+// first, and in money games "tournament transfer <name> <series> <table> <from> <to> <amount>"
+// per payment, followed by "tournament end <name>". Amounts are decimal (e.g. "-12.50").
+func (h *Handler) sendSeriesResult(sess *session.Session, name string, series int) error {
+	t, err := h.tournaments.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	if series == 0 {
+		series = len(t.Series)
+	}
+	if series < 1 || series > len(t.Series) {
+		return h.SendError(sess, "Series %d of tournament %s not started", series, name)
+	}
+	records, err := h.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		log.Printf("[%s] Failed to load tournament games: %v", sess.ID(), err)
+		return h.SendError(sess, "Result not available")
+	}
+	results, err := t.Results(series, records)
+	if err != nil {
+		log.Printf("[%s] Failed to settle tournament %s: %v", sess.ID(), name, err)
+		return h.SendError(sess, "Result not available")
+	}
+
+	for _, result := range results {
+		for _, b := range result.Settlement.Balances {
+			if err := sess.WriteLine("%s %s %s %d %d %s %d %s", MsgTournament, TournamentActionResult,
+				name, series, result.Table, b.Player, b.Score, scoresheet.FormatCents(b.Cents)); err != nil {
+				return err
+			}
+		}
+		for _, tr := range result.Settlement.Transfers {
+			if err := sess.WriteLine("%s %s %s %d %d %s %s %s", MsgTournament, TournamentActionTransfer,
+				name, series, result.Table, tr.From, tr.To, scoresheet.FormatCents(tr.Cents)); err != nil {
+				return err
+			}
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgTournament, TournamentActionEnd, name)
+}
+
+// sendTournamentStandings sends the standings of a tournament:
+// "tournament entry <name> <rank> <player> <won> <lost> <opponent> <score> <tie-break> <adjusted> <state>"
+// per player, in team tournaments "tournament team <name> <rank> <team> <score> <series scores>..."
+// per team, followed by "tournament end <name>". The tie-break is the one ranking the
+// player below the player with equal score directly above ("-" if none was needed),
+// adjusted is the sum of the director adjustments included in the score and the state
+// is "active" or "disqualified".
+func (h *Handler) sendTournamentStandings(sess *session.Session, name string) error {
+	t, err := h.tournaments.Get(name)
+	if err != nil {
+		return h.SendError(sess, "%v", err)
+	}
+	standings, err := h.tournamentStandings(t)
+	if err != nil {
+		log.Printf("[%s] Failed to rank tournament %s: %v", sess.ID(), name, err)
+		return h.SendError(sess, "Standings not available")
+	}
+	return h.writeStandings(sess, name, standings, t.TeamStandings(standings))
+}
+
+// writeStandings writes the entries of the standings and the team standings, followed
+// by "tournament end <name>".
+func (h *Handler) writeStandings(sess *session.Session, name string, standings []tournament.Standing, teams []tournament.TeamStanding) error {
+	for _, st := range standings {
+		tieBreak := "-"
+		if st.TieBreak != "" {
+			tieBreak = string(st.TieBreak)
+		}
+		state := "active"
+		if st.Disqualified {
+			state = "disqualified"
+		}
+		if err := sess.WriteLine("%s %s %s %d %s %d %d %d %d %s %d %s", MsgTournament, TournamentActionEntry,
+			name, st.Rank, st.Player, st.Won, st.Lost, st.Opponent, st.Score, tieBreak, st.Adjusted, state); err != nil {
+			return err
+		}
+	}
+	for _, team := range teams {
+		series := make([]string, len(team.Series))
+		for i, score := range team.Series {
+			series[i] = strconv.Itoa(score)
+		}
+		if err := sess.WriteLine("%s %s %s %d %s %d %s", MsgTournament, TournamentActionTeam,
+			name, team.Rank, team.Team, team.Score, strings.Join(series, " ")); err != nil {
+			return err
+		}
+	}
+	return sess.WriteLine("%s %s %s", MsgTournament, TournamentActionEnd, name)
+}
+
+// tournamentStandings computes the standings of a tournament from the archived games.
+func (h *Handler) tournamentStandings(t *tournament.Tournament) ([]tournament.Standing, error) {
+	records, err := h.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		return nil, err
+	}
+	return t.Standings(records)
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/internal/moderation"
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/session"
+)
+
+// maxYellLength is the maximum length of a lobby chat message
+const maxYellLength = 300
+
+// OnYell sets a function called with every lobby chat message of a client, e.g. to
+// relay it to a chat bridge. It must be set before the handler is used.
+func (h *Handler) OnYell(fn func(login, text string)) {
+	h.onYell = fn
+}
+
+// handleYell processes "yell <text>": the lobby chat message is sent to all logged-in
+// clients as "yell <login> <text>".
+func (h *Handler) handleYell(sess *session.Session, parts []string) error {
+	if sess.Username() == "" {
+		return h.SendError(sess, "Login required")
+	}
+	text, ok, err := h.chatText(sess, strings.Join(parts[1:], " "), maxYellLength)
+	if !ok {
+		return err
+	}
+
+	h.yell(sess.Username(), text)
+	if h.onYell != nil {
+		h.onYell(sess.Username(), text)
+	}
+	return nil
+}
+
+// Yell sends a lobby chat message to all logged-in clients and returns the number of
+// recipients. Bridges use it for messages from outside the server, which are sanitized
+// and moderated like those of clients; their senders are never muted.
+func (h *Handler) Yell(sender, text string) int {
+	var err error
+	if h.moderation == nil {
+		text, err = h.sanitizer.Text(text, 0)
+	} else {
+		text, err = sanitize.Clean(text, 0)
+	}
+	if err != nil {
+		return 0
+	}
+	if h.moderation != nil {
+		d := h.moderation.Check(context.Background(), "", text)
+		if d.Action == moderation.ActionDrop || d.Action == moderation.ActionMute {
+			return 0
+		}
+		text = d.Text
+	}
+	return h.yell(strings.ReplaceAll(sanitize.Normalize(sender), " ", "_"), text)
+}
+
+// sendTextError sends the error of a message of at most max characters rejected by the
+// sanitizer.
+func (h *Handler) sendTextError(sess *session.Session, err error, max int) error {
+	switch {
+	case errors.Is(err, sanitize.ErrEmpty):
+		return h.SendError(sess, "Empty message")
+	case errors.Is(err, sanitize.ErrTooLong):
+		return h.SendError(sess, "Message too long (max %d characters)", max)
+	default:
+		return h.SendError(sess, "Message rejected: %v", err)
+	}
+}
+
+// yell sends a sanitized lobby chat message to all logged-in clients.
+func (h *Handler) yell(sender, text string) int {
+	sent := 0
+	for _, sess := range h.sessionManager.List() {
+		if sess.Username() == "" {
+			continue
+		}
+		if err := sess.WriteLine("%s %s %s", MsgYell, sender, text); err != nil {
+			log.Printf("[%s] Failed to send yell: %v", sess.ID(), err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
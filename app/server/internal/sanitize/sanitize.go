@@ -0,0 +1,210 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sanitize cleans user-generated text (chat messages, comments, login names)
+// before the server sends it to other clients.
+package sanitize
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Errors of the sanitizer.
+var (
+	ErrEmpty       = errors.New("text is empty")
+	ErrTooLong     = errors.New("text is too long")
+	ErrInvalidName = errors.New("name contains invalid characters")
+	ErrRejected    = errors.New("text rejected")
+)
+
+// DefaultMaxName is the default maximum length of a login name in characters.
+const DefaultMaxName = 32
+
+// maxCombining is the maximum number of combining marks after a character; longer
+// runs ("zalgo" text) are cut.
+const maxCombining = 2
+
+// Filter checks sanitized text, e.g. a profanity filter. It returns the text to send,
+// possibly masked, or an error to reject it.
+type Filter interface {
+	Filter(text string) (string, error)
+}
+
+// Sanitizer cleans user-generated text. The zero value has no name limit and no filter.
+type Sanitizer struct {
+	// MaxName is the maximum length of a login name in characters (0 = no limit)
+	MaxName int
+	// Filter is an optional filter run after the sanitization (nil = none)
+	Filter Filter
+}
+
+// New creates a sanitizer with the default name limit and without a filter.
+func New() *Sanitizer {
+	return &Sanitizer{MaxName: DefaultMaxName}
+}
+
+// Text sanitizes a message of at most max characters (0 = no limit): the text is
+// normalized and run through the filter.
+func (s *Sanitizer) Text(text string, max int) (string, error) {
+	text, err := Clean(text, max)
+	if err != nil {
+		return "", err
+	}
+	return s.filter(text)
+}
+
+// Clean normalizes a message of at most max characters (0 = no limit) without a
+// filter, e.g. for a chat moderation of its own.
+func Clean(text string, max int) (string, error) {
+	text = Normalize(text)
+	if text == "" {
+		return "", ErrEmpty
+	}
+	if max > 0 && utf8.RuneCountInString(text) > max {
+		return "", ErrTooLong
+	}
+	return text, nil
+}
+
+// Name checks a login name. Names must not change by the normalization (no spaces,
+// control or format characters) and must pass the filter unchanged.
+func (s *Sanitizer) Name(name string) (string, error) {
+	if name == "" {
+		return "", ErrEmpty
+	}
+	if s.MaxName > 0 && utf8.RuneCountInString(name) > s.MaxName {
+		return "", ErrTooLong
+	}
+	if Normalize(name) != name || strings.ContainsRune(name, ' ') {
+		return "", ErrInvalidName
+	}
+	filtered, err := s.filter(name)
+	if err != nil || filtered != name {
+		return "", ErrRejected
+	}
+	return name, nil
+}
+
+// filter runs the filter, if any.
+func (s *Sanitizer) filter(text string) (string, error) {
+	if s.Filter == nil {
+		return text, nil
+	}
+	return s.Filter.Filter(text)
+}
+
+// Normalize returns the text as a single line: invalid UTF-8, control and format
+// characters (e.g. zero-width spaces and bidi overrides) are removed, runs of white
+// space become a single space and long runs of combining marks are cut.
+func Normalize(text string) string {
+	var b strings.Builder
+	space, combining := false, 0
+	for _, r := range strings.ToValidUTF8(text, "") {
+		switch {
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			combining = 0
+			continue
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			continue
+		case unicode.In(r, unicode.Mn, unicode.Me):
+			if combining++; combining > maxCombining {
+				continue
+			}
+		default:
+			combining = 0
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// WordList is a Filter masking words of a list with "*", e.g. profanity.
+type WordList struct {
+	words map[string]bool
+}
+
+// NewWordList creates a filter for the words (case-insensitive).
+func NewWordList(words []string) *WordList {
+	w := &WordList{words: make(map[string]bool)}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			w.words[word] = true
+		}
+	}
+	return w
+}
+
+// LoadWordList loads a word list from a file with one word per line. Empty lines and
+// lines starting with "#" are skipped.
+func LoadWordList(path string) (*WordList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewWordList(words), nil
+}
+
+// Len returns the number of words of the list.
+func (w *WordList) Len() int {
+	return len(w.words)
+}
+
+// Filter masks the words of the list in the text. Words are runs of letters and digits.
+func (w *WordList) Filter(text string) (string, error) {
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		if w.words[strings.ToLower(string(runes[start:end]))] {
+			for i := start; i < end; i++ {
+				runes[i] = '*'
+			}
+		}
+		start = end
+	}
+	return string(runes), nil
+}
+
+// isWordRune returns true for the characters of words.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Me)
+}
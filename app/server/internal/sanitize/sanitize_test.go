@@ -0,0 +1,152 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sanitize
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "good game", "good game"},
+		{"white space", "  good \t game\r\n\n", "good game"},
+		{"control characters", "good\x00 \x1b[31mgame\x07", "good [31mgame"},
+		{"zero-width space", "go\u200bod", "good"},
+		{"bidi override", "\u202egood\u202c", "good"},
+		{"invalid UTF-8", "good\xff\xfe game", "good game"},
+		{"combining marks", "e\u0301\u0302", "e\u0301\u0302"},
+		{"zalgo", "e\u0301\u0302\u0303\u0304\u0305x", "e\u0301\u0302x"},
+		{"umlauts", "Grüße, Skatfreunde", "Grüße, Skatfreunde"},
+		{"only blanks", " \t\u200b ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		text string
+		max  int
+		want string
+		err  error
+	}{
+		{"hello", 5, "hello", nil},
+		{"  hello  ", 5, "hello", nil},
+		{"hellö!", 5, "", ErrTooLong},
+		{"Grüße", 5, "Grüße", nil},
+		{strings.Repeat("x", 1000), 0, strings.Repeat("x", 1000), nil},
+		{"\u200b\n", 10, "", ErrEmpty},
+	}
+	for _, tt := range tests {
+		got, err := Clean(tt.text, tt.max)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("Clean(%.20q, %d) = %q, %v, want %q, %v", tt.text, tt.max, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestName(t *testing.T) {
+	s := New()
+	s.Filter = NewWordList([]string{"idiot"})
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"anna", nil},
+		{"Jürgen_1977", nil},
+		{"", ErrEmpty},
+		{strings.Repeat("a", DefaultMaxName), nil},
+		{strings.Repeat("a", DefaultMaxName+1), ErrTooLong},
+		{"anna smith", ErrInvalidName},
+		{"anna\u200b", ErrInvalidName},
+		{"\u202eanna", ErrInvalidName},
+		{"anna\x00", ErrInvalidName},
+		{"idiot", ErrRejected},
+		{"Idiot", ErrRejected},
+	}
+	for _, tt := range tests {
+		got, err := s.Name(tt.name)
+		if !errors.Is(err, tt.err) || (err == nil && got != tt.name) {
+			t.Errorf("Name(%q) = %q, %v, want error %v", tt.name, got, err, tt.err)
+		}
+	}
+}
+
+// rejectFilter rejects texts containing "spam".
+type rejectFilter struct{}
+
+func (rejectFilter) Filter(text string) (string, error) {
+	if strings.Contains(text, "spam") {
+		return "", ErrRejected
+	}
+	return text, nil
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		text   string
+		want   string
+		err    error
+	}{
+		{"no filter", nil, " you  idiot ", "you idiot", nil},
+		{"masked", NewWordList([]string{"Idiot", " ", ""}), "you IDIOT, idiots!", "you *****, idiots!", nil},
+		{"rejected", rejectFilter{}, "buy spam", "", ErrRejected},
+		{"too long before the filter", rejectFilter{}, "spam spam spam spam spam", "", ErrTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sanitizer{Filter: tt.filter}
+			got, err := s.Text(tt.text, 20)
+			if got != tt.want || !errors.Is(err, tt.err) {
+				t.Errorf("Text(%q) = %q, %v, want %q, %v", tt.text, got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadWordList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("# profanity\nidiot\n\n  Depp \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	words, err := LoadWordList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", words.Len())
+	}
+	if got, _ := words.Filter("du Depp, #profanity"); got != "du ****, #profanity" {
+		t.Errorf("Filter() = %q", got)
+	}
+	if _, err := LoadWordList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadWordList(missing): expected error")
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package season divides the player ratings into seasons. The current season rates
+// the games since its start, starting with the final ratings of the previous season
+// moved toward the ratings of new players (soft reset). Closing a season freezes its
+// final ratings, one JSON file per season in a directory.
+package season
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+	"github.com/mkloubert/freeskat-server/pkg/rating"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// filePrefix and fileExt make the file names of the seasons ("season-<number>.json").
+const (
+	filePrefix = "season-"
+	fileExt    = ".json"
+)
+
+// ErrNotFound is returned for unknown seasons.
+var ErrNotFound = errors.New("season not found")
+
+// Season is a rating season.
+type Season struct {
+	// Number is the season number (starting at 1)
+	Number int `json:"number"`
+	// Start is the start of the season (zero for the first season)
+	Start time.Time `json:"start"`
+	// End is the end of a closed season (nil for the current season)
+	End *time.Time `json:"end,omitempty"`
+	// Reset is the soft reset of the ratings at the start (0 = carried over, 1 = full reset)
+	Reset float64 `json:"reset"`
+	// ClosedBy is the admin who closed the season
+	ClosedBy string `json:"closedBy,omitempty"`
+	// Algorithm is the algorithm of the final ratings
+	Algorithm rating.Algorithm `json:"algorithm,omitempty"`
+	// Ratings are the final ratings of a closed season, best first
+	Ratings []rating.Rating `json:"ratings,omitempty"`
+}
+
+// Closed returns true if the season has been closed.
+func (s *Season) Closed() bool {
+	return s.End != nil
+}
+
+// contains returns true if a game started in the season.
+func (s *Season) contains(record *skat.GameRecord) bool {
+	return !record.StartedAt.Before(s.Start) && (s.End == nil || record.StartedAt.Before(*s.End))
+}
+
+// Store keeps the seasons. It is safe for concurrent use.
+type Store struct {
+	dir     string
+	seasons []*Season
+	mu      sync.Mutex
+}
+
+// Open opens the store in dir, creating the directory if needed, and loads all seasons.
+// Without seasons, the first season is started.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{dir: dir}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), filePrefix) || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		season := &Season{}
+		if err := json.Unmarshal(data, season); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		s.seasons = append(s.seasons, season)
+	}
+	sort.Slice(s.seasons, func(i, j int) bool {
+		return s.seasons[i].Number < s.seasons[j].Number
+	})
+	for i, season := range s.seasons {
+		if season.Number != i+1 {
+			return nil, fmt.Errorf("season %d missing", i+1)
+		}
+	}
+
+	if len(s.seasons) == 0 {
+		first := &Season{Number: 1}
+		if err := s.write(first); err != nil {
+			return nil, err
+		}
+		s.seasons = append(s.seasons, first)
+	}
+	return s, nil
+}
+
+// Current returns a copy of the current season.
+func (s *Store) Current() *Season {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copySeason(s.seasons[len(s.seasons)-1])
+}
+
+// Get returns a copy of a season.
+func (s *Store) Get(number int) (*Season, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if number < 1 || number > len(s.seasons) {
+		return nil, ErrNotFound
+	}
+	return copySeason(s.seasons[number-1]), nil
+}
+
+// List returns copies of all seasons, newest first.
+func (s *Store) List() []*Season {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]*Season, 0, len(s.seasons))
+	for i := len(s.seasons) - 1; i >= 0; i-- {
+		list = append(list, copySeason(s.seasons[i]))
+	}
+	return list
+}
+
+// Ratings returns the ratings of the current season from the games: the games started
+// in the season are rated with the algorithm, starting with the final ratings of the
+// previous season after the soft reset.
+func (s *Store) Ratings(algorithm rating.Algorithm, records []*skat.GameRecord) ([]rating.Rating, error) {
+	s.mu.Lock()
+	current := copySeason(s.seasons[len(s.seasons)-1])
+	var start []rating.Rating
+	if len(s.seasons) > 1 {
+		start = rating.SoftReset(s.seasons[len(s.seasons)-2].Ratings, current.Reset)
+	}
+	s.mu.Unlock()
+
+	var games []*skat.GameRecord
+	for _, record := range records {
+		if current.contains(record) {
+			games = append(games, record)
+		}
+	}
+	return rating.ComputeFrom(algorithm, start, games)
+}
+
+// Close closes the current season at the current time, freezing its final ratings,
+// and starts the next season with the soft reset (0 = ratings carried over, 1 = full
+// reset). It returns the closed season.
+func (s *Store) Close(admin string, algorithm rating.Algorithm, records []*skat.GameRecord, reset float64) (*Season, error) {
+	if reset < 0 || reset > 1 {
+		return nil, fmt.Errorf("invalid reset: %g (want 0 to 1)", reset)
+	}
+	// The ratings are computed without the lock; closing twice at once is caught below
+	current := s.Current()
+	ratings, err := s.Ratings(algorithm, records)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.seasons) != current.Number {
+		return nil, errors.New("the season has already been closed")
+	}
+
+	now := time.Now()
+	closed := copySeason(current)
+	closed.End = &now
+	closed.ClosedBy = admin
+	closed.Algorithm = algorithm
+	closed.Ratings = ratings
+	if err := s.write(closed); err != nil {
+		return nil, err
+	}
+	next := &Season{Number: closed.Number + 1, Start: now, Reset: reset}
+	if err := s.write(next); err != nil {
+		return nil, err
+	}
+	s.seasons[len(s.seasons)-1] = closed
+	s.seasons = append(s.seasons, next)
+	return copySeason(closed), nil
+}
+
+// write writes a season file. The caller must hold the lock (or own the store).
+func (s *Store) write(season *Season) error {
+	return jsonfile.Write(filepath.Join(s.dir, fmt.Sprintf("%s%d%s", filePrefix, season.Number, fileExt)), season)
+}
+
+// copySeason returns a copy of a season.
+func copySeason(season *Season) *Season {
+	c := *season
+	if season.End != nil {
+		end := *season.End
+		c.End = &end
+	}
+	c.Ratings = append([]rating.Rating(nil), season.Ratings...)
+	return &c
+}
@@ -17,21 +17,85 @@ package server
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/mkloubert/freeskat-server/internal/api"
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/async"
+	"github.com/mkloubert/freeskat-server/internal/ban"
+	"github.com/mkloubert/freeskat-server/internal/botpool"
+	"github.com/mkloubert/freeskat-server/internal/challenge"
 	"github.com/mkloubert/freeskat-server/internal/config"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/discord"
+	"github.com/mkloubert/freeskat-server/internal/i18n"
+	"github.com/mkloubert/freeskat-server/internal/league"
+	"github.com/mkloubert/freeskat-server/internal/live"
+	"github.com/mkloubert/freeskat-server/internal/moderation"
+	"github.com/mkloubert/freeskat-server/internal/motd"
+	"github.com/mkloubert/freeskat-server/internal/notify"
+	"github.com/mkloubert/freeskat-server/internal/profile"
 	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/internal/sanitize"
+	"github.com/mkloubert/freeskat-server/internal/season"
 	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/internal/tournament"
+	"github.com/mkloubert/freeskat-server/internal/visibility"
+	"github.com/mkloubert/freeskat-server/internal/webhook"
+	"github.com/mkloubert/freeskat-server/internal/ws"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/events"
+	"github.com/mkloubert/freeskat-server/pkg/rating"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
 )
 
+// idleCheckInterval is how often idle sessions are closed.
+const idleCheckInterval = time.Minute
+
+// turnCheckInterval is how often waiting turns are checked for notifications.
+const turnCheckInterval = 30 * time.Second
+
+// asyncCheckInterval is how often the deadlines of correspondence games are checked.
+const asyncCheckInterval = time.Minute
+
 // Server represents the FreeSkat TCP server.
 type Server struct {
 	config         *config.Config
 	listener       net.Listener
 	sessionManager *session.Manager
+	botPool        *botpool.Pool
+	archive        *archive.Archive
+	daily          *daily.Schedule
+	duplicate      *duplicate.Set
+	tournaments    *tournament.Store
+	leagues        *league.Store
+	seasons        *season.Store
+	bans           *ban.Store
+	mutes          *moderation.Mutes
+	profiles       *profile.Store
+	challenges     *challenge.Tracker
+	events         *live.Hub
+	stream         *events.Stream
+	webhooks       *webhook.Notifier
+	payments       *webhook.Notifier
+	discord        *discord.Bridge
+	discordDone    chan struct{}
+	httpServer     *http.Server
 	handler        *protocol.Handler
+	catalog        *i18n.Catalog
+	messages       *motd.Messages
 	wg             sync.WaitGroup
 	ctx            context.Context
 	cancel         context.CancelFunc
@@ -40,20 +104,56 @@ type Server struct {
 // New creates a new server instance.
 func New(cfg *config.Config) *Server {
 	ctx, cancel := context.WithCancel(context.Background())
-	sessionManager := session.NewManager()
+	sessionManager := session.NewManager(ctx)
+	sessionManager.Timeouts = cfg.Timeouts()
+	sessionManager.MaxLineLength = cfg.MaxLineLength
+	sessionManager.MaxViolations = cfg.MaxViolations
+	logSessions(sessionManager)
+
+	// Validated by config.Validate
+	difficulty, _ := ai.ParseDifficulty(cfg.BotDifficulty)
+	botPool := botpool.New("bot", cfg.BotCount, cfg.MaxBotGames, difficulty)
+	handler := protocol.NewHandler(sessionManager, botPool)
+	handler.SetAdmins(cfg.AdminLogins())
+	stream := events.NewStream()
+	handler.SetStream(stream)
 
 	return &Server{
 		config:         cfg,
 		sessionManager: sessionManager,
-		handler:        protocol.NewHandler(sessionManager),
+		botPool:        botPool,
+		events:         live.NewHub(),
+		stream:         stream,
+		handler:        handler,
 		ctx:            ctx,
 		cancel:         cancel,
 	}
 }
 
+// logSessions logs the creation and the end of sessions.
+func logSessions(m *session.Manager) {
+	m.OnCreate(func(sess *session.Session) {
+		log.Printf("[%s] Session created from %s", sess.ID(), sess.RemoteAddr())
+	})
+	m.OnClose(func(sess *session.Session) {
+		if errors.Is(context.Cause(sess.Context()), session.ErrKicked) {
+			log.Printf("[%s] Session kicked", sess.ID())
+			return
+		}
+		log.Printf("[%s] Session removed", sess.ID())
+	})
+}
+
+// Events returns the typed event stream of the games (PlayerJoined, GameStarted,
+// CardPlayed, GameFinished) for applications embedding the server.
+func (s *Server) Events() *events.Stream {
+	return s.stream
+}
+
 // Start starts the server and listens for connections.
 func (s *Server) Start() error {
-	listener, err := net.Listen("tcp", s.config.Address())
+	lc := net.ListenConfig{KeepAlive: s.config.TCPKeepAlive}
+	listener, err := lc.Listen(s.ctx, "tcp", s.config.Address())
 	if err != nil {
 		return err
 	}
@@ -61,12 +161,489 @@ func (s *Server) Start() error {
 
 	log.Printf("FreeSkat Server listening on %s", s.config.Address())
 	log.Printf("Protocol version: %d", protocol.ProtocolVersion)
+	log.Printf("Bot pool: %d bots (%s), max %d concurrent games", s.config.BotCount, s.config.BotDifficulty, s.config.MaxBotGames)
+
+	sanitizer := sanitize.New()
+	sanitizer.MaxName = s.config.MaxNameLength
+	var moderators []moderation.Moderator
+	if s.config.ProfanityFile != "" {
+		words, err := sanitize.LoadWordList(s.config.ProfanityFile)
+		if err != nil {
+			listener.Close()
+			return err
+		}
+		sanitizer.Filter = words
+		moderators = append(moderators, moderation.NewWordList(words))
+		log.Printf("Profanity filter: %d words", words.Len())
+	}
+	s.handler.SetSanitizer(sanitizer)
+
+	if s.config.ModerationURL != "" {
+		moderators = append(moderators, moderation.NewService(s.config.ModerationURL, s.config.ModerationSecret))
+		log.Printf("Chat moderation service: %s", s.config.ModerationURL)
+	}
+	s.mutes = moderation.NewMutes()
+	if s.config.ArchiveDir != "" {
+		if err = os.MkdirAll(s.config.StoreDir(), 0o755); err != nil {
+			listener.Close()
+			return err
+		}
+		if s.mutes, err = moderation.OpenMutes(s.storePath("mutes.json")); err != nil {
+			listener.Close()
+			return err
+		}
+	}
+	// Validated by config.Validate
+	policy, _ := moderation.ParsePolicy(s.config.ModerationPolicy)
+	chat := moderation.New(policy, s.mutes, moderators...)
+	chat.MuteDuration = s.config.MuteDuration
+	s.handler.SetModeration(chat)
+
+	s.catalog = i18n.New()
+	if s.config.MessagesDir != "" {
+		if err := s.catalog.LoadDir(s.config.MessagesDir); err != nil {
+			listener.Close()
+			return err
+		}
+	}
+	if !s.catalog.Has(s.config.Lang) {
+		listener.Close()
+		return fmt.Errorf("unknown language: %s (use %s)", s.config.Lang, strings.Join(s.catalog.Languages(), ", "))
+	}
+	s.handler.SetCatalog(s.catalog, s.config.Lang)
+	log.Printf("Languages: %s (default %s)", strings.Join(s.catalog.Languages(), ", "), s.config.Lang)
+
+	if s.messages, err = motd.New(s.config.WelcomeFile, s.config.MOTDFile); err != nil {
+		listener.Close()
+		return err
+	}
+	s.handler.SetMessages(s.messages)
+
+	if s.config.ArchiveDir != "" {
+		if s.archive, err = archive.Open(s.config.ArchiveDir); err != nil {
+			listener.Close()
+			return err
+		}
+		s.handler.SetArchive(s.archive)
+		log.Printf("Game archive: %s", s.config.ArchiveDir)
+		// Validated by config.Validate
+		algorithm, _ := rating.ParseAlgorithm(s.config.Rating)
+		s.handler.SetRating(algorithm)
+		if s.seasons, err = season.Open(filepath.Join(s.config.ArchiveDir, "seasons")); err != nil {
+			listener.Close()
+			return err
+		}
+		s.handler.SetSeasons(s.seasons)
+		if s.bans, err = ban.Open(s.storePath("bans.json")); err != nil {
+			listener.Close()
+			return err
+		}
+		s.handler.SetBans(s.bans)
+		if s.profiles, err = profile.Open(s.storePath("profiles.json")); err != nil {
+			listener.Close()
+			return err
+		}
+		s.handler.SetProfiles(s.profiles)
+		// Validated by config.Validate
+		location, _ := time.LoadLocation(s.config.DailyTimezone)
+		if s.challenges, err = challenge.Open(s.storePath("challenges.json"), s.config.DailySecret, location); err != nil {
+			listener.Close()
+			return err
+		}
+		s.challenges.Ignore = func(login string) bool {
+			return s.botPool.IsBot(login) || daily.IsBot(login)
+		}
+		s.handler.SetChallenges(s.challenges)
+		if s.config.TurnNotifications > 0 {
+			notifications, err := notify.Open(s.storePath("notifications.json"))
+			if err != nil {
+				listener.Close()
+				return err
+			}
+			s.handler.SetNotifications(notifications, s.config.TurnNotifications)
+			go s.handler.RunTurnNotifications(s.ctx, turnCheckInterval)
+			log.Printf("Turn notifications: after %s", s.config.TurnNotifications)
+		}
+		if s.config.AsyncMoveTime > 0 {
+			games, err := async.Open(s.storePath("async.json"))
+			if err != nil {
+				listener.Close()
+				return err
+			}
+			vacations, err := async.OpenVacations(s.storePath("vacations.json"))
+			if err != nil {
+				listener.Close()
+				return err
+			}
+			s.handler.SetAsync(games, vacations, s.config.AsyncMoveTime)
+			go s.handler.RunAsyncClocks(s.ctx, asyncCheckInterval)
+			log.Printf("Correspondence games: %s per move", s.config.AsyncMoveTime)
+		}
+		log.Printf("Player ratings: %s, season %d", algorithm, s.seasons.Current().Number)
+		if s.config.MistakeAnalysis > 0 {
+			s.handler.SetMistakeAnalysis(s.config.MistakeAnalysis)
+			log.Printf("Mistake analysis: card plays losing %d+ card points", s.config.MistakeAnalysis)
+		}
+		if urls := s.config.WebhookURLs(); len(urls) > 0 {
+			s.webhooks = webhook.New(urls, s.config.WebhookSecret)
+			log.Printf("Webhooks: %d URL(s)", len(urls))
+		}
+		s.startDaily()
+		if s.config.DuplicateSet != "" {
+			if s.duplicate, err = s.openDuplicate(); err != nil {
+				listener.Close()
+				return err
+			}
+			s.handler.SetDuplicate(s.duplicate)
+		}
+		if s.tournaments, err = tournament.Open(filepath.Join(s.config.ArchiveDir, "tournaments")); err != nil {
+			listener.Close()
+			return err
+		}
+		s.handler.SetTournaments(s.tournaments)
+		s.handler.SetEvents(s.events)
+		if s.config.PaymentURL != "" {
+			s.payments = webhook.New([]string{s.config.PaymentURL}, s.config.PaymentSecret)
+			log.Printf("Payment service: %s", s.config.PaymentURL)
+		}
+		s.tournaments.OnRegistration(s.registrationChanged)
+		s.archive.OnSave(s.gameSaved)
+		// Validated by config.Validate
+		bock, _ := tournament.ParseRules(s.config.Bock, s.config.BockRounds)
+		s.tournaments.SetBock(bock)
+		if bock.Enabled() {
+			log.Printf("Bock rules: %s", bock)
+		}
+		if s.leagues, err = league.Open(filepath.Join(s.config.ArchiveDir, "leagues")); err != nil {
+			listener.Close()
+			return err
+		}
+		s.leagues.SetBock(bock)
+		s.handler.SetLeagues(s.leagues)
+		go s.leagues.Run(s.ctx, func(l *league.League, round *league.Round) {
+			log.Printf("League %s: round %d started with %d tables", l.Name, round.Number, len(round.Tables))
+		})
+		if n, err := s.handler.RestoreAdjourned(); err != nil {
+			log.Printf("Failed to restore adjourned games: %v", err)
+		} else if n > 0 {
+			log.Printf("Adjourned games: %d waiting for their players", n)
+		}
+	}
+	if s.config.DiscordWebhook != "" {
+		s.startDiscord()
+	}
+	if s.config.HTTPAddress != "" {
+		s.startHTTP()
+	}
 
 	go s.acceptLoop()
+	go s.closeIdle()
+	if s.config.KeepAlive > 0 {
+		go s.sendKeepalives()
+	}
 
 	return nil
 }
 
+// closeIdle periodically closes the sessions idle longer than their idle timeout.
+func (s *Server) closeIdle() {
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			if n := s.sessionManager.CloseIdle(); n > 0 {
+				log.Printf("Closed %d idle session(s)", n)
+			}
+		}
+	}
+}
+
+// sendKeepalives periodically sends keepalives to the sessions without output for the
+// keepalive time.
+func (s *Server) sendKeepalives() {
+	ticker := time.NewTicker(max(s.config.KeepAlive/4, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.sessionManager.SendKeepalives(s.config.KeepAlive)
+		}
+	}
+}
+
+// startDiscord relays the game events, new tournament series and the lobby chat to Discord.
+func (s *Server) startDiscord() {
+	s.discord = discord.New(discord.Config{
+		WebhookURL: s.config.DiscordWebhook,
+		Events:     s.config.DiscordEventList(),
+		MinValue:   s.config.DiscordMinValue,
+		BotToken:   s.config.DiscordBotToken,
+		Channel:    s.config.DiscordChannel,
+	})
+	// The subscription ends when the stream is closed on shutdown
+	sub, _ := s.stream.Subscribe(256)
+	s.discordDone = make(chan struct{})
+	go func() {
+		defer close(s.discordDone)
+		for event := range sub {
+			// Games whose deal is still secret are not posted
+			if e, ok := event.(events.GameFinished); ok && s.visibility().Hidden(e.Record.ID, "", time.Now()) {
+				continue
+			}
+			s.discord.Event(event)
+		}
+	}()
+	if s.tournaments != nil {
+		s.tournaments.OnSeries(s.discord.SeriesStarted)
+	}
+	log.Printf("Discord: %s", strings.Join(s.config.DiscordEventList(), ", "))
+	if s.config.DiscordBotToken != "" {
+		s.handler.OnYell(s.discord.Chat)
+		go s.discord.ReadChat(s.ctx, func(sender, text string) {
+			s.handler.Yell(sender, text)
+		})
+		log.Printf("Discord chat bridge: channel %s", s.config.DiscordChannel)
+	}
+}
+
+// openDuplicate loads the duplicate set of -duplicate-set with its archived games, so
+// deals played at every table before a restart stay revealed.
+func (s *Server) openDuplicate() (*duplicate.Set, error) {
+	f, err := os.Open(s.config.DuplicateSet)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cfg, err := duplicate.ReadConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.config.DuplicateSet, err)
+	}
+	set, err := duplicate.NewSet(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.config.DuplicateSet, err)
+	}
+
+	records, err := s.archive.Records(archive.Filter{Prefix: cfg.Name + "-"})
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if err := set.Add(record); err != nil && !errors.Is(err, duplicate.ErrUnknownGame) {
+			log.Printf("Game %s of duplicate set %s not counted: %v", record.ID, cfg.Name, err)
+		}
+	}
+	log.Printf("Duplicate set: %s, %d deals at %d tables", cfg.Name, cfg.Deals, len(cfg.Tables))
+	return set, nil
+}
+
+// startDaily enables the deal of the day and logs each new deal.
+func (s *Server) startDaily() {
+	secret := s.config.DailySecret
+	if secret == "" {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			log.Printf("Daily deal disabled: %v", err)
+			return
+		}
+		secret = hex.EncodeToString(buf)
+		log.Printf("No daily secret configured, the deal of the day changes with every start")
+	}
+
+	// Validated by config.Validate
+	location, _ := time.LoadLocation(s.config.DailyTimezone)
+	s.daily = daily.NewSchedule(secret, location)
+	s.handler.SetDaily(s.daily)
+	if s.config.DailyClock > 0 {
+		s.handler.SetDailyClock(time.Duration(s.config.DailyClock) * time.Second)
+		log.Printf("Daily deal clock: %d seconds per player", s.config.DailyClock)
+	}
+	if s.config.DailyForfeit > 0 {
+		s.handler.SetDailyForfeit(s.config.DailyForfeit)
+		log.Printf("Daily deal forfeit after a disconnect: %s", s.config.DailyForfeit)
+	}
+
+	previous := ""
+	go s.daily.Run(s.ctx, func(deal *daily.Deal) {
+		log.Printf("Daily deal of %s (position %s)", deal.Date, deal.Position)
+		if previous != "" && previous != deal.Date {
+			s.dailyFinished(previous)
+		}
+		previous = deal.Date
+	})
+}
+
+// dailyFinished posts the final leaderboard of a daily deal to the webhooks.
+func (s *Server) dailyFinished(date string) {
+	if s.webhooks == nil {
+		return
+	}
+	records, err := s.archive.Records(archive.Filter{Prefix: daily.GamePrefix(date)})
+	if err == nil && len(records) == 0 {
+		return
+	}
+	var entries []daily.Entry
+	if err == nil {
+		entries, err = daily.Leaderboard(records)
+	}
+	if err != nil {
+		log.Printf("Failed to rank the daily deal of %s: %v", date, err)
+		return
+	}
+
+	games := make([]string, 0, len(entries))
+	standings := make([]webhook.Standing, 0, len(entries))
+	for _, e := range entries {
+		games = append(games, e.Game)
+		standings = append(standings, webhook.Standing{Rank: e.Rank, Player: e.Player, Games: 1, Score: e.Score})
+	}
+	s.webhooks.SeriesFinished("daily-"+date, games, standings)
+}
+
+// gameSaved notifies the webhooks of a saved game that everyone may see and publishes
+// the live standings of its tournament.
+func (s *Server) gameSaved(r *replay.Replay) {
+	if s.webhooks != nil && !s.visibility().Hidden(r.ID, "", time.Now()) {
+		s.webhooks.GameFinished(r)
+	}
+	if s.challenges != nil {
+		completions, err := s.challenges.Record(r, time.Now())
+		if err != nil {
+			log.Printf("Failed to track challenges of game %s: %v", r.ID, err)
+		}
+		s.handler.ChallengesCompleted(completions)
+	}
+	if s.duplicate != nil {
+		record, err := r.Record()
+		if err == nil {
+			err = s.duplicate.Add(record)
+		}
+		if err != nil && !errors.Is(err, duplicate.ErrUnknownGame) {
+			log.Printf("Game %s of duplicate set %s not counted: %v", r.ID, s.duplicate.Config().Name, err)
+		}
+	}
+
+	t, ok := s.tournaments.ForGame(r.ID)
+	if !ok {
+		return
+	}
+	records, err := s.archive.Records(archive.Filter{Prefix: t.GamePrefix()})
+	if err != nil {
+		log.Printf("Failed to load games of tournament %s: %v", t.Name, err)
+		return
+	}
+	standings, err := t.Standings(records)
+	if err != nil {
+		log.Printf("Failed to rank tournament %s: %v", t.Name, err)
+		return
+	}
+	s.events.Publish(live.StandingsEvent(t.Name, r.ID, standings, t.TeamStandings(standings)))
+}
+
+// registrationChanged asks the payment service to collect the seat fee of a pending
+// registration and notifies the player.
+func (s *Server) registrationChanged(name, player string, status tournament.Registration) {
+	log.Printf("Tournament %s: registration of %s %s", name, player, status)
+	if status == tournament.RegistrationPending && s.payments != nil {
+		if t, err := s.tournaments.Get(name); err == nil {
+			s.payments.PaymentRequired(name, player, t.Fee)
+		}
+	}
+	s.handler.RegistrationChanged(name, player, status)
+}
+
+// visibility returns the rules deciding which games are shown to everyone.
+func (s *Server) visibility() *visibility.Rules {
+	return visibility.New(s.archive, s.daily, s.duplicate)
+}
+
+// startHTTP starts the REST API.
+func (s *Server) startHTTP() {
+	handler := api.New(s.archive, s.events)
+	// Validated by config.Validate
+	algorithm, _ := rating.ParseAlgorithm(s.config.Rating)
+	handler.SetRating(algorithm)
+	if s.seasons != nil {
+		handler.SetSeasons(s.seasons)
+	}
+	if s.daily != nil {
+		handler.SetDaily(s.daily)
+	}
+	if s.duplicate != nil {
+		handler.SetDuplicate(s.duplicate)
+	}
+	if s.tournaments != nil {
+		handler.SetTournaments(s.tournaments)
+	}
+	if s.leagues != nil {
+		handler.SetLeagues(s.leagues)
+	}
+	if s.profiles != nil {
+		handler.SetProfiles(s.profiles)
+	}
+	if s.challenges != nil {
+		handler.SetChallenges(s.challenges)
+	}
+	if s.payments != nil {
+		handler.SetPaymentSecret(s.config.PaymentSecret)
+	}
+	if s.config.AdminToken != "" {
+		handler.SetAdmin(s.config.AdminToken, s.sessionManager, s.handler)
+		handler.SetBans(s.bans)
+		handler.SetMutes(s.mutes)
+		handler.SetMessages(s.messages)
+		if s.config.BackupDir != "" {
+			handler.SetBackupDir(s.config.BackupDir)
+			log.Printf("Admin API enabled, backups in %s", s.config.BackupDir)
+		} else {
+			log.Printf("Admin API enabled")
+		}
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.HandleFunc("GET /ws", s.handleWebSocket)
+	mux.HandleFunc("GET /ws/schema.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Write(ws.Schema)
+	})
+	s.httpServer = &http.Server{Addr: s.config.HTTPAddress, Handler: mux}
+	log.Printf("REST API and WebSocket transport (/ws) listening on %s", s.config.HTTPAddress)
+
+	go func() {
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("REST API error: %v", err)
+		}
+	}()
+}
+
+// handleWebSocket runs a client session on a WebSocket connection with the ISS lines
+// ("iss") or their JSON encoding ("iss-json").
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if s.sessionManager.Count() >= s.config.MaxConnections {
+		log.Printf("Max connections reached, rejecting WebSocket %s", r.RemoteAddr)
+		http.Error(w, "Too many connections", http.StatusServiceUnavailable)
+		return
+	}
+	conn, err := ws.Upgrade(w, r, ws.ProtocolLines, ws.ProtocolJSON)
+	if err != nil {
+		log.Printf("WebSocket upgrade of %s failed: %v", r.RemoteAddr, err)
+		return
+	}
+
+	sess := s.sessionManager.CreateSession(ws.NetConn(conn))
+	sess.SetTimeouts(s.config.WebSocketTimeouts())
+	sess.Transport = session.TransportWebSocket
+	sess.SetLang(s.catalog.Negotiate(r.Header.Get("Accept-Language")))
+	s.wg.Add(1)
+	s.handleConnection(sess)
+}
+
 // acceptLoop accepts incoming connections.
 func (s *Server) acceptLoop() {
 	for {
@@ -98,28 +675,62 @@ func (s *Server) acceptLoop() {
 // handleConnection handles a single client connection.
 func (s *Server) handleConnection(sess *session.Session) {
 	defer s.wg.Done()
-	defer s.sessionManager.RemoveSession(sess.ID)
+	// The ID changes when the session resumes a disconnected one
+	defer func() { s.sessionManager.RemoveSession(sess.ID()) }()
 
 	s.handler.HandleConnection(sess)
 }
 
+// ReloadMessages reads the templates of the welcome line and the message of the day
+// again.
+func (s *Server) ReloadMessages() error {
+	if err := s.messages.Reload(); err != nil {
+		return err
+	}
+	log.Printf("Welcome and MOTD templates reloaded")
+	return nil
+}
+
 // Shutdown gracefully shuts down the server.
 func (s *Server) Shutdown() {
 	log.Println("Shutting down server...")
 
-	// Signal shutdown
+	// Keep running games, so they can be resumed after the restart
+	s.handler.Adjourn()
+
+	// Signal shutdown, which also cancels the sessions
 	s.cancel()
 
 	// Close listener to stop accepting new connections
 	if s.listener != nil {
 		s.listener.Close()
 	}
+	if s.httpServer != nil {
+		// End the event streams, otherwise the HTTP server waits for them
+		s.events.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		s.httpServer.Shutdown(ctx)
+		cancel()
+	}
 
 	// Close all sessions
 	s.sessionManager.CloseAll()
 
 	// Wait for all handlers to finish
 	s.wg.Wait()
+	s.stream.Close()
+
+	// Deliver the results of the last games
+	if s.webhooks != nil {
+		s.webhooks.Close()
+	}
+	if s.payments != nil {
+		s.payments.Close()
+	}
+	if s.discord != nil {
+		<-s.discordDone
+		s.discord.Close()
+	}
 
 	log.Println("Server shutdown complete")
 }
@@ -128,3 +739,8 @@ func (s *Server) Shutdown() {
 func (s *Server) Wait() {
 	<-s.ctx.Done()
 }
+
+// storePath returns the path of a store file (bans, profiles, ...) in the store directory.
+func (s *Server) storePath(name string) string {
+	return filepath.Join(s.config.StoreDir(), name)
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"log"
+	"strings"
+	"sync"
+)
+
+// DefaultBroadcastQueue is the number of messages queued per subscriber of a broadcast.
+const DefaultBroadcastQueue = 64
+
+// Broadcast fans out lines to subscribed sessions, e.g. the observers of a table. A
+// message is formatted once and queued for every subscriber, whose own goroutine
+// writes it, so slow subscribers do not block the sender. Subscribers with a full queue
+// are detached.
+type Broadcast struct {
+	size     int
+	onDetach func(*Session)
+	subs     map[*Session]*subscriber
+	closed   bool
+	mu       sync.Mutex
+}
+
+// subscriber is the queue of a subscribed session.
+type subscriber struct {
+	queue    chan []byte
+	detached bool
+}
+
+// NewBroadcast creates a broadcast with queues of size messages per subscriber.
+// onDetach (optional) is called with detached subscribers, after which nothing more is
+// written to them by the broadcast.
+func NewBroadcast(size int, onDetach func(*Session)) *Broadcast {
+	return &Broadcast{size: size, onDetach: onDetach, subs: make(map[*Session]*subscriber)}
+}
+
+// Subscribe adds a session, which first receives the backlog lines. It returns false if
+// the broadcast is closed or the session is subscribed already.
+func (b *Broadcast) Subscribe(sess *Session, backlog ...string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || b.subs[sess] != nil {
+		return false
+	}
+	sub := &subscriber{queue: make(chan []byte, b.size+1)}
+	if len(backlog) > 0 {
+		sub.queue <- format(backlog)
+	}
+	b.subs[sess] = sub
+	go b.write(sess, sub)
+	return true
+}
+
+// Unsubscribe removes a session. The lines queued for it are still written.
+func (b *Broadcast) Unsubscribe(sess *Session) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remove(sess)
+}
+
+// Send queues lines for all subscribers. Subscribers with a full queue are detached.
+func (b *Broadcast) Send(lines ...string) {
+	if len(lines) == 0 {
+		return
+	}
+	message := format(lines)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sess, sub := range b.subs {
+		b.queue(sess, sub, message)
+	}
+}
+
+// SendFunc queues the lines of each subscriber, e.g. in its language. The function is
+// called with the broadcast locked. Subscribers with a full queue are detached.
+func (b *Broadcast) SendFunc(lines func(*Session) []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sess, sub := range b.subs {
+		if l := lines(sess); len(l) > 0 {
+			b.queue(sess, sub, format(l))
+		}
+	}
+}
+
+// queue queues a message for a subscriber or detaches it if its queue is full. The
+// caller must hold the lock.
+func (b *Broadcast) queue(sess *Session, sub *subscriber, message []byte) {
+	select {
+	case sub.queue <- message:
+	default:
+		log.Printf("[%s] Too slow for the broadcast, detached", sess.ID())
+		sub.detached = true
+		b.remove(sess)
+	}
+}
+
+// Len returns the number of subscribers.
+func (b *Broadcast) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Close removes all subscribers after their queued lines; later subscriptions fail.
+func (b *Broadcast) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sess := range b.subs {
+		b.remove(sess)
+	}
+}
+
+// remove closes the queue of a subscriber. The caller must hold the lock.
+func (b *Broadcast) remove(sess *Session) bool {
+	sub := b.subs[sess]
+	if sub == nil {
+		return false
+	}
+	delete(b.subs, sess)
+	close(sub.queue)
+	return true
+}
+
+// write writes the queued messages of a subscriber until its queue is closed. The rest
+// of the queue of detached subscribers and after a failed write is dropped.
+func (b *Broadcast) write(sess *Session, sub *subscriber) {
+	failed := false
+	for message := range sub.queue {
+		b.mu.Lock()
+		detached := sub.detached
+		b.mu.Unlock()
+		if detached || failed {
+			continue
+		}
+		if err := sess.WriteLines(message); err != nil {
+			log.Printf("[%s] Failed to send broadcast: %v", sess.ID(), err)
+			failed = true
+			b.mu.Lock()
+			if b.subs[sess] == sub {
+				b.remove(sess)
+			}
+			b.mu.Unlock()
+		}
+	}
+	if sub.detached && b.onDetach != nil {
+		b.onDetach(sess)
+	}
+}
+
+// format formats lines as one message.
+func format(lines []string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// subscriberClient collects the lines a session receives.
+type subscriberClient struct {
+	sess  *Session
+	mu    sync.Mutex
+	lines []string
+}
+
+func newSubscriberClient(t *testing.T, m *Manager) *subscriberClient {
+	t.Helper()
+	sess, conn := newPipeSession(t, m)
+	c := &subscriberClient{sess: sess}
+	go c.read(conn)
+	return c
+}
+
+func (c *subscriberClient) read(conn net.Conn) {
+	lines := bufio.NewScanner(conn)
+	for lines.Scan() {
+		c.mu.Lock()
+		c.lines = append(c.lines, lines.Text())
+		c.mu.Unlock()
+	}
+}
+
+// await returns the lines once n are received (or after a second).
+func (c *subscriberClient) await(n int) []string {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		c.mu.Lock()
+		if len(c.lines) >= n {
+			c.mu.Unlock()
+			break
+		}
+		c.mu.Unlock()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.lines)
+}
+
+func TestBroadcast(t *testing.T) {
+	m := NewManager(context.Background())
+	anna, ben := newSubscriberClient(t, m), newSubscriberClient(t, m)
+	b := NewBroadcast(DefaultBroadcastQueue, nil)
+
+	if !b.Subscribe(anna.sess, "backlog 1", "backlog 2") || !b.Subscribe(ben.sess) {
+		t.Fatal("Subscribe() = false")
+	}
+	if b.Subscribe(anna.sess) || b.Len() != 2 {
+		t.Errorf("second Subscribe() = true or Len() = %d, want 2", b.Len())
+	}
+	b.Send("table t1 anna play 0 CJ")
+	b.SendFunc(func(sess *Session) []string {
+		if sess == ben.sess {
+			return nil
+		}
+		return []string{"text for " + sess.ID()}
+	})
+	if !b.Unsubscribe(ben.sess) || b.Unsubscribe(ben.sess) {
+		t.Error("Unsubscribe() does not remove the session once")
+	}
+	b.Send("after ben left")
+	b.Close()
+	b.Send("after the close")
+	if b.Subscribe(ben.sess) || b.Len() != 0 {
+		t.Errorf("Subscribe() after Close() = true or Len() = %d, want 0", b.Len())
+	}
+
+	wantAnna := []string{"backlog 1", "backlog 2", "table t1 anna play 0 CJ", "text for " + anna.sess.ID(), "after ben left"}
+	wantBen := []string{"table t1 anna play 0 CJ"}
+	anna.await(len(wantAnna))
+	ben.await(len(wantBen))
+	// Nothing else arrives
+	time.Sleep(20 * time.Millisecond)
+	if got := anna.await(0); !slices.Equal(got, wantAnna) {
+		t.Errorf("anna received %q, want %q", got, wantAnna)
+	}
+	if got := ben.await(0); !slices.Equal(got, wantBen) {
+		t.Errorf("ben received %q, want %q", got, wantBen)
+	}
+}
+
+func TestBroadcastSlowSubscriber(t *testing.T) {
+	m := NewManager(context.Background())
+	fast := newSubscriberClient(t, m)
+	// Nobody reads the connection of the slow subscriber
+	slow, _ := newPipeSession(t, m)
+	slow.WriteTimeout = 50 * time.Millisecond
+	detached := make(chan *Session, 1)
+	b := NewBroadcast(2, func(sess *Session) { detached <- sess })
+	b.Subscribe(fast.sess)
+	b.Subscribe(slow)
+
+	// The sender is never blocked by the slow subscriber
+	start := time.Now()
+	for i := 1; i <= 10; i++ {
+		b.Send(fmt.Sprintf("line %d", i))
+		time.Sleep(time.Millisecond)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("sending took %v", elapsed)
+	}
+	if b.Len() != 1 {
+		t.Errorf("Len() = %d, want the fast subscriber only", b.Len())
+	}
+	select {
+	case sess := <-detached:
+		if sess != slow {
+			t.Errorf("detached %s, want the slow subscriber", sess.ID())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the slow subscriber was not detached")
+	}
+	if got := fast.await(10); len(got) != 10 || got[9] != "line 10" {
+		t.Errorf("fast subscriber received %q, want 10 lines", got)
+	}
+	if !b.Subscribe(slow) {
+		t.Error("a detached session cannot subscribe again")
+	}
+}
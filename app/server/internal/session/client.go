@@ -0,0 +1,120 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"bufio"
+	"compress/flate"
+	"io"
+	"slices"
+)
+
+// Transports of sessions.
+const (
+	TransportTCP       = "tcp"
+	TransportWebSocket = "websocket"
+)
+
+// Client is the self-identification of a client with the features enabled for it.
+type Client struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Features []string `json:"features,omitempty"`
+}
+
+// Has reports whether a feature is enabled for the client.
+func (c *Client) Has(feature string) bool {
+	return c != nil && slices.Contains(c.Features, feature)
+}
+
+// SetClient stores the self-identification of the client.
+func (s *Session) SetClient(client *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// Client returns the self-identification of the client (nil if it did not identify).
+func (s *Session) Client() *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+// SetLang sets the language of the texts sent to the client ("" = the server default).
+func (s *Session) SetLang(lang string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lang = lang
+}
+
+// Lang returns the language of the texts sent to the client ("" = the server default).
+func (s *Session) Lang() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lang
+}
+
+// SetNarrate switches the narration of game events as full sentences on or off.
+func (s *Session) SetNarrate(narrate bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.narrate = narrate
+}
+
+// Narrate reports whether the client gets the game events narrated as full sentences.
+func (s *Session) Narrate() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.narrate
+}
+
+// Wrap switches the line stream of the session, e.g. to compression, after flushing
+// the lines written so far. wrap gets the current reader and writer and returns the
+// ones for the following lines. It must be called by the goroutine reading the session.
+func (s *Session) Wrap(wrap func(r io.Reader, w io.Writer) (io.Reader, io.Writer)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	r, w := wrap(s.reader, s.out)
+	s.reader = bufio.NewReader(r)
+	s.writer = bufio.NewWriter(w)
+	s.out = w
+	return nil
+}
+
+// Deflate compresses the line stream in both directions (raw DEFLATE, RFC 1951),
+// flushing after every write.
+func Deflate(r io.Reader, w io.Writer) (io.Reader, io.Writer) {
+	// Only fails for invalid levels
+	fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+	return flate.NewReader(r), &flushWriter{fw}
+}
+
+// flushWriter flushes the compressed data after every write, so lines are not delayed.
+type flushWriter struct {
+	fw *flate.Writer
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.fw.Write(p)
+	if err == nil {
+		err = f.fw.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"bufio"
+	"context"
+	"testing"
+)
+
+func TestClientHas(t *testing.T) {
+	tests := []struct {
+		client  *Client
+		feature string
+		want    bool
+	}{
+		{nil, "json", false},
+		{&Client{Name: "skatcli", Version: "1.0"}, "json", false},
+		{&Client{Features: []string{"json", "deflate"}}, "deflate", true},
+		{&Client{Features: []string{"json"}}, "JSON", false},
+	}
+	for _, tt := range tests {
+		if got := tt.client.Has(tt.feature); got != tt.want {
+			t.Errorf("%+v Has(%q) = %v, want %v", tt.client, tt.feature, got, tt.want)
+		}
+	}
+}
+
+func TestSetClient(t *testing.T) {
+	m := NewManager(context.Background())
+	sess, _ := newPipeSession(t, m)
+	if sess.Client() != nil {
+		t.Fatal("new session has a client")
+	}
+	client := &Client{Name: "skatcli", Version: "1.0", Features: []string{"deflate"}}
+	sess.SetClient(client)
+	if sess.Client() != client || !sess.Client().Has("deflate") {
+		t.Errorf("Client() = %+v, want %+v", sess.Client(), client)
+	}
+}
+
+func TestDeflate(t *testing.T) {
+	m := NewManager(context.Background())
+	sess, conn := newPipeSession(t, m)
+	if err := sess.Wrap(Deflate); err != nil {
+		t.Fatal(err)
+	}
+	r, w := Deflate(conn, conn)
+	lines := bufio.NewScanner(r)
+
+	// Every line is flushed at once in both directions
+	for _, line := range []string{"password:", "tables"} {
+		errs := make(chan error, 1)
+		go func() { errs <- sess.WriteLine("%s", line) }()
+		if !lines.Scan() || lines.Text() != line {
+			t.Fatalf("client read %q (%v), want %q", lines.Text(), lines.Err(), line)
+		}
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	go w.Write([]byte("table t1 anna play CJ\n"))
+	if line, err := sess.ReadLine(); err != nil || line != "table t1 anna play CJ" {
+		t.Errorf("ReadLine() = %q, %v", line, err)
+	}
+}
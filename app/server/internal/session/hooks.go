@@ -0,0 +1,63 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+// hooks are the registered lifecycle functions of a manager.
+type hooks struct {
+	create        []func(*Session)
+	authenticated []func(*Session)
+	close         []func(*Session)
+}
+
+// OnCreate registers a function called with every new session.
+func (m *Manager) OnCreate(fn func(*Session)) {
+	m.hooksMu.Lock()
+	defer m.hooksMu.Unlock()
+	m.hooks.create = append(m.hooks.create, fn)
+}
+
+// OnAuthenticated registers a function called when a session logs in or resumes a
+// disconnected session.
+func (m *Manager) OnAuthenticated(fn func(*Session)) {
+	m.hooksMu.Lock()
+	defer m.hooksMu.Unlock()
+	m.hooks.authenticated = append(m.hooks.authenticated, fn)
+}
+
+// OnClose registers a function called when a session is removed, kicked or closed on
+// shutdown. context.Cause of its context is ErrKicked for kicked sessions.
+func (m *Manager) OnClose(fn func(*Session)) {
+	m.hooksMu.Lock()
+	defer m.hooksMu.Unlock()
+	m.hooks.close = append(m.hooks.close, fn)
+}
+
+// run calls the hooks selected by list with the sessions. The manager must not be
+// locked, so hooks can use it.
+func (m *Manager) run(list func(*hooks) []func(*Session), sessions ...*Session) {
+	m.hooksMu.RLock()
+	fns := list(&m.hooks)
+	m.hooksMu.RUnlock()
+
+	for _, session := range sessions {
+		for _, fn := range fns {
+			fn(session)
+		}
+	}
+}
+
+func onCreate(h *hooks) []func(*Session)        { return h.create }
+func onAuthenticated(h *hooks) []func(*Session) { return h.authenticated }
+func onClose(h *hooks) []func(*Session)         { return h.close }
@@ -0,0 +1,63 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	m := NewManager(context.Background())
+	var events []string
+	m.OnCreate(func(sess *Session) { events = append(events, "create "+sess.ID()) })
+	m.OnAuthenticated(func(sess *Session) { events = append(events, "login "+sess.Username()) })
+	m.OnClose(func(sess *Session) {
+		// Hooks can use the manager
+		kicked := errors.Is(context.Cause(sess.Context()), ErrKicked)
+		events = append(events, fmt.Sprintf("close %s kicked=%v left=%d", sess.ID(), kicked, m.Count()))
+	})
+	m.OnClose(func(sess *Session) { events = append(events, "close again "+sess.ID()) })
+
+	anna, _ := newPipeSession(t, m)
+	ben, _ := newPipeSession(t, m)
+	newPipeSession(t, m)
+	m.Login(anna, "anna")
+	m.RemoveSession(anna.ID())
+	m.Kick(ben.ID())
+	m.CloseAll()
+	// Unknown sessions run no hooks
+	m.RemoveSession(anna.ID())
+	m.Kick("session-9")
+
+	want := []string{
+		"create session-1",
+		"create session-2",
+		"create session-3",
+		"login anna",
+		"close session-1 kicked=false left=2",
+		"close again session-1",
+		"close session-2 kicked=true left=1",
+		"close again session-2",
+		"close session-3 kicked=false left=0",
+		"close again session-3",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
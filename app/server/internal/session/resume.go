@@ -0,0 +1,156 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+// DefaultResumeWindow is how long a disconnected session can be resumed.
+const DefaultResumeWindow = 2 * time.Minute
+
+// Resume errors.
+var (
+	ErrInvalidToken = errors.New("invalid resume token")
+	ErrNotResumable = errors.New("session cannot be resumed")
+)
+
+// detached is a disconnected session waiting to be resumed.
+type detached struct {
+	id       string
+	username string
+	at       time.Time
+}
+
+// newSecret returns a random key for signing resume tokens.
+func newSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
+
+// ResumeToken returns the token to resume the session after a disconnect ("" before login).
+func (s *Session) ResumeToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resumeToken
+}
+
+// Login sets the username of a session and issues its resume token.
+func (m *Manager) Login(sess *Session, username string) string {
+	m.mu.Lock()
+	sess.setIdentity(sess.ID(), username)
+	token := m.issue(sess)
+	m.mu.Unlock()
+
+	m.run(onAuthenticated, sess)
+	return token
+}
+
+// Resumable reports whether a session can be resumed once its connection is closed: it
+// is logged in, the manager keeps sessions for a resume window and it was not kicked.
+func (m *Manager) Resumable(sess *Session) bool {
+	return sess.ResumeToken() != "" && m.ResumeWindow > 0 && !errors.Is(context.Cause(sess.Context()), ErrKicked)
+}
+
+// Resume reattaches a new session to the identity (ID and username) of the disconnected
+// session of a token. Tokens can be used once, the new resume token is returned.
+func (m *Manager) Resume(sess *Session, token string) (string, error) {
+	if !m.valid(token) {
+		return "", ErrInvalidToken
+	}
+
+	m.mu.Lock()
+	m.prune()
+	old, ok := m.detached[token]
+	if !ok {
+		m.mu.Unlock()
+		return "", ErrNotResumable
+	}
+	delete(m.detached, token)
+
+	delete(m.sessions, sess.ID())
+	log.Printf("[%s] Session resumed by %s from %s", old.id, sess.ID(), sess.RemoteAddr())
+	sess.setIdentity(old.id, old.username)
+	m.sessions[old.id] = sess
+	newToken := m.issue(sess)
+	m.mu.Unlock()
+
+	m.run(onAuthenticated, sess)
+	return newToken, nil
+}
+
+// issue creates a new signed resume token for a session: "<session id>.<nonce>.<signature>".
+func (m *Manager) issue(sess *Session) string {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+	payload := sess.ID() + "." + hex.EncodeToString(nonce)
+	token := payload + "." + m.sign(payload)
+
+	sess.mu.Lock()
+	sess.resumeToken = token
+	sess.mu.Unlock()
+	return token
+}
+
+// sign returns the signature of a token payload.
+func (m *Manager) sign(payload string) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// valid reports whether a token was signed by the manager.
+func (m *Manager) valid(token string) bool {
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 || strings.Count(token, ".") != 2 {
+		return false
+	}
+	return hmac.Equal([]byte(token[i+1:]), []byte(m.sign(token[:i])))
+}
+
+// detach keeps a disconnected session resumable for the resume window.
+// The caller must hold the lock.
+func (m *Manager) detach(sess *Session) {
+	token := sess.ResumeToken()
+	if token == "" || m.ResumeWindow <= 0 {
+		return
+	}
+	m.prune()
+	m.detached[token] = detached{id: sess.ID(), username: sess.Username(), at: time.Now()}
+	log.Printf("[%s] Session of '%s' resumable for %s", sess.ID(), sess.Username(), m.ResumeWindow)
+}
+
+// prune drops the sessions whose resume window is over. The caller must hold the lock.
+func (m *Manager) prune() {
+	for token, d := range m.detached {
+		if time.Since(d.at) > m.ResumeWindow {
+			delete(m.detached, token)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// disconnect logs in a session and removes it, returning its ID and resume token.
+func disconnect(t *testing.T, m *Manager, login string) (string, string) {
+	t.Helper()
+	sess, _ := newPipeSession(t, m)
+	token := m.Login(sess, login)
+	if token == "" || sess.ResumeToken() != token {
+		t.Fatalf("Login() = %q, ResumeToken() = %q", token, sess.ResumeToken())
+	}
+	if !m.Resumable(sess) {
+		t.Fatal("logged-in session is not resumable")
+	}
+	id := sess.ID()
+	m.RemoveSession(id)
+	return id, token
+}
+
+func TestResumeToken(t *testing.T) {
+	m := NewManager(context.Background())
+	_, token := disconnect(t, m, "anna")
+	payload := token[:strings.LastIndexByte(token, '.')]
+
+	other := NewManager(context.Background())
+	tests := []struct {
+		name  string
+		m     *Manager
+		token string
+	}{
+		{"empty", m, ""},
+		{"garbage", m, "garbage"},
+		{"signature missing", m, payload},
+		{"tampered", m, strings.Replace(token, "session-1", "session-2", 1)},
+		{"wrong signature", m, payload + ".AAAA"},
+		{"other manager", other, token},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess, _ := newPipeSession(t, tt.m)
+			if _, err := tt.m.Resume(sess, tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Resume(%q) error = %v, want %v", tt.token, err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestResume(t *testing.T) {
+	m := NewManager(context.Background())
+	var authenticated []string
+	m.OnAuthenticated(func(sess *Session) { authenticated = append(authenticated, sess.Username()) })
+	id, token := disconnect(t, m, "anna")
+
+	sess, _ := newPipeSession(t, m)
+	newID := sess.ID()
+	newToken, err := m.Resume(sess, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.ID() != id || sess.Username() != "anna" {
+		t.Errorf("resumed session = %s of %q, want %s of anna", sess.ID(), sess.Username(), id)
+	}
+	if newToken == "" || newToken == token || sess.ResumeToken() != newToken {
+		t.Errorf("new token = %q, want a fresh one", newToken)
+	}
+	if m.GetSession(id) != sess || m.GetSession(newID) != nil || m.Count() != 1 {
+		t.Errorf("sessions = %v, want the resumed one as %s", m.List(), id)
+	}
+	if len(authenticated) != 2 || authenticated[1] != "anna" {
+		t.Errorf("authenticated = %v, want the login and the resume of anna", authenticated)
+	}
+
+	// Tokens can be used once
+	again, _ := newPipeSession(t, m)
+	if _, err := m.Resume(again, token); !errors.Is(err, ErrNotResumable) {
+		t.Errorf("second Resume() error = %v, want %v", err, ErrNotResumable)
+	}
+	// A live session cannot be resumed, only after its connection is closed
+	if _, err := m.Resume(again, newToken); !errors.Is(err, ErrNotResumable) {
+		t.Errorf("Resume() of a live session error = %v, want %v", err, ErrNotResumable)
+	}
+	m.RemoveSession(id)
+	if _, err := m.Resume(again, newToken); err != nil {
+		t.Errorf("Resume() with the new token error = %v", err)
+	}
+}
+
+func TestResumeWindow(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		wait   time.Duration
+		want   error
+	}{
+		{"within the window", time.Minute, 0, nil},
+		{"window over", 10 * time.Millisecond, 30 * time.Millisecond, ErrNotResumable},
+		{"no window", 0, 0, ErrNotResumable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(context.Background())
+			m.ResumeWindow = tt.window
+			sess, _ := newPipeSession(t, m)
+			token := m.Login(sess, "anna")
+			m.RemoveSession(sess.ID())
+			time.Sleep(tt.wait)
+
+			resumed, _ := newPipeSession(t, m)
+			if _, err := m.Resume(resumed, token); !errors.Is(err, tt.want) {
+				t.Errorf("Resume() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeKicked(t *testing.T) {
+	m := NewManager(context.Background())
+	sess, _ := newPipeSession(t, m)
+	token := m.Login(sess, "anna")
+	m.Kick(sess.ID())
+	if m.Resumable(sess) {
+		t.Error("kicked session is resumable")
+	}
+	resumed, _ := newPipeSession(t, m)
+	if _, err := m.Resume(resumed, token); !errors.Is(err, ErrNotResumable) {
+		t.Errorf("Resume() error = %v, want %v", err, ErrNotResumable)
+	}
+}
+
+// TestResumeIdentity reads the identity of the sessions while one resumes; run with
+// -race.
+func TestResumeIdentity(t *testing.T) {
+	m := NewManager(context.Background())
+	_, token := disconnect(t, m, "anna")
+	sess, _ := newPipeSession(t, m)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, s := range m.List() {
+				_ = s.ID() + s.Username()
+			}
+		}
+	}()
+	if _, err := m.Resume(sess, token); err != nil {
+		t.Error(err)
+	}
+	close(done)
+	wg.Wait()
+}
@@ -17,77 +17,254 @@ package session
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // Default timeout values.
 const (
-	DefaultReadTimeout  = 5 * time.Minute
-	DefaultWriteTimeout = 30 * time.Second
-	DefaultIdleTimeout  = 10 * time.Minute
+	DefaultReadTimeout    = 5 * time.Minute
+	DefaultWriteTimeout   = 30 * time.Second
+	DefaultIdleTimeout    = 10 * time.Minute
+	DefaultWaitingTimeout = 30 * time.Minute
+)
+
+// Default input limits.
+const (
+	DefaultMaxLineLength = 4096
+	DefaultMaxViolations = 3
+)
+
+// Timeouts are the timeouts of a session (0 = none).
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+	Idle  time.Duration
+	// Waiting is the read timeout while the client waits on other players
+	Waiting time.Duration
+}
+
+// DefaultTimeouts returns the default timeouts.
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Read:    DefaultReadTimeout,
+		Write:   DefaultWriteTimeout,
+		Idle:    DefaultIdleTimeout,
+		Waiting: DefaultWaitingTimeout,
+	}
+}
+
+// ErrKicked is the cause of the context of a session disconnected by an operator.
+var ErrKicked = errors.New("session kicked")
+
+// Input errors of ReadLine. Malformed lines are skipped; the session should be closed
+// after ErrTooManyViolations.
+var (
+	ErrLineTooLong       = errors.New("line too long")
+	ErrInvalidUTF8       = errors.New("line is not valid UTF-8")
+	ErrTooManyViolations = errors.New("too many protocol violations")
 )
 
 // Session represents a client connection session.
 type Session struct {
-	ID        string
 	Conn      net.Conn
-	Username  string
 	CreatedAt time.Time
+	// Transport is the transport of the connection (TransportTCP, TransportWebSocket)
+	Transport string
+
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	WaitingTimeout time.Duration
+
+	// MaxLineLength is the maximum length of a line of the client in bytes (0 = no limit)
+	MaxLineLength int
+	// MaxViolations is the number of protocol violations (malformed lines, see Violation)
+	// after which the session should be closed with ErrTooManyViolations (0 = no limit)
+	MaxViolations int
+
+	// idMu guards id and username, which a login or resume replaces. It is not mu,
+	// so reading them never waits for a write to the connection.
+	idMu     sync.RWMutex
+	id       string
+	username string
+
+	ctx         context.Context
+	cancel      context.CancelCauseFunc
+	reader      *bufio.Reader
+	writer      *bufio.Writer
+	out         io.Writer
+	mu          sync.Mutex
+	lastActive  time.Time
+	lastWrite   time.Time
+	waiting     bool
+	resumeToken string
+	client      *Client
+	lang        string
+	narrate     bool
+	traffic     counters
+	violations  int
+}
+
+// NewSession creates a new session for a connection. Its context is derived from ctx;
+// when it is cancelled, blocking reads and writes return immediately.
+func NewSession(ctx context.Context, id string, conn net.Conn) *Session {
+	ctx, cancel := context.WithCancelCause(ctx)
+	context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	s := &Session{
+		id:         id,
+		ctx:        ctx,
+		cancel:     cancel,
+		Conn:       conn,
+		CreatedAt:  time.Now(),
+		Transport:  TransportTCP,
+		lastActive: time.Now(),
+
+		MaxLineLength: DefaultMaxLineLength,
+		MaxViolations: DefaultMaxViolations,
+	}
+	s.reader = bufio.NewReader(&countReader{r: conn, count: &s.traffic.bytesIn})
+	s.out = &countWriter{w: conn, count: &s.traffic.bytesOut}
+	s.writer = bufio.NewWriter(s.out)
+	s.SetTimeouts(DefaultTimeouts())
+	return s
+}
+
+// ID returns the ID of the session.
+func (s *Session) ID() string {
+	s.idMu.RLock()
+	defer s.idMu.RUnlock()
+	return s.id
+}
 
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	IdleTimeout  time.Duration
+// Username returns the login of the session ("" before login).
+func (s *Session) Username() string {
+	s.idMu.RLock()
+	defer s.idMu.RUnlock()
+	return s.username
+}
+
+// setIdentity sets the ID and the login of the session.
+func (s *Session) setIdentity(id, username string) {
+	s.idMu.Lock()
+	s.id, s.username = id, username
+	s.idMu.Unlock()
+}
 
-	reader     *bufio.Reader
-	writer     *bufio.Writer
-	mu         sync.Mutex
-	lastActive time.Time
+// SetTimeouts sets the timeouts of the session.
+func (s *Session) SetTimeouts(t Timeouts) {
+	s.ReadTimeout = t.Read
+	s.WriteTimeout = t.Write
+	s.IdleTimeout = t.Idle
+	s.WaitingTimeout = t.Waiting
 }
 
-// NewSession creates a new session for a connection.
-func NewSession(id string, conn net.Conn) *Session {
-	return &Session{
-		ID:           id,
-		Conn:         conn,
-		CreatedAt:    time.Now(),
-		lastActive:   time.Now(),
-		ReadTimeout:  DefaultReadTimeout,
-		WriteTimeout: DefaultWriteTimeout,
-		IdleTimeout:  DefaultIdleTimeout,
-		reader:       bufio.NewReader(conn),
-		writer:       bufio.NewWriter(conn),
+// SetWaiting marks the client as waiting on other players, which reads with the
+// WaitingTimeout instead of the ReadTimeout.
+func (s *Session) SetWaiting(waiting bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.waiting = waiting
+}
+
+// readTimeout returns the current read timeout.
+func (s *Session) readTimeout() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiting && s.WaitingTimeout > 0 {
+		return s.WaitingTimeout
 	}
+	return s.ReadTimeout
 }
 
-// ReadLine reads a line from the connection with timeout.
+// ReadLine reads a line from the connection with timeout. Lines longer than
+// MaxLineLength fail with ErrLineTooLong, lines that are not UTF-8 with ErrInvalidUTF8;
+// both are skipped. After MaxViolations of them it fails with ErrTooManyViolations.
 func (s *Session) ReadLine() (string, error) {
-	// Set read deadline
-	if s.ReadTimeout > 0 {
-		s.Conn.SetReadDeadline(time.Now().Add(s.ReadTimeout))
+	// Set read deadline (before checking the context, which expires it when cancelled)
+	if timeout := s.readTimeout(); timeout > 0 {
+		s.Conn.SetReadDeadline(time.Now().Add(timeout))
+	}
+	if s.ctx.Err() != nil {
+		return "", context.Cause(s.ctx)
 	}
 
-	line, err := s.reader.ReadString('\n')
-	if err != nil {
+	line, err := s.readLine()
+	if err != nil && !errors.Is(err, ErrLineTooLong) {
+		if s.ctx.Err() != nil {
+			return "", context.Cause(s.ctx)
+		}
 		return "", err
 	}
 
 	// Update last active time
+	s.traffic.linesIn.Add(1)
 	s.mu.Lock()
 	s.lastActive = time.Now()
 	s.mu.Unlock()
 
-	// Remove trailing newline characters
-	if len(line) > 0 && line[len(line)-1] == '\n' {
-		line = line[:len(line)-1]
+	if err == nil && !utf8.Valid(line) {
+		err = ErrInvalidUTF8
 	}
-	if len(line) > 0 && line[len(line)-1] == '\r' {
-		line = line[:len(line)-1]
+	if err != nil {
+		if verr := s.Violation(); verr != nil {
+			return "", verr
+		}
+		return "", err
+	}
+	return string(line), nil
+}
+
+// Violation counts a protocol violation of the client besides malformed lines, e.g. an
+// illegal move, and returns ErrTooManyViolations once MaxViolations are reached. Like
+// ReadLine it must be called by the goroutine reading the session.
+func (s *Session) Violation() error {
+	s.violations++
+	if s.MaxViolations > 0 && s.violations >= s.MaxViolations {
+		return ErrTooManyViolations
+	}
+	return nil
+}
+
+// readLine reads a line without the trailing newline characters. The rest of a line
+// longer than MaxLineLength is discarded without buffering it.
+func (s *Session) readLine() ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := s.reader.ReadSlice('\n')
+		if !tooLong {
+			line = append(line, chunk...)
+			// Allow for the "\r\n" of the line end
+			if s.MaxLineLength > 0 && len(line) > s.MaxLineLength+2 {
+				tooLong, line = true, nil
+			}
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tooLong {
+			return nil, ErrLineTooLong
+		}
+		line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte{'\n'}), []byte{'\r'})
+		if s.MaxLineLength > 0 && len(line) > s.MaxLineLength {
+			return nil, ErrLineTooLong
+		}
+		return line, nil
 	}
-	return line, nil
 }
 
 // WriteLine writes a line to the connection with timeout.
@@ -95,21 +272,93 @@ func (s *Session) WriteLine(format string, args ...interface{}) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Set write deadline
+	// Set write deadline (before checking the context, which expires it when cancelled)
 	if s.WriteTimeout > 0 {
 		s.Conn.SetWriteDeadline(time.Now().Add(s.WriteTimeout))
 	}
+	if s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
 
 	message := fmt.Sprintf(format, args...)
 	_, err := s.writer.WriteString(message + "\n")
-	if err != nil {
-		return err
+	if err == nil {
+		s.lastActive = time.Now()
+		s.lastWrite = s.lastActive
+		s.traffic.linesOut.Add(1)
+		err = s.writer.Flush()
 	}
+	if err != nil && s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+	return err
+}
 
-	s.lastActive = time.Now()
+// WriteLines writes formatted, newline-terminated lines, e.g. the message of a broadcast.
+func (s *Session) WriteLines(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.WriteTimeout > 0 {
+		s.Conn.SetWriteDeadline(time.Now().Add(s.WriteTimeout))
+	}
+	if s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+
+	_, err := s.writer.Write(data)
+	if err == nil {
+		s.lastActive = time.Now()
+		s.lastWrite = s.lastActive
+		s.traffic.linesOut.Add(int64(bytes.Count(data, []byte{'\n'})))
+		err = s.writer.Flush()
+	}
+	if err != nil && s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+	return err
+}
+
+// Context returns the context of the session. It is cancelled when the session is
+// closed or kicked, or the server shuts down; background work for the session should
+// stop then.
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// Keepalive writes a keepalive to the connection: a ping if the connection has its
+// own keepalive (WebSocket), otherwise an empty line, which clients ignore. It does not
+// count as activity for the idle timeout.
+func (s *Session) Keepalive() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.WriteTimeout > 0 {
+		s.Conn.SetWriteDeadline(time.Now().Add(s.WriteTimeout))
+	}
+	if s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+	s.lastWrite = time.Now()
+	if k, ok := s.Conn.(interface{ Keepalive() error }); ok {
+		return k.Keepalive()
+	}
+	if err := s.writer.WriteByte('\n'); err != nil {
+		return err
+	}
 	return s.writer.Flush()
 }
 
+// Quiet returns how long nothing was written to the connection.
+func (s *Session) Quiet() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastWrite.IsZero() {
+		return time.Since(s.CreatedAt)
+	}
+	return time.Since(s.lastWrite)
+}
+
 // LastActive returns the time of the last activity.
 func (s *Session) LastActive() time.Time {
 	s.mu.Lock()
@@ -117,16 +366,24 @@ func (s *Session) LastActive() time.Time {
 	return s.lastActive
 }
 
-// IsIdle returns true if the session has been idle longer than IdleTimeout.
+// IsIdle returns true if the session has been idle longer than IdleTimeout (or the
+// longer WaitingTimeout while waiting on other players).
 func (s *Session) IsIdle() bool {
 	if s.IdleTimeout <= 0 {
 		return false
 	}
-	return time.Since(s.LastActive()) > s.IdleTimeout
+	s.mu.Lock()
+	timeout := s.IdleTimeout
+	if s.waiting && s.WaitingTimeout > timeout {
+		timeout = s.WaitingTimeout
+	}
+	s.mu.Unlock()
+	return time.Since(s.LastActive()) > timeout
 }
 
-// Close closes the session connection.
+// Close cancels the context of the session and closes its connection.
 func (s *Session) Close() error {
+	s.cancel(nil)
 	return s.Conn.Close()
 }
 
@@ -135,46 +392,128 @@ func (s *Session) RemoteAddr() string {
 	return s.Conn.RemoteAddr().String()
 }
 
-// Manager manages all active sessions.
+// Manager manages all active sessions and the disconnected sessions that can be resumed.
 type Manager struct {
+	// ResumeWindow is how long a disconnected session can be resumed (0 = never).
+	ResumeWindow time.Duration
+	// Timeouts are the timeouts of new sessions.
+	Timeouts Timeouts
+	// MaxLineLength and MaxViolations are the input limits of new sessions.
+	MaxLineLength int
+	MaxViolations int
+
+	ctx      context.Context
 	sessions map[string]*Session
+	detached map[string]detached
+	closed   Traffic
+	secret   []byte
+	hooks    hooks
+	hooksMu  sync.RWMutex
 	mu       sync.RWMutex
 	counter  int
 }
 
-// NewManager creates a new session manager.
-func NewManager() *Manager {
+// NewManager creates a new session manager. The contexts of the sessions are derived
+// from ctx.
+func NewManager(ctx context.Context) *Manager {
 	return &Manager{
-		sessions: make(map[string]*Session),
+		ResumeWindow:  DefaultResumeWindow,
+		Timeouts:      DefaultTimeouts(),
+		MaxLineLength: DefaultMaxLineLength,
+		MaxViolations: DefaultMaxViolations,
+		ctx:           ctx,
+		sessions:      make(map[string]*Session),
+		detached:      make(map[string]detached),
+		secret:        newSecret(),
 	}
 }
 
 // CreateSession creates a new session for a connection.
 func (m *Manager) CreateSession(conn net.Conn) *Session {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	m.counter++
 	id := fmt.Sprintf("session-%d", m.counter)
 
-	session := NewSession(id, conn)
+	session := NewSession(m.ctx, id, conn)
+	session.SetTimeouts(m.Timeouts)
+	session.MaxLineLength = m.MaxLineLength
+	session.MaxViolations = m.MaxViolations
 	m.sessions[id] = session
+	m.mu.Unlock()
 
-	log.Printf("[%s] Session created from %s", id, conn.RemoteAddr())
-
+	m.run(onCreate, session)
 	return session
 }
 
-// RemoveSession removes a session.
+// RemoveSession removes a session. Logged-in sessions stay resumable for the resume window.
 func (m *Manager) RemoveSession(id string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	session, exists := m.sessions[id]
+	if exists {
+		session.Close()
+		delete(m.sessions, id)
+		m.closed.Add(session.Traffic())
+		m.detach(session)
+	}
+	m.mu.Unlock()
+
+	if exists {
+		m.run(onClose, session)
+	}
+}
 
-	if session, exists := m.sessions[id]; exists {
+// Kick disconnects a session, cancelling its context with ErrKicked. Kicked sessions
+// cannot be resumed. It returns false for unknown sessions.
+func (m *Manager) Kick(id string) bool {
+	m.mu.Lock()
+	session, exists := m.sessions[id]
+	if exists {
+		session.cancel(ErrKicked)
 		session.Close()
 		delete(m.sessions, id)
-		log.Printf("[%s] Session removed", id)
+		m.closed.Add(session.Traffic())
+	}
+	m.mu.Unlock()
+
+	if exists {
+		m.run(onClose, session)
 	}
+	return exists
+}
+
+// CloseIdle closes the sessions idle longer than their IdleTimeout and returns their number.
+func (m *Manager) CloseIdle() int {
+	m.mu.RLock()
+	var idle []*Session
+	for _, session := range m.sessions {
+		if session.IsIdle() {
+			idle = append(idle, session)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, session := range idle {
+		log.Printf("[%s] Closing idle session (inactive since %s)", session.ID(), session.LastActive().Format(time.RFC3339))
+		session.Close()
+	}
+	return len(idle)
+}
+
+// SendKeepalives writes a keepalive to the sessions nothing was written to for at least
+// quiet and returns their number.
+func (m *Manager) SendKeepalives(quiet time.Duration) int {
+	sent := 0
+	for _, session := range m.List() {
+		if session.Quiet() < quiet {
+			continue
+		}
+		if err := session.Keepalive(); err != nil {
+			log.Printf("[%s] Failed to send keepalive: %v", session.ID(), err)
+			continue
+		}
+		sent++
+	}
+	return sent
 }
 
 // GetSession returns a session by ID.
@@ -185,6 +524,18 @@ func (m *Manager) GetSession(id string) *Session {
 	return m.sessions[id]
 }
 
+// List returns the active sessions.
+func (m *Manager) List() []*Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		list = append(list, session)
+	}
+	return list
+}
+
 // Count returns the number of active sessions.
 func (m *Manager) Count() int {
 	m.mu.RLock()
@@ -196,11 +547,15 @@ func (m *Manager) Count() int {
 // CloseAll closes all sessions.
 func (m *Manager) CloseAll() {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	for id, session := range m.sessions {
+	closed := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
 		session.Close()
-		log.Printf("[%s] Session closed during shutdown", id)
+		m.closed.Add(session.Traffic())
+		closed = append(closed, session)
 	}
 	m.sessions = make(map[string]*Session)
+	m.detached = make(map[string]detached)
+	m.mu.Unlock()
+
+	m.run(onClose, closed...)
 }
@@ -0,0 +1,257 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newPipeSession creates a session of the manager over a pipe, whose client end is
+// returned.
+func newPipeSession(t *testing.T, m *Manager) (*Session, net.Conn) {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return m.CreateSession(server), client
+}
+
+// result returns the error of a blocking call, failing the test if it does not return
+// within a second.
+func result(t *testing.T, errs <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-errs:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("the call still blocks")
+		return nil
+	}
+}
+
+func TestSessionContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		cancel func(m *Manager, sess *Session, shutdown context.CancelFunc)
+		want   error
+	}{
+		{"shutdown", func(_ *Manager, _ *Session, shutdown context.CancelFunc) { shutdown() }, context.Canceled},
+		{"kick", func(m *Manager, sess *Session, _ context.CancelFunc) { m.Kick(sess.ID()) }, ErrKicked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, shutdown := context.WithCancel(context.Background())
+			defer shutdown()
+			m := NewManager(ctx)
+
+			// A blocking read and a blocking write (nobody reads the client end) return
+			reader, _ := newPipeSession(t, m)
+			writer, _ := newPipeSession(t, m)
+			reads, writes := make(chan error, 1), make(chan error, 1)
+			go func() {
+				_, err := reader.ReadLine()
+				reads <- err
+			}()
+			go func() { writes <- writer.WriteLine("hello") }()
+			time.Sleep(10 * time.Millisecond)
+
+			tt.cancel(m, reader, shutdown)
+			if err := result(t, reads); !errors.Is(err, tt.want) {
+				t.Errorf("ReadLine() error = %v, want %v", err, tt.want)
+			}
+			if cause := context.Cause(reader.Context()); !errors.Is(cause, tt.want) {
+				t.Errorf("context cause = %v, want %v", cause, tt.want)
+			}
+
+			tt.cancel(m, writer, shutdown)
+			if err := result(t, writes); !errors.Is(err, tt.want) {
+				t.Errorf("WriteLine() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	m := NewManager(context.Background())
+	sess, _ := newPipeSession(t, m)
+	sess.Close()
+
+	select {
+	case <-sess.Context().Done():
+	default:
+		t.Error("context not cancelled by Close")
+	}
+	if err := sess.WriteLine("hello"); err == nil {
+		t.Error("WriteLine() after Close: expected error")
+	}
+	if _, err := sess.ReadLine(); err == nil {
+		t.Error("ReadLine() after Close: expected error")
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts Timeouts
+		waiting  bool
+		timeout  bool
+	}{
+		{"read timeout", Timeouts{Read: 20 * time.Millisecond}, false, true},
+		{"no timeout", Timeouts{}, false, false},
+		{"waiting on other players", Timeouts{Read: 20 * time.Millisecond, Waiting: time.Second}, true, false},
+		{"waiting without waiting timeout", Timeouts{Read: 20 * time.Millisecond}, true, true},
+		{"waiting timeout when not waiting", Timeouts{Read: 20 * time.Millisecond, Waiting: time.Second}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager(context.Background())
+			m.Timeouts = tt.timeouts
+			sess, client := newPipeSession(t, m)
+			sess.SetWaiting(tt.waiting)
+
+			// The line arrives after the read timeout
+			go func() {
+				time.Sleep(60 * time.Millisecond)
+				client.Write([]byte("hello\n"))
+			}()
+			line, err := sess.ReadLine()
+			var netErr net.Error
+			if timeout := errors.As(err, &netErr) && netErr.Timeout(); timeout != tt.timeout {
+				t.Fatalf("ReadLine() = %q, %v, want timeout %v", line, err, tt.timeout)
+			}
+			if !tt.timeout && line != "hello" {
+				t.Errorf("ReadLine() = %q, want hello", line)
+			}
+		})
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	m := NewManager(context.Background())
+	m.Timeouts = Timeouts{Idle: 20 * time.Millisecond, Waiting: time.Minute}
+	idle, _ := newPipeSession(t, m)
+	waiting, _ := newPipeSession(t, m)
+	waiting.SetWaiting(true)
+	if idle.IdleTimeout != m.Timeouts.Idle || idle.WaitingTimeout != m.Timeouts.Waiting {
+		t.Errorf("session timeouts = %v, %v, want those of the manager", idle.IdleTimeout, idle.WaitingTimeout)
+	}
+	if idle.IsIdle() {
+		t.Error("new session is idle")
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	if !idle.IsIdle() || waiting.IsIdle() {
+		t.Errorf("IsIdle() = %v, %v while waiting, want true, false", idle.IsIdle(), waiting.IsIdle())
+	}
+	if n := m.CloseIdle(); n != 1 {
+		t.Errorf("CloseIdle() = %d, want 1", n)
+	}
+	if idle.Context().Err() == nil || waiting.Context().Err() != nil {
+		t.Error("CloseIdle() closed the wrong session")
+	}
+}
+
+// pingConn is a connection with its own keepalive, like a WebSocket.
+type pingConn struct {
+	net.Conn
+	pings int
+}
+
+func (c *pingConn) Keepalive() error {
+	c.pings++
+	return nil
+}
+
+func TestKeepalive(t *testing.T) {
+	m := NewManager(context.Background())
+	quiet, quietClient := newPipeSession(t, m)
+	busy, busyClient := newPipeSession(t, m)
+	go io.Copy(io.Discard, busyClient)
+	received := make(chan string, 1)
+	go func() {
+		line, _ := bufio.NewReader(quietClient).ReadString('\n')
+		received <- line
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	if err := busy.WriteLine("hello"); err != nil {
+		t.Fatal(err)
+	}
+	active := quiet.LastActive()
+	if n := m.SendKeepalives(20 * time.Millisecond); n != 1 {
+		t.Errorf("SendKeepalives() = %d, want 1", n)
+	}
+	select {
+	case line := <-received:
+		if line != "\n" {
+			t.Errorf("keepalive = %q, want an empty line", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no keepalive received")
+	}
+	if quiet.Quiet() >= 20*time.Millisecond || !quiet.LastActive().Equal(active) {
+		t.Errorf("after the keepalive: Quiet() = %v, LastActive() changed %v", quiet.Quiet(), !quiet.LastActive().Equal(active))
+	}
+
+	// Connections with their own keepalive ping instead of writing a line
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := &pingConn{Conn: server}
+	sess := NewSession(context.Background(), "ws", conn)
+	if err := sess.Keepalive(); err != nil || conn.pings != 1 {
+		t.Errorf("Keepalive() = %v with %d pings, want one ping", err, conn.pings)
+	}
+}
+
+func TestReadLineLimits(t *testing.T) {
+	m := NewManager(context.Background())
+	m.MaxLineLength = 10
+	m.MaxViolations = 3
+	sess, client := newPipeSession(t, m)
+
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{"short\r\n", "short", nil},
+		{"0123456789\n", "0123456789", nil},
+		// Longer than the read buffer: discarded without buffering it
+		{strings.Repeat("x", 5000) + "\n", "", ErrLineTooLong},
+		{"ok\n", "ok", nil},
+		{"\xff\xfe\n", "", ErrInvalidUTF8},
+		{"0123456789a\n", "", ErrTooManyViolations},
+	}
+	go func() {
+		for _, tt := range tests {
+			client.Write([]byte(tt.input))
+		}
+	}()
+	for _, tt := range tests {
+		line, err := sess.ReadLine()
+		if line != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("ReadLine() of %.20q = %q, %v, want %q, %v", tt.input, line, err, tt.want, tt.err)
+		}
+	}
+}
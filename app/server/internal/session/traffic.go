@@ -0,0 +1,91 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Traffic is the traffic of a session: bytes as transferred on the connection (after
+// compression), lines as read and written by the protocol.
+type Traffic struct {
+	BytesIn  int64 `json:"bytesIn"`
+	BytesOut int64 `json:"bytesOut"`
+	LinesIn  int64 `json:"linesIn"`
+	LinesOut int64 `json:"linesOut"`
+}
+
+// Add adds the traffic of another session.
+func (t *Traffic) Add(other Traffic) {
+	t.BytesIn += other.BytesIn
+	t.BytesOut += other.BytesOut
+	t.LinesIn += other.LinesIn
+	t.LinesOut += other.LinesOut
+}
+
+// counters count the traffic of a session.
+type counters struct {
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+	linesIn  atomic.Int64
+	linesOut atomic.Int64
+}
+
+// countReader counts the bytes read from the connection.
+type countReader struct {
+	r     io.Reader
+	count *atomic.Int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.count.Add(int64(n))
+	return n, err
+}
+
+// countWriter counts the bytes written to the connection.
+type countWriter struct {
+	w     io.Writer
+	count *atomic.Int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count.Add(int64(n))
+	return n, err
+}
+
+// Traffic returns the traffic of the session so far.
+func (s *Session) Traffic() Traffic {
+	return Traffic{
+		BytesIn:  s.traffic.bytesIn.Load(),
+		BytesOut: s.traffic.bytesOut.Load(),
+		LinesIn:  s.traffic.linesIn.Load(),
+		LinesOut: s.traffic.linesOut.Load(),
+	}
+}
+
+// Traffic returns the traffic of all sessions since the start, closed ones included.
+func (m *Manager) Traffic() Traffic {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	total := m.closed
+	for _, session := range m.sessions {
+		total.Add(session.Traffic())
+	}
+	return total
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestTraffic(t *testing.T) {
+	m := NewManager(context.Background())
+	sess, client := newPipeSession(t, m)
+	go io.Copy(io.Discard, client)
+
+	if err := sess.WriteLine("password:"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.WriteLines([]byte("clients\ntables\n")); err != nil {
+		t.Fatal(err)
+	}
+	go client.Write([]byte("login anna secret\r\n"))
+	if _, err := sess.ReadLine(); err != nil {
+		t.Fatal(err)
+	}
+	want := Traffic{BytesIn: 19, BytesOut: 25, LinesIn: 1, LinesOut: 3}
+	if got := sess.Traffic(); got != want {
+		t.Errorf("Traffic() = %+v, want %+v", got, want)
+	}
+
+	// The totals keep the traffic of closed sessions
+	newPipeSession(t, m)
+	if got := m.Traffic(); got != want {
+		t.Errorf("Manager.Traffic() = %+v, want %+v", got, want)
+	}
+	m.RemoveSession(sess.ID())
+	if got := m.Traffic(); got != want {
+		t.Errorf("Manager.Traffic() after the removal = %+v, want %+v", got, want)
+	}
+
+	var total Traffic
+	total.Add(want)
+	total.Add(want)
+	if total != (Traffic{BytesIn: 38, BytesOut: 50, LinesIn: 2, LinesOut: 6}) {
+		t.Errorf("Add() = %+v", total)
+	}
+}
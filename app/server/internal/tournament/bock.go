@@ -0,0 +1,265 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Trigger is an event of a deal that schedules a Bock round and/or a Ramsch round.
+type Trigger string
+
+const (
+	// TriggerSplit - the declarer took exactly 60 card points (60-60 split)
+	TriggerSplit Trigger = "split"
+	// TriggerGrandHandLost - the declarer lost a Grand Hand
+	TriggerGrandHandLost Trigger = "grandhand"
+	// TriggerSchneiderLost - the declarer lost Schneider (30 card points or less)
+	TriggerSchneiderLost Trigger = "schneider"
+	// TriggerKontraLost - the defenders announced Kontra and the declarer won anyway
+	TriggerKontraLost Trigger = "kontra"
+	// TriggerAnnouncedLost - the declarer lost a game with Schneider or Schwarz announced
+	// (including Ouvert)
+	TriggerAnnouncedLost Trigger = "announced"
+	// TriggerHirsch - the declarer lost a game with all four Jacks (Hirsch)
+	TriggerHirsch Trigger = "hirsch"
+)
+
+// allTriggers are the known triggers.
+var allTriggers = []Trigger{TriggerSplit, TriggerGrandHandLost, TriggerSchneiderLost, TriggerKontraLost, TriggerAnnouncedLost, TriggerHirsch}
+
+// Mode is the mode of a deal.
+type Mode string
+
+const (
+	// ModeNormal - a normal deal
+	ModeNormal Mode = "normal"
+	// ModeBock - a Bock deal: the game score is doubled
+	ModeBock Mode = "bock"
+	// ModeRamsch - a Ramsch deal: nobody bids, the Ramsch loser scores the Ramsch score
+	ModeRamsch Mode = "ramsch"
+)
+
+// Rules are the triggers and the rounds they schedule. A round has one deal per
+// player of the table. With Bock and Ramsch, every trigger schedules a Bock round
+// followed by a Ramsch round. Scheduled rounds are played one after the other.
+type Rules struct {
+	Triggers []Trigger `json:"triggers,omitempty"`
+	// Bock schedules Bock rounds
+	Bock bool `json:"bock,omitempty"`
+	// Ramsch schedules Ramsch rounds
+	Ramsch bool `json:"ramsch,omitempty"`
+}
+
+// ParseRules parses comma-separated triggers (e.g. "split,grandhand") and rounds
+// ("bock", "ramsch" or "bock,ramsch"). Empty triggers disable the rules.
+func ParseRules(triggers, rounds string) (Rules, error) {
+	var rules Rules
+	for _, name := range strings.Split(triggers, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		trigger, ok := parseTrigger(name)
+		if !ok {
+			return Rules{}, fmt.Errorf("invalid trigger: %s (want %s)", name, joinTriggers(allTriggers))
+		}
+		rules.Triggers = append(rules.Triggers, trigger)
+	}
+	if len(rules.Triggers) == 0 {
+		return Rules{}, nil
+	}
+
+	for _, name := range strings.Split(rounds, ",") {
+		switch Mode(strings.ToLower(strings.TrimSpace(name))) {
+		case ModeBock:
+			rules.Bock = true
+		case ModeRamsch:
+			rules.Ramsch = true
+		default:
+			return Rules{}, fmt.Errorf("invalid round: %s (want bock or ramsch)", name)
+		}
+	}
+	return rules, nil
+}
+
+// Copy returns a copy of the rules.
+func (r Rules) Copy() Rules {
+	r.Triggers = append([]Trigger(nil), r.Triggers...)
+	return r
+}
+
+// parseTrigger returns the trigger of a name.
+func parseTrigger(name string) (Trigger, bool) {
+	for _, t := range allTriggers {
+		if string(t) == name {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// joinTriggers returns the triggers separated by commas.
+func joinTriggers(triggers []Trigger) string {
+	names := make([]string, len(triggers))
+	for i, t := range triggers {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ",")
+}
+
+// String returns the rules as "<triggers>/<rounds>", or "-" if they are disabled.
+func (r Rules) String() string {
+	if !r.Enabled() {
+		return "-"
+	}
+	var rounds []string
+	if r.Bock {
+		rounds = append(rounds, string(ModeBock))
+	}
+	if r.Ramsch {
+		rounds = append(rounds, string(ModeRamsch))
+	}
+	return joinTriggers(r.Triggers) + "/" + strings.Join(rounds, ",")
+}
+
+// Enabled returns true if the rules schedule rounds.
+func (r Rules) Enabled() bool {
+	return len(r.Triggers) > 0 && (r.Bock || r.Ramsch)
+}
+
+// fired returns the triggers fired by a finished declarer game.
+func (r Rules) fired(result *skat.GameResult) []Trigger {
+	null := result.Contract.GameType == skat.GameNull
+	var fired []Trigger
+	for _, t := range r.Triggers {
+		switch t {
+		case TriggerSplit:
+			if !null && result.DeclarerPoints == skat.TotalPoints/2 {
+				fired = append(fired, t)
+			}
+		case TriggerGrandHandLost:
+			if !result.DeclarerWon && result.Contract.GameType == skat.GameGrand && result.Contract.Hand {
+				fired = append(fired, t)
+			}
+		case TriggerSchneiderLost:
+			if !null && !result.DeclarerWon && result.DeclarerPoints <= skat.TotalPoints/4 {
+				fired = append(fired, t)
+			}
+		case TriggerKontraLost:
+			if result.Kontra && result.DeclarerWon {
+				fired = append(fired, t)
+			}
+		case TriggerAnnouncedLost:
+			c := result.Contract
+			if !null && !result.DeclarerWon && (c.Schneider || c.Schwarz || c.Ouvert) {
+				fired = append(fired, t)
+			}
+		case TriggerHirsch:
+			if !null && !result.DeclarerWon && result.Matadors >= 4 {
+				fired = append(fired, t)
+			}
+		}
+	}
+	return fired
+}
+
+// Schedule is the Bock and Ramsch state of a table.
+type Schedule struct {
+	// Next is the next deal to play (Deals + 1 when the table is complete)
+	Next int `json:"next"`
+	// Modes are the modes of the played deals and the next deal
+	Modes []Mode `json:"modes"`
+	// Pending are the modes of the scheduled Bock and Ramsch deals from the next deal on
+	Pending []Mode `json:"pending"`
+	// Fired are the triggers fired by the played deals, by deal
+	Fired map[int][]Trigger `json:"fired,omitempty"`
+}
+
+// Mode returns the mode of a deal (starting at 1) up to the next deal.
+func (s *Schedule) Mode(deal int) Mode {
+	if deal < 1 || deal > len(s.Modes) {
+		return ModeNormal
+	}
+	return s.Modes[deal-1]
+}
+
+// Count returns the number of pending deals of a mode.
+func (s *Schedule) Count(mode Mode) int {
+	n := 0
+	for _, m := range s.Pending {
+		if m == mode {
+			n++
+		}
+	}
+	return n
+}
+
+// schedule returns the schedule of the table from the finished games by deal. The
+// deals are played in order, so the schedule ends at the first deal without a game.
+func (tb *Table) schedule(rules Rules, games map[int]*skat.Game) *Schedule {
+	s := &Schedule{Next: 1, Modes: []Mode{}, Pending: []Mode{}}
+	for ; s.Next <= tb.Deals; s.Next++ {
+		game := games[s.Next]
+		if game == nil {
+			// Not played yet: the next deal has the first pending mode
+			mode := ModeNormal
+			if len(s.Pending) > 0 {
+				mode = s.Pending[0]
+			}
+			s.Modes = append(s.Modes, mode)
+			break
+		}
+
+		mode := ModeNormal
+		if len(s.Pending) > 0 {
+			mode, s.Pending = s.Pending[0], s.Pending[1:]
+		}
+		s.Modes = append(s.Modes, mode)
+		if game.Result == nil || !rules.Enabled() {
+			continue
+		}
+		fired := rules.fired(game.Result)
+		if len(fired) > 0 {
+			if s.Fired == nil {
+				s.Fired = make(map[int][]Trigger)
+			}
+			s.Fired[s.Next] = fired
+		}
+		for range fired {
+			if rules.Bock {
+				s.Pending = appendRound(s.Pending, ModeBock, len(tb.Players))
+			}
+			if rules.Ramsch {
+				s.Pending = appendRound(s.Pending, ModeRamsch, len(tb.Players))
+			}
+		}
+	}
+	if s.Next > tb.Deals {
+		s.Pending = []Mode{}
+	}
+	return s
+}
+
+// appendRound appends a round of n deals of a mode.
+func appendRound(modes []Mode, mode Mode, n int) []Mode {
+	for i := 0; i < n; i++ {
+		modes = append(modes, mode)
+	}
+	return modes
+}
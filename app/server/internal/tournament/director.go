@@ -0,0 +1,318 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Director actions in the audit trail.
+const (
+	ActionAssist     = "assist"
+	ActionPause      = "pause"
+	ActionResume     = "resume"
+	ActionAdjust     = "adjust"
+	ActionSubstitute = "substitute"
+	ActionExtend     = "extend"
+	ActionDisqualify = "disqualify"
+)
+
+// Adjustment is a correction of the Seeger-Fabian score of a player in a series.
+type Adjustment struct {
+	Series int    `json:"series"`
+	Player string `json:"player"`
+	// Score is added to the player's score (negative for a penalty)
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// Substitute is a player replacing another player at a table from a deal on.
+type Substitute struct {
+	// Deal is the first deal played by the substitute
+	Deal int    `json:"deal"`
+	Out  string `json:"out"`
+	In   string `json:"in"`
+}
+
+// Disqualification is a disqualified player. Disqualified players are not seated in
+// further series and are ranked after all other players.
+type Disqualification struct {
+	Player string `json:"player"`
+	Reason string `json:"reason"`
+}
+
+// AuditEntry is a director action in the audit trail of a tournament.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Director string    `json:"director"`
+	Action   string    `json:"action"`
+	// Details describe the action, e.g. "series 1 table 2"
+	Details string `json:"details"`
+}
+
+// IsDirector returns true if the login directs the tournament: the director who
+// created it or a designated assistant.
+func (t *Tournament) IsDirector(login string) bool {
+	if login == t.Director {
+		return true
+	}
+	for _, a := range t.Assistants {
+		if a == login {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDisqualified returns true if a player has been disqualified.
+func (t *Tournament) IsDisqualified(player string) bool {
+	for _, d := range t.Disqualified {
+		if d.Player == player {
+			return true
+		}
+	}
+	return false
+}
+
+// PlayersAt returns the players of the table in seating order at a deal, with the
+// substitutes of the deal in place of the players they replace.
+func (tb *Table) PlayersAt(deal int) []string {
+	players := append([]string(nil), tb.Players...)
+	for _, sub := range tb.Substitutes {
+		if sub.Deal > deal {
+			break
+		}
+		for i, p := range players {
+			if p == sub.Out {
+				players[i] = sub.In
+			}
+		}
+	}
+	return players
+}
+
+// seatedPlayers returns all players who sat at the table, including substitutes.
+func (tb *Table) seatedPlayers() []string {
+	players := append([]string(nil), tb.Players...)
+	for _, sub := range tb.Substitutes {
+		players = append(players, sub.In)
+	}
+	return players
+}
+
+// AddAssistant designates an assistant director (director only).
+func (s *Store) AddAssistant(name, login, assistant string) error {
+	return s.update(name, func(t *Tournament) error {
+		if t.Director != login {
+			return ErrNotDirector
+		}
+		if t.IsDirector(assistant) {
+			return errors.New("already a director")
+		}
+		t.Assistants = append(t.Assistants, assistant)
+		t.audit(login, ActionAssist, assistant)
+		return nil
+	})
+}
+
+// PauseTable pauses or resumes a table of a started series (directors only).
+func (s *Store) PauseTable(name, login string, series, table int, paused bool) error {
+	return s.direct(name, login, func(t *Tournament) error {
+		tb, ok := t.Table(series, table)
+		if !ok {
+			return fmt.Errorf("no table %d in series %d", table, series)
+		}
+		if tb.Paused == paused {
+			if paused {
+				return errors.New("table is already paused")
+			}
+			return errors.New("table is not paused")
+		}
+		tb.Paused = paused
+		action := ActionResume
+		if paused {
+			action = ActionPause
+		}
+		t.audit(login, action, fmt.Sprintf("series %d table %d", series, table))
+		return nil
+	})
+}
+
+// Adjust adds a score correction for a player in a started series (directors only).
+func (s *Store) Adjust(name, login string, series int, player string, score int, reason string) error {
+	return s.direct(name, login, func(t *Tournament) error {
+		if series < 1 || series > len(t.Series) {
+			return fmt.Errorf("series %d not started", series)
+		}
+		if !t.registered(player) {
+			return fmt.Errorf("%s is not registered", player)
+		}
+		if score == 0 {
+			return errors.New("the adjustment must not be 0")
+		}
+		t.Adjustments = append(t.Adjustments, Adjustment{Series: series, Player: player, Score: score, Reason: reason})
+		t.audit(login, ActionAdjust, fmt.Sprintf("series %d %s %+d: %s", series, player, score, reason))
+		return nil
+	})
+}
+
+// Substitute seats a substitute for a player at a table from a deal on (directors only).
+// The substitute is registered if needed and scores the games from that deal on.
+func (s *Store) Substitute(name, login string, series, table, deal int, out, in string) error {
+	return s.direct(name, login, func(t *Tournament) error {
+		tb, ok := t.Table(series, table)
+		if !ok {
+			return fmt.Errorf("no table %d in series %d", table, series)
+		}
+		if deal < 1 || deal > tb.Deals {
+			return fmt.Errorf("invalid deal: %d", deal)
+		}
+		if n := len(tb.Substitutes); n > 0 && tb.Substitutes[n-1].Deal > deal {
+			return fmt.Errorf("a substitute has been seated from deal %d on", tb.Substitutes[n-1].Deal)
+		}
+		if !contains(tb.PlayersAt(deal), out) {
+			return fmt.Errorf("%s does not sit at the table at deal %d", out, deal)
+		}
+		for _, other := range t.Series[series-1].Tables {
+			if contains(other.seatedPlayers(), in) {
+				return fmt.Errorf("%s already sits at table %d", in, other.Number)
+			}
+		}
+		if t.IsDisqualified(in) {
+			return fmt.Errorf("%s is disqualified", in)
+		}
+
+		tb.Substitutes = append(tb.Substitutes, Substitute{Deal: deal, Out: out, In: in})
+		if !t.registered(in) {
+			t.Players = append(t.Players, in)
+		}
+		t.audit(login, ActionSubstitute, fmt.Sprintf("series %d table %d deal %d %s for %s", series, table, deal, in, out))
+		return nil
+	})
+}
+
+// ExtendClock extends the thinking time of the players of a table by the given
+// seconds (directors only).
+func (s *Store) ExtendClock(name, login string, series, table, seconds int) error {
+	return s.direct(name, login, func(t *Tournament) error {
+		tb, ok := t.Table(series, table)
+		if !ok {
+			return fmt.Errorf("no table %d in series %d", table, series)
+		}
+		if seconds <= 0 {
+			return fmt.Errorf("invalid extension: %d", seconds)
+		}
+		tb.Extension += seconds
+		t.audit(login, ActionExtend, fmt.Sprintf("series %d table %d %ds", series, table, seconds))
+		return nil
+	})
+}
+
+// Disqualify disqualifies a registered player (directors only).
+func (s *Store) Disqualify(name, login, player, reason string) error {
+	return s.direct(name, login, func(t *Tournament) error {
+		if !t.registered(player) {
+			return fmt.Errorf("%s is not registered", player)
+		}
+		if t.IsDisqualified(player) {
+			return fmt.Errorf("%s is already disqualified", player)
+		}
+		t.Disqualified = append(t.Disqualified, Disqualification{Player: player, Reason: reason})
+		t.audit(login, ActionDisqualify, fmt.Sprintf("%s: %s", player, reason))
+		return nil
+	})
+}
+
+// direct applies a director action to a running tournament.
+func (s *Store) direct(name, login string, fn func(t *Tournament) error) error {
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status != StatusRunning {
+			return fmt.Errorf("tournament is %s", t.Status)
+		}
+		return fn(t)
+	})
+}
+
+// audit appends an entry to the audit trail.
+func (t *Tournament) audit(director, action, details string) {
+	t.Audit = append(t.Audit, AuditEntry{Time: time.Now(), Director: director, Action: action, Details: details})
+}
+
+// registered returns true if a player is registered.
+func (t *Tournament) registered(player string) bool {
+	return contains(t.Players, player)
+}
+
+// applyDirector applies the adjustments of the series selected by include and the
+// disqualifications to the standings.
+func (t *Tournament) applyDirector(standings []Standing, include func(series int) bool) {
+	index := make(map[string]int)
+	for i, st := range standings {
+		index[st.Player] = i
+	}
+	for _, adj := range t.Adjustments {
+		i, ok := index[adj.Player]
+		if !ok || !include(adj.Series) {
+			continue
+		}
+		standings[i].Adjusted += adj.Score
+		standings[i].Score += adj.Score
+		if adj.Series <= len(standings[i].Series) {
+			standings[i].Series[adj.Series-1] += adj.Score
+		}
+	}
+	for _, d := range t.Disqualified {
+		if i, ok := index[d.Player]; ok {
+			standings[i].Disqualified = true
+		}
+	}
+}
+
+// seatable returns the players to seat in the next series: the registered players in
+// random order for the first series, else in order of the standings, without
+// disqualified players.
+func (t *Tournament) seatable(standings []Standing, shuffle func([]string)) []string {
+	var players []string
+	if len(t.Series) == 0 {
+		players = append(players, t.Players...)
+		shuffle(players)
+	} else {
+		for _, st := range standings {
+			players = append(players, st.Player)
+		}
+	}
+	seatable := players[:0]
+	for _, p := range players {
+		if !t.IsDisqualified(p) {
+			seatable = append(seatable, p)
+		}
+	}
+	return seatable
+}
+
+// contains returns true if the list contains the name.
+func contains(list []string, name string) bool {
+	for _, s := range list {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
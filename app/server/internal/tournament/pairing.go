@@ -0,0 +1,217 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pairing is the way the players of a series are seated at the tables.
+type Pairing string
+
+const (
+	// PairingAuto - round-robin for fields up to RoundRobinPlayers players, else Swiss
+	PairingAuto Pairing = "auto"
+	// PairingSwiss - players with similar scores meet: the tables are filled in order
+	// of the standings
+	PairingSwiss Pairing = "swiss"
+	// PairingRoundRobin - every player meets every other player as evenly as possible,
+	// regardless of the scores
+	PairingRoundRobin Pairing = "roundrobin"
+)
+
+// RoundRobinPlayers is the largest field paired round-robin by PairingAuto.
+const RoundRobinPlayers = 16
+
+// roundRobinTries is the number of random seatings the round-robin pairing tries.
+const roundRobinTries = 200
+
+// ParsePairing parses a pairing name (case-insensitive).
+func ParsePairing(name string) (Pairing, error) {
+	switch p := Pairing(strings.ToLower(name)); p {
+	case PairingAuto, PairingSwiss, PairingRoundRobin:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid pairing: %s (want auto, swiss or roundrobin)", name)
+	}
+}
+
+// resolve returns the pairing used for a field of n players. Tournaments created
+// before pairings were configurable are paired Swiss.
+func (p Pairing) resolve(n int) Pairing {
+	switch p {
+	case PairingAuto:
+		if n <= RoundRobinPlayers {
+			return PairingRoundRobin
+		}
+		return PairingSwiss
+	case PairingRoundRobin:
+		return PairingRoundRobin
+	default:
+		return PairingSwiss
+	}
+}
+
+// meetings counts how often two players sat at the same table.
+type meetings map[[2]string]int
+
+// key returns the key of a pair of players.
+func (m meetings) key(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
+
+// add counts the meetings of the players of a table.
+func (m meetings) add(players []string) {
+	for i, a := range players {
+		for _, b := range players[i+1:] {
+			m[m.key(a, b)]++
+		}
+	}
+}
+
+// with returns how often a player met the players of a table.
+func (m meetings) with(player string, table []string) int {
+	n := 0
+	for _, p := range table {
+		n += m[m.key(player, p)]
+	}
+	return n
+}
+
+// cost returns the number of repeated meetings of a seating.
+func (m meetings) cost(tables [][]string) int {
+	n := 0
+	for _, table := range tables {
+		for i, p := range table {
+			n += m.with(p, table[i+1:])
+		}
+	}
+	return n
+}
+
+// meetings returns the meetings of all series played so far, substitutes included.
+// Team mates count as having met once, so they are seated apart where possible.
+func (t *Tournament) meetings() meetings {
+	m := make(meetings)
+	for _, team := range t.Teams {
+		m.add(team.Players)
+	}
+	for _, series := range t.Series {
+		for _, table := range series.Tables {
+			m.add(table.seatedPlayers())
+		}
+	}
+	return m
+}
+
+// pair seats the players of the next series at tables of three and four by the
+// pairing of the tournament, avoiding repeated tables where possible. For Swiss
+// pairings the players must be in order of the standings.
+func (t *Tournament) pair(players []string, shuffle func([]string)) ([]Table, error) {
+	sizes, err := tableSizes(len(players))
+	if err != nil {
+		return nil, err
+	}
+
+	met := t.meetings()
+	var groups [][]string
+	if t.Pairing.resolve(len(players)) == PairingRoundRobin {
+		groups = pairRoundRobin(players, sizes, met, shuffle)
+	} else {
+		groups = pairSwiss(players, sizes, met)
+	}
+	return seat(groups), nil
+}
+
+// pairSwiss fills the tables in order of the players: every table starts with the
+// best remaining player, followed by the best remaining players who have met nobody
+// at the table yet (or met the table least often). Swapping players of neighbouring
+// tables then removes repeated meetings, reaching further only while repeats remain.
+func pairSwiss(players []string, sizes []int, met meetings) [][]string {
+	groups := fill(players, sizes, met)
+	for distance := 1; distance < len(groups) && met.cost(groups) > 0; distance++ {
+		improve(groups, met, distance)
+	}
+	return groups
+}
+
+// pairRoundRobin tries random seatings, each filled and improved by swapping players
+// of any two tables, and returns the one with the fewest repeated meetings.
+func pairRoundRobin(players []string, sizes []int, met meetings, shuffle func([]string)) [][]string {
+	var best [][]string
+	bestCost := -1
+	order := append([]string(nil), players...)
+	for i := 0; i < roundRobinTries && bestCost != 0; i++ {
+		shuffle(order)
+		groups := fill(order, sizes, met)
+		improve(groups, met, len(groups))
+		if cost := met.cost(groups); bestCost < 0 || cost < bestCost {
+			best, bestCost = groups, cost
+		}
+	}
+	return best
+}
+
+// fill fills tables of the sizes greedily from the ordered players.
+func fill(players []string, sizes []int, met meetings) [][]string {
+	remaining := append([]string(nil), players...)
+	groups := make([][]string, len(sizes))
+	for i, size := range sizes {
+		table := []string{remaining[0]}
+		remaining = remaining[1:]
+		for len(table) < size {
+			pick, least := 0, -1
+			for j, p := range remaining {
+				if n := met.with(p, table); least < 0 || n < least {
+					pick, least = j, n
+					if n == 0 {
+						break
+					}
+				}
+			}
+			table = append(table, remaining[pick])
+			remaining = append(remaining[:pick], remaining[pick+1:]...)
+		}
+		groups[i] = table
+	}
+	return groups
+}
+
+// improve swaps players between tables at most distance apart as long as a swap
+// reduces the repeated meetings.
+func improve(groups [][]string, met meetings, distance int) {
+	for improved := true; improved; {
+		improved = false
+		for i := range groups {
+			for j := i + 1; j < len(groups) && j-i <= distance; j++ {
+				for a := range groups[i] {
+					for b := range groups[j] {
+						before := met.cost([][]string{groups[i], groups[j]})
+						groups[i][a], groups[j][b] = groups[j][b], groups[i][a]
+						if met.cost([][]string{groups[i], groups[j]}) < before {
+							improved = true
+							continue
+						}
+						groups[i][a], groups[j][b] = groups[j][b], groups[i][a]
+					}
+				}
+			}
+		}
+	}
+}
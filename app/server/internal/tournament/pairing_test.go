@@ -0,0 +1,98 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// testPlayers returns n players p1 to pn.
+func testPlayers(n int) []string {
+	players := make([]string, n)
+	for i := range players {
+		players[i] = fmt.Sprintf("p%d", i+1)
+	}
+	return players
+}
+
+func TestParsePairing(t *testing.T) {
+	for _, name := range []string{"auto", "Swiss", "ROUNDROBIN"} {
+		if _, err := ParsePairing(name); err != nil {
+			t.Errorf("ParsePairing(%q) error: %v", name, err)
+		}
+	}
+	if _, err := ParsePairing("knockout"); err == nil {
+		t.Error("ParsePairing(knockout): expected error")
+	}
+}
+
+func TestPair(t *testing.T) {
+	tests := []struct {
+		pairing Pairing
+		players int
+		series  int // series paired without repeated opponents
+		teams   []Team
+	}{
+		{PairingSwiss, 9, 2, nil},
+		{PairingSwiss, 12, 3, nil},
+		{PairingSwiss, 13, 2, nil},
+		{PairingRoundRobin, 9, 3, nil},
+		{PairingRoundRobin, 12, 3, nil},
+		{PairingAuto, 15, 3, nil},
+		{PairingAuto, 18, 3, nil},
+		// Team mates are seated apart
+		{PairingRoundRobin, 9, 2, []Team{{Name: "a", Players: []string{"p1", "p2", "p3"}}, {Name: "b", Players: []string{"p4", "p5", "p6"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d players", tt.pairing, tt.players), func(t *testing.T) {
+			players := testPlayers(tt.players)
+			tr := &Tournament{Name: "cup", Players: players, Pairing: tt.pairing, Teams: tt.teams}
+			rng := rand.New(rand.NewSource(1))
+			shuffle := func(s []string) { rng.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] }) }
+
+			for series := 1; series <= tt.series; series++ {
+				met := tr.meetings()
+				tables, err := tr.pair(players, shuffle)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// Every player is seated once, at tables of three and four
+				seated := make(map[string]int)
+				groups := make([][]string, len(tables))
+				for i, table := range tables {
+					if n := len(table.Players); n < 3 || n > 4 || table.Number != i+1 {
+						t.Errorf("series %d: table %d = %+v", series, i+1, table)
+					}
+					for _, p := range table.Players {
+						seated[p]++
+					}
+					groups[i] = table.Players
+				}
+				for _, p := range players {
+					if seated[p] != 1 {
+						t.Errorf("series %d: %s seated %d times", series, p, seated[p])
+					}
+				}
+				if cost := met.cost(groups); cost != 0 {
+					t.Errorf("series %d: %d repeated meetings in %v", series, cost, groups)
+				}
+				tr.Series = append(tr.Series, Series{Number: series, Tables: tables})
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Profile is a rule profile bound to a tournament. Every table of the tournament plays
+// by it; the tables cannot override its options.
+type Profile struct {
+	Name string `json:"name"`
+	// Kontra allows Kontra and Re announcements
+	Kontra bool `json:"kontra"`
+	// KontraTiming is the last moment for Kontra
+	KontraTiming skat.KontraTiming `json:"kontra_timing"`
+	// KontraMinBid is the value a defender must have bid or held to announce Kontra
+	KontraMinBid int `json:"kontra_min_bid,omitempty"`
+	// Ramsch allows Ramsch rounds
+	Ramsch bool `json:"ramsch"`
+	// RamschSkat is who gets the skat in Ramsch games
+	RamschSkat skat.RamschSkat `json:"ramsch_skat"`
+	// RamschScoring is how the Ramsch loser scores: card points or a fixed penalty
+	RamschScoring skat.RamschScoring `json:"ramsch_scoring,omitempty"`
+	// RamschGrandHand lets Forehand play a Grand Hand instead of the Ramsch
+	RamschGrandHand bool `json:"ramsch_grand_hand,omitempty"`
+	// Bock allows Bock rounds
+	Bock bool `json:"bock"`
+	// BockTriggers replace the triggers of the tournament's Bock and Ramsch rounds (empty = keep them)
+	BockTriggers []Trigger `json:"bock_triggers,omitempty"`
+	// RefuseOverbid refuses announcements that cannot reach the bid
+	RefuseOverbid bool `json:"refuse_overbid,omitempty"`
+	// HideHandSkat keeps the untouched skat of Hand games hidden after the game
+	HideHandSkat bool `json:"hide_hand_skat,omitempty"`
+	// Clock is the thinking time of every player per deal in seconds (0 = untimed)
+	Clock int `json:"clock"`
+	// Grace is the time in seconds a player who disconnected during the trick play has
+	// to come back before forfeiting the game (0 = the game is adjourned until then)
+	Grace int `json:"grace,omitempty"`
+	// VacationDays are the days per year a player of correspondence games by the
+	// profile can be on vacation, with the move time paused (0 = no vacation)
+	VacationDays int `json:"vacation_days,omitempty"`
+}
+
+// Profiles are the known rule profiles by name.
+var Profiles = map[string]Profile{
+	// ISkO: the international Skat rules of tournament play, without Kontra, Ramsch
+	// and Bock, with a thinking time of two minutes per player and deal; disconnected
+	// players forfeit their game after two minutes; 14 vacation days in correspondence games
+	"isko": {Name: "isko", Clock: 120, Grace: 120, VacationDays: 14},
+	// Club: everything the tables allow, untimed; Kontra before the declarer's first
+	// card by defenders who bid or held 18; games that cannot reach the bid are refused;
+	// 30 vacation days in correspondence games
+	"club": {Name: "club", Kontra: true, KontraMinBid: skat.MinBid, Ramsch: true, Bock: true, RefuseOverbid: true,
+		VacationDays: 30},
+}
+
+// ParseProfile returns the rule profile of a name (case-insensitive).
+func ParseProfile(name string) (Profile, error) {
+	if p, ok := Profiles[strings.ToLower(name)]; ok {
+		return p, nil
+	}
+	names := make([]string, 0, len(Profiles))
+	for n := range Profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return Profile{}, fmt.Errorf("invalid rule profile: %s (want %s)", name, strings.Join(names, ", "))
+}
+
+// String returns the options of the profile, e.g. "isko kontra=off ramsch=off bock=off clock=120".
+func (p Profile) String() string {
+	onOff := func(b bool) string {
+		if b {
+			return "on"
+		}
+		return "off"
+	}
+	return fmt.Sprintf("%s kontra=%s ramsch=%s bock=%s clock=%d", p.Name, onOff(p.Kontra), onOff(p.Ramsch), onOff(p.Bock), p.Clock)
+}
+
+// AllPass returns what happens when all players pass: a Ramsch if the profile allows
+// Ramsch rounds (unless Forehand plays a Grand Hand with RamschGrandHand), otherwise
+// the deal is thrown in.
+func (p Profile) AllPass() skat.AllPass {
+	if p.Ramsch && p.RamschGrandHand {
+		return skat.AllPassGrandHand
+	}
+	if p.Ramsch {
+		return skat.AllPassRamsch
+	}
+	return skat.AllPassThrowIn
+}
+
+// KontraRules returns the Kontra and Re rules of the profile.
+func (p Profile) KontraRules() skat.KontraRules {
+	return skat.KontraRules{Off: !p.Kontra, Timing: p.KontraTiming, MinBid: p.KontraMinBid}
+}
+
+// Rules returns the Bock and Ramsch rules of the tables: the rules of the tournament
+// without the rounds its profile forbids, triggered by the profile's triggers if it has any.
+func (t *Tournament) Rules() Rules {
+	rules := t.Bock.Copy()
+	if t.Profile != nil {
+		rules.Bock = rules.Bock && t.Profile.Bock
+		rules.Ramsch = rules.Ramsch && t.Profile.Ramsch
+		if len(t.Profile.BockTriggers) > 0 {
+			rules.Triggers = append([]Trigger(nil), t.Profile.BockTriggers...)
+		}
+	}
+	return rules
+}
+
+// NewGame returns the game of the next deal at a table of a started series, with the
+// Kontra, all-pass, overbid, Ramsch and Hand skat rules and the thinking times of
+// the profile plus the extension of the directors. Tables of the tournament create
+// their games with it, so they play by the profile.
+func (t *Tournament) NewGame(series, table int) (*skat.Game, error) {
+	if t.Status != StatusRunning {
+		return nil, fmt.Errorf("tournament is %s", t.Status)
+	}
+	tb, ok := t.Table(series, table)
+	if !ok {
+		return nil, fmt.Errorf("no table %d in series %d", table, series)
+	}
+	if tb.Paused {
+		return nil, errors.New("table is paused")
+	}
+
+	game := skat.NewGame()
+	if t.Profile != nil {
+		rules := t.Profile.KontraRules()
+		game.KontraRules = &rules
+		game.RamschSkat = t.Profile.RamschSkat
+		game.RamschScoring = t.Profile.RamschScoring
+		game.AllPass = t.Profile.AllPass()
+		game.RefuseOverbid = t.Profile.RefuseOverbid
+		game.HideHandSkat = t.Profile.HideHandSkat
+	}
+	if t.Profile != nil && t.Profile.Clock > 0 {
+		clock := time.Duration(t.Profile.Clock+tb.Extension) * time.Second
+		game.Clocks = make(map[skat.Player]time.Duration)
+		for _, p := range skat.AllPlayers {
+			game.Clocks[p] = clock
+		}
+	}
+	return game, nil
+}
+
+// SetProfile binds a rule profile to a tournament in registration status (directors
+// only; nil removes it). Once the first series has started, the profile is fixed.
+func (s *Store) SetProfile(name, login string, profile *Profile) error {
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status != StatusRegistration {
+			return errors.New("the tournament has started")
+		}
+		t.Profile = profile
+		return nil
+	})
+}
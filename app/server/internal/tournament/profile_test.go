@@ -0,0 +1,177 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestParseProfile(t *testing.T) {
+	for _, name := range []string{"isko", "Club"} {
+		p, err := ParseProfile(name)
+		if err != nil || p.Name == "" {
+			t.Errorf("ParseProfile(%q) = %+v, %v", name, p, err)
+		}
+	}
+	if _, err := ParseProfile("pub"); err == nil {
+		t.Error("ParseProfile(pub): expected error")
+	}
+}
+
+func TestProfileAllPass(t *testing.T) {
+	tests := []struct {
+		profile Profile
+		want    skat.AllPass
+	}{
+		{Profile{}, skat.AllPassThrowIn},
+		{Profile{RamschGrandHand: true}, skat.AllPassThrowIn},
+		{Profile{Ramsch: true}, skat.AllPassRamsch},
+		{Profile{Ramsch: true, RamschGrandHand: true}, skat.AllPassGrandHand},
+	}
+	for _, tt := range tests {
+		if got := tt.profile.AllPass(); got != tt.want {
+			t.Errorf("%+v AllPass() = %v, want %v", tt.profile, got, tt.want)
+		}
+	}
+}
+
+func TestTournamentRules(t *testing.T) {
+	bock := Rules{Triggers: []Trigger{TriggerSplit}, Bock: true, Ramsch: true}
+	tests := []struct {
+		name    string
+		profile *Profile
+		want    Rules
+	}{
+		{"no profile", nil, bock},
+		{"isko", &Profile{Name: "isko"}, Rules{Triggers: []Trigger{TriggerSplit}}},
+		{"Bock only", &Profile{Bock: true}, Rules{Triggers: []Trigger{TriggerSplit}, Bock: true}},
+		{"own triggers", &Profile{Bock: true, Ramsch: true, BockTriggers: []Trigger{TriggerHirsch}},
+			Rules{Triggers: []Trigger{TriggerHirsch}, Bock: true, Ramsch: true}},
+	}
+	for _, tt := range tests {
+		tr := &Tournament{Bock: bock.Copy(), Profile: tt.profile}
+		if got := tr.Rules(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Rules() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewGame(t *testing.T) {
+	club := Profiles["club"]
+	isko := Profiles["isko"]
+	running := func(profile *Profile) *Tournament {
+		return &Tournament{
+			Name:    "cup",
+			Status:  StatusRunning,
+			Profile: profile,
+			Series: []Series{{Number: 1, Tables: []Table{
+				{Number: 1, Players: []string{"anna", "ben", "carl"}, Deals: 36},
+				{Number: 2, Players: []string{"dora", "emil", "fritz"}, Deals: 36, Extension: 30},
+				{Number: 3, Players: []string{"gerd", "hans", "ida"}, Deals: 36, Paused: true},
+			}}},
+		}
+	}
+
+	// The club profile allows Kontra from a bid of 18 and refuses overbids, untimed
+	game, err := running(&club).NewGame(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := skat.KontraRules{MinBid: skat.MinBid}
+	if game.KontraRules == nil || *game.KontraRules != want || game.AllPass != skat.AllPassRamsch ||
+		!game.RefuseOverbid || game.Clocks != nil {
+		t.Errorf("club game = %+v", game)
+	}
+
+	// ISkO forbids Kontra and throws the deal in; the clock includes the extension
+	game, err = running(&isko).NewGame(1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.KontraRules == nil || !game.KontraRules.Off || game.AllPass != skat.AllPassThrowIn || game.RefuseOverbid {
+		t.Errorf("isko game = %+v", game)
+	}
+	for _, p := range skat.AllPlayers {
+		if got := game.Clocks[p]; got != 150*time.Second {
+			t.Errorf("clock of %v = %v, want 2m30s", p, got)
+		}
+	}
+
+	// Without a profile, the game keeps the defaults of the tables
+	game, err = running(nil).NewGame(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.KontraRules != nil || game.Clocks != nil {
+		t.Errorf("game without profile = %+v", game)
+	}
+
+	for _, tt := range []struct {
+		name          string
+		status        Status
+		series, table int
+	}{
+		{"registration", StatusRegistration, 1, 1},
+		{"unknown table", StatusRunning, 1, 4},
+		{"unknown series", StatusRunning, 2, 1},
+		{"paused table", StatusRunning, 1, 3},
+	} {
+		tr := running(&isko)
+		tr.Status = tt.status
+		if _, err := tr.NewGame(tt.series, tt.table); err == nil {
+			t.Errorf("%s: NewGame(%d, %d): expected error", tt.name, tt.series, tt.table)
+		}
+	}
+}
+
+func TestSetProfile(t *testing.T) {
+	s := openTestStore(t, "anna", "ben", "carl")
+	isko := Profiles["isko"]
+	if err := s.SetProfile("cup", "anna", &isko); err != ErrNotDirector {
+		t.Errorf("SetProfile() by a player = %v, want %v", err, ErrNotDirector)
+	}
+	if err := s.SetProfile("cup", "dora", &isko); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := s.Get("cup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Profile == nil || tr.Profile.Name != "isko" {
+		t.Fatalf("profile = %+v, want isko", tr.Profile)
+	}
+
+	// The profile is fixed once the first series has started
+	if _, err := s.NextSeries("cup", "dora", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetProfile("cup", "dora", nil); err == nil {
+		t.Error("SetProfile() after the start: expected error")
+	}
+	if tr, err = s.Get("cup"); err != nil {
+		t.Fatal(err)
+	}
+	game, err := tr.NewGame(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.KontraRules == nil || !game.KontraRules.Off || game.Clocks == nil {
+		t.Errorf("game of the started tournament = %+v, want the isko rules", game)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Registration is the registration status of a player.
+type Registration string
+
+const (
+	// RegistrationConfirmed - the player is registered and plays
+	RegistrationConfirmed Registration = "registered"
+	// RegistrationPending - the player has a seat, but the fee has not been paid yet
+	RegistrationPending Registration = "pending"
+	// RegistrationWaitlisted - the player waits for a free seat
+	RegistrationWaitlisted Registration = "waitlisted"
+	// RegistrationCancelled - the registration has been withdrawn or declined
+	RegistrationCancelled Registration = "cancelled"
+)
+
+// RegistrationOf returns the registration status of a player ("" if not registered).
+func (t *Tournament) RegistrationOf(player string) Registration {
+	switch {
+	case contains(t.Players, player):
+		return RegistrationConfirmed
+	case contains(t.Pending, player):
+		return RegistrationPending
+	case contains(t.Waitlist, player):
+		return RegistrationWaitlisted
+	default:
+		return ""
+	}
+}
+
+// full returns true if all seats are taken by registered and pending players.
+func (t *Tournament) full() bool {
+	return t.Capacity > 0 && len(t.Players)+len(t.Pending) >= t.Capacity
+}
+
+// seat gives a player a seat: registered, or pending if the tournament has a fee.
+func (t *Tournament) seat(player string) Registration {
+	if t.Fee > 0 {
+		t.Pending = append(t.Pending, player)
+		return RegistrationPending
+	}
+	t.Players = append(t.Players, player)
+	return RegistrationConfirmed
+}
+
+// change is a changed registration of a player.
+type change struct {
+	player string
+	status Registration
+}
+
+// promote seats the waiting players while seats are free and returns their changes.
+func (t *Tournament) promote() []change {
+	var changes []change
+	for len(t.Waitlist) > 0 && !t.full() {
+		player := t.Waitlist[0]
+		t.Waitlist = t.Waitlist[1:]
+		changes = append(changes, change{player, t.seat(player)})
+	}
+	return changes
+}
+
+// OnRegistration sets a function called with every registration change, e.g. to request
+// the payment of the fee or to notify the player. It must be set before the store is used.
+func (s *Store) OnRegistration(fn func(name, player string, status Registration)) {
+	s.onRegistration = fn
+}
+
+// Register registers a player for a tournament in registration status and returns the
+// status: registered, pending until the fee is paid, or waitlisted if all seats are taken.
+func (s *Store) Register(name, player string) (Registration, error) {
+	var status Registration
+	err := s.register(name, func(t *Tournament) ([]change, error) {
+		if t.RegistrationOf(player) != "" {
+			return nil, errors.New("already registered")
+		}
+		if t.full() {
+			t.Waitlist = append(t.Waitlist, player)
+			status = RegistrationWaitlisted
+		} else {
+			status = t.seat(player)
+		}
+		return []change{{player, status}}, nil
+	})
+	return status, err
+}
+
+// Unregister withdraws the registration of a player (registered, pending or waitlisted)
+// from a tournament in registration status. A freed seat goes to the first waiting player.
+func (s *Store) Unregister(name, player string) error {
+	return s.register(name, func(t *Tournament) ([]change, error) {
+		switch t.RegistrationOf(player) {
+		case RegistrationConfirmed:
+			t.Players = remove(t.Players, player)
+			t.removeFromTeams(player)
+		case RegistrationPending:
+			t.Pending = remove(t.Pending, player)
+		case RegistrationWaitlisted:
+			t.Waitlist = remove(t.Waitlist, player)
+		default:
+			return nil, errors.New("not registered")
+		}
+		return append([]change{{player, RegistrationCancelled}}, t.promote()...), nil
+	})
+}
+
+// ConfirmPayment confirms the pending registration of a player whose fee has been paid
+// (paid) or declines it, which frees the seat for the first waiting player.
+func (s *Store) ConfirmPayment(name, player string, paid bool) error {
+	return s.register(name, func(t *Tournament) ([]change, error) {
+		if t.RegistrationOf(player) != RegistrationPending {
+			return nil, fmt.Errorf("no pending registration of %s", player)
+		}
+		t.Pending = remove(t.Pending, player)
+		if !paid {
+			return append([]change{{player, RegistrationCancelled}}, t.promote()...), nil
+		}
+		t.Players = append(t.Players, player)
+		return []change{{player, RegistrationConfirmed}}, nil
+	})
+}
+
+// SetCapacity limits the seats of a tournament in registration status (0 = unlimited;
+// directors only). Raising the capacity seats waiting players; lowering it keeps
+// the registrations made so far.
+func (s *Store) SetCapacity(name, login string, capacity int) error {
+	if capacity < 0 {
+		return fmt.Errorf("invalid capacity: %d", capacity)
+	}
+	return s.register(name, func(t *Tournament) ([]change, error) {
+		if !t.IsDirector(login) {
+			return nil, ErrNotDirector
+		}
+		t.Capacity = capacity
+		return t.promote(), nil
+	})
+}
+
+// SetFee sets the seat fee in cents of a tournament in registration status (directors
+// only). The fee applies to registrations from now on.
+func (s *Store) SetFee(name, login string, cents int) error {
+	if cents < 0 {
+		return fmt.Errorf("invalid fee: %d", cents)
+	}
+	return s.register(name, func(t *Tournament) ([]change, error) {
+		if !t.IsDirector(login) {
+			return nil, ErrNotDirector
+		}
+		t.Fee = cents
+		return nil, nil
+	})
+}
+
+// register applies a registration change to a tournament in registration status and
+// reports the changed registrations.
+func (s *Store) register(name string, fn func(t *Tournament) ([]change, error)) error {
+	var changes []change
+	err := s.update(name, func(t *Tournament) error {
+		if t.Status != StatusRegistration {
+			return errors.New("registration is closed")
+		}
+		var err error
+		changes, err = fn(t)
+		return err
+	})
+	if err != nil || s.onRegistration == nil {
+		return err
+	}
+	for _, c := range changes {
+		s.onRegistration(name, c.player, c.status)
+	}
+	return nil
+}
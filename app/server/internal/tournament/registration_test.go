@@ -0,0 +1,118 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistration(t *testing.T) {
+	s := openTestStore(t)
+	var notified []string
+	s.OnRegistration(func(name, player string, status Registration) {
+		notified = append(notified, player+":"+string(status))
+	})
+	if err := s.SetCapacity("cup", "dora", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetFee("cup", "dora", 500); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each step changes the registrations of the tournament with a capacity of two
+	// seats and a fee, then the seats and the waitlist are checked
+	tests := []struct {
+		name     string
+		change   func() error
+		notified []string
+		players  []string
+		pending  []string
+		waitlist []string
+		wantErr  bool
+	}{
+		{"pending until paid", register(s, "anna"), []string{"anna:pending"}, nil, []string{"anna"}, nil, false},
+		{"second seat", register(s, "ben"), []string{"ben:pending"}, nil, []string{"anna", "ben"}, nil, false},
+		{"waitlisted when full", register(s, "carl"), []string{"carl:waitlisted"}, nil, []string{"anna", "ben"}, []string{"carl"}, false},
+		{"waitlisted in order", register(s, "emil"), []string{"emil:waitlisted"}, nil, []string{"anna", "ben"}, []string{"carl", "emil"}, false},
+		{"registered twice", register(s, "ben"), nil, nil, []string{"anna", "ben"}, []string{"carl", "emil"}, true},
+		{"paid", func() error { return s.ConfirmPayment("cup", "anna", true) }, []string{"anna:registered"},
+			[]string{"anna"}, []string{"ben"}, []string{"carl", "emil"}, false},
+		{"paid twice", func() error { return s.ConfirmPayment("cup", "anna", true) }, nil,
+			[]string{"anna"}, []string{"ben"}, []string{"carl", "emil"}, true},
+		{"declined payment frees the seat", func() error { return s.ConfirmPayment("cup", "ben", false) },
+			[]string{"ben:cancelled", "carl:pending"}, []string{"anna"}, []string{"carl"}, []string{"emil"}, false},
+		{"withdrawn from the waitlist", func() error { return s.Unregister("cup", "emil") }, []string{"emil:cancelled"},
+			[]string{"anna"}, []string{"carl"}, nil, false},
+		{"not registered", func() error { return s.Unregister("cup", "emil") }, nil, []string{"anna"}, []string{"carl"}, nil, true},
+		{"more seats for the waitlist", func() error {
+			if _, err := s.Register("cup", "fritz"); err != nil {
+				return err
+			}
+			return s.SetCapacity("cup", "dora", 3)
+		}, []string{"fritz:waitlisted", "fritz:pending"}, []string{"anna"}, []string{"carl", "fritz"}, nil, false},
+		{"capacity by others than the directors", func() error { return s.SetCapacity("cup", "anna", 9) }, nil,
+			[]string{"anna"}, []string{"carl", "fritz"}, nil, true},
+		{"withdrawn seat goes to the waitlist", func() error {
+			if _, err := s.Register("cup", "gerd"); err != nil {
+				return err
+			}
+			return s.Unregister("cup", "anna")
+		}, []string{"gerd:waitlisted", "anna:cancelled", "gerd:pending"}, nil, []string{"carl", "fritz", "gerd"}, nil, false},
+	}
+	for _, tt := range tests {
+		notified = nil
+		err := tt.change()
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: error = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		tr, _ := s.Get("cup")
+		if !reflect.DeepEqual(notified, tt.notified) || !equalNames(tr.Players, tt.players) ||
+			!equalNames(tr.Pending, tt.pending) || !equalNames(tr.Waitlist, tt.waitlist) {
+			t.Errorf("%s: notified %v, players %v, pending %v, waitlist %v; want %v, %v, %v, %v", tt.name,
+				notified, tr.Players, tr.Pending, tr.Waitlist, tt.notified, tt.players, tt.pending, tt.waitlist)
+		}
+	}
+
+	// Registration closes with the first series
+	for _, p := range []string{"carl", "fritz", "gerd"} {
+		if err := s.ConfirmPayment("cup", p, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.NextSeries("cup", "dora", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Register("cup", "hans"); err == nil {
+		t.Error("Register() after the start: expected error")
+	}
+	if err := s.SetFee("cup", "dora", -1); err == nil {
+		t.Error("SetFee(-1): expected error")
+	}
+}
+
+// register returns a change registering a player.
+func register(s *Store, player string) func() error {
+	return func() error {
+		_, err := s.Register("cup", player)
+		return err
+	}
+}
+
+// equalNames returns true if the lists hold the same names in the same order; nil
+// equals an empty list.
+func equalNames(a, b []string) bool {
+	return len(a) == 0 && len(b) == 0 || reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Stage is a stage of a multi-stage tournament, e.g. qualification groups followed by
+// semifinal and final tables. The players of a stage are split into groups, which
+// are seated and ranked separately; the best players of each group advance to the
+// next stage, which starts from zero.
+type Stage struct {
+	Name string `json:"name"`
+	// Series is the number of series of the stage
+	Series int `json:"series"`
+	// Groups is the number of groups
+	Groups int `json:"groups"`
+	// Advance is the number of players of each group advancing to the next stage
+	// (0 in the last stage)
+	Advance int `json:"advance,omitempty"`
+	// Assigned are the players of each group, set when the stage starts
+	Assigned [][]string `json:"assigned,omitempty"`
+}
+
+// ParseStages parses comma-separated stages "<name>:<series>:<groups>:<advance>",
+// e.g. "qualification:2:4:3,final:1:1:0" (four qualification groups of two series,
+// the best three of each group play one final series at one final group).
+func ParseStages(spec string) ([]Stage, error) {
+	var stages []Stage
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid stage: %s (want <name>:<series>:<groups>:<advance>)", part)
+		}
+		stage := Stage{Name: fields[0]}
+		for i, n := range []*int{&stage.Series, &stage.Groups, &stage.Advance} {
+			v, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid stage: %s (want <name>:<series>:<groups>:<advance>)", part)
+			}
+			*n = v
+		}
+		stages = append(stages, stage)
+	}
+	return stages, validateStages(stages)
+}
+
+// validateStages checks that every stage has a name, series and groups, that every
+// stage but the last advances players and that the advancing players can be seated
+// in the groups of the next stage.
+func validateStages(stages []Stage) error {
+	if len(stages) == 0 {
+		return errors.New("no stages")
+	}
+	for i, stage := range stages {
+		if !validName(stage.Name) {
+			return fmt.Errorf("invalid stage name: %q", stage.Name)
+		}
+		if stage.Series < 1 || stage.Groups < 1 || stage.Advance < 0 {
+			return fmt.Errorf("stage %s: invalid series, groups or advancing players", stage.Name)
+		}
+		if i == len(stages)-1 {
+			if stage.Advance != 0 {
+				return fmt.Errorf("stage %s: nobody advances from the last stage", stage.Name)
+			}
+			continue
+		}
+		if stage.Advance == 0 {
+			return fmt.Errorf("stage %s: no advancing players", stage.Name)
+		}
+		next := stages[i+1]
+		for _, size := range groupSizes(stage.Groups*stage.Advance, next.Groups) {
+			if _, err := tableSizes(size); err != nil {
+				return fmt.Errorf("stage %s: %w", next.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// groupSizes returns the sizes of n players split into g groups as evenly as possible.
+func groupSizes(n, g int) []int {
+	sizes := make([]int, g)
+	for i := range sizes {
+		sizes[i] = n / g
+		if i < n%g {
+			sizes[i]++
+		}
+	}
+	return sizes
+}
+
+// StageOf returns the stage (starting at 1) of a series and whether the series is the
+// first of its stage. Without stages, the stage is 0.
+func (t *Tournament) StageOf(series int) (int, bool) {
+	first := 1
+	for i, stage := range t.Stages {
+		if series < first+stage.Series {
+			return i + 1, series == first
+		}
+		first += stage.Series
+	}
+	return 0, false
+}
+
+// StageStandings returns the standings of each group of a started stage (starting at 1)
+// from the series of the stage.
+func (t *Tournament) StageStandings(stage int, records []*skat.GameRecord) ([][]Standing, error) {
+	if stage < 1 || stage > len(t.Stages) || t.Stages[stage-1].Assigned == nil {
+		return nil, fmt.Errorf("stage %d not started", stage)
+	}
+	include := func(series int) bool {
+		return t.Series[series-1].Stage == stage
+	}
+	var groups [][]Standing
+	for _, players := range t.Stages[stage-1].Assigned {
+		standings, err := t.standings(players, include, records)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, standings)
+	}
+	return groups, nil
+}
+
+// advancing returns the players in the advancing places of group standings: the best
+// n players who are not disqualified.
+func advancing(standings []Standing, n int) []string {
+	var players []string
+	for _, st := range standings {
+		if len(players) == n || st.Disqualified {
+			break
+		}
+		players = append(players, st.Player)
+	}
+	return players
+}
+
+// start assigns the seeded players to the groups of a stage in snake order, so
+// the best seeds are spread over the groups.
+func (stage *Stage) start(seeded []string) {
+	stage.Assigned = make([][]string, stage.Groups)
+	for i, p := range seeded {
+		g := i % (2 * stage.Groups)
+		if g >= stage.Groups {
+			g = 2*stage.Groups - 1 - g
+		}
+		stage.Assigned[g] = append(stage.Assigned[g], p)
+	}
+}
+
+// stageTables returns the tables of the next series of a multi-stage tournament. The
+// first series of a stage assigns its players to the groups: the registered players in
+// random order for the first stage, else the advancing players of the previous stage,
+// group winners first. Every group is seated separately by the tournament's pairing,
+// ordered by the group standings of the stage.
+func (t *Tournament) stageTables(records []*skat.GameRecord, shuffle func([]string)) (int, []Table, error) {
+	number, first := t.StageOf(len(t.Series) + 1)
+	stage := &t.Stages[number-1]
+	if first {
+		var seeded []string
+		if number == 1 {
+			seeded = t.seatable(nil, shuffle)
+		} else {
+			groups, err := t.StageStandings(number-1, records)
+			if err != nil {
+				return 0, nil, err
+			}
+			// Group winners first, then the second places, ...
+			previous := t.Stages[number-2]
+			for place := 0; place < previous.Advance; place++ {
+				for _, standings := range groups {
+					if players := advancing(standings, previous.Advance); place < len(players) {
+						seeded = append(seeded, players[place])
+					}
+				}
+			}
+		}
+		stage.start(seeded)
+	}
+
+	var groups [][]Standing
+	if !first {
+		var err error
+		if groups, err = t.StageStandings(number, records); err != nil {
+			return 0, nil, err
+		}
+	}
+	var tables []Table
+	for g, players := range stage.Assigned {
+		order := players
+		if !first {
+			order = nil
+			for _, st := range groups[g] {
+				order = append(order, st.Player)
+			}
+		}
+		var seated []string
+		for _, p := range order {
+			if !t.IsDisqualified(p) {
+				seated = append(seated, p)
+			}
+		}
+		groupTables, err := t.pair(seated, shuffle)
+		if err != nil {
+			return 0, nil, fmt.Errorf("group %d: %w", g+1, err)
+		}
+		for _, table := range groupTables {
+			table.Number = len(tables) + 1
+			table.Group = g + 1
+			tables = append(tables, table)
+		}
+	}
+	return number, tables, nil
+}
+
+// SetStages makes a tournament in registration status a multi-stage tournament
+// (directors only). The number of series becomes the sum of the series of the stages.
+func (s *Store) SetStages(name, login string, stages []Stage) error {
+	if err := validateStages(stages); err != nil {
+		return err
+	}
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status != StatusRegistration {
+			return errors.New("registration is closed")
+		}
+		t.Stages = nil
+		t.SeriesCount = 0
+		for _, stage := range stages {
+			stage.Assigned = nil
+			t.Stages = append(t.Stages, stage)
+			t.SeriesCount += stage.Series
+		}
+		return nil
+	})
+}
+
+// BracketGroup is a group of a stage in the bracket.
+type BracketGroup struct {
+	// Number is the group number (starting at 1)
+	Number    int        `json:"number"`
+	Standings []Standing `json:"standings"`
+	// Advancing are the players in the advancing places (final once the stage is complete)
+	Advancing []string `json:"advancing"`
+}
+
+// BracketStage is a stage in the bracket.
+type BracketStage struct {
+	// Number is the stage number (starting at 1)
+	Number  int    `json:"number"`
+	Name    string `json:"name"`
+	Series  int    `json:"series"`
+	Advance int    `json:"advance"`
+	// Started is the number of started series of the stage
+	Started int `json:"started"`
+	// Groups are the groups of a started stage
+	Groups []BracketGroup `json:"groups"`
+}
+
+// Bracket returns the stages with the standings of their groups from the tournament's
+// games, e.g. to visualize the bracket. Without stages, the bracket is empty.
+func (t *Tournament) Bracket(records []*skat.GameRecord) ([]BracketStage, error) {
+	bracket := []BracketStage{}
+	for i, stage := range t.Stages {
+		bs := BracketStage{Number: i + 1, Name: stage.Name, Series: stage.Series, Advance: stage.Advance, Groups: []BracketGroup{}}
+		for _, series := range t.Series {
+			if series.Stage == i+1 {
+				bs.Started++
+			}
+		}
+		if stage.Assigned != nil {
+			groups, err := t.StageStandings(i+1, records)
+			if err != nil {
+				return nil, err
+			}
+			for g, standings := range groups {
+				bs.Groups = append(bs.Groups, BracketGroup{
+					Number: g + 1, Standings: standings, Advancing: append([]string{}, advancing(standings, stage.Advance)...),
+				})
+			}
+		}
+		bracket = append(bracket, bs)
+	}
+	return bracket, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseStages(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []Stage
+		wantErr bool
+	}{
+		{"qualification:2:4:3,final:1:1:0", []Stage{
+			{Name: "qualification", Series: 2, Groups: 4, Advance: 3},
+			{Name: "final", Series: 1, Groups: 1},
+		}, false},
+		{"final:3:1:0", []Stage{{Name: "final", Series: 3, Groups: 1}}, false},
+		{"final:3:1", nil, true},
+		{"final:x:1:0", nil, true},
+		{"final:1:1:2", nil, true},              // the last stage advances players
+		{"groups:1:2:0,final:1:1:0", nil, true}, // nobody advances
+		{"groups:1:0:3,final:1:1:0", nil, true}, // no groups
+		{"groups:1:5:1,final:1:1:0", nil, true}, // five players cannot be seated
+		{"groups:1:2:5,final:1:2:0", nil, true}, // nor two groups of five
+		{"groups:1:2:2,semi:1:1:3,final:1:1:0", []Stage{
+			{Name: "groups", Series: 1, Groups: 2, Advance: 2},
+			{Name: "semi", Series: 1, Groups: 1, Advance: 3},
+			{Name: "final", Series: 1, Groups: 1},
+		}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseStages(tt.spec)
+		if (err != nil) != tt.wantErr || !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseStages(%q) = %+v, %v, want %+v (error %v)", tt.spec, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestStageOf(t *testing.T) {
+	tr := &Tournament{Stages: []Stage{{Series: 2}, {Series: 1}}}
+	tests := []struct {
+		series int
+		stage  int
+		first  bool
+	}{
+		{1, 1, true},
+		{2, 1, false},
+		{3, 2, true},
+		{4, 0, false},
+	}
+	for _, tt := range tests {
+		if stage, first := tr.StageOf(tt.series); stage != tt.stage || first != tt.first {
+			t.Errorf("StageOf(%d) = %d, %v, want %d, %v", tt.series, stage, first, tt.stage, tt.first)
+		}
+	}
+}
+
+func TestStageStart(t *testing.T) {
+	stage := &Stage{Groups: 3}
+	stage.start(testPlayers(8))
+	// Snake order: 1 2 3 | 3 2 1 | 1 2
+	want := [][]string{{"p1", "p6", "p7"}, {"p2", "p5", "p8"}, {"p3", "p4"}}
+	if !reflect.DeepEqual(stage.Assigned, want) {
+		t.Errorf("start() = %v, want %v", stage.Assigned, want)
+	}
+}
+
+func TestStages(t *testing.T) {
+	players := testPlayers(12)
+	s := openTestStore(t, players...)
+	stages, err := ParseStages("qualification:1:2:3,final:1:1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetStages("cup", "dora", stages); err != nil {
+		t.Fatal(err)
+	}
+
+	// The qualification seats each group at its own tables
+	series, err := s.NextSeries("cup", "dora", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, _ := s.Get("cup")
+	groups := tr.Stages[0].Assigned
+	if series.Stage != 1 || len(groups) != 2 || len(groups[0]) != 6 || len(groups[1]) != 6 {
+		t.Fatalf("qualification = stage %d, groups %v", series.Stage, groups)
+	}
+	for _, table := range series.Tables {
+		for _, p := range table.Players {
+			if !slices.Contains(groups[table.Group-1], p) {
+				t.Errorf("table %d of group %d seats %s of another group", table.Number, table.Group, p)
+			}
+		}
+	}
+
+	// The best three of each group advance, the group winners first
+	var want []string
+	for place := 0; place < 3; place++ {
+		for _, group := range groups {
+			want = append(want, group[place])
+		}
+	}
+	for i, group := range groups {
+		for place, p := range group {
+			if err := s.Adjust("cup", "dora", 1, p, 100*(6-place)+i, "test"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tr, _ = s.Get("cup")
+	bracket, err := tr.Bracket(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bracket) != 2 || bracket[0].Started != 1 || !reflect.DeepEqual(bracket[0].Groups[0].Advancing, groups[0][:3]) || len(bracket[1].Groups) != 0 {
+		t.Errorf("Bracket() = %+v", bracket)
+	}
+
+	final, err := s.NextSeries("cup", "dora", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, _ = s.Get("cup")
+	if final.Stage != 2 || !reflect.DeepEqual(tr.Stages[1].Assigned, [][]string{want}) {
+		t.Errorf("final = stage %d, groups %v, want %v", final.Stage, tr.Stages[1].Assigned, want)
+	}
+	var seated []string
+	for _, table := range final.Tables {
+		seated = append(seated, table.Players...)
+	}
+	slices.Sort(seated)
+	slices.Sort(want)
+	if !reflect.DeepEqual(seated, want) {
+		t.Errorf("final seats %v, want %v", seated, want)
+	}
+
+	if done, err := s.NextSeries("cup", "dora", nil); err != nil || done != nil {
+		t.Errorf("NextSeries() after the final = %v, %v, want the end of the tournament", done, err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Team is a team of a team tournament.
+type Team struct {
+	Name string `json:"name"`
+	// Players are the registered players of the team
+	Players []string `json:"players"`
+}
+
+// TeamStanding is the result of a team.
+type TeamStanding struct {
+	// Rank is the rank by score (teams with equal score share a rank)
+	Rank int    `json:"rank"`
+	Team string `json:"team"`
+	// Series are the counted scores of the started series
+	Series []int `json:"series"`
+	// Score is the sum of the counted series scores
+	Score int `json:"score"`
+}
+
+// TeamOf returns the team of a player ("" if the player is in no team).
+func (t *Tournament) TeamOf(player string) string {
+	for _, team := range t.Teams {
+		if contains(team.Players, player) {
+			return team.Name
+		}
+	}
+	return ""
+}
+
+// TeamStandings returns the team standings from the individual standings, best first:
+// in every series a team scores the sum of the series scores of its best TeamBest
+// players (all players if 0). Without teams the result is empty.
+func (t *Tournament) TeamStandings(standings []Standing) []TeamStanding {
+	series := make(map[string][]int)
+	for _, st := range standings {
+		series[st.Player] = st.Series
+	}
+
+	teams := []TeamStanding{}
+	for _, team := range t.Teams {
+		ts := TeamStanding{Team: team.Name, Series: make([]int, len(t.Series))}
+		for i := range ts.Series {
+			var scores []int
+			for _, p := range team.Players {
+				if i < len(series[p]) {
+					scores = append(scores, series[p][i])
+				}
+			}
+			sort.Sort(sort.Reverse(sort.IntSlice(scores)))
+			if t.TeamBest > 0 && len(scores) > t.TeamBest {
+				scores = scores[:t.TeamBest]
+			}
+			for _, score := range scores {
+				ts.Series[i] += score
+			}
+			ts.Score += ts.Series[i]
+		}
+		teams = append(teams, ts)
+	}
+
+	sort.SliceStable(teams, func(i, j int) bool {
+		return teams[i].Score > teams[j].Score
+	})
+	for i := range teams {
+		teams[i].Rank = i + 1
+		if i > 0 && teams[i].Score == teams[i-1].Score {
+			teams[i].Rank = teams[i-1].Rank
+		}
+	}
+	return teams
+}
+
+// SetTeam sets the players of a team of a tournament in registration status (directors
+// only). The players must be registered and leave their previous teams; without players
+// the team is removed.
+func (s *Store) SetTeam(name, login, team string, players []string) error {
+	if !validName(team) {
+		return fmt.Errorf("invalid team name: %q", team)
+	}
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status != StatusRegistration {
+			return errors.New("registration is closed")
+		}
+		for i, p := range players {
+			if !t.registered(p) {
+				return fmt.Errorf("%s is not registered", p)
+			}
+			if contains(players[:i], p) {
+				return fmt.Errorf("duplicate player: %s", p)
+			}
+		}
+
+		teams := t.Teams[:0]
+		for _, other := range t.Teams {
+			if other.Name == team {
+				continue
+			}
+			other.Players = remove(other.Players, players...)
+			if len(other.Players) > 0 {
+				teams = append(teams, other)
+			}
+		}
+		if len(players) > 0 {
+			teams = append(teams, Team{Name: team, Players: append([]string(nil), players...)})
+		}
+		t.Teams = teams
+		return nil
+	})
+}
+
+// SetTeamBest sets the number of players counted per team and series of a tournament in
+// registration status (0 = all players; directors only).
+func (s *Store) SetTeamBest(name, login string, best int) error {
+	if best < 0 {
+		return fmt.Errorf("invalid number of counted players: %d", best)
+	}
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status != StatusRegistration {
+			return errors.New("registration is closed")
+		}
+		t.TeamBest = best
+		return nil
+	})
+}
+
+// removeFromTeams removes a player from the teams, dropping teams left empty.
+func (t *Tournament) removeFromTeams(player string) {
+	teams := t.Teams[:0]
+	for _, team := range t.Teams {
+		team.Players = remove(team.Players, player)
+		if len(team.Players) > 0 {
+			teams = append(teams, team)
+		}
+	}
+	t.Teams = teams
+}
+
+// remove returns the list without the names.
+func remove(list []string, names ...string) []string {
+	var kept []string
+	for _, s := range list {
+		if !contains(names, s) {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTeamStandings(t *testing.T) {
+	standings := []Standing{
+		{Player: "anna", Series: []int{300, 100}},
+		{Player: "ben", Series: []int{200, 200}},
+		{Player: "carl", Series: []int{-100, 400}},
+		{Player: "dora", Series: []int{250, 150}},
+		{Player: "emil", Series: []int{150, 50}},
+		{Player: "fritz", Series: []int{100, 200}},
+	}
+	teams := []Team{{Name: "red", Players: []string{"anna", "ben", "carl"}}, {Name: "blue", Players: []string{"dora", "emil", "fritz"}}}
+
+	tests := []struct {
+		name string
+		best int
+		want []TeamStanding
+	}{
+		{"all players", 0, []TeamStanding{
+			{Rank: 1, Team: "red", Series: []int{400, 700}, Score: 1100},
+			{Rank: 2, Team: "blue", Series: []int{500, 400}, Score: 900},
+		}},
+		{"best two", 2, []TeamStanding{
+			{Rank: 1, Team: "red", Series: []int{500, 600}, Score: 1100},
+			{Rank: 2, Team: "blue", Series: []int{400, 350}, Score: 750},
+		}},
+		{"best one", 1, []TeamStanding{
+			{Rank: 1, Team: "red", Series: []int{300, 400}, Score: 700},
+			{Rank: 2, Team: "blue", Series: []int{250, 200}, Score: 450},
+		}},
+		{"more than the team", 5, []TeamStanding{
+			{Rank: 1, Team: "red", Series: []int{400, 700}, Score: 1100},
+			{Rank: 2, Team: "blue", Series: []int{500, 400}, Score: 900},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &Tournament{Series: make([]Series, 2), Teams: teams, TeamBest: tt.best}
+			if got := tr.TeamStandings(standings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TeamStandings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	// Teams with equal score share the rank
+	tr := &Tournament{Series: make([]Series, 1), Teams: []Team{{Name: "a", Players: []string{"anna"}}, {Name: "b", Players: []string{"ben"}}}}
+	equal := tr.TeamStandings([]Standing{{Player: "anna", Series: []int{100}}, {Player: "ben", Series: []int{100}}})
+	if equal[0].Rank != 1 || equal[1].Rank != 1 {
+		t.Errorf("TeamStandings() with equal scores = %+v, want both ranked 1", equal)
+	}
+	if got := (&Tournament{}).TeamStandings(standings); len(got) != 0 {
+		t.Errorf("TeamStandings() without teams = %+v", got)
+	}
+}
+
+func TestSetTeam(t *testing.T) {
+	s := openTestStore(t, "anna", "ben", "carl")
+	if err := s.SetTeam("cup", "dora", "red", []string{"anna", "ben"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		login   string
+		team    string
+		players []string
+		want    []Team
+		wantErr bool
+	}{
+		{"not the director", "anna", "blue", []string{"carl"}, nil, true},
+		{"not registered", "dora", "blue", []string{"gerd"}, nil, true},
+		{"duplicate player", "dora", "blue", []string{"carl", "carl"}, nil, true},
+		{"invalid name", "dora", "blue team", []string{"carl"}, nil, true},
+		{"player changes the team", "dora", "blue", []string{"ben", "carl"}, []Team{
+			{Name: "red", Players: []string{"anna"}},
+			{Name: "blue", Players: []string{"ben", "carl"}},
+		}, false},
+		{"emptied team is dropped", "dora", "blue", []string{"anna", "ben", "carl"}, []Team{
+			{Name: "blue", Players: []string{"anna", "ben", "carl"}},
+		}, false},
+		{"removed without players", "dora", "blue", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.SetTeam("cup", tt.login, tt.team, tt.players)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetTeam() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			tr, _ := s.Get("cup")
+			if len(tr.Teams)+len(tt.want) > 0 && !reflect.DeepEqual(tr.Teams, tt.want) {
+				t.Errorf("Teams = %+v, want %+v", tr.Teams, tt.want)
+			}
+		})
+	}
+
+	if err := s.SetTeamBest("cup", "dora", -1); err == nil {
+		t.Error("SetTeamBest(-1): expected error")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TieBreak is a rule ranking players with equal Seeger-Fabian score.
+type TieBreak string
+
+const (
+	// TieBreakWon - more won declarer games
+	TieBreakWon TieBreak = "won"
+	// TieBreakLost - fewer lost declarer games
+	TieBreakLost TieBreak = "lost"
+	// TieBreakPoints - more game points (sum of the game scores)
+	TieBreakPoints TieBreak = "points"
+	// TieBreakOpponent - more games the declarers lost against the player
+	TieBreakOpponent TieBreak = "opponent"
+	// TieBreakLastSeries - a higher score in the last series, then the series before
+	TieBreakLastSeries TieBreak = "series"
+)
+
+// allTieBreaks are the known tie-breaks.
+var allTieBreaks = []TieBreak{TieBreakWon, TieBreakLost, TieBreakPoints, TieBreakOpponent, TieBreakLastSeries}
+
+// DefaultTieBreaks are the tie-breaks of new tournaments: more won games, then fewer
+// lost games, then more game points.
+var DefaultTieBreaks = []TieBreak{TieBreakWon, TieBreakLost, TieBreakPoints}
+
+// ParseTieBreaks parses comma-separated tie-breaks in order of their precedence,
+// e.g. "won,lost,points". Every tie-break may be given once.
+func ParseTieBreaks(names string) ([]TieBreak, error) {
+	var tieBreaks []TieBreak
+	seen := make(map[TieBreak]bool)
+	for _, name := range strings.Split(names, ",") {
+		tb := TieBreak(strings.ToLower(strings.TrimSpace(name)))
+		if !validTieBreak(tb) {
+			return nil, fmt.Errorf("invalid tie-break: %s (want %s)", name, joinTieBreaks(allTieBreaks))
+		}
+		if seen[tb] {
+			return nil, fmt.Errorf("duplicate tie-break: %s", tb)
+		}
+		seen[tb] = true
+		tieBreaks = append(tieBreaks, tb)
+	}
+	return tieBreaks, nil
+}
+
+// validTieBreak returns true for a known tie-break.
+func validTieBreak(tb TieBreak) bool {
+	for _, known := range allTieBreaks {
+		if tb == known {
+			return true
+		}
+	}
+	return false
+}
+
+// joinTieBreaks returns the tie-breaks separated by commas.
+func joinTieBreaks(tieBreaks []TieBreak) string {
+	names := make([]string, len(tieBreaks))
+	for i, tb := range tieBreaks {
+		names[i] = string(tb)
+	}
+	return strings.Join(names, ",")
+}
+
+// compare compares two standings by a tie-break: positive if a ranks before b,
+// negative if b ranks before a and 0 if the tie-break does not decide.
+func (tb TieBreak) compare(a, b *Standing) int {
+	switch tb {
+	case TieBreakWon:
+		return a.Won - b.Won
+	case TieBreakLost:
+		return b.Lost - a.Lost
+	case TieBreakPoints:
+		return a.Points - b.Points
+	case TieBreakOpponent:
+		return a.Opponent - b.Opponent
+	case TieBreakLastSeries:
+		for i := len(a.Series) - 1; i >= 0 && i < len(b.Series); i-- {
+			if a.Series[i] != b.Series[i] {
+				return a.Series[i] - b.Series[i]
+			}
+		}
+	}
+	return 0
+}
+
+// SortStandings sorts standings by score, best first, and sets the ranks. Disqualified
+// players are ranked after all others. Players with equal score are ranked by the
+// tie-breaks in order; the tie-break deciding against the player ranked directly above
+// is set as TieBreak. Players equal in all tie-breaks share a rank.
+func SortStandings(standings []Standing, tieBreaks ...TieBreak) {
+	// decide returns the first tie-break deciding between a and b and its comparison
+	decide := func(a, b *Standing) (TieBreak, int) {
+		for _, tb := range tieBreaks {
+			if c := tb.compare(a, b); c != 0 {
+				return tb, c
+			}
+		}
+		return "", 0
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Disqualified != standings[j].Disqualified {
+			return standings[j].Disqualified
+		}
+		if standings[i].Score != standings[j].Score {
+			return standings[i].Score > standings[j].Score
+		}
+		_, c := decide(&standings[i], &standings[j])
+		return c > 0
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		standings[i].TieBreak = ""
+		if i == 0 || standings[i].Score != standings[i-1].Score || standings[i].Disqualified != standings[i-1].Disqualified {
+			continue
+		}
+		tb, c := decide(&standings[i-1], &standings[i])
+		if c == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].TieBreak = tb
+		}
+	}
+}
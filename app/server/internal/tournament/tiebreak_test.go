@@ -0,0 +1,80 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseTieBreaks(t *testing.T) {
+	tests := []struct {
+		names   string
+		want    []TieBreak
+		wantErr bool
+	}{
+		{"won,lost,points", DefaultTieBreaks, false},
+		{" Series , opponent", []TieBreak{TieBreakLastSeries, TieBreakOpponent}, false},
+		{"won,won", nil, true},
+		{"luck", nil, true},
+		{"", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTieBreaks(tt.names)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTieBreaks(%q) = %v, %v, want %v (error %v)", tt.names, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSortStandings(t *testing.T) {
+	// anna and ben have equal score; anna won more games, ben has more points, more
+	// opponent games and the better last series
+	standings := func() []Standing {
+		return []Standing{
+			{Player: "carl", Seeger: Seeger{Won: 9, Score: 900}, Disqualified: true},
+			{Player: "ben", Seeger: Seeger{Won: 2, Lost: 1, Opponent: 3, Points: 200, Score: 500}, Series: []int{400, 100}},
+			{Player: "dora", Seeger: Seeger{Won: 1, Score: 100}},
+			{Player: "anna", Seeger: Seeger{Won: 3, Lost: 1, Opponent: 1, Points: 150, Score: 500}, Series: []int{100, 400}},
+			{Player: "emil", Seeger: Seeger{Won: 1, Score: 100}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		tieBreaks []TieBreak
+		want      []string // player:rank:tie-break
+	}{
+		{"won", []TieBreak{TieBreakWon}, []string{"anna:1:", "ben:2:won", "dora:3:", "emil:3:", "carl:5:"}},
+		{"lost before points", []TieBreak{TieBreakLost, TieBreakPoints}, []string{"ben:1:", "anna:2:points", "dora:3:", "emil:3:", "carl:5:"}},
+		{"opponent", []TieBreak{TieBreakOpponent}, []string{"ben:1:", "anna:2:opponent", "dora:3:", "emil:3:", "carl:5:"}},
+		{"last series", []TieBreak{TieBreakLastSeries}, []string{"anna:1:", "ben:2:series", "dora:3:", "emil:3:", "carl:5:"}},
+		{"none", nil, []string{"ben:1:", "anna:1:", "dora:3:", "emil:3:", "carl:5:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := standings()
+			SortStandings(got, tt.tieBreaks...)
+			var ranks []string
+			for _, s := range got {
+				ranks = append(ranks, fmt.Sprintf("%s:%d:%s", s.Player, s.Rank, s.TieBreak))
+			}
+			if !reflect.DeepEqual(ranks, tt.want) {
+				t.Errorf("SortStandings() = %v, want %v", ranks, tt.want)
+			}
+		})
+	}
+}
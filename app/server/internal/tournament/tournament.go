@@ -0,0 +1,874 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tournament manages Skat tournaments for clubs: registered players are seated
+// at tables of three or four for every series (Liste) and ranked by Seeger-Fabian scoring.
+//
+// The games of a tournament are archived games whose IDs follow the scheme
+// "<name>-s<series>-t<table>-g<deal>", so the standings are always computed from the archive.
+package tournament
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/jsonfile"
+	"github.com/mkloubert/freeskat-server/pkg/scoresheet"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// DealsPerSeat is the number of deals every player of a table deals in a series:
+// a series has 36 deals at tables of three and 48 deals at tables of four.
+const DealsPerSeat = 12
+
+// DefaultSeries is the number of series of a tournament if none is given.
+const DefaultSeries = 2
+
+// Seeger-Fabian points besides the game score.
+const (
+	// WonBonus is added for every game won by the declarer
+	WonBonus = 50
+	// LostPenalty is subtracted for every game lost by the declarer
+	LostPenalty = 50
+	// OpponentBonus3 is added for every game the declarer lost to each other player of a table of three
+	OpponentBonus3 = 40
+	// OpponentBonus4 is added for every game the declarer lost to each other player of a table of four
+	OpponentBonus4 = 30
+)
+
+// fileExt is the extension of the tournament files.
+const fileExt = ".json"
+
+var (
+	// ErrNotFound is returned for unknown tournaments.
+	ErrNotFound = errors.New("tournament not found")
+	// ErrExists is returned when creating a tournament whose name is taken.
+	ErrExists = errors.New("tournament already exists")
+	// ErrNotDirector is returned when someone else than the directors manages a tournament.
+	ErrNotDirector = errors.New("only the tournament directors can do this")
+)
+
+// Status is the status of a tournament.
+type Status string
+
+const (
+	// StatusRegistration - players can register
+	StatusRegistration Status = "registration"
+	// StatusRunning - the series are played
+	StatusRunning Status = "running"
+	// StatusFinished - all series have been played
+	StatusFinished Status = "finished"
+)
+
+// Tournament is a tournament: its players and the table assignments of the started series.
+type Tournament struct {
+	Name string `json:"name"`
+	// Director is the login who created the tournament and manages it
+	Director  string    `json:"director"`
+	CreatedAt time.Time `json:"createdAt"`
+	Status    Status    `json:"status"`
+	// SeriesCount is the number of series to play
+	SeriesCount int `json:"seriesCount"`
+	// Players are the registered players in order of registration
+	Players []string `json:"players"`
+	// Series are the started series
+	Series []Series `json:"series"`
+	// Bock are the rules scheduling Bock and Ramsch rounds at the tables
+	Bock Rules `json:"bock"`
+	// Profile is the rule profile every table must play by (nil = none)
+	Profile *Profile `json:"profile,omitempty"`
+	// Stakes make the tables money games: the series scores of each table are settled
+	Stakes scoresheet.Stakes `json:"stakes"`
+	// TieBreaks rank players with equal score, in order of their precedence
+	TieBreaks []TieBreak `json:"tieBreaks"`
+	// Pairing is the way the players of a series are seated (empty = Swiss)
+	Pairing Pairing `json:"pairing,omitempty"`
+	// Teams are the teams of a team tournament
+	Teams []Team `json:"teams,omitempty"`
+	// TeamBest is the number of players counted per team and series (0 = all)
+	TeamBest int `json:"teamBest,omitempty"`
+	// Stages are the stages of a multi-stage tournament
+	Stages []Stage `json:"stages,omitempty"`
+	// Capacity is the maximum number of registered and pending players (0 = unlimited)
+	Capacity int `json:"capacity,omitempty"`
+	// Fee is the seat fee in cents; registrations are pending until it is paid (0 = free)
+	Fee int `json:"fee,omitempty"`
+	// Pending are the players whose registration awaits the payment of the fee
+	Pending []string `json:"pending,omitempty"`
+	// Waitlist are the players waiting for a free seat, in order of their registration
+	Waitlist []string `json:"waitlist,omitempty"`
+	// Assistants are the designated assistant directors
+	Assistants []string `json:"assistants,omitempty"`
+	// Adjustments are the score corrections of the directors
+	Adjustments []Adjustment `json:"adjustments,omitempty"`
+	// Disqualified are the disqualified players
+	Disqualified []Disqualification `json:"disqualified,omitempty"`
+	// Audit is the audit trail of the director actions
+	Audit []AuditEntry `json:"audit,omitempty"`
+}
+
+// Series is a series (Liste) with its table assignment.
+type Series struct {
+	// Number is the series number (starting at 1)
+	Number int `json:"number"`
+	// Stage is the stage of the series in multi-stage tournaments (starting at 1)
+	Stage  int     `json:"stage,omitempty"`
+	Tables []Table `json:"tables"`
+}
+
+// Table is a table of a series.
+type Table struct {
+	// Number is the table number (starting at 1)
+	Number int `json:"number"`
+	// Players are the three or four players in seating order; the first player deals first
+	Players []string `json:"players"`
+	// Deals is the number of deals of the table in the series
+	Deals int `json:"deals"`
+	// Paused is true while a director has paused the table
+	Paused bool `json:"paused,omitempty"`
+	// Extension is the thinking time in seconds the directors added for the players
+	Extension int `json:"extension,omitempty"`
+	// Substitutes are the substitutes seated by the directors, in order of their first deal
+	Substitutes []Substitute `json:"substitutes,omitempty"`
+	// Group is the group of the table in multi-stage tournaments (starting at 1)
+	Group int `json:"group,omitempty"`
+}
+
+// GameID returns the archive ID of a deal at a table of a series (all starting at 1).
+func (t *Tournament) GameID(series, table, deal int) string {
+	return fmt.Sprintf("%s-s%d-t%d-g%d", t.Name, series, table, deal)
+}
+
+// GamePrefix returns the prefix of the archive IDs of the tournament's games.
+func (t *Tournament) GamePrefix() string {
+	return t.Name + "-s"
+}
+
+// Table returns a table of a started series.
+func (t *Tournament) Table(series, table int) (*Table, bool) {
+	if series < 1 || series > len(t.Series) {
+		return nil, false
+	}
+	tables := t.Series[series-1].Tables
+	if table < 1 || table > len(tables) {
+		return nil, false
+	}
+	return &tables[table-1], true
+}
+
+// parseGameID returns series, table and deal of a game ID of the tournament.
+func (t *Tournament) parseGameID(id string) (int, int, int, bool) {
+	if !strings.HasPrefix(id, t.GamePrefix()) {
+		return 0, 0, 0, false
+	}
+	var series, table, deal int
+	if _, err := fmt.Sscanf(id[len(t.Name):], "-s%d-t%d-g%d", &series, &table, &deal); err != nil {
+		return 0, 0, 0, false
+	}
+	// Reject IDs with trailing characters, e.g. of another tournament "<name>-s1-t1-g1x"
+	if t.GameID(series, table, deal) != id {
+		return 0, 0, 0, false
+	}
+	return series, table, deal, true
+}
+
+// Seats returns the player names by position of a deal at a table. The deal passes
+// clockwise; at tables of four the dealer sits the deal out. Substitutes take the
+// seats of the players they replace.
+func (tb *Table) Seats(deal int) map[skat.Player]string {
+	players := tb.PlayersAt(deal)
+	n := len(players)
+	dealer := (deal - 1) % n
+	seats := make(map[skat.Player]string)
+	for i, p := range skat.AllPlayers {
+		seats[p] = players[(dealer+1+i)%n]
+	}
+	return seats
+}
+
+// seated returns true if the players sit at the positions of the deal.
+func (tb *Table) seated(deal int, players map[skat.Player]string) bool {
+	for p, name := range tb.Seats(deal) {
+		if players[p] != name {
+			return false
+		}
+	}
+	return true
+}
+
+// Assign seats the players at tables of three and four: as many tables of three as
+// possible, the remaining players at tables of four first. Five players cannot be seated.
+func Assign(players []string) ([]Table, error) {
+	sizes, err := tableSizes(len(players))
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([][]string, len(sizes))
+	i := 0
+	for j, size := range sizes {
+		groups[j] = players[i : i+size]
+		i += size
+	}
+	return seat(groups), nil
+}
+
+// tableSizes returns the table sizes for n players: as many tables of three as
+// possible, the tables of four first.
+func tableSizes(n int) ([]int, error) {
+	fours := n % 3
+	if n < 3 || n < 4*fours {
+		return nil, fmt.Errorf("%d players cannot be seated at tables of three and four", n)
+	}
+
+	var sizes []int
+	for seated := 0; seated < n; {
+		size := 3
+		if len(sizes) < fours {
+			size = 4
+		}
+		sizes = append(sizes, size)
+		seated += size
+	}
+	return sizes, nil
+}
+
+// seat returns the numbered tables of the groups of players.
+func seat(groups [][]string) []Table {
+	tables := make([]Table, len(groups))
+	for i, players := range groups {
+		tables[i] = Table{
+			Number:  i + 1,
+			Players: append([]string(nil), players...),
+			Deals:   DealsPerSeat * len(players),
+		}
+	}
+	return tables
+}
+
+// Seeger is a Seeger-Fabian result of a player.
+type Seeger struct {
+	// Won and Lost are the player's won and lost declarer games
+	Won  int `json:"won"`
+	Lost int `json:"lost"`
+	// Opponent is the number of games the declarers lost against the player
+	Opponent int `json:"opponent"`
+	// Points is the sum of the player's game scores
+	Points int `json:"points"`
+	// Score is the Seeger-Fabian total
+	Score int `json:"score"`
+}
+
+// Add adds another result.
+func (s *Seeger) Add(other Seeger) {
+	s.Won += other.Won
+	s.Lost += other.Lost
+	s.Opponent += other.Opponent
+	s.Points += other.Points
+	s.Score += other.Score
+}
+
+// Evaluate returns the Bock and Ramsch schedule of the table and the Seeger-Fabian
+// results of the table's players from the games by deal (starting at 1). Games whose
+// players do not sit as assigned and unfinished games are ignored.
+func (tb *Table) Evaluate(rules Rules, records map[int]*skat.GameRecord) (*Schedule, map[string]Seeger, error) {
+	games := make(map[int]*skat.Game)
+	for deal, record := range records {
+		if deal < 1 || deal > tb.Deals || !tb.seated(deal, record.Players) {
+			continue
+		}
+		game, err := record.Replay(nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("game %s: %w", record.ID, err)
+		}
+		if game.State == skat.StateGameOver {
+			games[deal] = game
+		}
+	}
+
+	schedule := tb.schedule(rules, games)
+	points := make(map[string]Seeger)
+	for deal, game := range games {
+		for name, p := range tb.gamePoints(deal, game, records[deal].Players, schedule.Mode(deal)) {
+			sum := points[name]
+			sum.Add(p)
+			points[name] = sum
+		}
+	}
+	return schedule, points, nil
+}
+
+// gamePoints returns the Seeger-Fabian results of the finished game of a deal in a mode.
+// The declarer scores the game score (doubled in Bock deals) plus 50 if won or minus 50
+// if lost; every other player of the table scores 40 at tables of three and 30 at tables
+// of four for each lost game. In Ramsch deals only the Ramsch losers score the Ramsch
+// score, a Durchmarsch scores its player. Passed in games (Ramsch) in other deals and declarer games in Ramsch deals
+// do not count.
+func (tb *Table) gamePoints(deal int, game *skat.Game, players map[skat.Player]string, mode Mode) map[string]Seeger {
+	points := make(map[string]Seeger)
+	if mode == ModeRamsch {
+		if r := game.RamschResult; r != nil {
+			for _, p := range skat.AllPlayers {
+				if score := r.Score(p); score != 0 {
+					points[players[p]] = Seeger{Points: score, Score: score}
+				}
+			}
+		}
+		return points
+	}
+	result := game.Result
+	if result == nil {
+		return points
+	}
+
+	score := result.Score
+	if mode == ModeBock {
+		score *= 2
+	}
+	declarer := players[result.Declarer]
+	if result.DeclarerWon {
+		points[declarer] = Seeger{Won: 1, Points: score, Score: score + WonBonus}
+		return points
+	}
+	points[declarer] = Seeger{Lost: 1, Points: score, Score: score - LostPenalty}
+	bonus := OpponentBonus3
+	if len(tb.Players) == 4 {
+		bonus = OpponentBonus4
+	}
+	for _, name := range tb.PlayersAt(deal) {
+		if name != declarer {
+			points[name] = Seeger{Opponent: 1, Score: bonus}
+		}
+	}
+	return points
+}
+
+// Standing is the Seeger-Fabian result of a player in the standings.
+type Standing struct {
+	// Rank is the rank by score (players with equal score share a rank)
+	Rank   int    `json:"rank"`
+	Player string `json:"player"`
+	Seeger
+	// Series are the Seeger-Fabian totals of the started series
+	Series []int `json:"series"`
+	// TieBreak is the tie-break that ranks the player below the player with equal score
+	// directly above ("" if the scores differ or the players share the rank)
+	TieBreak TieBreak `json:"tieBreak,omitempty"`
+	// Adjusted is the sum of the director's score corrections (included in Score)
+	Adjusted int `json:"adjusted,omitempty"`
+	// Disqualified is true for disqualified players, who are ranked last
+	Disqualified bool `json:"disqualified,omitempty"`
+}
+
+// Standings returns the Seeger-Fabian standings of all players from the tournament's
+// finished games and the director's adjustments, best first; equal scores are ranked
+// by the tournament's tie-breaks and disqualified players last.
+// Games of other tournaments and games that do not count
+// (see Table.Evaluate) are ignored.
+func (t *Tournament) Standings(records []*skat.GameRecord) ([]Standing, error) {
+	return t.standings(t.Players, func(int) bool { return true }, records)
+}
+
+// standings returns the standings of the players from the series selected by include.
+func (t *Tournament) standings(players []string, include func(series int) bool, records []*skat.GameRecord) ([]Standing, error) {
+	standings := make([]Standing, len(players))
+	index := make(map[string]int)
+	for i, name := range players {
+		standings[i] = Standing{Player: name, Series: make([]int, len(t.Series))}
+		index[name] = i
+	}
+	byTable := t.tableRecords(records)
+	for _, series := range t.Series {
+		if !include(series.Number) {
+			continue
+		}
+		for _, table := range series.Tables {
+			_, points, err := table.Evaluate(t.Rules(), byTable[[2]int{series.Number, table.Number}])
+			if err != nil {
+				return nil, err
+			}
+			for name, p := range points {
+				if i, ok := index[name]; ok {
+					standings[i].Add(p)
+					standings[i].Series[series.Number-1] += p.Score
+				}
+			}
+		}
+	}
+	t.applyDirector(standings, include)
+	SortStandings(standings, t.TieBreaks...)
+	return standings, nil
+}
+
+// Schedules returns the Bock and Ramsch schedules of the tables of a started series
+// from the tournament's games.
+func (t *Tournament) Schedules(series int, records []*skat.GameRecord) ([]*Schedule, error) {
+	if series < 1 || series > len(t.Series) {
+		return nil, fmt.Errorf("series %d not started", series)
+	}
+	byTable := t.tableRecords(records)
+	var schedules []*Schedule
+	for _, table := range t.Series[series-1].Tables {
+		schedule, _, err := table.Evaluate(t.Rules(), byTable[[2]int{series, table.Number}])
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// TableResult is the result of a table in a series: the Seeger-Fabian series scores
+// of its players, settled with the tournament's stakes.
+type TableResult struct {
+	Table      int                    `json:"table"`
+	Settlement *scoresheet.Settlement `json:"settlement"`
+}
+
+// Results returns the results of the tables of a started series from the tournament's games.
+func (t *Tournament) Results(series int, records []*skat.GameRecord) ([]TableResult, error) {
+	if series < 1 || series > len(t.Series) {
+		return nil, fmt.Errorf("series %d not started", series)
+	}
+	byTable := t.tableRecords(records)
+	var results []TableResult
+	for _, table := range t.Series[series-1].Tables {
+		_, points, err := table.Evaluate(t.Rules(), byTable[[2]int{series, table.Number}])
+		if err != nil {
+			return nil, err
+		}
+		scores := make(map[string]int)
+		for _, name := range table.seatedPlayers() {
+			scores[name] = points[name].Score
+		}
+		results = append(results, TableResult{Table: table.Number, Settlement: scoresheet.Settle(scores, t.Stakes)})
+	}
+	return results, nil
+}
+
+// ResultList returns the result list of all started series from the tournament's games:
+// one row per player and series, ordered by series, table and seat, with the director's
+// adjustments of the series.
+func (t *Tournament) ResultList(records []*skat.GameRecord) ([]scoresheet.ListRow, error) {
+	adjustments := make(map[int]map[string]int)
+	for _, adj := range t.Adjustments {
+		if adjustments[adj.Series] == nil {
+			adjustments[adj.Series] = make(map[string]int)
+		}
+		adjustments[adj.Series][adj.Player] += adj.Score
+	}
+
+	byTable := t.tableRecords(records)
+	var rows []scoresheet.ListRow
+	for _, series := range t.Series {
+		for _, table := range series.Tables {
+			_, points, err := table.Evaluate(t.Rules(), byTable[[2]int{series.Number, table.Number}])
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range table.seatedPlayers() {
+				p := points[name]
+				adjustment := adjustments[series.Number][name]
+				rows = append(rows, scoresheet.ListRow{
+					List: series.Number, Table: table.Number, Player: name,
+					Points: p.Points, Won: p.Won, Lost: p.Lost, Opponent: p.Opponent,
+					Adjustment: adjustment, Score: p.Score + adjustment,
+				})
+			}
+		}
+	}
+	return rows, nil
+}
+
+// Sheet returns the score sheet of a table of a started series: its games in order
+// of the deals, with the scores of the Bock deals doubled.
+func (t *Tournament) Sheet(series, table int, records []*skat.GameRecord) (*scoresheet.Sheet, error) {
+	tb, ok := t.Table(series, table)
+	if !ok {
+		return nil, fmt.Errorf("table %d of series %d not found", table, series)
+	}
+	byDeal := t.tableRecords(records)[[2]int{series, table}]
+	schedule, _, err := tb.Evaluate(t.Rules(), byDeal)
+	if err != nil {
+		return nil, err
+	}
+
+	var played []*skat.GameRecord
+	bock := make(map[string]bool)
+	for deal := 1; deal <= tb.Deals; deal++ {
+		record := byDeal[deal]
+		if record == nil || !tb.seated(deal, record.Players) {
+			continue
+		}
+		played = append(played, record)
+		if schedule.Mode(deal) == ModeBock {
+			bock[record.ID] = true
+		}
+	}
+	sheet, err := scoresheet.New(played)
+	if err != nil {
+		return nil, err
+	}
+	sheet.ApplyBock(bock)
+	return sheet, nil
+}
+
+// tableRecords groups the tournament's records by series and table, and by deal.
+func (t *Tournament) tableRecords(records []*skat.GameRecord) map[[2]int]map[int]*skat.GameRecord {
+	byTable := make(map[[2]int]map[int]*skat.GameRecord)
+	for _, record := range records {
+		series, table, deal, ok := t.parseGameID(record.ID)
+		if !ok {
+			continue
+		}
+		key := [2]int{series, table}
+		if byTable[key] == nil {
+			byTable[key] = make(map[int]*skat.GameRecord)
+		}
+		byTable[key][deal] = record
+	}
+	return byTable
+}
+
+// Store keeps the tournaments, one JSON file per tournament in a directory.
+// It is safe for concurrent use.
+type Store struct {
+	dir         string
+	tournaments map[string]*Tournament
+	// bock are the Bock and Ramsch rules of new tournaments
+	bock Rules
+	// onRegistration is called with every registration change (nil = none)
+	onRegistration func(name, player string, status Registration)
+	// onSeries is called with every started series (nil = none)
+	onSeries func(name string, series Series)
+	mu       sync.Mutex
+}
+
+// Open opens the store in dir, creating the directory if needed, and loads all tournaments.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{dir: dir, tournaments: make(map[string]*Tournament)}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		t := &Tournament{}
+		if err := json.Unmarshal(data, t); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		s.tournaments[t.Name] = t
+	}
+	return s, nil
+}
+
+// SetBock sets the Bock and Ramsch rules of tournaments created from now on.
+// Existing tournaments keep their rules.
+func (s *Store) SetBock(rules Rules) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bock = rules.Copy()
+}
+
+// Create creates a tournament in registration status.
+func (s *Store) Create(name, director string, series int) (*Tournament, error) {
+	if !validName(name) {
+		return nil, fmt.Errorf("invalid tournament name: %q", name)
+	}
+	if series < 1 {
+		return nil, fmt.Errorf("invalid number of series: %d", series)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tournaments[name] != nil {
+		return nil, ErrExists
+	}
+	t := &Tournament{
+		Name:        name,
+		Director:    director,
+		CreatedAt:   time.Now(),
+		Status:      StatusRegistration,
+		SeriesCount: series,
+		Players:     []string{},
+		Series:      []Series{},
+		Bock:        s.bock.Copy(),
+		TieBreaks:   append([]TieBreak(nil), DefaultTieBreaks...),
+		Pairing:     PairingAuto,
+	}
+	if err := s.write(t); err != nil {
+		return nil, err
+	}
+	s.tournaments[name] = t
+	return copyTournament(t), nil
+}
+
+// Get returns a copy of a tournament.
+func (s *Store) Get(name string) (*Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tournaments[name]
+	if t == nil {
+		return nil, ErrNotFound
+	}
+	return copyTournament(t), nil
+}
+
+// ForGame returns a copy of the tournament an archived game belongs to.
+func (s *Store) ForGame(id string) (*Tournament, bool) {
+	name, _, ok := strings.Cut(id, "-")
+	if !ok {
+		return nil, false
+	}
+	t, err := s.Get(name)
+	if err != nil {
+		return nil, false
+	}
+	if _, _, _, ok := t.parseGameID(id); !ok {
+		return nil, false
+	}
+	return t, true
+}
+
+// List returns copies of all tournaments, newest first.
+func (s *Store) List() []*Tournament {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]*Tournament, 0, len(s.tournaments))
+	for _, t := range s.tournaments {
+		list = append(list, copyTournament(t))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.After(list[j].CreatedAt)
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// SetStakes sets the stakes of a tournament in registration status (directors only).
+func (s *Store) SetStakes(name, login string, stakes scoresheet.Stakes) error {
+	if stakes.Cents < 0 {
+		return fmt.Errorf("invalid stakes: %d", stakes.Cents)
+	}
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status != StatusRegistration {
+			return errors.New("the tournament has started")
+		}
+		t.Stakes = stakes
+		return nil
+	})
+}
+
+// SetTieBreaks sets the tie-breaks of a tournament that is not finished (directors only).
+func (s *Store) SetTieBreaks(name, login string, tieBreaks []TieBreak) error {
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status == StatusFinished {
+			return errors.New("tournament is finished")
+		}
+		t.TieBreaks = append([]TieBreak(nil), tieBreaks...)
+		return nil
+	})
+}
+
+// SetPairing sets the pairing of the next series of a tournament that is not finished
+// (directors only).
+func (s *Store) SetPairing(name, login string, pairing Pairing) error {
+	return s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status == StatusFinished {
+			return errors.New("tournament is finished")
+		}
+		t.Pairing = pairing
+		return nil
+	})
+}
+
+// NextSeries starts the next series of a tournament, which closes the registration
+// with the first series. The first series seats the players in random order, every
+// further series by the pairing of the tournament: Swiss by the standings from the
+// tournament's games, so players with similar scores meet, or round-robin. Both avoid
+// repeated tables where possible. Disqualified players are not seated. Multi-stage
+// tournaments seat the groups of the stage separately (see Stage). After the last
+// series, the tournament is finished and nil is returned.
+func (s *Store) NextSeries(name, login string, records []*skat.GameRecord) (*Series, error) {
+	var started *Series
+	err := s.update(name, func(t *Tournament) error {
+		if !t.IsDirector(login) {
+			return ErrNotDirector
+		}
+		if t.Status == StatusFinished {
+			return errors.New("tournament is finished")
+		}
+		if len(t.Series) == t.SeriesCount {
+			t.Status = StatusFinished
+			return nil
+		}
+
+		shuffle := func(players []string) {
+			rand.Shuffle(len(players), func(i, j int) {
+				players[i], players[j] = players[j], players[i]
+			})
+		}
+		series := Series{Number: len(t.Series) + 1}
+		if len(t.Stages) > 0 {
+			stage, tables, err := t.stageTables(records, shuffle)
+			if err != nil {
+				return err
+			}
+			series.Stage, series.Tables = stage, tables
+		} else {
+			var standings []Standing
+			if len(t.Series) > 0 {
+				var err error
+				if standings, err = t.Standings(records); err != nil {
+					return err
+				}
+			}
+			tables, err := t.pair(t.seatable(standings, shuffle), shuffle)
+			if err != nil {
+				return err
+			}
+			series.Tables = tables
+		}
+
+		t.Series = append(t.Series, series)
+		t.Status = StatusRunning
+		started = &t.Series[len(t.Series)-1]
+		return nil
+	})
+	if err != nil || started == nil {
+		return nil, err
+	}
+	series := *started
+	if s.onSeries != nil {
+		s.onSeries(name, series)
+	}
+	return &series, nil
+}
+
+// OnSeries sets a function called with every started series, e.g. to announce the
+// start of a tournament. It must be set before the store is used.
+func (s *Store) OnSeries(fn func(name string, series Series)) {
+	s.onSeries = fn
+}
+
+// update applies fn to a tournament and writes it. Nothing is changed if fn fails.
+func (s *Store) update(name string, fn func(t *Tournament) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.tournaments[name]
+	if current == nil {
+		return ErrNotFound
+	}
+	t := copyTournament(current)
+	if err := fn(t); err != nil {
+		return err
+	}
+	if err := s.write(t); err != nil {
+		return err
+	}
+	s.tournaments[name] = t
+	return nil
+}
+
+// write writes a tournament file. The caller must hold the lock.
+func (s *Store) write(t *Tournament) error {
+	return jsonfile.Write(filepath.Join(s.dir, t.Name+fileExt), t)
+}
+
+// copyTournament returns a deep copy of a tournament.
+func copyTournament(t *Tournament) *Tournament {
+	c := *t
+	c.Players = append([]string{}, t.Players...)
+	c.Pending = append([]string(nil), t.Pending...)
+	c.Waitlist = append([]string(nil), t.Waitlist...)
+	c.Bock = t.Bock.Copy()
+	if t.Profile != nil {
+		profile := *t.Profile
+		profile.BockTriggers = append([]Trigger(nil), t.Profile.BockTriggers...)
+		c.Profile = &profile
+	}
+	c.TieBreaks = append([]TieBreak(nil), t.TieBreaks...)
+	c.Assistants = append([]string(nil), t.Assistants...)
+	c.Adjustments = append([]Adjustment(nil), t.Adjustments...)
+	c.Disqualified = append([]Disqualification(nil), t.Disqualified...)
+	c.Audit = append([]AuditEntry(nil), t.Audit...)
+	c.Stages = nil
+	for _, stage := range t.Stages {
+		assigned := stage.Assigned
+		stage.Assigned = nil
+		for _, players := range assigned {
+			stage.Assigned = append(stage.Assigned, append([]string(nil), players...))
+		}
+		c.Stages = append(c.Stages, stage)
+	}
+	c.Teams = make([]Team, len(t.Teams))
+	for i, team := range t.Teams {
+		c.Teams[i] = Team{Name: team.Name, Players: append([]string(nil), team.Players...)}
+	}
+	c.Series = make([]Series, len(t.Series))
+	for i, series := range t.Series {
+		c.Series[i] = Series{Number: series.Number, Stage: series.Stage, Tables: make([]Table, len(series.Tables))}
+		for j, table := range series.Tables {
+			table.Players = append([]string(nil), table.Players...)
+			table.Substitutes = append([]Substitute(nil), table.Substitutes...)
+			c.Series[i].Tables[j] = table
+		}
+	}
+	return &c
+}
+
+// validName returns true if the name can be used as file name and game ID prefix
+// (letters, digits and "_"; "-" separates the parts of the game IDs).
+func validName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tournament
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// openTestStore opens a store in a temporary directory with the tournament "cup" of
+// director dora and the registered players.
+func openTestStore(t *testing.T, players ...string) *Store {
+	t.Helper()
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create("cup", "dora", 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range players {
+		if _, err := s.Register("cup", p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+// declared returns a finished declarer game of a value won or lost by the declarer.
+func declared(declarer skat.Player, value int, won bool) *skat.Game {
+	score := value
+	if !won {
+		score = -2 * value
+	}
+	return &skat.Game{Result: &skat.GameResult{Declarer: declarer, DeclarerWon: won, GameValue: value, Score: score}}
+}
+
+func TestGamePoints(t *testing.T) {
+	three := &Table{Number: 1, Players: []string{"anna", "ben", "carl"}, Deals: 36}
+	four := &Table{Number: 1, Players: []string{"anna", "ben", "carl", "dora"}, Deals: 48}
+	ramsch := &skat.Game{RamschResult: &skat.RamschResult{Losers: []skat.Player{skat.Middlehand}, LoserScore: -61}}
+	rearhand := skat.Rearhand
+	durchmarsch := &skat.Game{RamschResult: &skat.RamschResult{Durchmarsch: true, DurchmarschPlayer: &rearhand, DurchmarschScore: 120}}
+
+	tests := []struct {
+		name  string
+		table *Table
+		deal  int
+		game  *skat.Game
+		mode  Mode
+		want  map[string]Seeger
+	}{
+		{"won", three, 1, declared(skat.Forehand, 48, true), ModeNormal, map[string]Seeger{
+			"ben": {Won: 1, Points: 48, Score: 98},
+		}},
+		{"lost at a table of three", three, 1, declared(skat.Forehand, 48, false), ModeNormal, map[string]Seeger{
+			"ben":  {Lost: 1, Points: -96, Score: -146},
+			"carl": {Opponent: 1, Score: 40},
+			"anna": {Opponent: 1, Score: 40},
+		}},
+		{"lost at a table of four", four, 1, declared(skat.Middlehand, 24, false), ModeNormal, map[string]Seeger{
+			"carl": {Lost: 1, Points: -48, Score: -98},
+			"ben":  {Opponent: 1, Score: 30},
+			"dora": {Opponent: 1, Score: 30},
+			// The dealer sits the deal out, but scores for the lost game too
+			"anna": {Opponent: 1, Score: 30},
+		}},
+		{"won in a Bock deal", three, 1, declared(skat.Forehand, 23, true), ModeBock, map[string]Seeger{
+			"ben": {Won: 1, Points: 46, Score: 96},
+		}},
+		{"lost in a Bock deal", three, 2, declared(skat.Forehand, 23, false), ModeBock, map[string]Seeger{
+			"carl": {Lost: 1, Points: -92, Score: -142},
+			"anna": {Opponent: 1, Score: 40},
+			"ben":  {Opponent: 1, Score: 40},
+		}},
+		{"Ramsch", three, 1, ramsch, ModeRamsch, map[string]Seeger{
+			"carl": {Points: -61, Score: -61},
+		}},
+		{"Durchmarsch", three, 1, durchmarsch, ModeRamsch, map[string]Seeger{
+			"anna": {Points: 120, Score: 120},
+		}},
+		{"passed in", three, 1, &skat.Game{}, ModeNormal, map[string]Seeger{}},
+		{"Ramsch in a normal deal", three, 1, ramsch, ModeNormal, map[string]Seeger{}},
+		{"declarer game in a Ramsch deal", three, 1, declared(skat.Forehand, 48, true), ModeRamsch, map[string]Seeger{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players := tt.table.Seats(tt.deal)
+			if got := tt.table.gamePoints(tt.deal, tt.game, players, tt.mode); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("gamePoints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssign(t *testing.T) {
+	tests := []struct {
+		players int
+		want    []int // table sizes, nil if the players cannot be seated
+	}{
+		{2, nil},
+		{3, []int{3}},
+		{4, []int{4}},
+		{5, nil},
+		{6, []int{3, 3}},
+		{7, []int{4, 3}},
+		{8, []int{4, 4}},
+		{11, []int{4, 4, 3}},
+	}
+	for _, tt := range tests {
+		players := make([]string, tt.players)
+		for i := range players {
+			players[i] = string(rune('a' + i))
+		}
+		tables, err := Assign(players)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("Assign(%d players): expected error", tt.players)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Assign(%d players) error: %v", tt.players, err)
+		}
+		var sizes []int
+		for i, table := range tables {
+			sizes = append(sizes, len(table.Players))
+			if table.Number != i+1 || table.Deals != DealsPerSeat*len(table.Players) {
+				t.Errorf("Assign(%d players) table %d = %+v", tt.players, i+1, table)
+			}
+		}
+		if !reflect.DeepEqual(sizes, tt.want) {
+			t.Errorf("Assign(%d players) = tables of %v, want %v", tt.players, sizes, tt.want)
+		}
+	}
+}
+
+func TestStandings(t *testing.T) {
+	tr := &Tournament{
+		Name:    "cup",
+		Players: []string{"anna", "ben", "carl"},
+		Series:  []Series{{Number: 1, Tables: []Table{{Number: 1, Players: []string{"anna", "ben", "carl"}, Deals: 36}}}},
+	}
+	// anna sits at Forehand in the third deal; she wins, loses, and the other
+	// tournament's game does not count
+	won := recordtest.Grand(t, tr.GameID(1, 1, 3), time.Now(), false)
+	lost := recordtest.Grand(t, tr.GameID(1, 1, 6), time.Now(), true)
+	other := recordtest.Grand(t, "open-s1-t1-g3", time.Now(), false)
+	unseated := recordtest.Grand(t, tr.GameID(1, 1, 4), time.Now(), false)
+
+	standings, err := tr.Standings([]*skat.GameRecord{won, lost, other, unseated})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wonGame, err := won.Replay(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lostGame, err := lost.Replay(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wonScore, lostScore := wonGame.Result.Score, lostGame.Result.Score
+	anna := Seeger{Won: 1, Lost: 1, Points: wonScore + lostScore, Score: wonScore + WonBonus + lostScore - LostPenalty}
+	if standings[0].Player != "anna" || standings[0].Seeger != anna || standings[0].Series[0] != anna.Score {
+		t.Errorf("standings[0] = %+v, want anna with %+v", standings[0], anna)
+	}
+	for _, s := range standings[1:] {
+		if s.Seeger != (Seeger{Opponent: 1, Score: OpponentBonus3}) {
+			t.Errorf("standing of %s = %+v, want one lost game of anna", s.Player, s.Seeger)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package visibility decides who may see a game. Private games are kept from everyone
+// but their players, and so are daily games of the current day and duplicate games
+// whose deal is not played at every table, since they reveal the deal. Replays,
+// exports, statistics and event streams of other players' games pass this check.
+package visibility
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+	"github.com/mkloubert/freeskat-server/pkg/stats"
+)
+
+// Rules are the visibility rules of a server. The zero value and nil hide nothing.
+type Rules struct {
+	archive   *archive.Archive
+	daily     *daily.Schedule
+	duplicate *duplicate.Set
+}
+
+// New creates the rules of the archive's private games, the deal of the day and the
+// duplicate set; each may be nil.
+func New(games *archive.Archive, schedule *daily.Schedule, set *duplicate.Set) *Rules {
+	return &Rules{archive: games, daily: schedule, duplicate: set}
+}
+
+// Hidden returns true if the game must not be shown to login ("" = everyone) at time
+// now. The players of duplicate games see the games of their table; players always
+// see their own games, which the caller checks.
+func (r *Rules) Hidden(id, login string, now time.Time) bool {
+	if r == nil {
+		return false
+	}
+	return r.archive != nil && r.archive.IsPrivate(id) ||
+		r.daily != nil && r.daily.Hidden(id, now) ||
+		r.duplicate != nil && !r.duplicate.CanView(id, login)
+}
+
+// Records returns the archived games matching the filter that everyone may see at
+// time now.
+func (r *Rules) Records(filter archive.Filter, now time.Time) ([]*skat.GameRecord, error) {
+	filter.Public = true
+	records, err := r.archive.Records(filter)
+	if err != nil {
+		return nil, err
+	}
+	visible := records[:0]
+	for _, record := range records {
+		if !r.Hidden(record.ID, "", now) {
+			visible = append(visible, record)
+		}
+	}
+	return visible, nil
+}
+
+// Stats returns the statistics of the games everyone may see at time now, without
+// adjourned games as in archive.Archive.Stats.
+func (r *Rules) Stats(now time.Time) (*stats.Collector, error) {
+	records, err := r.Records(archive.Filter{}, now)
+	if err != nil {
+		return nil, err
+	}
+	collector := stats.NewCollector()
+	for _, record := range records {
+		if r.archive.IsAdjourned(record.ID) {
+			continue
+		}
+		if err := collector.Add(record); err != nil {
+			return nil, fmt.Errorf("game %s: %w", record.ID, err)
+		}
+	}
+	return collector, nil
+}
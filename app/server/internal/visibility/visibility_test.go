@@ -0,0 +1,181 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package visibility
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/pkg/duplicate"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// now is the time of the tests, on the day after recordtest.Start.
+var now = recordtest.Start.Add(24 * time.Hour)
+
+// fixture is an archive with a public game g1 of anna, ben and carl and one hidden
+// game of each kind, whose player is named after it.
+type fixture struct {
+	rules *Rules
+	games *archive.Archive
+	set   *duplicate.Set
+	// today and yesterday are the IDs of the daily games
+	today, yesterday string
+}
+
+// players returns players of a game: login against two others.
+func players(login string) map[skat.Player]string {
+	return map[skat.Player]string{skat.Forehand: login, skat.Middlehand: "bot1", skat.Rearhand: "bot2"}
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	games, err := archive.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := daily.NewSchedule("secret", time.UTC)
+	set, err := duplicate.NewSet(&duplicate.Config{
+		Name:   "cup",
+		Seed:   7,
+		Deals:  1,
+		Tables: [][3]string{{"dirk", "dana", "dean"}, {"dora", "emil", "fritz"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fixture{
+		rules:     New(games, schedule, set),
+		games:     games,
+		set:       set,
+		today:     daily.GamePrefix(schedule.Date(now)) + "1",
+		yesterday: daily.GamePrefix(schedule.Date(recordtest.Start)) + "1",
+	}
+
+	save := func(record *skat.GameRecord) {
+		t.Helper()
+		if err := games.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save(recordtest.Played(t, 1))
+	private := recordtest.PlayedBy(t, 2, players("petra"))
+	save(private)
+	if err := games.SetPrivate(private.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	adjourned := recordtest.PlayedBy(t, 3, players("adam"))
+	save(adjourned)
+	if err := games.SetAdjourned(adjourned.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	for id, login := range map[string]string{f.today: "tina", f.yesterday: "yves"} {
+		record := recordtest.PlayedBy(t, 4, players(login))
+		record.ID = id
+		save(record)
+	}
+	f.addDeal(t, 0)
+	return f
+}
+
+// addDeal saves the deal of the duplicate set played at a table.
+func (f *fixture) addDeal(t *testing.T, table int) {
+	t.Helper()
+	record := recordtest.Finish(t, f.set.Record(table, 0, now))
+	if err := f.games.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.set.Add(record); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHidden(t *testing.T) {
+	f := newFixture(t)
+	deal := f.set.GameID(0, 0)
+	tests := []struct {
+		name  string
+		id    string
+		login string
+		want  bool
+	}{
+		{"public", "g1", "", false},
+		{"private", "g2", "", true},
+		{"private to its player", "g2", "petra", true},
+		{"daily of today", f.today, "", true},
+		{"daily of yesterday", f.yesterday, "", false},
+		{"unrevealed deal", deal, "", true},
+		{"unrevealed deal to its table", deal, "dana", false},
+		{"unrevealed deal to the other table", deal, "dora", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.rules.Hidden(tt.id, tt.login, now); got != tt.want {
+				t.Errorf("Hidden(%q, %q) = %v, want %v", tt.id, tt.login, got, tt.want)
+			}
+		})
+	}
+
+	// Played at every table, the deal is revealed to everyone
+	f.addDeal(t, 1)
+	if f.rules.Hidden(deal, "", now) {
+		t.Error("Hidden() = true after the deal was played at every table")
+	}
+
+	var none *Rules
+	if none.Hidden("g2", "", now) {
+		t.Error("nil Rules hide a game")
+	}
+}
+
+func TestRecords(t *testing.T) {
+	f := newFixture(t)
+	records, err := f.rules.Records(archive.Filter{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	slices.Sort(ids)
+	want := []string{f.yesterday, "g1", "g3"}
+	slices.Sort(want)
+	if !slices.Equal(ids, want) {
+		t.Errorf("Records() = %v, want %v", ids, want)
+	}
+}
+
+func TestStats(t *testing.T) {
+	f := newFixture(t)
+	collector, err := f.rules.Stats(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, login := range []string{"anna", "yves"} {
+		if _, ok := collector.Player(login); !ok {
+			t.Errorf("no statistics of %s", login)
+		}
+	}
+	for _, login := range []string{"petra", "adam", "tina", "dana"} {
+		if _, ok := collector.Player(login); ok {
+			t.Errorf("statistics of %s reveal a hidden or adjourned game", login)
+		}
+	}
+}
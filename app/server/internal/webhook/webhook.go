@@ -0,0 +1,272 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook posts the results of finished games and series as JSON to
+// configured URLs, so leagues can feed them into their own websites. It also asks
+// an external payment service to collect the seat fees of tournament registrations.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// Event types.
+const (
+	EventGameFinished   = "game.finished"
+	EventSeriesFinished = "series.finished"
+	// EventPaymentRequired asks the payment service to collect the seat fee of a
+	// pending registration
+	EventPaymentRequired = "registration.payment"
+	// EventTurnWaiting tells a player that a game has been waiting for its move (sent
+	// to the player's own URL, see internal/notify)
+	EventTurnWaiting = "turn.waiting"
+)
+
+// SignatureHeader is the header with the HMAC-SHA256 signature of the body
+// ("sha256=<hex>"), sent if a secret is configured.
+const SignatureHeader = "X-FreeSkat-Signature"
+
+const (
+	// queueSize is the number of events waiting for delivery; further events are dropped
+	queueSize = 256
+	// attempts is the number of delivery attempts per URL
+	attempts = 3
+	// timeout is the timeout of a single request
+	timeout = 10 * time.Second
+)
+
+// Event is the JSON payload of a webhook request.
+type Event struct {
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	Game   *Game     `json:"game,omitempty"`
+	Series *Series   `json:"series,omitempty"`
+	// Registration is the registration of a registration.payment event
+	Registration *Registration `json:"registration,omitempty"`
+	// Turn is the waiting turn of a turn.waiting event
+	Turn *Turn `json:"turn,omitempty"`
+}
+
+// Game is a finished game.
+type Game struct {
+	ID        string          `json:"id"`
+	StartedAt time.Time       `json:"startedAt"`
+	Players   []replay.Player `json:"players"`
+	// Declarer is the position of the declarer (nil for Ramsch)
+	Declarer *int   `json:"declarer"`
+	Contract string `json:"contract,omitempty"`
+	Bid      int    `json:"bid"`
+	// Result and Ramsch are the game result, as in the replay format
+	Result *replay.Result      `json:"result,omitempty"`
+	Ramsch *replay.RamschScore `json:"ramsch,omitempty"`
+}
+
+// Series is a finished series of games.
+type Series struct {
+	Name string `json:"name"`
+	// Games are the IDs of the games of the series
+	Games     []string   `json:"games"`
+	Standings []Standing `json:"standings"`
+}
+
+// Standing is the final standing of a player in a series.
+type Standing struct {
+	Rank   int    `json:"rank"`
+	Player string `json:"player"`
+	Games  int    `json:"games"`
+	Score  int    `json:"score"`
+}
+
+// Registration is a tournament registration awaiting the payment of the seat fee.
+type Registration struct {
+	Tournament string `json:"tournament"`
+	Player     string `json:"player"`
+	// Fee is the seat fee in cents
+	Fee int `json:"fee"`
+}
+
+// Turn is a game waiting for the move of a player.
+type Turn struct {
+	Player string `json:"player"`
+	Table  string `json:"table"`
+	Game   string `json:"game"`
+	// Since is when the turn began
+	Since time.Time `json:"since"`
+}
+
+// Payment is the confirmation the payment service posts back for a registration.
+type Payment struct {
+	Player string `json:"player"`
+	// Confirmed is true if the fee has been paid, false if the payment failed
+	Confirmed bool `json:"confirmed"`
+}
+
+// Notifier delivers events to the webhook URLs in the background. Failed requests
+// are retried a few times, then the event is dropped for that URL.
+type Notifier struct {
+	urls   []string
+	secret string
+	client *http.Client
+	queue  chan *Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+// New creates a notifier posting to the URLs. If secret is set, every request is signed.
+func New(urls []string, secret string) *Notifier {
+	n := &Notifier{
+		urls:   urls,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan *Event, queueSize),
+		done:   make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+// GameFinished sends a game.finished event. Games without result (e.g. abandoned
+// ones) are ignored.
+func (n *Notifier) GameFinished(r *replay.Replay) {
+	if r.Result == nil && r.Ramsch == nil {
+		return
+	}
+	n.send(&Event{
+		Type: EventGameFinished,
+		Time: time.Now(),
+		Game: &Game{
+			ID:        r.ID,
+			StartedAt: r.StartedAt,
+			Players:   r.Players,
+			Declarer:  r.Declarer,
+			Contract:  r.Contract,
+			Bid:       r.Bid,
+			Result:    r.Result,
+			Ramsch:    r.Ramsch,
+		},
+	})
+}
+
+// SeriesFinished sends a series.finished event.
+func (n *Notifier) SeriesFinished(name string, games []string, standings []Standing) {
+	n.send(&Event{
+		Type:   EventSeriesFinished,
+		Time:   time.Now(),
+		Series: &Series{Name: name, Games: games, Standings: standings},
+	})
+}
+
+// PaymentRequired sends a registration.payment event.
+func (n *Notifier) PaymentRequired(tournament, player string, fee int) {
+	n.send(&Event{
+		Type:         EventPaymentRequired,
+		Time:         time.Now(),
+		Registration: &Registration{Tournament: tournament, Player: player, Fee: fee},
+	})
+}
+
+// Close stops the notifier after the queued events have been delivered.
+func (n *Notifier) Close() {
+	n.once.Do(func() { close(n.queue) })
+	<-n.done
+}
+
+// send queues an event.
+func (n *Notifier) send(event *Event) {
+	select {
+	case n.queue <- event:
+	default:
+		log.Printf("[webhook] Queue full, dropping %s event", event.Type)
+	}
+}
+
+// run delivers the queued events.
+func (n *Notifier) run() {
+	defer close(n.done)
+
+	for event := range n.queue {
+		body, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("[webhook] Failed to encode %s event: %v", event.Type, err)
+			continue
+		}
+		for _, url := range n.urls {
+			if err := n.deliver(url, body); err != nil {
+				log.Printf("[webhook] Failed to deliver %s event to %s: %v", event.Type, url, err)
+			}
+		}
+	}
+}
+
+// deliver posts a body to a URL, retrying with increasing delays.
+func (n *Notifier) deliver(url string, body []byte) error {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		if err = n.post(url, body); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// post posts a body once. Every 2xx status counts as success.
+func (n *Notifier) post(url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of a body, so receivers can verify requests.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if a signature header value ("sha256=<hex>") is the signature
+// of a body with the secret.
+func Verify(secret string, body []byte, signature string) bool {
+	sum, ok := strings.CutPrefix(signature, "sha256=")
+	return ok && hmac.Equal([]byte(sum), []byte(Sign(secret, body)))
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// receiver collects the events posted to a test server. The first fail requests
+// are answered with an error.
+type receiver struct {
+	t      *testing.T
+	secret string
+	mu     sync.Mutex
+	fail   int
+	events []Event
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.fail > 0 {
+		rc.fail--
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+		return
+	}
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+		rc.t.Errorf("%s with %s, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+	}
+	if rc.secret != "" && !Verify(rc.secret, body, r.Header.Get(SignatureHeader)) {
+		rc.t.Errorf("invalid signature %q", r.Header.Get(SignatureHeader))
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		rc.t.Errorf("invalid body: %v", err)
+	}
+	rc.events = append(rc.events, event)
+}
+
+func TestNotifier(t *testing.T) {
+	signed := &receiver{t: t, secret: "s3cret", fail: 1}
+	plain := &receiver{t: t}
+	signedServer, plainServer := httptest.NewServer(signed), httptest.NewServer(plain)
+	defer signedServer.Close()
+	defer plainServer.Close()
+
+	game, err := replay.New(recordtest.Played(t, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unfinished, _ := replay.New(recordtest.Played(t, 2))
+	unfinished.Result, unfinished.Ramsch = nil, nil
+
+	n := New([]string{signedServer.URL, plainServer.URL}, "s3cret")
+	n.GameFinished(game)
+	n.GameFinished(unfinished)
+	n.SeriesFinished("friday", []string{"g1"}, []Standing{{Rank: 1, Player: "anna", Games: 1, Score: 96}})
+	n.PaymentRequired("cup", "ben", 500)
+	// Close waits for the deliveries, including the retry after the failed request
+	n.Close()
+
+	for _, rc := range []*receiver{signed, plain} {
+		if len(rc.events) != 3 {
+			t.Fatalf("received %d events, want 3", len(rc.events))
+		}
+		g := rc.events[0]
+		if g.Type != EventGameFinished || g.Game == nil || g.Game.ID != "g1" || len(g.Game.Players) != 3 || g.Game.Contract != game.Contract {
+			t.Errorf("game event = %+v", g)
+		}
+		if g.Game != nil && g.Game.Result == nil && g.Game.Ramsch == nil {
+			t.Error("the game event has no result")
+		}
+		if s := rc.events[1]; s.Type != EventSeriesFinished || s.Series.Name != "friday" || s.Series.Standings[0].Score != 96 {
+			t.Errorf("series event = %+v", s)
+		}
+		if p := rc.events[2]; p.Type != EventPaymentRequired || *p.Registration != (Registration{Tournament: "cup", Player: "ben", Fee: 500}) {
+			t.Errorf("payment event = %+v", p)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"game.finished"}`)
+	signature := "sha256=" + Sign("s3cret", body)
+	if !Verify("s3cret", body, signature) {
+		t.Error("Verify() rejects the signature")
+	}
+	for _, tt := range []struct {
+		secret, body, signature string
+	}{
+		{"other", string(body), signature},
+		{"s3cret", `{"type":"series.finished"}`, signature},
+		{"s3cret", string(body), Sign("s3cret", body)},
+	} {
+		if Verify(tt.secret, []byte(tt.body), tt.signature) {
+			t.Errorf("Verify(%q, %q, %q) = true", tt.secret, tt.body, tt.signature)
+		}
+	}
+}
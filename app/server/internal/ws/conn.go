@@ -0,0 +1,156 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// messageStream is a stream of messages: WebSocket messages or the lines of a TCP client.
+type messageStream interface {
+	ReadMessage() ([]byte, error)
+	WriteMessage(data []byte) error
+}
+
+// lines adapts a message stream to the line stream of a session: every message is one
+// line, in the JSON encoding converted from and to ISS lines.
+type lines struct {
+	stream  messageStream
+	json    bool
+	pending []byte
+	written []byte
+	mu      sync.Mutex
+}
+
+// lineConn carries the lines of a session over a WebSocket connection.
+type lineConn struct {
+	*lines
+	ws *Conn
+}
+
+// NetConn returns the connection as net.Conn carrying ISS lines, e.g. for a session.
+// The encoding follows the negotiated subprotocol.
+func NetConn(c *Conn) net.Conn {
+	return &lineConn{lines: &lines{stream: c, json: c.Protocol() == ProtocolJSON}, ws: c}
+}
+
+// JSONStream switches the line stream of a TCP client to the JSON encoding, one message
+// per line. It returns the reader and writer of the ISS lines.
+func JSONStream(r io.Reader, w io.Writer) (io.Reader, io.Writer) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), MaxMessageSize)
+	l := &lines{stream: &textStream{scanner: scanner, w: w}, json: true}
+	return l, l
+}
+
+// textStream is a stream of newline-terminated messages.
+type textStream struct {
+	scanner *bufio.Scanner
+	w       io.Writer
+}
+
+// ReadMessage returns the next line.
+func (t *textStream) ReadMessage() ([]byte, error) {
+	if !t.scanner.Scan() {
+		if err := t.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return bytes.Clone(t.scanner.Bytes()), nil
+}
+
+// WriteMessage writes a message as one line.
+func (t *textStream) WriteMessage(data []byte) error {
+	_, err := t.w.Write(append(data, '\n'))
+	return err
+}
+
+// Read returns the next lines of the client. Invalid JSON messages are answered with
+// an error message and skipped.
+func (c *lines) Read(p []byte) (int, error) {
+	for len(c.pending) == 0 {
+		message, err := c.stream.ReadMessage()
+		if err != nil {
+			return 0, err
+		}
+		if !c.json {
+			c.pending = append(message, '\n')
+			continue
+		}
+		line, err := DecodeCommand(message)
+		if err != nil {
+			if err := c.send(Message{Type: "error", Text: err.Error()}); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		c.pending = []byte(line + "\n")
+	}
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+	return n, nil
+}
+
+// Write sends the complete lines written so far, one message per line.
+func (c *lines) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.written = append(c.written, p...)
+	for {
+		i := bytes.IndexByte(c.written, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := bytes.TrimSuffix(c.written[:i], []byte("\r"))
+		c.written = c.written[i+1:]
+
+		var err error
+		if !c.json {
+			err = c.stream.WriteMessage(line)
+		} else if m, ok := EncodeLine(string(line)); ok {
+			err = c.send(m)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// send sends a JSON message.
+func (c *lines) send(m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return c.stream.WriteMessage(data)
+}
+
+// Keepalive sends a ping instead of a keepalive line.
+func (c *lineConn) Keepalive() error { return c.ws.Ping() }
+
+func (c *lineConn) Close() error                       { return c.ws.Close() }
+func (c *lineConn) LocalAddr() net.Addr                { return c.ws.conn.LocalAddr() }
+func (c *lineConn) RemoteAddr() net.Addr               { return c.ws.conn.RemoteAddr() }
+func (c *lineConn) SetDeadline(t time.Time) error      { return c.ws.conn.SetDeadline(t) }
+func (c *lineConn) SetReadDeadline(t time.Time) error  { return c.ws.conn.SetReadDeadline(t) }
+func (c *lineConn) SetWriteDeadline(t time.Time) error { return c.ws.conn.SetWriteDeadline(t) }
@@ -0,0 +1,199 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Schema is the JSON schema of the messages of the JSON encoding.
+//
+//go:embed schema.json
+var Schema []byte
+
+// Message is an ISS line in the JSON encoding. Type is the first token of the line in
+// lower case ("welcome", "version", "password", "table", "error", "daily", ...). The
+// table, text and login messages have their own fields; all other tokens of a line
+// are in Args.
+type Message struct {
+	Type string `json:"type"`
+	// Table, Login and Action are the first tokens of a table message
+	Table  string `json:"table,omitempty"`
+	Login  string `json:"login,omitempty"`
+	Action string `json:"action,omitempty"`
+	// Players are the players of "table ... start"
+	Players []string `json:"players,omitempty"`
+	// Player and Move are the move of "table ... play"
+	Player string `json:"player,omitempty"`
+	Move   string `json:"move,omitempty"`
+	// Times are the remaining seconds of the players after a move of a timed game
+	Times []string `json:"times,omitempty"`
+	// Hand is the open hand of "table ... ouvert" (with Player) or the skat of "table ... skat"
+	Hand string `json:"hand,omitempty"`
+	// Summary is the game summary of "table ... end"
+	Summary string `json:"summary,omitempty"`
+	// Sender is the author of "yell", "table ... tell" and "table ... comment"
+	Sender string `json:"sender,omitempty"`
+	// Code is the error code of "table ... error"
+	Code string `json:"code,omitempty"`
+	// Text is the free text of error, text, welcome and chat messages
+	Text string `json:"text,omitempty"`
+	// Version is the protocol version of "version"
+	Version int `json:"version,omitempty"`
+	// Password is the password of the "login" command
+	Password string `json:"password,omitempty"`
+	// Args are the remaining tokens
+	Args []string `json:"args,omitempty"`
+}
+
+// EncodeLine converts a line of the server to its JSON message (ok = false for empty
+// lines).
+func EncodeLine(line string) (Message, bool) {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return Message{}, false
+	}
+
+	switch parts[0] {
+	case "Welcome":
+		return Message{Type: "welcome", Text: after(line, 1)}, true
+	case "Version":
+		m := Message{Type: "version"}
+		if len(parts) > 1 {
+			m.Version, _ = strconv.Atoi(parts[1])
+		}
+		return m, true
+	case "password:":
+		return Message{Type: "password"}, true
+	case "error", "text":
+		return Message{Type: parts[0], Text: after(line, 1)}, true
+	case "yell":
+		if len(parts) > 1 {
+			return Message{Type: "yell", Sender: parts[1], Text: after(line, 2)}, true
+		}
+	case "table":
+		if len(parts) >= 4 {
+			return encodeTable(line, parts), true
+		}
+	}
+	return Message{Type: parts[0], Args: parts[1:]}, true
+}
+
+// encodeTable converts "table <name> <login> <action> ..." to its JSON message.
+func encodeTable(line string, parts []string) Message {
+	m := Message{Type: "table", Table: parts[1], Login: parts[2], Action: parts[3]}
+	args := parts[4:]
+	switch {
+	case m.Action == "start":
+		m.Players = args
+	case m.Action == "play" && len(args) == 2:
+		m.Player, m.Move = args[0], args[1]
+	case m.Action == "play" && len(args) == 5:
+		m.Player, m.Move, m.Times = args[0], args[1], args[2:]
+	case m.Action == "ouvert" && len(args) == 2:
+		m.Player, m.Hand = args[0], args[1]
+	case m.Action == "skat" && len(args) == 1:
+		m.Hand = args[0]
+	case m.Action == "hint" && len(args) > 0:
+		m.Move, m.Text = args[0], after(line, 5)
+	case m.Action == "end":
+		m.Summary = after(line, 4)
+	case m.Action == "error" && len(args) > 0:
+		m.Code, m.Text = args[0], after(line, 5)
+	case (m.Action == "tell" || m.Action == "comment") && len(args) > 0:
+		m.Sender, m.Text = args[0], after(line, 5)
+	default:
+		m.Args = args
+	}
+	return m
+}
+
+// DecodeCommand converts a JSON message of the client to its ISS line:
+//
+//	{"type":"login","login":"alice","password":"secret"}             login alice secret
+//	{"type":"table","table":"t1","login":"alice","action":"play","move":"CJ"}
+//	{"type":"table","table":"t1","login":"alice","action":"tell","text":"hi all"}
+//	{"type":"daily","args":["play"]}                                  daily play
+//
+// Text is appended after the tokens. Tokens must not contain whitespace, texts no line
+// breaks.
+func DecodeCommand(data []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var m Message
+	if err := decoder.Decode(&m); err != nil {
+		return "", fmt.Errorf("invalid JSON message: %w", err)
+	}
+	if m.Type == "" {
+		return "", errors.New("invalid JSON message: missing type")
+	}
+
+	var line []string
+	switch m.Type {
+	case "login":
+		line = []string{"login", m.Login, m.Password}
+	case "table":
+		line = []string{"table", m.Table, m.Login, m.Action}
+		if m.Action == "play" {
+			line = append(line, m.Move)
+		}
+		line = append(line, m.Args...)
+	default:
+		line = append([]string{m.Type}, m.Args...)
+	}
+
+	for _, token := range line {
+		if err := checkToken(token); err != nil {
+			return "", fmt.Errorf("%s message: %w", m.Type, err)
+		}
+	}
+	if m.Text != "" {
+		if strings.ContainsAny(m.Text, "\r\n") {
+			return "", fmt.Errorf("%s message: text with line breaks", m.Type)
+		}
+		line = append(line, m.Text)
+	}
+	return strings.Join(line, " "), nil
+}
+
+// checkToken returns an error for empty tokens and tokens with whitespace.
+func checkToken(token string) error {
+	if token == "" {
+		return errors.New("missing value")
+	}
+	if strings.ContainsAny(token, " \t\r\n") {
+		return fmt.Errorf("value with whitespace: %q", token)
+	}
+	return nil
+}
+
+// after returns the text of a line after its first n tokens.
+func after(line string, n int) string {
+	s := strings.TrimLeft(line, " \t")
+	for i := 0; i < n; i++ {
+		end := strings.IndexAny(s, " \t")
+		if end < 0 {
+			return ""
+		}
+		s = strings.TrimLeft(s[end:], " \t")
+	}
+	return strings.TrimRight(s, " \t\r")
+}
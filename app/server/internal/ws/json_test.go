@@ -0,0 +1,134 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Message
+	}{
+		{"Welcome to FreeSkat,  anna", Message{Type: "welcome", Text: "to FreeSkat,  anna"}},
+		{"Version 14", Message{Type: "version", Version: 14}},
+		{"password:", Message{Type: "password"}},
+		{"error Unknown table: t9", Message{Type: "error", Text: "Unknown table: t9"}},
+		{"yell ben good game all", Message{Type: "yell", Sender: "ben", Text: "good game all"}},
+		{"table t1 anna start anna ben carl", Message{Type: "table", Table: "t1", Login: "anna", Action: "start", Players: []string{"anna", "ben", "carl"}}},
+		{"table t1 anna play 1 18", Message{Type: "table", Table: "t1", Login: "anna", Action: "play", Player: "1", Move: "18"}},
+		{"table t1 anna play 2 CJ 12.5 30.0 29.1", Message{Type: "table", Table: "t1", Login: "anna", Action: "play", Player: "2", Move: "CJ", Times: []string{"12.5", "30.0", "29.1"}}},
+		{"table t1 anna ouvert 0 CJ.SJ", Message{Type: "table", Table: "t1", Login: "anna", Action: "ouvert", Player: "0", Hand: "CJ.SJ"}},
+		{"table t1 anna skat H7.D7", Message{Type: "table", Table: "t1", Login: "anna", Action: "skat", Hand: "H7.D7"}},
+		{"table t1 anna hint CJ trump out", Message{Type: "table", Table: "t1", Login: "anna", Action: "hint", Move: "CJ", Text: "trump out"}},
+		{"table t1 anna end (;GM[Skat] R[d:0 win];)", Message{Type: "table", Table: "t1", Login: "anna", Action: "end", Summary: "(;GM[Skat] R[d:0 win];)"}},
+		{"table t1 anna error 12 Not your turn", Message{Type: "table", Table: "t1", Login: "anna", Action: "error", Code: "12", Text: "Not your turn"}},
+		{"table t1 anna tell ben well played", Message{Type: "table", Table: "t1", Login: "anna", Action: "tell", Sender: "ben", Text: "well played"}},
+		{"table t1 anna destroy", Message{Type: "table", Table: "t1", Login: "anna", Action: "destroy", Args: []string{}}},
+		{"table t1", Message{Type: "table", Args: []string{"t1"}}},
+		{"daily entry 2025-03-01 1 anna 192", Message{Type: "daily", Args: []string{"entry", "2025-03-01", "1", "anna", "192"}}},
+	}
+	for _, tt := range tests {
+		got, ok := EncodeLine(tt.line)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EncodeLine(%q) = %+v, %v, want %+v", tt.line, got, ok, tt.want)
+		}
+	}
+	if _, ok := EncodeLine("  "); ok {
+		t.Error("EncodeLine() encoded an empty line")
+	}
+}
+
+func TestDecodeCommand(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{`{"type":"login","login":"anna","password":"s3cret"}`, "login anna s3cret"},
+		{`{"type":"table","table":"t1","login":"anna","action":"play","move":"CJ"}`, "table t1 anna play CJ"},
+		{`{"type":"table","table":"t1","login":"anna","action":"tell","text":"hi all"}`, "table t1 anna tell hi all"},
+		{`{"type":"daily","args":["leaderboard","2025-03-01"]}`, "daily leaderboard 2025-03-01"},
+		{`{"type":"yell","text":"hello"}`, "yell hello"},
+	}
+	for _, tt := range tests {
+		if got, err := DecodeCommand([]byte(tt.message)); err != nil || got != tt.want {
+			t.Errorf("DecodeCommand(%s) = %q, %v, want %q", tt.message, got, err, tt.want)
+		}
+	}
+
+	invalid := []struct {
+		message string
+		want    string
+	}{
+		{`{"type":`, "invalid JSON message"},
+		{`{"args":["play"]}`, "missing type"},
+		{`{"type":"daily","color":"red"}`, "unknown field"},
+		{`{"type":"login","login":"anna"}`, "login message: missing value"},
+		{`{"type":"table","table":"t 1","login":"anna","action":"leave"}`, "value with whitespace"},
+		{`{"type":"yell","text":"line\nbreak"}`, "text with line breaks"},
+	}
+	for _, tt := range invalid {
+		if _, err := DecodeCommand([]byte(tt.message)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("DecodeCommand(%s) = %v, want %q", tt.message, err, tt.want)
+		}
+	}
+}
+
+func TestJSONStream(t *testing.T) {
+	in := strings.NewReader(`{"type":"login","login":"anna","password":"s3cret"}` + "\n" +
+		`{"type":"login"` + "\n" +
+		`{"type":"table","table":"t1","login":"anna","action":"play","move":"18"}` + "\n")
+	var out bytes.Buffer
+	r, w := JSONStream(in, &out)
+
+	lines, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lines) != "login anna s3cret\ntable t1 anna play 18\n" {
+		t.Errorf("read %q", lines)
+	}
+	// The invalid message is answered with an error
+	if !strings.HasPrefix(out.String(), `{"type":"error","text":"invalid JSON message: `) {
+		t.Errorf("answered %q", out.String())
+	}
+
+	out.Reset()
+	if _, err := io.WriteString(w, "table t1 anna play 1 18\r\nVers"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != `{"type":"table","table":"t1","login":"anna","action":"play","player":"1","move":"18"}`+"\n" {
+		t.Errorf("wrote %q", out.String())
+	}
+	io.WriteString(w, "ion 14\n")
+	if !strings.HasSuffix(out.String(), `{"type":"version","version":14}`+"\n") {
+		t.Errorf("wrote %q after completing the line", out.String())
+	}
+}
+
+func TestSchema(t *testing.T) {
+	var schema struct {
+		Schema string `json:"$schema"`
+	}
+	if err := json.Unmarshal(Schema, &schema); err != nil || schema.Schema == "" {
+		t.Errorf("invalid schema: %v", err)
+	}
+}
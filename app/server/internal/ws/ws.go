@@ -0,0 +1,339 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ws is a minimal WebSocket server (RFC 6455) carrying the ISS protocol for
+// browser clients. One text message is one ISS line. The subprotocol is negotiated at
+// connection time: "iss" (or none) sends the lines as they are, "iss-json" encodes them
+// as JSON objects (see the JSON schema in schema.json).
+package ws
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Subprotocols of the transport.
+const (
+	ProtocolLines = "iss"
+	ProtocolJSON  = "iss-json"
+)
+
+// MaxMessageSize is the maximum size of a message of a client in bytes.
+const MaxMessageSize = 64 << 10
+
+// acceptGUID is appended to the key of the client for the accept header.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Frame opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// Close status codes.
+const (
+	closeNormal      = 1000
+	closeProtocol    = 1002
+	closeUnsupported = 1003
+	closeTooLarge    = 1009
+)
+
+var (
+	// ErrProtocol is returned for frames violating the WebSocket protocol.
+	ErrProtocol = errors.New("websocket protocol error")
+	// ErrMessageTooLarge is returned for messages larger than MaxMessageSize.
+	ErrMessageTooLarge = errors.New("websocket message too large")
+)
+
+// Conn is the server side of a WebSocket connection.
+type Conn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	protocol string
+	mu       sync.Mutex
+	closed   bool
+}
+
+// Upgrade performs the opening handshake of a WebSocket connection. The first
+// subprotocol offered by the client that is in protocols is selected; without an
+// offer the connection has no subprotocol. On errors the HTTP response is written.
+func Upgrade(w http.ResponseWriter, r *http.Request, protocols ...string) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case r.Method != http.MethodGet:
+		http.Error(w, "WebSocket requires GET", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("%w: method %s", ErrProtocol, r.Method)
+	case !hasToken(r.Header, "Connection", "upgrade") || !hasToken(r.Header, "Upgrade", "websocket"):
+		http.Error(w, "WebSocket upgrade required", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("%w: no upgrade request", ErrProtocol)
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("%w: version %q", ErrProtocol, r.Header.Get("Sec-WebSocket-Version"))
+	case key == "":
+		http.Error(w, "Missing WebSocket key", http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: missing key", ErrProtocol)
+	}
+
+	protocol := ""
+	offered := tokens(r.Header, "Sec-WebSocket-Protocol")
+	for _, p := range offered {
+		if protocol == "" && contains(protocols, p) {
+			protocol = p
+		}
+	}
+	if len(offered) > 0 && protocol == "" {
+		http.Error(w, "Unsupported subprotocol, use "+strings.Join(protocols, " or "), http.StatusBadRequest)
+		return nil, fmt.Errorf("%w: subprotocols %v", ErrProtocol, offered)
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("connection cannot be hijacked")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	// Deadlines of the HTTP server must not end the connection
+	conn.SetDeadline(time.Time{})
+
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n"
+	if protocol != "" {
+		response += "Sec-WebSocket-Protocol: " + protocol + "\r\n"
+	}
+	if _, err := conn.Write([]byte(response + "\r\n")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &Conn{conn: conn, reader: rw.Reader, protocol: protocol}, nil
+}
+
+// Protocol returns the negotiated subprotocol ("" = none).
+func (c *Conn) Protocol() string {
+	return c.protocol
+}
+
+// ReadMessage reads the next text message, answering pings. It returns io.EOF when
+// the client closes the connection. Binary messages close the connection.
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrMessageTooLarge):
+				c.closeWith(closeTooLarge)
+			case errors.Is(err, ErrProtocol):
+				c.closeWith(closeProtocol)
+			}
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.closeWith(closeNormal)
+			return nil, io.EOF
+		case opBinary:
+			c.closeWith(closeUnsupported)
+			return nil, fmt.Errorf("%w: binary message", ErrProtocol)
+		case opText:
+			if started {
+				c.closeWith(closeProtocol)
+				return nil, fmt.Errorf("%w: unfinished message", ErrProtocol)
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				c.closeWith(closeProtocol)
+				return nil, fmt.Errorf("%w: continuation without message", ErrProtocol)
+			}
+		default:
+			c.closeWith(closeProtocol)
+			return nil, fmt.Errorf("%w: opcode %d", ErrProtocol, opcode)
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			c.closeWith(closeTooLarge)
+			return nil, ErrMessageTooLarge
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads a frame of the client. Client frames must be masked.
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	opcode := head[0] & 0x0f
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, fmt.Errorf("%w: reserved bits set", ErrProtocol)
+	}
+	if head[1]&0x80 == 0 {
+		return false, 0, nil, fmt.Errorf("%w: unmasked frame", ErrProtocol)
+	}
+
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, fmt.Errorf("%w: invalid control frame", ErrProtocol)
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends a text message. It is safe for concurrent use.
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// Ping sends a ping frame, e.g. as keepalive. It is safe for concurrent use.
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, nil)
+}
+
+// writeFrame sends an unmasked, unfragmented frame.
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return net.ErrClosed
+	}
+
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|opcode)
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// closeWith sends a close frame with the status code and closes the connection.
+func (c *Conn) closeWith(code uint16) error {
+	c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, code))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
+
+// Close sends a close frame and closes the connection.
+func (c *Conn) Close() error {
+	return c.closeWith(closeNormal)
+}
+
+// hasToken returns true if the comma-separated header contains the token (case
+// insensitive).
+func hasToken(header http.Header, name, token string) bool {
+	for _, t := range tokens(header, name) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokens returns the comma-separated values of a header.
+func tokens(header http.Header, name string) []string {
+	var result []string
+	for _, value := range header.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
+
+// contains returns true if the list contains the value.
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
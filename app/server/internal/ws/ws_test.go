@@ -0,0 +1,121 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// startEcho starts a server upgrading to WebSocket connections that answers each ISS
+// line with "text got <line>".
+func startEcho(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r, ProtocolLines, ProtocolJSON)
+		if err != nil {
+			return
+		}
+		conn := NetConn(c)
+		defer conn.Close()
+		lines := bufio.NewScanner(conn)
+		for lines.Scan() {
+			fmt.Fprintf(conn, "text got %s\n", lines.Text())
+		}
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+// dial opens a WebSocket connection offering the subprotocol and returns the status
+// line, the selected subprotocol and the connection.
+func dial(t *testing.T, address, protocol string) (string, string, net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Protocol: %s\r\n\r\n", address, protocol)
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Status, resp.Header.Get("Sec-WebSocket-Protocol"), conn, reader
+}
+
+// writeText writes a masked text frame of a client.
+func writeText(t *testing.T, conn net.Conn, text string) {
+	t.Helper()
+	mask := []byte{1, 2, 3, 4}
+	frame := append([]byte{0x81, 0x80 | byte(len(text))}, mask...)
+	for i := range len(text) {
+		frame = append(frame, text[i]^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readText reads an unmasked text frame of the server.
+func readText(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		t.Fatal(err)
+	}
+	if header[0] != 0x81 || header[1] >= 126 {
+		t.Fatalf("frame header %x", header)
+	}
+	payload := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		t.Fatal(err)
+	}
+	return string(payload)
+}
+
+func TestSubprotocols(t *testing.T) {
+	address := startEcho(t)
+
+	status, protocol, conn, reader := dial(t, address, ProtocolJSON+", "+ProtocolLines)
+	if !strings.HasPrefix(status, "101") || protocol != ProtocolJSON {
+		t.Fatalf("handshake = %s with %q, want %s", status, protocol, ProtocolJSON)
+	}
+	writeText(t, conn, `{"type":"yell","text":"hi"}`)
+	if got := readText(t, reader); got != `{"type":"text","text":"got yell hi"}` {
+		t.Errorf("JSON answer = %s", got)
+	}
+
+	_, protocol, conn, reader = dial(t, address, ProtocolLines)
+	if protocol != ProtocolLines {
+		t.Fatalf("selected %q, want %s", protocol, ProtocolLines)
+	}
+	writeText(t, conn, "table t1 anna play CJ")
+	if got := readText(t, reader); got != "text got table t1 anna play CJ" {
+		t.Errorf("line answer = %s", got)
+	}
+
+	if status, _, _, _ := dial(t, address, "iss-xml"); !strings.HasPrefix(status, "400") {
+		t.Errorf("unknown subprotocol answered %s, want 400", status)
+	}
+}
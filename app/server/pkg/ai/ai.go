@@ -0,0 +1,150 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ai provides computer opponent implementations.
+package ai
+
+import (
+	"math/rand"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// BidAction represents an action a player can take during bidding.
+type BidAction int
+
+const (
+	// BidActionBid - Make a new bid
+	BidActionBid BidAction = iota
+	// BidActionHold - Accept/hold the current bid
+	BidActionHold
+	// BidActionPass - Pass on bidding
+	BidActionPass
+)
+
+// String returns the string representation of the bid action.
+func (a BidAction) String() string {
+	switch a {
+	case BidActionBid:
+		return "Bid"
+	case BidActionHold:
+		return "Hold"
+	case BidActionPass:
+		return "Pass"
+	default:
+		return "Unknown"
+	}
+}
+
+// BidDecision is the bidding decision of an AI player.
+type BidDecision struct {
+	Action BidAction
+	// Value is the bid value (only set for BidActionBid)
+	Value int
+}
+
+// BidContext describes the bidding situation an AI player has to decide on.
+type BidContext struct {
+	// Player is the position of the deciding player
+	Player skat.Player
+	// CurrentBid is the current bid value (0 if no bids yet)
+	CurrentBid int
+	// Bidding is true if the player has to name a bid, false if responding to one
+	Bidding bool
+}
+
+// PlayContext describes the trick situation an AI player has to decide on.
+type PlayContext struct {
+	// Player is the position of the deciding player
+	Player skat.Player
+	// Declarer is the declarer of the game (nil in Ramsch)
+	Declarer *skat.Player
+	// GameType is the game type being played
+	GameType skat.GameType
+	// Trick is the current (incomplete) trick
+	Trick *skat.Trick
+	// DeclarerHand is the declarer's hand if it is visible (Ouvert games, otherwise nil)
+	DeclarerHand *skat.Hand
+}
+
+// IsDeclarer returns true if the deciding player is the declarer.
+func (c PlayContext) IsDeclarer() bool {
+	return c.Declarer != nil && *c.Declarer == c.Player
+}
+
+// IsPartner returns true if the given player plays on the same side as the deciding player.
+func (c PlayContext) IsPartner(p skat.Player) bool {
+	if p == c.Player {
+		return true
+	}
+	if c.Declarer == nil {
+		return false // Ramsch: everybody plays alone
+	}
+	return p != *c.Declarer && c.Player != *c.Declarer
+}
+
+// AIPlayer defines the contract for AI implementations.
+type AIPlayer interface {
+	// Name returns the AI player name.
+	Name() string
+
+	// DecideBid decides the bidding action.
+	DecideBid(hand *skat.Hand, ctx BidContext) BidDecision
+
+	// DecidePickUpSkat decides whether to pick up the skat.
+	DecidePickUpSkat(hand *skat.Hand) bool
+
+	// SelectDiscards selects the two cards to discard to the skat.
+	SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card
+
+	// DecideAnnouncement decides the contract to announce.
+	DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract
+
+	// SelectCard selects a card to play.
+	SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card
+}
+
+// intn returns a random number in [0, n) using rng, or the global source if rng is nil.
+func intn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+	return rng.Intn(n)
+}
+
+// float64n returns a random number in [0.0, 1.0) using rng, or the global source if rng is nil.
+func float64n(rng *rand.Rand) float64 {
+	if rng == nil {
+		return rand.Float64()
+	}
+	return rng.Float64()
+}
+
+// findClosestBid returns the lowest valid bid value greater than or equal to value.
+func findClosestBid(value int) int {
+	for _, bid := range skat.BidOrder {
+		if bid >= value {
+			return bid
+		}
+	}
+	return skat.MaxBid
+}
+
+// minimumBid returns the lowest bid the player may name in the given context.
+func minimumBid(ctx BidContext) int {
+	if ctx.CurrentBid > 0 {
+		return findClosestBid(ctx.CurrentBid + 1)
+	}
+	return skat.MinBid
+}
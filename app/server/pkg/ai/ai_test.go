@@ -0,0 +1,643 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// ============================================================================
+// Difficulty Tests
+// ============================================================================
+
+func TestParseDifficulty(t *testing.T) {
+	for _, d := range AllDifficulties {
+		got, err := ParseDifficulty(d.String())
+		if err != nil {
+			t.Fatalf("ParseDifficulty(%q) error: %v", d.String(), err)
+		}
+		if got != d {
+			t.Errorf("ParseDifficulty(%q) = %s, want %s", d.String(), got, d)
+		}
+	}
+
+	if _, err := ParseDifficulty("grandmaster"); err == nil {
+		t.Error("ParseDifficulty(grandmaster) should fail")
+	}
+}
+
+func TestDifficultyNoiseDecreases(t *testing.T) {
+	for i := 1; i < len(AllDifficulties); i++ {
+		if AllDifficulties[i].Noise() > AllDifficulties[i-1].Noise() {
+			t.Errorf("%s has more noise than %s", AllDifficulties[i], AllDifficulties[i-1])
+		}
+	}
+}
+
+// ============================================================================
+// Decision Tests
+// ============================================================================
+
+func TestSelectCardIsLegal(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+
+	for _, d := range AllDifficulties {
+		player := New(d, rng)
+
+		for i := 0; i < 50; i++ {
+			deck := skat.NewDeck()
+			rng.Shuffle(len(deck.Cards), func(a, b int) {
+				deck.Cards[a], deck.Cards[b] = deck.Cards[b], deck.Cards[a]
+			})
+			lead := deck.Deal(1)[0]
+			hand := skat.NewHandFromCards(deck.Deal(10))
+
+			trick := skat.NewTrick(skat.Forehand)
+			trick.AddCard(lead, skat.Forehand)
+
+			declarer := skat.Forehand
+			ctx := PlayContext{
+				Player:   skat.Middlehand,
+				Declarer: &declarer,
+				GameType: skat.GameHearts,
+				Trick:    trick,
+			}
+
+			card := player.SelectCard(hand, ctx)
+			if !hand.Contains(card) || !card.CanPlay(&lead, hand, skat.GameHearts) {
+				t.Fatalf("%s selected illegal card %s on %s", d, card.Code(), lead.Code())
+			}
+		}
+	}
+}
+
+func TestEvaluateHandStrongGrand(t *testing.T) {
+	hand, err := skat.HandFromCode("CJ.SJ.HJ.DJ.CA.CT.SA.ST.HA.HT")
+	if err != nil {
+		t.Fatalf("HandFromCode() error: %v", err)
+	}
+
+	evaluation := EvaluateHand(hand)
+	if evaluation.BestGameType != skat.GameGrand {
+		t.Errorf("BestGameType = %s, want Grand", evaluation.BestGameType)
+	}
+	if evaluation.Matadors != 4 {
+		t.Errorf("Matadors = %d, want 4", evaluation.Matadors)
+	}
+	if evaluation.MaxBid < 96 {
+		t.Errorf("MaxBid = %d, want at least 96 (Grand with 4)", evaluation.MaxBid)
+	}
+}
+
+func TestHeuristicAIPassesWeakHand(t *testing.T) {
+	// No Jacks, no long suit and too many high cards for a Null game
+	hand, err := skat.HandFromCode("C9.CQ.S8.SQ.SK.H9.HQ.D8.DQ.DK")
+	if err != nil {
+		t.Fatalf("HandFromCode() error: %v", err)
+	}
+
+	decision := NewHeuristicAI(false).DecideBid(hand, BidContext{
+		Player:     skat.Middlehand,
+		CurrentBid: 30,
+		Bidding:    true,
+	})
+	if decision.Action != BidActionPass {
+		t.Errorf("DecideBid() = %s, want Pass", decision.Action)
+	}
+}
+
+// ============================================================================
+// Null Tests
+// ============================================================================
+
+func TestEvaluateNull(t *testing.T) {
+	tests := []struct {
+		code     string
+		risk     int
+		safe     bool
+		playable bool
+	}{
+		{"C7.C8.S7.S8.S9.H7.H8.D7.D8.D9", 0, true, true},
+		{"C7.C9.CJ.CK.S7.S9.H7.H9.D7.D8", 0, true, true},
+		{"C7.C9.CJ.CK.S8.S9.H7.H9.D7.D8", 1, false, true},
+		{"CA.CK.SA.SK.HA.HK.DA.DK.DQ.DJ", 10, false, false},
+	}
+
+	for _, tt := range tests {
+		hand, _ := skat.HandFromCode(tt.code)
+		evaluation := EvaluateNull(hand)
+		if evaluation.Risk != tt.risk || evaluation.Safe != tt.safe || evaluation.Playable != tt.playable {
+			t.Errorf("EvaluateNull(%s) = risk %d, safe %v, playable %v, want %d, %v, %v",
+				tt.code, evaluation.Risk, evaluation.Safe, evaluation.Playable, tt.risk, tt.safe, tt.playable)
+		}
+	}
+}
+
+func TestEvaluateNullUnsafeCards(t *testing.T) {
+	// The spade 8 cannot stay below the 7 of an opponent; the 9 is safe above it
+	hand, _ := skat.HandFromCode("C7.C9.CJ.CK.S9.S8.H7.H9.D7.D8")
+	evaluation := EvaluateNull(hand)
+
+	var codes []string
+	for _, c := range evaluation.UnsafeCards {
+		codes = append(codes, c.Code())
+	}
+	if got := strings.Join(codes, "."); got != "S8" {
+		t.Errorf("UnsafeCards = %s, want S8", got)
+	}
+	if len(evaluation.UnsafeSuits) != 1 || evaluation.UnsafeSuits[0] != skat.Spades {
+		t.Errorf("UnsafeSuits = %v, want [Spades]", evaluation.UnsafeSuits)
+	}
+}
+
+func TestHeuristicAIDeclaresNullOuvert(t *testing.T) {
+	hand, _ := skat.HandFromCode("C7.C8.S7.S8.S9.H7.H8.D7.D8.D9")
+	h := NewHeuristicAI(true)
+
+	if evaluation := EvaluateHand(hand); evaluation.BestGameType != skat.GameNull || evaluation.MaxBid != 59 {
+		t.Errorf("EvaluateHand() = %s up to %d, want Null up to 59", evaluation.BestGameType, evaluation.MaxBid)
+	}
+	if h.DecidePickUpSkat(hand) {
+		t.Error("DecidePickUpSkat() = true, want Hand game")
+	}
+	if contract := h.DecideAnnouncement(hand, 59, true); contract.Code() != "NHO" {
+		t.Errorf("DecideAnnouncement() = %s, want NHO", contract.Code())
+	}
+}
+
+func TestNullDiscards(t *testing.T) {
+	// Spades 8/9 and the lonely heart queen are unsafe
+	hand, _ := skat.HandFromCode("C7.C9.CJ.CK.S8.S9.HQ.D7.D8.D9.DT.DJ")
+	discards := NewHeuristicAI(true).SelectDiscards(hand, skat.GameNull)
+
+	rest := skat.NewHandFromCards(removeCard(removeCard(hand.Cards, discards[0]), discards[1]))
+	if risk := EvaluateNull(rest).Risk; risk != 1 {
+		t.Errorf("SelectDiscards() = %s %s leaves risk %d, want 1", discards[0].Code(), discards[1].Code(), risk)
+	}
+}
+
+func TestNullDeclarerStaysBelow(t *testing.T) {
+	hand, _ := skat.HandFromCode("H7.HT.HA.S7")
+	declarer := skat.Middlehand
+
+	trick := skat.NewTrick(skat.Forehand)
+	trick.AddCard(skat.NewCard(skat.Hearts, skat.Queen), skat.Forehand)
+
+	card := NewHeuristicAI(true).SelectCard(hand, PlayContext{
+		Player:   skat.Middlehand,
+		Declarer: &declarer,
+		GameType: skat.GameNull,
+		Trick:    trick,
+	})
+	if card.Code() != "HT" {
+		t.Errorf("SelectCard() = %s, want HT", card.Code())
+	}
+}
+
+func TestNullDefenderForcesDeclarer(t *testing.T) {
+	hand, _ := skat.HandFromCode("S7.H8.HA")
+	declarerHand, _ := skat.HandFromCode("S8.H7.DA")
+	declarer := skat.Rearhand
+
+	// Ouvert: the declarer's S8 has to take the S7
+	card := NewHeuristicAI(true).SelectCard(hand, PlayContext{
+		Player:       skat.Forehand,
+		Declarer:     &declarer,
+		GameType:     skat.GameNull,
+		Trick:        skat.NewTrick(skat.Forehand),
+		DeclarerHand: declarerHand,
+	})
+	if card.Code() != "S7" {
+		t.Errorf("SelectCard() = %s, want S7", card.Code())
+	}
+
+	// The partner's card is above the declarer's: get rid of a high card
+	trick := skat.NewTrick(skat.Middlehand)
+	trick.AddCard(skat.NewCard(skat.Hearts, skat.King), skat.Middlehand)
+	trick.AddCard(skat.NewCard(skat.Hearts, skat.Queen), skat.Rearhand)
+	hand, _ = skat.HandFromCode("H7.HJ.HA")
+	card = NewHeuristicAI(true).SelectCard(hand, PlayContext{
+		Player:   skat.Forehand,
+		Declarer: &declarer,
+		GameType: skat.GameNull,
+		Trick:    trick,
+	})
+	if card.Code() != "HA" {
+		t.Errorf("SelectCard() = %s, want HA", card.Code())
+	}
+}
+
+// ============================================================================
+// Discard Tests
+// ============================================================================
+
+func TestAdviseDiscardsBuriesUnprotectedTen(t *testing.T) {
+	hand, _ := skat.HandFromCode("CJ.SJ.CA.CT.CK.C9.C8.SA.ST.HT.H7.D7")
+
+	for _, gameType := range []skat.GameType{skat.GameClubs, skat.GameGrand} {
+		discards, rationale := AdviseDiscards(hand, gameType)
+		if discards[0].Code() != "HT" || discards[1].Code() != "H7" {
+			t.Errorf("AdviseDiscards(%s) = %s %s, want HT H7", gameType, discards[0].Code(), discards[1].Code())
+		}
+		if rationale != "bury 10 points, create a void in hearts, keep the matadors" {
+			t.Errorf("AdviseDiscards(%s) rationale = %q", gameType, rationale)
+		}
+	}
+}
+
+func TestAdviseDiscardsKeepsTrumps(t *testing.T) {
+	hand, _ := skat.HandFromCode("CJ.SJ.HJ.DJ.CA.CT.CK.CQ.C9.C8.C7.SA")
+
+	discards, _ := AdviseDiscards(hand, skat.GameClubs)
+	for _, c := range discards {
+		if c.IsJack() || c.IsTrump(skat.GameClubs) && c.Rank != skat.Seven {
+			t.Errorf("AdviseDiscards() discards %s, want no matador but the lowest", c.Code())
+		}
+	}
+
+	hint := HintDiscards(hand, skat.GameClubs)
+	if hint.Move != discards[0].Code()+"."+discards[1].Code() {
+		t.Errorf("HintDiscards() = %s, want the advised cards", hint.Move)
+	}
+}
+
+// ============================================================================
+// Hint Tests
+// ============================================================================
+
+func TestHintCardSmearsOntoPartner(t *testing.T) {
+	hand, err := skat.HandFromCode("HA.H7.SK")
+	if err != nil {
+		t.Fatalf("HandFromCode() error: %v", err)
+	}
+
+	// Clubs game, declarer (Forehand) leads H8, partner (Middlehand) wins with HK
+	trick := skat.NewTrick(skat.Forehand)
+	trick.AddCard(skat.NewCard(skat.Hearts, skat.Eight), skat.Forehand)
+	trick.AddCard(skat.NewCard(skat.Hearts, skat.King), skat.Middlehand)
+
+	declarer := skat.Forehand
+	hint := HintCard(hand, PlayContext{
+		Player:   skat.Rearhand,
+		Declarer: &declarer,
+		GameType: skat.GameClubs,
+		Trick:    trick,
+	})
+
+	if hint.Move != "HA" {
+		t.Errorf("HintCard() = %s, want HA", hint)
+	}
+	if hint.Rationale == "" {
+		t.Error("HintCard() should return a rationale")
+	}
+}
+
+func TestHintTurn(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 20; i++ {
+		deck := skat.NewDeck()
+		deck.ShuffleWith(rng)
+		hands, skatCards, err := skat.DealCards(deck)
+		if err != nil {
+			t.Fatalf("DealCards() error: %v", err)
+		}
+		game := skat.NewGame()
+		if err := game.Deal(hands, skatCards); err != nil {
+			t.Fatalf("Deal() error: %v", err)
+		}
+
+		// Every decision of the bots has a hint that leaves the hand unchanged
+		bot := New(DifficultyStrong, rng)
+		for !game.State.IsFinished() {
+			player := *game.ActivePlayer()
+			cards := game.Hands[player].Code()
+			hint, err := HintTurn(game)
+			if err != nil {
+				t.Fatalf("HintTurn() in state %s error: %v", game.State, err)
+			}
+			if hint.Move == "" || hint.Rationale == "" {
+				t.Errorf("HintTurn() in state %s = %q, want a move and a rationale", game.State, hint)
+			}
+			if got := game.Hands[player].Code(); got != cards {
+				t.Fatalf("HintTurn() changed the hand from %s to %s", cards, got)
+			}
+			if err := PlayTurn(game, bot); err != nil {
+				t.Fatalf("PlayTurn() error: %v", err)
+			}
+		}
+		if _, err := HintTurn(game); !errors.Is(err, ErrNoHint) {
+			t.Errorf("HintTurn() after the game error = %v, want ErrNoHint", err)
+		}
+	}
+}
+
+// ============================================================================
+// External AI Tests
+// ============================================================================
+
+// startFakeEngine runs an engine answering each request with the next answer.
+func startFakeEngine(t *testing.T, answers ...string) (*ExternalAI, <-chan string) {
+	t.Helper()
+
+	requestReader, requestWriter := io.Pipe()
+	answerReader, answerWriter := io.Pipe()
+	requests := make(chan string, len(answers))
+
+	go func() {
+		defer answerWriter.Close()
+		scanner := bufio.NewScanner(requestReader)
+		for _, answer := range answers {
+			if !scanner.Scan() {
+				return
+			}
+			requests <- scanner.Text()
+			fmt.Fprintln(answerWriter, answer)
+		}
+	}()
+	t.Cleanup(func() { requestWriter.Close() })
+
+	return NewExternalAI("fake", answerReader, requestWriter), requests
+}
+
+func TestExternalAISelectCard(t *testing.T) {
+	engine, requests := startFakeEngine(t, "SA")
+	hand, _ := skat.HandFromCode("SA.S7.HA")
+
+	trick := skat.NewTrick(skat.Forehand)
+	trick.AddCard(skat.NewCard(skat.Spades, skat.King), skat.Forehand)
+
+	declarer := skat.Forehand
+	card := engine.SelectCard(hand, PlayContext{
+		Player:   skat.Middlehand,
+		Declarer: &declarer,
+		GameType: skat.GameGrand,
+		Trick:    trick,
+	})
+
+	if card.Code() != "SA" {
+		t.Errorf("SelectCard() = %s, want SA", card.Code())
+	}
+	if got, want := <-requests, "play 1 0 G 0 SK SA.S7.HA"; got != want {
+		t.Errorf("request = %q, want %q", got, want)
+	}
+}
+
+func TestExternalAIFallsBackOnIllegalCard(t *testing.T) {
+	// HA does not follow suit
+	engine, _ := startFakeEngine(t, "HA")
+	hand, _ := skat.HandFromCode("SA.S7.HA")
+
+	trick := skat.NewTrick(skat.Forehand)
+	trick.AddCard(skat.NewCard(skat.Spades, skat.King), skat.Forehand)
+
+	card := engine.SelectCard(hand, PlayContext{
+		Player:   skat.Middlehand,
+		GameType: skat.GameGrand,
+		Trick:    trick,
+	})
+
+	if card.Suit != skat.Spades {
+		t.Errorf("SelectCard() = %s, want a spades card", card.Code())
+	}
+}
+
+func TestExternalAIDecideBid(t *testing.T) {
+	engine, _ := startFakeEngine(t, "20", "19", "y")
+	hand, _ := skat.HandFromCode("SA.SK.SQ")
+
+	decision := engine.DecideBid(hand, BidContext{Player: skat.Middlehand, CurrentBid: 18, Bidding: true})
+	if decision.Action != BidActionBid || decision.Value != 20 {
+		t.Errorf("DecideBid() = %+v, want bid 20", decision)
+	}
+
+	// 19 is no valid bid value, the fallback AI passes with this weak hand
+	decision = engine.DecideBid(hand, BidContext{Player: skat.Middlehand, CurrentBid: 18, Bidding: true})
+	if decision.Action != BidActionPass {
+		t.Errorf("DecideBid() = %+v, want pass", decision)
+	}
+
+	decision = engine.DecideBid(hand, BidContext{Player: skat.Forehand, CurrentBid: 18})
+	if decision.Action != BidActionHold {
+		t.Errorf("DecideBid() = %+v, want hold", decision)
+	}
+}
+
+// ============================================================================
+// Game Play Tests
+// ============================================================================
+
+func TestPlayGameFinishes(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	for i := 0; i < 50; i++ {
+		deck := skat.NewDeck()
+		deck.ShuffleWith(rng)
+		hands, skatCards, err := skat.DealCards(deck)
+		if err != nil {
+			t.Fatalf("DealCards() error: %v", err)
+		}
+		game := skat.NewGame()
+		if err := game.Deal(hands, skatCards); err != nil {
+			t.Fatalf("Deal() error: %v", err)
+		}
+
+		players := map[skat.Player]AIPlayer{
+			skat.Forehand:   New(DifficultyBeginner, rng),
+			skat.Middlehand: New(DifficultyStrong, rng),
+			skat.Rearhand:   NewRandomAI(rng),
+		}
+		if err := PlayGame(game, players); err != nil {
+			t.Fatalf("PlayGame() error: %v", err)
+		}
+		if game.State != skat.StateGameOver {
+			t.Errorf("State = %s, want %s", game.State, skat.StateGameOver)
+		}
+	}
+}
+
+// ============================================================================
+// Deadline Tests
+// ============================================================================
+
+// slowAI is a heuristic AI that needs too long for every card.
+type slowAI struct {
+	*HeuristicAI
+	delay time.Duration
+}
+
+func (s *slowAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	time.Sleep(s.delay)
+	return s.HeuristicAI.SelectCard(hand, ctx)
+}
+
+func TestDeadlineAIFallsBack(t *testing.T) {
+	hand, _ := skat.HandFromCode("SA.S7.HA")
+	trick := skat.NewTrick(skat.Forehand)
+	trick.AddCard(skat.NewCard(skat.Spades, skat.King), skat.Forehand)
+	ctx := PlayContext{Player: skat.Middlehand, GameType: skat.GameGrand, Trick: trick}
+
+	d := NewDeadlineAI(&slowAI{HeuristicAI: NewHeuristicAI(false), delay: time.Second}, 30*time.Millisecond)
+	d.Reserve = 10 * time.Millisecond
+
+	start := time.Now()
+	card := d.SelectCard(hand, ctx)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("SelectCard() took %s, want fallback within the budget", elapsed)
+	}
+	if !card.CanPlay(trick.LeadCard(), hand, skat.GameGrand) {
+		t.Errorf("SelectCard() = %s, not a legal card", card.Code())
+	}
+
+	// Without time left the fallback decides immediately
+	expired, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if card := d.SelectCardContext(expired, hand, ctx); card.Suit != skat.Spades {
+		t.Errorf("SelectCardContext() = %s, want a spades card", card.Code())
+	}
+}
+
+// racyAI counts its concurrent decisions and draws from an unguarded random source
+// like NoisyAI, so overlapping decisions show up as data race with -race.
+type racyAI struct {
+	*HeuristicAI
+	rng     *rand.Rand
+	delay   time.Duration
+	running atomic.Int32
+	overlap atomic.Bool
+}
+
+func (r *racyAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	if r.running.Add(1) > 1 {
+		r.overlap.Store(true)
+	}
+	defer r.running.Add(-1)
+	r.rng.Float64()
+	time.Sleep(r.delay)
+	return r.HeuristicAI.SelectCard(hand, ctx)
+}
+
+func TestDeadlineAIWaitsForBase(t *testing.T) {
+	hand, _ := skat.HandFromCode("SA.S7.HA")
+	trick := skat.NewTrick(skat.Forehand)
+	trick.AddCard(skat.NewCard(skat.Spades, skat.King), skat.Forehand)
+	ctx := PlayContext{Player: skat.Middlehand, GameType: skat.GameGrand, Trick: trick}
+
+	base := &racyAI{HeuristicAI: NewHeuristicAI(false), rng: rand.New(rand.NewSource(1)), delay: 50 * time.Millisecond}
+	d := NewDeadlineAI(base, 10*time.Millisecond)
+	d.Reserve = time.Millisecond
+
+	// The base AI misses every deadline; the decisions after a fallback must not
+	// start it again while it still runs
+	for i := 0; i < 20; i++ {
+		if card := d.SelectCard(hand, ctx); card.Suit != skat.Spades {
+			t.Fatalf("SelectCard() = %s, want a spades card", card.Code())
+		}
+	}
+	if base.overlap.Load() {
+		t.Error("the base AI decided twice at once")
+	}
+}
+
+func TestDeadlineAIWithClock(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    time.Duration
+		remaining time.Duration
+		cards     int
+		want      time.Duration
+	}{
+		{"share of the clock", 0, 11 * time.Second, 10, time.Second},
+		{"last card", 0, 2 * time.Second, 1, time.Second},
+		{"budget shorter than the share", 500 * time.Millisecond, 11 * time.Second, 10, 500 * time.Millisecond},
+		{"share shorter than the budget", 5 * time.Second, 11 * time.Second, 10, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeadlineAI(NewHeuristicAI(false), tt.budget)
+			player, cancel := d.WithClock(tt.remaining, tt.cards)
+			defer cancel()
+			deadline, ok := player.(*contextAI).c.Deadline()
+			if got := time.Until(deadline); !ok || got > tt.want || got < tt.want-100*time.Millisecond {
+				t.Errorf("WithClock() deadline in %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// With the clock run down, the fallback decides at once
+	hand, _ := skat.HandFromCode("SA.S7.HA")
+	trick := skat.NewTrick(skat.Forehand)
+	ctx := PlayContext{Player: skat.Forehand, GameType: skat.GameGrand, Trick: trick}
+	player, cancel := NewDeadlineAI(&slowAI{HeuristicAI: NewHeuristicAI(false), delay: time.Second}, 0).WithClock(0, 3)
+	defer cancel()
+	start := time.Now()
+	player.SelectCard(hand, ctx)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("SelectCard() without time left took %s", elapsed)
+	}
+}
+
+// ============================================================================
+// Match Tests
+// ============================================================================
+
+func TestPlayMatchIdenticalPlayers(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	strong := New(DifficultyStrong, rng)
+
+	result, err := PlayMatch(strong, strong, 5, rng)
+	if err != nil {
+		t.Fatalf("PlayMatch() error: %v", err)
+	}
+	if result.Deals != 5 || result.Games != 30 {
+		t.Errorf("PlayMatch() = %d deals, %d games, want 5, 30", result.Deals, result.Games)
+	}
+	// Deterministic players on duplicate deals score exactly the same
+	if result.Mean != 0 || result.A != result.B || result.Significant() {
+		t.Errorf("PlayMatch() = %+v, want no difference", result)
+	}
+}
+
+func TestSeatPoints(t *testing.T) {
+	lost := &skat.Game{Result: &skat.GameResult{Declarer: skat.Middlehand, Score: -48}}
+	won := &skat.Game{Result: &skat.GameResult{Declarer: skat.Middlehand, DeclarerWon: true, Score: 24}}
+
+	tests := []struct {
+		game     *skat.Game
+		position skat.Player
+		want     int
+	}{
+		{lost, skat.Middlehand, -98},
+		{lost, skat.Forehand, 40},
+		{won, skat.Middlehand, 74},
+		{won, skat.Rearhand, 0},
+	}
+	for _, tt := range tests {
+		if got := SeatPoints(tt.game, tt.position); got != tt.want {
+			t.Errorf("SeatPoints(%s) = %d, want %d", tt.position, got, tt.want)
+		}
+	}
+}
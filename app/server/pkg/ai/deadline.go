@@ -0,0 +1,241 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// DefaultReserve is the time kept back from every deadline for the fast fallback decision.
+const DefaultReserve = 50 * time.Millisecond
+
+// DeadlineAI bounds the decisions of an AI player by a deadline.
+//
+// Each decision runs the base AI until the deadline minus the reserve; if it has not
+// decided by then (or the time is already short), a fast heuristic decides instead,
+// so a bot never loses on time. The base AI works on copies of the hand and trick
+// and may keep running in the background after a fallback; until it has finished, the
+// heuristic makes the following decisions too, so the base AI (and its random source)
+// is never used by two decisions at once.
+type DeadlineAI struct {
+	base AIPlayer
+	fast AIPlayer
+	// busy is held while the base AI decides
+	busy sync.Mutex
+	// Budget is the time per decision used by the plain AIPlayer methods (0 = unlimited)
+	Budget time.Duration
+	// Reserve is the time kept back from every deadline for the fallback decision
+	Reserve time.Duration
+}
+
+// NewDeadlineAI creates an AI that gives base at most budget per decision.
+func NewDeadlineAI(base AIPlayer, budget time.Duration) *DeadlineAI {
+	return &DeadlineAI{
+		base:    base,
+		fast:    NewHeuristicAI(true),
+		Budget:  budget,
+		Reserve: DefaultReserve,
+	}
+}
+
+// Name returns the AI player name.
+func (d *DeadlineAI) Name() string {
+	if d.Budget <= 0 {
+		return d.base.Name()
+	}
+	return fmt.Sprintf("%s (%s per move)", d.base.Name(), d.Budget)
+}
+
+// ClockBudget returns the time for the next decision of a player with the remaining
+// thinking time of the deal and the cards in hand: an equal share for every card still
+// to play and one for the bidding and the announcement.
+func ClockBudget(remaining time.Duration, cards int) time.Duration {
+	return remaining / time.Duration(cards+1)
+}
+
+// WithClock returns the AI player making the next decision of a player with the
+// remaining thinking time of the deal and the cards in hand within the ClockBudget,
+// at most the Budget. The cancel function must be called once the decision is made.
+func (d *DeadlineAI) WithClock(remaining time.Duration, cards int) (AIPlayer, context.CancelFunc) {
+	budget := ClockBudget(remaining, cards)
+	if d.Budget > 0 {
+		budget = min(budget, d.Budget)
+	}
+	c, cancel := context.WithTimeout(context.Background(), budget)
+	return &contextAI{DeadlineAI: d, c: c}, cancel
+}
+
+// contextAI makes the decisions of a DeadlineAI before the deadline of a context.
+type contextAI struct {
+	*DeadlineAI
+	c context.Context
+}
+
+// context returns the context for a decision without an explicit deadline.
+func (d *DeadlineAI) context() (context.Context, context.CancelFunc) {
+	if d.Budget <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), d.Budget)
+}
+
+// decide runs slow until the deadline minus the reserve and falls back to fast afterwards.
+// While a slow decision of an earlier deadline still runs, fast decides at once.
+func decide[T any](ctx context.Context, reserve time.Duration, busy *sync.Mutex, slow, fast func() T) T {
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline && time.Until(deadline) <= reserve || !busy.TryLock() {
+		return fast()
+	}
+
+	result := make(chan T, 1)
+	go func() {
+		defer busy.Unlock()
+		result <- slow()
+	}()
+
+	var timeout <-chan time.Time
+	if hasDeadline {
+		timer := time.NewTimer(time.Until(deadline) - reserve)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case r := <-result:
+		return r
+	case <-timeout:
+		return fast()
+	case <-ctx.Done():
+		return fast()
+	}
+}
+
+// copyHand returns a copy of the hand the background decision can safely work on.
+func copyHand(hand *skat.Hand) *skat.Hand {
+	return skat.NewHandFromCards(append([]skat.Card(nil), hand.Cards...))
+}
+
+// DecideBid decides the bidding action within the budget.
+func (d *DeadlineAI) DecideBid(hand *skat.Hand, ctx BidContext) BidDecision {
+	c, cancel := d.context()
+	defer cancel()
+	return d.DecideBidContext(c, hand, ctx)
+}
+
+// DecideBidContext decides the bidding action before the context's deadline.
+func (d *DeadlineAI) DecideBidContext(c context.Context, hand *skat.Hand, ctx BidContext) BidDecision {
+	handCopy := copyHand(hand)
+	return decide(c, d.Reserve, &d.busy,
+		func() BidDecision { return d.base.DecideBid(handCopy, ctx) },
+		func() BidDecision { return d.fast.DecideBid(hand, ctx) })
+}
+
+// DecidePickUpSkat decides whether to pick up the skat within the budget.
+func (d *DeadlineAI) DecidePickUpSkat(hand *skat.Hand) bool {
+	c, cancel := d.context()
+	defer cancel()
+	return d.DecidePickUpSkatContext(c, hand)
+}
+
+// DecidePickUpSkatContext decides whether to pick up the skat before the context's deadline.
+func (d *DeadlineAI) DecidePickUpSkatContext(c context.Context, hand *skat.Hand) bool {
+	handCopy := copyHand(hand)
+	return decide(c, d.Reserve, &d.busy,
+		func() bool { return d.base.DecidePickUpSkat(handCopy) },
+		func() bool { return d.fast.DecidePickUpSkat(hand) })
+}
+
+// SelectDiscards selects the two cards to discard within the budget.
+func (d *DeadlineAI) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	c, cancel := d.context()
+	defer cancel()
+	return d.SelectDiscardsContext(c, hand, gameType)
+}
+
+// SelectDiscardsContext selects the two cards to discard before the context's deadline.
+func (d *DeadlineAI) SelectDiscardsContext(c context.Context, hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	handCopy := copyHand(hand)
+	return decide(c, d.Reserve, &d.busy,
+		func() [2]skat.Card { return d.base.SelectDiscards(handCopy, gameType) },
+		func() [2]skat.Card { return d.fast.SelectDiscards(hand, gameType) })
+}
+
+// DecideAnnouncement decides the contract to announce within the budget.
+func (d *DeadlineAI) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	c, cancel := d.context()
+	defer cancel()
+	return d.DecideAnnouncementContext(c, hand, bidValue, handGame)
+}
+
+// DecideAnnouncementContext decides the contract to announce before the context's deadline.
+func (d *DeadlineAI) DecideAnnouncementContext(c context.Context, hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	handCopy := copyHand(hand)
+	return decide(c, d.Reserve, &d.busy,
+		func() *skat.Contract { return d.base.DecideAnnouncement(handCopy, bidValue, handGame) },
+		func() *skat.Contract { return d.fast.DecideAnnouncement(hand, bidValue, handGame) })
+}
+
+// SelectCard selects a card to play within the budget.
+func (d *DeadlineAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	c, cancel := d.context()
+	defer cancel()
+	return d.SelectCardContext(c, hand, ctx)
+}
+
+// SelectCardContext selects a card to play before the context's deadline.
+func (d *DeadlineAI) SelectCardContext(c context.Context, hand *skat.Hand, ctx PlayContext) skat.Card {
+	handCopy := copyHand(hand)
+	ctxCopy := ctx
+	trick := *ctx.Trick
+	trick.Cards = append([]skat.TrickCard(nil), ctx.Trick.Cards...)
+	ctxCopy.Trick = &trick
+	if ctx.DeclarerHand != nil {
+		ctxCopy.DeclarerHand = copyHand(ctx.DeclarerHand)
+	}
+
+	return decide(c, d.Reserve, &d.busy,
+		func() skat.Card { return d.base.SelectCard(handCopy, ctxCopy) },
+		func() skat.Card { return d.fast.SelectCard(hand, ctx) })
+}
+
+// DecideBid decides the bidding action before the deadline.
+func (a *contextAI) DecideBid(hand *skat.Hand, ctx BidContext) BidDecision {
+	return a.DecideBidContext(a.c, hand, ctx)
+}
+
+// DecidePickUpSkat decides whether to pick up the skat before the deadline.
+func (a *contextAI) DecidePickUpSkat(hand *skat.Hand) bool {
+	return a.DecidePickUpSkatContext(a.c, hand)
+}
+
+// SelectDiscards selects the two cards to discard before the deadline.
+func (a *contextAI) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	return a.SelectDiscardsContext(a.c, hand, gameType)
+}
+
+// DecideAnnouncement decides the contract to announce before the deadline.
+func (a *contextAI) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	return a.DecideAnnouncementContext(a.c, hand, bidValue, handGame)
+}
+
+// SelectCard selects a card to play before the deadline.
+func (a *contextAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	return a.SelectCardContext(a.c, hand, ctx)
+}
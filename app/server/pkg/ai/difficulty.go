@@ -0,0 +1,170 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Difficulty represents a named AI difficulty level.
+type Difficulty int
+
+const (
+	// DifficultyBeginner - Heuristic play with frequent random mistakes
+	DifficultyBeginner Difficulty = iota
+	// DifficultyClub - Heuristic play with occasional random mistakes
+	DifficultyClub
+	// DifficultyStrong - Heuristic play with partner cooperation and no random mistakes
+	DifficultyStrong
+)
+
+// AllDifficulties contains all difficulty levels from weakest to strongest.
+var AllDifficulties = []Difficulty{DifficultyBeginner, DifficultyClub, DifficultyStrong}
+
+// String returns the name of the difficulty level.
+func (d Difficulty) String() string {
+	switch d {
+	case DifficultyBeginner:
+		return "beginner"
+	case DifficultyClub:
+		return "club"
+	case DifficultyStrong:
+		return "strong"
+	default:
+		return fmt.Sprintf("Difficulty(%d)", d)
+	}
+}
+
+// GermanName returns the German name of the difficulty level.
+func (d Difficulty) GermanName() string {
+	switch d {
+	case DifficultyBeginner:
+		return "Anfänger"
+	case DifficultyClub:
+		return "Vereinsspieler"
+	case DifficultyStrong:
+		return "Stark"
+	default:
+		return fmt.Sprintf("Spielstärke(%d)", d)
+	}
+}
+
+// Noise returns the probability (0.0-1.0) that a decision is replaced by a random legal one.
+func (d Difficulty) Noise() float64 {
+	switch d {
+	case DifficultyBeginner:
+		return 0.3
+	case DifficultyClub:
+		return 0.1
+	default:
+		return 0
+	}
+}
+
+// ParseDifficulty parses a difficulty level from its name.
+func ParseDifficulty(name string) (Difficulty, error) {
+	for _, d := range AllDifficulties {
+		if d.String() == name {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid difficulty: %s", name)
+}
+
+// New creates an AI player for the given difficulty level.
+// If rng is nil, the global random source is used.
+func New(difficulty Difficulty, rng *rand.Rand) AIPlayer {
+	switch difficulty {
+	case DifficultyStrong:
+		return NewHeuristicAI(true)
+	default:
+		return NewNoisyAI(NewHeuristicAI(false), difficulty.Noise(), rng)
+	}
+}
+
+// NewByName creates an AI player from a strategy name: a difficulty level or "random".
+func NewByName(name string, rng *rand.Rand) (AIPlayer, error) {
+	if name == "random" {
+		return NewRandomAI(rng), nil
+	}
+	difficulty, err := ParseDifficulty(name)
+	if err != nil {
+		return nil, err
+	}
+	return New(difficulty, rng), nil
+}
+
+// NoisyAI wraps an AI player and replaces some of its decisions by random ones.
+type NoisyAI struct {
+	base   AIPlayer
+	random *RandomAI
+	noise  float64
+	rng    *rand.Rand
+}
+
+// NewNoisyAI creates an AI that deviates from base with the given probability.
+func NewNoisyAI(base AIPlayer, noise float64, rng *rand.Rand) *NoisyAI {
+	return &NoisyAI{
+		base:   base,
+		random: NewRandomAI(rng),
+		noise:  noise,
+		rng:    rng,
+	}
+}
+
+// Name returns the AI player name.
+func (n *NoisyAI) Name() string {
+	return fmt.Sprintf("%s (%.0f%% noise)", n.base.Name(), n.noise*100)
+}
+
+// pick returns true if the next decision should be a random one.
+func (n *NoisyAI) pick() bool {
+	return n.noise > 0 && float64n(n.rng) < n.noise
+}
+
+// DecideBid decides the bidding action.
+// Bidding is never randomized to avoid absurd contracts; noise only affects card play and discards.
+func (n *NoisyAI) DecideBid(hand *skat.Hand, ctx BidContext) BidDecision {
+	return n.base.DecideBid(hand, ctx)
+}
+
+// DecidePickUpSkat decides whether to pick up the skat.
+func (n *NoisyAI) DecidePickUpSkat(hand *skat.Hand) bool {
+	return n.base.DecidePickUpSkat(hand)
+}
+
+// SelectDiscards selects the two cards to discard to the skat.
+func (n *NoisyAI) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	if n.pick() {
+		return n.random.SelectDiscards(hand, gameType)
+	}
+	return n.base.SelectDiscards(hand, gameType)
+}
+
+// DecideAnnouncement decides the contract to announce.
+func (n *NoisyAI) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	return n.base.DecideAnnouncement(hand, bidValue, handGame)
+}
+
+// SelectCard selects a card to play.
+func (n *NoisyAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	if n.pick() {
+		return n.random.SelectCard(hand, ctx)
+	}
+	return n.base.SelectCard(hand, ctx)
+}
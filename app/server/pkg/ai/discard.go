@@ -0,0 +1,156 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// AdviseDiscards recommends the two cards to discard (drücken) from a 12-card hand for the
+// intended game type and returns a one-line rationale.
+//
+// All pairs are rated by the points buried in the skat and the strength of the remaining
+// hand: trumps and matadors are kept, voids are created and unprotected tens are buried.
+func AdviseDiscards(hand *skat.Hand, gameType skat.GameType) ([2]skat.Card, string) {
+	if gameType.IsNull() {
+		return nullDiscards(hand), "keep the safest Null hand"
+	}
+
+	cards := hand.Cards
+
+	var best [2]skat.Card
+	bestScore := 0
+	found := false
+	for i := 0; i < len(cards); i++ {
+		for j := i + 1; j < len(cards); j++ {
+			remaining := removeCard(removeCard(cards, cards[i]), cards[j])
+			score := cards[i].Points() + cards[j].Points() + keepValue(remaining, gameType)
+			for _, c := range []skat.Card{cards[i], cards[j]} {
+				if isMatador(c, cards, gameType) {
+					score -= 25
+				}
+			}
+			if !found || score > bestScore {
+				best = [2]skat.Card{cards[i], cards[j]}
+				bestScore = score
+				found = true
+			}
+		}
+	}
+	return best, discardRationale(cards, best, gameType)
+}
+
+// keepValue rates the remaining hand after discarding.
+func keepValue(cards []skat.Card, gameType skat.GameType) int {
+	value := 0
+	suitCards := make(map[skat.Suit][]skat.Card)
+	for _, c := range cards {
+		if c.IsTrump(gameType) {
+			value += 12 + c.Points() + trumpStrength(c, gameType) // trumps take their points home
+			continue
+		}
+		suitCards[c.Suit] = append(suitCards[c.Suit], c)
+	}
+
+	for _, suit := range skat.AllSuits {
+		if gameType.IsSuitGame() && skat.GameTypeFromSuit(suit) == gameType {
+			continue
+		}
+		held := suitCards[suit]
+		if len(held) == 0 {
+			value += 6 // void: trump in or smear
+			continue
+		}
+		hasAce, hasTen := false, false
+		for _, c := range held {
+			hasAce = hasAce || c.Rank == skat.Ace
+			hasTen = hasTen || c.Rank == skat.Ten
+		}
+		switch {
+		case hasAce:
+			value += 12
+			if hasTen {
+				value += 11 // the ten comes home behind the ace
+			}
+		case hasTen:
+			value -= 6 // the ten is caught by the ace
+		case len(held) == 1:
+			value -= 3 // a single low card gives the opponents the lead
+		}
+	}
+	return value
+}
+
+// discardRationale explains the advised discards.
+func discardRationale(cards []skat.Card, discards [2]skat.Card, gameType skat.GameType) string {
+	remaining := removeCard(removeCard(cards, discards[0]), discards[1])
+	reasons := []string{}
+
+	if points := discards[0].Points() + discards[1].Points(); points > 0 {
+		reasons = append(reasons, fmt.Sprintf("bury %d points", points))
+	}
+	for _, suit := range skat.AllSuits {
+		before := countSuit(cards, suit, gameType)
+		if before > 0 && countSuit(remaining, suit, gameType) == 0 {
+			reasons = append(reasons, "create a void in "+strings.ToLower(suit.String()))
+		}
+	}
+	if skat.CountMatadors(cards, gameType) > 0 && !isMatador(discards[0], cards, gameType) && !isMatador(discards[1], cards, gameType) {
+		reasons = append(reasons, "keep the matadors")
+	}
+	if len(reasons) == 0 {
+		return "discard the weakest cards"
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// isMatador returns true if the card belongs to the unbroken run of top trumps in cards.
+func isMatador(card skat.Card, cards []skat.Card, gameType skat.GameType) bool {
+	if !card.IsTrump(gameType) {
+		return false
+	}
+	stronger := 0
+	for _, c := range cards {
+		if c.TrumpOrder(gameType) > card.TrumpOrder(gameType) {
+			stronger++
+		}
+	}
+	if card.IsJack() {
+		return stronger == 103-card.TrumpOrder(gameType)
+	}
+	return stronger == 4+7-card.TrumpOrder(gameType)
+}
+
+// trumpStrength returns the strength of a trump from 1 (lowest) to 11 (Jack of Clubs).
+func trumpStrength(c skat.Card, gameType skat.GameType) int {
+	if c.IsJack() {
+		return 8 + c.TrumpOrder(gameType) - 100
+	}
+	return c.TrumpOrder(gameType)
+}
+
+// countSuit returns the number of non-trump cards of a suit.
+func countSuit(cards []skat.Card, suit skat.Suit, gameType skat.GameType) int {
+	n := 0
+	for _, c := range cards {
+		if c.Suit == suit && !c.IsTrump(gameType) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// HandEvaluation is the result of a hand evaluation for bidding decisions.
+type HandEvaluation struct {
+	// MaxBid is the estimated maximum safe bid value
+	MaxBid int
+	// BestGameType is the best game type for this hand
+	BestGameType skat.GameType
+	// TrumpCount is the number of trump cards (including Jacks)
+	TrumpCount int
+	// JackCount is the number of Jacks
+	JackCount int
+	// Matadors is the matador count (positive = with, negative = without)
+	Matadors int
+	// TotalPoints is the total point value of the cards
+	TotalPoints int
+	// Strength is the strength score (0-100)
+	Strength int
+	// RecommendHand is true if playing hand (no skat pickup) is recommended
+	RecommendHand bool
+}
+
+// EvaluateHand evaluates a hand for bidding purposes.
+func EvaluateHand(hand *skat.Hand) HandEvaluation {
+	suitCounts := make(map[skat.Suit]int)
+	suitPoints := make(map[skat.Suit]int)
+	jackCount := 0
+	null := EvaluateNull(hand)
+
+	for _, c := range hand.Cards {
+		if c.IsJack() {
+			jackCount++
+			continue
+		}
+		suitCounts[c.Suit]++
+		suitPoints[c.Suit] += c.Points()
+	}
+
+	// Find best suit for trump (prefer length over points)
+	bestSuit := skat.Clubs
+	bestSuitScore := -1
+	for _, suit := range skat.AllSuits {
+		score := suitCounts[suit]*10 + suitPoints[suit]
+		if score > bestSuitScore {
+			bestSuitScore = score
+			bestSuit = suit
+		}
+	}
+
+	// Determine best game type
+	var bestGameType skat.GameType
+	var trumpCount int
+
+	switch {
+	case null.Safe:
+		// A safe Null hand is worth a Null Hand Ouvert
+		bestGameType = skat.GameNull
+		trumpCount = 0
+	case jackCount >= 3:
+		// With 3+ Jacks, Grand is often best
+		bestGameType = skat.GameGrand
+		trumpCount = jackCount
+	case suitCounts[bestSuit] >= 4, jackCount >= 2:
+		// Long suit or 2 Jacks with decent suits - play suit game
+		bestGameType = skat.GameTypeFromSuit(bestSuit)
+		trumpCount = jackCount + suitCounts[bestSuit]
+	case null.Playable:
+		bestGameType = skat.GameNull
+		trumpCount = 0
+	default:
+		bestGameType = skat.GameTypeFromSuit(bestSuit)
+		trumpCount = jackCount + suitCounts[bestSuit]
+	}
+
+	matadors := skat.CountMatadors(hand.Cards, skat.GameGrand)
+	totalPoints := hand.Points()
+
+	// Calculate strength score (0-100)
+	strength := jackCount*15 + min(trumpCount, 7)*5 + min(totalPoints, 60)/2
+
+	// Calculate max bid
+	maxBid := 0
+	recommendHand := strength >= 60 && trumpCount >= 5
+	if bestGameType.IsNull() {
+		// Null values do not depend on the strength
+		maxBid = nullValue(null)
+		recommendHand = null.Safe
+	} else {
+		maxBid = bestGameType.BaseValue() * (abs(matadors) + 1)
+
+		// Reduce max bid for weak hands
+		if strength < 30 {
+			maxBid = min(maxBid, skat.MinBid)
+		} else if strength < 50 {
+			maxBid = maxBid * 7 / 10
+		}
+	}
+
+	return HandEvaluation{
+		MaxBid:        findClosestBid(maxBid),
+		BestGameType:  bestGameType,
+		TrumpCount:    trumpCount,
+		JackCount:     jackCount,
+		Matadors:      matadors,
+		TotalPoints:   totalPoints,
+		Strength:      strength,
+		RecommendHand: recommendHand,
+	}
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// ExternalAI lets an external engine process play a seat.
+//
+// The engine receives one request line per decision and answers with one line.
+// Cards, hands and contracts use the ISS codes; positions are 0 (Forehand),
+// 1 (Middlehand) and 2 (Rearhand). See docs/AI-BRIDGE.md for the full format:
+//
+//	bid <pos> <current> <bid|respond> <hand>                  -> <value> | y | p
+//	pickup <hand>                                             -> y | n
+//	discard <game> <hand>                                     -> <card>.<card>
+//	announce <bid> <hand|skat> <hand>                         -> <contract>
+//	play <pos> <declarer|-> <game> <leader> <trick|-> <hand>  -> <card>
+//
+// Invalid answers or I/O errors never stall the game: the decision is taken by
+// a built-in fallback AI instead and the problem is logged.
+type ExternalAI struct {
+	name     string
+	reader   *bufio.Reader
+	writer   io.Writer
+	closer   io.Closer
+	fallback AIPlayer
+	mu       sync.Mutex
+}
+
+// NewExternalAI creates an external AI talking over the given reader and writer.
+func NewExternalAI(name string, r io.Reader, w io.Writer) *ExternalAI {
+	return &ExternalAI{
+		name:     name,
+		reader:   bufio.NewReader(r),
+		writer:   w,
+		fallback: NewHeuristicAI(true),
+	}
+}
+
+// StartExternalAI starts an engine process and talks to it over stdin/stdout.
+func StartExternalAI(command string, args ...string) (*ExternalAI, error) {
+	cmd := exec.Command(command, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	e := NewExternalAI(command, stdout, stdin)
+	e.closer = closerFunc(func() error {
+		stdin.Close()
+		return cmd.Wait()
+	})
+	return e, nil
+}
+
+// DialExternalAI connects to an engine listening on a TCP address.
+func DialExternalAI(address string) (*ExternalAI, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewExternalAI(address, conn, conn)
+	e.closer = conn
+	return e, nil
+}
+
+// closerFunc adapts a function to io.Closer.
+type closerFunc func() error
+
+// Close calls the function.
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// Close stops the engine process or closes the connection.
+func (e *ExternalAI) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// Name returns the AI player name.
+func (e *ExternalAI) Name() string {
+	return fmt.Sprintf("External AI (%s)", e.name)
+}
+
+// request sends a request line and returns the trimmed answer line.
+func (e *ExternalAI) request(format string, args ...interface{}) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := fmt.Fprintf(e.writer, format+"\n", args...); err != nil {
+		return "", err
+	}
+
+	line, err := e.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// failed logs an engine failure before the fallback AI takes the decision.
+func (e *ExternalAI) failed(decision string, err error) {
+	log.Printf("[%s] %s failed, using fallback: %v", e.Name(), decision, err)
+}
+
+// DecideBid decides the bidding action.
+func (e *ExternalAI) DecideBid(hand *skat.Hand, ctx BidContext) BidDecision {
+	mode := "respond"
+	if ctx.Bidding {
+		mode = "bid"
+	}
+
+	answer, err := e.request("bid %d %d %s %s", ctx.Player.Index(), ctx.CurrentBid, mode, hand.Code())
+	if err == nil {
+		switch {
+		case answer == "p":
+			return BidDecision{Action: BidActionPass}
+		case answer == "y" && !ctx.Bidding:
+			return BidDecision{Action: BidActionHold}
+		case ctx.Bidding:
+			value, convErr := strconv.Atoi(answer)
+			if convErr == nil && skat.IsValidBid(value) && value > ctx.CurrentBid {
+				return BidDecision{Action: BidActionBid, Value: value}
+			}
+		}
+		err = fmt.Errorf("invalid answer: %q", answer)
+	}
+
+	e.failed("bid", err)
+	return e.fallback.DecideBid(hand, ctx)
+}
+
+// DecidePickUpSkat decides whether to pick up the skat.
+func (e *ExternalAI) DecidePickUpSkat(hand *skat.Hand) bool {
+	answer, err := e.request("pickup %s", hand.Code())
+	if err == nil {
+		switch answer {
+		case "y":
+			return true
+		case "n":
+			return false
+		}
+		err = fmt.Errorf("invalid answer: %q", answer)
+	}
+
+	e.failed("pickup", err)
+	return e.fallback.DecidePickUpSkat(hand)
+}
+
+// SelectDiscards selects the two cards to discard to the skat.
+func (e *ExternalAI) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	answer, err := e.request("discard %s %s", gameType.Code(), hand.Code())
+	if err == nil {
+		var discards *skat.Hand
+		discards, err = skat.HandFromCode(answer)
+		if err == nil {
+			if discards.Size() == 2 && discards.Cards[0] != discards.Cards[1] &&
+				hand.Contains(discards.Cards[0]) && hand.Contains(discards.Cards[1]) {
+				return [2]skat.Card{discards.Cards[0], discards.Cards[1]}
+			}
+			err = fmt.Errorf("invalid answer: %q", answer)
+		}
+	}
+
+	e.failed("discard", err)
+	return e.fallback.SelectDiscards(hand, gameType)
+}
+
+// DecideAnnouncement decides the contract to announce.
+func (e *ExternalAI) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	mode := "skat"
+	if handGame {
+		mode = "hand"
+	}
+
+	answer, err := e.request("announce %d %s %s", bidValue, mode, hand.Code())
+	if err == nil {
+		var contract *skat.Contract
+		contract, err = skat.ContractFromCode(answer)
+		if err == nil {
+			contract.Hand = handGame
+			return contract
+		}
+	}
+
+	e.failed("announce", err)
+	return e.fallback.DecideAnnouncement(hand, bidValue, handGame)
+}
+
+// SelectCard selects a card to play.
+func (e *ExternalAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	declarer := "-"
+	if ctx.Declarer != nil {
+		declarer = strconv.Itoa(ctx.Declarer.Index())
+	}
+	trick := "-"
+	if len(ctx.Trick.Cards) > 0 {
+		trick = ctx.Trick.Code()
+	}
+
+	answer, err := e.request("play %d %s %s %d %s %s",
+		ctx.Player.Index(), declarer, ctx.GameType.Code(), ctx.Trick.Forehand.Index(), trick, hand.Code())
+	if err == nil {
+		var card skat.Card
+		card, err = skat.CardFromCode(answer)
+		if err == nil {
+			if hand.Contains(card) && card.CanPlay(ctx.Trick.LeadCard(), hand, ctx.GameType) {
+				return card
+			}
+			err = fmt.Errorf("illegal card: %s", answer)
+		}
+	}
+
+	e.failed("play", err)
+	return e.fallback.SelectCard(hand, ctx)
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"sort"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// HeuristicAI uses simple heuristics for decision making.
+type HeuristicAI struct {
+	// PartnerPlay enables defender cooperation: smearing points onto a trick
+	// the partner is already winning instead of always playing the lowest card.
+	PartnerPlay bool
+}
+
+// NewHeuristicAI creates a new heuristic AI.
+func NewHeuristicAI(partnerPlay bool) *HeuristicAI {
+	return &HeuristicAI{PartnerPlay: partnerPlay}
+}
+
+// Name returns the AI player name.
+func (h *HeuristicAI) Name() string {
+	return "Heuristic AI"
+}
+
+// DecideBid decides the bidding action.
+func (h *HeuristicAI) DecideBid(hand *skat.Hand, ctx BidContext) BidDecision {
+	evaluation := EvaluateHand(hand)
+
+	if ctx.Bidding {
+		if next := minimumBid(ctx); next <= evaluation.MaxBid {
+			return BidDecision{Action: BidActionBid, Value: next}
+		}
+		return BidDecision{Action: BidActionPass}
+	}
+
+	if ctx.CurrentBid <= evaluation.MaxBid {
+		return BidDecision{Action: BidActionHold}
+	}
+	return BidDecision{Action: BidActionPass}
+}
+
+// DecidePickUpSkat decides whether to pick up the skat.
+func (h *HeuristicAI) DecidePickUpSkat(hand *skat.Hand) bool {
+	// Don't pick up if hand is already strong
+	return !EvaluateHand(hand).RecommendHand
+}
+
+// SelectDiscards selects the two cards to discard to the skat.
+func (h *HeuristicAI) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	discards, _ := AdviseDiscards(hand, gameType)
+	return discards
+}
+
+// DecideAnnouncement decides the contract to announce.
+func (h *HeuristicAI) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	gameType := EvaluateHand(hand).BestGameType
+	if gameType.IsNull() {
+		return nullContract(hand, bidValue, handGame)
+	}
+	contract := skat.NewContract(gameType)
+	contract.Hand = handGame
+	return contract
+}
+
+// SelectCard selects a card to play.
+func (h *HeuristicAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	card, _ := h.chooseCard(hand, ctx)
+	return card
+}
+
+// chooseCard selects a card to play and returns a one-line rationale for the choice.
+func (h *HeuristicAI) chooseCard(hand *skat.Hand, ctx PlayContext) (skat.Card, string) {
+	moves := hand.LegalMoves(ctx.Trick.LeadCard(), ctx.GameType)
+	if len(moves) == 1 {
+		return moves[0], "only legal card"
+	}
+
+	if ctx.GameType.IsNull() {
+		return selectNullCard(hand, moves, ctx)
+	}
+
+	if ctx.Trick.LeadCard() == nil {
+		return h.selectLeadCard(moves, ctx.GameType)
+	}
+	return h.selectFollowCard(moves, ctx)
+}
+
+// selectLeadCard selects a card when leading a trick.
+func (h *HeuristicAI) selectLeadCard(moves []skat.Card, gameType skat.GameType) (skat.Card, string) {
+	// Prefer leading with trump to draw out opponent's trump (if we have some but not too many)
+	trumps := make([]skat.Card, 0, len(moves))
+	for _, c := range moves {
+		if c.IsTrump(gameType) {
+			trumps = append(trumps, c)
+		}
+	}
+	if len(trumps) > 0 && len(trumps) <= 4 {
+		return trumps[0], "lead trump to draw the opponents' trumps"
+	}
+
+	// Lead with Aces (high value, likely to win)
+	for _, c := range moves {
+		if c.Rank == skat.Ace && !c.IsTrump(gameType) {
+			return c, "cash the ace while it still wins"
+		}
+	}
+
+	// Lead with low cards
+	if low, ok := lowestCard(moves, func(c skat.Card) bool { return !c.IsTrump(gameType) }); ok {
+		return low, "lead a low card to give away as few points as possible"
+	}
+	return moves[0], "no better lead available"
+}
+
+// selectFollowCard selects a card when following in a trick.
+func (h *HeuristicAI) selectFollowCard(moves []skat.Card, ctx PlayContext) (skat.Card, string) {
+	winningCard, winner := currentWinner(ctx.Trick, ctx.GameType)
+	leadSuit := ctx.Trick.LeadCard().Suit
+	lastToPlay := len(ctx.Trick.Cards) == 2
+
+	// Partner already wins the trick: smear points if nobody can take it away anymore
+	if h.PartnerPlay && ctx.IsPartner(winner) && lastToPlay && winner != ctx.Player {
+		return highestPoints(moves), "partner wins the trick, add as many points as possible"
+	}
+
+	// Try to win with minimum card
+	winning := make([]skat.Card, 0, len(moves))
+	for _, c := range moves {
+		if c.Beats(winningCard, leadSuit, ctx.GameType) {
+			winning = append(winning, c)
+		}
+	}
+	if len(winning) > 0 && !(h.PartnerPlay && ctx.IsPartner(winner)) {
+		sort.SliceStable(winning, func(i, j int) bool {
+			return cardStrength(winning[i], ctx.GameType) < cardStrength(winning[j], ctx.GameType)
+		})
+		return winning[0], "win the trick with the lowest card that beats " + winningCard.Code()
+	}
+
+	// Can't (or shouldn't) win - play lowest value card
+	low, _ := lowestCard(moves, func(skat.Card) bool { return true })
+	if len(winning) > 0 {
+		return low, "partner already wins the trick, keep your high cards"
+	}
+	return low, "the trick cannot be won, give away as few points as possible"
+}
+
+// currentWinner returns the card currently winning the trick and the player who played it.
+func currentWinner(trick *skat.Trick, gameType skat.GameType) (skat.Card, skat.Player) {
+	best := trick.Cards[trick.WinningIndex(gameType)]
+	return best.Card, best.Player
+}
+
+// cardStrength returns a comparable strength value for a card (trumps above all suit cards).
+func cardStrength(c skat.Card, gameType skat.GameType) int {
+	if c.IsTrump(gameType) {
+		return 100 + c.TrumpOrder(gameType)
+	}
+	return c.SuitOrder(gameType)
+}
+
+// lowestCard returns the card with the fewest points matching the filter.
+func lowestCard(cards []skat.Card, filter func(skat.Card) bool) (skat.Card, bool) {
+	found := false
+	var low skat.Card
+	for _, c := range cards {
+		if !filter(c) {
+			continue
+		}
+		if !found || c.Points() < low.Points() {
+			low = c
+			found = true
+		}
+	}
+	return low, found
+}
+
+// highestPoints returns the card with the most points, preferring non-Jacks.
+func highestPoints(cards []skat.Card) skat.Card {
+	best := cards[0]
+	for _, c := range cards[1:] {
+		if best.IsJack() && !c.IsJack() || c.Points() > best.Points() && (!c.IsJack() || best.IsJack()) {
+			best = c
+		}
+	}
+	return best
+}
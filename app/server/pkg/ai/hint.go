@@ -0,0 +1,141 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Hint is a suggested move for a human player with a one-line rationale.
+type Hint struct {
+	// Move is the suggested move as ISS protocol token (e.g. "CJ", "18", "p")
+	Move string
+	// Rationale explains the suggestion in one line
+	Rationale string
+}
+
+// String returns the hint as "<move>: <rationale>".
+func (h Hint) String() string {
+	return fmt.Sprintf("%s: %s", h.Move, h.Rationale)
+}
+
+// hintPlayer is the AI used for hints (strongest available heuristic, no noise).
+var hintPlayer = NewHeuristicAI(true)
+
+// HintCard suggests a legal card to play.
+func HintCard(hand *skat.Hand, ctx PlayContext) Hint {
+	card, rationale := hintPlayer.chooseCard(hand, ctx)
+	return Hint{Move: card.Code(), Rationale: rationale}
+}
+
+// HintDiscards suggests the two cards to discard after picking up the skat (e.g. "CT.HT").
+func HintDiscards(hand *skat.Hand, gameType skat.GameType) Hint {
+	discards, rationale := AdviseDiscards(hand, gameType)
+	return Hint{Move: discards[0].Code() + "." + discards[1].Code(), Rationale: rationale}
+}
+
+// HintBid suggests a bidding move.
+func HintBid(hand *skat.Hand, ctx BidContext) Hint {
+	evaluation := EvaluateHand(hand)
+	decision := hintPlayer.DecideBid(hand, ctx)
+	reason := fmt.Sprintf("hand is worth about %d as %s", evaluation.MaxBid, evaluation.BestGameType)
+
+	switch decision.Action {
+	case BidActionBid:
+		return Hint{Move: fmt.Sprintf("%d", decision.Value), Rationale: reason}
+	case BidActionHold:
+		return Hint{Move: "y", Rationale: reason}
+	default:
+		return Hint{Move: "p", Rationale: reason + ", too weak for the current bid"}
+	}
+}
+
+// ErrNoHint is returned by HintTurn for decisions without hints, e.g. answering a claim.
+var ErrNoHint = errors.New("no hint for this decision")
+
+// HintTurn suggests the move of the active player of a game: a bid, picking up the
+// skat or playing Hand, the announcement with the discards or a card.
+func HintTurn(game *skat.Game) (Hint, error) {
+	player := game.ActivePlayer()
+	if player == nil || game.PendingClaim != nil || game.Abandoned != nil {
+		return Hint{}, ErrNoHint
+	}
+	hand := game.Hands[*player]
+	evaluation := EvaluateHand(hand)
+	worth := fmt.Sprintf("hand is worth about %d as %s", evaluation.MaxBid, evaluation.BestGameType)
+
+	switch game.State {
+	case skat.StateBidding:
+		return HintBid(hand, BidContext{
+			Player:     *player,
+			CurrentBid: game.Bidding.CurrentBid,
+			Bidding:    game.Bidding.IsActiveBidding,
+		}), nil
+	case skat.StatePickingUpSkat:
+		if game.GrandHandOffer {
+			if contract := hintPlayer.DecideAnnouncement(hand, 0, true); contract != nil && contract.GameType == skat.GameGrand {
+				return Hint{Move: contract.Code(), Rationale: worth + ", strong enough for a Grand Hand"}, nil
+			}
+			return Hint{Move: "p", Rationale: worth + ", too weak for a Grand Hand"}, nil
+		}
+		if hintPlayer.DecidePickUpSkat(hand) {
+			return Hint{Move: "s", Rationale: worth + ", the skat may improve it"}, nil
+		}
+		contract := hintPlayer.DecideAnnouncement(hand, game.Bidding.FinalBid, true)
+		return Hint{Move: announcementMove(contract, nil, hand), Rationale: worth + ", strong enough without the skat"}, nil
+	case skat.StateDiscarding:
+		discards, rationale := AdviseDiscards(hand, evaluation.BestGameType)
+		rest := copyHand(hand)
+		rest.Remove(discards[0])
+		rest.Remove(discards[1])
+		contract := hintPlayer.DecideAnnouncement(rest, game.Bidding.FinalBid, false)
+		return Hint{Move: announcementMove(contract, discards[:], rest), Rationale: rationale}, nil
+	case skat.StateDeclaring:
+		contract := hintPlayer.DecideAnnouncement(hand, game.Bidding.FinalBid, false)
+		return Hint{Move: announcementMove(contract, nil, hand), Rationale: worth}, nil
+	case skat.StateTrickPlaying:
+		ctx := PlayContext{
+			Player:   *player,
+			Declarer: game.Declarer,
+			GameType: game.Contract.GameType,
+			Trick:    game.Trick,
+		}
+		if game.Contract.Ouvert && *player != *game.Declarer {
+			ctx.DeclarerHand = game.Hands[*game.Declarer]
+		}
+		return HintCard(hand, ctx), nil
+	default:
+		return Hint{}, ErrNoHint
+	}
+}
+
+// announcementMove returns the ISS token of an announcement: the contract, the
+// discards and the cards of an Ouvert game (e.g. "G.C7.D8").
+func announcementMove(contract *skat.Contract, discards []skat.Card, hand *skat.Hand) string {
+	parts := []string{contract.Code()}
+	for _, c := range discards {
+		parts = append(parts, c.Code())
+	}
+	if contract.Ouvert {
+		for _, c := range hand.Cards {
+			parts = append(parts, c.Code())
+		}
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// MatchSide holds the statistics of one AI player in a duplicate match.
+type MatchSide struct {
+	// Points is the sum of the seat points over all games the player sat alone
+	Points int
+	// Declared is the number of games the player declared
+	Declared int
+	// Won is the number of declared games the player won
+	Won int
+}
+
+// MatchResult is the result of a duplicate match between two AI players.
+type MatchResult struct {
+	// Deals is the number of deals played
+	Deals int
+	// Games is the number of games played (6 per deal)
+	Games int
+	// A and B are the statistics of both players
+	A, B MatchSide
+	// Mean is the average seat point difference A - B per game
+	Mean float64
+	// StdErr is the standard error of Mean
+	StdErr float64
+
+	m2 float64
+}
+
+// Interval returns the 95% confidence interval of Mean.
+func (r *MatchResult) Interval() (float64, float64) {
+	return r.Mean - 1.96*r.StdErr, r.Mean + 1.96*r.StdErr
+}
+
+// Significant returns true if the 95% confidence interval does not contain 0.
+func (r *MatchResult) Significant() bool {
+	low, high := r.Interval()
+	return low > 0 || high < 0
+}
+
+// add adds the point difference of a deal (Welford's online algorithm).
+func (r *MatchResult) add(diff float64) {
+	r.Deals++
+	delta := diff - r.Mean
+	r.Mean += delta / float64(r.Deals)
+	r.m2 += delta * (diff - r.Mean)
+	if r.Deals > 1 {
+		r.StdErr = math.Sqrt(r.m2 / float64(r.Deals-1) / float64(r.Deals))
+	}
+}
+
+// PlayMatch plays a duplicate match between a and b over the given number of deals.
+//
+// Every deal is played six times: at each position once with a alone against two
+// copies of b and once the other way round. Comparing the seat points of a and b
+// at the same position with the same cards removes most of the luck of the deal.
+func PlayMatch(a, b AIPlayer, deals int, rng *rand.Rand) (*MatchResult, error) {
+	result := &MatchResult{}
+
+	for n := 0; n < deals; n++ {
+		deck := skat.NewDeck()
+		deck.ShuffleWith(rng)
+		hands, skatCards, err := skat.DealCards(deck)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := 0
+		for _, position := range skat.AllPlayers {
+			pointsA, err := playMatchGame(hands, skatCards, position, a, b, &result.A)
+			if err != nil {
+				return nil, err
+			}
+			pointsB, err := playMatchGame(hands, skatCards, position, b, a, &result.B)
+			if err != nil {
+				return nil, err
+			}
+			diff += pointsA - pointsB
+			result.Games += 2
+		}
+		result.add(float64(diff) / float64(len(skat.AllPlayers)))
+	}
+	return result, nil
+}
+
+// playMatchGame plays a deal with player alone at the position and the opponent at the
+// other seats and returns the seat points of the position.
+func playMatchGame(hands map[skat.Player]*skat.Hand, skatCards *skat.Hand, position skat.Player, player, opponent AIPlayer, side *MatchSide) (int, error) {
+	game := skat.NewGame()
+	if err := game.Deal(hands, skatCards); err != nil {
+		return 0, err
+	}
+
+	players := make(map[skat.Player]AIPlayer)
+	for _, p := range skat.AllPlayers {
+		players[p] = opponent
+	}
+	players[position] = player
+
+	if err := PlayGame(game, players); err != nil {
+		return 0, err
+	}
+
+	points := SeatPoints(game, position)
+	side.Points += points
+	if game.Result != nil && game.Result.Declarer == position {
+		side.Declared++
+		if game.Result.DeclarerWon {
+			side.Won++
+		}
+	}
+	return points, nil
+}
+
+// SeatPoints returns the Seeger-Fabian points of a position in a finished game: the
+// declarer scores the game value plus 50 if won or twice the value plus 50 negative if
+// lost, each defender gets 40 if the declarer loses. In Ramsch each loser's score counts.
+func SeatPoints(game *skat.Game, position skat.Player) int {
+	if result := game.RamschResult; result != nil {
+		return result.Score(position)
+	}
+
+	result := game.Result
+	if result == nil {
+		return 0
+	}
+	switch {
+	case result.Declarer == position && result.DeclarerWon:
+		return result.Score + 50
+	case result.Declarer == position:
+		return result.Score - 50
+	case !result.DeclarerWon:
+		return 40
+	default:
+		return 0
+	}
+}
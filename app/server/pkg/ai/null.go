@@ -0,0 +1,276 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"sort"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// NullEvaluation is the result of a hand evaluation for Null games.
+type NullEvaluation struct {
+	// Risk is the number of cards the declarer can be forced to take a trick with
+	Risk int
+	// UnsafeSuits are the suits containing such cards
+	UnsafeSuits []skat.Suit
+	// UnsafeCards are the cards the declarer can be forced to take a trick with
+	UnsafeCards []skat.Card
+	// Safe is true if no card can be forced to take a trick (Hand and Ouvert are possible)
+	Safe bool
+	// Playable is true if the unsafe cards can be discarded after picking up the skat
+	Playable bool
+}
+
+// EvaluateNull evaluates the safety of a hand for Null games.
+//
+// A suit is safe if its n-th lowest card (counting from 0) is at most the 2n-th lowest
+// card of the suit (7, 9, J, K): every time the suit is led, the declarer can stay
+// below the card of an opponent.
+func EvaluateNull(hand *skat.Hand) NullEvaluation {
+	unsafeCards, unsafe := nullRisk(hand.Cards)
+	return NullEvaluation{
+		Risk:        len(unsafeCards),
+		UnsafeSuits: unsafe,
+		UnsafeCards: unsafeCards,
+		Safe:        len(unsafeCards) == 0,
+		Playable:    len(unsafeCards) <= 2,
+	}
+}
+
+// nullRisk returns the unsafe cards, lowest first per suit, and the suits containing them.
+func nullRisk(cards []skat.Card) ([]skat.Card, []skat.Suit) {
+	var risky []skat.Card
+	var unsafe []skat.Suit
+	for _, suit := range skat.AllSuits {
+		suitCards := make([]skat.Card, 0, len(cards))
+		for _, c := range cards {
+			if c.Suit == suit {
+				suitCards = append(suitCards, c)
+			}
+		}
+		sort.Slice(suitCards, func(i, j int) bool {
+			return nullPosition(suitCards[i]) < nullPosition(suitCards[j])
+		})
+
+		suitRisk := 0
+		for i, c := range suitCards {
+			if nullPosition(c) > 2*i {
+				risky = append(risky, c)
+				suitRisk++
+			}
+		}
+		if suitRisk > 0 {
+			unsafe = append(unsafe, suit)
+		}
+	}
+	return risky, unsafe
+}
+
+// nullPosition returns the position of a card within its suit in Null order (7 = 0, A = 7).
+func nullPosition(c skat.Card) int {
+	return c.SuitOrder(skat.GameNull) - 1
+}
+
+// nullValue returns the maximum bid value of a Null hand.
+func nullValue(evaluation NullEvaluation) int {
+	contract := skat.NewContract(skat.GameNull)
+	contract.Hand = evaluation.Safe
+	contract.Ouvert = evaluation.Safe
+	return contract.BaseValue()
+}
+
+// nullContract returns the Null contract to announce: Ouvert if the hand is safe or the
+// bid cannot be reached otherwise.
+func nullContract(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	contract := skat.NewContract(skat.GameNull)
+	contract.Hand = handGame
+	if EvaluateNull(hand).Safe || contract.BaseValue() < bidValue {
+		contract.Ouvert = true
+	}
+	return contract
+}
+
+// nullDiscards selects the two cards that leave the safest Null hand.
+func nullDiscards(hand *skat.Hand) [2]skat.Card {
+	remaining := append([]skat.Card(nil), hand.Cards...)
+	var discards [2]skat.Card
+	for i := range discards {
+		discards[i] = mostDangerousCard(remaining)
+		remaining = removeCard(remaining, discards[i])
+	}
+	return discards
+}
+
+// mostDangerousCard returns the card whose removal reduces the Null risk the most.
+// Ties prefer high cards and short suits (creating voids).
+func mostDangerousCard(cards []skat.Card) skat.Card {
+	suitCounts := make(map[skat.Suit]int)
+	for _, c := range cards {
+		suitCounts[c.Suit]++
+	}
+
+	best := cards[0]
+	bestRisk := -1
+	for _, c := range cards {
+		unsafe, _ := nullRisk(removeCard(cards, c))
+		risk := len(unsafe)
+		better := bestRisk < 0 || risk < bestRisk
+		if risk == bestRisk {
+			if nullPosition(c) != nullPosition(best) {
+				better = nullPosition(c) > nullPosition(best)
+			} else {
+				better = suitCounts[c.Suit] < suitCounts[best.Suit]
+			}
+		}
+		if better {
+			best = c
+			bestRisk = risk
+		}
+	}
+	return best
+}
+
+// removeCard returns a copy of cards without the given card.
+func removeCard(cards []skat.Card, card skat.Card) []skat.Card {
+	result := make([]skat.Card, 0, len(cards))
+	for _, c := range cards {
+		if c != card {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// selectNullCard selects a card in a Null game and returns a one-line rationale for the choice.
+func selectNullCard(hand *skat.Hand, moves []skat.Card, ctx PlayContext) (skat.Card, string) {
+	if ctx.IsDeclarer() {
+		return selectNullDeclarerCard(hand, moves, ctx)
+	}
+	return selectNullDefenderCard(moves, ctx)
+}
+
+// selectNullDeclarerCard selects a card for the declarer, who must not take any trick.
+func selectNullDeclarerCard(hand *skat.Hand, moves []skat.Card, ctx PlayContext) (skat.Card, string) {
+	if ctx.Trick.LeadCard() == nil {
+		// Lead the card with the fewest lower cards held by the opponents
+		best := moves[0]
+		bestLower := -1
+		for _, c := range moves {
+			lower := nullPosition(c)
+			for _, own := range hand.Cards {
+				if own.Suit == c.Suit && nullPosition(own) < nullPosition(c) {
+					lower--
+				}
+			}
+			if bestLower < 0 || lower < bestLower || lower == bestLower && nullPosition(c) > nullPosition(best) {
+				best = c
+				bestLower = lower
+			}
+		}
+		return best, "lead a card the opponents can hardly stay below"
+	}
+
+	winningCard, _ := currentWinner(ctx.Trick, ctx.GameType)
+	if moves[0].Suit != ctx.Trick.LeadCard().Suit {
+		return mostDangerousCard(moves), "discard the most dangerous card"
+	}
+
+	if below, ok := highestBelow(moves, winningCard); ok {
+		return below, "stay below " + winningCard.Code() + " with the highest possible card"
+	}
+	low := moves[0]
+	for _, c := range moves[1:] {
+		if nullPosition(c) < nullPosition(low) {
+			low = c
+		}
+	}
+	return low, "cannot stay below " + winningCard.Code() + ", hope a defender has to go over"
+}
+
+// selectNullDefenderCard selects a card for a defender, who tries to force the declarer into a trick.
+func selectNullDefenderCard(moves []skat.Card, ctx PlayContext) (skat.Card, string) {
+	lead := ctx.Trick.LeadCard()
+	if lead == nil {
+		if ctx.DeclarerHand != nil {
+			// Ouvert: lead below all of the declarer's cards of a suit
+			for _, c := range lowestFirst(moves) {
+				if forcesDeclarer(c, ctx.DeclarerHand) {
+					return c, "the declarer has no card below " + c.Code()
+				}
+			}
+		}
+		return lowestFirst(moves)[0], "lead low, the declarer has to stay below"
+	}
+
+	winningCard, winner := currentWinner(ctx.Trick, ctx.GameType)
+	declarerPlayed := false
+	for _, tc := range ctx.Trick.Cards {
+		if tc.Player == *ctx.Declarer {
+			declarerPlayed = true
+		}
+	}
+
+	if !declarerPlayed {
+		return lowestFirst(moves)[0], "play low, the declarer has to stay below"
+	}
+	if winner == *ctx.Declarer {
+		if below, ok := highestBelow(moves, winningCard); ok {
+			return below, "leave the trick to the declarer"
+		}
+	}
+	moves = lowestFirst(moves)
+	return moves[len(moves)-1], "get rid of a high card"
+}
+
+// forcesDeclarer returns true if the declarer holds cards of the suit, but none below the card.
+func forcesDeclarer(card skat.Card, declarerHand *skat.Hand) bool {
+	hasSuit := false
+	for _, c := range declarerHand.Cards {
+		if c.Suit != card.Suit {
+			continue
+		}
+		if nullPosition(c) < nullPosition(card) {
+			return false
+		}
+		hasSuit = true
+	}
+	return hasSuit
+}
+
+// highestBelow returns the highest card of the same suit below the given card.
+func highestBelow(cards []skat.Card, card skat.Card) (skat.Card, bool) {
+	found := false
+	var best skat.Card
+	for _, c := range cards {
+		if c.Suit != card.Suit || nullPosition(c) >= nullPosition(card) {
+			continue
+		}
+		if !found || nullPosition(c) > nullPosition(best) {
+			best = c
+			found = true
+		}
+	}
+	return best, found
+}
+
+// lowestFirst returns a copy of the cards sorted by Null position, lowest first.
+func lowestFirst(cards []skat.Card) []skat.Card {
+	sorted := append([]skat.Card(nil), cards...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return nullPosition(sorted[i]) < nullPosition(sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// PlayGame plays a dealt game to the end with the given AI players. The engine
+// calculates the result with the last trick.
+func PlayGame(game *skat.Game, players map[skat.Player]AIPlayer) error {
+	for !game.State.IsFinished() {
+		player := game.ActivePlayer()
+		if player == nil {
+			return fmt.Errorf("no active player in state %s", game.State)
+		}
+		if err := playTurn(game, *player, players[*player]); err != nil {
+			return fmt.Errorf("%s (%s): %w", *player, players[*player].Name(), err)
+		}
+	}
+	return nil
+}
+
+// PlayTurn lets the bot make the next decision of the active player.
+func PlayTurn(game *skat.Game, bot AIPlayer) error {
+	player := game.ActivePlayer()
+	if player == nil {
+		return fmt.Errorf("no active player in state %s", game.State)
+	}
+	return playTurn(game, *player, bot)
+}
+
+// playTurn lets the active player make a single decision.
+func playTurn(game *skat.Game, player skat.Player, bot AIPlayer) error {
+	hand := game.Hands[player]
+	if game.PendingClaim != nil {
+		// Bots do not verify claims: they reject them and play on
+		return game.RejectClaim(player)
+	}
+	if game.Abandoned != nil {
+		// Nor do they claim the tricks of an abandoned game: they replay the deal
+		return game.Redeal(player)
+	}
+
+	switch game.State {
+	case skat.StateBidding:
+		return bidTurn(game, player, bot)
+	case skat.StatePickingUpSkat:
+		if game.GrandHandOffer {
+			return grandHandOffer(game, player, bot)
+		}
+		if bot.DecidePickUpSkat(hand) {
+			return game.PickUpSkat(player)
+		}
+		return announce(game, player, bot.DecideAnnouncement(hand, game.Bidding.FinalBid, true))
+	case skat.StateDiscarding:
+		discards := bot.SelectDiscards(hand, EvaluateHand(hand).BestGameType)
+		return game.Discard(player, discards[0], discards[1])
+	case skat.StateDeclaring:
+		return announce(game, player, bot.DecideAnnouncement(hand, game.Bidding.FinalBid, false))
+	case skat.StateTrickPlaying:
+		ctx := PlayContext{
+			Player:   player,
+			Declarer: game.Declarer,
+			GameType: game.Contract.GameType,
+			Trick:    game.Trick,
+		}
+		if game.Contract.Ouvert && player != *game.Declarer {
+			ctx.DeclarerHand = game.Hands[*game.Declarer]
+		}
+		card := bot.SelectCard(hand, ctx)
+		return game.PlayCard(player, card)
+	default:
+		return fmt.Errorf("unexpected state %s", game.State)
+	}
+}
+
+// announce announces the contract of the bot. If the table refuses it as overbid, the
+// bot announces the suit or Grand game with the highest value instead.
+func announce(game *skat.Game, player skat.Player, contract *skat.Contract) error {
+	err := game.Announce(player, contract)
+	if !errors.Is(err, skat.ErrOverbid) {
+		return err
+	}
+	cards := append(append([]skat.Card(nil), game.Hands[player].Cards...), game.Skat.Cards...)
+	best, value := contract, 0
+	for _, gameType := range append([]skat.GameType{skat.GameGrand}, skat.SuitGameTypes...) {
+		other := skat.NewContract(gameType)
+		other.Hand = game.State == skat.StatePickingUpSkat
+		if v := skat.MaxGameValue(*other, cards); v > value {
+			best, value = other, v
+		}
+	}
+	return game.Announce(player, best)
+}
+
+// grandHandOffer lets Forehand play the Grand Hand offered instead of the Ramsch if the
+// bot would announce a Grand as Hand game; otherwise it passes and the Ramsch is played.
+func grandHandOffer(game *skat.Game, player skat.Player, bot AIPlayer) error {
+	if contract := bot.DecideAnnouncement(game.Hands[player], 0, true); contract != nil && contract.GameType == skat.GameGrand {
+		return game.Announce(player, skat.NewContract(skat.GameGrand))
+	}
+	return game.Pass(player)
+}
+
+// bidTurn lets the active player bid, hold or pass. Invalid bid decisions count as pass.
+func bidTurn(game *skat.Game, player skat.Player, bot AIPlayer) error {
+	bidding := game.Bidding
+	decision := bot.DecideBid(game.Hands[player], BidContext{
+		Player:     player,
+		CurrentBid: bidding.CurrentBid,
+		Bidding:    bidding.IsActiveBidding,
+	})
+
+	switch {
+	case decision.Action == BidActionBid && bidding.IsActiveBidding &&
+		skat.IsValidBid(decision.Value) && decision.Value > bidding.CurrentBid:
+		return game.Bid(player, decision.Value)
+	case decision.Action == BidActionHold && !bidding.IsActiveBidding:
+		return game.Hold(player)
+	default:
+		return game.Pass(player)
+	}
+}
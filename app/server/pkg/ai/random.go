@@ -0,0 +1,85 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ai
+
+import (
+	"math/rand"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// RandomAI makes random legal moves.
+type RandomAI struct {
+	rng *rand.Rand
+}
+
+// NewRandomAI creates a new random AI. If rng is nil, the global random source is used.
+func NewRandomAI(rng *rand.Rand) *RandomAI {
+	return &RandomAI{rng: rng}
+}
+
+// Name returns the AI player name.
+func (r *RandomAI) Name() string {
+	return "Random AI"
+}
+
+// DecideBid decides the bidding action.
+func (r *RandomAI) DecideBid(hand *skat.Hand, ctx BidContext) BidDecision {
+	// 50% chance to pass
+	if float64n(r.rng) < 0.5 {
+		return BidDecision{Action: BidActionPass}
+	}
+
+	if ctx.Bidding {
+		// Only bid low values randomly
+		if next := minimumBid(ctx); next <= 24 {
+			return BidDecision{Action: BidActionBid, Value: next}
+		}
+		return BidDecision{Action: BidActionPass}
+	}
+
+	if ctx.CurrentBid <= skat.MinBid {
+		return BidDecision{Action: BidActionHold}
+	}
+	return BidDecision{Action: BidActionPass}
+}
+
+// DecidePickUpSkat decides whether to pick up the skat.
+func (r *RandomAI) DecidePickUpSkat(hand *skat.Hand) bool {
+	return float64n(r.rng) < 0.7 // 70% pickup
+}
+
+// SelectDiscards selects the two cards to discard to the skat.
+func (r *RandomAI) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	sorted := make([]skat.Card, len(hand.Cards))
+	copy(sorted, hand.Cards)
+	skat.SortForGame(sorted, gameType)
+
+	// Discard last two cards (weakest after sorting)
+	return [2]skat.Card{sorted[len(sorted)-1], sorted[len(sorted)-2]}
+}
+
+// DecideAnnouncement decides the contract to announce.
+func (r *RandomAI) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	contract := skat.NewContract(EvaluateHand(hand).BestGameType)
+	contract.Hand = handGame
+	return contract
+}
+
+// SelectCard selects a card to play.
+func (r *RandomAI) SelectCard(hand *skat.Hand, ctx PlayContext) skat.Card {
+	moves := hand.LegalMoves(ctx.Trick.LeadCard(), ctx.GameType)
+	return moves[intn(r.rng, len(moves))]
+}
@@ -0,0 +1,361 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package botkit runs an AI player as bot on ISS-compatible Skat servers. It connects
+// and logs in, takes a seat (creating or joining a table, or the deal of the day),
+// tracks the games, asks the AI player for its moves and reconnects after lost
+// connections, so a bot author only implements the ai.AIPlayer interface:
+//
+//	type myAI struct{ ... } // implements ai.AIPlayer
+//
+//	bot := botkit.New(botkit.Config{
+//		Address:   "localhost:7000",
+//		Login:     "mybot",
+//		Password:  "secret",
+//		Table:     "practice", // "" = create a table
+//		Reconnect: time.Second,
+//	}, &myAI{})
+//	err := bot.Run(ctx) // until ctx is done, the games are played or the table closes
+//
+// The AI player is called from a single goroutine, one decision at a time. Its cards
+// are the bot's own cards only; the other hands are never revealed to it.
+package botkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+)
+
+// DefaultMaxReconnect is the longest delay between two reconnection attempts if
+// Config.MaxReconnect is not set.
+const DefaultMaxReconnect = time.Minute
+
+// ErrSeat is returned when the bot cannot take its seat, e.g. the table does not exist
+// or the deal of the day has been played.
+var ErrSeat = errors.New("no seat")
+
+// Config configures a bot.
+type Config struct {
+	// Address is the host:port of the server
+	Address string
+	// Dial connects to the server instead of Address, e.g. to an in-memory test server
+	// of package skattest (nil = client.Dial of Address)
+	Dial func() (*client.Client, error)
+	// Login and Password are the credentials of the bot
+	Login    string
+	Password string
+	// Table is the table to join ("" = create a table of Size players)
+	Table string
+	// Size is the number of players of created tables (0 = 3)
+	Size int
+	// Daily plays the deal of the day against the server bots instead of a table
+	// (one game per day)
+	Daily bool
+	// Games is the number of games to play before leaving the table (0 = unlimited)
+	Games int
+	// Reconnect is the delay before the first reconnection attempt after a lost
+	// connection; it doubles with every failed attempt up to MaxReconnect (0 = the
+	// bot stops when the connection is lost)
+	Reconnect time.Duration
+	// MaxReconnect is the longest delay between two attempts (0 = DefaultMaxReconnect)
+	MaxReconnect time.Duration
+	// Logf logs the progress of the bot (nil = the standard logger)
+	Logf func(format string, args ...any)
+}
+
+// Bot is a bot playing with an AI player. Create it with New and start it with Run.
+type Bot struct {
+	config Config
+	player ai.AIPlayer
+
+	// table is the table of the bot, kept for rejoining after a reconnect
+	table  string
+	game   *game
+	ready  bool
+	played int
+	// failure ends the session (nil = the connection was lost)
+	failure error
+	done    bool
+
+	c  *client.Client
+	mu sync.Mutex
+}
+
+// New creates a bot playing with the AI player.
+func New(config Config, player ai.AIPlayer) *Bot {
+	if config.Size == 0 {
+		config.Size = 3
+	}
+	if config.Daily {
+		config.Games = 1
+	}
+	if config.MaxReconnect == 0 {
+		config.MaxReconnect = DefaultMaxReconnect
+	}
+	if config.Logf == nil {
+		config.Logf = log.Printf
+	}
+	if config.Dial == nil {
+		address := config.Address
+		config.Dial = func() (*client.Client, error) { return client.Dial(address) }
+	}
+	return &Bot{config: config, player: player}
+}
+
+// Played returns the number of finished games.
+func (b *Bot) Played() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.played
+}
+
+// Run plays until the context is done (the bot leaves its table), the configured games
+// are played or the table is closed, which return nil. Lost connections are
+// reestablished if Config.Reconnect is set; a rejected login and a seat the bot cannot
+// take (ErrSeat) end the bot with an error.
+func (b *Bot) Run(ctx context.Context) error {
+	delay := b.config.Reconnect
+	for {
+		seated, err := b.session(ctx)
+		b.mu.Lock()
+		done := b.done
+		b.mu.Unlock()
+		switch {
+		case ctx.Err() != nil || done:
+			return nil
+		case errors.Is(err, client.ErrLogin) || errors.Is(err, ErrSeat):
+			return err
+		case b.config.Reconnect <= 0:
+			return fmt.Errorf("connection lost: %w", err)
+		}
+		if seated {
+			// The connection worked, so the next attempt starts with the first delay
+			delay = b.config.Reconnect
+		}
+
+		b.config.Logf("[%s] Connection lost (%v), reconnecting in %s", b.config.Login, err, delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, b.config.MaxReconnect)
+	}
+}
+
+// session connects, logs in, takes the seat and plays until the connection ends. It
+// returns true if the bot took its seat.
+func (b *Bot) session(ctx context.Context) (bool, error) {
+	c, err := b.config.Dial()
+	if err != nil {
+		return false, err
+	}
+	defer c.Close()
+	if err := c.Login(b.config.Login, b.config.Password); err != nil {
+		return false, err
+	}
+
+	b.mu.Lock()
+	b.c = c
+	b.game = nil
+	b.ready = false
+	b.failure = nil
+	b.mu.Unlock()
+
+	stop := context.AfterFunc(ctx, b.leave)
+	defer stop()
+
+	if err := b.seat(); err != nil {
+		return false, err
+	}
+	err = c.Run(client.Handlers{
+		Created:   b.created,
+		State:     b.state,
+		Start:     b.start,
+		Move:      b.move,
+		End:       b.end,
+		Destroyed: b.destroyed,
+		Error:     b.serverError,
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failure != nil {
+		err = b.failure
+	}
+	if err == nil {
+		err = errors.New("connection closed by the server")
+	}
+	return b.table != "", err
+}
+
+// seat takes the seat: rejoins the table of a previous connection, joins the
+// configured table, creates a table or plays the deal of the day.
+func (b *Bot) seat() error {
+	b.mu.Lock()
+	table := b.table
+	b.mu.Unlock()
+
+	switch {
+	case table != "":
+		b.config.Logf("[%s] Rejoining table %s", b.config.Login, table)
+		return b.c.Join(table)
+	case b.config.Daily:
+		return b.c.Send("daily play")
+	case b.config.Table != "":
+		b.mu.Lock()
+		b.table = b.config.Table
+		b.mu.Unlock()
+		return b.c.Join(b.config.Table)
+	default:
+		return b.c.Create(b.config.Size)
+	}
+}
+
+// leave leaves the table and closes the connection (on shutdown).
+func (b *Bot) leave() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = true
+	if b.table != "" && !b.config.Daily {
+		b.c.Leave(b.table)
+	}
+	b.c.Close()
+}
+
+// finish ends the session: with an error if the bot cannot play, as done otherwise.
+// The caller must hold the lock.
+func (b *Bot) finish(err error) {
+	if err != nil {
+		b.failure = err
+	} else {
+		b.done = true
+	}
+	b.c.Close()
+}
+
+// created takes the table the bot created.
+func (b *Bot) created(t client.TableCreated) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t.Creator != b.config.Login || b.table != "" {
+		return
+	}
+	b.table = t.Table
+	b.config.Logf("[%s] Created table %s", b.config.Login, t.Table)
+	b.sendReady()
+}
+
+// state signals readiness at a table without a running game.
+func (b *Bot) state(s client.TableState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if s.Table == b.table && b.game == nil {
+		b.sendReady()
+	}
+}
+
+// start begins tracking a new game.
+func (b *Bot) start(s client.GameStart) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.config.Daily && b.table == "" {
+		b.table = s.Table
+	}
+	if s.Table != b.table {
+		return
+	}
+	b.game = newGame(b.player)
+	b.ready = false
+}
+
+// move applies a move and answers with the bot's move if it is its turn.
+func (b *Bot) move(m client.Move) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if m.Table != b.table || b.game == nil {
+		return
+	}
+	token, err := b.game.apply(m)
+	if err != nil {
+		b.config.Logf("[%s] Cannot follow move %s at table %s: %v", b.config.Login, m.Token, b.table, err)
+		return
+	}
+	if token != "" {
+		if err := b.c.Play(b.table, token); err != nil {
+			b.config.Logf("[%s] Failed to send move %s: %v", b.config.Login, token, err)
+		}
+	}
+}
+
+// end counts a finished game and signals readiness for the next one, or leaves after
+// the configured games.
+func (b *Bot) end(e client.GameEnd) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e.Table != b.table {
+		return
+	}
+	b.played++
+	b.game = nil
+	b.config.Logf("[%s] Game %d at table %s finished", b.config.Login, b.played, b.table)
+	if b.config.Games > 0 && b.played >= b.config.Games {
+		if !b.config.Daily {
+			b.c.Leave(b.table)
+		}
+		b.finish(nil)
+		return
+	}
+	b.sendReady()
+}
+
+// destroyed ends the bot when its table is closed.
+func (b *Bot) destroyed(table string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if table != b.table {
+		return
+	}
+	b.config.Logf("[%s] Table %s closed", b.config.Login, table)
+	b.finish(nil)
+}
+
+// serverError logs an error of the server. Before the bot has a seat, the error means
+// it cannot take one.
+func (b *Bot) serverError(text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.config.Logf("[%s] Server error: %s", b.config.Login, text)
+	if b.game == nil && (b.table == "" || b.table == b.config.Table && b.played == 0 && !b.ready) {
+		b.finish(fmt.Errorf("%w: %s", ErrSeat, text))
+	}
+}
+
+// sendReady signals once per game that the bot is ready. The caller must hold the lock.
+func (b *Bot) sendReady() {
+	if b.ready {
+		return
+	}
+	b.ready = true
+	if err := b.c.Ready(b.table); err != nil {
+		b.config.Logf("[%s] Failed to send ready: %v", b.config.Login, err)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package botkit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// script are the replies of the fake server to the received lines. The reply "close"
+// closes the connection.
+type script map[string][]string
+
+// fakeServer accepts a connection per script, sends the welcome and version and
+// answers the received lines. All received lines are sent to the returned channel,
+// which is closed after the last connection.
+func fakeServer(t *testing.T, scripts ...script) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 64)
+	go func() {
+		defer close(received)
+		for _, s := range scripts {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("Welcome to ISS\nVersion 14\n"))
+			scanner := bufio.NewScanner(conn)
+		lines:
+			for scanner.Scan() {
+				line := scanner.Text()
+				received <- line
+				for _, reply := range s[line] {
+					if reply == "close" {
+						break lines
+					}
+					conn.Write([]byte(reply + "\n"))
+				}
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+// quiet discards the log of a bot.
+func quiet(string, ...any) {}
+
+// passAI is an AI player that always passes.
+type passAI struct {
+	*ai.RandomAI
+}
+
+func (passAI) DecideBid(*skat.Hand, ai.BidContext) ai.BidDecision {
+	return ai.BidDecision{Action: ai.BidActionPass}
+}
+
+func TestRunPlaysGames(t *testing.T) {
+	address, received := fakeServer(t, script{
+		"login bot secret": {"password:", "clients", "tables"},
+		"create / 3":       {"create t1 bot 3"},
+		"table t1 bot ready": {
+			"table t1 bot start anna bot carl",
+			"table t1 bot play w ??.??.??.??.??.??.??.??.??.??|CJ.SJ.HA.HT.HK.HQ.H9.H8.H7.DA|??.??.??.??.??.??.??.??.??.??|??.??",
+		},
+		"table t1 bot play p": {
+			"table t1 bot end (;GM[Skat];)",
+		},
+		"table t1 bot leave": {"table t1 bot destroy"},
+	})
+
+	bot := New(Config{Address: address, Login: "bot", Password: "secret", Games: 1, Logf: quiet}, passAI{ai.NewRandomAI(nil)})
+	if err := bot.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if bot.Played() != 1 {
+		t.Errorf("Played() = %d, want 1", bot.Played())
+	}
+
+	var sent []string
+	for line := range received {
+		sent = append(sent, line)
+	}
+	want := []string{"login bot secret", "create / 3", "table t1 bot ready", "table t1 bot play p", "table t1 bot leave"}
+	if len(sent) != len(want) {
+		t.Fatalf("sent %q, want %q", sent, want)
+	}
+	for i := range want {
+		if sent[i] != want[i] {
+			t.Errorf("sent[%d] = %q, want %q", i, sent[i], want[i])
+		}
+	}
+}
+
+func TestRunSeatError(t *testing.T) {
+	address, _ := fakeServer(t, script{
+		"login bot secret": {"password:"},
+		"join missing":     {"error Table missing does not exist"},
+	})
+
+	bot := New(Config{Address: address, Login: "bot", Password: "secret", Table: "missing", Reconnect: time.Millisecond, Logf: quiet}, ai.NewRandomAI(nil))
+	if err := bot.Run(context.Background()); !errors.Is(err, ErrSeat) {
+		t.Errorf("Run() error = %v, want ErrSeat", err)
+	}
+}
+
+func TestRunLoginRejected(t *testing.T) {
+	address, _ := fakeServer(t, script{
+		"login bot1 x": {"error Login name 'bot1' is reserved"},
+	})
+
+	bot := New(Config{Address: address, Login: "bot1", Password: "x", Reconnect: time.Millisecond, Logf: quiet}, ai.NewRandomAI(nil))
+	if err := bot.Run(context.Background()); !errors.Is(err, client.ErrLogin) {
+		t.Errorf("Run() error = %v, want ErrLogin", err)
+	}
+}
+
+func TestRunReconnectsToTable(t *testing.T) {
+	address, received := fakeServer(t,
+		script{
+			"login bot secret":   {"password:"},
+			"create / 3":         {"create t1 bot 3"},
+			"table t1 bot ready": {"close"},
+		},
+		script{
+			"login bot secret":   {"password:"},
+			"join t1":            {"table t1 bot state 3 bot anna carl"},
+			"table t1 bot ready": {"table t1 bot destroy"},
+		},
+	)
+
+	bot := New(Config{Address: address, Login: "bot", Password: "secret", Reconnect: time.Millisecond, Logf: quiet}, ai.NewRandomAI(nil))
+	if err := bot.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	rejoined := false
+	for line := range received {
+		if line == "join t1" {
+			rejoined = true
+		}
+	}
+	if !rejoined {
+		t.Error("bot did not rejoin table t1 after the reconnect")
+	}
+}
+
+func TestRunStopsWithoutReconnect(t *testing.T) {
+	address, _ := fakeServer(t, script{
+		"login bot secret": {"password:"},
+		"create / 3":       {"close"},
+	})
+
+	bot := New(Config{Address: address, Login: "bot", Password: "secret", Logf: quiet}, ai.NewRandomAI(nil))
+	if err := bot.Run(context.Background()); err == nil {
+		t.Error("Run() error = nil, want lost connection")
+	}
+}
+
+func TestGameAsksPlayerOnItsTurn(t *testing.T) {
+	g := newGame(ai.NewRandomAI(rand.New(rand.NewSource(1))))
+	deal := client.Move{Table: "t1", Player: skat.MoveWorld, Token: "??.??.??.??.??.??.??.??.??.??|CJ.SJ.HA.HT.HK.HQ.H9.H8.H7.DA|??.??.??.??.??.??.??.??.??.??|??.??"}
+
+	// Middlehand bids first
+	token, err := g.apply(deal)
+	if err != nil {
+		t.Fatalf("apply(deal) error: %v", err)
+	}
+	if token == "" {
+		t.Fatal("apply(deal) = \"\", want the bid of middlehand")
+	}
+	if g.position == nil || *g.position != skat.Middlehand {
+		t.Errorf("position = %v, want middlehand", g.position)
+	}
+	if g.hand.Size() != 10 {
+		t.Errorf("hand size = %d, want 10", g.hand.Size())
+	}
+}
+
+func TestGameForehandDecides(t *testing.T) {
+	g := newGame(ai.NewHeuristicAI(false))
+	deal := client.Move{Table: "t1", Player: skat.MoveWorld, Token: "CJ.SJ.HJ.DJ.CA.CT.CK.CQ.C9.C8|??.??.??.??.??.??.??.??.??.??|??.??.??.??.??.??.??.??.??.??|??.??"}
+	if _, err := g.apply(deal); err != nil {
+		t.Fatalf("apply(deal) error: %v", err)
+	}
+
+	// Forehand must still declare at 18 or pass after both others passed
+	for _, p := range []skat.MovePlayer{skat.MoveMiddlehand, skat.MoveRearhand} {
+		token, err := g.apply(client.Move{Table: "t1", Player: p, Token: client.TokenPass})
+		if err != nil {
+			t.Fatalf("apply(%s pass) error: %v", p, err)
+		}
+		if p == skat.MoveRearhand && token != "18" {
+			t.Errorf("apply(%s pass) = %q, want 18", p, token)
+		}
+	}
+	if g.bidding.Phase != skat.BidPhaseForehandDecides {
+		t.Errorf("phase = %s, want %s", g.bidding.Phase, skat.BidPhaseForehandDecides)
+	}
+}
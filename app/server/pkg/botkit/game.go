@@ -0,0 +1,223 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package botkit
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// game tracks a game from the bot's point of view and asks the AI player for the
+// bot's moves.
+type game struct {
+	player       ai.AIPlayer
+	position     *skat.Player
+	hand         *skat.Hand
+	bidding      *skat.BiddingState
+	declarer     *skat.Player
+	contract     *skat.Contract
+	trick        *skat.Trick
+	awaitingSkat bool
+	announced    bool
+	over         bool
+}
+
+// newGame creates a game tracker deciding with the AI player.
+func newGame(player ai.AIPlayer) *game {
+	return &game{player: player, hand: skat.NewHand(), bidding: skat.NewBiddingState()}
+}
+
+// apply applies a move and returns the bot's next move token ("" if it is not its
+// turn).
+func (g *game) apply(m client.Move) (string, error) {
+	if g.over {
+		return "", nil
+	}
+	player, ok := m.Player.ToPlayer()
+	if !ok {
+		if err := g.worldMove(m); err != nil {
+			return "", err
+		}
+		return g.next(), nil
+	}
+
+	var err error
+	if value, ok := m.Bid(); ok {
+		err = g.bidding.Bid(player, value)
+	} else if card, ok := m.Card(); ok {
+		err = g.cardPlay(player, card)
+	} else if contract, ok := m.Contract(); ok {
+		g.declarer = &player
+		g.contract = contract
+		g.trick = skat.NewTrick(skat.Forehand)
+	} else {
+		switch m.Token {
+		case client.TokenHoldBid:
+			err = g.bidding.Hold(player)
+		case client.TokenPass:
+			err = g.bidding.Pass(player)
+			if err == nil && g.bidding.Result == skat.BidResultAllPassed {
+				// Servers playing Ramsch continue with card play, others end the game
+				g.contract = skat.NewContract(skat.GameRamsch)
+				g.trick = skat.NewTrick(skat.Forehand)
+			}
+		case client.TokenSkatRequest:
+			// The skat cards follow as move of the server
+		default:
+			// Showing cards, resigning, timeouts and leaving end the game
+			g.over = true
+			return "", nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return g.next(), nil
+}
+
+// worldMove applies a move of the server: the deal or the skat picked up by the bot.
+func (g *game) worldMove(m client.Move) error {
+	if hands, _, ok := m.Deal(); ok {
+		// The bot's own hand is the only one not hidden
+		for _, p := range skat.AllPlayers {
+			if hands[p].Size() > 0 {
+				position := p
+				g.position = &position
+				g.hand = hands[p]
+			}
+		}
+		if g.position == nil {
+			return fmt.Errorf("no visible hand in deal: %s", m.Token)
+		}
+		return nil
+	}
+	if !g.awaitingSkat {
+		return nil
+	}
+
+	cards, err := skat.HandFromCode(m.Token)
+	if err != nil {
+		return err
+	}
+	for _, c := range cards.Cards {
+		g.hand.Add(c)
+	}
+	g.awaitingSkat = false
+	return nil
+}
+
+// cardPlay adds a played card to the trick.
+func (g *game) cardPlay(player skat.Player, card skat.Card) error {
+	if g.trick == nil {
+		return fmt.Errorf("card play before game announcement: %s", card.Code())
+	}
+	if err := g.trick.AddCard(card, player); err != nil {
+		return err
+	}
+	if g.isMe(player) {
+		g.hand.Remove(card)
+	}
+	if g.trick.IsComplete() {
+		winner, err := g.trick.DetermineWinner(g.contract.GameType)
+		if err != nil {
+			return err
+		}
+		g.trick = skat.NewTrick(winner)
+	}
+	return nil
+}
+
+// isMe returns true if the player is the bot.
+func (g *game) isMe(player skat.Player) bool {
+	return g.position != nil && *g.position == player
+}
+
+// next returns the bot's next move token ("" if it is not its turn).
+func (g *game) next() string {
+	if g.position == nil {
+		return ""
+	}
+
+	// Bidding
+	if !g.bidding.IsDone() {
+		if !g.isMe(g.bidding.ActivePlayer) {
+			return ""
+		}
+		return g.bid()
+	}
+
+	// Skat pickup and announcement
+	if g.contract == nil {
+		if g.bidding.Declarer == nil || !g.isMe(*g.bidding.Declarer) || g.awaitingSkat || g.announced {
+			return ""
+		}
+		if g.hand.Size() == 12 {
+			return g.announce()
+		}
+		if g.player.DecidePickUpSkat(g.hand) {
+			g.awaitingSkat = true
+			return client.TokenSkatRequest
+		}
+		g.announced = true
+		return g.player.DecideAnnouncement(g.hand, g.bidding.FinalBid, true).Code()
+	}
+
+	// Trick playing
+	next := g.trick.NextPlayer()
+	if next == nil || !g.isMe(*next) || g.hand.Size() == 0 {
+		return ""
+	}
+	return g.player.SelectCard(g.hand, ai.PlayContext{
+		Player:   *g.position,
+		Declarer: g.declarer,
+		GameType: g.contract.GameType,
+		Trick:    g.trick,
+	}).Code()
+}
+
+// bid returns the bidding move token. Decisions the bidding does not allow are
+// answered with a pass.
+func (g *game) bid() string {
+	decision := g.player.DecideBid(g.hand, ai.BidContext{
+		Player:     *g.position,
+		CurrentBid: g.bidding.CurrentBid,
+		Bidding:    g.bidding.IsActiveBidding,
+	})
+	switch {
+	case decision.Action == ai.BidActionBid && decision.Value > g.bidding.CurrentBid && skat.IsValidBid(decision.Value):
+		return strconv.Itoa(decision.Value)
+	case decision.Action == ai.BidActionHold && !g.bidding.IsActiveBidding:
+		return client.TokenHoldBid
+	default:
+		return client.TokenPass
+	}
+}
+
+// announce discards two cards after picking up the skat and returns the announcement
+// token (e.g. "G.C7.C8").
+func (g *game) announce() string {
+	gameType := ai.EvaluateHand(g.hand).BestGameType
+	discards := g.player.SelectDiscards(g.hand, gameType)
+	for _, c := range discards {
+		g.hand.Remove(c)
+	}
+	g.announced = true
+	contract := g.player.DecideAnnouncement(g.hand, g.bidding.FinalBid, false)
+	return fmt.Sprintf("%s.%s.%s", contract.Code(), discards[0].Code(), discards[1].Code())
+}
@@ -0,0 +1,624 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client is a client of ISS-compatible Skat servers: it connects and logs in,
+// reports the messages of the server as typed events and sends the commands to create,
+// join and leave tables and to play moves, so clients and bots do not need to
+// implement the line protocol themselves.
+//
+// A client connects with Dial, logs in with Login and then calls Run, which reads the
+// messages until the connection is closed and calls the handlers:
+//
+//	c, err := client.Dial("localhost:7000")
+//	...
+//	if err := c.Login("alice", "secret"); err != nil { ... }
+//	c.Create(3)
+//	err = c.Run(client.Handlers{
+//		Move: func(m client.Move) { ... c.PlayCard(m.Table, card) ... },
+//	})
+//
+// The commands may be sent from the handlers and from other goroutines.
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Message types of the server.
+const (
+	msgVersion  = "Version"
+	msgPassword = "password:"
+	msgClients  = "clients"
+	msgTables   = "tables"
+	msgTable    = "table"
+	msgCreate   = "create"
+	msgError    = "error"
+	msgText     = "text"
+	msgYell     = "yell"
+)
+
+// Table actions (third token after "table <name> <login>").
+const (
+	actionState   = "state"
+	actionStart   = "start"
+	actionPlay    = "play"
+	actionEnd     = "end"
+	actionReady   = "ready"
+	actionLeave   = "leave"
+	actionDestroy = "destroy"
+	actionError   = "error"
+	actionTell    = "tell"
+	actionOuvert  = "ouvert"
+	actionSkat    = "skat"
+	actionBot     = "bot"
+)
+
+// Move tokens of the ISS protocol.
+const (
+	TokenHoldBid     = "y"
+	TokenPass        = "p"
+	TokenSkatRequest = "s"
+	TokenShowCards   = "SC"
+	TokenResign      = "RE"
+	TokenAcceptClaim = "AC"
+	TokenRejectClaim = "RC"
+	TokenLeaveTable  = "LE"
+	TokenRedeal      = "RD"
+)
+
+// DialTimeout is the timeout of connecting and reading the version of the server.
+const DialTimeout = 10 * time.Second
+
+// ErrLogin is returned when the server rejects a login.
+var ErrLogin = errors.New("login rejected")
+
+// Message is a raw message of the server: the first token and the remaining tokens.
+type Message struct {
+	Command string
+	Args    []string
+	Raw     string
+}
+
+// parseMessage splits a line of the server into tokens.
+func parseMessage(line string) Message {
+	line = strings.TrimSpace(line)
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return Message{Raw: line}
+	}
+	return Message{Command: parts[0], Args: parts[1:], Raw: line}
+}
+
+// LobbyUpdate is an update of the lobby: the logged-in clients or the open tables.
+type LobbyUpdate struct {
+	// Kind is "clients" or "tables"
+	Kind string
+	// Args are the tokens of the update as sent by the server (none for an empty list)
+	Args []string
+}
+
+// TableCreated is a table created by a client: "create <table> <creator> <size>".
+type TableCreated struct {
+	Table   string
+	Creator string
+	Size    int
+}
+
+// TableState is the state of a table as sent by the server: "table <name> <login> state ...".
+type TableState struct {
+	Table string
+	Args  []string
+}
+
+// GameStart is the start of a game at a table.
+type GameStart struct {
+	Table string
+	// Players are the player names by position (Forehand, Middlehand, Rearhand)
+	Players []string
+}
+
+// Move is a move at a table: a player's move or a move of the server (the deal and
+// the skat).
+type Move struct {
+	Table  string
+	Player skat.MovePlayer
+	// Token is the move as sent by the server, e.g. "18", "p", "G.C7.C8" or "SJ"
+	Token string
+	// Clocks are the remaining thinking times of the players by position after the
+	// move (nil if the game is not timed)
+	Clocks []time.Duration
+}
+
+// Card returns the played card of a card play.
+func (m Move) Card() (skat.Card, bool) {
+	if m.Player == skat.MoveWorld {
+		return skat.Card{}, false
+	}
+	card, err := skat.CardFromCode(m.Token)
+	return card, err == nil
+}
+
+// Bid returns the value of a bid.
+func (m Move) Bid() (int, bool) {
+	if m.Player == skat.MoveWorld {
+		return 0, false
+	}
+	value, err := strconv.Atoi(m.Token)
+	return value, err == nil && skat.IsValidBid(value)
+}
+
+// Contract returns the announced contract of a game announcement.
+func (m Move) Contract() (*skat.Contract, bool) {
+	if _, ok := m.Card(); ok || m.Player == skat.MoveWorld {
+		return nil, false
+	}
+	code, _, _ := strings.Cut(m.Token, ".")
+	contract, err := skat.ContractFromCode(code)
+	return contract, err == nil
+}
+
+// Deal returns the hands and the skat of the deal, a move of the server
+// ("<forehand>|<middlehand>|<rearhand>|<skat>"). Hidden cards are left out, so only
+// the own hand has cards.
+func (m Move) Deal() (map[skat.Player]*skat.Hand, *skat.Hand, bool) {
+	parts := strings.Split(m.Token, "|")
+	if m.Player != skat.MoveWorld || len(parts) != 4 {
+		return nil, nil, false
+	}
+	hands := make(map[skat.Player]*skat.Hand)
+	for i, p := range skat.AllPlayers {
+		hand, err := skat.HandFromCode(parts[i])
+		if err != nil {
+			return nil, nil, false
+		}
+		hands[p] = hand
+	}
+	skatCards, err := skat.HandFromCode(parts[3])
+	if err != nil {
+		return nil, nil, false
+	}
+	return hands, skatCards, true
+}
+
+// OpenHand is the open hand of the declarer of an Ouvert game, shown before the first
+// card: "table <name> <login> ouvert <player> <cards>".
+type OpenHand struct {
+	Table  string
+	Player skat.MovePlayer
+	Hand   *skat.Hand
+}
+
+// BotSeat is a bot at a table with its difficulty, shown after the start:
+// "table <name> <login> bot <player> <bot> <difficulty>".
+type BotSeat struct {
+	Table      string
+	Player     skat.MovePlayer
+	Name       string
+	Difficulty string
+}
+
+// HandSkat is the untouched skat of a Hand game, shown after the game:
+// "table <name> <login> skat <cards>".
+type HandSkat struct {
+	Table string
+	Skat  *skat.Hand
+}
+
+// GameEnd is the end of a game at a table.
+type GameEnd struct {
+	Table string
+	// Summary is the game summary in the ISS format ("(;GM[Skat]...;)")
+	Summary string
+}
+
+// Chat is a chat message at a table: "table <name> <login> tell <sender> <text>".
+type Chat struct {
+	Table  string
+	Sender string
+	Text   string
+}
+
+// Handlers are the functions Run calls for the messages of the server. Handlers left
+// nil are not called.
+type Handlers struct {
+	// Lobby is called with the updates of the client and table lists
+	Lobby func(LobbyUpdate)
+	// Created is called when a table has been created
+	Created func(TableCreated)
+	// State is called with the state of a table
+	State func(TableState)
+	// Start is called when a game starts
+	Start func(GameStart)
+	// Move is called with every move, including the deal
+	Move func(Move)
+	// Bot is called with the bots of a game and their difficulties after the start
+	Bot func(BotSeat)
+	// Open is called with the open hand of the declarer of an Ouvert game
+	Open func(OpenHand)
+	// Skat is called with the untouched skat of a Hand game after the game
+	Skat func(HandSkat)
+	// End is called when a game has ended
+	End func(GameEnd)
+	// Destroyed is called when a table has been closed
+	Destroyed func(table string)
+	// Chat is called with the chat messages at a table
+	Chat func(Chat)
+	// Text is called with text and yell messages
+	Text func(text string)
+	// Error is called with error messages of the server, including errors at a table
+	Error func(text string)
+	// Message is called with every message, before the typed handler
+	Message func(Message)
+}
+
+// Client is a connection to a server.
+type Client struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	version int
+	login   string
+	mu      sync.Mutex
+}
+
+// Dial connects to a server and reads its welcome and protocol version.
+func Dial(address string) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", address, DialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return Connect(conn)
+}
+
+// Connect creates a client on an established connection, e.g. to an in-memory server,
+// and reads the welcome and protocol version like Dial. The connection is closed if
+// the version cannot be read.
+func Connect(conn net.Conn) (*Client, error) {
+	c := NewClient(conn)
+	conn.SetReadDeadline(time.Now().Add(DialTimeout))
+	if err := c.readVersion(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetReadDeadline(time.Time{})
+	return c, nil
+}
+
+// NewClient creates a client on an established connection. Unlike Dial and Connect,
+// it does not wait for the version of the server.
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn, reader: bufio.NewReader(conn)}
+}
+
+// readVersion reads the messages up to the protocol version.
+func (c *Client) readVersion() error {
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return err
+		}
+		if msg.Command != msgVersion {
+			continue
+		}
+		if len(msg.Args) == 0 {
+			return fmt.Errorf("invalid version message: %s", msg.Raw)
+		}
+		if c.version, err = strconv.Atoi(msg.Args[0]); err != nil {
+			return fmt.Errorf("invalid version message: %s", msg.Raw)
+		}
+		return nil
+	}
+}
+
+// Version returns the protocol version of the server (0 if not read by Dial).
+func (c *Client) Version() int {
+	return c.version
+}
+
+// User returns the login name after a successful login.
+func (c *Client) User() string {
+	return c.login
+}
+
+// Login logs in and waits for the confirmation of the server. A rejected login returns
+// an error wrapping ErrLogin. The lobby lists following the confirmation are reported
+// by Run.
+func (c *Client) Login(login, password string) error {
+	if err := c.Send("login %s %s", login, password); err != nil {
+		return err
+	}
+	for {
+		msg, err := c.read()
+		if err != nil {
+			return err
+		}
+		switch msg.Command {
+		case msgPassword:
+			c.login = login
+			return nil
+		case msgError:
+			return fmt.Errorf("%w: %s", ErrLogin, strings.Join(msg.Args, " "))
+		}
+	}
+}
+
+// Run reads the messages of the server and calls the handlers until the connection is
+// closed. It returns nil if the connection was closed with Close.
+func (c *Client) Run(h Handlers) error {
+	for {
+		msg, err := c.read()
+		if err != nil {
+			// In-memory connections (net.Pipe) report io.ErrClosedPipe after Close
+			if errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+				return nil
+			}
+			return err
+		}
+		if msg.Command == "" {
+			continue
+		}
+		if h.Message != nil {
+			h.Message(msg)
+		}
+		dispatch(h, msg)
+	}
+}
+
+// dispatch calls the typed handler of a message.
+func dispatch(h Handlers, msg Message) {
+	switch msg.Command {
+	case msgClients, msgTables:
+		if h.Lobby != nil {
+			h.Lobby(LobbyUpdate{Kind: msg.Command, Args: msg.Args})
+		}
+	case msgCreate:
+		if h.Created != nil && len(msg.Args) >= 3 {
+			size, _ := strconv.Atoi(msg.Args[2])
+			h.Created(TableCreated{Table: msg.Args[0], Creator: msg.Args[1], Size: size})
+		}
+	case msgTable:
+		dispatchTable(h, msg)
+	case msgText, msgYell:
+		if h.Text != nil {
+			h.Text(strings.Join(msg.Args, " "))
+		}
+	case msgError:
+		if h.Error != nil {
+			h.Error(strings.Join(msg.Args, " "))
+		}
+	}
+}
+
+// dispatchTable calls the handler of a table message: "table <name> <login> <action> ...".
+func dispatchTable(h Handlers, msg Message) {
+	if len(msg.Args) < 3 {
+		return
+	}
+	table, action, args := msg.Args[0], msg.Args[2], msg.Args[3:]
+	switch action {
+	case actionState:
+		if h.State != nil {
+			h.State(TableState{Table: table, Args: args})
+		}
+	case actionStart:
+		if h.Start != nil {
+			h.Start(GameStart{Table: table, Players: args})
+		}
+	case actionPlay:
+		if h.Move == nil || len(args) < 2 {
+			return
+		}
+		if player, err := skat.MovePlayerFromCode(args[0]); err == nil {
+			h.Move(Move{Table: table, Player: player, Token: args[1], Clocks: parseClocks(args[2:])})
+		}
+	case actionBot:
+		if h.Bot == nil || len(args) < 3 {
+			return
+		}
+		if player, err := skat.MovePlayerFromCode(args[0]); err == nil {
+			h.Bot(BotSeat{Table: table, Player: player, Name: args[1], Difficulty: args[2]})
+		}
+	case actionOuvert:
+		if h.Open == nil || len(args) < 2 {
+			return
+		}
+		player, err := skat.MovePlayerFromCode(args[0])
+		if err != nil {
+			return
+		}
+		if hand, err := skat.HandFromCode(args[1]); err == nil {
+			h.Open(OpenHand{Table: table, Player: player, Hand: hand})
+		}
+	case actionSkat:
+		if h.Skat == nil || len(args) < 1 {
+			return
+		}
+		if skatCards, err := skat.HandFromCode(args[0]); err == nil {
+			h.Skat(HandSkat{Table: table, Skat: skatCards})
+		}
+	case actionEnd:
+		if h.End != nil {
+			h.End(GameEnd{Table: table, Summary: strings.Join(args, " ")})
+		}
+	case actionDestroy:
+		if h.Destroyed != nil {
+			h.Destroyed(table)
+		}
+	case actionError:
+		if h.Error != nil {
+			h.Error(strings.Join(args, " "))
+		}
+	case actionTell:
+		if h.Chat != nil && len(args) >= 2 {
+			h.Chat(Chat{Table: table, Sender: args[0], Text: strings.Join(args[1:], " ")})
+		}
+	}
+}
+
+// parseClocks parses the remaining thinking times in seconds after a move
+// ("120.0 95.5 120.0"), nil if there are none or they are invalid.
+func parseClocks(args []string) []time.Duration {
+	if len(args) != len(skat.AllPlayers) {
+		return nil
+	}
+	clocks := make([]time.Duration, len(args))
+	for i, arg := range args {
+		seconds, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil
+		}
+		clocks[i] = time.Duration(seconds * float64(time.Second))
+	}
+	return clocks
+}
+
+// read reads the next message.
+func (c *Client) read() (Message, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil && line == "" {
+		return Message{}, err
+	}
+	return parseMessage(line), nil
+}
+
+// Send sends a command line, e.g. "tournament list".
+func (c *Client) Send(format string, args ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fprintf(c.conn, format+"\n", args...)
+	return err
+}
+
+// Create creates a table for three or four players. The server reports the new
+// table to the Created handler.
+func (c *Client) Create(size int) error {
+	return c.Send("create / %d", size)
+}
+
+// Join joins a table.
+func (c *Client) Join(table string) error {
+	return c.Send("join %s", table)
+}
+
+// Observe watches a table without playing.
+func (c *Client) Observe(table string) error {
+	return c.Send("observe %s", table)
+}
+
+// ObservePlayer watches the table of a player without playing. FreeSkat servers
+// observe their bot tables by the player, as the table names are not unique; the
+// tables are listed by "tables add <name> <player> <players...>" lobby updates.
+func (c *Client) ObservePlayer(login string) error {
+	return c.Send("observe %s", login)
+}
+
+// Tell sends a chat message to a table.
+func (c *Client) Tell(table, text string) error {
+	return c.sendTable(table, actionTell+" "+text)
+}
+
+// Ready signals that the client is ready for the next game at a table.
+func (c *Client) Ready(table string) error {
+	return c.sendTable(table, actionReady)
+}
+
+// Leave leaves a table.
+func (c *Client) Leave(table string) error {
+	return c.sendTable(table, actionLeave)
+}
+
+// Play sends a move token at a table, e.g. "18", "p" or "C7".
+func (c *Client) Play(table, token string) error {
+	return c.sendTable(table, actionPlay+" "+token)
+}
+
+// Bid bids a value.
+func (c *Client) Bid(table string, value int) error {
+	return c.Play(table, strconv.Itoa(value))
+}
+
+// Hold holds the bid.
+func (c *Client) Hold(table string) error {
+	return c.Play(table, TokenHoldBid)
+}
+
+// Pass passes.
+func (c *Client) Pass(table string) error {
+	return c.Play(table, TokenPass)
+}
+
+// PickUpSkat asks for the skat; the server sends its cards as a move.
+func (c *Client) PickUpSkat(table string) error {
+	return c.Play(table, TokenSkatRequest)
+}
+
+// Announce announces the contract, with the two discarded cards after picking up the
+// skat (none for hand games).
+func (c *Client) Announce(table string, contract *skat.Contract, discards ...skat.Card) error {
+	token := contract.Code()
+	for _, card := range discards {
+		token += "." + card.Code()
+	}
+	return c.Play(table, token)
+}
+
+// PlayCard plays a card.
+func (c *Client) PlayCard(table string, card skat.Card) error {
+	return c.Play(table, card.Code())
+}
+
+// Resign resigns the game: a defender concedes the remaining tricks to the declarer.
+func (c *Client) Resign(table string) error {
+	return c.Play(table, TokenResign)
+}
+
+// Claim shows the cards of the declarer and claims the remaining tricks.
+func (c *Client) Claim(table string) error {
+	return c.Play(table, TokenShowCards)
+}
+
+// AcceptClaim accepts the pending claim or concession.
+func (c *Client) AcceptClaim(table string) error {
+	return c.Play(table, TokenAcceptClaim)
+}
+
+// RejectClaim rejects the pending claim or concession; the play continues.
+func (c *Client) RejectClaim(table string) error {
+	return c.Play(table, TokenRejectClaim)
+}
+
+// Redeal replays a deal whose defender left the game instead of claiming the remaining
+// tricks (declarers only).
+func (c *Client) Redeal(table string) error {
+	return c.Play(table, TokenRedeal)
+}
+
+// sendTable sends a table command: "table <name> <login> <command>".
+func (c *Client) sendTable(table, command string) error {
+	return c.Send("%s %s %s %s", msgTable, table, c.login, command)
+}
+
+// Close closes the connection; Run returns.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,238 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// fakeServer accepts a single connection, sends the welcome and version, and then
+// answers every received line with the replies of the script (nil = no reply).
+// The received lines are sent to the returned channel.
+func fakeServer(t *testing.T, script map[string][]string) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 16)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("Welcome to ISS\nVersion 14\n"))
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			line := scanner.Text()
+			received <- line
+			for _, reply := range script[line] {
+				conn.Write([]byte(reply + "\n"))
+			}
+		}
+		close(received)
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestDialAndLogin(t *testing.T) {
+	address, received := fakeServer(t, map[string][]string{
+		"login alice secret": {"password:", "clients", "tables"},
+	})
+
+	c, err := Dial(address)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+	if c.Version() != 14 {
+		t.Errorf("Version() = %d, want 14", c.Version())
+	}
+	if err := c.Login("alice", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+	if c.User() != "alice" {
+		t.Errorf("User() = %q, want alice", c.User())
+	}
+	if line := <-received; line != "login alice secret" {
+		t.Errorf("sent %q, want login", line)
+	}
+}
+
+func TestLoginRejected(t *testing.T) {
+	address, _ := fakeServer(t, map[string][]string{
+		"login bot1 x": {"error Login name 'bot1' is reserved"},
+	})
+
+	c, err := Dial(address)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+	err = c.Login("bot1", "x")
+	if !errors.Is(err, ErrLogin) {
+		t.Fatalf("Login() error = %v, want ErrLogin", err)
+	}
+	if !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("Login() error = %v, want the server message", err)
+	}
+}
+
+func TestRunEvents(t *testing.T) {
+	address, _ := fakeServer(t, map[string][]string{
+		"login alice secret": {"password:", "clients + alice", "tables"},
+		"create / 3":         {"create t1 alice 3"},
+		"table t1 alice ready": {
+			"table t1 alice start alice bob carl",
+			"table t1 alice bot 1 bob strong",
+			"table t1 alice play w CJ.SJ.HA.HT.HK.HQ.H9.H8.H7.DA|??.??.??.??.??.??.??.??.??.??|??.??.??.??.??.??.??.??.??.??|??.??",
+			"table t1 alice play 1 18",
+			"table t1 alice play 0 GH",
+			"table t1 alice ouvert 0 CJ.SJ.HA",
+			"table t1 alice play 0 CJ 95.5 120.0 118.0",
+			"table t1 alice skat D7.D8",
+			"table t1 alice end (;GM[Skat];)",
+			"text Good game",
+			"table t1 alice error Not your turn",
+			"table t1 alice tell bob well played",
+			"table t1 alice destroy",
+		},
+	})
+
+	c, err := Dial(address)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	if err := c.Login("alice", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+
+	var events []string
+	var moves []Move
+	h := Handlers{
+		Lobby:   func(u LobbyUpdate) { events = append(events, "lobby "+u.Kind+" "+strings.Join(u.Args, " ")) },
+		Created: func(tc TableCreated) { events = append(events, "created "+tc.Table); c.Ready(tc.Table) },
+		Start:   func(s GameStart) { events = append(events, "start "+strings.Join(s.Players, ",")) },
+		Move:    func(m Move) { moves = append(moves, m) },
+		Bot:     func(b BotSeat) { events = append(events, "bot "+b.Player.String()+" "+b.Name+" "+b.Difficulty) },
+		Open:    func(o OpenHand) { events = append(events, "open "+o.Player.String()+" "+o.Hand.Code()) },
+		Skat:    func(hs HandSkat) { events = append(events, "skat "+hs.Skat.Code()) },
+		End:     func(e GameEnd) { events = append(events, "end "+e.Summary) },
+		Text:    func(text string) { events = append(events, "text "+text) },
+		Error:   func(text string) { events = append(events, "error "+text) },
+		Chat:    func(ch Chat) { events = append(events, "chat "+ch.Table+" "+ch.Sender+": "+ch.Text) },
+		Destroyed: func(table string) {
+			events = append(events, "destroyed "+table)
+			c.Close()
+		},
+	}
+	if err := c.Create(3); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	if err := c.Run(h); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	want := []string{
+		"lobby clients + alice", "lobby tables ", "created t1", "start alice,bob,carl", "bot 1 bob strong", "open 0 CJ.SJ.HA",
+		"skat D7.D8", "end (;GM[Skat];)", "text Good game", "error Not your turn", "chat t1 bob: well played", "destroyed t1",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+
+	if len(moves) != 4 {
+		t.Fatalf("got %d moves, want 4", len(moves))
+	}
+	hands, _, ok := moves[0].Deal()
+	if !ok || hands[skat.Forehand].Size() != 10 || hands[skat.Middlehand].Size() != 0 {
+		t.Errorf("Deal() = %v, %t, want the own hand of 10 cards", hands, ok)
+	}
+	if bid, ok := moves[1].Bid(); !ok || bid != 18 || moves[1].Player != skat.MoveMiddlehand {
+		t.Errorf("Bid() = %d, %t by %v, want 18 by Middlehand", bid, ok, moves[1].Player)
+	}
+	if contract, ok := moves[2].Contract(); !ok || contract.GameType != skat.GameGrand || !contract.Hand {
+		t.Errorf("Contract() = %v, %t, want Grand Hand", contract, ok)
+	}
+	if card, ok := moves[3].Card(); !ok || card.Code() != "CJ" {
+		t.Errorf("Card() = %v, %t, want CJ", card, ok)
+	}
+	if _, ok := moves[3].Contract(); ok {
+		t.Errorf("Contract() of a card play = true, want false")
+	}
+	if want := []time.Duration{95500 * time.Millisecond, 120 * time.Second, 118 * time.Second}; !reflect.DeepEqual(moves[3].Clocks, want) {
+		t.Errorf("Clocks = %v, want %v", moves[3].Clocks, want)
+	}
+	if moves[1].Clocks != nil {
+		t.Errorf("Clocks of an untimed move = %v, want nil", moves[1].Clocks)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	address, received := fakeServer(t, map[string][]string{
+		"login alice secret": {"password:"},
+	})
+
+	c, err := Dial(address)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+	if err := c.Login("alice", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+	<-received
+
+	contract := skat.NewContract(skat.GameClubs)
+	seven, _ := skat.CardFromCode("C7")
+	eight, _ := skat.CardFromCode("C8")
+	commands := []struct {
+		send func() error
+		want string
+	}{
+		{func() error { return c.Join("t1") }, "join t1"},
+		{func() error { return c.Observe("t2") }, "observe t2"},
+		{func() error { return c.ObservePlayer("bob") }, "observe bob"},
+		{func() error { return c.Tell("t1", "good luck") }, "table t1 alice tell good luck"},
+		{func() error { return c.Bid("t1", 20) }, "table t1 alice play 20"},
+		{func() error { return c.Hold("t1") }, "table t1 alice play y"},
+		{func() error { return c.Pass("t1") }, "table t1 alice play p"},
+		{func() error { return c.PickUpSkat("t1") }, "table t1 alice play s"},
+		{func() error { return c.Announce("t1", contract, seven, eight) }, "table t1 alice play C.C7.C8"},
+		{func() error { return c.PlayCard("t1", seven) }, "table t1 alice play C7"},
+		{func() error { return c.Leave("t1") }, "table t1 alice leave"},
+		{func() error { return c.Send("tournament list") }, "tournament list"},
+	}
+	for _, cmd := range commands {
+		if err := cmd.send(); err != nil {
+			t.Fatalf("sending %q: %v", cmd.want, err)
+		}
+		if line := <-received; line != cmd.want {
+			t.Errorf("sent %q, want %q", line, cmd.want)
+		}
+	}
+}
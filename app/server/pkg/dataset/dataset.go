@@ -0,0 +1,111 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dataset exports archived games as anonymized datasets for research.
+//
+// Player names are replaced by pseudonyms and game IDs by sequence numbers, so a
+// dataset can be published without revealing who played (see docs/REPLAY-FORMAT.md).
+package dataset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// Options control which metadata is included in a dataset.
+type Options struct {
+	// Timestamps includes the game start and move times
+	Timestamps bool
+	// Comments includes the post-game comments (free text, may contain names)
+	Comments bool
+}
+
+// Pseudonymizer assigns stable pseudonyms ("player1", "player2", ...) to player names
+// in the order they are first seen.
+type Pseudonymizer struct {
+	names map[string]string
+}
+
+// NewPseudonymizer creates an empty pseudonymizer.
+func NewPseudonymizer() *Pseudonymizer {
+	return &Pseudonymizer{names: make(map[string]string)}
+}
+
+// Name returns the pseudonym of a player name.
+func (p *Pseudonymizer) Name(name string) string {
+	pseudonym, ok := p.names[name]
+	if !ok {
+		pseudonym = fmt.Sprintf("player%d", len(p.names)+1)
+		p.names[name] = pseudonym
+	}
+	return pseudonym
+}
+
+// Anonymize returns a copy of the replay with pseudonymized players, the game ID
+// "game<n>" and only the metadata selected by the options. The deal, moves,
+// results, mistakes and shuffle seed are kept.
+func Anonymize(r *replay.Replay, n int, names *Pseudonymizer, opts Options) *replay.Replay {
+	a := *r
+	a.ID = fmt.Sprintf("game%d", n)
+
+	a.Players = make([]replay.Player, len(r.Players))
+	for i, p := range r.Players {
+		a.Players[i] = replay.Player{Position: p.Position, Name: names.Name(p.Name)}
+	}
+
+	a.Moves = make([]replay.Move, len(r.Moves))
+	copy(a.Moves, r.Moves)
+	if !opts.Timestamps {
+		a.StartedAt = time.Time{}
+		for i := range a.Moves {
+			a.Moves[i].Time = nil
+		}
+	}
+
+	a.Comments = nil
+	if opts.Comments {
+		for _, c := range r.Comments {
+			c.Author = names.Name(c.Author)
+			if !opts.Timestamps {
+				c.Time = time.Time{}
+			}
+			a.Comments = append(a.Comments, c)
+		}
+	}
+	return &a
+}
+
+// Export writes the anonymized replays as JSON lines, oldest game first, and returns
+// the number of games written. Pseudonyms are consistent within one export only.
+func Export(w io.Writer, replays []*replay.Replay, opts Options) (int, error) {
+	sorted := make([]*replay.Replay, len(replays))
+	copy(sorted, replays)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.Before(sorted[j].StartedAt)
+	})
+
+	encoder := json.NewEncoder(w)
+	names := NewPseudonymizer()
+	for i, r := range sorted {
+		if err := encoder.Encode(Anonymize(r, i+1, names, opts)); err != nil {
+			return i, err
+		}
+	}
+	return len(sorted), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/replay"
+)
+
+// newTestReplay returns a replay with the given ID, start, players, one timed move and a comment.
+func newTestReplay(id string, startedAt time.Time, names ...string) *replay.Replay {
+	r := &replay.Replay{Version: replay.Version, ID: id, StartedAt: startedAt}
+	for i, name := range names {
+		r.Players = append(r.Players, replay.Player{Position: i, Name: name})
+	}
+	moveTime := startedAt.Add(time.Second)
+	r.Moves = []replay.Move{{Index: 1, Player: 1, Type: replay.MovePass, Time: &moveTime}}
+	r.Comments = []replay.Comment{{Move: 0, Author: names[0], Text: "well played", Time: moveTime}}
+	return r
+}
+
+// ============================================================================
+// Anonymize Tests
+// ============================================================================
+
+func TestAnonymize(t *testing.T) {
+	started := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	r := newTestReplay("daily-2025-03-01-1", started, "anna", "ben", "carl")
+
+	names := NewPseudonymizer()
+	a := Anonymize(r, 7, names, Options{})
+	if a.ID != "game7" {
+		t.Errorf("ID = %q, want game7", a.ID)
+	}
+	for i, want := range []string{"player1", "player2", "player3"} {
+		if a.Players[i].Name != want {
+			t.Errorf("player %d = %q, want %q", i, a.Players[i].Name, want)
+		}
+	}
+	if !a.StartedAt.IsZero() || a.Moves[0].Time != nil || a.Comments != nil {
+		t.Errorf("metadata not removed: %+v", a)
+	}
+	if r.Players[0].Name != "anna" || r.Moves[0].Time == nil {
+		t.Error("Anonymize() changed the original replay")
+	}
+
+	a = Anonymize(r, 8, names, Options{Timestamps: true, Comments: true})
+	if !a.StartedAt.Equal(started) || a.Moves[0].Time == nil {
+		t.Error("timestamps removed")
+	}
+	if len(a.Comments) != 1 || a.Comments[0].Author != "player1" || a.Comments[0].Time.IsZero() {
+		t.Errorf("comments = %+v", a.Comments)
+	}
+}
+
+// ============================================================================
+// Export Tests
+// ============================================================================
+
+func TestExport(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	replays := []*replay.Replay{
+		newTestReplay("later", day.Add(time.Hour), "dora", "anna", "ben"),
+		newTestReplay("first", day, "anna", "ben", "carl"),
+	}
+
+	var buf bytes.Buffer
+	count, err := Export(&buf, replays, Options{})
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("Export() = %d, want 2", count)
+	}
+	if out := buf.String(); strings.Contains(out, "anna") || strings.Contains(out, "startedAt") || strings.Contains(out, "first") {
+		t.Errorf("export not anonymized: %s", out)
+	}
+
+	var games []replay.Replay
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r replay.Replay
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("invalid JSON line: %v", err)
+		}
+		games = append(games, r)
+	}
+
+	// Oldest game first, pseudonyms stable across games
+	if games[0].ID != "game1" || games[0].Players[0].Name != "player1" {
+		t.Errorf("first game = %+v", games[0])
+	}
+	if got := []string{games[1].Players[0].Name, games[1].Players[1].Name}; got[0] != "player4" || got[1] != "player1" {
+		t.Errorf("second game players = %v, want [player4 player1]", got)
+	}
+}
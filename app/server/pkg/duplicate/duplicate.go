@@ -0,0 +1,328 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package duplicate implements duplicate Skat: several tables play the same pre-dealt
+// deals, and every player is compared with the players who held the same cards at
+// the other tables. This removes most of the luck of the deal from the ranking.
+package duplicate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+var (
+	// ErrUnknownGame is returned for games that do not belong to the set.
+	ErrUnknownGame = errors.New("game does not belong to the duplicate set")
+	// ErrDealMismatch is returned for games whose cards differ from the set's deal.
+	ErrDealMismatch = errors.New("game was not dealt the set's cards")
+)
+
+// Config defines a duplicate set. The deals are derived from the seed, so a set can be
+// recreated from its config.
+type Config struct {
+	// Name is the set name, used as prefix of the game IDs
+	Name string `json:"name"`
+	// Seed is the seed the deals are derived from
+	Seed int64 `json:"seed"`
+	// Deals is the number of deals
+	Deals int `json:"deals"`
+	// Tables are the three players of each table
+	Tables [][3]string `json:"tables"`
+}
+
+// ReadConfig reads a JSON set config.
+func ReadConfig(r io.Reader) (*Config, error) {
+	var cfg Config
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Deal is a pre-dealt deal of a set.
+type Deal struct {
+	// Shuffle is the seeded shuffle of the deal
+	Shuffle skat.ShuffleInfo
+	// Hands and Skat are the dealt cards
+	Hands map[skat.Player]*skat.Hand
+	Skat  *skat.Hand
+}
+
+// Set is a duplicate set: every table plays every deal. It is safe for concurrent use.
+type Set struct {
+	config *Config
+	deals  []*Deal
+	// games are the finished games by table and deal
+	games map[[2]int]*skat.GameRecord
+	mu    sync.Mutex
+}
+
+// NewSet creates a set and deals its cards.
+func NewSet(cfg *Config) (*Set, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("missing set name")
+	}
+	if cfg.Deals < 1 {
+		return nil, fmt.Errorf("invalid number of deals: %d", cfg.Deals)
+	}
+	if len(cfg.Tables) < 2 {
+		return nil, errors.New("a duplicate set needs at least two tables")
+	}
+	players := make(map[string]bool)
+	for _, table := range cfg.Tables {
+		for _, name := range table {
+			if name == "" || players[name] {
+				return nil, fmt.Errorf("invalid or duplicate player: %q", name)
+			}
+			players[name] = true
+		}
+	}
+
+	s := &Set{config: cfg, games: make(map[[2]int]*skat.GameRecord)}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	for n := 0; n < cfg.Deals; n++ {
+		deck := skat.NewDeck()
+		shuffle := deck.ShuffleSeed(rng.Int63())
+		hands, skatCards, err := skat.DealCards(deck)
+		if err != nil {
+			return nil, err
+		}
+		s.deals = append(s.deals, &Deal{Shuffle: shuffle, Hands: hands, Skat: skatCards})
+	}
+	return s, nil
+}
+
+// Config returns the set config.
+func (s *Set) Config() *Config {
+	return s.config
+}
+
+// GameID returns the game ID of a deal at a table (both 0-based): "<name>-t<table>-d<deal>".
+func (s *Set) GameID(table, deal int) string {
+	return fmt.Sprintf("%s-t%d-d%d", s.config.Name, table+1, deal+1)
+}
+
+// Seats returns the player names by position of a deal at a table. The seats rotate
+// with table and deal, so the same cards are held by different players at every table.
+func (s *Set) Seats(table, deal int) map[skat.Player]string {
+	seats := make(map[skat.Player]string)
+	for i, p := range skat.AllPlayers {
+		seats[p] = s.config.Tables[table][(i+table+deal)%3]
+	}
+	return seats
+}
+
+// Record returns the dealt record of a deal at a table, ready to be played.
+func (s *Set) Record(table, deal int, startedAt time.Time) *skat.GameRecord {
+	d := s.deals[deal]
+	record := &skat.GameRecord{
+		ID:        s.GameID(table, deal),
+		StartedAt: startedAt,
+		Players:   s.Seats(table, deal),
+		Hands:     make(map[skat.Player]*skat.Hand),
+		Skat:      skat.NewHandFromCards(d.Skat.Cards),
+		Shuffle:   d.Shuffle,
+		Engine:    skat.EngineVersion,
+	}
+	for _, p := range skat.AllPlayers {
+		record.Hands[p] = skat.NewHandFromCards(d.Hands[p].Cards)
+	}
+	return record
+}
+
+// Add adds a finished game of the set. Games not belonging to the set return ErrUnknownGame.
+func (s *Set) Add(record *skat.GameRecord) error {
+	table, deal, ok := s.find(record.ID)
+	if !ok {
+		return ErrUnknownGame
+	}
+	d := s.deals[deal]
+	for _, p := range skat.AllPlayers {
+		if record.Hands[p] == nil || record.Hands[p].Code() != d.Hands[p].Code() {
+			return ErrDealMismatch
+		}
+	}
+
+	game, err := record.Replay(nil)
+	if err != nil {
+		return err
+	}
+	if game.State != skat.StateGameOver {
+		return errors.New("game is not finished")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games[[2]int{table, deal}] = record
+	return nil
+}
+
+// find returns table and deal of a game ID of the set.
+func (s *Set) find(id string) (int, int, bool) {
+	for t := range s.config.Tables {
+		for d := range s.deals {
+			if s.GameID(t, d) == id {
+				return t, d, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Revealed returns true if the deal has been played at all tables. Until then its
+// cards and games must be kept secret from everyone who has not played it.
+func (s *Set) Revealed(deal int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revealed(deal)
+}
+
+// revealed is Revealed without locking.
+func (s *Set) revealed(deal int) bool {
+	for t := range s.config.Tables {
+		if s.games[[2]int{t, deal}] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// CanView returns true if the login may see the game: its players always, everyone
+// else once the deal has been played at all tables.
+func (s *Set) CanView(id, login string) bool {
+	table, deal, ok := s.find(id)
+	if !ok {
+		return true
+	}
+	for _, name := range s.config.Tables[table] {
+		if name == login {
+			return true
+		}
+	}
+	return s.Revealed(deal)
+}
+
+// Score is the comparative score of a player.
+type Score struct {
+	Rank   int    `json:"rank"`
+	Player string `json:"player"`
+	// Deals is the number of compared deals
+	Deals int `json:"deals"`
+	// Points is the sum of the player's seat points
+	Points int `json:"points"`
+	// Score is the sum of the differences to the average seat points of the same
+	// cards at all tables
+	Score float64 `json:"score"`
+}
+
+// Scores returns the comparative scores of all deals played at all tables, best first.
+// Seat points are the Seeger-Fabian points of the position (see ai.SeatPoints).
+func (s *Set) Scores() ([]Score, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scores := make(map[string]*Score)
+	for _, table := range s.config.Tables {
+		for _, name := range table {
+			scores[name] = &Score{Player: name}
+		}
+	}
+
+	for d := range s.deals {
+		if !s.revealed(d) {
+			continue
+		}
+		points := make([]map[skat.Player]int, len(s.config.Tables))
+		for t := range s.config.Tables {
+			game, err := s.games[[2]int{t, d}].Replay(nil)
+			if err != nil {
+				return nil, err
+			}
+			points[t] = make(map[skat.Player]int)
+			for _, p := range skat.AllPlayers {
+				points[t][p] = ai.SeatPoints(game, p)
+			}
+		}
+
+		for _, p := range skat.AllPlayers {
+			sum := 0
+			for t := range points {
+				sum += points[t][p]
+			}
+			mean := float64(sum) / float64(len(points))
+			for t := range points {
+				score := scores[s.Seats(t, d)[p]]
+				score.Points += points[t][p]
+				score.Score += float64(points[t][p]) - mean
+			}
+		}
+		for t := range s.config.Tables {
+			for _, name := range s.config.Tables[t] {
+				scores[name].Deals++
+			}
+		}
+	}
+
+	result := make([]Score, 0, len(scores))
+	for _, score := range scores {
+		result = append(result, *score)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Player < result[j].Player
+	})
+	for i := range result {
+		result[i].Rank = i + 1
+		if i > 0 && result[i].Score == result[i-1].Score {
+			result[i].Rank = result[i-1].Rank
+		}
+	}
+	return result, nil
+}
+
+// WriteScoresCSV writes comparative scores as CSV.
+func WriteScoresCSV(w io.Writer, scores []Score) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"rank", "player", "deals", "points", "score"}); err != nil {
+		return err
+	}
+	for _, s := range scores {
+		row := []string{
+			strconv.Itoa(s.Rank),
+			s.Player,
+			strconv.Itoa(s.Deals),
+			strconv.Itoa(s.Points),
+			strconv.FormatFloat(s.Score, 'f', 2, 64),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
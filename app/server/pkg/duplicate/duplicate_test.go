@@ -0,0 +1,166 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duplicate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// newTestSet creates a set of two deals at two tables.
+func newTestSet(t *testing.T) *Set {
+	t.Helper()
+
+	set, err := NewSet(&Config{
+		Name:   "cup",
+		Seed:   7,
+		Deals:  2,
+		Tables: [][3]string{{"anna", "ben", "carl"}, {"dora", "emil", "fritz"}},
+	})
+	if err != nil {
+		t.Fatalf("NewSet() error: %v", err)
+	}
+	return set
+}
+
+// playDeal plays a deal of the set with the strong AI and returns the record.
+func playDeal(t *testing.T, set *Set, table, deal int) *skat.GameRecord {
+	t.Helper()
+
+	record := set.Record(table, deal, time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC))
+	game := skat.NewGame()
+	if err := game.Deal(record.Hands, record.Skat); err != nil {
+		t.Fatalf("Deal() error: %v", err)
+	}
+	player := ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(int64(table))))
+	players := map[skat.Player]ai.AIPlayer{skat.Forehand: player, skat.Middlehand: player, skat.Rearhand: player}
+	if err := ai.PlayGame(game, players); err != nil {
+		t.Fatalf("PlayGame() error: %v", err)
+	}
+	record.Actions = game.Actions
+	return record
+}
+
+// ============================================================================
+// Set Tests
+// ============================================================================
+
+func TestSetDealsAndSeats(t *testing.T) {
+	set := newTestSet(t)
+	a, b := set.Record(0, 0, time.Now()), set.Record(1, 0, time.Now())
+	for _, p := range skat.AllPlayers {
+		if a.Hands[p].Code() != b.Hands[p].Code() {
+			t.Errorf("%s hands differ between tables", p)
+		}
+	}
+	if a.ID != "cup-t1-d1" || b.ID != "cup-t2-d1" {
+		t.Errorf("GameID() = %s, %s", a.ID, b.ID)
+	}
+	if err := a.CheckDeal(); err != nil {
+		t.Errorf("CheckDeal() error: %v", err)
+	}
+
+	// Seats rotate with the deal
+	first, second := set.Seats(0, 0), set.Seats(0, 1)
+	if first[skat.Forehand] == second[skat.Forehand] {
+		t.Errorf("Seats() forehand %s in both deals", first[skat.Forehand])
+	}
+
+	if _, err := NewSet(&Config{Name: "x", Deals: 1, Tables: [][3]string{{"a", "b", "c"}, {"a", "d", "e"}}}); err == nil {
+		t.Error("NewSet() with duplicate player: expected error")
+	}
+}
+
+func TestSetSecrecy(t *testing.T) {
+	set := newTestSet(t)
+	if err := set.Add(playDeal(t, set, 0, 0)); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	if set.Revealed(0) {
+		t.Error("Revealed(0) = true after one table")
+	}
+	if !set.CanView("cup-t1-d1", "anna") || set.CanView("cup-t1-d1", "dora") {
+		t.Error("CanView() before reveal: players of the table only")
+	}
+
+	if err := set.Add(playDeal(t, set, 1, 0)); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if !set.Revealed(0) || !set.CanView("cup-t1-d1", "dora") {
+		t.Error("deal not revealed after all tables")
+	}
+
+	other := playDeal(t, set, 0, 1)
+	other.ID = "other"
+	if err := set.Add(other); err != ErrUnknownGame {
+		t.Errorf("Add(other) = %v, want ErrUnknownGame", err)
+	}
+}
+
+// ============================================================================
+// Scoring Tests
+// ============================================================================
+
+func TestSetScores(t *testing.T) {
+	set := newTestSet(t)
+	for table := 0; table < 2; table++ {
+		for deal := 0; deal < 2; deal++ {
+			if err := set.Add(playDeal(t, set, table, deal)); err != nil {
+				t.Fatalf("Add() error: %v", err)
+			}
+		}
+	}
+
+	scores, err := set.Scores()
+	if err != nil {
+		t.Fatalf("Scores() error: %v", err)
+	}
+	if len(scores) != 6 {
+		t.Fatalf("len(Scores()) = %d, want 6", len(scores))
+	}
+
+	// Differences to the mean cancel out
+	sum := 0.0
+	for i, s := range scores {
+		sum += s.Score
+		if s.Deals != 2 {
+			t.Errorf("%s: %d deals, want 2", s.Player, s.Deals)
+		}
+		if i > 0 && s.Score > scores[i-1].Score {
+			t.Errorf("scores not sorted: %.2f after %.2f", s.Score, scores[i-1].Score)
+		}
+	}
+	if math.Abs(sum) > 1e-9 {
+		t.Errorf("sum of scores = %f, want 0", sum)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteScoresCSV(&buf, scores); err != nil {
+		t.Fatalf("WriteScoresCSV() error: %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil || len(rows) != 7 {
+		t.Errorf("WriteScoresCSV() = %d rows (error %v), want 7", len(rows), err)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events is the typed event stream of a server embedded as a library. Host
+// applications subscribe to a Stream and react to the game events without parsing
+// protocol lines:
+//
+//	ch, cancel := stream.Subscribe(64)
+//	defer cancel()
+//	for event := range ch {
+//		switch e := event.(type) {
+//		case events.GameFinished:
+//			log.Printf("%s: %s", e.Table, e.Record.ID)
+//		}
+//	}
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Event is one of PlayerJoined, GameStarted, CardPlayed and GameFinished.
+type Event interface {
+	// TableName returns the name of the table of the event.
+	TableName() string
+	// When returns the time of the event.
+	When() time.Time
+}
+
+// PlayerJoined is sent when a player takes a seat at a table (bots included).
+type PlayerJoined struct {
+	Time     time.Time
+	Table    string
+	Player   string
+	Position skat.Player
+}
+
+// GameStarted is sent when the cards of a game are dealt.
+type GameStarted struct {
+	Time  time.Time
+	Table string
+	// Game is the ID of the game
+	Game string
+	// Players are the player names by position
+	Players map[skat.Player]string
+}
+
+// CardPlayed is sent for every card played to a trick.
+type CardPlayed struct {
+	Time     time.Time
+	Table    string
+	Player   string
+	Position skat.Player
+	Card     skat.Card
+	// Trick is the number of the trick (1-10)
+	Trick int
+}
+
+// GameFinished is sent when a game has ended.
+type GameFinished struct {
+	Time  time.Time
+	Table string
+	// Record is the complete record of the game
+	Record *skat.GameRecord
+	// Result is the result of a declared game (nil for Ramsch)
+	Result *skat.GameResult
+	// Ramsch is the result of a Ramsch game (nil otherwise)
+	Ramsch *skat.RamschResult
+}
+
+func (e PlayerJoined) TableName() string { return e.Table }
+func (e GameStarted) TableName() string  { return e.Table }
+func (e CardPlayed) TableName() string   { return e.Table }
+func (e GameFinished) TableName() string { return e.Table }
+
+func (e PlayerJoined) When() time.Time { return e.Time }
+func (e GameStarted) When() time.Time  { return e.Time }
+func (e CardPlayed) When() time.Time   { return e.Time }
+func (e GameFinished) When() time.Time { return e.Time }
+
+// Stream distributes events to its subscribers. It is safe for concurrent use; a nil
+// stream ignores all events.
+type Stream struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]bool
+	dropped     int64
+	closed      bool
+}
+
+// NewStream creates a stream without subscribers.
+func NewStream() *Stream {
+	return &Stream{subscribers: make(map[chan Event]bool)}
+}
+
+// Subscribe returns a channel receiving the events published from now on, with the
+// given buffer size. Publishing never blocks the games: events are dropped for
+// subscribers with a full buffer. The channel is closed by cancel or Close.
+func (s *Stream) Subscribe(buffer int) (<-chan Event, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan Event, buffer)
+	if s.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	s.subscribers[ch] = true
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.subscribers[ch] {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+// Publish sends events to all subscribers.
+func (s *Stream) Publish(events ...Event) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	for _, event := range events {
+		for ch := range s.subscribers {
+			select {
+			case ch <- event:
+			default:
+				// Slow subscriber, drop the event
+				s.dropped++
+			}
+		}
+	}
+}
+
+// Dropped returns the number of events dropped for slow subscribers.
+func (s *Stream) Dropped() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close closes all subscriber channels. Events published afterwards are ignored.
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = nil
+}
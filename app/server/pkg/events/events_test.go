@@ -0,0 +1,86 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestStream(t *testing.T) {
+	s := NewStream()
+	first, cancelFirst := s.Subscribe(4)
+	second, cancelSecond := s.Subscribe(4)
+	defer cancelSecond()
+
+	now := time.Now()
+	s.Publish(
+		PlayerJoined{Time: now, Table: "t1", Player: "anna", Position: skat.Forehand},
+		CardPlayed{Time: now, Table: "t1", Player: "anna", Card: skat.Card{Suit: skat.Clubs, Rank: skat.Jack}, Trick: 1},
+	)
+	for _, ch := range []<-chan Event{first, second} {
+		if e := <-ch; e.TableName() != "t1" || !e.When().Equal(now) {
+			t.Errorf("event = %#v, want table t1", e)
+		}
+		if played, ok := (<-ch).(CardPlayed); !ok || played.Trick != 1 {
+			t.Errorf("second event = %#v, want CardPlayed", played)
+		}
+	}
+
+	cancelFirst()
+	if _, ok := <-first; ok {
+		t.Error("channel open after cancel")
+	}
+	cancelFirst()
+	s.Publish(GameStarted{Table: "t2"})
+	if e := <-second; e.TableName() != "t2" {
+		t.Errorf("event = %#v, want table t2", e)
+	}
+}
+
+func TestStreamDropsForSlowSubscribers(t *testing.T) {
+	s := NewStream()
+	ch, cancel := s.Subscribe(1)
+	defer cancel()
+
+	s.Publish(GameStarted{Table: "t1"}, GameStarted{Table: "t2"})
+	if s.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", s.Dropped())
+	}
+	if e := <-ch; e.TableName() != "t1" {
+		t.Errorf("event = %#v, want table t1", e)
+	}
+}
+
+func TestStreamClose(t *testing.T) {
+	s := NewStream()
+	ch, cancel := s.Subscribe(1)
+	s.Close()
+	if _, ok := <-ch; ok {
+		t.Error("channel open after Close")
+	}
+	cancel()
+	s.Publish(GameFinished{Table: "t1"})
+
+	late, _ := s.Subscribe(1)
+	if _, ok := <-late; ok {
+		t.Error("subscription after Close is open")
+	}
+
+	var none *Stream
+	none.Publish(GameFinished{Table: "t1"})
+}
@@ -0,0 +1,577 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notation writes and parses a compact human-readable notation of Skat games,
+// similar to PGN in chess: header tags, the deal, the bidding, the skat and the tricks.
+//
+// The notation is documented in docs/GAME-NOTATION.md.
+package notation
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Standard tags. Only ID, Date, the player names, Shuffle and Engine are read back,
+// the other tags are informational and derived from the moves.
+const (
+	TagID         = "ID"
+	TagDate       = "Date"
+	TagForehand   = "Forehand"
+	TagMiddlehand = "Middlehand"
+	TagRearhand   = "Rearhand"
+	TagDeclarer   = "Declarer"
+	TagContract   = "Contract"
+	TagBid        = "Bid"
+	TagResult     = "Result"
+	TagShuffle    = "Shuffle"
+	TagEngine     = "Engine"
+)
+
+// Move words of the notation.
+const (
+	wordHold     = "hold"
+	wordPass     = "pass"
+	wordPickUp   = "pickup"
+	wordDiscard  = "discard"
+	wordAnnounce = "announce"
+)
+
+// Section labels of the notation.
+const (
+	labelSkat        = "Skat"
+	labelBidding     = "Bidding"
+	labelDeclaration = "Declaration"
+)
+
+// Tag is a header tag.
+type Tag struct {
+	Name  string
+	Value string
+}
+
+// Game is a game in notation: header tags and the game record.
+type Game struct {
+	// Tags are the header tags in order (including custom tags like "Event")
+	Tags []Tag
+	// Record is the game
+	Record *skat.GameRecord
+}
+
+// New creates the notation of a game record with the standard tags.
+// The record is replayed to determine the informational tags.
+func New(record *skat.GameRecord) (*Game, error) {
+	game, err := record.Replay(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Game{Record: record}
+	g.SetTag(TagID, record.ID)
+	g.SetTag(TagDate, record.StartedAt.UTC().Format(time.RFC3339))
+	for _, p := range skat.AllPlayers {
+		g.SetTag(p.String(), record.Players[p])
+	}
+	if game.Declarer != nil {
+		g.SetTag(TagDeclarer, game.Declarer.String())
+	}
+	if game.Contract != nil {
+		g.SetTag(TagContract, game.Contract.Code())
+	}
+	if game.Bidding != nil && game.Bidding.FinalBid > 0 {
+		g.SetTag(TagBid, strconv.Itoa(game.Bidding.FinalBid))
+	}
+	if result := resultText(game); result != "" {
+		g.SetTag(TagResult, result)
+	}
+	switch record.Shuffle.Mode {
+	case skat.ShuffleSeeded:
+		g.SetTag(TagShuffle, fmt.Sprintf("%s %d", skat.ShuffleSeeded, record.Shuffle.Seed))
+	case skat.ShuffleCrypto:
+		g.SetTag(TagShuffle, skat.ShuffleCrypto)
+	}
+	if record.Engine > 0 {
+		g.SetTag(TagEngine, strconv.Itoa(record.Engine))
+	}
+	return g, nil
+}
+
+// resultText returns the value of the Result tag (empty if the game is not finished).
+func resultText(game *skat.Game) string {
+	if result := game.Result; result != nil && game.Declarer != nil {
+		outcome := "wins"
+		if !result.DeclarerWon {
+			outcome = "loses"
+		}
+		return fmt.Sprintf("%s %s with %d points, %+d", game.Declarer, outcome, result.DeclarerPoints, result.Score)
+	}
+	if result := game.RamschResult; result != nil && result.Durchmarsch {
+		return fmt.Sprintf("%s wins Ramsch by Durchmarsch, %+d", result.DurchmarschPlayer, result.DurchmarschScore)
+	}
+	if result := game.RamschResult; result != nil {
+		losers := make([]string, len(result.Losers))
+		for i, p := range result.Losers {
+			losers[i] = p.String()
+		}
+		verb := "loses"
+		if len(losers) > 1 {
+			verb = "lose"
+		}
+		return fmt.Sprintf("%s %s Ramsch with %d points, %+d", strings.Join(losers, " and "), verb, result.PlayerPoints[result.Losers[0]], result.LoserScore)
+	}
+	return ""
+}
+
+// Tag returns the value of a tag (empty if not set).
+func (g *Game) Tag(name string) string {
+	for _, tag := range g.Tags {
+		if tag.Name == name {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// SetTag sets the value of a tag, appending it if it is not set.
+func (g *Game) SetTag(name, value string) {
+	for i, tag := range g.Tags {
+		if tag.Name == name {
+			g.Tags[i].Value = value
+			return
+		}
+	}
+	g.Tags = append(g.Tags, Tag{Name: name, Value: value})
+}
+
+// Write writes the game in notation.
+func (g *Game) Write(w io.Writer) error {
+	_, err := io.WriteString(w, g.String())
+	return err
+}
+
+// String returns the game in notation.
+func (g *Game) String() string {
+	var b strings.Builder
+	for _, tag := range g.Tags {
+		fmt.Fprintf(&b, "[%s %s]\n", tag.Name, strconv.Quote(tag.Value))
+	}
+
+	b.WriteString("\n")
+	for _, p := range skat.AllPlayers {
+		fmt.Fprintf(&b, "%s: %s\n", playerCode(p), cardList(g.Record.Hands[p].Cards))
+	}
+	fmt.Fprintf(&b, "%s: %s\n\n", labelSkat, cardList(g.Record.Skat.Cards))
+
+	comments := make(map[int][]string)
+	for _, c := range g.Record.Comments {
+		comments[c.Move] = append(comments[c.Move], commentText(c))
+	}
+	for _, c := range comments[0] {
+		b.WriteString(c + "\n")
+	}
+
+	var bidding, declaration, plays []string
+	for i, action := range g.Record.Actions {
+		move := playerCode(action.Player) + " " + moveText(action)
+		for _, c := range comments[i+1] {
+			move += " " + c
+		}
+		switch action.Type {
+		case skat.ActionBid, skat.ActionHold, skat.ActionPass:
+			bidding = append(bidding, move)
+		case skat.ActionPickUpSkat, skat.ActionDiscard, skat.ActionAnnounce:
+			declaration = append(declaration, move)
+		case skat.ActionPlayCard:
+			plays = append(plays, move)
+		}
+	}
+	if len(bidding) > 0 {
+		fmt.Fprintf(&b, "%s: %s\n", labelBidding, strings.Join(bidding, ", "))
+	}
+	if len(declaration) > 0 {
+		fmt.Fprintf(&b, "%s: %s\n", labelDeclaration, strings.Join(declaration, ", "))
+	}
+	for i := 0; i < len(plays); i += 3 {
+		fmt.Fprintf(&b, "%d. %s\n", i/3+1, strings.Join(plays[i:min(i+3, len(plays))], ", "))
+	}
+	return b.String()
+}
+
+// moveText returns the notation of an action without the player.
+func moveText(action skat.Action) string {
+	switch action.Type {
+	case skat.ActionBid:
+		return strconv.Itoa(action.Value)
+	case skat.ActionHold:
+		return wordHold
+	case skat.ActionPass:
+		return wordPass
+	case skat.ActionPickUpSkat:
+		return wordPickUp
+	case skat.ActionDiscard:
+		return wordDiscard + " " + cardList(action.Cards)
+	case skat.ActionAnnounce:
+		return wordAnnounce + " " + action.Contract.Code()
+	default:
+		return cardList(action.Cards)
+	}
+}
+
+// Parse parses a game in notation. The moves are replayed, so illegal games are rejected.
+func Parse(text string) (*Game, error) {
+	return Read(strings.NewReader(text))
+}
+
+// Read reads a game in notation.
+func Read(r io.Reader) (*Game, error) {
+	g := &Game{Record: &skat.GameRecord{
+		Players: make(map[skat.Player]string),
+		Hands:   make(map[skat.Player]*skat.Hand),
+	}}
+
+	scanner := bufio.NewScanner(r)
+	inMoves := false
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, ";"):
+			// Empty lines and comments
+		case strings.HasPrefix(text, "["):
+			if inMoves {
+				return nil, fmt.Errorf("line %d: tag after the moves", line)
+			}
+			if err := g.parseTag(text); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		default:
+			inMoves = true
+			if err := g.parseLine(text); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := g.finish(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// parseTag parses a header tag line: [Name "Value"].
+func (g *Game) parseTag(text string) error {
+	if !strings.HasSuffix(text, "]") {
+		return fmt.Errorf("invalid tag: %s", text)
+	}
+	name, quoted, ok := strings.Cut(text[1:len(text)-1], " ")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid tag: %s", text)
+	}
+	value, err := strconv.Unquote(strings.TrimSpace(quoted))
+	if err != nil {
+		return fmt.Errorf("invalid tag value: %s", text)
+	}
+	g.SetTag(name, value)
+	return nil
+}
+
+// parseLine parses a deal, bidding, declaration, trick or game comment line.
+func (g *Game) parseLine(text string) error {
+	if strings.HasPrefix(text, "{") {
+		rest, comments, err := extractComments(text)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return fmt.Errorf("invalid line: %s", text)
+		}
+		g.addComments(0, comments)
+		return nil
+	}
+
+	label, rest, ok := strings.Cut(text, ":")
+	if ok {
+		rest = strings.TrimSpace(rest)
+		switch label {
+		case labelSkat:
+			return g.parseCards(&g.Record.Skat, rest)
+		case labelBidding, labelDeclaration:
+			return g.parseMoves(rest)
+		}
+		if p, err := playerFromCode(label); err == nil {
+			hand := g.Record.Hands[p]
+			if err := g.parseCards(&hand, rest); err != nil {
+				return fmt.Errorf("%s: %w", p, err)
+			}
+			g.Record.Hands[p] = hand
+			return nil
+		}
+	}
+
+	// Trick lines: "<n>. <moves>"
+	number, rest, ok := strings.Cut(text, ".")
+	if _, err := strconv.Atoi(number); !ok || err != nil {
+		return fmt.Errorf("invalid line: %s", text)
+	}
+	return g.parseMoves(strings.TrimSpace(rest))
+}
+
+// parseCards parses a space-separated card list into a hand.
+func (g *Game) parseCards(hand **skat.Hand, text string) error {
+	if *hand != nil {
+		return fmt.Errorf("cards given twice")
+	}
+	h, err := skat.HandFromCode(strings.Join(strings.Fields(text), "."))
+	if err != nil {
+		return err
+	}
+	*hand = h
+	return nil
+}
+
+// parseMoves parses a comma-separated list of moves and appends them to the record.
+func (g *Game) parseMoves(text string) error {
+	moves, err := splitMoves(text)
+	if err != nil {
+		return err
+	}
+	for _, move := range moves {
+		move, comments, err := extractComments(move)
+		if err != nil {
+			return err
+		}
+		action, err := parseMove(strings.Fields(move))
+		if err != nil {
+			return fmt.Errorf("move %q: %w", strings.TrimSpace(move), err)
+		}
+		g.Record.Actions = append(g.Record.Actions, action)
+		g.addComments(len(g.Record.Actions), comments)
+	}
+	return nil
+}
+
+// addComments adds parsed comments on a move (0 = the game) to the record.
+func (g *Game) addComments(move int, comments []skat.Comment) {
+	for _, c := range comments {
+		c.Move = move
+		g.Record.Comments = append(g.Record.Comments, c)
+	}
+}
+
+// splitMoves splits a move list at the commas outside of comments.
+func splitMoves(text string) ([]string, error) {
+	var moves []string
+	start, inComment, escaped := 0, false, false
+	for i, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '{':
+			inComment = true
+		case r == '}':
+			inComment = false
+		case r == ',' && !inComment:
+			moves = append(moves, text[start:i])
+			start = i + 1
+		}
+	}
+	if inComment {
+		return nil, fmt.Errorf("unterminated comment: %s", text)
+	}
+	return append(moves, text[start:]), nil
+}
+
+// extractComments removes the comments "{author: text}" from a move and returns them.
+func extractComments(text string) (string, []skat.Comment, error) {
+	var rest, body strings.Builder
+	var comments []skat.Comment
+	inComment, escaped := false, false
+	for _, r := range text {
+		switch {
+		case escaped:
+			body.WriteRune(r)
+			escaped = false
+		case r == '\\' && inComment:
+			escaped = true
+		case r == '{' && !inComment:
+			inComment = true
+			body.Reset()
+		case r == '}' && inComment:
+			inComment = false
+			author, comment, _ := strings.Cut(body.String(), ": ")
+			comments = append(comments, skat.Comment{Author: author, Text: comment})
+		case inComment:
+			body.WriteRune(r)
+		default:
+			rest.WriteRune(r)
+		}
+	}
+	if inComment {
+		return "", nil, fmt.Errorf("unterminated comment: %s", text)
+	}
+	return rest.String(), comments, nil
+}
+
+// commentText returns the notation of a comment: "{author: text}".
+func commentText(c skat.Comment) string {
+	escape := strings.NewReplacer("\\", "\\\\", "{", "\\{", "}", "\\}")
+	return "{" + escape.Replace(c.Author) + ": " + escape.Replace(c.Text) + "}"
+}
+
+// parseMove parses a move: the player code followed by the move words.
+func parseMove(fields []string) (skat.Action, error) {
+	if len(fields) < 2 {
+		return skat.Action{}, fmt.Errorf("invalid move")
+	}
+	player, err := playerFromCode(fields[0])
+	if err != nil {
+		return skat.Action{}, err
+	}
+	action := skat.Action{Player: player}
+	args := fields[2:]
+
+	switch word := fields[1]; word {
+	case wordHold:
+		action.Type = skat.ActionHold
+	case wordPass:
+		action.Type = skat.ActionPass
+	case wordPickUp:
+		action.Type = skat.ActionPickUpSkat
+	case wordDiscard:
+		action.Type = skat.ActionDiscard
+		if len(args) != 2 {
+			return action, fmt.Errorf("discard needs two cards")
+		}
+		action.Cards, err = parseCardList(args)
+		return action, err
+	case wordAnnounce:
+		action.Type = skat.ActionAnnounce
+		if len(args) != 1 {
+			return action, fmt.Errorf("announce needs a contract")
+		}
+		action.Contract, err = skat.ContractFromCode(args[0])
+		return action, err
+	default:
+		if value, err := strconv.Atoi(word); err == nil {
+			action.Type = skat.ActionBid
+			action.Value = value
+			break
+		}
+		action.Type = skat.ActionPlayCard
+		action.Cards, err = parseCardList(fields[1:])
+		if err == nil && len(action.Cards) != 1 {
+			err = fmt.Errorf("play needs one card")
+		}
+		return action, err
+	}
+	if len(args) > 0 {
+		return action, fmt.Errorf("unexpected %q", strings.Join(args, " "))
+	}
+	return action, nil
+}
+
+// finish reads the standard tags into the record and validates the game by replaying it.
+func (g *Game) finish() error {
+	record := g.Record
+	record.ID = g.Tag(TagID)
+	if date := g.Tag(TagDate); date != "" {
+		startedAt, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return fmt.Errorf("invalid %s tag: %w", TagDate, err)
+		}
+		record.StartedAt = startedAt
+	}
+	if shuffle := g.Tag(TagShuffle); shuffle != "" {
+		mode, seed, _ := strings.Cut(shuffle, " ")
+		record.Shuffle.Mode = mode
+		switch mode {
+		case skat.ShuffleSeeded:
+			n, err := strconv.ParseInt(seed, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s tag: %s", TagShuffle, shuffle)
+			}
+			record.Shuffle.Seed = n
+		case skat.ShuffleCrypto:
+		default:
+			return fmt.Errorf("invalid %s tag: %s", TagShuffle, shuffle)
+		}
+	}
+	if engine := g.Tag(TagEngine); engine != "" {
+		n, err := strconv.Atoi(engine)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s tag: %s", TagEngine, engine)
+		}
+		record.Engine = n
+	}
+	for _, p := range skat.AllPlayers {
+		record.Players[p] = g.Tag(p.String())
+		if record.Hands[p] == nil {
+			return fmt.Errorf("missing %s hand", p)
+		}
+	}
+	if record.Skat == nil {
+		return fmt.Errorf("missing skat")
+	}
+
+	if _, err := record.Replay(nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// playerCode returns the one-letter code of a player position (F, M or R).
+func playerCode(p skat.Player) string {
+	return p.String()[:1]
+}
+
+// playerFromCode parses a player position from its one-letter code.
+func playerFromCode(code string) (skat.Player, error) {
+	for _, p := range skat.AllPlayers {
+		if playerCode(p) == code {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid player: %s", code)
+}
+
+// cardList returns the space-separated ISS codes of the cards.
+func cardList(cards []skat.Card) string {
+	codes := make([]string, len(cards))
+	for i, c := range cards {
+		codes[i] = c.Code()
+	}
+	return strings.Join(codes, " ")
+}
+
+// parseCardList parses ISS card codes.
+func parseCardList(codes []string) ([]skat.Card, error) {
+	cards := make([]skat.Card, 0, len(codes))
+	for _, code := range codes {
+		card, err := skat.CardFromCode(code)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
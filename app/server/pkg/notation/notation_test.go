@@ -0,0 +1,174 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// newTestRecord returns a record played by the strong AI without move times, which
+// are not part of the notation.
+func newTestRecord(t *testing.T, seed int64) *skat.GameRecord {
+	t.Helper()
+
+	names := recordtest.Names()
+	names[skat.Middlehand] = `ben "the bidder"`
+	record := recordtest.PlayedBy(t, seed, names)
+	for i := range record.Actions {
+		record.Actions[i].Time = time.Time{}
+	}
+	return record
+}
+
+// ============================================================================
+// Round Trip Tests
+// ============================================================================
+
+func TestNotationRoundTrip(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		record := newTestRecord(t, seed)
+		record.Shuffle = skat.ShuffleInfo{Mode: skat.ShuffleSeeded, Seed: seed}
+
+		g, err := New(record)
+		if err != nil {
+			t.Fatalf("New() error: %v", err)
+		}
+		text := g.String()
+
+		parsed, err := Parse(text)
+		if err != nil {
+			t.Fatalf("Parse() error: %v\n%s", err, text)
+		}
+		if !reflect.DeepEqual(parsed.Record, record) {
+			t.Fatalf("Parse() record differs from the original:\n%s", text)
+		}
+		if !reflect.DeepEqual(parsed.Tags, g.Tags) {
+			t.Errorf("Parse() tags = %v, want %v", parsed.Tags, g.Tags)
+		}
+	}
+}
+
+func TestNotationTags(t *testing.T) {
+	g, err := New(newTestRecord(t, 1))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	g.SetTag("Event", "Club evening")
+
+	text := g.String()
+	for _, want := range []string{`[ID "g1"]`, `[Middlehand "ben \"the bidder\""]`, `[Event "Club evening"]`, "Bidding: ", "1. "} {
+		if !strings.Contains(text, want) {
+			t.Errorf("String() has no %q:\n%s", want, text)
+		}
+	}
+	if g.Tag(TagResult) == "" {
+		t.Error("Tag(Result) is empty")
+	}
+}
+
+func TestNotationComments(t *testing.T) {
+	record := newTestRecord(t, 4)
+	record.Comments = []skat.Comment{
+		{Move: 0, Author: "anna", Text: "Good game, everyone"},
+		{Move: 1, Author: "ben", Text: "Bidding {too} high, wasn't it?"},
+		{Move: len(record.Actions), Author: "1:strong", Text: `Last card \ trick`},
+	}
+
+	g, err := New(record)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	text := g.String()
+	if !strings.Contains(text, `{ben: Bidding \{too\} high, wasn't it?}`) {
+		t.Errorf("String() has no escaped comment:\n%s", text)
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse() error: %v\n%s", err, text)
+	}
+	if !reflect.DeepEqual(parsed.Record.Comments, record.Comments) {
+		t.Errorf("Parse() comments = %+v, want %+v", parsed.Record.Comments, record.Comments)
+	}
+	if !reflect.DeepEqual(parsed.Record.Actions, record.Actions) {
+		t.Error("Parse() actions differ from the original")
+	}
+}
+
+// ============================================================================
+// Parser Tests
+// ============================================================================
+
+func TestParseHandwritten(t *testing.T) {
+	text := `; Posted in the forum
+[ID "forum-1"]
+[Forehand "anna"]
+[Middlehand "ben"]
+[Rearhand "carl"]
+
+F: CJ SJ HJ DJ CA CT CK CQ C9 C8
+M: SA ST SK SQ S9 S8 S7 HA HT HK
+R: HQ H9 H8 H7 DA DT DK DQ D9 D8
+Skat: C7 D7
+
+Bidding: M pass, R pass
+Declaration: F pickup, F discard C7 D7, F announce C
+1. F CJ, M S7, R H7
+`
+	g, err := Parse(text)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	record := g.Record
+	if record.ID != "forum-1" || record.Players[skat.Rearhand] != "carl" {
+		t.Errorf("Parse() = id %s, rearhand %s", record.ID, record.Players[skat.Rearhand])
+	}
+	if len(record.Actions) != 8 {
+		t.Fatalf("len(Actions) = %d, want 8", len(record.Actions))
+	}
+	if a := record.Actions[4]; a.Type != skat.ActionAnnounce || a.Contract.GameType != skat.GameClubs {
+		t.Errorf("Actions[4] = %v %v, want announce C", a.Type, a.Contract)
+	}
+	if a := record.Actions[7]; a.Type != skat.ActionPlayCard || a.Player != skat.Rearhand || a.Cards[0].Code() != "H7" {
+		t.Errorf("Actions[7] = %v %v %v, want R H7", a.Player, a.Type, a.Cards)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	deal := "F: CJ SJ HJ DJ CA CT CK CQ C9 C8\nM: SA ST SK SQ S9 S8 S7 HA HT HK\nR: HQ H9 H8 H7 DA DT DK DQ D9 D8\nSkat: C7 D7\n"
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"missing skat", "F: CJ\nM: SA\nR: HQ\n"},
+		{"invalid tag", "[ID g1]\n" + deal},
+		{"invalid card", deal + "Bidding: M pass, R XX\n"},
+		{"invalid player", deal + "Bidding: X pass\n"},
+		{"illegal move", deal + "Bidding: F pass\n"},
+		{"tag after moves", deal + "[ID \"g1\"]\n"},
+		{"invalid shuffle", "[Shuffle \"seeded x\"]\n" + deal},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.text); err == nil {
+			t.Errorf("Parse(%s) error = nil, want error", tt.name)
+		}
+	}
+}
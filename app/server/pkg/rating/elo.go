@@ -0,0 +1,86 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rating
+
+import (
+	"math"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// EloK is the Elo K-factor per pairwise match. A game has two matches per player,
+// so a player gains or loses at most 2*EloK per game.
+const EloK = 16
+
+// Elo rates players with the Elo system. Every game updates the ratings immediately.
+type Elo struct {
+	ratings map[string]float64
+	games   map[string]int
+}
+
+// NewElo creates an Elo rater.
+func NewElo() *Elo {
+	return &Elo{ratings: make(map[string]float64), games: make(map[string]int)}
+}
+
+// Add rates a finished game. All matches of the game use the ratings before the game.
+func (e *Elo) Add(record *skat.GameRecord) error {
+	result, err := matches(record)
+	if err != nil || result == nil {
+		return err
+	}
+
+	changes := make(map[string]float64)
+	for _, m := range result {
+		changes[m.player] += EloK * (m.score - EloExpected(e.rating(m.player), e.rating(m.opponent)))
+	}
+	for name, change := range changes {
+		e.ratings[name] = e.rating(name) + change
+		e.games[name]++
+	}
+	return nil
+}
+
+// seed sets the ratings players start with.
+func (e *Elo) seed(start []Rating) {
+	for _, r := range start {
+		e.ratings[r.Player] = r.Rating
+	}
+}
+
+// rating returns the rating of a player (InitialRating for new players).
+func (e *Elo) rating(name string) float64 {
+	if r, ok := e.ratings[name]; ok {
+		return r
+	}
+	return InitialRating
+}
+
+// Ratings returns the current ratings of all players who have played, best first.
+func (e *Elo) Ratings() []Rating {
+	ratings := make([]Rating, 0, len(e.ratings))
+	for name, r := range e.ratings {
+		if e.games[name] > 0 {
+			ratings = append(ratings, Rating{Player: name, Rating: r, Games: e.games[name]})
+		}
+	}
+	sortRatings(ratings)
+	return ratings
+}
+
+// EloExpected returns the expected score of a player with rating a against rating b.
+func EloExpected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
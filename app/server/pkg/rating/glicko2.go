@@ -0,0 +1,255 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rating
+
+import (
+	"math"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Glicko-2 parameters (see Glickman, "Example of the Glicko-2 system").
+const (
+	// InitialDeviation is the rating deviation of new players
+	InitialDeviation = 350
+	// InitialVolatility is the volatility of new players
+	InitialVolatility = 0.06
+	// Tau constrains the change of the volatility
+	Tau = 0.5
+	// RatingPeriod is the length of a rating period. Games of the same period are
+	// rated together; the deviation of a player grows with every period without games.
+	RatingPeriod = 24 * time.Hour
+)
+
+// glickoScale converts between the Glicko and the Glicko-2 scale.
+const glickoScale = 173.7178
+
+// convergence is the tolerance of the volatility iteration.
+const convergence = 0.000001
+
+// glickoPlayer is the Glicko-2 state of a player on the Glicko-2 scale.
+type glickoPlayer struct {
+	mu    float64
+	phi   float64
+	sigma float64
+	games int
+	// period is the last rating period the player was rated in
+	period int64
+}
+
+// Glicko2 rates players with the Glicko-2 system. Games are rated per period, so the
+// rating of sporadic players becomes less certain (a higher deviation) and moves
+// faster when they return.
+type Glicko2 struct {
+	players map[string]*glickoPlayer
+	// period is the current rating period and pending are its matches
+	period  int64
+	pending []match
+}
+
+// NewGlicko2 creates a Glicko-2 rater.
+func NewGlicko2() *Glicko2 {
+	return &Glicko2{players: make(map[string]*glickoPlayer)}
+}
+
+// Add adds a finished game to its rating period. A game of a later period rates
+// the pending period first.
+func (g *Glicko2) Add(record *skat.GameRecord) error {
+	result, err := matches(record)
+	if err != nil || result == nil {
+		return err
+	}
+
+	period := record.StartedAt.UnixNano() / int64(RatingPeriod)
+	if g.period == 0 {
+		// Seeded players start in the first period
+		for _, p := range g.players {
+			p.period = period
+		}
+	}
+	if period > g.period && len(g.pending) > 0 {
+		g.players = g.rate(g.pending)
+		g.pending = nil
+	}
+	if period > g.period {
+		g.period = period
+	}
+	for _, m := range result {
+		if g.players[m.player] == nil {
+			g.players[m.player] = &glickoPlayer{mu: 0, phi: InitialDeviation / glickoScale, sigma: InitialVolatility, period: g.period}
+		}
+	}
+	g.pending = append(g.pending, result...)
+	return nil
+}
+
+// seed sets the ratings players start with.
+func (g *Glicko2) seed(start []Rating) {
+	for _, r := range start {
+		p := &glickoPlayer{
+			mu:    (r.Rating - InitialRating) / glickoScale,
+			phi:   InitialDeviation / glickoScale,
+			sigma: InitialVolatility,
+		}
+		if r.Deviation > 0 {
+			p.phi = math.Min(r.Deviation, InitialDeviation) / glickoScale
+		}
+		if r.Volatility > 0 {
+			p.sigma = r.Volatility
+		}
+		g.players[r.Player] = p
+	}
+}
+
+// Ratings returns the ratings of all players who have played including the pending
+// period, best first. Deviations include the periods without games up to the current period.
+func (g *Glicko2) Ratings() []Rating {
+	players := g.rate(g.pending)
+
+	ratings := make([]Rating, 0, len(players))
+	for name, p := range players {
+		if p.games == 0 {
+			continue
+		}
+		phi := idle(p.phi, p.sigma, g.period-p.period)
+		ratings = append(ratings, Rating{
+			Player:     name,
+			Rating:     p.mu*glickoScale + InitialRating,
+			Deviation:  phi * glickoScale,
+			Volatility: p.sigma,
+			Games:      p.games,
+		})
+	}
+	sortRatings(ratings)
+	return ratings
+}
+
+// rate returns the players after rating the matches of the current period. The
+// current state is not changed.
+func (g *Glicko2) rate(pending []match) map[string]*glickoPlayer {
+	players := make(map[string]*glickoPlayer, len(g.players))
+	for name, p := range g.players {
+		c := *p
+		players[name] = &c
+	}
+
+	// All matches of the period use the ratings before the period
+	before := make(map[string]glickoPlayer)
+	for name, p := range g.players {
+		q := *p
+		q.phi = idle(p.phi, p.sigma, g.period-p.period-1)
+		before[name] = q
+	}
+
+	byPlayer := make(map[string][]match)
+	games := make(map[string]int)
+	for _, m := range pending {
+		byPlayer[m.player] = append(byPlayer[m.player], m)
+	}
+	// Every player has two matches per game
+	for name, list := range byPlayer {
+		games[name] = len(list) / 2
+	}
+
+	for name, list := range byPlayer {
+		p := before[name]
+		var opponents []glickoPlayer
+		var scores []float64
+		for _, m := range list {
+			opponents = append(opponents, before[m.opponent])
+			scores = append(scores, m.score)
+		}
+		mu, phi, sigma := glicko2Update(p.mu, p.phi, p.sigma, opponents, scores)
+		players[name] = &glickoPlayer{mu: mu, phi: phi, sigma: sigma, games: p.games + games[name], period: g.period}
+	}
+	return players
+}
+
+// idle returns the deviation phi after the given number of periods without games,
+// at most the deviation of new players.
+func idle(phi, sigma float64, periods int64) float64 {
+	if periods <= 0 {
+		return phi
+	}
+	phi = math.Sqrt(phi*phi + float64(periods)*sigma*sigma)
+	return math.Min(phi, InitialDeviation/glickoScale)
+}
+
+// glicko2Update rates a player (mu, phi, sigma on the Glicko-2 scale) for one
+// period with the given opponents and scores and returns the new values.
+func glicko2Update(mu, phi, sigma float64, opponents []glickoPlayer, scores []float64) (float64, float64, float64) {
+	v, delta := 0.0, 0.0
+	for i, o := range opponents {
+		g := glickoG(o.phi)
+		e := glickoE(mu, o.mu, o.phi)
+		v += g * g * e * (1 - e)
+		delta += g * (scores[i] - e)
+	}
+	v = 1 / v
+	improvement := delta
+	delta *= v
+
+	sigma = glicko2Volatility(phi, sigma, delta, v)
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * improvement
+	return mu, phi, sigma
+}
+
+// glicko2Volatility computes the new volatility with the Illinois algorithm (step 5).
+func glicko2Volatility(phi, sigma, delta, v float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(Tau*Tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*Tau) < 0 {
+			k++
+		}
+		B = a - k*Tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+// glickoG reduces the impact of an opponent with an uncertain rating.
+func glickoG(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// glickoE is the expected score against an opponent.
+func glickoE(mu, opponentMu, opponentPhi float64) float64 {
+	return 1 / (1 + math.Exp(-glickoG(opponentPhi)*(mu-opponentMu)))
+}
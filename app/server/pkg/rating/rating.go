@@ -0,0 +1,188 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rating rates players from finished games with Elo or Glicko-2.
+//
+// A Skat game is rated as three pairwise matches: of two players, the one with more
+// Seeger-Fabian seat points wins (see ai.SeatPoints), equal points are a draw. So a
+// won declarer game beats both defenders, and a lost one loses against both.
+package rating
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Algorithm is a rating algorithm.
+type Algorithm string
+
+const (
+	// AlgorithmElo is the Elo rating
+	AlgorithmElo Algorithm = "elo"
+	// AlgorithmGlicko2 is the Glicko-2 rating with rating deviation and volatility
+	AlgorithmGlicko2 Algorithm = "glicko2"
+)
+
+// InitialRating is the rating of new players.
+const InitialRating = 1500
+
+// ParseAlgorithm parses an algorithm name (case-insensitive).
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch a := Algorithm(strings.ToLower(name)); a {
+	case AlgorithmElo, AlgorithmGlicko2:
+		return a, nil
+	default:
+		return "", fmt.Errorf("invalid rating algorithm: %s (want elo or glicko2)", name)
+	}
+}
+
+// Rating is the rating of a player.
+type Rating struct {
+	// Rank is the rank by rating (players with equal rating share a rank)
+	Rank   int     `json:"rank"`
+	Player string  `json:"player"`
+	Rating float64 `json:"rating"`
+	// Deviation is the Glicko-2 rating deviation (0 for Elo)
+	Deviation float64 `json:"deviation,omitempty"`
+	// Volatility is the Glicko-2 volatility (0 for Elo)
+	Volatility float64 `json:"volatility,omitempty"`
+	// Games is the number of rated games
+	Games int `json:"games"`
+}
+
+// Rater rates players from games added in chronological order.
+type Rater interface {
+	// Add rates a finished game. Unfinished games are ignored.
+	Add(record *skat.GameRecord) error
+	// Ratings returns the current ratings of all players, best first.
+	Ratings() []Rating
+}
+
+// New creates a rater for the algorithm.
+func New(algorithm Algorithm) Rater {
+	if algorithm == AlgorithmGlicko2 {
+		return NewGlicko2()
+	}
+	return NewElo()
+}
+
+// Seed creates a rater for the algorithm whose players start with the given ratings,
+// e.g. the final ratings of the previous season. Seeded players are listed once they
+// have played.
+func Seed(algorithm Algorithm, start []Rating) Rater {
+	if algorithm == AlgorithmGlicko2 {
+		g := NewGlicko2()
+		g.seed(start)
+		return g
+	}
+	e := NewElo()
+	e.seed(start)
+	return e
+}
+
+// Compute rates the games with the algorithm in order of their start and returns the ratings.
+func Compute(algorithm Algorithm, records []*skat.GameRecord) ([]Rating, error) {
+	return ComputeFrom(algorithm, nil, records)
+}
+
+// ComputeFrom rates the games like Compute, starting with the given ratings (see Seed).
+func ComputeFrom(algorithm Algorithm, start []Rating, records []*skat.GameRecord) ([]Rating, error) {
+	sorted := append([]*skat.GameRecord(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.Before(sorted[j].StartedAt)
+	})
+
+	rater := Seed(algorithm, start)
+	for _, record := range sorted {
+		if err := rater.Add(record); err != nil {
+			return nil, fmt.Errorf("game %s: %w", record.ID, err)
+		}
+	}
+	return rater.Ratings(), nil
+}
+
+// SoftReset returns the ratings moved toward the ratings of new players by a fraction
+// (0 = unchanged, 1 = full reset). Glicko-2 deviations grow toward InitialDeviation,
+// so the ratings adapt faster in the new season.
+func SoftReset(ratings []Rating, fraction float64) []Rating {
+	reset := make([]Rating, len(ratings))
+	for i, r := range ratings {
+		r.Rating += (InitialRating - r.Rating) * fraction
+		if r.Deviation > 0 {
+			r.Deviation += (InitialDeviation - r.Deviation) * fraction
+		}
+		r.Games = 0
+		reset[i] = r
+	}
+	sortRatings(reset)
+	return reset
+}
+
+// match is a pairwise result of a game: score is 1 if the player beat the opponent,
+// 0.5 for a draw and 0 for a loss.
+type match struct {
+	player   string
+	opponent string
+	score    float64
+}
+
+// matches returns the pairwise results of a finished game in both directions, or
+// nil if the game is unfinished.
+func matches(record *skat.GameRecord) ([]match, error) {
+	game, err := record.Replay(nil)
+	if err != nil {
+		return nil, err
+	}
+	if game.Result == nil && game.RamschResult == nil {
+		return nil, nil
+	}
+
+	var result []match
+	for i, a := range skat.AllPlayers {
+		for _, b := range skat.AllPlayers[i+1:] {
+			pa, pb := ai.SeatPoints(game, a), ai.SeatPoints(game, b)
+			score := 0.5
+			switch {
+			case pa > pb:
+				score = 1
+			case pa < pb:
+				score = 0
+			}
+			result = append(result,
+				match{player: record.Players[a], opponent: record.Players[b], score: score},
+				match{player: record.Players[b], opponent: record.Players[a], score: 1 - score})
+		}
+	}
+	return result, nil
+}
+
+// sortRatings sorts ratings best first and sets the ranks.
+func sortRatings(ratings []Rating) {
+	sort.Slice(ratings, func(i, j int) bool {
+		if ratings[i].Rating != ratings[j].Rating {
+			return ratings[i].Rating > ratings[j].Rating
+		}
+		return ratings[i].Player < ratings[j].Player
+	})
+	for i := range ratings {
+		ratings[i].Rank = i + 1
+		if i > 0 && ratings[i].Rating == ratings[i-1].Rating {
+			ratings[i].Rank = ratings[i-1].Rank
+		}
+	}
+}
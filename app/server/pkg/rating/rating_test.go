@@ -0,0 +1,156 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// find returns the rating of a player.
+func find(t *testing.T, ratings []Rating, player string) Rating {
+	t.Helper()
+	for _, r := range ratings {
+		if r.Player == player {
+			return r
+		}
+	}
+	t.Fatalf("no rating for %s", player)
+	return Rating{}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	if a, err := ParseAlgorithm("Glicko2"); err != nil || a != AlgorithmGlicko2 {
+		t.Errorf("ParseAlgorithm(Glicko2) = %s, %v", a, err)
+	}
+	if _, err := ParseAlgorithm("trueskill"); err == nil {
+		t.Error("ParseAlgorithm(trueskill) expected error")
+	}
+}
+
+func TestElo(t *testing.T) {
+	start := time.Date(2025, 3, 1, 19, 0, 0, 0, time.UTC)
+	records := []*skat.GameRecord{
+		recordtest.Grand(t, "g1", start, false),
+		recordtest.Grand(t, "g2", start.Add(time.Minute), false),
+	}
+	ratings, err := Compute(AlgorithmElo, records)
+	if err != nil {
+		t.Fatalf("Compute() error: %v", err)
+	}
+
+	anna, ben, carl := find(t, ratings, "anna"), find(t, ratings, "ben"), find(t, ratings, "carl")
+	if ratings[0].Player != "anna" || anna.Rank != 1 || anna.Games != 2 {
+		t.Errorf("anna = %+v, want rank 1 with 2 games", anna)
+	}
+	// Both defenders draw against each other and lose the same against anna
+	if ben.Rating != carl.Rating || ben.Rank != 2 || carl.Rank != 2 {
+		t.Errorf("ben = %+v, carl = %+v, want equal ratings", ben, carl)
+	}
+	// The first game moves anna by 2 * K/2, ratings sum to the initial ratings
+	if sum := anna.Rating + ben.Rating + carl.Rating; math.Abs(sum-3*InitialRating) > 1e-9 {
+		t.Errorf("sum of ratings = %f, want %d", sum, 3*InitialRating)
+	}
+	if anna.Rating <= InitialRating+EloK || anna.Deviation != 0 {
+		t.Errorf("anna = %+v, want rating above %d without deviation", anna, InitialRating+EloK)
+	}
+}
+
+func TestGlicko2Update(t *testing.T) {
+	// Example from Glickman, "Example of the Glicko-2 system"
+	scale := func(r, rd float64) glickoPlayer {
+		return glickoPlayer{mu: (r - InitialRating) / glickoScale, phi: rd / glickoScale}
+	}
+	player := scale(1500, 200)
+	opponents := []glickoPlayer{scale(1400, 30), scale(1550, 100), scale(1700, 300)}
+
+	mu, phi, sigma := glicko2Update(player.mu, player.phi, 0.06, opponents, []float64{1, 0, 0})
+	if r := mu*glickoScale + InitialRating; math.Abs(r-1464.06) > 0.01 {
+		t.Errorf("rating = %.2f, want 1464.06", r)
+	}
+	if rd := phi * glickoScale; math.Abs(rd-151.52) > 0.01 {
+		t.Errorf("deviation = %.2f, want 151.52", rd)
+	}
+	if math.Abs(sigma-0.05999) > 0.00001 {
+		t.Errorf("volatility = %.5f, want 0.05999", sigma)
+	}
+}
+
+func TestGlicko2Periods(t *testing.T) {
+	start := time.Date(2025, 3, 1, 19, 0, 0, 0, time.UTC)
+	g := NewGlicko2()
+	for i, at := range []time.Time{start, start.Add(time.Minute), start.Add(24 * time.Hour)} {
+		if err := g.Add(recordtest.Grand(t, "g", at, i == 2)); err != nil {
+			t.Fatalf("Add() error: %v", err)
+		}
+	}
+
+	ratings := g.Ratings()
+	anna := find(t, ratings, "anna")
+	if anna.Games != 3 || anna.Volatility == 0 {
+		t.Errorf("anna = %+v, want 3 games with volatility", anna)
+	}
+	if anna.Deviation >= InitialDeviation {
+		t.Errorf("anna deviation = %f, want below %d", anna.Deviation, InitialDeviation)
+	}
+
+	// After losing the last game, anna is still ahead of the defenders she beat twice
+	if ratings[0].Player != "anna" {
+		t.Errorf("best player = %s, want anna", ratings[0].Player)
+	}
+
+	// A long absence increases the deviation
+	before := anna.Deviation
+	if err := g.Add(recordtest.Grand(t, "g", start.Add(100*24*time.Hour), false)); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	g.pending = nil
+	if after := find(t, g.Ratings(), "anna").Deviation; after <= before {
+		t.Errorf("deviation after absence = %f, want above %f", after, before)
+	}
+}
+
+func TestSeedSoftReset(t *testing.T) {
+	previous := []Rating{
+		{Player: "anna", Rating: 1700, Deviation: 50, Volatility: 0.06, Games: 40},
+		{Player: "dora", Rating: 1400, Games: 10},
+	}
+	reset := SoftReset(previous, 0.5)
+	if reset[0].Player != "anna" || reset[0].Rating != 1600 || reset[0].Deviation != 200 || reset[0].Games != 0 {
+		t.Errorf("anna after reset = %+v, want 1600 with deviation 200", reset[0])
+	}
+	if reset[1].Rating != 1450 || reset[1].Deviation != 0 {
+		t.Errorf("dora after reset = %+v, want 1450 without deviation", reset[1])
+	}
+
+	start := time.Date(2025, 3, 1, 19, 0, 0, 0, time.UTC)
+	for _, alg := range []Algorithm{AlgorithmElo, AlgorithmGlicko2} {
+		ratings, err := ComputeFrom(alg, reset, []*skat.GameRecord{recordtest.Grand(t, "g1", start, false)})
+		if err != nil {
+			t.Fatalf("ComputeFrom(%s) error: %v", alg, err)
+		}
+		// dora has not played this season
+		if len(ratings) != 3 {
+			t.Fatalf("%s: %d ratings, want 3", alg, len(ratings))
+		}
+		if anna := find(t, ratings, "anna"); anna.Rating <= 1600 || anna.Games != 1 {
+			t.Errorf("%s: anna = %+v, want above her seeded 1600", alg, anna)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package recordtest builds finished game records for the tests of the packages
+// working on records (replays, notation, statistics, ratings, ...).
+package recordtest
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Start is the start time of the first record, 2025-03-01 18:00 UTC.
+var Start = time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+
+// Names returns the default players: anna, ben and carl.
+func Names() map[skat.Player]string {
+	return map[skat.Player]string{skat.Forehand: "anna", skat.Middlehand: "ben", skat.Rearhand: "carl"}
+}
+
+// Played plays a game with the strong AI on a deck shuffled by seed and returns its
+// record with the ID "g<seed>", started seed minutes after Start by the default players.
+func Played(t testing.TB, seed int64) *skat.GameRecord {
+	t.Helper()
+	return PlayedBy(t, seed, Names())
+}
+
+// PlayedBy is Played with other players.
+func PlayedBy(t testing.TB, seed int64, names map[skat.Player]string) *skat.GameRecord {
+	t.Helper()
+
+	rng := rand.New(rand.NewSource(seed))
+	deck := skat.NewDeck()
+	deck.ShuffleWith(rng)
+	return play(t, seed, deck, ai.New(ai.DifficultyStrong, rng), names)
+}
+
+// PlayedWith is Played with another AI on the deck of ShuffleSeed(seed).
+func PlayedWith(t testing.TB, seed int64, player ai.AIPlayer) *skat.GameRecord {
+	t.Helper()
+
+	deck := skat.NewDeck()
+	deck.ShuffleSeed(seed)
+	return play(t, seed, deck, player, Names())
+}
+
+// play deals the deck, lets player play all seats and returns the record.
+func play(t testing.TB, seed int64, deck *skat.Deck, player ai.AIPlayer, names map[skat.Player]string) *skat.GameRecord {
+	t.Helper()
+
+	hands, skatCards, err := skat.DealCards(deck)
+	if err != nil {
+		t.Fatalf("DealCards() error: %v", err)
+	}
+	game := skat.NewGame()
+	if err := game.Deal(hands, skatCards); err != nil {
+		t.Fatalf("Deal() error: %v", err)
+	}
+
+	players := map[skat.Player]ai.AIPlayer{skat.Forehand: player, skat.Middlehand: player, skat.Rearhand: player}
+	if err := ai.PlayGame(game, players); err != nil {
+		t.Fatalf("PlayGame() error: %v", err)
+	}
+
+	record, err := skat.NewGameRecord(fmt.Sprintf("g%d", seed), Start.Add(time.Duration(seed)*time.Minute), names, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+	return record
+}
+
+// Finish plays a dealt record (e.g. of a duplicate set) with the strong AI and returns
+// the finished record with the ID, start, players and shuffle of the dealt one.
+func Finish(t testing.TB, dealt *skat.GameRecord) *skat.GameRecord {
+	t.Helper()
+
+	game := skat.NewGame()
+	if err := game.Deal(dealt.Hands, dealt.Skat); err != nil {
+		t.Fatalf("Deal() error: %v", err)
+	}
+	player := ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(dealt.Shuffle.Seed)))
+	players := map[skat.Player]ai.AIPlayer{skat.Forehand: player, skat.Middlehand: player, skat.Rearhand: player}
+	if err := ai.PlayGame(game, players); err != nil {
+		t.Fatalf("PlayGame() error: %v", err)
+	}
+
+	record, err := skat.NewGameRecord(dealt.ID, dealt.StartedAt, dealt.Players, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+	record.Shuffle = dealt.Shuffle
+	return record
+}
+
+// Grand returns the record of a Grand Hand game of anna at Forehand, who holds the
+// bid of ben at 18 and plays the first legal card like the others. anna wins with
+// all Jacks and Aces; with lose she announces Null instead and loses.
+func Grand(t testing.TB, id string, startedAt time.Time, lose bool) *skat.GameRecord {
+	t.Helper()
+
+	codes := map[skat.Player]string{
+		skat.Forehand:   "CJ.SJ.HJ.DJ.CA.SA.HA.DA.CT.ST",
+		skat.Middlehand: "HT.DT.CK.SK.HK.DK.CQ.SQ.HQ.DQ",
+		skat.Rearhand:   "C9.S9.H9.D9.C8.S8.H8.D8.C7.S7",
+	}
+	hands := make(map[skat.Player]*skat.Hand)
+	for p, code := range codes {
+		hand, err := skat.HandFromCode(code)
+		if err != nil {
+			t.Fatalf("HandFromCode(%q) error: %v", code, err)
+		}
+		hands[p] = hand
+	}
+	skatCards, _ := skat.HandFromCode("H7.D7")
+
+	game := skat.NewGame()
+	if err := game.Deal(hands, skatCards); err != nil {
+		t.Fatalf("Deal() error: %v", err)
+	}
+	contract := skat.NewContract(skat.GameGrand)
+	if lose {
+		contract = skat.NewContract(skat.GameNull)
+	}
+	for _, err := range []error{
+		game.Bid(skat.Middlehand, 18),
+		game.Hold(skat.Forehand),
+		game.Pass(skat.Middlehand),
+		game.Pass(skat.Rearhand),
+		game.Announce(skat.Forehand, contract),
+	} {
+		if err != nil {
+			t.Fatalf("game setup error: %v", err)
+		}
+	}
+	for game.State == skat.StateTrickPlaying {
+		if err := game.PlayCard(*game.ActivePlayer(), game.LegalMoves()[0]); err != nil {
+			t.Fatalf("PlayCard() error: %v", err)
+		}
+	}
+
+	record, err := skat.NewGameRecord(id, startedAt, Names(), game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+	return record
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package render writes games as fixed-width text for terminals and logs: cards with
+// Unicode suit symbols or ASCII codes, the public view of a table (the game, the
+// tricks, the current trick and the own hand) and score sheets. It is shared by the
+// terminal client, the dashboard and debugging output.
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Style selects how cards are written.
+type Style int
+
+const (
+	// Unicode writes suit symbols and "10" for tens, e.g. "♣J ♥10"
+	Unicode Style = iota
+	// ASCII writes the card codes of the ISS protocol, e.g. "CJ HT"
+	ASCII
+)
+
+// suitSymbols are the Unicode symbols of the suits.
+var suitSymbols = map[skat.Suit]string{
+	skat.Clubs:    "♣",
+	skat.Spades:   "♠",
+	skat.Hearts:   "♥",
+	skat.Diamonds: "♦",
+}
+
+// Card returns a card, e.g. "♣J" or "♥10" (Unicode) or "CJ" or "HT" (ASCII).
+func Card(c skat.Card, style Style) string {
+	if style == ASCII {
+		return c.Code()
+	}
+	rank := c.Rank.Code()
+	if c.Rank == skat.Ten {
+		rank = "10"
+	}
+	return suitSymbols[c.Suit] + rank
+}
+
+// Cards returns cards separated by spaces.
+func Cards(cards []skat.Card, style Style) string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = Card(c, style)
+	}
+	return strings.Join(names, " ")
+}
+
+// NumberedCards returns cards numbered from 1 for selecting them by number, e.g.
+// "1:♣J  2:♠A".
+func NumberedCards(cards []skat.Card, style Style) string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = fmt.Sprintf("%d:%s", i+1, Card(c, style))
+	}
+	return strings.Join(names, "  ")
+}
+
+// Contract returns the name of a contract, e.g. "Grand Hand Schneider" ("?" for nil).
+func Contract(c *skat.Contract) string {
+	if c == nil {
+		return "?"
+	}
+	name := c.GameType.String()
+	for _, m := range []struct {
+		set  bool
+		name string
+	}{{c.Hand, "Hand"}, {c.Ouvert, "Ouvert"}, {c.Schneider, "Schneider"}, {c.Schwarz, "Schwarz"}} {
+		if m.set {
+			name += " " + m.name
+		}
+	}
+	return name
+}
+
+// Sorted returns a copy of the cards in display order: sorted for the game type, or
+// Jacks first and by suit (as for Grand) before the announcement (nil contract).
+func Sorted(cards []skat.Card, contract *skat.Contract) []skat.Card {
+	sorted := append([]skat.Card(nil), cards...)
+	gameType := skat.GameGrand
+	if contract != nil {
+		gameType = contract.GameType
+	}
+	skat.SortForGame(sorted, gameType)
+	return sorted
+}
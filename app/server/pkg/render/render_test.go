@@ -0,0 +1,151 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/scoresheet"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// mustCards parses card codes separated by dots.
+func mustCards(t *testing.T, code string) []skat.Card {
+	t.Helper()
+	hand, err := skat.HandFromCode(code)
+	if err != nil {
+		t.Fatalf("HandFromCode(%q) error: %v", code, err)
+	}
+	return hand.Cards
+}
+
+func TestCards(t *testing.T) {
+	cards := mustCards(t, "CJ.HT.D7")
+	if got := Cards(cards, Unicode); got != "♣J ♥10 ♦7" {
+		t.Errorf("Cards(Unicode) = %q", got)
+	}
+	if got := Cards(cards, ASCII); got != "CJ HT D7" {
+		t.Errorf("Cards(ASCII) = %q", got)
+	}
+	if got := NumberedCards(cards[:2], ASCII); got != "1:CJ  2:HT" {
+		t.Errorf("NumberedCards() = %q", got)
+	}
+}
+
+func TestContract(t *testing.T) {
+	contract := skat.NewContract(skat.GameGrand)
+	contract.Hand = true
+	contract.Schneider = true
+	if got := Contract(contract); got != "Grand Hand Schneider" {
+		t.Errorf("Contract() = %q", got)
+	}
+	if got := Contract(nil); got != "?" {
+		t.Errorf("Contract(nil) = %q, want ?", got)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	cards := mustCards(t, "D7.SJ.CA.CJ")
+	if got := Cards(Sorted(cards, nil), ASCII); got != "CJ SJ CA D7" {
+		t.Errorf("Sorted() = %q, want Jacks first", got)
+	}
+	if Cards(cards, ASCII) != "D7 SJ CA CJ" {
+		t.Error("Sorted() changed the cards")
+	}
+}
+
+func TestViewWrite(t *testing.T) {
+	contract := skat.NewContract(skat.GameGrand)
+	view := &View{
+		Table:    "t1",
+		Players:  []string{"anna", "ben", "carl"},
+		Bid:      24,
+		Declarer: "anna",
+		Contract: contract,
+		Tricks:   []Trick{{Cards: mustCards(t, "CJ.SJ.HJ"), Winner: "anna", Points: 6}},
+		Trick:    mustCards(t, "CA.CT"),
+		Winning:  0,
+		Hand:     mustCards(t, "D7.DJ"),
+	}
+
+	var b strings.Builder
+	if err := view.Write(&b, ASCII); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	want := "=== t1: anna, ben, carl ===\n" +
+		"anna plays Grand (bid 24)\n" +
+		"   1. CJ SJ HJ       -> anna (6)\n" +
+		"   2. CA CT (CA wins)\n" +
+		"Hand: 1:DJ  2:D7\n"
+	if b.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestViewGame(t *testing.T) {
+	tests := []struct {
+		view View
+		want string
+	}{
+		{View{}, "Bidding"},
+		{View{Bid: 18}, "Bidding at 18"},
+		{View{Contract: skat.NewContract(skat.GameRamsch)}, "All passed: Ramsch"},
+	}
+	for _, tt := range tests {
+		if got := tt.view.Game(); got != tt.want {
+			t.Errorf("Game() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestWriteScores(t *testing.T) {
+	var b strings.Builder
+	WriteScores(&b, []ScoreLine{
+		{Declarer: "anna", Game: "Grand", Value: 48},
+		{Declarer: "-", Game: "passed"},
+		{Declarer: "anna", Game: "Clubs", Value: -48},
+	})
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("WriteScores() = %d lines, want 3", len(lines))
+	}
+	if !strings.HasSuffix(lines[2], "-48  (total 0)") {
+		t.Errorf("line 3 = %q, want the running total 0", lines[2])
+	}
+}
+
+func TestWriteSheet(t *testing.T) {
+	sheet := &scoresheet.Sheet{
+		Players: []string{"anna", "bernhardine"},
+		Entries: []scoresheet.Entry{
+			{Number: 1, Date: time.Now(), Declarer: "anna", Contract: "G", Won: true, Players: []string{"anna"}, Score: 72, Totals: []int{72, 0}},
+			{Number: 2, Declarer: "bernhardine", Contract: "C", Players: []string{"bernhardine"}, Score: -48, Totals: []int{72, -48}},
+			{Number: 3, Declarer: "anna", Contract: "D", Won: true, Players: []string{"anna"}, Score: 36, Bock: true, Totals: []int{108, -48}},
+		},
+	}
+	var b strings.Builder
+	if err := WriteSheet(&b, sheet); err != nil {
+		t.Fatalf("WriteSheet() error: %v", err)
+	}
+	want := "  #  Player       Game     Score     anna bernhard\n" +
+		"  1  anna         G          +72       72        0\n" +
+		"  2  bernhardine  C lost     -48       72      -48\n" +
+		"  3  anna         D x2       +36      108      -48\n"
+	if b.String() != want {
+		t.Errorf("WriteSheet() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
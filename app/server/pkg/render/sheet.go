@@ -0,0 +1,83 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/scoresheet"
+)
+
+// ScoreLine is a finished game of a live score sheet.
+type ScoreLine struct {
+	// Declarer is the declarer name ("-" for passed games)
+	Declarer string
+	// Game is the name of the game, e.g. "Grand Hand" or "passed"
+	Game  string
+	Value int
+}
+
+// WriteScores writes a live score sheet: one line per game with the running total of
+// its declarer.
+func WriteScores(w io.Writer, lines []ScoreLine) error {
+	var b strings.Builder
+	totals := make(map[string]int)
+	for i, s := range lines {
+		totals[s.Declarer] += s.Value
+		fmt.Fprintf(&b, "  %2d. %-12s %-24s %+5d  (total %d)\n", i+1, s.Declarer, s.Game, s.Value, totals[s.Declarer])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteSheet writes a score sheet as a table: one line per game with the declarer,
+// the contract (marked "x2" in Bock deals), the score and the running totals of all
+// players in columns.
+func WriteSheet(w io.Writer, sheet *scoresheet.Sheet) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%3s  %-12s %-8s %5s", "#", "Player", "Game", "Score")
+	for _, p := range sheet.Players {
+		fmt.Fprintf(&b, " %8s", truncate(p, 8))
+	}
+	b.WriteString("\n")
+
+	for _, e := range sheet.Entries {
+		game := e.Contract
+		if e.Declarer != "" && !e.Won {
+			game += " lost"
+		}
+		if e.Bock {
+			game += " x2"
+		}
+		fmt.Fprintf(&b, "%3d  %-12s %-8s %+5d", e.Number, truncate(strings.Join(e.Players, ", "), 12), game, e.Score)
+		for _, total := range e.Totals {
+			fmt.Fprintf(&b, " %8d", total)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// truncate shortens a name to at most n characters.
+func truncate(name string, n int) string {
+	runes := []rune(name)
+	if len(runes) <= n {
+		return name
+	}
+	return string(runes[:n])
+}
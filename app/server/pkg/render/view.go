@@ -0,0 +1,90 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// View is the public view of a game at a table, as seen by an observer, or by a
+// player with the own hand.
+type View struct {
+	Table string
+	// Players are the player names by position
+	Players []string
+	// Bid is the current bid (0 = no bid yet)
+	Bid int
+	// Declarer is the name of the declarer ("" = bidding or Ramsch)
+	Declarer string
+	// Contract is the announced contract (nil = bidding)
+	Contract *skat.Contract
+	// Tricks are the complete tricks
+	Tricks []Trick
+	// Trick are the cards of the current trick
+	Trick []skat.Card
+	// Winning is the index of the card of the current trick that wins so far
+	Winning int
+	// Hand is the own hand (nil = observer)
+	Hand []skat.Card
+}
+
+// Trick is a complete trick.
+type Trick struct {
+	Cards  []skat.Card
+	Winner string
+	Points int
+}
+
+// Game returns the state line of the game, e.g. "anna plays Grand Hand (bid 24)".
+func (v *View) Game() string {
+	switch {
+	case v.Contract != nil && v.Declarer != "":
+		return fmt.Sprintf("%s plays %s (bid %d)", v.Declarer, Contract(v.Contract), v.Bid)
+	case v.Contract != nil:
+		return "All passed: Ramsch"
+	case v.Bid > 0:
+		return fmt.Sprintf("Bidding at %d", v.Bid)
+	default:
+		return "Bidding"
+	}
+}
+
+// Write writes the view: the table and its players, the game, the numbered tricks
+// with their winners and points, the current trick with its winning card and the own
+// hand.
+func (v *View) Write(w io.Writer, style Style) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "=== %s: %s ===\n", v.Table, strings.Join(v.Players, ", "))
+	b.WriteString(v.Game() + "\n")
+	for i, t := range v.Tricks {
+		fmt.Fprintf(&b, "  %2d. %-14s -> %s (%d)\n", i+1, Cards(t.Cards, style), t.Winner, t.Points)
+	}
+	if len(v.Trick) > 0 {
+		fmt.Fprintf(&b, "  %2d. %s", len(v.Tricks)+1, Cards(v.Trick, style))
+		if v.Winning >= 0 && v.Winning < len(v.Trick) {
+			fmt.Fprintf(&b, " (%s wins)", Card(v.Trick[v.Winning], style))
+		}
+		b.WriteString("\n")
+	}
+	if len(v.Hand) > 0 {
+		fmt.Fprintf(&b, "Hand: %s\n", NumberedCards(Sorted(v.Hand, v.Contract), style))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
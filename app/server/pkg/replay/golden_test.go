@@ -0,0 +1,108 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// updateGolden rewrites the recorded results of the golden games. Use it only after
+// an intended rule change: go test ./pkg/replay -run TestGoldenGames -update
+var updateGolden = flag.Bool("update", false, "update the results of the golden games")
+
+// goldenDir holds the curated corpus of complete games (one replay file per game).
+const goldenDir = "testdata/golden"
+
+// ============================================================================
+// Golden Game Tests
+// ============================================================================
+
+// TestGoldenGames replays every game of the corpus through the engine and checks that
+// all moves are accepted and the result matches the recorded one.
+func TestGoldenGames(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(goldenDir, "*.json"))
+	if err != nil {
+		t.Fatalf("Glob() error: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no golden games in %s", goldenDir)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			want := readGolden(t, file)
+
+			record, err := want.Record()
+			if err != nil {
+				t.Fatalf("Record() error: %v", err)
+			}
+			got, err := New(record)
+			if err != nil {
+				t.Fatalf("replay rejected: %v", err)
+			}
+
+			if *updateGolden {
+				writeGolden(t, file, got)
+				return
+			}
+			if !reflect.DeepEqual(got.Declarer, want.Declarer) || got.Contract != want.Contract || got.Bid != want.Bid {
+				t.Errorf("contract = %s by %v at %d, want %s by %v at %d",
+					got.Contract, got.Declarer, got.Bid, want.Contract, want.Declarer, want.Bid)
+			}
+			if !reflect.DeepEqual(got.Result, want.Result) {
+				t.Errorf("result = %+v, want %+v", got.Result, want.Result)
+			}
+			if !reflect.DeepEqual(got.Ramsch, want.Ramsch) {
+				t.Errorf("Ramsch result = %+v, want %+v", got.Ramsch, want.Ramsch)
+			}
+		})
+	}
+}
+
+// readGolden reads a golden game.
+func readGolden(t *testing.T, file string) *Replay {
+	t.Helper()
+
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer f.Close()
+
+	r, err := Read(f)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	return r
+}
+
+// writeGolden rewrites a golden game with the current result.
+func writeGolden(t *testing.T, file string, r *Replay) {
+	t.Helper()
+
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	defer f.Close()
+
+	if err := r.Write(f); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+}
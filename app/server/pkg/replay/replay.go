@@ -0,0 +1,488 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay serializes complete games to JSON for websites and analysis tools.
+//
+// The format is documented in docs/REPLAY-FORMAT.md.
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Version is the version of the replay format.
+const Version = 1
+
+// Move types of the replay format.
+const (
+	MoveBid      = "bid"
+	MoveHold     = "hold"
+	MovePass     = "pass"
+	MovePickUp   = "pickup"
+	MoveDiscard  = "discard"
+	MoveAnnounce = "announce"
+	MovePlay     = "play"
+	MoveKontra   = "kontra"
+	MoveRe       = "re"
+	MoveClaim    = "claim"
+	MoveConcede  = "concede"
+	MoveAccept   = "accept"
+	MoveReject   = "reject"
+	MoveAbandon  = "abandon"
+	MoveRedeal   = "redeal"
+)
+
+// Replay is a complete game: players, deal, moves and result.
+type Replay struct {
+	Version   int          `json:"version"`
+	ID        string       `json:"id"`
+	StartedAt time.Time    `json:"startedAt,omitzero"`
+	Players   []Player     `json:"players"`
+	Deal      Deal         `json:"deal"`
+	Declarer  *int         `json:"declarer"`
+	Contract  string       `json:"contract,omitempty"`
+	Bid       int          `json:"bid"`
+	Moves     []Move       `json:"moves"`
+	Result    *Result      `json:"result,omitempty"`
+	Ramsch    *RamschScore `json:"ramsch,omitempty"`
+	PassedIn  bool         `json:"passedIn,omitempty"`
+	Rules     *Rules       `json:"rules,omitempty"`
+	Forfeit   string       `json:"forfeit,omitempty"`
+	Comments  []Comment    `json:"comments,omitempty"`
+	Mistakes  []Mistake    `json:"mistakes,omitempty"`
+	Shuffle   *Shuffle     `json:"shuffle,omitempty"`
+	Engine    int          `json:"engine,omitempty"`
+}
+
+// Rules are the all-pass and Ramsch rules of the game, if they differ from the
+// defaults (Ramsch, skat to the last trick, card points).
+type Rules struct {
+	AllPass       string `json:"allPass,omitempty"`
+	RamschSkat    string `json:"ramschSkat,omitempty"`
+	RamschScoring string `json:"ramschScoring,omitempty"`
+}
+
+// Player is a player of the game.
+type Player struct {
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+}
+
+// Deal is the card distribution.
+type Deal struct {
+	Hands [3][]string `json:"hands"`
+	Skat  []string    `json:"skat"`
+}
+
+// Shuffle describes how the deal was shuffled.
+type Shuffle struct {
+	Mode string `json:"mode"`
+	Seed *int64 `json:"seed,omitempty"`
+}
+
+// Move is a single player move.
+type Move struct {
+	Index    int        `json:"index"`
+	Player   int        `json:"player"`
+	Type     string     `json:"type"`
+	Value    int        `json:"value,omitempty"`
+	Cards    []string   `json:"cards,omitempty"`
+	Contract string     `json:"contract,omitempty"`
+	Time     *time.Time `json:"time,omitempty"`
+}
+
+// Comment is a post-game comment on the game (move 0) or on a move.
+type Comment struct {
+	Move   int       `json:"move"`
+	Author string    `json:"author"`
+	Text   string    `json:"text"`
+	Time   time.Time `json:"time,omitzero"`
+}
+
+// Mistake is a card play that the post-game analysis found to lose points against best play.
+type Mistake struct {
+	Move     int    `json:"move"`
+	Player   int    `json:"player"`
+	Card     string `json:"card"`
+	Best     string `json:"best"`
+	Loss     int    `json:"loss"`
+	GameLost bool   `json:"gameLost,omitempty"`
+}
+
+// Result is the result of a normal game. The game value and the score include the
+// Multiplier of Kontra and Re (omitted without Kontra).
+type Result struct {
+	DeclarerWon    bool `json:"declarerWon"`
+	DeclarerPoints int  `json:"declarerPoints"`
+	TrickPoints    int  `json:"trickPoints"`
+	SkatPoints     int  `json:"skatPoints"`
+	DeclarerTricks int  `json:"declarerTricks"`
+	Matadors       int  `json:"matadors"`
+	GameValue      int  `json:"gameValue"`
+	Overbid        bool `json:"overbid"`
+	Schneider      bool `json:"schneider"`
+	Schwarz        bool `json:"schwarz"`
+	Kontra         bool `json:"kontra,omitempty"`
+	Re             bool `json:"re,omitempty"`
+	Multiplier     int  `json:"multiplier,omitempty"`
+	Score          int  `json:"score"`
+}
+
+// RamschScore is the result of a Ramsch game.
+type RamschScore struct {
+	Loser             int    `json:"loser"`
+	Losers            []int  `json:"losers,omitempty"`
+	Points            [3]int `json:"points"`
+	Score             int    `json:"score"`
+	Durchmarsch       bool   `json:"durchmarsch"`
+	DurchmarschPlayer *int   `json:"durchmarschPlayer,omitempty"`
+	SkatPlayer        int    `json:"skatPlayer"`
+	SkatPoints        int    `json:"skatPoints"`
+}
+
+// New creates the replay of a game record. The record is replayed to determine the result.
+func New(record *skat.GameRecord) (*Replay, error) {
+	game, err := record.Replay(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replay{
+		Version:   Version,
+		ID:        record.ID,
+		StartedAt: record.StartedAt,
+		Deal:      Deal{Skat: codes(record.Skat.Cards)},
+		Moves:     make([]Move, 0, len(record.Actions)),
+		Engine:    record.Engine,
+		PassedIn:  game.PassedIn,
+	}
+	if record.AllPass != skat.AllPassRamsch || record.RamschSkat != skat.RamschSkatLastTrick || record.RamschScoring != skat.RamschScoringPoints {
+		r.Rules = &Rules{}
+		if record.AllPass != skat.AllPassRamsch {
+			r.Rules.AllPass = record.AllPass.String()
+		}
+		if record.RamschSkat != skat.RamschSkatLastTrick {
+			r.Rules.RamschSkat = record.RamschSkat.String()
+		}
+		if record.RamschScoring != skat.RamschScoringPoints {
+			r.Rules.RamschScoring = record.RamschScoring.String()
+		}
+	}
+	if mode := record.Shuffle.Mode; mode != "" {
+		r.Shuffle = &Shuffle{Mode: mode}
+		if mode == skat.ShuffleSeeded {
+			seed := record.Shuffle.Seed
+			r.Shuffle.Seed = &seed
+		}
+	}
+	for _, p := range skat.AllPlayers {
+		r.Players = append(r.Players, Player{Position: p.Index(), Name: record.Players[p]})
+		r.Deal.Hands[p.Index()] = codes(record.Hands[p].Cards)
+	}
+	if game.Declarer != nil {
+		declarer := game.Declarer.Index()
+		r.Declarer = &declarer
+	}
+	if game.Contract != nil {
+		r.Contract = game.Contract.Code()
+	}
+	if game.Bidding != nil {
+		r.Bid = game.Bidding.FinalBid
+	}
+
+	for i, action := range record.Actions {
+		r.Moves = append(r.Moves, NewMove(i+1, action))
+	}
+
+	if result := game.Result; result != nil {
+		r.Result = NewResult(result)
+	}
+	if game.Forfeit != skat.ForfeitNone {
+		r.Forfeit = game.Forfeit.String()
+	}
+	if result := game.RamschResult; result != nil {
+		r.Ramsch = NewRamschScore(result)
+	}
+	for _, c := range record.Comments {
+		r.Comments = append(r.Comments, Comment{Move: c.Move, Author: c.Author, Text: c.Text, Time: c.Time})
+	}
+	return r, nil
+}
+
+// NewResult converts the result of a normal game.
+func NewResult(result *skat.GameResult) *Result {
+	r := &Result{
+		DeclarerWon:    result.DeclarerWon,
+		DeclarerPoints: result.DeclarerPoints,
+		TrickPoints:    result.TrickPoints,
+		SkatPoints:     result.SkatPoints,
+		DeclarerTricks: result.DeclarerTricks,
+		Matadors:       result.Matadors,
+		GameValue:      result.GameValue,
+		Overbid:        result.Overbid,
+		Schneider:      result.Schneider,
+		Schwarz:        result.Schwarz,
+		Score:          result.Score,
+	}
+	if result.Multiplier > 1 {
+		r.Kontra, r.Re, r.Multiplier = result.Kontra, result.Re, result.Multiplier
+	}
+	return r
+}
+
+// NewRamschScore converts the result of a Ramsch game. A Durchmarsch has no loser
+// (-1) and scores the Durchmarsch player.
+func NewRamschScore(result *skat.RamschResult) *RamschScore {
+	score := &RamschScore{
+		Loser:       -1,
+		Score:       result.LoserScore,
+		Durchmarsch: result.Durchmarsch,
+		SkatPlayer:  result.SkatPlayer.Index(),
+		SkatPoints:  result.SkatPoints,
+	}
+	if len(result.Losers) > 0 {
+		score.Loser = result.Losers[0].Index()
+	}
+	if result.Durchmarsch && result.DurchmarschPlayer != nil {
+		index := result.DurchmarschPlayer.Index()
+		score.DurchmarschPlayer = &index
+		score.Score = result.DurchmarschScore
+	}
+	for _, p := range skat.AllPlayers {
+		score.Points[p.Index()] = result.PlayerPoints[p]
+	}
+	if len(result.Losers) > 1 {
+		for _, p := range result.Losers {
+			score.Losers = append(score.Losers, p.Index())
+		}
+	}
+	return score
+}
+
+// NewMove converts a game action to the move with the given index (starting at 1).
+func NewMove(index int, action skat.Action) Move {
+	move := Move{Index: index, Player: action.Player.Index(), Cards: codes(action.Cards)}
+	if !action.Time.IsZero() {
+		t := action.Time
+		move.Time = &t
+	}
+
+	switch action.Type {
+	case skat.ActionBid:
+		move.Type = MoveBid
+		move.Value = action.Value
+	case skat.ActionHold:
+		move.Type = MoveHold
+	case skat.ActionPass:
+		move.Type = MovePass
+	case skat.ActionPickUpSkat:
+		move.Type = MovePickUp
+	case skat.ActionDiscard:
+		move.Type = MoveDiscard
+	case skat.ActionAnnounce:
+		move.Type = MoveAnnounce
+		move.Contract = action.Contract.Code()
+	case skat.ActionPlayCard:
+		move.Type = MovePlay
+	case skat.ActionKontra:
+		move.Type = MoveKontra
+	case skat.ActionRe:
+		move.Type = MoveRe
+	case skat.ActionClaim:
+		move.Type = MoveClaim
+	case skat.ActionConcede:
+		move.Type = MoveConcede
+	case skat.ActionAcceptClaim:
+		move.Type = MoveAccept
+	case skat.ActionRejectClaim:
+		move.Type = MoveReject
+	case skat.ActionAbandon:
+		move.Type = MoveAbandon
+	case skat.ActionRedeal:
+		move.Type = MoveRedeal
+	}
+	return move
+}
+
+// Record converts the replay back to a game record.
+func (r *Replay) Record() (*skat.GameRecord, error) {
+	if r.Version != Version {
+		return nil, fmt.Errorf("unsupported replay version: %d", r.Version)
+	}
+
+	record := &skat.GameRecord{
+		ID:        r.ID,
+		StartedAt: r.StartedAt,
+		Players:   make(map[skat.Player]string),
+		Hands:     make(map[skat.Player]*skat.Hand),
+		Actions:   make([]skat.Action, 0, len(r.Moves)),
+		Engine:    r.Engine,
+	}
+	if r.PassedIn {
+		record.AllPass = skat.AllPassThrowIn
+	}
+	if err := r.Rules.apply(record); err != nil {
+		return nil, err
+	}
+	if r.Shuffle != nil {
+		record.Shuffle.Mode = r.Shuffle.Mode
+		if r.Shuffle.Seed != nil {
+			record.Shuffle.Seed = *r.Shuffle.Seed
+		}
+	}
+	for _, p := range r.Players {
+		player, err := skat.PlayerFromIndex(p.Position)
+		if err != nil {
+			return nil, err
+		}
+		record.Players[player] = p.Name
+	}
+
+	var err error
+	for _, p := range skat.AllPlayers {
+		if record.Hands[p], err = skat.HandFromCode(strings.Join(r.Deal.Hands[p.Index()], ".")); err != nil {
+			return nil, fmt.Errorf("invalid %s hand: %w", p, err)
+		}
+	}
+	if record.Skat, err = skat.HandFromCode(strings.Join(r.Deal.Skat, ".")); err != nil {
+		return nil, fmt.Errorf("invalid skat: %w", err)
+	}
+
+	for _, move := range r.Moves {
+		action, err := move.action()
+		if err != nil {
+			return nil, fmt.Errorf("move %d: %w", move.Index, err)
+		}
+		record.Actions = append(record.Actions, action)
+	}
+	for _, c := range r.Comments {
+		if c.Move < 0 || c.Move > len(r.Moves) {
+			return nil, fmt.Errorf("comment on invalid move %d", c.Move)
+		}
+		record.Comments = append(record.Comments, skat.Comment{Move: c.Move, Author: c.Author, Text: c.Text, Time: c.Time})
+	}
+	return record, nil
+}
+
+// action converts the move to a game action.
+func (m Move) action() (skat.Action, error) {
+	player, err := skat.PlayerFromIndex(m.Player)
+	if err != nil {
+		return skat.Action{}, err
+	}
+	action := skat.Action{Player: player, Value: m.Value}
+	if m.Time != nil {
+		action.Time = *m.Time
+	}
+	for _, code := range m.Cards {
+		card, err := skat.CardFromCode(code)
+		if err != nil {
+			return action, err
+		}
+		action.Cards = append(action.Cards, card)
+	}
+
+	switch m.Type {
+	case MoveBid:
+		action.Type = skat.ActionBid
+	case MoveHold:
+		action.Type = skat.ActionHold
+	case MovePass:
+		action.Type = skat.ActionPass
+	case MovePickUp:
+		action.Type = skat.ActionPickUpSkat
+	case MoveDiscard:
+		action.Type = skat.ActionDiscard
+	case MoveAnnounce:
+		action.Type = skat.ActionAnnounce
+		if action.Contract, err = skat.ContractFromCode(m.Contract); err != nil {
+			return action, err
+		}
+	case MovePlay:
+		action.Type = skat.ActionPlayCard
+	case MoveKontra:
+		action.Type = skat.ActionKontra
+	case MoveRe:
+		action.Type = skat.ActionRe
+	case MoveClaim:
+		action.Type = skat.ActionClaim
+	case MoveConcede:
+		action.Type = skat.ActionConcede
+	case MoveAccept:
+		action.Type = skat.ActionAcceptClaim
+	case MoveReject:
+		action.Type = skat.ActionRejectClaim
+	case MoveAbandon:
+		action.Type = skat.ActionAbandon
+	case MoveRedeal:
+		action.Type = skat.ActionRedeal
+	default:
+		return action, fmt.Errorf("invalid move type: %s", m.Type)
+	}
+	return action, nil
+}
+
+// Write writes the replay as indented JSON.
+func (r *Replay) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// Read reads a replay from JSON.
+func Read(rd io.Reader) (*Replay, error) {
+	r := &Replay{}
+	if err := json.NewDecoder(rd).Decode(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// codes returns the ISS codes of the cards.
+func codes(cards []skat.Card) []string {
+	result := make([]string, len(cards))
+	for i, c := range cards {
+		result[i] = c.Code()
+	}
+	return result
+}
+
+// apply sets the rules of the record (none for nil).
+func (rules *Rules) apply(record *skat.GameRecord) error {
+	if rules == nil {
+		return nil
+	}
+	if rules.AllPass != "" {
+		if err := record.AllPass.UnmarshalText([]byte(rules.AllPass)); err != nil {
+			return err
+		}
+	}
+	if rules.RamschSkat != "" {
+		if err := record.RamschSkat.UnmarshalText([]byte(rules.RamschSkat)); err != nil {
+			return err
+		}
+	}
+	if rules.RamschScoring != "" {
+		if err := record.RamschScoring.UnmarshalText([]byte(rules.RamschScoring)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
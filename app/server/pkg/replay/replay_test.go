@@ -0,0 +1,221 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	record := recordtest.Played(t, 1)
+	record.Shuffle = skat.ShuffleInfo{Mode: skat.ShuffleSeeded, Seed: 0}
+
+	r, err := New(record)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if r.Version != Version || r.ID != "g1" || r.Players[1].Name != "ben" {
+		t.Errorf("New() = version %d, id %s, player 1 %s", r.Version, r.ID, r.Players[1].Name)
+	}
+	if len(r.Moves) != len(record.Actions) {
+		t.Errorf("len(Moves) = %d, want %d", len(r.Moves), len(record.Actions))
+	}
+	if r.Result == nil && r.Ramsch == nil {
+		t.Error("New() has no result")
+	}
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"type": "play"`) {
+		t.Errorf("Write() has no card plays:\n%s", buf.String())
+	}
+
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	back, err := read.Record()
+	if err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	again, err := New(back)
+	if err != nil {
+		t.Fatalf("New() of the read record error: %v", err)
+	}
+	if *again.Declarer != *r.Declarer || again.Contract != r.Contract || *again.Result != *r.Result {
+		t.Errorf("round trip = %s by %d %+v, want %s by %d %+v",
+			again.Contract, *again.Declarer, *again.Result, r.Contract, *r.Declarer, *r.Result)
+	}
+	if back.Shuffle != record.Shuffle || back.Engine != skat.EngineVersion {
+		t.Errorf("round trip shuffle = %+v, engine %d, want %+v, engine %d", back.Shuffle, back.Engine, record.Shuffle, skat.EngineVersion)
+	}
+	for i, action := range back.Actions {
+		if !action.Time.Equal(record.Actions[i].Time) {
+			t.Errorf("action %d time = %s, want %s", i+1, action.Time, record.Actions[i].Time)
+		}
+	}
+}
+
+func TestReplayPassedIn(t *testing.T) {
+	deck := skat.NewDeck()
+	deck.ShuffleSeed(7)
+	hands, skatCards, err := skat.DealCards(deck)
+	if err != nil {
+		t.Fatalf("DealCards() error: %v", err)
+	}
+	game := skat.NewGame()
+	game.AllPass = skat.AllPassThrowIn
+	if err := game.Deal(hands, skatCards); err != nil {
+		t.Fatalf("Deal() error: %v", err)
+	}
+	for _, p := range []skat.Player{skat.Middlehand, skat.Rearhand, skat.Forehand} {
+		if err := game.Pass(p); err != nil {
+			t.Fatalf("Pass(%s) error: %v", p, err)
+		}
+	}
+	names := map[skat.Player]string{skat.Forehand: "anna", skat.Middlehand: "ben", skat.Rearhand: "carl"}
+	record, err := skat.NewGameRecord("g1", time.Now(), names, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+
+	r, err := New(record)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if !r.PassedIn || r.Contract != "" || r.Result != nil || r.Ramsch != nil {
+		t.Errorf("New() = passed in %v, contract %q, want thrown in without result", r.PassedIn, r.Contract)
+	}
+	back, err := r.Record()
+	if err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	if back.AllPass != skat.AllPassThrowIn {
+		t.Errorf("Record() all-pass rule = %s, want %s", back.AllPass, skat.AllPassThrowIn)
+	}
+}
+
+func TestReplayKontra(t *testing.T) {
+	deck := skat.NewDeck()
+	deck.ShuffleSeed(3)
+	hands, skatCards, err := skat.DealCards(deck)
+	if err != nil {
+		t.Fatalf("DealCards() error: %v", err)
+	}
+	game := skat.NewGame()
+	if err := game.Deal(hands, skatCards); err != nil {
+		t.Fatalf("Deal() error: %v", err)
+	}
+	for _, step := range []func() error{
+		func() error { return game.Bid(skat.Middlehand, 18) },
+		func() error { return game.Hold(skat.Forehand) },
+		func() error { return game.Pass(skat.Middlehand) },
+		func() error { return game.Pass(skat.Rearhand) },
+		func() error { return game.Announce(skat.Forehand, skat.NewContract(skat.GameGrand)) },
+		func() error { return game.Kontra(skat.Middlehand) },
+		func() error { return game.Re(skat.Forehand) },
+	} {
+		if err := step(); err != nil {
+			t.Fatalf("setup error: %v", err)
+		}
+	}
+	player := ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(3)))
+	players := map[skat.Player]ai.AIPlayer{skat.Forehand: player, skat.Middlehand: player, skat.Rearhand: player}
+	if err := ai.PlayGame(game, players); err != nil {
+		t.Fatalf("PlayGame() error: %v", err)
+	}
+	names := map[skat.Player]string{skat.Forehand: "anna", skat.Middlehand: "ben", skat.Rearhand: "carl"}
+	record, err := skat.NewGameRecord("g1", time.Now(), names, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+
+	r, err := New(record)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if !r.Result.Kontra || !r.Result.Re || r.Result.Multiplier != 4 || r.Result.Score != game.Result.Score {
+		t.Errorf("Result = %+v, want Kontra and Re with multiplier 4", *r.Result)
+	}
+	back, err := r.Record()
+	if err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	again, err := New(back)
+	if err != nil {
+		t.Fatalf("New() of the record error: %v", err)
+	}
+	if *again.Result != *r.Result {
+		t.Errorf("round trip = %+v, want %+v", *again.Result, *r.Result)
+	}
+}
+
+func TestReplayComments(t *testing.T) {
+	record := recordtest.Played(t, 3)
+	at := time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)
+	record.Comments = []skat.Comment{
+		{Move: 0, Author: "anna", Text: "Well played", Time: at},
+		{Move: 5, Author: "ben", Text: "Why this bid?", Time: at},
+	}
+
+	r, err := New(record)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if len(r.Comments) != 2 || r.Comments[1].Move != 5 || r.Comments[1].Author != "ben" {
+		t.Fatalf("New() comments = %+v", r.Comments)
+	}
+
+	back, err := r.Record()
+	if err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+	if len(back.Comments) != 2 || back.Comments[0] != record.Comments[0] || back.Comments[1] != record.Comments[1] {
+		t.Errorf("Record() comments = %+v, want %+v", back.Comments, record.Comments)
+	}
+
+	r.Comments[1].Move = len(r.Moves) + 1
+	if _, err := r.Record(); err == nil {
+		t.Error("Record() with comment on invalid move: expected error")
+	}
+}
+
+func TestReplayRecordErrors(t *testing.T) {
+	r, err := New(recordtest.Played(t, 2))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	r.Moves[0].Type = "shout"
+	if _, err := r.Record(); err == nil {
+		t.Error("Record() with invalid move type: expected error")
+	}
+
+	r.Version = 99
+	if _, err := r.Record(); err == nil {
+		t.Error("Record() with unsupported version: expected error")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoresheet
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// listHeader is the header of a result list, as used by the tooling of the German
+// Skat association (DSKV).
+var listHeader = []string{"Liste", "Tisch", "Name", "Spielpunkte", "Gewonnen", "Verloren", "Gegnerspiele", "Korrektur", "Punkte"}
+
+// ListRow is the result of a player in a series (Liste) of a tournament.
+type ListRow struct {
+	// List and Table are the series and the table (starting at 1)
+	List  int
+	Table int
+	// Player is the player's name
+	Player string
+	// Points is the sum of the player's game scores (Spielpunkte)
+	Points int
+	// Won and Lost are the player's won and lost declarer games
+	Won  int
+	Lost int
+	// Opponent is the number of games the declarers at the table lost against the player
+	Opponent int
+	// Adjustment is a correction by the tournament director (included in Score)
+	Adjustment int
+	// Score is the Seeger-Fabian result of the series
+	Score int
+}
+
+// WriteList writes a result list in the format of the DSKV tooling: one row per player
+// and series with the German column names, separated by semicolons with CRLF line
+// endings, so club tournaments played partly online and partly at the table can
+// merge their lists.
+func WriteList(w io.Writer, rows []ListRow) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	cw.UseCRLF = true
+	if err := cw.Write(listHeader); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		row := []string{
+			strconv.Itoa(r.List),
+			strconv.Itoa(r.Table),
+			r.Player,
+			strconv.Itoa(r.Points),
+			strconv.Itoa(r.Won),
+			strconv.Itoa(r.Lost),
+			strconv.Itoa(r.Opponent),
+			strconv.Itoa(r.Adjustment),
+			strconv.Itoa(r.Score),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoresheet
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteList(t *testing.T) {
+	rows := []ListRow{
+		{List: 1, Table: 2, Player: "Jürgen", Points: 230, Won: 4, Lost: 1, Opponent: 2, Score: 460},
+		{List: 1, Table: 2, Player: "anna", Points: -48, Lost: 1, Adjustment: -10, Score: -108},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteList(&buf, rows); err != nil {
+		t.Fatalf("WriteList() error: %v", err)
+	}
+	want := "Liste;Tisch;Name;Spielpunkte;Gewonnen;Verloren;Gegnerspiele;Korrektur;Punkte\r\n" +
+		"1;2;Jürgen;230;4;1;2;0;460\r\n" +
+		"1;2;anna;-48;0;1;0;-10;-108\r\n"
+	if buf.String() != want {
+		t.Errorf("WriteList() = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoresheet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Variant is the way the players of a money game settle their scores.
+type Variant string
+
+const (
+	// VariantEveryone - every player pays every player with a higher score the
+	// difference (Jeder gegen jeden)
+	VariantEveryone Variant = "everyone"
+	// VariantLoser - only the players with the lowest score pay, every other player
+	// the difference (Verlierer zahlt)
+	VariantLoser Variant = "loser"
+	// VariantWinner - only the players with the highest score collect, from every other
+	// player the difference (Gewinner kassiert)
+	VariantWinner Variant = "winner"
+)
+
+// ParseVariant parses a variant name (case-insensitive).
+func ParseVariant(name string) (Variant, error) {
+	switch v := Variant(strings.ToLower(name)); v {
+	case VariantEveryone, VariantLoser, VariantWinner:
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid settlement variant: %s (want everyone, loser or winner)", name)
+	}
+}
+
+// Stakes are the stakes of a money game.
+type Stakes struct {
+	// Cents is the amount per point in cents (0 = no money game)
+	Cents   int     `json:"cents"`
+	Variant Variant `json:"variant,omitempty"`
+}
+
+// Enabled returns true if the stakes make a money game.
+func (s Stakes) Enabled() bool {
+	return s.Cents > 0
+}
+
+// Balance is the amount a player wins (positive) or pays (negative).
+type Balance struct {
+	Player string `json:"player"`
+	Score  int    `json:"score"`
+	Cents  int    `json:"cents"`
+}
+
+// Transfer is a payment between two players.
+type Transfer struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Cents int    `json:"cents"`
+}
+
+// Settlement is the settlement of the final scores of a table.
+type Settlement struct {
+	Stakes   Stakes    `json:"stakes"`
+	Balances []Balance `json:"balances"`
+	// Transfers are the payments, starting with the player with the lowest score
+	Transfers []Transfer `json:"transfers"`
+}
+
+// Settle converts the final scores of the players of a table (by name) into money:
+// for every pair of players that settles (see Variant) the player with the lower score
+// pays the other the difference times the stakes. Balances are sorted best first.
+// Without stakes, the balances only rank the scores.
+func Settle(scores map[string]int, stakes Stakes) *Settlement {
+	players := make([]string, 0, len(scores))
+	for name := range scores {
+		players = append(players, name)
+	}
+	// Best first, equal scores by name, so payers and transfers have a fixed order
+	sort.Slice(players, func(i, j int) bool {
+		if scores[players[i]] != scores[players[j]] {
+			return scores[players[i]] > scores[players[j]]
+		}
+		return players[i] < players[j]
+	})
+
+	s := &Settlement{Stakes: stakes, Balances: []Balance{}, Transfers: []Transfer{}}
+	if len(players) == 0 {
+		return s
+	}
+	best, worst := scores[players[0]], scores[players[len(players)-1]]
+	cents := make(map[string]int)
+	for i := len(players) - 1; i >= 0; i-- {
+		from := players[i]
+		for _, to := range players[:i] {
+			diff := scores[to] - scores[from]
+			if diff == 0 || !stakes.Enabled() || !stakes.settles(scores[from] == worst, scores[to] == best) {
+				continue
+			}
+			amount := diff * stakes.Cents
+			cents[from] -= amount
+			cents[to] += amount
+			s.Transfers = append(s.Transfers, Transfer{From: from, To: to, Cents: amount})
+		}
+	}
+	for _, name := range players {
+		s.Balances = append(s.Balances, Balance{Player: name, Score: scores[name], Cents: cents[name]})
+	}
+	return s
+}
+
+// settles returns true if a player pays another player with a higher score, given
+// whether the payer has the lowest and the receiver the highest score of the table.
+func (s Stakes) settles(payerWorst, receiverBest bool) bool {
+	switch s.Variant {
+	case VariantLoser:
+		return payerWorst
+	case VariantWinner:
+		return receiverBest
+	default:
+		return true
+	}
+}
+
+// FormatCents formats an amount in cents as a decimal amount, e.g. "-12.50".
+func FormatCents(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoresheet
+
+import (
+	"testing"
+)
+
+// testScores are the final scores of a table of four.
+var testScores = map[string]int{"anna": 300, "ben": 120, "carl": 120, "dora": -60}
+
+// balances returns the balances of a settlement by player.
+func balances(s *Settlement) map[string]int {
+	cents := make(map[string]int)
+	for _, b := range s.Balances {
+		cents[b.Player] = b.Cents
+	}
+	return cents
+}
+
+func TestSettleEveryone(t *testing.T) {
+	s := Settle(testScores, Stakes{Cents: 1, Variant: VariantEveryone})
+
+	// Every player settles the differences to all others: n*score - sum of scores
+	want := map[string]int{"anna": 4*300 - 480, "ben": 4*120 - 480, "carl": 4*120 - 480, "dora": 4*-60 - 480}
+	got := balances(s)
+	for name, cents := range want {
+		if got[name] != cents {
+			t.Errorf("%s = %d, want %d", name, got[name], cents)
+		}
+	}
+	if s.Balances[0].Player != "anna" || s.Balances[3].Player != "dora" {
+		t.Errorf("balances = %+v, want anna first and dora last", s.Balances)
+	}
+	// ben and carl have equal scores and do not pay each other
+	if len(s.Transfers) != 5 || s.Transfers[0] != (Transfer{From: "dora", To: "anna", Cents: 360}) {
+		t.Errorf("transfers = %+v, want 5 starting with dora to anna", s.Transfers)
+	}
+}
+
+func TestSettleLoserWinner(t *testing.T) {
+	loser := balances(Settle(testScores, Stakes{Cents: 10, Variant: VariantLoser}))
+	if loser["dora"] != -(360+180+180)*10 || loser["anna"] != 3600 || loser["ben"] != 1800 {
+		t.Errorf("loser pays = %v", loser)
+	}
+
+	winner := balances(Settle(testScores, Stakes{Cents: 10, Variant: VariantWinner}))
+	if winner["anna"] != (180+180+360)*10 || winner["dora"] != -3600 || winner["ben"] != -1800 {
+		t.Errorf("winner collects = %v", winner)
+	}
+}
+
+func TestFormatCents(t *testing.T) {
+	for cents, want := range map[int]string{0: "0.00", 5: "0.05", 1250: "12.50", -1250: "-12.50"} {
+		if got := FormatCents(cents); got != want {
+			t.Errorf("FormatCents(%d) = %s, want %s", cents, got, want)
+		}
+	}
+}
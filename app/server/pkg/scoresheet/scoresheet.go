@@ -0,0 +1,261 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scoresheet builds Skat score sheets and standings from finished games,
+// exports them as CSV for spreadsheets and settles the scores of money games.
+package scoresheet
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Entry is a row of the score sheet: one finished game.
+type Entry struct {
+	// Number is the game number on the sheet (starting at 1)
+	Number int
+	ID     string
+	Date   time.Time
+	// Seats are the player names by position
+	Seats [3]string
+	// Declarer is the declarer name (empty for Ramsch)
+	Declarer string
+	// Contract is the contract code (e.g. "GH", "R")
+	Contract string
+	Bid      int
+	Won      bool
+	// Value is the game value (0 for Ramsch)
+	Value int
+	// Players are the players the score is booked for (the declarer or the Ramsch losers)
+	Players []string
+	Score   int
+	// Bock is true if the score was doubled in a Bock deal
+	Bock bool
+	// Totals are the running totals of all sheet players after the game
+	Totals []int
+}
+
+// Sheet is a score sheet: the games in order and the running totals of all players.
+type Sheet struct {
+	// Players are the player names in order of their first game
+	Players []string
+	Entries []Entry
+}
+
+// New creates the score sheet of the games in the given order. Unfinished games are skipped.
+func New(records []*skat.GameRecord) (*Sheet, error) {
+	s := &Sheet{}
+	index := make(map[string]int)
+	var totals []int
+
+	for _, record := range records {
+		game, err := record.Replay(nil)
+		if err != nil {
+			return nil, err
+		}
+		if game.Result == nil && game.RamschResult == nil {
+			continue
+		}
+
+		entry := Entry{Number: len(s.Entries) + 1, ID: record.ID, Date: record.StartedAt, Contract: game.Contract.Code()}
+		for _, p := range skat.AllPlayers {
+			name := record.Players[p]
+			entry.Seats[p.Index()] = name
+			if _, ok := index[name]; !ok {
+				index[name] = len(s.Players)
+				s.Players = append(s.Players, name)
+				totals = append(totals, 0)
+			}
+		}
+
+		if game.Bidding != nil {
+			entry.Bid = game.Bidding.FinalBid
+		}
+		if result := game.Result; result != nil {
+			entry.Declarer = record.Players[result.Declarer]
+			entry.Won = result.DeclarerWon
+			entry.Value = result.GameValue
+			entry.Players = []string{entry.Declarer}
+			entry.Score = result.Score
+		} else if r := game.RamschResult; r.Durchmarsch {
+			entry.Players = []string{record.Players[*r.DurchmarschPlayer]}
+			entry.Won = true
+			entry.Score = r.DurchmarschScore
+		} else {
+			for _, p := range r.Losers {
+				entry.Players = append(entry.Players, record.Players[p])
+			}
+			entry.Score = r.LoserScore
+		}
+
+		for _, name := range entry.Players {
+			totals[index[name]] += entry.Score
+		}
+		entry.Totals = append([]int(nil), totals...)
+		s.Entries = append(s.Entries, entry)
+	}
+	return s, nil
+}
+
+// ApplyBock doubles the scores of the declarer games played in Bock deals, given by
+// their game IDs, and updates the running totals.
+func (s *Sheet) ApplyBock(ids map[string]bool) {
+	index := make(map[string]int)
+	for i, name := range s.Players {
+		index[name] = i
+	}
+	totals := make([]int, len(s.Players))
+	for i := range s.Entries {
+		e := &s.Entries[i]
+		if ids[e.ID] && e.Declarer != "" && !e.Bock {
+			e.Bock = true
+			e.Score *= 2
+		}
+		for _, name := range e.Players {
+			totals[index[name]] += e.Score
+		}
+		e.Totals = append(e.Totals[:0], totals[:len(e.Totals)]...)
+	}
+}
+
+// Standing is the result of a player on the sheet.
+type Standing struct {
+	// Rank is the rank by score (players with equal score share a rank)
+	Rank          int
+	Player        string
+	Games         int
+	DeclarerGames int
+	Won           int
+	Lost          int
+	Score         int
+}
+
+// Standings returns the standings of all sheet players, best first.
+func (s *Sheet) Standings() []Standing {
+	standings := make([]Standing, len(s.Players))
+	index := make(map[string]int)
+	for i, name := range s.Players {
+		standings[i].Player = name
+		index[name] = i
+	}
+	for _, e := range s.Entries {
+		for _, name := range e.Seats {
+			standings[index[name]].Games++
+		}
+		if e.Declarer != "" {
+			st := &standings[index[e.Declarer]]
+			st.DeclarerGames++
+			if e.Won {
+				st.Won++
+			} else {
+				st.Lost++
+			}
+		}
+	}
+	if len(s.Entries) > 0 {
+		totals := s.Entries[len(s.Entries)-1].Totals
+		for i := range standings {
+			standings[i].Score = totals[i]
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Score > standings[j].Score
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Score == standings[i-1].Score {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+	return standings
+}
+
+// WriteCSV writes the score sheet as CSV: one row per game with the running totals
+// of all players as the last columns.
+func (s *Sheet) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"game", "id", "date", "declarer", "contract", "bid", "won", "value", "bock", "player", "score"}
+	if err := cw.Write(append(header, s.Players...)); err != nil {
+		return err
+	}
+
+	for _, e := range s.Entries {
+		row := []string{
+			strconv.Itoa(e.Number),
+			e.ID,
+			e.Date.UTC().Format(time.RFC3339),
+			e.Declarer,
+			e.Contract,
+			strconv.Itoa(e.Bid),
+			wonText(e),
+			strconv.Itoa(e.Value),
+			strconv.FormatBool(e.Bock),
+			strings.Join(e.Players, ", "),
+			strconv.Itoa(e.Score),
+		}
+		for i := range s.Players {
+			total := ""
+			if i < len(e.Totals) {
+				total = strconv.Itoa(e.Totals[i])
+			}
+			row = append(row, total)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// wonText returns the "won" column of an entry (empty for Ramsch).
+func wonText(e Entry) string {
+	if e.Declarer == "" {
+		return ""
+	}
+	return strconv.FormatBool(e.Won)
+}
+
+// WriteStandingsCSV writes standings as CSV.
+func WriteStandingsCSV(w io.Writer, standings []Standing) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"rank", "player", "games", "declarer", "won", "lost", "score"}); err != nil {
+		return err
+	}
+	for _, st := range standings {
+		row := []string{
+			strconv.Itoa(st.Rank),
+			st.Player,
+			strconv.Itoa(st.Games),
+			strconv.Itoa(st.DeclarerGames),
+			strconv.Itoa(st.Won),
+			strconv.Itoa(st.Lost),
+			strconv.Itoa(st.Score),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
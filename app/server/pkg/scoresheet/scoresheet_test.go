@@ -0,0 +1,151 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoresheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// newTestSheet creates a sheet of 12 games with four players rotating through the seats.
+func newTestSheet(t *testing.T) *Sheet {
+	t.Helper()
+
+	names := []string{"anna", "ben", "carl", "dora"}
+	var records []*skat.GameRecord
+	for i := 0; i < 12; i++ {
+		seats := map[skat.Player]string{
+			skat.Forehand:   names[i%4],
+			skat.Middlehand: names[(i+1)%4],
+			skat.Rearhand:   names[(i+2)%4],
+		}
+		records = append(records, recordtest.PlayedBy(t, int64(i+1), seats))
+	}
+
+	sheet, err := New(records)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return sheet
+}
+
+// ============================================================================
+// Sheet Tests
+// ============================================================================
+
+func TestSheetTotals(t *testing.T) {
+	sheet := newTestSheet(t)
+	if len(sheet.Entries) != 12 || len(sheet.Players) != 4 {
+		t.Fatalf("New() = %d entries, %d players, want 12, 4", len(sheet.Entries), len(sheet.Players))
+	}
+
+	sums := make(map[string]int)
+	for _, e := range sheet.Entries {
+		for _, name := range e.Players {
+			sums[name] += e.Score
+		}
+	}
+	last := sheet.Entries[len(sheet.Entries)-1].Totals
+	for i, name := range sheet.Players {
+		if last[i] != sums[name] {
+			t.Errorf("total of %s = %d, want %d", name, last[i], sums[name])
+		}
+	}
+}
+
+func TestApplyBock(t *testing.T) {
+	sheet := newTestSheet(t)
+	before := append([]Entry(nil), sheet.Entries...)
+	ids := make(map[string]bool)
+	for _, e := range sheet.Entries {
+		ids[e.ID] = true
+	}
+	sheet.ApplyBock(ids)
+	sheet.ApplyBock(ids)
+
+	sums := make(map[string]int)
+	for i, e := range sheet.Entries {
+		want, bock := before[i].Score, before[i].Declarer != ""
+		if bock {
+			want *= 2
+		}
+		if e.Score != want || e.Bock != bock {
+			t.Errorf("game %d: score %d, Bock %v, want %d, %v", e.Number, e.Score, e.Bock, want, bock)
+		}
+		for _, name := range e.Players {
+			sums[name] += e.Score
+		}
+	}
+	last := sheet.Entries[len(sheet.Entries)-1].Totals
+	for i, name := range sheet.Players {
+		if last[i] != sums[name] {
+			t.Errorf("total of %s = %d, want %d", name, last[i], sums[name])
+		}
+	}
+}
+
+func TestStandings(t *testing.T) {
+	standings := newTestSheet(t).Standings()
+
+	games := 0
+	for i, st := range standings {
+		games += st.Games
+		if st.Won+st.Lost != st.DeclarerGames {
+			t.Errorf("%s: won %d + lost %d != declarer %d", st.Player, st.Won, st.Lost, st.DeclarerGames)
+		}
+		if i > 0 && st.Score > standings[i-1].Score {
+			t.Errorf("standings not sorted: %d after %d", st.Score, standings[i-1].Score)
+		}
+	}
+	// Every game has three players
+	if games != 36 {
+		t.Errorf("sum of games = %d, want 36", games)
+	}
+}
+
+// ============================================================================
+// CSV Tests
+// ============================================================================
+
+func TestWriteCSV(t *testing.T) {
+	sheet := newTestSheet(t)
+
+	var buf bytes.Buffer
+	if err := sheet.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	if len(rows) != 13 || len(rows[0]) != 15 {
+		t.Fatalf("WriteCSV() = %d rows of %d columns, want 13 of 15", len(rows), len(rows[0]))
+	}
+	if rows[0][11] != "anna" || rows[1][1] != "g1" {
+		t.Errorf("WriteCSV() header %v, first row %v", rows[0], rows[1])
+	}
+
+	buf.Reset()
+	if err := WriteStandingsCSV(&buf, sheet.Standings()); err != nil {
+		t.Fatalf("WriteStandingsCSV() error: %v", err)
+	}
+	if rows, err = csv.NewReader(&buf).ReadAll(); err != nil || len(rows) != 5 {
+		t.Errorf("WriteStandingsCSV() = %d rows (error %v), want 5", len(rows), err)
+	}
+}
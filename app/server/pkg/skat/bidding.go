@@ -14,6 +14,11 @@
 
 package skat
 
+import (
+	"errors"
+	"fmt"
+)
+
 // BidOrder contains all valid bid values in ascending order.
 var BidOrder = []int{
 	18, 20, 22, 23, 24, 27, 30, 33, 35, 36, 40, 44, 45, 46, 48, 50, 54, 55, 59, 60,
@@ -81,6 +86,9 @@ const (
 	BidPhaseMiddleToFore BiddingPhase = iota
 	// BidPhaseWinnerToRear - Winner of first phase bids to Rearhand
 	BidPhaseWinnerToRear
+	// BidPhaseForehandDecides - Middlehand and Rearhand passed without a bid, Forehand
+	// declares at 18 or passes as well
+	BidPhaseForehandDecides
 	// BidPhaseDone - Bidding is complete
 	BidPhaseDone
 )
@@ -92,9 +100,260 @@ func (p BiddingPhase) String() string {
 		return "MiddlehandToForehand"
 	case BidPhaseWinnerToRear:
 		return "WinnerToRearhand"
+	case BidPhaseForehandDecides:
+		return "ForehandDecides"
 	case BidPhaseDone:
 		return "Done"
 	default:
 		return "Unknown"
 	}
 }
+
+// BiddingResult represents the outcome of the bidding phase.
+type BiddingResult int
+
+const (
+	// BidResultInProgress - Bidding is still in progress
+	BidResultInProgress BiddingResult = iota
+	// BidResultHasDeclarer - A player won the bidding
+	BidResultHasDeclarer
+	// BidResultAllPassed - All players passed
+	BidResultAllPassed
+)
+
+// String returns the string representation of the bidding result.
+func (r BiddingResult) String() string {
+	switch r {
+	case BidResultInProgress:
+		return "InProgress"
+	case BidResultHasDeclarer:
+		return "HasDeclarer"
+	case BidResultAllPassed:
+		return "AllPassed"
+	default:
+		return "Unknown"
+	}
+}
+
+// AllPass is the rule what happens when all players pass.
+type AllPass int
+
+const (
+	// AllPassRamsch plays a Ramsch
+	AllPassRamsch AllPass = iota
+	// AllPassThrowIn throws the deal in (Einpassen), the next dealer deals
+	AllPassThrowIn
+	// AllPassGrandHand lets Forehand play a Grand Hand instead of the Ramsch
+	AllPassGrandHand
+)
+
+// String returns the code of the rule: "ramsch", "throw-in" or "grand-hand".
+func (a AllPass) String() string {
+	switch a {
+	case AllPassRamsch:
+		return "ramsch"
+	case AllPassThrowIn:
+		return "throw-in"
+	case AllPassGrandHand:
+		return "grand-hand"
+	default:
+		return fmt.Sprintf("AllPass(%d)", int(a))
+	}
+}
+
+// MarshalText encodes the rule as its code.
+func (a AllPass) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes a rule code.
+func (a *AllPass) UnmarshalText(text []byte) error {
+	for _, rule := range []AllPass{AllPassRamsch, AllPassThrowIn, AllPassGrandHand} {
+		if rule.String() == string(text) {
+			*a = rule
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid all-pass rule: %s", text)
+}
+
+// BiddingState represents the complete state of the bidding phase.
+type BiddingState struct {
+	// Phase is the current phase of bidding
+	Phase BiddingPhase
+	// CurrentBid is the current bid value (0 if no bids yet)
+	CurrentBid int
+	// CurrentBidder is the player who made the current bid (nil if no bids)
+	CurrentBidder *Player
+	// ActivePlayer is the player who must act next
+	ActivePlayer Player
+	// IsActiveBidding is true if the active player has to bid (vs respond)
+	IsActiveBidding bool
+	// Passed contains the players who have passed
+	Passed map[Player]bool
+	// FirstPhaseWinner is the winner of the first phase (Middlehand vs Forehand)
+	FirstPhaseWinner *Player
+	// Result is the result of bidding
+	Result BiddingResult
+	// Declarer is the winner of the bidding
+	Declarer *Player
+	// FinalBid is the final bid value
+	FinalBid int
+}
+
+// NewBiddingState creates a new bidding state for the start of bidding.
+func NewBiddingState() *BiddingState {
+	return &BiddingState{
+		Phase:           BidPhaseMiddleToFore,
+		ActivePlayer:    Middlehand, // Middlehand bids first
+		IsActiveBidding: true,
+		Passed:          make(map[Player]bool),
+		Result:          BidResultInProgress,
+	}
+}
+
+// MinimumBid returns the minimum valid bid value for the current state.
+func (b *BiddingState) MinimumBid() int {
+	if b.CurrentBid == 0 {
+		return MinBid
+	}
+	return NextBid(b.CurrentBid)
+}
+
+// checkTurn returns an error if the player may not act now.
+func (b *BiddingState) checkTurn(player Player) error {
+	if b.Result != BidResultInProgress {
+		return errors.New("bidding is already complete")
+	}
+	if player != b.ActivePlayer {
+		return fmt.Errorf("it is not %s's turn to act", player)
+	}
+	return nil
+}
+
+// Bid processes a new bid of the active player.
+func (b *BiddingState) Bid(player Player, value int) error {
+	if err := b.checkTurn(player); err != nil {
+		return err
+	}
+	if !b.IsActiveBidding {
+		return errors.New("active player should hold or pass, not bid")
+	}
+	if !IsValidBid(value) || value <= b.CurrentBid {
+		return fmt.Errorf("invalid bid value: %d", value)
+	}
+	if b.Phase == BidPhaseForehandDecides && value != MinBid {
+		return fmt.Errorf("forehand declares at %d after both passed, not %d", MinBid, value)
+	}
+
+	bidder := player
+	b.CurrentBid = value
+	b.CurrentBidder = &bidder
+	b.IsActiveBidding = false
+
+	// Forehand bids alone after both other players passed and becomes declarer
+	if b.Phase == BidPhaseForehandDecides {
+		b.Phase = BidPhaseDone
+		b.Result = BidResultHasDeclarer
+		b.Declarer = &bidder
+		b.FinalBid = value
+		return nil
+	}
+
+	// Switch to the responder
+	if b.Phase == BidPhaseMiddleToFore {
+		b.ActivePlayer = Forehand
+	} else {
+		b.ActivePlayer = *b.FirstPhaseWinner
+	}
+	return nil
+}
+
+// Hold processes a hold (accepting the current bid) of the active player.
+func (b *BiddingState) Hold(player Player) error {
+	if err := b.checkTurn(player); err != nil {
+		return err
+	}
+	if b.IsActiveBidding {
+		return errors.New("active player should bid, not hold")
+	}
+	if b.CurrentBid == 0 || b.CurrentBidder == nil {
+		return errors.New("cannot hold when no bid has been made")
+	}
+
+	// Switch back to the bidder, who must raise or pass
+	b.IsActiveBidding = true
+	b.ActivePlayer = *b.CurrentBidder
+	return nil
+}
+
+// Pass processes a pass of the active player.
+func (b *BiddingState) Pass(player Player) error {
+	if err := b.checkTurn(player); err != nil {
+		return err
+	}
+
+	b.Passed[player] = true
+
+	switch b.Phase {
+	case BidPhaseMiddleToFore:
+		b.passInFirstPhase(player)
+	case BidPhaseForehandDecides:
+		b.Phase = BidPhaseDone
+		b.Result = BidResultAllPassed
+	default:
+		b.passInSecondPhase(player)
+	}
+	return nil
+}
+
+// passInFirstPhase handles a pass in the first bidding phase (Middlehand vs Forehand).
+func (b *BiddingState) passInFirstPhase(player Player) {
+	winner := Forehand
+	if player == Forehand {
+		winner = Middlehand
+	}
+
+	// Rearhand now bids against the winner and has to beat the current bid
+	b.Phase = BidPhaseWinnerToRear
+	b.FirstPhaseWinner = &winner
+	b.ActivePlayer = Rearhand
+	b.IsActiveBidding = true
+}
+
+// passInSecondPhase handles a pass in the second bidding phase (winner vs Rearhand).
+func (b *BiddingState) passInSecondPhase(player Player) {
+	b.Phase = BidPhaseDone
+
+	if player == Rearhand {
+		if b.CurrentBid == 0 {
+			// Nobody has bid yet - Forehand must bid 18 or pass as well
+			b.Phase = BidPhaseForehandDecides
+			b.ActivePlayer = *b.FirstPhaseWinner
+			b.IsActiveBidding = true
+			return
+		}
+		// Rearhand passed - first phase winner becomes declarer
+		declarer := *b.FirstPhaseWinner
+		b.Result = BidResultHasDeclarer
+		b.Declarer = &declarer
+		b.FinalBid = b.CurrentBid
+		return
+	}
+
+	// First phase winner passed - Rearhand wins if it had bid
+	if b.CurrentBidder != nil && *b.CurrentBidder == Rearhand {
+		declarer := Rearhand
+		b.Result = BidResultHasDeclarer
+		b.Declarer = &declarer
+		b.FinalBid = b.CurrentBid
+		return
+	}
+
+	b.Result = BidResultAllPassed
+}
+
+// IsDone returns true if the bidding is complete.
+func (b *BiddingState) IsDone() bool {
+	return b.Result != BidResultInProgress
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"testing"
+)
+
+// ============================================================================
+// Bid Value Tests
+// ============================================================================
+
+func TestBidValues(t *testing.T) {
+	if BidOrder[0] != MinBid {
+		t.Errorf("BidOrder[0] = %d, want %d", BidOrder[0], MinBid)
+	}
+	if NextBid(0) != MinBid {
+		t.Errorf("NextBid(0) = %d, want %d", NextBid(0), MinBid)
+	}
+	if NextBid(18) != 20 {
+		t.Errorf("NextBid(18) = %d, want 20", NextBid(18))
+	}
+	if NextBid(MaxBid) != -1 {
+		t.Errorf("NextBid(%d) = %d, want -1", MaxBid, NextBid(MaxBid))
+	}
+	if PreviousBid(MinBid) != -1 {
+		t.Errorf("PreviousBid(%d) = %d, want -1", MinBid, PreviousBid(MinBid))
+	}
+	if IsValidBid(19) {
+		t.Error("IsValidBid(19) should be false")
+	}
+}
+
+// ============================================================================
+// Bidding State Tests
+// ============================================================================
+
+func TestBiddingStateWrongPlayer(t *testing.T) {
+	b := NewBiddingState()
+
+	if err := b.Bid(Forehand, 18); err == nil {
+		t.Error("Forehand should not be able to bid first")
+	}
+	if err := b.Bid(Middlehand, 19); err == nil {
+		t.Error("Bid(19) should be rejected as invalid value")
+	}
+	if err := b.Hold(Middlehand); err == nil {
+		t.Error("Middlehand should not be able to hold before any bid")
+	}
+}
+
+func TestBiddingStateMiddlehandWins(t *testing.T) {
+	b := NewBiddingState()
+
+	mustDo(t, b.Bid(Middlehand, 18))
+	mustDo(t, b.Hold(Forehand))
+	mustDo(t, b.Bid(Middlehand, 20))
+	mustDo(t, b.Pass(Forehand))
+
+	// Rearhand must beat 20 against Middlehand
+	if b.ActivePlayer != Rearhand || !b.IsActiveBidding {
+		t.Fatalf("ActivePlayer = %s (bidding=%v), want bidding Rearhand", b.ActivePlayer, b.IsActiveBidding)
+	}
+	if b.MinimumBid() != 22 {
+		t.Errorf("MinimumBid() = %d, want 22", b.MinimumBid())
+	}
+
+	mustDo(t, b.Bid(Rearhand, 22))
+	if b.ActivePlayer != Middlehand {
+		t.Fatalf("ActivePlayer = %s, want Middlehand to respond", b.ActivePlayer)
+	}
+	mustDo(t, b.Hold(Middlehand))
+	mustDo(t, b.Pass(Rearhand))
+
+	if b.Result != BidResultHasDeclarer || *b.Declarer != Middlehand {
+		t.Fatalf("Result = %s, want Middlehand as declarer", b.Result)
+	}
+	if b.FinalBid != 22 {
+		t.Errorf("FinalBid = %d, want 22", b.FinalBid)
+	}
+}
+
+func TestBiddingStateRearhandWins(t *testing.T) {
+	b := NewBiddingState()
+
+	mustDo(t, b.Pass(Middlehand))
+	mustDo(t, b.Bid(Rearhand, 18))
+	mustDo(t, b.Pass(Forehand))
+
+	if b.Result != BidResultHasDeclarer || *b.Declarer != Rearhand {
+		t.Fatalf("Result = %s, want Rearhand as declarer", b.Result)
+	}
+	if b.FinalBid != 18 {
+		t.Errorf("FinalBid = %d, want 18", b.FinalBid)
+	}
+}
+
+func TestBiddingStateForehandAt18(t *testing.T) {
+	b := NewBiddingState()
+
+	mustDo(t, b.Pass(Middlehand))
+	mustDo(t, b.Pass(Rearhand))
+
+	// Forehand still has to bid 18 or pass
+	if b.Phase != BidPhaseForehandDecides || b.ActivePlayer != Forehand || !b.IsActiveBidding {
+		t.Fatalf("Phase = %s, active %s, want Forehand to decide", b.Phase, b.ActivePlayer)
+	}
+	// Higher bids are refused, nobody is left to outbid
+	for _, value := range []int{20, 23, 48} {
+		if err := b.Bid(Forehand, value); err == nil {
+			t.Errorf("Bid(Forehand, %d) after both passed: expected error", value)
+		}
+	}
+	if b.Phase != BidPhaseForehandDecides || b.CurrentBid != 0 {
+		t.Fatalf("refused bid changed the state: phase %s, bid %d", b.Phase, b.CurrentBid)
+	}
+	mustDo(t, b.Bid(Forehand, MinBid))
+
+	if b.Result != BidResultHasDeclarer || *b.Declarer != Forehand {
+		t.Fatalf("Result = %s, want Forehand as declarer", b.Result)
+	}
+	if b.FinalBid != MinBid {
+		t.Errorf("FinalBid = %d, want %d", b.FinalBid, MinBid)
+	}
+}
+
+func TestBiddingStateAllPassed(t *testing.T) {
+	b := NewBiddingState()
+
+	mustDo(t, b.Pass(Middlehand))
+	mustDo(t, b.Pass(Rearhand))
+	mustDo(t, b.Pass(Forehand))
+
+	if b.Result != BidResultAllPassed || b.Declarer != nil || b.Phase != BidPhaseDone {
+		t.Errorf("Result = %s, declarer %v, want all passed", b.Result, b.Declarer)
+	}
+}
+
+// mustDo fails the test if err is not nil.
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
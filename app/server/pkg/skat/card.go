@@ -15,7 +15,9 @@
 package skat
 
 import (
+	crand "crypto/rand"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"sort"
 	"strings"
@@ -81,6 +83,22 @@ type Deck struct {
 	Cards []Card
 }
 
+// Shuffle modes of recorded deals.
+const (
+	// ShuffleSeeded deals are shuffled with a math/rand source of a known seed
+	ShuffleSeeded = "seeded"
+	// ShuffleCrypto deals are shuffled with crypto/rand and cannot be reproduced
+	ShuffleCrypto = "crypto"
+)
+
+// ShuffleInfo describes how a deck was shuffled.
+type ShuffleInfo struct {
+	// Mode is ShuffleSeeded or ShuffleCrypto (empty if unknown)
+	Mode string
+	// Seed is the seed of a seeded shuffle
+	Seed int64
+}
+
 // NewDeck creates a new standard 32-card Skat deck.
 func NewDeck() *Deck {
 	deck := &Deck{
@@ -103,6 +121,33 @@ func (d *Deck) Shuffle() {
 	})
 }
 
+// ShuffleWith shuffles the deck using the given random source (for reproducible deals).
+func (d *Deck) ShuffleWith(rng *rand.Rand) {
+	rng.Shuffle(len(d.Cards), func(i, j int) {
+		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	})
+}
+
+// ShuffleSeed shuffles the deck with a new random source of the seed.
+// The same seed always gives the same order.
+func (d *Deck) ShuffleSeed(seed int64) ShuffleInfo {
+	d.ShuffleWith(rand.New(rand.NewSource(seed)))
+	return ShuffleInfo{Mode: ShuffleSeeded, Seed: seed}
+}
+
+// ShuffleCrypto shuffles the deck with the cryptographically secure random generator.
+func (d *Deck) ShuffleCrypto() (ShuffleInfo, error) {
+	for i := len(d.Cards) - 1; i > 0; i-- {
+		n, err := crand.Int(crand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return ShuffleInfo{}, err
+		}
+		j := int(n.Int64())
+		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
+	}
+	return ShuffleInfo{Mode: ShuffleCrypto}, nil
+}
+
 // Deal removes and returns the specified number of cards from the top of the deck.
 func (d *Deck) Deal(count int) []Card {
 	if count > len(d.Cards) {
@@ -324,7 +369,8 @@ func (c Card) SuitOrder(gameType GameType) int {
 }
 
 // CompareCards compares two cards to determine which wins a trick.
-// Returns positive if c beats other, negative if other beats c, 0 if equal.
+// Returns positive if c beats other, negative if other beats c, 0 if neither card is
+// trump or follows the lead suit. Use Beats to decide a trick.
 func (c Card) CompareCards(other Card, leadSuit Suit, gameType GameType) int {
 	trump1 := c.IsTrump(gameType)
 	trump2 := other.IsTrump(gameType)
@@ -363,6 +409,13 @@ func (c Card) CompareCards(other Card, leadSuit Suit, gameType GameType) int {
 	return 0
 }
 
+// Beats returns true if c, played after the card winning so far, takes over the trick.
+// Only a higher card beats it; of two cards that neither trump nor follow the lead suit
+// the earlier one keeps the trick.
+func (c Card) Beats(winning Card, leadSuit Suit, gameType GameType) bool {
+	return c.CompareCards(winning, leadSuit, gameType) > 0
+}
+
 // CanPlay determines if a card can legally be played given the lead card and hand.
 func (c Card) CanPlay(leadCard *Card, hand *Hand, gameType GameType) bool {
 	// If no lead card, any card can be played
@@ -417,6 +470,17 @@ func (c Card) CanPlay(leadCard *Card, hand *Hand, gameType GameType) bool {
 	return true
 }
 
+// LegalMoves returns all cards of the hand that can legally be played on the given lead card.
+func (h *Hand) LegalMoves(leadCard *Card, gameType GameType) []Card {
+	moves := make([]Card, 0, len(h.Cards))
+	for _, c := range h.Cards {
+		if c.CanPlay(leadCard, h, gameType) {
+			moves = append(moves, c)
+		}
+	}
+	return moves
+}
+
 // ============================================================================
 // Card Sorting Functions
 // ============================================================================
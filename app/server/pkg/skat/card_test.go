@@ -395,3 +395,97 @@ func TestSortForGameSuit(t *testing.T) {
 		t.Errorf("Expected 2 Hearts after Jack, found %d", heartsFound)
 	}
 }
+
+func TestHandLegalMoves(t *testing.T) {
+	hand := NewHandFromCards([]Card{
+		NewCard(Clubs, Jack),
+		NewCard(Hearts, Ace),
+		NewCard(Hearts, Seven),
+		NewCard(Spades, King),
+	})
+
+	// Hearts led in a Clubs game: must follow with Hearts (CJ is trump, not Hearts)
+	lead := NewCard(Hearts, Ten)
+	moves := hand.LegalMoves(&lead, GameClubs)
+	if len(moves) != 2 {
+		t.Fatalf("LegalMoves() returned %d cards, want 2", len(moves))
+	}
+	for _, c := range moves {
+		if c.Suit != Hearts || c.IsJack() {
+			t.Errorf("LegalMoves() returned %s, want only Hearts", c)
+		}
+	}
+
+	// No lead card: every card is legal
+	if got := len(hand.LegalMoves(nil, GameClubs)); got != 4 {
+		t.Errorf("LegalMoves(nil) returned %d cards, want 4", got)
+	}
+}
+
+// ============================================================================
+// Localized Name Tests
+// ============================================================================
+
+func TestLocalizedName(t *testing.T) {
+	tests := []struct {
+		lang     string
+		got      string
+		expected string
+	}{
+		{LangEnglish, Hearts.LocalizedName(LangEnglish), "Hearts"},
+		{LangGerman, Hearts.LocalizedName(LangGerman), "Herz"},
+		{LangFrench, Hearts.LocalizedName(LangFrench), "Cœur"},
+		{LangPolish, Hearts.LocalizedName(LangPolish), "Kier"},
+		{LangGerman, Jack.LocalizedName(LangGerman), "Bube"},
+		{LangFrench, Jack.LocalizedName(LangFrench), "Valet"},
+		{LangPolish, Queen.LocalizedName(LangPolish), "Dama"},
+		{LangFrench, GameGrand.LocalizedName(LangFrench), "Grand"},
+		{LangPolish, GameRamsch.LocalizedName(LangPolish), "Ramsz"},
+		{LangGerman, Middlehand.LocalizedName(LangGerman), "Mittelhand"},
+		{"xx", Ace.LocalizedName("xx"), "Ace"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("LocalizedName(%s) = %q, want %q", tt.lang, tt.got, tt.expected)
+		}
+	}
+
+	// Every shipped language names every suit, rank, game type and player position
+	for _, lang := range NameLanguages() {
+		localized := Names(lang)
+		if lang == LangEnglish {
+			continue
+		}
+		for _, s := range AllSuits {
+			if _, ok := localized[s.String()]; !ok {
+				t.Errorf("%s: missing name of %s", lang, s)
+			}
+		}
+		for _, r := range AllRanks {
+			if _, ok := localized[r.String()]; !ok {
+				t.Errorf("%s: missing name of %s", lang, r)
+			}
+		}
+		for _, g := range append(AllGameTypes, GameRamsch) {
+			if _, ok := localized[g.String()]; !ok {
+				t.Errorf("%s: missing name of %s", lang, g)
+			}
+		}
+		for _, p := range AllPlayers {
+			if _, ok := localized[p.String()]; !ok {
+				t.Errorf("%s: missing name of %s", lang, p)
+			}
+		}
+	}
+}
+
+func TestAddNames(t *testing.T) {
+	AddNames("it", map[string]string{"Hearts": "Cuori"})
+	if got := Hearts.LocalizedName("it"); got != "Cuori" {
+		t.Errorf("Hearts.LocalizedName(it) = %q, want Cuori", got)
+	}
+	if got := Spades.LocalizedName("it"); got != "Spades" {
+		t.Errorf("Spades.LocalizedName(it) = %q, want Spades", got)
+	}
+}
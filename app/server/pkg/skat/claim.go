@@ -0,0 +1,162 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrClaimPending is returned for moves while a claim waits for the answers of the
+// other players.
+var ErrClaimPending = errors.New("claim pending")
+
+// Claim is a pending claim of the remaining tricks: the declarer shows the cards and
+// claims them, or a defender concedes them to the declarer. Either way the declarer
+// wins the rest of the game if the other side accepts.
+type Claim struct {
+	// By is the declarer who claims or the defender who concedes
+	By Player
+	// Accepted are the players who have accepted the claim so far
+	Accepted []Player
+}
+
+// respondents returns the players who must accept the claim: both defenders for a
+// claim of the declarer, the partner for a concession of a defender.
+func (c *Claim) respondents(declarer Player) []Player {
+	var players []Player
+	for _, p := range AllPlayers {
+		if p != declarer && p != c.By {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+// next returns the next player who has to answer the claim.
+func (c *Claim) next(declarer Player) *Player {
+	for _, p := range c.respondents(declarer) {
+		if !containsPlayer(c.Accepted, p) {
+			return &p
+		}
+	}
+	return nil
+}
+
+// Claim lets the declarer show the cards and claim the remaining tricks.
+func (g *Game) Claim(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionClaim})
+}
+
+// Concede lets a defender concede the remaining tricks to the declarer.
+func (g *Game) Concede(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionConcede})
+}
+
+// AcceptClaim accepts the pending claim.
+func (g *Game) AcceptClaim(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionAcceptClaim})
+}
+
+// RejectClaim rejects the pending claim; the play continues.
+func (g *Game) RejectClaim(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionRejectClaim})
+}
+
+// claim processes a claim of the declarer or a concession of a defender.
+func (g *Game) claim(player Player, concede bool) error {
+	if err := g.checkState(StateTrickPlaying); err != nil {
+		return err
+	}
+	if g.Contract.GameType.IsRamsch() {
+		return errors.New("no claims in ramsch games")
+	}
+	if concede && *g.Declarer == player {
+		return errors.New("the declarer cannot concede")
+	}
+	if !concede && *g.Declarer != player {
+		return errors.New("only the declarer can claim")
+	}
+	if g.Abandoned != nil {
+		// The defender who left cannot answer: the claim settles the forfeit
+		if err := g.checkForfeitChoice(player); err != nil {
+			return err
+		}
+		g.Forfeit = ForfeitClaimed
+		if err := g.settleClaim(); err != nil {
+			return err
+		}
+		g.Result.Forfeit = ForfeitClaimed
+		return nil
+	}
+	g.PendingClaim = &Claim{By: player}
+	return nil
+}
+
+// answerClaim processes the answer of a player to the pending claim. The game ends
+// when the last player accepts.
+func (g *Game) answerClaim(player Player, accept bool) error {
+	if g.PendingClaim == nil {
+		return errors.New("no claim pending")
+	}
+	if next := g.PendingClaim.next(*g.Declarer); next == nil || *next != player {
+		return fmt.Errorf("%w: %s", ErrNotYourTurn, player)
+	}
+	if !accept {
+		g.PendingClaim = nil
+		return nil
+	}
+	g.PendingClaim.Accepted = append(g.PendingClaim.Accepted, player)
+	if g.PendingClaim.next(*g.Declarer) != nil {
+		return nil
+	}
+	return g.settleClaim()
+}
+
+// settleClaim ends the game with the accepted claim: the remaining cards are put
+// into tricks, which the declarer takes in a suit or Grand game and the defenders in
+// a Null game.
+func (g *Game) settleClaim() error {
+	winner := *g.Declarer
+	if g.Contract.GameType.IsNull() {
+		winner = winner.LeftNeighbor()
+	}
+	for len(g.Tricks) < TricksPerGame {
+		for !g.Trick.IsComplete() {
+			player := *g.Trick.NextPlayer()
+			card := g.Hands[player].Cards[0]
+			g.Hands[player].Remove(card)
+			if err := g.Trick.AddCard(card, player); err != nil {
+				return err
+			}
+		}
+		g.Trick.Winner = &winner
+		g.Trick.Claimed = true
+		g.Tricks = append(g.Tricks, g.Trick)
+		g.Trick = NewTrick(winner)
+	}
+	g.State = StatePreliminaryGameEnd
+	return g.finish()
+}
+
+// containsPlayer returns true if the player is in the list.
+func containsPlayer(players []Player, player Player) bool {
+	for _, p := range players {
+		if p == player {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import "time"
+
+// StartClock starts the clock of the active player of a timed game, e.g. once the
+// table has sent the position to the player. Apply charges the time until the move to
+// the player and stops the clock again, so the clocks pause while the server
+// processes a move and deals with the next phase.
+func (g *Game) StartClock(now time.Time) {
+	player := g.ActivePlayer()
+	if g.Clocks == nil || player == nil {
+		return
+	}
+	g.clockPlayer = *player
+	g.clockStarted = now
+}
+
+// Remaining returns the thinking time the player has left at the given time,
+// including a running clock (0 if the game is not timed).
+func (g *Game) Remaining(player Player, now time.Time) time.Duration {
+	remaining := g.Clocks[player]
+	if !g.clockStarted.IsZero() && g.clockPlayer == player {
+		remaining -= now.Sub(g.clockStarted)
+	}
+	return max(remaining, 0)
+}
+
+// chargeClock charges the running clock of the player making the action and stops it.
+// Moves of other players (e.g. Kontra) leave the clock running.
+func (g *Game) chargeClock(action Action) {
+	if g.clockStarted.IsZero() || g.clockPlayer != action.Player {
+		return
+	}
+	g.Clocks[action.Player] = g.Remaining(action.Player, action.Time)
+	g.clockStarted = time.Time{}
+}
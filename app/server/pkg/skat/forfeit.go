@@ -0,0 +1,170 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrForfeitPending is returned for moves while the declarer decides how a game
+// abandoned by a defender ends.
+var ErrForfeitPending = errors.New("forfeit pending")
+
+// Forfeit is how a game ended that a player abandoned, e.g. by not coming back after a
+// disconnect.
+type Forfeit int
+
+const (
+	// ForfeitNone - The game was played to the end
+	ForfeitNone Forfeit = iota
+	// ForfeitLost - The declarer abandoned the game, it is lost at its current value
+	ForfeitLost
+	// ForfeitClaimed - A defender abandoned the game, the declarer took the remaining tricks
+	ForfeitClaimed
+	// ForfeitRedeal - A defender abandoned the game, the declarer chose to replay the
+	// deal; the game has no result
+	ForfeitRedeal
+)
+
+// String returns the code of the forfeit: "none", "lost", "claimed" or "redeal".
+func (f Forfeit) String() string {
+	switch f {
+	case ForfeitNone:
+		return "none"
+	case ForfeitLost:
+		return "lost"
+	case ForfeitClaimed:
+		return "claimed"
+	case ForfeitRedeal:
+		return "redeal"
+	default:
+		return fmt.Sprintf("Forfeit(%d)", int(f))
+	}
+}
+
+// MarshalText encodes the forfeit as its code.
+func (f Forfeit) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText decodes a forfeit code.
+func (f *Forfeit) UnmarshalText(text []byte) error {
+	for _, forfeit := range []Forfeit{ForfeitNone, ForfeitLost, ForfeitClaimed, ForfeitRedeal} {
+		if forfeit.String() == string(text) {
+			*f = forfeit
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid forfeit: %s", text)
+}
+
+// Abandon records that a player left the game during the trick play. An abandoning
+// declarer loses the game at its current value; if a defender abandons it, the
+// declarer chooses to claim the remaining tricks (Claim) or to replay the deal
+// (Redeal).
+func (g *Game) Abandon(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionAbandon})
+}
+
+// Redeal lets the declarer of a game abandoned by a defender replay the deal.
+func (g *Game) Redeal(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionRedeal})
+}
+
+// abandon processes a player leaving the game.
+func (g *Game) abandon(player Player) error {
+	if err := g.checkState(StateTrickPlaying); err != nil {
+		return err
+	}
+	if g.Contract.GameType.IsRamsch() {
+		return errors.New("no forfeits in ramsch games")
+	}
+	if g.Abandoned != nil {
+		return fmt.Errorf("%s already abandoned the game", *g.Abandoned)
+	}
+	g.PendingClaim = nil
+	g.Abandoned = &player
+	if player != *g.Declarer {
+		// The declarer decides with Claim or Redeal
+		return nil
+	}
+
+	g.Forfeit = ForfeitLost
+	g.State = StateCalculatingGameValue
+	g.Result = g.forfeitResult()
+	g.State = StateGameOver
+	return nil
+}
+
+// redeal ends a game abandoned by a defender without a result.
+func (g *Game) redeal(player Player) error {
+	if err := g.checkForfeitChoice(player); err != nil {
+		return err
+	}
+	g.Forfeit = ForfeitRedeal
+	g.State = StateGameOver
+	return nil
+}
+
+// checkForfeitChoice returns an error unless the player is the declarer of a game
+// abandoned by a defender.
+func (g *Game) checkForfeitChoice(player Player) error {
+	if err := g.checkState(StateTrickPlaying); err != nil {
+		return err
+	}
+	if g.Abandoned == nil || *g.Abandoned == *g.Declarer {
+		return errors.New("no defender abandoned the game")
+	}
+	return g.checkDeclarer(player)
+}
+
+// forfeitResult returns the result of a game abandoned by the declarer: lost with the
+// value of the contract as announced, the Schneider and Schwarz levels not yet
+// reached, but at least the bid.
+func (g *Game) forfeitResult() *GameResult {
+	contract := g.Contract.implied()
+	result := &GameResult{
+		Contract:   contract,
+		Declarer:   *g.Declarer,
+		BidValue:   g.Bidding.FinalBid,
+		Multiplier: 1,
+		Forfeit:    ForfeitLost,
+	}
+	tricks := g.TricksWonBy(*g.Declarer)
+	result.DeclarerTricks = len(tricks)
+	for _, t := range tricks {
+		result.TrickPoints += t.Points()
+	}
+	if contract.GameType.IsNull() {
+		result.GameValue = contract.BaseValue()
+	} else {
+		result.SkatPoints = cardPoints(g.Skat.Cards)
+		result.Matadors = CountMatadors(g.DeclarerCards, contract.GameType)
+		result.GameValue = contract.GameType.BaseValue() * gameLevel(contract, result)
+	}
+	result.DeclarerPoints = result.TrickPoints + result.SkatPoints
+
+	if result.GameValue < result.BidValue {
+		result.Overbid = true
+		base := contract.BaseValue()
+		result.GameValue = (result.BidValue + base - 1) / base * base
+	}
+	result.Score = -2 * result.GameValue
+	if g.KontraBy != nil {
+		result.applyKontra(g.ReAnnounced)
+	}
+	return result
+}
@@ -0,0 +1,703 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors of card plays rejected by the game.
+var (
+	ErrNotYourTurn   = errors.New("not your turn")
+	ErrCardNotInHand = errors.New("card not in hand")
+	ErrIllegalCard   = errors.New("illegal card play")
+)
+
+// ErrOverbid is returned for an announcement that cannot reach the bid (RefuseOverbid).
+var ErrOverbid = errors.New("game cannot reach the bid")
+
+// ActionType represents the type of a player action in a game.
+type ActionType int
+
+const (
+	// ActionBid - Bid a value
+	ActionBid ActionType = iota
+	// ActionHold - Hold the current bid
+	ActionHold
+	// ActionPass - Pass on bidding
+	ActionPass
+	// ActionPickUpSkat - Declarer picks up the skat
+	ActionPickUpSkat
+	// ActionDiscard - Declarer discards two cards to the skat
+	ActionDiscard
+	// ActionAnnounce - Declarer announces the contract
+	ActionAnnounce
+	// ActionPlayCard - Play a card
+	ActionPlayCard
+	// ActionKontra - A defender announces Kontra
+	ActionKontra
+	// ActionRe - The declarer answers a Kontra with Re
+	ActionRe
+	// ActionClaim - The declarer shows the cards and claims the remaining tricks
+	ActionClaim
+	// ActionConcede - A defender concedes the remaining tricks
+	ActionConcede
+	// ActionAcceptClaim - A player accepts the pending claim
+	ActionAcceptClaim
+	// ActionRejectClaim - A player rejects the pending claim
+	ActionRejectClaim
+	// ActionAbandon - A player left the game and forfeits it
+	ActionAbandon
+	// ActionRedeal - The declarer of a game abandoned by a defender replays the deal
+	ActionRedeal
+)
+
+// String returns the string representation of the action type.
+func (a ActionType) String() string {
+	switch a {
+	case ActionBid:
+		return "Bid"
+	case ActionHold:
+		return "Hold"
+	case ActionPass:
+		return "Pass"
+	case ActionPickUpSkat:
+		return "PickUpSkat"
+	case ActionDiscard:
+		return "Discard"
+	case ActionAnnounce:
+		return "Announce"
+	case ActionPlayCard:
+		return "PlayCard"
+	case ActionKontra:
+		return "Kontra"
+	case ActionRe:
+		return "Re"
+	case ActionClaim:
+		return "Claim"
+	case ActionConcede:
+		return "Concede"
+	case ActionAcceptClaim:
+		return "AcceptClaim"
+	case ActionRejectClaim:
+		return "RejectClaim"
+	case ActionAbandon:
+		return "Abandon"
+	case ActionRedeal:
+		return "Redeal"
+	default:
+		return fmt.Sprintf("ActionType(%d)", a)
+	}
+}
+
+// Action represents a single player action in a game.
+type Action struct {
+	// Player is the player who acted
+	Player Player
+	// Type is the type of the action
+	Type ActionType
+	// Value is the bid value (ActionBid only)
+	Value int
+	// Cards are the discarded cards (ActionDiscard) or the played card (ActionPlayCard)
+	Cards []Card
+	// Contract is the announced contract (ActionAnnounce only)
+	Contract *Contract
+	// Time is when the action was made (set by Apply if zero)
+	Time time.Time
+}
+
+// Game represents a single Skat game from the deal to the result.
+type Game struct {
+	// State is the current game state
+	State GameState
+	// Hands are the current hands of all players
+	Hands map[Player]*Hand
+	// Skat is the current skat
+	Skat *Hand
+	// DealtHands are the hands as dealt
+	DealtHands map[Player]*Hand
+	// DealtSkat is the skat as dealt
+	DealtSkat *Hand
+	// SkatPickedUp is true if the declarer picked up the skat
+	SkatPickedUp bool
+	// Bidding is the bidding state
+	Bidding *BiddingState
+	// Declarer is the winner of the bidding (nil for Ramsch)
+	Declarer *Player
+	// Contract is the announced contract (Ramsch if all players passed, nil if the
+	// deal was thrown in)
+	Contract *Contract
+	// DeclarerCards are the declarer's ten cards plus the skat at announcement
+	DeclarerCards []Card
+	// Trick is the trick currently being played
+	Trick *Trick
+	// Tricks are the completed tricks
+	Tricks []*Trick
+	// Actions are all player actions in order
+	Actions []Action
+	// Result is the result of a normal game
+	Result *GameResult
+	// RamschResult is the result of a Ramsch game
+	RamschResult *RamschResult
+	// Clocks are the remaining thinking times of the players (nil if not timed).
+	// The table starts the clock of the active player (StartClock), each action
+	// charges it; they are stored in snapshots.
+	Clocks map[Player]time.Duration
+	// RamschSkat is the rule who gets the skat in Ramsch games
+	RamschSkat RamschSkat
+	// RamschScoring is the rule how the loser of a Ramsch game scores
+	RamschScoring RamschScoring
+	// AllPass is the rule what happens when all players pass
+	AllPass AllPass
+	// GrandHandOffer is true while Forehand decides to play a Grand Hand (Announce) or
+	// the Ramsch (Pass) after all players passed (AllPassGrandHand)
+	GrandHandOffer bool
+	// PassedIn is true if all players passed and the deal was thrown in
+	PassedIn bool
+	// RefuseOverbid refuses announcements that cannot reach the bid while another game
+	// could; otherwise they are only lost as overbid at scoring
+	RefuseOverbid bool
+	// HideHandSkat keeps the untouched skat of a Hand game hidden after the game
+	HideHandSkat bool
+	// KontraRules are the Kontra and Re rules of the table (nil = only the order of
+	// the announcements is checked, e.g. when replaying)
+	KontraRules *KontraRules
+	// KontraBy is the defender who announced Kontra, if any
+	KontraBy *Player
+	// ReAnnounced is true if the declarer answered the Kontra with Re
+	ReAnnounced bool
+	// PendingClaim is the claim waiting for the answers of the other players (nil if
+	// none); no other moves are allowed meanwhile
+	PendingClaim *Claim
+	// Abandoned is the player who left the game during the trick play (nil if none);
+	// after a defender the declarer must Claim or Redeal
+	Abandoned *Player
+	// Forfeit is how an abandoned game ended
+	Forfeit Forfeit
+
+	// kontraAt is the index of the Kontra action
+	kontraAt int
+	// clockPlayer is the player whose clock runs since clockStarted (zero if stopped)
+	clockPlayer  Player
+	clockStarted time.Time
+}
+
+// NewGame creates a new game waiting for the deal.
+func NewGame() *Game {
+	return &Game{
+		State:   StateGameStart,
+		Hands:   make(map[Player]*Hand),
+		Skat:    NewHand(),
+		Bidding: NewBiddingState(),
+	}
+}
+
+// DealCards deals a 32-card deck in the 3-skat-4-3 pattern.
+func DealCards(deck *Deck) (map[Player]*Hand, *Hand, error) {
+	if deck.Remaining() != 32 {
+		return nil, nil, fmt.Errorf("deck must have 32 cards, has %d", deck.Remaining())
+	}
+
+	hands := make(map[Player]*Hand)
+	for _, p := range AllPlayers {
+		hands[p] = NewHand()
+	}
+	dealRound := func(count int) {
+		for i := 0; i < count; i++ {
+			for _, p := range AllPlayers {
+				hands[p].Add(deck.Deal(1)[0])
+			}
+		}
+	}
+
+	dealRound(3)
+	skatCards := NewHandFromCards(deck.Deal(2))
+	dealRound(4)
+	dealRound(3)
+
+	return hands, skatCards, nil
+}
+
+// Deal sets the dealt hands and the skat and starts the bidding. Misdeals are rejected
+// with ErrMisdeal (see ValidateDeal).
+func (g *Game) Deal(hands map[Player]*Hand, skatCards *Hand) error {
+	if g.State != StateGameStart {
+		return errors.New("can only deal at game start")
+	}
+	if err := ValidateDeal(hands, skatCards); err != nil {
+		return err
+	}
+
+	g.DealtHands = make(map[Player]*Hand)
+	for _, p := range AllPlayers {
+		g.DealtHands[p] = copyHand(hands[p])
+		g.Hands[p] = copyHand(hands[p])
+	}
+	g.DealtSkat = copyHand(skatCards)
+	g.Skat = copyHand(skatCards)
+	g.State = StateBidding
+	return nil
+}
+
+// copyHand returns a copy of the hand.
+func copyHand(hand *Hand) *Hand {
+	return NewHandFromCards(append([]Card(nil), hand.Cards...))
+}
+
+// ActivePlayer returns the player who has to act next, if any.
+func (g *Game) ActivePlayer() *Player {
+	switch g.State {
+	case StateBidding:
+		player := g.Bidding.ActivePlayer
+		return &player
+	case StatePickingUpSkat, StateDiscarding, StateDeclaring:
+		return g.Declarer
+	case StateTrickPlaying:
+		if g.PendingClaim != nil {
+			return g.PendingClaim.next(*g.Declarer)
+		}
+		if g.Abandoned != nil {
+			return g.Declarer
+		}
+		return g.Trick.NextPlayer()
+	default:
+		return nil
+	}
+}
+
+// OpenHand returns the cards the declarer of an Ouvert game has left. They are open to
+// the defenders and observers from the announcement, before the first card is played,
+// until the game end (nil in other games and before the announcement).
+func (g *Game) OpenHand() *Hand {
+	if g.Contract == nil || !g.Contract.Ouvert || g.Declarer == nil || g.State < StateTrickPlaying {
+		return nil
+	}
+	return copyHand(g.Hands[*g.Declarer])
+}
+
+// HandSkat returns the untouched skat of a Hand game. It stays face-down during the
+// game, counts for the declarer and is shown to all players after the game unless
+// HideHandSkat is set (nil in other games and before the game is over).
+func (g *Game) HandSkat() *Hand {
+	if g.Contract == nil || !g.Contract.Hand || g.Contract.GameType.IsRamsch() || g.HideHandSkat || g.State != StateGameOver {
+		return nil
+	}
+	return copyHand(g.DealtSkat)
+}
+
+// LegalMoves returns the cards the active player may play (empty outside trick playing).
+func (g *Game) LegalMoves() []Card {
+	player := g.ActivePlayer()
+	if g.State != StateTrickPlaying || g.PendingClaim != nil || g.Abandoned != nil || player == nil {
+		return nil
+	}
+	return g.Hands[*player].LegalMoves(g.Trick.LeadCard(), g.Contract.GameType)
+}
+
+// Apply applies a player action.
+func (g *Game) Apply(action Action) error {
+	switch {
+	case g.PendingClaim != nil && action.Type != ActionAcceptClaim && action.Type != ActionRejectClaim && action.Type != ActionAbandon:
+		return fmt.Errorf("%w by %s", ErrClaimPending, g.PendingClaim.By)
+	case g.Abandoned != nil && g.State == StateTrickPlaying && action.Type != ActionClaim && action.Type != ActionRedeal:
+		return fmt.Errorf("%w: %s abandoned the game", ErrForfeitPending, *g.Abandoned)
+	}
+
+	var err error
+	switch action.Type {
+	case ActionBid:
+		err = g.bid(action.Player, action.Value)
+	case ActionHold:
+		err = g.hold(action.Player)
+	case ActionPass:
+		err = g.pass(action.Player)
+	case ActionPickUpSkat:
+		err = g.pickUpSkat(action.Player)
+	case ActionDiscard:
+		err = g.discard(action.Player, action.Cards)
+	case ActionAnnounce:
+		err = g.announce(action.Player, action.Contract)
+	case ActionPlayCard:
+		if len(action.Cards) != 1 {
+			return errors.New("exactly one card must be played")
+		}
+		err = g.playCard(action.Player, action.Cards[0])
+	case ActionKontra, ActionRe:
+		if g.KontraRules != nil {
+			if err := g.KontraRules.Check(g, action); err != nil {
+				return err
+			}
+		}
+		if action.Type == ActionKontra {
+			err = g.kontra(action.Player)
+		} else {
+			err = g.re(action.Player)
+		}
+	case ActionClaim, ActionConcede:
+		err = g.claim(action.Player, action.Type == ActionConcede)
+	case ActionAcceptClaim, ActionRejectClaim:
+		err = g.answerClaim(action.Player, action.Type == ActionAcceptClaim)
+	case ActionAbandon:
+		err = g.abandon(action.Player)
+	case ActionRedeal:
+		err = g.redeal(action.Player)
+	default:
+		err = fmt.Errorf("unknown action: %s", action.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	if action.Time.IsZero() {
+		action.Time = time.Now()
+	}
+	g.chargeClock(action)
+	if action.Type == ActionAnnounce {
+		// Record the effective contract (the Hand flag is set by the game)
+		contract := *g.Contract
+		action.Contract = &contract
+	}
+	g.Actions = append(g.Actions, action)
+	return nil
+}
+
+// Bid bids a value.
+func (g *Game) Bid(player Player, value int) error {
+	return g.Apply(Action{Player: player, Type: ActionBid, Value: value})
+}
+
+// Hold holds the current bid.
+func (g *Game) Hold(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionHold})
+}
+
+// Pass passes on bidding.
+func (g *Game) Pass(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionPass})
+}
+
+// PickUpSkat lets the declarer pick up the skat.
+func (g *Game) PickUpSkat(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionPickUpSkat})
+}
+
+// Discard lets the declarer discard two cards to the skat.
+func (g *Game) Discard(player Player, card1, card2 Card) error {
+	return g.Apply(Action{Player: player, Type: ActionDiscard, Cards: []Card{card1, card2}})
+}
+
+// Announce lets the declarer announce the contract.
+// Announcing without picking up the skat makes the contract a Hand game.
+func (g *Game) Announce(player Player, contract *Contract) error {
+	return g.Apply(Action{Player: player, Type: ActionAnnounce, Contract: contract})
+}
+
+// PlayCard plays a card in the current trick.
+func (g *Game) PlayCard(player Player, card Card) error {
+	return g.Apply(Action{Player: player, Type: ActionPlayCard, Cards: []Card{card}})
+}
+
+// Kontra lets a defender announce Kontra.
+func (g *Game) Kontra(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionKontra})
+}
+
+// Re lets the declarer answer a Kontra with Re.
+func (g *Game) Re(player Player) error {
+	return g.Apply(Action{Player: player, Type: ActionRe})
+}
+
+// checkState returns an error if the game is not in the given state.
+func (g *Game) checkState(state GameState) error {
+	if g.State != state {
+		return fmt.Errorf("not in %s phase (current: %s)", state, g.State)
+	}
+	return nil
+}
+
+// checkDeclarer returns an error if the player is not the declarer.
+func (g *Game) checkDeclarer(player Player) error {
+	if g.Declarer == nil || *g.Declarer != player {
+		return fmt.Errorf("%s is not the declarer", player)
+	}
+	return nil
+}
+
+// bid processes a bid.
+func (g *Game) bid(player Player, value int) error {
+	if err := g.checkState(StateBidding); err != nil {
+		return err
+	}
+	if err := g.Bidding.Bid(player, value); err != nil {
+		return err
+	}
+	g.afterBidding()
+	return nil
+}
+
+// hold processes a hold.
+func (g *Game) hold(player Player) error {
+	if err := g.checkState(StateBidding); err != nil {
+		return err
+	}
+	if err := g.Bidding.Hold(player); err != nil {
+		return err
+	}
+	g.afterBidding()
+	return nil
+}
+
+// pass processes a pass, or Forehand declining the Grand Hand offer.
+func (g *Game) pass(player Player) error {
+	if g.GrandHandOffer {
+		return g.declineGrandHand(player)
+	}
+	if err := g.checkState(StateBidding); err != nil {
+		return err
+	}
+	if err := g.Bidding.Pass(player); err != nil {
+		return err
+	}
+	g.afterBidding()
+	return nil
+}
+
+// afterBidding advances the game once the bidding is complete.
+func (g *Game) afterBidding() {
+	switch g.Bidding.Result {
+	case BidResultHasDeclarer:
+		g.Declarer = g.Bidding.Declarer
+		g.State = StatePickingUpSkat
+	case BidResultAllPassed:
+		if g.AllPass == AllPassThrowIn {
+			// The deal is thrown in and scores nothing
+			g.PassedIn = true
+			g.State = StateGameOver
+			return
+		}
+		if g.AllPass == AllPassGrandHand {
+			forehand := Forehand
+			g.Declarer = &forehand
+			g.GrandHandOffer = true
+			g.State = StatePickingUpSkat
+			return
+		}
+		g.startRamsch()
+	}
+}
+
+// startRamsch starts the Ramsch played when all players passed.
+func (g *Game) startRamsch() {
+	g.Contract = NewContract(GameRamsch)
+	g.startTrickPlaying()
+}
+
+// declineGrandHand lets Forehand pass on the Grand Hand offer: the Ramsch is played.
+func (g *Game) declineGrandHand(player Player) error {
+	if err := g.checkDeclarer(player); err != nil {
+		return err
+	}
+	g.GrandHandOffer = false
+	g.Declarer = nil
+	g.startRamsch()
+	return nil
+}
+
+// pickUpSkat moves the skat into the declarer's hand.
+func (g *Game) pickUpSkat(player Player) error {
+	if err := g.checkState(StatePickingUpSkat); err != nil {
+		return err
+	}
+	if err := g.checkDeclarer(player); err != nil {
+		return err
+	}
+	if g.GrandHandOffer {
+		return errOnlyGrandHand
+	}
+
+	for _, c := range g.Skat.Cards {
+		g.Hands[player].Add(c)
+	}
+	g.Skat = NewHand()
+	g.SkatPickedUp = true
+	g.State = StateDiscarding
+	return nil
+}
+
+// discard moves two cards from the declarer's hand into the skat.
+func (g *Game) discard(player Player, cards []Card) error {
+	if err := g.checkState(StateDiscarding); err != nil {
+		return err
+	}
+	if err := g.checkDeclarer(player); err != nil {
+		return err
+	}
+	if len(cards) != 2 || cards[0] == cards[1] {
+		return errors.New("exactly two different cards must be discarded")
+	}
+
+	hand := g.Hands[player]
+	for _, c := range cards {
+		if !hand.Contains(c) {
+			return fmt.Errorf("card not in hand: %s", c.Code())
+		}
+	}
+	for _, c := range cards {
+		hand.Remove(c)
+		g.Skat.Add(c)
+	}
+	g.State = StateDeclaring
+	return nil
+}
+
+// announce sets the contract and starts the trick playing.
+func (g *Game) announce(player Player, contract *Contract) error {
+	if g.State != StatePickingUpSkat && g.State != StateDeclaring {
+		return fmt.Errorf("not in %s phase (current: %s)", StateDeclaring, g.State)
+	}
+	if err := g.checkDeclarer(player); err != nil {
+		return err
+	}
+	if contract == nil || contract.GameType.IsRamsch() {
+		return errors.New("invalid contract")
+	}
+	if g.GrandHandOffer && contract.GameType != GameGrand {
+		return errOnlyGrandHand
+	}
+
+	announced := *contract
+	announced.Hand = g.State == StatePickingUpSkat
+	if !announced.Hand && announced.Ouvert && !announced.GameType.IsNull() {
+		return fmt.Errorf("invalid contract %s: Ouvert requires a Hand game", announced.Code())
+	}
+	announced = announced.implied()
+	if err := announced.Validate(); err != nil {
+		return err
+	}
+	cards := append(append([]Card(nil), g.Hands[player].Cards...), g.Skat.Cards...)
+	if g.RefuseOverbid {
+		if err := g.checkBidObligation(announced, cards); err != nil {
+			return err
+		}
+	}
+	g.Contract = &announced
+
+	g.DeclarerCards = cards
+	g.GrandHandOffer = false
+	g.startTrickPlaying()
+	return nil
+}
+
+// errOnlyGrandHand is returned for other moves than a Grand Hand or a pass while
+// Forehand decides on the Grand Hand offer.
+var errOnlyGrandHand = errors.New("only a Grand Hand can replace the Ramsch")
+
+// checkBidObligation refuses a contract whose highest value stays below the bid when
+// another contract the declarer may still announce could reach it.
+func (g *Game) checkBidObligation(contract Contract, cards []Card) error {
+	bid := g.Bidding.FinalBid
+	if MaxGameValue(contract, cards) >= bid {
+		return nil
+	}
+	for _, gameType := range AllGameTypes {
+		// Ouvert adds the most; suit and Grand games only allow it in Hand games
+		other := Contract{GameType: gameType, Hand: contract.Hand, Ouvert: contract.Hand || gameType.IsNull()}
+		if MaxGameValue(other, cards) >= bid {
+			return fmt.Errorf("%w: %s reaches at most %d, bid %d", ErrOverbid, contract.Code(), MaxGameValue(contract, cards), bid)
+		}
+	}
+	return nil
+}
+
+// startTrickPlaying starts the first trick, led by Forehand.
+func (g *Game) startTrickPlaying() {
+	g.Trick = NewTrick(Forehand)
+	g.State = StateTrickPlaying
+}
+
+// playCard plays a card and completes the trick if all players have played.
+func (g *Game) playCard(player Player, card Card) error {
+	if err := g.checkState(StateTrickPlaying); err != nil {
+		return err
+	}
+	if next := g.Trick.NextPlayer(); next == nil || *next != player {
+		return fmt.Errorf("%w: %s", ErrNotYourTurn, player)
+	}
+
+	hand := g.Hands[player]
+	if !hand.Contains(card) {
+		return fmt.Errorf("%w: %s", ErrCardNotInHand, card.Code())
+	}
+	if !card.CanPlay(g.Trick.LeadCard(), hand, g.Contract.GameType) {
+		return fmt.Errorf("%w: %s", ErrIllegalCard, card.Code())
+	}
+
+	hand.Remove(card)
+	if err := g.Trick.AddCard(card, player); err != nil {
+		return err
+	}
+
+	if !g.Trick.IsComplete() {
+		return nil
+	}
+	if err := g.Trick.Complete(g.Contract.GameType); err != nil {
+		return err
+	}
+	g.Tricks = append(g.Tricks, g.Trick)
+
+	// The declarer loses a Null game with the first trick taken
+	nullLost := g.Contract.GameType.IsNull() && *g.Trick.Winner == *g.Declarer
+	if len(g.Tricks) == TricksPerGame || nullLost {
+		g.State = StatePreliminaryGameEnd
+		return g.finish()
+	}
+	g.Trick = NewTrick(*g.Trick.Winner)
+	return nil
+}
+
+// finish calculates the result of a game whose tricks are complete and ends it.
+// playCard calls it with the last trick.
+func (g *Game) finish() error {
+	if err := g.checkState(StatePreliminaryGameEnd); err != nil {
+		return err
+	}
+	g.State = StateCalculatingGameValue
+
+	if g.Contract.GameType.IsRamsch() {
+		g.RamschResult = CalculateRamschResult(g.Tricks, g.Skat.Cards, g.RamschSkat)
+		g.RamschResult.applyScoring(g.RamschScoring)
+	} else {
+		g.Result = CalculateGameResult(*g.Contract, *g.Declarer, g.DeclarerCards, g.TricksWonBy(*g.Declarer), g.Skat.Cards, g.Bidding.FinalBid)
+		if g.KontraBy != nil {
+			g.Result.applyKontra(g.ReAnnounced)
+		}
+	}
+
+	g.State = StateGameOver
+	return nil
+}
+
+// TricksWonBy returns the completed tricks won by the given player.
+func (g *Game) TricksWonBy(player Player) []*Trick {
+	tricks := make([]*Trick, 0, len(g.Tricks))
+	for _, t := range g.Tricks {
+		if t.Winner != nil && *t.Winner == player {
+			tricks = append(tricks, t)
+		}
+	}
+	return tricks
+}
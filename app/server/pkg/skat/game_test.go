@@ -0,0 +1,991 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestGame creates a dealt game where Forehand holds all Jacks and six Clubs.
+func newTestGame(t *testing.T) *Game {
+	t.Helper()
+
+	codes := map[Player]string{
+		Forehand:   "CJ.SJ.HJ.DJ.CA.CT.CK.CQ.C9.C8",
+		Middlehand: "SA.ST.SK.SQ.S9.S8.S7.HA.HT.HK",
+		Rearhand:   "HQ.H9.H8.H7.DA.DT.DK.DQ.D9.D8",
+	}
+	hands := make(map[Player]*Hand)
+	for p, code := range codes {
+		hand, err := HandFromCode(code)
+		if err != nil {
+			t.Fatalf("HandFromCode(%q) error: %v", code, err)
+		}
+		hands[p] = hand
+	}
+	skatCards, _ := HandFromCode("C7.D7")
+
+	game := NewGame()
+	if err := game.Deal(hands, skatCards); err != nil {
+		t.Fatalf("Deal() error: %v", err)
+	}
+	return game
+}
+
+func TestMisdeal(t *testing.T) {
+	valid := newTestGame(t)
+	hands := map[Player]*Hand{Forehand: valid.DealtHands[Forehand], Middlehand: valid.DealtHands[Middlehand], Rearhand: valid.DealtHands[Rearhand]}
+	twice, _ := HandFromCode("C7.CJ")
+	if err := NewGame().Deal(hands, twice); !errors.Is(err, ErrMisdeal) {
+		t.Errorf("Deal() with a card dealt twice: error = %v, want %v", err, ErrMisdeal)
+	}
+	short := map[Player]*Hand{Forehand: hands[Forehand], Middlehand: hands[Middlehand], Rearhand: NewHand()}
+	if err := NewGame().Deal(short, valid.DealtSkat); !errors.Is(err, ErrMisdeal) {
+		t.Errorf("Deal() with an empty hand: error = %v, want %v", err, ErrMisdeal)
+	}
+
+	var misdeals []int
+	dealt, skatCards, err := DealUntilValid(func(attempt int) (map[Player]*Hand, *Hand, error) {
+		if attempt < 2 {
+			return hands, twice, nil
+		}
+		return hands, valid.DealtSkat, nil
+	}, func(attempt int, err error) { misdeals = append(misdeals, attempt) })
+	if err != nil || dealt[Forehand] != hands[Forehand] || skatCards != valid.DealtSkat || len(misdeals) != 2 {
+		t.Errorf("DealUntilValid() = %v after misdeals %v, want the third deal", err, misdeals)
+	}
+	_, _, err = DealUntilValid(func(int) (map[Player]*Hand, *Hand, error) { return hands, twice, nil }, nil)
+	if !errors.Is(err, ErrMisdeal) {
+		t.Errorf("DealUntilValid() with misdeals only: error = %v, want %v", err, ErrMisdeal)
+	}
+}
+
+// playOut plays the remaining tricks with the first legal card of each player.
+func playOut(t *testing.T, game *Game) {
+	t.Helper()
+
+	for game.State == StateTrickPlaying {
+		player := game.ActivePlayer()
+		if err := game.PlayCard(*player, game.LegalMoves()[0]); err != nil {
+			t.Fatalf("PlayCard() error: %v", err)
+		}
+	}
+}
+
+// ============================================================================
+// Game Flow Tests
+// ============================================================================
+
+func TestDealCards(t *testing.T) {
+	hands, skatCards, err := DealCards(NewDeck())
+	if err != nil {
+		t.Fatalf("DealCards() error: %v", err)
+	}
+	for _, p := range AllPlayers {
+		if hands[p].Size() != 10 {
+			t.Errorf("%s has %d cards, want 10", p, hands[p].Size())
+		}
+	}
+	if skatCards.Size() != 2 {
+		t.Errorf("skat has %d cards, want 2", skatCards.Size())
+	}
+}
+
+func TestGameSchwarzClubs(t *testing.T) {
+	game := newTestGame(t)
+
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	if game.State != StatePickingUpSkat || game.Declarer == nil || *game.Declarer != Forehand {
+		t.Fatalf("Forehand should declare, state = %s", game.State)
+	}
+
+	mustDo(t, game.PickUpSkat(Forehand))
+	if err := game.Discard(Forehand, NewCard(Diamonds, Seven), NewCard(Diamonds, Ten)); err == nil {
+		t.Fatal("discarding a card not in hand should fail")
+	}
+	mustDo(t, game.Discard(Forehand, NewCard(Diamonds, Seven), NewCard(Clubs, Seven)))
+	mustDo(t, game.Announce(Forehand, NewContract(GameClubs)))
+
+	if game.Contract.Hand {
+		t.Error("contract should not be a Hand game after picking up the skat")
+	}
+	if err := game.PlayCard(Middlehand, NewCard(Spades, Ace)); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("Middlehand leading: error = %v, want %v", err, ErrNotYourTurn)
+	}
+
+	playOut(t, game)
+	if game.State != StateGameOver {
+		t.Fatalf("State = %s, want %s", game.State, StateGameOver)
+	}
+
+	result := game.Result
+	if !result.DeclarerWon || !result.Schneider || !result.Schwarz {
+		t.Errorf("Result = %+v, want won with Schneider and Schwarz", result)
+	}
+	if result.Matadors != 11 {
+		t.Errorf("Matadors = %d, want 11", result.Matadors)
+	}
+	// Clubs (12) x (11 matadors + game + Schneider + Schwarz)
+	if result.GameValue != 168 || result.Score != 168 {
+		t.Errorf("GameValue = %d, Score = %d, want 168", result.GameValue, result.Score)
+	}
+}
+
+func TestGameHandAnnouncement(t *testing.T) {
+	game := newTestGame(t)
+
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Bid(Rearhand, 18))
+	mustDo(t, game.Pass(Forehand))
+	mustDo(t, game.Announce(Rearhand, NewContract(GameNull)))
+
+	if !game.Contract.Hand {
+		t.Error("contract should be a Hand game without picking up the skat")
+	}
+	if got := game.Actions[len(game.Actions)-1].Contract.Code(); got != "NH" {
+		t.Errorf("recorded contract = %s, want NH", got)
+	}
+
+	playOut(t, game)
+
+	won := len(game.TricksWonBy(Rearhand)) == 0
+	if game.Result.DeclarerWon != won {
+		t.Errorf("DeclarerWon = %v, want %v", game.Result.DeclarerWon, won)
+	}
+	if !won && len(game.Tricks) == TricksPerGame && *game.Tricks[len(game.Tricks)-1].Winner != Rearhand {
+		t.Error("Null game should end with the declarer's first trick")
+	}
+}
+
+func TestHandSkat(t *testing.T) {
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Bid(Rearhand, 18))
+	mustDo(t, game.Pass(Forehand))
+	mustDo(t, game.Announce(Rearhand, NewContract(GameClubs)))
+	if game.HandSkat() != nil {
+		t.Error("HandSkat() before the game end should be nil")
+	}
+
+	playOut(t, game)
+	skatCards := game.HandSkat()
+	if skatCards == nil || skatCards.Code() != game.DealtSkat.Code() {
+		t.Fatalf("HandSkat() = %v, want %s", skatCards, game.DealtSkat.Code())
+	}
+	if want := cardPoints(game.DealtSkat.Cards); game.Result.SkatPoints != want {
+		t.Errorf("SkatPoints = %d, want %d", game.Result.SkatPoints, want)
+	}
+	game.HideHandSkat = true
+	if game.HandSkat() != nil {
+		t.Error("HandSkat() with HideHandSkat should be nil")
+	}
+}
+
+func TestRefuseOverbid(t *testing.T) {
+	bidTo36 := func(refuse bool) *Game {
+		game := newTestGame(t)
+		game.RefuseOverbid = refuse
+		mustDo(t, game.Bid(Middlehand, 36))
+		mustDo(t, game.Pass(Forehand))
+		mustDo(t, game.Pass(Rearhand))
+		return game
+	}
+
+	game := bidTo36(true)
+	if err := game.Announce(Middlehand, NewContract(GameNull)); !errors.Is(err, ErrOverbid) {
+		t.Fatalf("Null Hand at 36: error = %v, want %v", err, ErrOverbid)
+	}
+	if game.Contract != nil || game.State != StatePickingUpSkat {
+		t.Errorf("refused announcement changed the game: contract %v, state %s", game.Contract, game.State)
+	}
+	mustDo(t, game.Announce(Middlehand, NewContract(GameGrand)))
+
+	game = bidTo36(false)
+	mustDo(t, game.Announce(Middlehand, NewContract(GameNull)))
+	playOut(t, game)
+	if !game.Result.Overbid || game.Result.DeclarerWon {
+		t.Errorf("Result = %+v, want lost as overbid", game.Result)
+	}
+}
+
+func TestMaxGameValue(t *testing.T) {
+	cards := []Card{NewCard(Clubs, Jack), NewCard(Spades, Jack), NewCard(Clubs, Seven), NewCard(Diamonds, Seven)}
+	tests := []struct {
+		contract Contract
+		want     int
+	}{
+		{Contract{GameType: GameNull}, 23},
+		{Contract{GameType: GameNull, Hand: true, Ouvert: true}, 59},
+		// With 2, game 3, Schneider 4, Schwarz 5
+		{Contract{GameType: GameClubs}, 5 * 12},
+		// Hand 4, Schneider and Schwarz announced 8, Ouvert 9
+		{Contract{GameType: GameGrand, Hand: true, Ouvert: true}, 9 * 24},
+	}
+	for _, tt := range tests {
+		if got := MaxGameValue(tt.contract, cards); got != tt.want {
+			t.Errorf("MaxGameValue(%s) = %d, want %d", tt.contract.Code(), got, tt.want)
+		}
+	}
+}
+
+func TestPlayCardErrors(t *testing.T) {
+	game := newTestGame(t)
+
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	mustDo(t, game.Announce(Forehand, NewContract(GameHearts)))
+	mustDo(t, game.PlayCard(Forehand, NewCard(Hearts, Jack)))
+
+	tests := []struct {
+		player Player
+		card   Card
+		want   error
+	}{
+		{Rearhand, NewCard(Hearts, Queen), ErrNotYourTurn},
+		{Middlehand, NewCard(Clubs, Jack), ErrCardNotInHand},
+		// Middlehand holds trumps (Hearts) and must follow
+		{Middlehand, NewCard(Spades, Ace), ErrIllegalCard},
+	}
+	for _, tt := range tests {
+		if err := game.PlayCard(tt.player, tt.card); !errors.Is(err, tt.want) {
+			t.Errorf("PlayCard(%s, %s) error = %v, want %v", tt.player, tt.card.Code(), err, tt.want)
+		}
+	}
+
+	// Rejected plays leave the game unchanged
+	if len(game.Actions) != 5 || len(game.Trick.Cards) != 1 || *game.ActivePlayer() != Middlehand {
+		t.Errorf("game changed by rejected plays: %d actions, %d cards in trick", len(game.Actions), len(game.Trick.Cards))
+	}
+	if game.Hands[Middlehand].Size() != 10 {
+		t.Errorf("Middlehand has %d cards, want 10", game.Hands[Middlehand].Size())
+	}
+}
+
+// newKontraGame returns a test game with the rules where Middlehand bid 18 and
+// Rearhand passed, and Forehand announced a Clubs Hand game.
+func newKontraGame(t *testing.T, rules *KontraRules) *Game {
+	t.Helper()
+
+	game := newTestGame(t)
+	game.KontraRules = rules
+	mustDo(t, game.Bid(Middlehand, 18))
+	mustDo(t, game.Hold(Forehand))
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Announce(Forehand, NewContract(GameClubs)))
+	return game
+}
+
+func TestKontraRe(t *testing.T) {
+	plain := newKontraGame(t, nil)
+	playOut(t, plain)
+
+	game := newKontraGame(t, &KontraRules{MinBid: 18})
+	if err := game.Kontra(Forehand); err == nil {
+		t.Error("the declarer should not be able to announce kontra")
+	}
+	if err := game.Re(Forehand); err == nil {
+		t.Error("re should require a kontra")
+	}
+	mustDo(t, game.Kontra(Middlehand))
+	if err := game.Kontra(Rearhand); err == nil {
+		t.Error("kontra should be announced only once")
+	}
+	mustDo(t, game.Re(Forehand))
+
+	// Snapshots restore the announcements
+	data, err := game.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error: %v", err)
+	}
+	restored, err := Deserialize(data)
+	if err != nil {
+		t.Fatalf("Deserialize() error: %v", err)
+	}
+	if restored.KontraBy == nil || *restored.KontraBy != Middlehand || !restored.ReAnnounced {
+		t.Error("restored game lost kontra and re")
+	}
+
+	playOut(t, game)
+	if !game.Result.Kontra || !game.Result.Re {
+		t.Errorf("Result = %+v, want kontra and re", game.Result)
+	}
+	if game.Result.Score != 4*plain.Result.Score {
+		t.Errorf("Score = %d, want %d", game.Result.Score, 4*plain.Result.Score)
+	}
+	if game.Result.Multiplier != 4 || game.Result.BaseGameValue() != plain.Result.GameValue || plain.Result.Multiplier != 1 {
+		t.Errorf("Multiplier = %d, base value %d, want 4 and %d", game.Result.Multiplier, game.Result.BaseGameValue(), plain.Result.GameValue)
+	}
+}
+
+func TestClocks(t *testing.T) {
+	game := newTestGame(t)
+	game.Clocks = map[Player]time.Duration{Forehand: time.Minute, Middlehand: time.Minute, Rearhand: time.Minute}
+	start := time.Now()
+
+	game.StartClock(start)
+	if got := game.Remaining(Middlehand, start.Add(10*time.Second)); got != 50*time.Second {
+		t.Errorf("running clock: Remaining() = %v, want 50s", got)
+	}
+	mustDo(t, game.Apply(Action{Player: Middlehand, Type: ActionBid, Value: 18, Time: start.Add(10 * time.Second)}))
+	// The clocks pause until the table starts the next one
+	if got := game.Remaining(Forehand, start.Add(time.Hour)); got != time.Minute {
+		t.Errorf("paused clock: Remaining() = %v, want 1m", got)
+	}
+
+	game.StartClock(start.Add(20 * time.Second))
+	mustDo(t, game.Apply(Action{Player: Forehand, Type: ActionHold, Time: start.Add(25 * time.Second)}))
+	game.StartClock(start.Add(30 * time.Second))
+	mustDo(t, game.Apply(Action{Player: Middlehand, Type: ActionPass, Time: start.Add(2 * time.Minute)}))
+
+	want := map[Player]time.Duration{Forehand: 55 * time.Second, Middlehand: 0, Rearhand: time.Minute}
+	for p, d := range want {
+		if game.Clocks[p] != d {
+			t.Errorf("%s clock = %v, want %v", p, game.Clocks[p], d)
+		}
+	}
+}
+
+func TestClaim(t *testing.T) {
+	game := newKontraGame(t, nil)
+	mustDo(t, game.PlayCard(Forehand, game.LegalMoves()[0]))
+	if err := game.Concede(Forehand); err == nil {
+		t.Error("the declarer should not be able to concede")
+	}
+	if err := game.Claim(Middlehand); err == nil {
+		t.Error("a defender should not be able to claim")
+	}
+
+	mustDo(t, game.Claim(Forehand))
+	if err := game.PlayCard(Middlehand, game.Hands[Middlehand].Cards[0]); !errors.Is(err, ErrClaimPending) {
+		t.Errorf("PlayCard() with a pending claim: error = %v, want %v", err, ErrClaimPending)
+	}
+	if active := game.ActivePlayer(); active == nil || *active != Middlehand || game.LegalMoves() != nil {
+		t.Errorf("ActivePlayer() = %v with legal moves %v, want Middlehand to answer", active, game.LegalMoves())
+	}
+	if err := game.AcceptClaim(Rearhand); !errors.Is(err, ErrNotYourTurn) {
+		t.Errorf("AcceptClaim(Rearhand) error = %v, want %v", err, ErrNotYourTurn)
+	}
+	mustDo(t, game.AcceptClaim(Middlehand))
+	mustDo(t, game.RejectClaim(Rearhand))
+	if game.PendingClaim != nil || game.State != StateTrickPlaying || len(game.LegalMoves()) == 0 {
+		t.Fatalf("rejected claim: pending %v in state %s, want the play to continue", game.PendingClaim, game.State)
+	}
+
+	mustDo(t, game.Claim(Forehand))
+	mustDo(t, game.AcceptClaim(Middlehand))
+	mustDo(t, game.AcceptClaim(Rearhand))
+	if game.State != StateGameOver || len(game.Tricks) != TricksPerGame || !game.Tricks[0].Claimed {
+		t.Fatalf("accepted claim: state %s with %d tricks, want the game over", game.State, len(game.Tricks))
+	}
+	if !game.Result.Schwarz || game.Result.DeclarerTricks != TricksPerGame || game.Result.DeclarerPoints != TotalPoints {
+		t.Errorf("Result = %+v, want all tricks for the declarer", game.Result)
+	}
+	if restored := roundTrip(t, game); restored.State != StateGameOver || restored.Result.Score != game.Result.Score {
+		t.Errorf("restored state %s with score %v, want %s with %d", restored.State, restored.Result, StateGameOver, game.Result.Score)
+	}
+
+	// A concession only needs the partner
+	game = newKontraGame(t, nil)
+	mustDo(t, game.Concede(Rearhand))
+	if active := game.ActivePlayer(); active == nil || *active != Middlehand {
+		t.Fatalf("ActivePlayer() = %v, want Middlehand", active)
+	}
+	mustDo(t, game.AcceptClaim(Middlehand))
+	if game.State != StateGameOver || !game.Result.DeclarerWon {
+		t.Errorf("conceded game: state %s, result %+v, want won by the declarer", game.State, game.Result)
+	}
+}
+
+func TestForfeit(t *testing.T) {
+	// The declarer abandons: lost at the value of the contract, at least the bid
+	game := newKontraGame(t, nil)
+	mustDo(t, game.PlayCard(Forehand, game.LegalMoves()[0]))
+	mustDo(t, game.Abandon(Forehand))
+	want := GameClubs.BaseValue() * (abs(CountMatadors(game.DeclarerCards, GameClubs)) + game.Contract.Multiplier())
+	if game.State != StateGameOver || game.Forfeit != ForfeitLost || game.Result.Forfeit != ForfeitLost {
+		t.Fatalf("declarer forfeit: state %s, forfeit %s, want %s", game.State, game.Forfeit, ForfeitLost)
+	}
+	if game.Result.DeclarerWon || game.Result.GameValue != max(want, 18) || game.Result.Score != -2*game.Result.GameValue {
+		t.Errorf("Result = %+v, want lost with value %d", game.Result, want)
+	}
+	if restored := roundTrip(t, game); restored.Forfeit != ForfeitLost || restored.Result.Score != game.Result.Score {
+		t.Errorf("restored forfeit %s with result %+v", restored.Forfeit, restored.Result)
+	}
+
+	// A defender abandons: the declarer chooses
+	game = newKontraGame(t, nil)
+	mustDo(t, game.Abandon(Middlehand))
+	if err := game.PlayCard(Forehand, game.Hands[Forehand].Cards[0]); !errors.Is(err, ErrForfeitPending) {
+		t.Errorf("PlayCard() after a forfeit: error = %v, want %v", err, ErrForfeitPending)
+	}
+	if active := game.ActivePlayer(); active == nil || *active != Forehand || game.LegalMoves() != nil {
+		t.Errorf("ActivePlayer() = %v, want the declarer to choose", active)
+	}
+	if err := game.Redeal(Rearhand); err == nil {
+		t.Error("a defender should not be able to choose a redeal")
+	}
+	mustDo(t, game.Claim(Forehand))
+	if game.State != StateGameOver || game.Forfeit != ForfeitClaimed || !game.Result.DeclarerWon || game.Result.DeclarerTricks != TricksPerGame {
+		t.Errorf("claimed forfeit: state %s, forfeit %s, result %+v", game.State, game.Forfeit, game.Result)
+	}
+
+	game = newKontraGame(t, nil)
+	mustDo(t, game.Abandon(Rearhand))
+	mustDo(t, game.Redeal(Forehand))
+	if game.State != StateGameOver || game.Forfeit != ForfeitRedeal || game.Result != nil {
+		t.Errorf("redeal: state %s, forfeit %s, result %+v, want no result", game.State, game.Forfeit, game.Result)
+	}
+	if restored := roundTrip(t, game); restored.Forfeit != ForfeitRedeal || restored.State != StateGameOver {
+		t.Errorf("restored redeal: state %s, forfeit %s", restored.State, restored.Forfeit)
+	}
+}
+
+func TestKontraRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  KontraRules
+		cards  int
+		player Player
+		want   error
+	}{
+		{"before the first card", KontraRules{}, 0, Middlehand, nil},
+		{"after the declarer's first card", KontraRules{}, 1, Middlehand, ErrTooLate},
+		{"in the first trick", KontraRules{Timing: KontraUntilSecondTrick}, 2, Middlehand, nil},
+		{"in the second trick", KontraRules{Timing: KontraUntilSecondTrick}, 3, Middlehand, ErrTooLate},
+		{"bid 18", KontraRules{MinBid: 18}, 0, Middlehand, nil},
+		{"passed without bidding", KontraRules{MinBid: 18}, 0, Rearhand, ErrBidTooLow},
+		{"not allowed", KontraRules{Off: true}, 0, Middlehand, ErrKontraNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := tt.rules
+			game := newKontraGame(t, &rules)
+			for i := 0; i < tt.cards; i++ {
+				mustDo(t, game.PlayCard(*game.ActivePlayer(), game.LegalMoves()[0]))
+			}
+			actions := len(game.Actions)
+			if err := game.Kontra(tt.player); !errors.Is(err, tt.want) {
+				t.Errorf("Kontra() error = %v, want %v", err, tt.want)
+			}
+			if tt.want != nil && (len(game.Actions) != actions || game.KontraBy != nil) {
+				t.Error("rejected kontra changed the game")
+			}
+		})
+	}
+}
+
+func TestReTiming(t *testing.T) {
+	game := newKontraGame(t, &KontraRules{Timing: KontraUntilSecondTrick})
+	mustDo(t, game.PlayCard(Forehand, game.LegalMoves()[0]))
+	mustDo(t, game.Kontra(Middlehand))
+	mustDo(t, game.PlayCard(Middlehand, game.LegalMoves()[0]))
+	mustDo(t, game.PlayCard(Rearhand, game.LegalMoves()[0]))
+	// Forehand won the first trick and leads the second one
+	mustDo(t, game.PlayCard(Forehand, game.LegalMoves()[0]))
+	if err := game.Re(Forehand); !errors.Is(err, ErrTooLate) {
+		t.Errorf("Re() error = %v, want %v", err, ErrTooLate)
+	}
+}
+
+func TestContractValidate(t *testing.T) {
+	tests := []struct {
+		code  string
+		valid bool
+	}{
+		{"C", true},
+		{"GHSZ", true},
+		{"CHOSZ", true},
+		{"NO", true},
+		{"NHO", true},
+		{"CS", false},
+		{"GZ", false},
+		{"GHZ", false},
+		{"CHO", false},
+		{"NHS", false},
+		{"RH", false},
+	}
+	for _, tt := range tests {
+		contract, err := ContractFromCode(tt.code)
+		if err != nil {
+			t.Fatalf("ContractFromCode(%q) error: %v", tt.code, err)
+		}
+		if err := contract.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%s) error = %v, want valid %v", tt.code, err, tt.valid)
+		}
+	}
+}
+
+func TestAnnounceSchneiderSchwarz(t *testing.T) {
+	// Announcements after picking up the skat are rejected
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	mustDo(t, game.PickUpSkat(Forehand))
+	mustDo(t, game.Discard(Forehand, NewCard(Diamonds, Seven), NewCard(Clubs, Seven)))
+	if err := game.Announce(Forehand, &Contract{GameType: GameClubs, Schneider: true}); err == nil {
+		t.Error("Schneider announced without Hand should fail")
+	}
+	if game.State != StateDeclaring {
+		t.Errorf("State = %s, want %s", game.State, StateDeclaring)
+	}
+
+	// Ouvert implies Schneider and Schwarz announced
+	game = newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	mustDo(t, game.Announce(Forehand, &Contract{GameType: GameClubs, Ouvert: true}))
+	if c := game.Contract; !c.Hand || !c.Schneider || !c.Schwarz {
+		t.Errorf("Contract = %s, want CHOSZ", c.Code())
+	}
+}
+
+func TestOpenHand(t *testing.T) {
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	if game.OpenHand() != nil {
+		t.Error("OpenHand() before the announcement is not nil")
+	}
+
+	// The hand is open before the first card and shrinks with the played cards
+	mustDo(t, game.Announce(Forehand, &Contract{GameType: GameClubs, Ouvert: true}))
+	if open := game.OpenHand(); open == nil || open.Code() != game.Hands[Forehand].Code() {
+		t.Fatalf("OpenHand() = %v, want the hand of Forehand", open)
+	}
+	mustDo(t, game.PlayCard(Forehand, NewCard(Clubs, Jack)))
+	if open := game.OpenHand(); open.Size() != 9 || open.Contains(NewCard(Clubs, Jack)) {
+		t.Errorf("OpenHand() after the first card = %s", open.Code())
+	}
+
+	game = newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	mustDo(t, game.Announce(Forehand, NewContract(GameClubs)))
+	if game.OpenHand() != nil {
+		t.Error("OpenHand() of a closed game is not nil")
+	}
+}
+
+func TestAllPass(t *testing.T) {
+	passAll := func(game *Game) {
+		mustDo(t, game.Pass(Middlehand))
+		mustDo(t, game.Pass(Rearhand))
+		mustDo(t, game.Pass(Forehand))
+	}
+
+	game := newTestGame(t)
+	passAll(game)
+	if game.State != StateTrickPlaying || !game.Contract.GameType.IsRamsch() || game.PassedIn {
+		t.Errorf("AllPassRamsch: state %s, contract %v, passed in %v, want Ramsch", game.State, game.Contract, game.PassedIn)
+	}
+
+	game = newTestGame(t)
+	game.AllPass = AllPassThrowIn
+	passAll(game)
+	if game.State != StateGameOver || game.Contract != nil || !game.PassedIn || game.ActivePlayer() != nil {
+		t.Fatalf("AllPassThrowIn: state %s, contract %v, passed in %v, want thrown in", game.State, game.Contract, game.PassedIn)
+	}
+
+	record, err := NewGameRecord("g1", time.Now(), map[Player]string{Forehand: "a", Middlehand: "b", Rearhand: "c"}, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+	if replayed, err := record.Replay(nil); err != nil || !replayed.PassedIn {
+		t.Errorf("Replay() = passed in %v, error %v, want thrown in", replayed != nil && replayed.PassedIn, err)
+	}
+	if restored := roundTrip(t, game); !restored.PassedIn || restored.AllPass != AllPassThrowIn {
+		t.Errorf("restored passed in %v, rule %s, want thrown in", restored.PassedIn, restored.AllPass)
+	}
+
+	var rule AllPass
+	if err := rule.UnmarshalText([]byte("throw-in")); err != nil || rule != AllPassThrowIn {
+		t.Errorf("UnmarshalText(throw-in) = %s, %v", rule, err)
+	}
+	if err := rule.UnmarshalText([]byte("redeal")); err == nil {
+		t.Error("UnmarshalText(redeal) succeeded")
+	}
+}
+
+func TestAllPassGrandHand(t *testing.T) {
+	offer := func() *Game {
+		game := newTestGame(t)
+		game.AllPass = AllPassGrandHand
+		mustDo(t, game.Pass(Middlehand))
+		mustDo(t, game.Pass(Rearhand))
+		mustDo(t, game.Pass(Forehand))
+		return game
+	}
+
+	game := offer()
+	if !game.GrandHandOffer || game.ActivePlayer() == nil || *game.ActivePlayer() != Forehand {
+		t.Fatalf("offer %v, active %v, want Forehand to decide", game.GrandHandOffer, game.ActivePlayer())
+	}
+	if err := game.PickUpSkat(Forehand); err == nil {
+		t.Error("PickUpSkat() during the offer succeeded")
+	}
+	if err := game.Announce(Forehand, NewContract(GameClubs)); err == nil {
+		t.Error("Announce(clubs) during the offer succeeded")
+	}
+	mustDo(t, game.Announce(Forehand, NewContract(GameGrand)))
+	if game.GrandHandOffer || game.Contract.GameType != GameGrand || !game.Contract.Hand {
+		t.Errorf("contract %v, offer %v, want Grand Hand", game.Contract, game.GrandHandOffer)
+	}
+	playOut(t, game)
+	record, err := NewGameRecord("g1", time.Now(), map[Player]string{Forehand: "a", Middlehand: "b", Rearhand: "c"}, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+	if replayed, err := record.Replay(nil); err != nil || replayed.Result == nil || replayed.Result.GameValue != game.Result.GameValue {
+		t.Errorf("Replay() = %v, error %v, want the Grand Hand result", replayed, err)
+	}
+
+	game = offer()
+	mustDo(t, game.Pass(Forehand))
+	if game.Declarer != nil || game.State != StateTrickPlaying || !game.Contract.GameType.IsRamsch() {
+		t.Errorf("declined: declarer %v, state %s, contract %v, want Ramsch", game.Declarer, game.State, game.Contract)
+	}
+	if restored := roundTrip(t, game); restored.AllPass != AllPassGrandHand || !restored.Contract.GameType.IsRamsch() {
+		t.Errorf("restored rule %s, contract %v, want Ramsch after the offer", restored.AllPass, restored.Contract)
+	}
+}
+
+func TestGameRecordReplay(t *testing.T) {
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	mustDo(t, game.Announce(Forehand, NewContract(GameGrand)))
+	playOut(t, game)
+
+	record, err := NewGameRecord("g1", time.Now(), map[Player]string{Forehand: "a", Middlehand: "b", Rearhand: "c"}, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+
+	observed := 0
+	replayed, err := record.Replay(func(*Game, Action) { observed++ })
+	if err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	if observed != len(game.Actions) {
+		t.Errorf("observed %d actions, want %d", observed, len(game.Actions))
+	}
+	if replayed.Result == nil || replayed.Result.Score != game.Result.Score {
+		t.Errorf("replayed result = %+v, want %+v", replayed.Result, game.Result)
+	}
+}
+
+func TestGameRecordReplayLegacyBid(t *testing.T) {
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	mustDo(t, game.Announce(Forehand, NewContract(GameGrand)))
+	playOut(t, game)
+
+	record, err := NewGameRecord("g1", time.Now(), map[Player]string{Forehand: "a", Middlehand: "b", Rearhand: "c"}, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+
+	// Older engines let Forehand play at 18 without a bid
+	record.Engine = forehandBidEngine - 1
+	record.Actions = append(record.Actions[:2:2], record.Actions[3:]...)
+	replayed, err := record.Replay(nil)
+	if err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	if replayed.Result == nil || replayed.Result.Score != game.Result.Score || replayed.Bidding.FinalBid != MinBid {
+		t.Errorf("replayed result = %+v, want %+v", replayed.Result, game.Result)
+	}
+
+	record.Engine = EngineVersion
+	if _, err := record.Replay(nil); err == nil {
+		t.Error("Replay() without the bid of Forehand succeeded")
+	}
+}
+
+func TestGameRecordCheckDeal(t *testing.T) {
+	deck := NewDeck()
+	info := deck.ShuffleSeed(42)
+	hands, skatCards, err := DealCards(deck)
+	if err != nil {
+		t.Fatalf("DealCards() error: %v", err)
+	}
+	game := NewGame()
+	mustDo(t, game.Deal(hands, skatCards))
+
+	record, err := NewGameRecord("g1", time.Now(), map[Player]string{Forehand: "a", Middlehand: "b", Rearhand: "c"}, game)
+	if err != nil {
+		t.Fatalf("NewGameRecord() error: %v", err)
+	}
+	if record.Engine != EngineVersion {
+		t.Errorf("Engine = %d, want %d", record.Engine, EngineVersion)
+	}
+	if err := record.CheckDeal(); err != ErrNotReproducible {
+		t.Errorf("CheckDeal() without seed = %v, want ErrNotReproducible", err)
+	}
+
+	record.Shuffle = info
+	if err := record.CheckDeal(); err != nil {
+		t.Errorf("CheckDeal() error: %v", err)
+	}
+	record.Shuffle.Seed = 43
+	if err := record.CheckDeal(); err == nil {
+		t.Error("CheckDeal() with wrong seed: expected error")
+	}
+
+	if _, err := NewDeck().ShuffleCrypto(); err != nil {
+		t.Errorf("ShuffleCrypto() error: %v", err)
+	}
+}
+
+// ============================================================================
+// Result Tests
+// ============================================================================
+
+func TestCalculateGameResultOverbid(t *testing.T) {
+	// With 1, game 2 x Diamonds (9) = 18, but the bid was 20
+	cards := []Card{NewCard(Clubs, Jack)}
+	trick := &Trick{Cards: []TrickCard{
+		{Card: NewCard(Hearts, Ace)}, {Card: NewCard(Hearts, Ten)}, {Card: NewCard(Spades, Ace)},
+	}}
+	// The declarer takes 62 points but still loses
+	result := CalculateGameResult(*NewContract(GameDiamonds), Forehand, cards, []*Trick{trick, trick}, nil, 20)
+
+	if !result.Overbid || result.DeclarerWon {
+		t.Errorf("Result = %+v, want overbid and lost", result)
+	}
+	if result.GameValue != 27 || result.Score != -54 {
+		t.Errorf("GameValue = %d, Score = %d, want 27 and -54", result.GameValue, result.Score)
+	}
+}
+
+func TestCalculateGameResultOuvert(t *testing.T) {
+	cards := []Card{NewCard(Clubs, Jack)}
+	trick := &Trick{Cards: []TrickCard{
+		{Card: NewCard(Hearts, Ace)}, {Card: NewCard(Hearts, Ten)}, {Card: NewCard(Spades, Ace)},
+	}}
+	tricks := func(n int) []*Trick {
+		all := make([]*Trick, n)
+		for i := range all {
+			all[i] = trick
+		}
+		return all
+	}
+
+	tests := []struct {
+		name     string
+		contract Contract
+		tricks   int
+		score    int
+	}{
+		// With 1, game, Hand, Schneider, announced, Schwarz, announced, Ouvert: 8 x 12
+		{"Clubs Ouvert won", Contract{GameType: GameClubs, Ouvert: true}, 10, 96},
+		{"Clubs Ouvert without Schwarz", Contract{GameType: GameClubs, Hand: true, Ouvert: true}, 9, -192},
+		{"Null Ouvert", Contract{GameType: GameNull, Ouvert: true}, 0, 46},
+		{"Null Hand Ouvert lost", Contract{GameType: GameNull, Hand: true, Ouvert: true}, 1, -118},
+	}
+	for _, tt := range tests {
+		result := CalculateGameResult(tt.contract, Forehand, cards, tricks(tt.tricks), nil, 18)
+		if result.Score != tt.score {
+			t.Errorf("%s: Score = %d, want %d", tt.name, result.Score, tt.score)
+		}
+	}
+}
+
+func TestCalculateGameResultSkatPoints(t *testing.T) {
+	cards := []Card{NewCard(Clubs, Jack)}
+	// 25 points per trick: the declarer takes 50 in tricks and wins with the skat
+	trick := &Trick{Cards: []TrickCard{
+		{Card: NewCard(Hearts, Ace)}, {Card: NewCard(Hearts, Ten)}, {Card: NewCard(Hearts, King)},
+	}}
+	skatCards := []Card{NewCard(Diamonds, Ace), NewCard(Diamonds, Ten)}
+	result := CalculateGameResult(*NewContract(GameClubs), Forehand, cards, []*Trick{trick, trick}, skatCards, 18)
+
+	if result.TrickPoints != 50 || result.SkatPoints != 21 || result.DeclarerPoints != 71 {
+		t.Errorf("points = %d + %d = %d, want 50 + 21 = 71", result.TrickPoints, result.SkatPoints, result.DeclarerPoints)
+	}
+	if !result.DeclarerWon {
+		t.Error("declarer with 71 points should win")
+	}
+
+	// The skat does not count in Null games
+	result = CalculateGameResult(*NewContract(GameNull), Forehand, cards, nil, skatCards, 18)
+	if result.SkatPoints != 0 {
+		t.Errorf("SkatPoints = %d in Null, want 0", result.SkatPoints)
+	}
+}
+
+func TestCalculateRamschResultSkat(t *testing.T) {
+	forehand, middlehand := Forehand, Middlehand
+	// Forehand takes 25 points, Middlehand 23 with the last trick, the skat has 15
+	tricks := []*Trick{
+		{Winner: &forehand, Cards: []TrickCard{{Card: NewCard(Hearts, Ace)}, {Card: NewCard(Hearts, Ten)}, {Card: NewCard(Hearts, King)}}},
+		{Winner: &middlehand, Cards: []TrickCard{{Card: NewCard(Spades, Ace)}, {Card: NewCard(Spades, Ten)}, {Card: NewCard(Clubs, Jack)}}},
+	}
+	skatCards := []Card{NewCard(Diamonds, Ace), NewCard(Diamonds, King)}
+
+	result := CalculateRamschResult(tricks, skatCards, RamschSkatLastTrick)
+	if result.SkatPlayer != Middlehand || result.SkatPoints != 15 || !slices.Equal(result.Losers, []Player{Middlehand}) || result.LoserScore != -2*38 {
+		t.Errorf("last trick: skat %d to %s, loser %s with %d, want 15 to Middlehand, loser Middlehand with -76",
+			result.SkatPoints, result.SkatPlayer, result.Losers, result.LoserScore)
+	}
+
+	result = CalculateRamschResult(tricks, skatCards, RamschSkatLoser)
+	if result.SkatPlayer != Forehand || !slices.Equal(result.Losers, []Player{Forehand}) || result.PlayerPoints[Forehand] != 40 || result.LoserScore != -2*40 {
+		t.Errorf("loser: skat to %s, losers %v with %d, want Forehand with 40 points", result.SkatPlayer, result.Losers, result.PlayerPoints[Forehand])
+	}
+
+	// Rearhand took no trick, so the Jungfrau doubles the fixed penalty
+	result.applyScoring(RamschScoringFixed)
+	if result.LoserScore != 2*RamschPenalty {
+		t.Errorf("fixed: loser score %d, want %d", result.LoserScore, 2*RamschPenalty)
+	}
+}
+
+func TestCalculateRamschResultTie(t *testing.T) {
+	trick := func(winner Player, cards ...Card) *Trick {
+		tc := make([]TrickCard, len(cards))
+		for i, c := range cards {
+			tc[i] = TrickCard{Card: c}
+		}
+		return &Trick{Winner: &winner, Cards: tc}
+	}
+
+	tests := []struct {
+		name       string
+		tricks     []*Trick
+		skat       []Card
+		rule       RamschSkat
+		wantLosers []Player
+		wantScore  int
+		wantSkat   Player
+	}{
+		{
+			// 25 points each with the skat of the last trick, Rearhand is Jungfrau
+			name: "two tied with the last trick",
+			tricks: []*Trick{
+				trick(Forehand, NewCard(Hearts, Ace), NewCard(Hearts, Ten), NewCard(Hearts, King)),
+				trick(Middlehand, NewCard(Spades, Ace), NewCard(Spades, Ten), NewCard(Clubs, Jack)),
+			},
+			skat:       []Card{NewCard(Diamonds, Seven), NewCard(Diamonds, Jack)},
+			rule:       RamschSkatLastTrick,
+			wantLosers: []Player{Forehand, Middlehand},
+			wantScore:  -2 * 25,
+			wantSkat:   Middlehand,
+		},
+		{
+			// 25 points each before the skat, which counts for both losers
+			name: "two tied with the skat to the loser",
+			tricks: []*Trick{
+				trick(Forehand, NewCard(Hearts, Ace), NewCard(Hearts, Ten), NewCard(Hearts, King)),
+				trick(Middlehand, NewCard(Spades, Ace), NewCard(Spades, Ten), NewCard(Spades, King)),
+			},
+			skat:       []Card{NewCard(Diamonds, Ace), NewCard(Diamonds, King)},
+			rule:       RamschSkatLoser,
+			wantLosers: []Player{Forehand, Middlehand},
+			wantScore:  -2 * 40,
+			wantSkat:   Forehand,
+		},
+		{
+			name: "three tied",
+			tricks: []*Trick{
+				trick(Forehand, NewCard(Clubs, Ten), NewCard(Clubs, Seven), NewCard(Clubs, Eight)),
+				trick(Middlehand, NewCard(Spades, Ten), NewCard(Spades, Seven), NewCard(Spades, Eight)),
+				trick(Rearhand, NewCard(Hearts, Ten), NewCard(Hearts, Seven), NewCard(Hearts, Eight)),
+			},
+			skat:       []Card{NewCard(Diamonds, Seven), NewCard(Diamonds, Eight)},
+			rule:       RamschSkatLastTrick,
+			wantLosers: []Player{Forehand, Middlehand, Rearhand},
+			wantScore:  -10,
+			wantSkat:   Rearhand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CalculateRamschResult(tt.tricks, tt.skat, tt.rule)
+			if !slices.Equal(result.Losers, tt.wantLosers) || result.LoserScore != tt.wantScore || result.SkatPlayer != tt.wantSkat {
+				t.Errorf("losers %v score %d skat to %s, want %v score %d skat to %s",
+					result.Losers, result.LoserScore, result.SkatPlayer, tt.wantLosers, tt.wantScore, tt.wantSkat)
+			}
+			for _, p := range AllPlayers {
+				if result.IsLoser(p) != slices.Contains(tt.wantLosers, p) {
+					t.Errorf("IsLoser(%s) = %v", p, result.IsLoser(p))
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateRamschResultDurchmarsch(t *testing.T) {
+	rearhand := Rearhand
+	// Rearhand takes all tricks with 78 card points and the skat with 12 (120 in a full game)
+	var tricks []*Trick
+	for _, suit := range []Suit{Clubs, Spades, Hearts, Diamonds} {
+		tricks = append(tricks, &Trick{Winner: &rearhand, Cards: []TrickCard{
+			{Card: NewCard(suit, Ace)}, {Card: NewCard(suit, King)}, {Card: NewCard(suit, Queen)},
+		}})
+	}
+	tricks = append(tricks, &Trick{Winner: &rearhand, Cards: []TrickCard{
+		{Card: NewCard(Clubs, Jack)}, {Card: NewCard(Spades, Jack)}, {Card: NewCard(Hearts, Jack)},
+	}})
+	skatCards := []Card{NewCard(Clubs, Ten), NewCard(Diamonds, Jack)}
+
+	tests := []struct {
+		name      string
+		rule      RamschSkat
+		scoring   RamschScoring
+		wantScore int
+	}{
+		{"points, skat to the last trick", RamschSkatLastTrick, RamschScoringPoints, 78 + 12},
+		{"points, skat to the loser", RamschSkatLoser, RamschScoringPoints, 78 + 12},
+		{"fixed", RamschSkatLastTrick, RamschScoringFixed, -RamschPenalty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CalculateRamschResult(tricks, skatCards, tt.rule)
+			result.applyScoring(tt.scoring)
+
+			if !result.Durchmarsch || result.DurchmarschPlayer == nil || *result.DurchmarschPlayer != Rearhand {
+				t.Fatalf("Durchmarsch = %v by %v, want Rearhand", result.Durchmarsch, result.DurchmarschPlayer)
+			}
+			if len(result.Losers) != 0 || result.LoserScore != 0 {
+				t.Errorf("losers %v with %d, want none", result.Losers, result.LoserScore)
+			}
+			if result.SkatPlayer != Rearhand {
+				t.Errorf("skat to %s, want Rearhand", result.SkatPlayer)
+			}
+			want := map[Player]int{Forehand: 0, Middlehand: 0, Rearhand: tt.wantScore}
+			for p, score := range want {
+				if got := result.Score(p); got != score {
+					t.Errorf("Score(%s) = %d, want %d", p, got, score)
+				}
+			}
+		})
+	}
+}
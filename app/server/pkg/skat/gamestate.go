@@ -14,7 +14,10 @@
 
 package skat
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // GameState represents the current state of a Skat game.
 type GameState int
@@ -158,30 +161,72 @@ func (c *Contract) nullValue() int {
 	return 23 // Null
 }
 
-// Multiplier calculates the multiplier based on modifiers (excluding matadors).
+// Multiplier calculates the multiplier based on modifiers (excluding matadors): game,
+// Hand, Schneider and Schneider announced, Schwarz and Schwarz announced, Ouvert.
 func (c *Contract) Multiplier() int {
 	if c.GameType.IsNull() {
-		return 1 // Null games don't use multipliers
+		return 1 // Null games have fixed values
 	}
 
+	implied := c.implied()
 	mult := 1 // Base multiplier
 
-	if c.Hand {
+	if implied.Hand {
 		mult++
 	}
-	if c.Schneider {
-		mult++
+	if implied.Schneider {
+		mult += 2
 	}
-	if c.Schwarz {
-		mult++
+	if implied.Schwarz {
+		mult += 2
 	}
-	if c.Ouvert {
+	if implied.Ouvert {
 		mult++
 	}
 
 	return mult
 }
 
+// implied returns the contract with the announcements implied by its modifiers: suit
+// and Grand Ouvert games are Hand games with Schwarz announced, and Schwarz announced
+// implies Schneider announced.
+func (c *Contract) implied() Contract {
+	implied := *c
+	if c.GameType.IsNull() || c.GameType.IsRamsch() {
+		return implied
+	}
+	if implied.Ouvert {
+		implied.Hand, implied.Schwarz = true, true
+	}
+	if implied.Schwarz {
+		implied.Schneider = true
+	}
+	return implied
+}
+
+// Validate returns an error for contracts that cannot be played: Schneider and Schwarz
+// announcements without Hand, suit and Grand Ouvert games without Hand, Schneider and
+// Schwarz, Null games with Schneider or Schwarz and Ramsch games with any modifier.
+func (c *Contract) Validate() error {
+	switch {
+	case c.GameType.IsRamsch():
+		if c.Hand || c.Ouvert || c.Schneider || c.Schwarz {
+			return fmt.Errorf("invalid contract %s: Ramsch has no modifiers", c.Code())
+		}
+	case c.GameType.IsNull():
+		if c.Schneider || c.Schwarz {
+			return fmt.Errorf("invalid contract %s: Null games cannot announce Schneider or Schwarz", c.Code())
+		}
+	case (c.Schneider || c.Schwarz || c.Ouvert) && !c.Hand:
+		return fmt.Errorf("invalid contract %s: announcements and Ouvert require a Hand game", c.Code())
+	case c.Schwarz && !c.Schneider:
+		return fmt.Errorf("invalid contract %s: Schwarz announced implies Schneider announced", c.Code())
+	case c.Ouvert && !c.Schwarz:
+		return fmt.Errorf("invalid contract %s: Ouvert implies Schneider and Schwarz announced", c.Code())
+	}
+	return nil
+}
+
 // Code returns the ISS protocol code for the contract.
 func (c *Contract) Code() string {
 	code := c.GameType.Code()
@@ -201,3 +246,32 @@ func (c *Contract) Code() string {
 
 	return code
 }
+
+// ContractFromCode parses a contract from its ISS protocol code (e.g. "GH", "NO").
+func ContractFromCode(code string) (*Contract, error) {
+	if code == "" {
+		return nil, errors.New("empty contract code")
+	}
+
+	gameType, err := GameTypeFromCode(code[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	contract := NewContract(gameType)
+	for _, modifier := range code[1:] {
+		switch modifier {
+		case 'H':
+			contract.Hand = true
+		case 'O':
+			contract.Ouvert = true
+		case 'S':
+			contract.Schneider = true
+		case 'Z':
+			contract.Schwarz = true
+		default:
+			return nil, fmt.Errorf("invalid contract modifier: %c", modifier)
+		}
+	}
+	return contract, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors of Kontra and Re announcements rejected by the rules.
+var (
+	ErrKontraNotAllowed = errors.New("kontra and re are not allowed")
+	ErrTooLate          = errors.New("too late to announce")
+	ErrBidTooLow        = errors.New("bid too low to announce kontra")
+)
+
+// KontraTiming is the last moment a defender may announce Kontra.
+type KontraTiming int
+
+const (
+	// KontraBeforeFirstCard allows Kontra until the declarer plays the first card
+	KontraBeforeFirstCard KontraTiming = iota
+	// KontraUntilSecondTrick allows Kontra until the second trick starts
+	KontraUntilSecondTrick
+)
+
+// String returns the code of the timing: "first-card" or "second-trick".
+func (t KontraTiming) String() string {
+	switch t {
+	case KontraBeforeFirstCard:
+		return "first-card"
+	case KontraUntilSecondTrick:
+		return "second-trick"
+	default:
+		return fmt.Sprintf("KontraTiming(%d)", int(t))
+	}
+}
+
+// MarshalText encodes the timing as its code.
+func (t KontraTiming) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes a timing code.
+func (t *KontraTiming) UnmarshalText(text []byte) error {
+	for _, timing := range []KontraTiming{KontraBeforeFirstCard, KontraUntilSecondTrick} {
+		if timing.String() == string(text) {
+			*t = timing
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid kontra timing: %s", text)
+}
+
+// KontraRules are the constraints of Kontra and Re of a rule profile. Re is allowed
+// until the declarer plays the next card after the Kontra.
+type KontraRules struct {
+	// Off disallows Kontra and Re
+	Off bool
+	// Timing is the last moment for Kontra
+	Timing KontraTiming
+	// MinBid is the value a defender must have bid or held to announce Kontra (0 = any)
+	MinBid int
+}
+
+// Check returns an error if the rules do not allow the Kontra or Re announcement of a
+// player now. The order of the announcements and the players are checked by the game.
+func (r KontraRules) Check(g *Game, action Action) error {
+	if action.Type != ActionKontra && action.Type != ActionRe {
+		return nil
+	}
+	if r.Off {
+		return ErrKontraNotAllowed
+	}
+	if action.Type == ActionRe {
+		if g.KontraBy != nil && g.playedSince(*g.Declarer, g.kontraAt) {
+			return fmt.Errorf("%w re: the declarer has played a card after the kontra", ErrTooLate)
+		}
+		return nil
+	}
+
+	switch r.Timing {
+	case KontraBeforeFirstCard:
+		if g.Declarer != nil && g.playedSince(*g.Declarer, 0) {
+			return fmt.Errorf("%w kontra: the declarer has played a card", ErrTooLate)
+		}
+	case KontraUntilSecondTrick:
+		if len(g.Tricks) > 0 {
+			return fmt.Errorf("%w kontra: the first trick is complete", ErrTooLate)
+		}
+	}
+	if r.MinBid > 0 && g.highestBid(action.Player) < r.MinBid {
+		return fmt.Errorf("%w: %s did not bid or hold %d", ErrBidTooLow, action.Player, r.MinBid)
+	}
+	return nil
+}
+
+// kontra processes a Kontra announcement of a defender.
+func (g *Game) kontra(player Player) error {
+	if err := g.checkState(StateTrickPlaying); err != nil {
+		return err
+	}
+	if g.Contract.GameType.IsRamsch() {
+		return errors.New("no kontra in ramsch games")
+	}
+	if *g.Declarer == player {
+		return errors.New("the declarer cannot announce kontra")
+	}
+	if g.KontraBy != nil {
+		return fmt.Errorf("%s has announced kontra already", *g.KontraBy)
+	}
+	g.KontraBy = &player
+	g.kontraAt = len(g.Actions)
+	return nil
+}
+
+// re processes the Re announcement of the declarer after a Kontra.
+func (g *Game) re(player Player) error {
+	if err := g.checkState(StateTrickPlaying); err != nil {
+		return err
+	}
+	if g.Declarer == nil || *g.Declarer != player {
+		return errors.New("only the declarer can announce re")
+	}
+	if g.KontraBy == nil {
+		return errors.New("re requires a kontra")
+	}
+	if g.ReAnnounced {
+		return errors.New("re has been announced already")
+	}
+	g.ReAnnounced = true
+	return nil
+}
+
+// playedSince returns true if the player played a card at or after the action index.
+func (g *Game) playedSince(player Player, index int) bool {
+	for _, action := range g.Actions[index:] {
+		if action.Type == ActionPlayCard && action.Player == player {
+			return true
+		}
+	}
+	return false
+}
+
+// highestBid returns the highest value a player bid or held (0 if none).
+func (g *Game) highestBid(player Player) int {
+	current, highest := 0, 0
+	for _, action := range g.Actions {
+		switch action.Type {
+		case ActionBid:
+			current = action.Value
+		case ActionHold:
+		default:
+			continue
+		}
+		if action.Player == player {
+			highest = current
+		}
+	}
+	return highest
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMisdeal is returned for deals that cannot start a game: hands without ten cards,
+// a skat without two cards or cards dealt twice, e.g. from a faulty import.
+var ErrMisdeal = errors.New("misdeal")
+
+// MaxRedeals is how often DealUntilValid redeals after misdeals before giving up.
+const MaxRedeals = 3
+
+// ValidateDeal returns an error wrapping ErrMisdeal unless every player has ten cards,
+// the skat two and all 32 cards are different.
+func ValidateDeal(hands map[Player]*Hand, skatCards *Hand) error {
+	for _, p := range AllPlayers {
+		if hands[p] == nil || hands[p].Size() != 10 {
+			return fmt.Errorf("%w: %s must have 10 cards", ErrMisdeal, p)
+		}
+	}
+	if skatCards == nil || skatCards.Size() != 2 {
+		return fmt.Errorf("%w: skat must have 2 cards", ErrMisdeal)
+	}
+
+	seen := make(map[Card]bool, 32)
+	cards := append([]Card(nil), skatCards.Cards...)
+	for _, p := range AllPlayers {
+		cards = append(cards, hands[p].Cards...)
+	}
+	for _, c := range cards {
+		if seen[c] {
+			return fmt.Errorf("%w: %s dealt twice", ErrMisdeal, c.Code())
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// DealUntilValid deals with the deal function until the deal is valid. A misdeal is
+// voided, reported to onMisdeal (e.g. to log it) and dealt again by the same dealer,
+// up to MaxRedeals times. The function gets the number of the attempt (0 for the
+// first deal), so seeded deals can derive a new seed for every redeal.
+func DealUntilValid(deal func(attempt int) (map[Player]*Hand, *Hand, error), onMisdeal func(attempt int, err error)) (map[Player]*Hand, *Hand, error) {
+	for attempt := 0; ; attempt++ {
+		hands, skatCards, err := deal(attempt)
+		if err == nil {
+			err = ValidateDeal(hands, skatCards)
+		}
+		if err == nil {
+			return hands, skatCards, nil
+		}
+		if !errors.Is(err, ErrMisdeal) || attempt == MaxRedeals {
+			return nil, nil, err
+		}
+		if onMisdeal != nil {
+			onMisdeal(attempt, err)
+		}
+	}
+}
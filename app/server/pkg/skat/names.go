@@ -0,0 +1,145 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"sort"
+	"sync"
+)
+
+// Languages with shipped names of suits, ranks, game types and player positions.
+const (
+	LangEnglish = "en"
+	LangGerman  = "de"
+	LangFrench  = "fr"
+	LangPolish  = "pl"
+)
+
+// names are the localized names by language, keyed by the English names (String) of
+// suits, ranks, game types and player positions. German is added from the GermanName
+// methods.
+var (
+	namesMu sync.RWMutex
+	names   = map[string]map[string]string{
+		LangFrench: {
+			"Clubs": "Trèfle", "Spades": "Pique", "Hearts": "Cœur", "Diamonds": "Carreau",
+			"Seven": "Sept", "Eight": "Huit", "Nine": "Neuf", "Queen": "Dame",
+			"King": "Roi", "Ten": "Dix", "Ace": "As", "Jack": "Valet",
+			"Grand": "Grand", "Null": "Null", "Ramsch": "Ramsch",
+			"Forehand": "Premier joueur", "Middlehand": "Deuxième joueur", "Rearhand": "Troisième joueur",
+		},
+		LangPolish: {
+			"Clubs": "Trefl", "Spades": "Pik", "Hearts": "Kier", "Diamonds": "Karo",
+			"Seven": "Siódemka", "Eight": "Ósemka", "Nine": "Dziewiątka", "Queen": "Dama",
+			"King": "Król", "Ten": "Dziesiątka", "Ace": "As", "Jack": "Walet",
+			"Grand": "Grand", "Null": "Null", "Ramsch": "Ramsz",
+			"Forehand": "Pierwsza ręka", "Middlehand": "Druga ręka", "Rearhand": "Trzecia ręka",
+		},
+	}
+)
+
+func init() {
+	german := make(map[string]string)
+	for _, s := range AllSuits {
+		german[s.String()] = s.GermanName()
+	}
+	for _, r := range AllRanks {
+		german[r.String()] = r.GermanName()
+	}
+	for _, g := range append(AllGameTypes, GameRamsch) {
+		german[g.String()] = g.GermanName()
+	}
+	for _, p := range AllPlayers {
+		german[p.String()] = p.GermanName()
+	}
+	names[LangGerman] = german
+}
+
+// AddNames adds localized names of a language, keyed by the English names of suits,
+// ranks, game types and player positions. Existing names of the language are replaced.
+func AddNames(lang string, localized map[string]string) {
+	namesMu.Lock()
+	defer namesMu.Unlock()
+	m := names[lang]
+	if m == nil {
+		m = make(map[string]string, len(localized))
+		names[lang] = m
+	}
+	for english, name := range localized {
+		m[english] = name
+	}
+}
+
+// Names returns a copy of the localized names of a language (nil if it has none).
+func Names(lang string) map[string]string {
+	namesMu.RLock()
+	defer namesMu.RUnlock()
+	m, ok := names[lang]
+	if !ok {
+		return nil
+	}
+	localized := make(map[string]string, len(m))
+	for english, name := range m {
+		localized[english] = name
+	}
+	return localized
+}
+
+// NameLanguages returns the languages with localized names in sorted order, English
+// included.
+func NameLanguages() []string {
+	namesMu.RLock()
+	defer namesMu.RUnlock()
+	langs := []string{LangEnglish}
+	for lang := range names {
+		if lang != LangEnglish {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// localize returns the name of an English name in a language (English if it has none).
+func localize(lang, english string) string {
+	namesMu.RLock()
+	defer namesMu.RUnlock()
+	if name, ok := names[lang][english]; ok {
+		return name
+	}
+	return english
+}
+
+// LocalizedName returns the name of the suit in a language (e.g. "fr"), falling back
+// to English.
+func (s Suit) LocalizedName(lang string) string {
+	return localize(lang, s.String())
+}
+
+// LocalizedName returns the name of the rank in a language, falling back to English.
+func (r Rank) LocalizedName(lang string) string {
+	return localize(lang, r.String())
+}
+
+// LocalizedName returns the name of the game type in a language, falling back to English.
+func (g GameType) LocalizedName(lang string) string {
+	return localize(lang, g.String())
+}
+
+// LocalizedName returns the name of the player position in a language, falling back
+// to English.
+func (p Player) LocalizedName(lang string) string {
+	return localize(lang, p.String())
+}
@@ -112,6 +112,22 @@ func (m MovePlayer) String() string {
 	}
 }
 
+// MovePlayerFromCode parses a move player from its ISS protocol code ("w", "0", "1" or "2").
+func MovePlayerFromCode(code string) (MovePlayer, error) {
+	switch code {
+	case "w":
+		return MoveWorld, nil
+	case "0":
+		return MoveForehand, nil
+	case "1":
+		return MoveMiddlehand, nil
+	case "2":
+		return MoveRearhand, nil
+	default:
+		return 0, fmt.Errorf("invalid move player code: %s", code)
+	}
+}
+
 // ToPlayer converts MovePlayer to Player (if applicable).
 func (m MovePlayer) ToPlayer() (Player, bool) {
 	switch m {
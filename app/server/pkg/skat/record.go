@@ -0,0 +1,175 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EngineVersion is the version of the rules engine. It is increased whenever a rule
+// change can change the outcome of recorded moves.
+const EngineVersion = 4
+
+// forehandBidEngine is the first EngineVersion in which Forehand must bid 18 or pass
+// after both other players passed. Older engines let Forehand play at 18 without a bid.
+const forehandBidEngine = 4
+
+// ErrNotReproducible is returned by CheckDeal for deals without a known seed.
+var ErrNotReproducible = errors.New("deal was not shuffled with a known seed")
+
+// GameRecord is the archived form of a game: the deal and all player actions.
+type GameRecord struct {
+	// ID is the unique game ID
+	ID string
+	// StartedAt is the time the game was dealt
+	StartedAt time.Time
+	// Players are the player names by position
+	Players map[Player]string
+	// Hands are the dealt hands
+	Hands map[Player]*Hand
+	// Skat is the dealt skat
+	Skat *Hand
+	// Actions are all player actions in order
+	Actions []Action
+	// Comments are the post-game comments of players and observers
+	Comments []Comment
+	// Shuffle describes how the deal was shuffled
+	Shuffle ShuffleInfo
+	// Engine is the EngineVersion the game was played with (0 if unknown)
+	Engine int
+	// AllPass is the rule what happens when all players pass
+	AllPass AllPass
+	// RamschSkat and RamschScoring are the Ramsch rules of the game
+	RamschSkat    RamschSkat
+	RamschScoring RamschScoring
+}
+
+// Comment is a comment on a game or on one of its actions.
+type Comment struct {
+	// Move is the 1-based index of the commented action (0 = the whole game)
+	Move int
+	// Author is the login of the author
+	Author string
+	// Text is the comment text
+	Text string
+	// Time is when the comment was written
+	Time time.Time
+}
+
+// NewGameRecord creates a record of a dealt game.
+func NewGameRecord(id string, startedAt time.Time, players map[Player]string, game *Game) (*GameRecord, error) {
+	if game.DealtHands == nil || game.DealtSkat == nil {
+		return nil, errors.New("game has not been dealt")
+	}
+
+	record := &GameRecord{
+		ID:        id,
+		StartedAt: startedAt,
+		Players:   make(map[Player]string),
+		Hands:     make(map[Player]*Hand),
+		Skat:      copyHand(game.DealtSkat),
+		Actions:   append([]Action(nil), game.Actions...),
+		Engine:    EngineVersion,
+		AllPass:   game.AllPass,
+
+		RamschSkat:    game.RamschSkat,
+		RamschScoring: game.RamschScoring,
+	}
+	for _, p := range AllPlayers {
+		record.Players[p] = players[p]
+		record.Hands[p] = copyHand(game.DealtHands[p])
+	}
+	return record, nil
+}
+
+// Replay replays the record and returns the resulting game.
+// If observe is not nil, it is called with the game state before each action.
+// Games whose tricks are complete are finished, so the result is available.
+func (r *GameRecord) Replay(observe func(game *Game, action Action)) (*Game, error) {
+	game := NewGame()
+	game.AllPass = r.AllPass
+	game.RamschSkat = r.RamschSkat
+	game.RamschScoring = r.RamschScoring
+	if err := game.Deal(r.Hands, r.Skat); err != nil {
+		return nil, err
+	}
+
+	for i, action := range r.Actions {
+		if r.Engine < forehandBidEngine {
+			if err := game.legacyBid(action); err != nil {
+				return nil, fmt.Errorf("action %d: %w", i+1, err)
+			}
+		}
+		if observe != nil {
+			observe(game, action)
+		}
+		if err := game.Apply(action); err != nil {
+			return nil, fmt.Errorf("action %d (%s by %s): %w", i+1, action.Type, action.Player, err)
+		}
+	}
+	return game, nil
+}
+
+// legacyBid applies the implicit 18 of Forehand that engines before forehandBidEngine
+// assumed when both other players passed, if Forehand continues without a bid.
+func (g *Game) legacyBid(action Action) error {
+	b := g.Bidding
+	if g.State != StateBidding || b.Phase != BidPhaseForehandDecides {
+		return nil
+	}
+	if action.Player == b.ActivePlayer && (action.Type == ActionBid || action.Type == ActionPass) {
+		return nil
+	}
+	return g.Apply(Action{Player: b.ActivePlayer, Type: ActionBid, Value: MinBid, Time: action.Time})
+}
+
+// CheckDeal verifies that the recorded seed reproduces the deal. It returns
+// ErrNotReproducible for deals without a seed (e.g. crypto shuffled deals).
+func (r *GameRecord) CheckDeal() error {
+	if r.Shuffle.Mode != ShuffleSeeded {
+		return ErrNotReproducible
+	}
+
+	deck := NewDeck()
+	deck.ShuffleSeed(r.Shuffle.Seed)
+	hands, skatCards, err := DealCards(deck)
+	if err != nil {
+		return err
+	}
+	for _, p := range AllPlayers {
+		if r.Hands[p] == nil || !sameCards(r.Hands[p], hands[p]) {
+			return fmt.Errorf("seed %d does not reproduce the %s hand", r.Shuffle.Seed, p)
+		}
+	}
+	if r.Skat == nil || !sameCards(r.Skat, skatCards) {
+		return fmt.Errorf("seed %d does not reproduce the skat", r.Shuffle.Seed)
+	}
+	return nil
+}
+
+// sameCards returns true if both hands hold the same cards in any order.
+func sameCards(a, b *Hand) bool {
+	if a.Size() != b.Size() {
+		return false
+	}
+	for _, card := range a.Cards {
+		if !b.Contains(card) {
+			return false
+		}
+	}
+	return true
+}
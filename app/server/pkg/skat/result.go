@@ -0,0 +1,391 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"fmt"
+	"slices"
+)
+
+// TricksPerGame is the number of tricks in a Skat game.
+const TricksPerGame = 10
+
+// TotalPoints is the total number of card points in a Skat deck.
+const TotalPoints = 120
+
+// GameResult represents the outcome of a completed game.
+type GameResult struct {
+	// Contract is the contract that was played
+	Contract Contract
+	// Declarer is the player who played the contract
+	Declarer Player
+	// DeclarerWon is true if the declarer won the game
+	DeclarerWon bool
+	// DeclarerPoints are the card points of the declarer: TrickPoints plus SkatPoints
+	DeclarerPoints int
+	// TrickPoints are the card points of the tricks won by the declarer
+	TrickPoints int
+	// SkatPoints are the card points of the skat, which count for the declarer (0 in
+	// Null games)
+	SkatPoints int
+	// DeclarerTricks is the number of tricks won by the declarer
+	DeclarerTricks int
+	// BidValue is the final bid value
+	BidValue int
+	// Matadors are the matadors "with" (positive) or "without" (negative)
+	Matadors int
+	// GameValue is the calculated game value, including Kontra and Re
+	GameValue int
+	// Overbid is true if the game value is lower than the bid value
+	Overbid bool
+	// Score is the final score (positive for win, negative for loss)
+	Score int
+	// Schneider is true if Schneider was achieved by either side
+	Schneider bool
+	// Schwarz is true if Schwarz was achieved by either side
+	Schwarz bool
+	// Kontra and Re are true if they were announced (each doubles the game value)
+	Kontra bool
+	Re     bool
+	// Multiplier is the factor of Kontra and Re in GameValue and Score (1, 2 or 4)
+	Multiplier int
+	// Forfeit is set if the game ended because a player abandoned it
+	Forfeit Forfeit
+}
+
+// CalculateGameResult calculates the result of a finished game.
+// declarerCards are the declarer's cards including the skat (used for the matadors),
+// skatCards the skat at the end of the game (the discards, or the dealt skat in Hand
+// games), whose points count for the declarer.
+// Suit and Grand Ouvert games are scored as Hand games with Schwarz announced, whatever
+// the contract says.
+func CalculateGameResult(contract Contract, declarer Player, declarerCards []Card, declarerTricks []*Trick, skatCards []Card, bidValue int) *GameResult {
+	contract = contract.implied()
+	result := &GameResult{
+		Contract:       contract,
+		Declarer:       declarer,
+		DeclarerTricks: len(declarerTricks),
+		BidValue:       bidValue,
+		Multiplier:     1,
+	}
+	for _, t := range declarerTricks {
+		result.TrickPoints += t.Points()
+	}
+	if !contract.GameType.IsNull() {
+		result.SkatPoints = cardPoints(skatCards)
+	}
+	result.DeclarerPoints = result.TrickPoints + result.SkatPoints
+
+	if contract.GameType.IsNull() {
+		result.DeclarerWon = result.DeclarerTricks == 0
+		result.GameValue = contract.BaseValue()
+	} else {
+		result.Schneider = result.DeclarerPoints >= 90 || result.DeclarerPoints <= 30
+		result.Schwarz = result.DeclarerTricks == TricksPerGame || result.DeclarerTricks == 0
+
+		result.DeclarerWon = result.DeclarerPoints >= 61
+		if contract.Schneider && result.DeclarerPoints < 90 {
+			result.DeclarerWon = false
+		}
+		if contract.Schwarz && result.DeclarerTricks < TricksPerGame {
+			result.DeclarerWon = false
+		}
+
+		result.Matadors = CountMatadors(declarerCards, contract.GameType)
+		result.GameValue = contract.GameType.BaseValue() * gameLevel(contract, result)
+	}
+
+	// Overbid: the game is lost with the lowest value reaching the bid
+	if result.GameValue < bidValue {
+		result.Overbid = true
+		result.DeclarerWon = false
+		base := contract.BaseValue()
+		result.GameValue = (bidValue + base - 1) / base * base
+	}
+
+	if result.DeclarerWon {
+		result.Score = result.GameValue
+	} else {
+		result.Score = -2 * result.GameValue
+	}
+	return result
+}
+
+// MaxGameValue returns the highest value the contract can reach with the declarer's
+// cards including the skat: the fixed value of a Null game, or a suit or Grand game
+// won Schneider and Schwarz.
+func MaxGameValue(contract Contract, declarerCards []Card) int {
+	contract = contract.implied()
+	if contract.GameType.IsNull() {
+		return contract.BaseValue()
+	}
+	best := &GameResult{Matadors: CountMatadors(declarerCards, contract.GameType), Schneider: true, Schwarz: true}
+	return contract.GameType.BaseValue() * gameLevel(contract, best)
+}
+
+// applyKontra doubles the game value and the score for Kontra, and again for Re.
+func (r *GameResult) applyKontra(re bool) {
+	factor := 2
+	r.Kontra, r.Re = true, re
+	if re {
+		factor = 4
+	}
+	r.Multiplier = factor
+	r.GameValue *= factor
+	r.Score *= factor
+}
+
+// BaseGameValue returns the game value without Kontra and Re.
+func (r *GameResult) BaseGameValue() int {
+	if r.Multiplier > 1 {
+		return r.GameValue / r.Multiplier
+	}
+	return r.GameValue
+}
+
+// gameLevel returns the multiplier of a suit or Grand game: matadors, the modifiers of
+// the contract and Schneider or Schwarz reached without announcement.
+func gameLevel(contract Contract, result *GameResult) int {
+	level := abs(result.Matadors) + contract.Multiplier()
+
+	if result.Schneider && !contract.Schneider {
+		level++
+	}
+	if result.Schwarz && !contract.Schwarz {
+		level++
+	}
+	return level
+}
+
+// cardPoints returns the card points of the cards.
+func cardPoints(cards []Card) int {
+	points := 0
+	for _, c := range cards {
+		points += c.Points()
+	}
+	return points
+}
+
+// abs returns the absolute value of an integer.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RamschSkat is the rule who gets the skat in Ramsch games.
+type RamschSkat int
+
+const (
+	// RamschSkatLastTrick gives the skat to the winner of the last trick
+	RamschSkatLastTrick RamschSkat = iota
+	// RamschSkatLoser gives the skat to the loser
+	RamschSkatLoser
+)
+
+// String returns the code of the rule: "last-trick" or "loser".
+func (r RamschSkat) String() string {
+	switch r {
+	case RamschSkatLastTrick:
+		return "last-trick"
+	case RamschSkatLoser:
+		return "loser"
+	default:
+		return fmt.Sprintf("RamschSkat(%d)", int(r))
+	}
+}
+
+// MarshalText encodes the rule as its code.
+func (r RamschSkat) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes a rule code.
+func (r *RamschSkat) UnmarshalText(text []byte) error {
+	for _, rule := range []RamschSkat{RamschSkatLastTrick, RamschSkatLoser} {
+		if rule.String() == string(text) {
+			*r = rule
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid Ramsch skat rule: %s", text)
+}
+
+// RamschScoring is the rule how the loser of a Ramsch game scores.
+type RamschScoring int
+
+const (
+	// RamschScoringPoints scores the card points of the loser as minus points
+	RamschScoringPoints RamschScoring = iota
+	// RamschScoringFixed scores a fixed penalty (RamschPenalty)
+	RamschScoringFixed
+)
+
+// RamschPenalty is the score of the Ramsch loser with RamschScoringFixed. Like the
+// card points it is doubled if a player took no trick. A Durchmarsch wins as much.
+const RamschPenalty = -20
+
+// String returns the code of the rule: "points" or "fixed".
+func (r RamschScoring) String() string {
+	switch r {
+	case RamschScoringPoints:
+		return "points"
+	case RamschScoringFixed:
+		return "fixed"
+	default:
+		return fmt.Sprintf("RamschScoring(%d)", int(r))
+	}
+}
+
+// MarshalText encodes the rule as its code.
+func (r RamschScoring) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes a rule code.
+func (r *RamschScoring) UnmarshalText(text []byte) error {
+	for _, rule := range []RamschScoring{RamschScoringPoints, RamschScoringFixed} {
+		if rule.String() == string(text) {
+			*r = rule
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid Ramsch scoring rule: %s", text)
+}
+
+// RamschResult represents the outcome of a Ramsch game.
+type RamschResult struct {
+	// Losers are the players who took the most points, in seat order. All players
+	// tied for the most points lose.
+	Losers []Player
+	// PlayerPoints are the card points taken by each player, including the skat
+	PlayerPoints map[Player]int
+	// SkatPlayer is the player the skat counts for
+	SkatPlayer Player
+	// SkatPoints are the card points of the skat
+	SkatPoints int
+	// LoserScore is the final score of each loser (negative)
+	LoserScore int
+	// Durchmarsch is true if one player won all tricks. The player wins the game and
+	// nobody loses.
+	Durchmarsch bool
+	// DurchmarschPlayer is the player who achieved Durchmarsch, if any
+	DurchmarschPlayer *Player
+	// DurchmarschScore is the score of the Durchmarsch player (positive)
+	DurchmarschScore int
+	// JungfrauPlayers are the players who took no trick
+	JungfrauPlayers []Player
+}
+
+// IsLoser returns true if the player lost the Ramsch game.
+func (r *RamschResult) IsLoser(p Player) bool {
+	return slices.Contains(r.Losers, p)
+}
+
+// Score returns the score of a player: the loser score for the losers, the
+// Durchmarsch score for the Durchmarsch player and 0 for everybody else.
+func (r *RamschResult) Score(p Player) int {
+	switch {
+	case r.IsLoser(p):
+		return r.LoserScore
+	case r.Durchmarsch && r.DurchmarschPlayer != nil && *r.DurchmarschPlayer == p:
+		return r.DurchmarschScore
+	default:
+		return 0
+	}
+}
+
+// CalculateRamschResult calculates the result of a Ramsch game from the completed tricks
+// and the skat, which counts for the winner of the last trick or the loser by the rule.
+// All players tied for the most points lose and score the same. A player who took all
+// tricks wins a Durchmarsch with all card points, including the skat.
+func CalculateRamschResult(tricks []*Trick, skatCards []Card, rule RamschSkat) *RamschResult {
+	result := &RamschResult{
+		PlayerPoints: make(map[Player]int),
+		SkatPoints:   cardPoints(skatCards),
+	}
+
+	trickCounts := make(map[Player]int)
+	for _, t := range tricks {
+		if t.Winner == nil {
+			continue
+		}
+		result.PlayerPoints[*t.Winner] += t.Points()
+		trickCounts[*t.Winner]++
+	}
+	if last := len(tricks) - 1; rule == RamschSkatLastTrick && last >= 0 && tricks[last].Winner != nil {
+		result.SkatPlayer = *tricks[last].Winner
+		result.PlayerPoints[result.SkatPlayer] += result.SkatPoints
+	}
+
+	maxPoints := -1
+	for _, p := range AllPlayers {
+		points := result.PlayerPoints[p]
+		if trickCounts[p] == len(tricks) && len(tricks) > 0 {
+			player := p
+			result.Durchmarsch = true
+			result.DurchmarschPlayer = &player
+		}
+		if trickCounts[p] == 0 {
+			result.JungfrauPlayers = append(result.JungfrauPlayers, p)
+		}
+		if points > maxPoints {
+			maxPoints = points
+		}
+	}
+	if result.Durchmarsch {
+		if rule == RamschSkatLoser {
+			result.SkatPlayer = *result.DurchmarschPlayer
+			result.PlayerPoints[result.SkatPlayer] += result.SkatPoints
+		}
+		result.DurchmarschScore = result.PlayerPoints[*result.DurchmarschPlayer]
+		return result
+	}
+	for _, p := range AllPlayers {
+		if result.PlayerPoints[p] == maxPoints {
+			result.Losers = append(result.Losers, p)
+		}
+	}
+
+	// Every loser scores the skat; with a tie it is counted for the first loser
+	if rule == RamschSkatLoser {
+		result.SkatPlayer = result.Losers[0]
+		result.PlayerPoints[result.SkatPlayer] += result.SkatPoints
+		maxPoints += result.SkatPoints
+	}
+
+	result.LoserScore = -maxPoints
+	if len(result.JungfrauPlayers) > 0 {
+		result.LoserScore *= 2
+	}
+	return result
+}
+
+// applyScoring replaces the score of the loser by the fixed penalty with
+// RamschScoringFixed; a Durchmarsch then wins the penalty.
+func (r *RamschResult) applyScoring(scoring RamschScoring) {
+	if scoring != RamschScoringFixed {
+		return
+	}
+	if r.Durchmarsch {
+		r.DurchmarschScore = -RamschPenalty
+		return
+	}
+	r.LoserScore = RamschPenalty
+	if len(r.JungfrauPlayers) > 0 {
+		r.LoserScore *= 2
+	}
+}
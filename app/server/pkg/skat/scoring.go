@@ -0,0 +1,61 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+// matadorTrumpRanks contains the trump suit ranks in matador order (after the Jacks).
+var matadorTrumpRanks = []Rank{Ace, Ten, King, Queen, Nine, Eight, Seven}
+
+// CountMatadors counts the matadors ("with" or "without") for the given cards.
+// Positive = "with" (consecutive top trumps held), negative = "without" (consecutive top trumps missing).
+// In Grand games only the Jacks count; Null and Ramsch games have no matadors (returns 0).
+func CountMatadors(cards []Card, gameType GameType) int {
+	if gameType.IsNull() || gameType.IsRamsch() {
+		return 0
+	}
+
+	has := func(card Card) bool {
+		for _, c := range cards {
+			if c == card {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Trump order from the top: CJ, SJ, HJ, DJ, then the trump suit (suit games only)
+	order := make([]Card, 0, 11)
+	for _, suit := range AllSuits {
+		order = append(order, NewCard(suit, Jack))
+	}
+	if trumpSuit, ok := gameType.TrumpSuit(); ok {
+		for _, rank := range matadorTrumpRanks {
+			order = append(order, NewCard(trumpSuit, rank))
+		}
+	}
+
+	with := has(order[0])
+	count := 0
+	for _, card := range order {
+		if has(card) != with {
+			break
+		}
+		count++
+	}
+
+	if with {
+		return count
+	}
+	return -count
+}
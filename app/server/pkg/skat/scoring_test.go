@@ -0,0 +1,73 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"testing"
+)
+
+// ============================================================================
+// Matador Tests
+// ============================================================================
+
+func TestCountMatadors(t *testing.T) {
+	tests := []struct {
+		name     string
+		cards    []Card
+		gameType GameType
+		expected int
+	}{
+		{
+			name:     "with 2 in Grand",
+			cards:    []Card{NewCard(Clubs, Jack), NewCard(Spades, Jack), NewCard(Diamonds, Jack)},
+			gameType: GameGrand,
+			expected: 2,
+		},
+		{
+			name:     "without 3 in Grand",
+			cards:    []Card{NewCard(Diamonds, Jack), NewCard(Hearts, Ace)},
+			gameType: GameGrand,
+			expected: -3,
+		},
+		{
+			name: "with 6 in Hearts",
+			cards: []Card{
+				NewCard(Clubs, Jack), NewCard(Spades, Jack), NewCard(Hearts, Jack),
+				NewCard(Diamonds, Jack), NewCard(Hearts, Ace), NewCard(Hearts, Ten),
+				NewCard(Hearts, Queen),
+			},
+			gameType: GameHearts,
+			expected: 6,
+		},
+		{
+			name:     "without 5 in Clubs",
+			cards:    []Card{NewCard(Clubs, Ten), NewCard(Hearts, Ace)},
+			gameType: GameClubs,
+			expected: -5,
+		},
+		{
+			name:     "no matadors in Null",
+			cards:    []Card{NewCard(Clubs, Jack)},
+			gameType: GameNull,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := CountMatadors(tt.cards, tt.gameType); got != tt.expected {
+			t.Errorf("%s: CountMatadors() = %d, want %d", tt.name, got, tt.expected)
+		}
+	}
+}
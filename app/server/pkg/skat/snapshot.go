@@ -0,0 +1,219 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format. Deserialize rejects other versions.
+const SnapshotVersion = 1
+
+// snapshot is the serialized form of a game. The state is not stored directly but
+// rebuilt from the deal and the actions, so a snapshot can never hold an inconsistent
+// game. The stored state and trick count only verify the result.
+type snapshot struct {
+	Version int              `json:"version"`
+	Engine  int              `json:"engine"`
+	State   string           `json:"state"`
+	Hands   []string         `json:"hands,omitempty"`
+	Skat    string           `json:"skat,omitempty"`
+	Actions []snapshotAction `json:"actions"`
+	Tricks  int              `json:"tricks"`
+	Clocks  []int64          `json:"clocks,omitempty"`
+	AllPass string           `json:"all_pass,omitempty"`
+	// RamschSkat and RamschScoring are stored if they differ from the defaults
+	RamschSkat    string `json:"ramsch_skat,omitempty"`
+	RamschScoring string `json:"ramsch_scoring,omitempty"`
+}
+
+// snapshotAction is a player action of a snapshot.
+type snapshotAction struct {
+	Player   int       `json:"player"`
+	Type     string    `json:"type"`
+	Value    int       `json:"value,omitempty"`
+	Cards    string    `json:"cards,omitempty"`
+	Contract string    `json:"contract,omitempty"`
+	Time     time.Time `json:"time,omitzero"`
+}
+
+// Serialize returns a snapshot of the game: the deal, all actions (bids, contract,
+// tricks) and the clocks. Deserialize restores the game from it, e.g. after a
+// reconnect, an adjournment or a crash, or on another server node.
+func (g *Game) Serialize() ([]byte, error) {
+	s := snapshot{
+		Version: SnapshotVersion,
+		Engine:  EngineVersion,
+		State:   g.State.String(),
+		Actions: make([]snapshotAction, 0, len(g.Actions)),
+		Tricks:  len(g.Tricks),
+	}
+	if g.DealtHands != nil && g.DealtSkat != nil {
+		for _, p := range AllPlayers {
+			s.Hands = append(s.Hands, g.DealtHands[p].Code())
+		}
+		s.Skat = g.DealtSkat.Code()
+	}
+	for _, action := range g.Actions {
+		a := snapshotAction{
+			Player: action.Player.Index(),
+			Type:   action.Type.String(),
+			Value:  action.Value,
+			Cards:  NewHandFromCards(action.Cards).Code(),
+			Time:   action.Time,
+		}
+		if action.Contract != nil {
+			a.Contract = action.Contract.Code()
+		}
+		s.Actions = append(s.Actions, a)
+	}
+	if g.AllPass != AllPassRamsch {
+		s.AllPass = g.AllPass.String()
+	}
+	if g.RamschSkat != RamschSkatLastTrick {
+		s.RamschSkat = g.RamschSkat.String()
+	}
+	if g.RamschScoring != RamschScoringPoints {
+		s.RamschScoring = g.RamschScoring.String()
+	}
+	if g.Clocks != nil {
+		for _, p := range AllPlayers {
+			s.Clocks = append(s.Clocks, g.Clocks[p].Milliseconds())
+		}
+	}
+	return json.Marshal(s)
+}
+
+// Deserialize restores a game from a snapshot created by Serialize.
+func Deserialize(data []byte) (*Game, error) {
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version: %d", s.Version)
+	}
+	if s.Engine > EngineVersion {
+		return nil, fmt.Errorf("snapshot of newer engine version %d", s.Engine)
+	}
+
+	game := NewGame()
+	if s.AllPass != "" {
+		if err := game.AllPass.UnmarshalText([]byte(s.AllPass)); err != nil {
+			return nil, err
+		}
+	}
+	if s.RamschSkat != "" {
+		if err := game.RamschSkat.UnmarshalText([]byte(s.RamschSkat)); err != nil {
+			return nil, err
+		}
+	}
+	if s.RamschScoring != "" {
+		if err := game.RamschScoring.UnmarshalText([]byte(s.RamschScoring)); err != nil {
+			return nil, err
+		}
+	}
+	if len(s.Hands) > 0 {
+		if len(s.Hands) != len(AllPlayers) {
+			return nil, fmt.Errorf("snapshot has %d hands", len(s.Hands))
+		}
+		hands := make(map[Player]*Hand)
+		for _, p := range AllPlayers {
+			hand, err := HandFromCode(s.Hands[p.Index()])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s hand: %w", p, err)
+			}
+			hands[p] = hand
+		}
+		skatCards, err := HandFromCode(s.Skat)
+		if err != nil {
+			return nil, fmt.Errorf("invalid skat: %w", err)
+		}
+		if err := game.Deal(hands, skatCards); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, a := range s.Actions {
+		action, err := a.action()
+		if err != nil {
+			return nil, fmt.Errorf("action %d: %w", i+1, err)
+		}
+		if s.Engine < forehandBidEngine {
+			if err := game.legacyBid(action); err != nil {
+				return nil, fmt.Errorf("action %d: %w", i+1, err)
+			}
+		}
+		if err := game.Apply(action); err != nil {
+			return nil, fmt.Errorf("action %d (%s by %s): %w", i+1, action.Type, action.Player, err)
+		}
+	}
+	// Older snapshots could be taken after the last trick, before the result
+	state := s.State
+	if state == StatePreliminaryGameEnd.String() {
+		state = StateGameOver.String()
+	}
+	if game.State.String() != state || len(game.Tricks) != s.Tricks {
+		return nil, fmt.Errorf("snapshot state %s with %d tricks, restored %s with %d tricks", s.State, s.Tricks, game.State, len(game.Tricks))
+	}
+
+	if len(s.Clocks) > 0 {
+		if len(s.Clocks) != len(AllPlayers) {
+			return nil, fmt.Errorf("snapshot has %d clocks", len(s.Clocks))
+		}
+		game.Clocks = make(map[Player]time.Duration)
+		for _, p := range AllPlayers {
+			game.Clocks[p] = time.Duration(s.Clocks[p.Index()]) * time.Millisecond
+		}
+	}
+	return game, nil
+}
+
+// action converts the snapshot action to a game action.
+func (a snapshotAction) action() (Action, error) {
+	player, err := PlayerFromIndex(a.Player)
+	if err != nil {
+		return Action{}, err
+	}
+	action := Action{Player: player, Value: a.Value, Time: a.Time}
+
+	found := false
+	for t := ActionBid; t <= ActionRedeal; t++ {
+		if t.String() == a.Type {
+			action.Type = t
+			found = true
+			break
+		}
+	}
+	if !found {
+		return action, fmt.Errorf("invalid action type: %s", a.Type)
+	}
+
+	if a.Cards != "" {
+		cards, err := HandFromCode(a.Cards)
+		if err != nil {
+			return action, err
+		}
+		action.Cards = cards.Cards
+	}
+	if a.Contract != "" {
+		if action.Contract, err = ContractFromCode(a.Contract); err != nil {
+			return action, err
+		}
+	}
+	return action, nil
+}
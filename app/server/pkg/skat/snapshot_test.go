@@ -0,0 +1,119 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skat
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTrip serializes and deserializes the game.
+func roundTrip(t *testing.T, game *Game) *Game {
+	t.Helper()
+
+	data, err := game.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error: %v", err)
+	}
+	restored, err := Deserialize(data)
+	if err != nil {
+		t.Fatalf("Deserialize() error: %v", err)
+	}
+	return restored
+}
+
+func TestSnapshotInProgress(t *testing.T) {
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Pass(Rearhand))
+	mustDo(t, game.Bid(Forehand, 18))
+	mustDo(t, game.PickUpSkat(Forehand))
+	mustDo(t, game.Discard(Forehand, NewCard(Diamonds, Seven), NewCard(Clubs, Seven)))
+	mustDo(t, game.Announce(Forehand, NewContract(GameClubs)))
+	for i := 0; i < 4; i++ {
+		player := game.ActivePlayer()
+		mustDo(t, game.PlayCard(*player, game.LegalMoves()[0]))
+	}
+	game.Clocks = map[Player]time.Duration{Forehand: 90 * time.Second, Middlehand: time.Minute, Rearhand: 1500 * time.Millisecond}
+
+	restored := roundTrip(t, game)
+	if restored.State != game.State || len(restored.Tricks) != 1 || len(restored.Trick.Cards) != 1 {
+		t.Fatalf("restored state %s with %d tricks, want %s with 1 trick and 1 card", restored.State, len(restored.Tricks), game.State)
+	}
+	if restored.Contract.Code() != "C" || restored.Skat.Code() != game.Skat.Code() {
+		t.Errorf("restored contract %s, skat %s", restored.Contract.Code(), restored.Skat.Code())
+	}
+	for _, p := range AllPlayers {
+		if restored.Hands[p].Code() != game.Hands[p].Code() {
+			t.Errorf("%s hand = %s, want %s", p, restored.Hands[p].Code(), game.Hands[p].Code())
+		}
+		if restored.Clocks[p] != game.Clocks[p] {
+			t.Errorf("%s clock = %v, want %v", p, restored.Clocks[p], game.Clocks[p])
+		}
+	}
+	if !restored.Actions[0].Time.Equal(game.Actions[0].Time) {
+		t.Errorf("action time = %v, want %v", restored.Actions[0].Time, game.Actions[0].Time)
+	}
+
+	// The restored game continues like the original
+	playOut(t, restored)
+	if !restored.Result.DeclarerWon {
+		t.Errorf("Result = %+v, want won", restored.Result)
+	}
+}
+
+func TestSnapshotFinished(t *testing.T) {
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	mustDo(t, game.Bid(Rearhand, 18))
+	mustDo(t, game.Pass(Forehand))
+	mustDo(t, game.Announce(Rearhand, NewContract(GameNull)))
+	playOut(t, game)
+
+	restored := roundTrip(t, game)
+	if restored.State != StateGameOver || restored.Result.Score != game.Result.Score {
+		t.Errorf("restored state %s with result %+v, want %s with %+v", restored.State, restored.Result, StateGameOver, game.Result)
+	}
+	if restored.Contract.Code() != "NH" || restored.Clocks != nil {
+		t.Errorf("restored contract %s, clocks %v, want NH without clocks", restored.Contract.Code(), restored.Clocks)
+	}
+
+	// A game before the deal has no cards
+	if restored := roundTrip(t, NewGame()); restored.State != StateGameStart {
+		t.Errorf("restored new game state = %s", restored.State)
+	}
+}
+
+func TestDeserializeInvalid(t *testing.T) {
+	game := newTestGame(t)
+	mustDo(t, game.Pass(Middlehand))
+	data, err := game.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize() error: %v", err)
+	}
+
+	tests := map[string]string{
+		"version": strings.Replace(string(data), `"version":1`, `"version":2`, 1),
+		"state":   strings.Replace(string(data), `"state":"Bidding"`, `"state":"TrickPlaying"`, 1),
+		"action":  strings.Replace(string(data), `"type":"Pass"`, `"type":"Bid"`, 1),
+		"json":    "{",
+	}
+	for name, snapshot := range tests {
+		if _, err := Deserialize([]byte(snapshot)); err == nil {
+			t.Errorf("%s: Deserialize() expected error", name)
+		}
+	}
+}
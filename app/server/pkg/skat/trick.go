@@ -33,6 +33,8 @@ type Trick struct {
 	Cards []TrickCard
 	// Winner is the winner of the trick (determined after all 3 cards played)
 	Winner *Player
+	// Claimed is true for a trick made of the remaining cards after an accepted claim
+	Claimed bool
 }
 
 // NewTrick creates a new empty trick.
@@ -76,29 +78,35 @@ func (t *Trick) LeadSuit() *Suit {
 	return &suit
 }
 
-// DetermineWinner determines the winner of a complete trick.
-func (t *Trick) DetermineWinner(gameType GameType) (Player, error) {
-	if !t.IsComplete() {
-		return 0, errors.New("cannot determine winner of incomplete trick")
+// WinningIndex returns the index of the card that wins the trick so far (-1 if no card
+// has been played). Every card is compared with the card winning before it, so the
+// earlier card wins unless a later one beats it.
+func (t *Trick) WinningIndex(gameType GameType) int {
+	if len(t.Cards) == 0 {
+		return -1
 	}
-
-	leadCard := t.Cards[0].Card
-	leadSuit := leadCard.Suit
-
-	winningIndex := 0
-	winningCard := t.Cards[0].Card
-
+	leadSuit := t.Cards[0].Card.Suit
+	winning := 0
 	for i := 1; i < len(t.Cards); i++ {
-		currentCard := t.Cards[i].Card
-		comparison := currentCard.CompareCards(winningCard, leadSuit, gameType)
-
-		if comparison > 0 {
-			winningIndex = i
-			winningCard = currentCard
+		if t.Cards[i].Card.Beats(t.Cards[winning].Card, leadSuit, gameType) {
+			winning = i
 		}
 	}
+	return winning
+}
+
+// IsWinning returns true if the card at index wins the trick so far, e.g. to highlight
+// it in a user interface.
+func (t *Trick) IsWinning(index int, gameType GameType) bool {
+	return index >= 0 && index == t.WinningIndex(gameType)
+}
 
-	return t.Cards[winningIndex].Player, nil
+// DetermineWinner determines the winner of a complete trick.
+func (t *Trick) DetermineWinner(gameType GameType) (Player, error) {
+	if !t.IsComplete() {
+		return 0, errors.New("cannot determine winner of incomplete trick")
+	}
+	return t.Cards[t.WinningIndex(gameType)].Player, nil
 }
 
 // Points calculates the total points in a trick.
@@ -273,6 +273,78 @@ func TestTrickWinnerNull_DifferentSuitDoesntWin(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// Off-Suit Discard Tests
+// ============================================================================
+
+func TestTrickWinnerOffSuitDiscards(t *testing.T) {
+	// Cards that neither trump nor follow the lead suit never take the trick, in any order
+	cards := NewDeck().Cards
+	for _, gameType := range []GameType{GameClubs, GameSpades, GameHearts, GameDiamonds, GameGrand, GameNull, GameRamsch} {
+		for _, lead := range cards {
+			if lead.IsTrump(gameType) {
+				continue
+			}
+			var discards []Card
+			for _, c := range cards {
+				if c.Suit != lead.Suit && !c.IsTrump(gameType) {
+					discards = append(discards, c)
+				}
+			}
+			for _, second := range discards {
+				if second.Beats(lead, lead.Suit, gameType) {
+					t.Fatalf("%s: %s beats %s led", gameType, second.Code(), lead.Code())
+				}
+				for _, third := range discards {
+					if third == second {
+						continue
+					}
+					// Two discards tie and the earlier one stays ahead
+					if third.CompareCards(second, lead.Suit, gameType) != 0 || third.Beats(second, lead.Suit, gameType) {
+						t.Fatalf("%s: %s compared with %s (%s led), want a tie", gameType, third.Code(), second.Code(), lead.Code())
+					}
+					trick := NewTrick(Rearhand)
+					trick.AddCard(lead, Rearhand)
+					trick.AddCard(second, Forehand)
+					trick.AddCard(third, Middlehand)
+					if winner, err := trick.DetermineWinner(gameType); err != nil || winner != Rearhand {
+						t.Fatalf("%s: %s wins %s, want %s (error %v)", gameType, winner, trick.Code(), Rearhand, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTrickWinningIndex(t *testing.T) {
+	trick := NewTrick(Forehand)
+	if trick.WinningIndex(GameHearts) != -1 || trick.IsWinning(0, GameHearts) {
+		t.Errorf("WinningIndex() of an empty trick = %d, want -1", trick.WinningIndex(GameHearts))
+	}
+
+	// The lead wins until a higher card follows; a discard does not take over
+	steps := []struct {
+		card    Card
+		player  Player
+		winning int
+	}{
+		{NewCard(Hearts, King), Forehand, 0},
+		{NewCard(Spades, Ace), Middlehand, 0},
+		{NewCard(Hearts, Ace), Rearhand, 2},
+	}
+	for _, step := range steps {
+		trick.AddCard(step.card, step.player)
+		if got := trick.WinningIndex(GameGrand); got != step.winning {
+			t.Errorf("after %s: WinningIndex() = %d, want %d", step.card.Code(), got, step.winning)
+		}
+		for i := range trick.Cards {
+			if trick.IsWinning(i, GameGrand) != (i == step.winning) {
+				t.Errorf("after %s: IsWinning(%d) = %v", step.card.Code(), i, !(i == step.winning))
+			}
+		}
+	}
+}
+
 // ============================================================================
 // Trick Points Tests
 // ============================================================================
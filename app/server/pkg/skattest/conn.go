@@ -0,0 +1,82 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skattest
+
+import (
+	"net"
+	"sync"
+)
+
+// bufferedConn is the server side of a pipe with a send buffer like a TCP connection:
+// writes return at once and are forwarded in order, so the server does not block
+// until the client reads (net.Pipe writes wait for the reader).
+type bufferedConn struct {
+	net.Conn
+	pending []byte
+	closed  bool
+	mu      sync.Mutex
+	cond    *sync.Cond
+}
+
+// newBufferedConn wraps the server side of a pipe.
+func newBufferedConn(conn net.Conn) *bufferedConn {
+	c := &bufferedConn{Conn: conn}
+	c.cond = sync.NewCond(&c.mu)
+	go c.flush()
+	return c
+}
+
+// Write adds the data to the send buffer.
+func (c *bufferedConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, net.ErrClosed
+	}
+	c.pending = append(c.pending, p...)
+	c.cond.Signal()
+	return len(p), nil
+}
+
+// flush forwards the send buffer to the pipe until the connection is closed.
+func (c *bufferedConn) flush() {
+	for {
+		c.mu.Lock()
+		for len(c.pending) == 0 && !c.closed {
+			c.cond.Wait()
+		}
+		if c.closed {
+			c.mu.Unlock()
+			return
+		}
+		data := c.pending
+		c.pending = nil
+		c.mu.Unlock()
+
+		if _, err := c.Conn.Write(data); err != nil {
+			c.Close()
+			return
+		}
+	}
+}
+
+// Close closes the connection; unsent data is discarded.
+func (c *bufferedConn) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.cond.Signal()
+	c.mu.Unlock()
+	return c.Conn.Close()
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skattest
+
+import (
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Script is a scripted opponent: an ai.AIPlayer making the configured moves. It keeps
+// no state, so one script may play several games.
+type Script struct {
+	// Bid is the highest value the opponent bids or holds (0 = always passes)
+	Bid int
+	// PickUp picks up the skat when the opponent declares
+	PickUp bool
+	// Contract is announced when the opponent declares (nil = Grand)
+	Contract *skat.Contract
+	// Discards are put into the skat after picking it up (if not in the hand: the last
+	// two cards sorted for the game)
+	Discards []skat.Card
+	// Cards are played in this order: the opponent plays the first card of the list
+	// that is still in its hand and allowed, otherwise its first allowed card
+	Cards []skat.Card
+}
+
+// Name returns the AI player name.
+func (s *Script) Name() string {
+	return "Script"
+}
+
+// DecideBid bids the next value up to Bid, or holds bids up to Bid.
+func (s *Script) DecideBid(hand *skat.Hand, ctx ai.BidContext) ai.BidDecision {
+	if !ctx.Bidding {
+		if ctx.CurrentBid <= s.Bid {
+			return ai.BidDecision{Action: ai.BidActionHold}
+		}
+		return ai.BidDecision{Action: ai.BidActionPass}
+	}
+	next := skat.MinBid
+	if ctx.CurrentBid > 0 {
+		next = skat.NextBid(ctx.CurrentBid)
+	}
+	if next > 0 && next <= s.Bid {
+		return ai.BidDecision{Action: ai.BidActionBid, Value: next}
+	}
+	return ai.BidDecision{Action: ai.BidActionPass}
+}
+
+// DecidePickUpSkat returns PickUp.
+func (s *Script) DecidePickUpSkat(hand *skat.Hand) bool {
+	return s.PickUp
+}
+
+// SelectDiscards returns the scripted discards if the hand holds them.
+func (s *Script) SelectDiscards(hand *skat.Hand, gameType skat.GameType) [2]skat.Card {
+	if len(s.Discards) == 2 && hand.Contains(s.Discards[0]) && hand.Contains(s.Discards[1]) && s.Discards[0] != s.Discards[1] {
+		return [2]skat.Card{s.Discards[0], s.Discards[1]}
+	}
+	sorted := make([]skat.Card, len(hand.Cards))
+	copy(sorted, hand.Cards)
+	skat.SortForGame(sorted, gameType)
+	return [2]skat.Card{sorted[len(sorted)-1], sorted[len(sorted)-2]}
+}
+
+// DecideAnnouncement announces the scripted contract.
+func (s *Script) DecideAnnouncement(hand *skat.Hand, bidValue int, handGame bool) *skat.Contract {
+	contract := skat.NewContract(skat.GameGrand)
+	if s.Contract != nil {
+		copied := *s.Contract
+		contract = &copied
+	}
+	contract.Hand = handGame
+	return contract
+}
+
+// SelectCard plays the first scripted card that is allowed.
+func (s *Script) SelectCard(hand *skat.Hand, ctx ai.PlayContext) skat.Card {
+	moves := hand.LegalMoves(ctx.Trick.LeadCard(), ctx.GameType)
+	for _, card := range s.Cards {
+		for _, move := range moves {
+			if move == card {
+				return card
+			}
+		}
+	}
+	return moves[0]
+}
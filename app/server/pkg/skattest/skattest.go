@@ -0,0 +1,216 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package skattest runs the FreeSkat protocol handler in memory for testing clients and
+// bots without a live server, like net/http/httptest for HTTP handlers. The connections
+// are net.Pipe pairs, the games are archived in a temporary directory.
+//
+// The client plays a configured deal against two opponents, by default AI players,
+// or Script opponents making scripted moves. It starts the game with "daily play" (the
+// deal of the day), so every login plays the deal once:
+//
+//	srv, err := skattest.NewServer(skattest.Config{
+//		Position: skat.Middlehand,
+//		Hands:    hands,
+//		Skat:     skatCards,
+//		Opponents: map[skat.Player]ai.AIPlayer{
+//			skat.Forehand: &skattest.Script{},         // always passes
+//			skat.Rearhand: &skattest.Script{Bid: 20},  // bids up to 20
+//		},
+//	})
+//	...
+//	defer srv.Close()
+//	c, err := srv.Dial()
+//	c.Login("alice", "secret")
+//	c.Send("daily play")
+//	err = c.Run(client.Handlers{ ... })
+//
+// Bots built with package botkit connect with botkit.Config{Dial: srv.Dial, Daily: true}.
+package skattest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/internal/archive"
+	"github.com/mkloubert/freeskat-server/internal/botpool"
+	"github.com/mkloubert/freeskat-server/internal/daily"
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/internal/session"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/events"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// ErrClosed is returned when connecting to a closed server.
+var ErrClosed = errors.New("server closed")
+
+// Config configures a server.
+type Config struct {
+	// Position is the position of the client in the games (default Forehand)
+	Position skat.Player
+	// Hands and Skat are the dealt cards (nil = a deal shuffled with Seed)
+	Hands map[skat.Player]*skat.Hand
+	Skat  *skat.Hand
+	// Seed shuffles the deal if Hands is not set and seeds the default opponents
+	Seed int64
+	// Opponents play the other two positions (nil = AI players of the beginner
+	// difficulty seeded with Seed)
+	Opponents map[skat.Player]ai.AIPlayer
+	// Dir is the directory of the game archive ("" = a temporary directory removed
+	// by Close)
+	Dir string
+}
+
+// Server is an in-memory server running the protocol handler.
+type Server struct {
+	handler  *protocol.Handler
+	sessions *session.Manager
+	games    *archive.Archive
+	stream   *events.Stream
+	// temp is the temporary archive directory removed by Close ("" = none)
+	temp   string
+	closed bool
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+}
+
+// NewServer creates a server playing the configured deal.
+func NewServer(config Config) (*Server, error) {
+	deal, err := newDeal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{}
+	dir := config.Dir
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "skattest-"); err != nil {
+			return nil, err
+		}
+		s.temp = dir
+	}
+	if s.games, err = archive.Open(dir); err != nil {
+		s.removeTemp()
+		return nil, err
+	}
+
+	s.sessions = session.NewManager(context.Background())
+	s.stream = events.NewStream()
+	s.handler = protocol.NewHandler(s.sessions, botpool.New("bot", 0, 0, ai.DifficultyBeginner))
+	s.handler.SetStream(s.stream)
+	s.handler.SetArchive(s.games)
+	s.handler.SetDaily(daily.NewFixedSchedule(*deal, time.UTC))
+	return s, nil
+}
+
+// newDeal creates the deal of the configuration.
+func newDeal(config Config) (*daily.Deal, error) {
+	deal := &daily.Deal{
+		Position:  config.Position,
+		Hands:     config.Hands,
+		Skat:      config.Skat,
+		Opponents: config.Opponents,
+	}
+	if deal.Hands == nil {
+		deck := skat.NewDeck()
+		deal.Shuffle = deck.ShuffleSeed(config.Seed)
+		hands, skatCards, err := skat.DealCards(deck)
+		if err != nil {
+			return nil, err
+		}
+		deal.Hands, deal.Skat = hands, skatCards
+	}
+	if deal.Opponents == nil {
+		deal.Opponents = make(map[skat.Player]ai.AIPlayer)
+		for _, p := range skat.AllPlayers {
+			if p != config.Position {
+				deal.Opponents[p] = ai.New(ai.DifficultyBeginner, rand.New(rand.NewSource(config.Seed+int64(p.Index()))))
+			}
+		}
+	}
+	return deal, nil
+}
+
+// Connect returns the client side of a new connection. The server sends the welcome
+// and version messages first.
+func (s *Server) Connect() (net.Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	serverConn, clientConn := net.Pipe()
+	sess := s.sessions.CreateSession(newBufferedConn(serverConn))
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { s.sessions.RemoveSession(sess.ID()) }()
+		s.handler.HandleConnection(sess)
+	}()
+	return clientConn, nil
+}
+
+// Dial returns a client on a new connection that has read the protocol version.
+func (s *Server) Dial() (*client.Client, error) {
+	conn, err := s.Connect()
+	if err != nil {
+		return nil, err
+	}
+	return client.Connect(conn)
+}
+
+// Games returns the archived games, oldest first. Games are archived when they end
+// or their client leaves.
+func (s *Server) Games() ([]*skat.GameRecord, error) {
+	return s.games.Records(archive.Filter{})
+}
+
+// Events returns the typed event stream of the games. Subscribe before the client
+// starts its game; the stream is closed by Close.
+func (s *Server) Events() *events.Stream {
+	return s.stream
+}
+
+// Close closes all connections, waits for their handlers and removes the temporary
+// archive directory.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.sessions.CloseAll()
+	s.wg.Wait()
+	s.stream.Close()
+	return s.removeTemp()
+}
+
+// removeTemp removes the temporary archive directory.
+func (s *Server) removeTemp() error {
+	if s.temp == "" {
+		return nil
+	}
+	return os.RemoveAll(s.temp)
+}
@@ -0,0 +1,373 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skattest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/internal/protocol"
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/botkit"
+	"github.com/mkloubert/freeskat-server/pkg/client"
+	"github.com/mkloubert/freeskat-server/pkg/events"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// mustHand parses a hand code.
+func mustHand(t *testing.T, code string) *skat.Hand {
+	t.Helper()
+	hand, err := skat.HandFromCode(code)
+	if err != nil {
+		t.Fatalf("HandFromCode(%q) error: %v", code, err)
+	}
+	return hand
+}
+
+// newServer creates a server closed at the end of the test.
+func newServer(t *testing.T, config Config) *Server {
+	t.Helper()
+	srv, err := NewServer(config)
+	if err != nil {
+		t.Fatalf("NewServer() error: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+func TestDialAndLogin(t *testing.T) {
+	srv := newServer(t, Config{Seed: 1})
+
+	c, err := srv.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer c.Close()
+	if c.Version() != protocol.ProtocolVersion {
+		t.Errorf("Version() = %d, want %d", c.Version(), protocol.ProtocolVersion)
+	}
+	if err := c.Login("alice", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+}
+
+func TestScriptedOpponents(t *testing.T) {
+	srv := newServer(t, Config{
+		Position: skat.Middlehand,
+		Hands: map[skat.Player]*skat.Hand{
+			skat.Forehand:   mustHand(t, "C7.C8.C9.S7.S8.S9.H7.H8.H9.D7"),
+			skat.Middlehand: mustHand(t, "CQ.CK.SQ.SK.HQ.HK.DQ.DK.D8.D9"),
+			skat.Rearhand:   mustHand(t, "CJ.SJ.HJ.DJ.CA.CT.SA.ST.HA.HT"),
+		},
+		Skat: mustHand(t, "DA.DT"),
+		Opponents: map[skat.Player]ai.AIPlayer{
+			skat.Forehand: &Script{Cards: []skat.Card{skat.NewCard(skat.Hearts, skat.Ace), skat.NewCard(skat.Spades, skat.Nine)}},
+			skat.Rearhand: &Script{Bid: 24},
+		},
+	})
+
+	c, err := srv.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	if err := c.Login("alice", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+	if err := c.Send("daily play"); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	var moves []string
+	var contract *skat.Contract
+	err = c.Run(client.Handlers{
+		Move: func(m client.Move) {
+			moves = append(moves, m.Player.String()+" "+m.Token)
+			switch {
+			case m.Player == skat.MoveWorld && len(moves) == 1:
+				// Middlehand passes the first bid
+				c.Pass(m.Table)
+			case m.Player == skat.MoveRearhand:
+				if announced, ok := m.Contract(); ok {
+					contract = announced
+				}
+			case m.Player == skat.MoveForehand:
+				if _, ok := m.Card(); ok {
+					c.Leave(m.Table)
+				}
+			}
+		},
+		Destroyed: func(string) { c.Close() },
+		Error:     func(text string) { t.Errorf("server error: %s", text) },
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if contract == nil || contract.GameType != skat.GameGrand || !contract.Hand {
+		t.Fatalf("rearhand announced %v, want Grand hand (moves %q)", contract, moves)
+	}
+	// Forehand does not hold the first scripted card
+	if last := moves[len(moves)-1]; last != "0 S9" {
+		t.Errorf("last move = %q, want the scripted 0 S9", last)
+	}
+	games, err := srv.Games()
+	if err != nil {
+		t.Fatalf("Games() error: %v", err)
+	}
+	if len(games) != 1 || games[0].Players[skat.Middlehand] != "alice" {
+		t.Errorf("Games() = %d games, want the game of alice", len(games))
+	}
+}
+
+func TestOuvertHandShown(t *testing.T) {
+	srv := newServer(t, Config{
+		Position: skat.Middlehand,
+		Hands: map[skat.Player]*skat.Hand{
+			skat.Forehand:   mustHand(t, "C7.C8.C9.S7.S8.S9.H7.H8.H9.D7"),
+			skat.Middlehand: mustHand(t, "CQ.CK.SQ.SK.HQ.HK.DQ.DK.D8.D9"),
+			skat.Rearhand:   mustHand(t, "CJ.SJ.HJ.DJ.CA.CT.SA.ST.HA.HT"),
+		},
+		Skat: mustHand(t, "DA.DT"),
+		Opponents: map[skat.Player]ai.AIPlayer{
+			skat.Forehand: &Script{},
+			skat.Rearhand: &Script{Bid: 24, Contract: &skat.Contract{GameType: skat.GameGrand, Ouvert: true}},
+		},
+	})
+
+	c, err := srv.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	if err := c.Login("alice", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+	if err := c.Send("daily play"); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	var seen []string
+	err = c.Run(client.Handlers{
+		Move: func(m client.Move) {
+			switch {
+			case m.Player == skat.MoveWorld && len(seen) == 0:
+				seen = append(seen, "deal")
+				c.Pass(m.Table)
+			case m.Player == skat.MoveForehand:
+				if _, ok := m.Card(); ok {
+					seen = append(seen, "card")
+					c.Leave(m.Table)
+				}
+			}
+		},
+		Open: func(o client.OpenHand) {
+			seen = append(seen, "open "+o.Player.String()+" "+o.Hand.Code())
+		},
+		Destroyed: func(string) { c.Close() },
+		Error:     func(text string) { t.Errorf("server error: %s", text) },
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	// The defenders see the open hand before Forehand leads
+	want := "open 2 " + mustHand(t, "CJ.SJ.HJ.DJ.CA.CT.SA.ST.HA.HT").Code()
+	if len(seen) != 3 || seen[1] != want || seen[2] != "card" {
+		t.Errorf("seen %q, want the deal, %q and the first card", seen, want)
+	}
+}
+
+func TestClaimAccepted(t *testing.T) {
+	srv := newServer(t, Config{
+		Position: skat.Rearhand,
+		Hands: map[skat.Player]*skat.Hand{
+			skat.Forehand:   mustHand(t, "C7.C8.C9.S7.S8.S9.H7.H8.H9.D7"),
+			skat.Middlehand: mustHand(t, "CQ.CK.SQ.SK.HQ.HK.DQ.DK.D8.D9"),
+			skat.Rearhand:   mustHand(t, "CJ.SJ.HJ.DJ.CA.CT.SA.ST.HA.HT"),
+		},
+		Skat: mustHand(t, "DA.DT"),
+		Opponents: map[skat.Player]ai.AIPlayer{
+			skat.Forehand:   &Script{},
+			skat.Middlehand: &Script{},
+		},
+	})
+
+	c, err := srv.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	if err := c.Login("alice", "secret"); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+	if err := c.Send("daily play"); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	var answers []string
+	var summary string
+	err = c.Run(client.Handlers{
+		Move: func(m client.Move) {
+			switch {
+			case m.Token == client.TokenAcceptClaim || m.Token == client.TokenRejectClaim:
+				answers = append(answers, m.Player.String()+" "+m.Token)
+			case m.Player == skat.MoveMiddlehand && m.Token == client.TokenPass:
+				c.Bid(m.Table, 18)
+			case m.Player == skat.MoveForehand && m.Token == client.TokenPass:
+				c.Announce(m.Table, &skat.Contract{GameType: skat.GameGrand, Hand: true})
+			case m.Player == skat.MoveMiddlehand:
+				// The declarer claims the rest as soon as it is its turn
+				if _, ok := m.Card(); ok {
+					c.Claim(m.Table)
+				}
+			}
+		},
+		End: func(e client.GameEnd) {
+			summary = e.Summary
+			c.Close()
+		},
+		Error: func(text string) { t.Errorf("server error: %s", text) },
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if want := []string{"0 AC", "1 AC"}; len(answers) != 2 || answers[0] != want[0] || answers[1] != want[1] {
+		t.Errorf("answers = %q, want %q", answers, want)
+	}
+	parsed, err := protocol.ParseGameSummary(summary)
+	if err != nil {
+		t.Fatalf("ParseGameSummary() error: %v", err)
+	}
+	if r := parsed.Result; !r.Won || !r.Schwarz || r.Tricks != skat.TricksPerGame {
+		t.Errorf("result = %+v, want won Schwarz", r)
+	}
+}
+
+func TestBotkitBot(t *testing.T) {
+	srv := newServer(t, Config{Position: skat.Rearhand, Seed: 7})
+
+	bot := botkit.New(botkit.Config{
+		Dial:     srv.Dial,
+		Login:    "mybot",
+		Password: "secret",
+		Daily:    true,
+		Logf:     func(string, ...any) {},
+	}, ai.NewRandomAI(rand.New(rand.NewSource(7))))
+	if err := bot.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if bot.Played() != 1 {
+		t.Errorf("Played() = %d, want 1", bot.Played())
+	}
+
+	games, err := srv.Games()
+	if err != nil {
+		t.Fatalf("Games() error: %v", err)
+	}
+	if len(games) != 1 || games[0].Players[skat.Rearhand] != "mybot" {
+		t.Fatalf("Games() = %d games, want the game of mybot", len(games))
+	}
+}
+
+func TestEvents(t *testing.T) {
+	srv := newServer(t, Config{Position: skat.Middlehand, Seed: 3})
+	ch, cancel := srv.Events().Subscribe(64)
+	defer cancel()
+
+	bot := botkit.New(botkit.Config{
+		Dial:     srv.Dial,
+		Login:    "mybot",
+		Password: "secret",
+		Daily:    true,
+		Logf:     func(string, ...any) {},
+	}, ai.NewRandomAI(rand.New(rand.NewSource(3))))
+	if err := bot.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	srv.Close()
+
+	var received []events.Event
+	for event := range ch {
+		received = append(received, event)
+	}
+	if len(received) < 5 {
+		t.Fatalf("received %d events, want at least 5", len(received))
+	}
+	for i, p := range skat.AllPlayers {
+		joined, ok := received[i].(events.PlayerJoined)
+		if !ok || joined.Position != p {
+			t.Errorf("event %d = %#v, want PlayerJoined of %s", i+1, received[i], p)
+		}
+	}
+	started, ok := received[3].(events.GameStarted)
+	if !ok || started.Players[skat.Middlehand] != "mybot" {
+		t.Errorf("event 4 = %#v, want GameStarted with mybot", received[3])
+	}
+
+	cards := 0
+	for _, event := range received[4 : len(received)-1] {
+		played, ok := event.(events.CardPlayed)
+		if !ok {
+			t.Fatalf("event = %#v, want CardPlayed", event)
+		}
+		cards++
+		if played.Trick != (cards+2)/3 {
+			t.Errorf("card %d: Trick = %d, want %d", cards, played.Trick, (cards+2)/3)
+		}
+	}
+	finished, ok := received[len(received)-1].(events.GameFinished)
+	if !ok || finished.Record == nil || finished.Record.ID != started.Game {
+		t.Fatalf("last event = %#v, want GameFinished of game %s", received[len(received)-1], started.Game)
+	}
+	if finished.Result == nil && finished.Ramsch == nil {
+		t.Error("GameFinished without a result")
+	}
+	for _, action := range finished.Record.Actions {
+		if action.Type == skat.ActionPlayCard {
+			cards--
+		}
+	}
+	if cards != 0 {
+		t.Errorf("CardPlayed events differ from the recorded card plays by %d", cards)
+	}
+}
+
+func TestDialClosed(t *testing.T) {
+	srv := newServer(t, Config{})
+	srv.Close()
+	if _, err := srv.Dial(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Dial() error = %v, want ErrClosed", err)
+	}
+}
+
+func TestScriptBidding(t *testing.T) {
+	script := &Script{Bid: 20}
+	tests := []struct {
+		ctx  ai.BidContext
+		want ai.BidDecision
+	}{
+		{ai.BidContext{Bidding: true}, ai.BidDecision{Action: ai.BidActionBid, Value: 18}},
+		{ai.BidContext{Bidding: true, CurrentBid: 18}, ai.BidDecision{Action: ai.BidActionBid, Value: 20}},
+		{ai.BidContext{Bidding: true, CurrentBid: 20}, ai.BidDecision{Action: ai.BidActionPass}},
+		{ai.BidContext{CurrentBid: 20}, ai.BidDecision{Action: ai.BidActionHold}},
+		{ai.BidContext{CurrentBid: 22}, ai.BidDecision{Action: ai.BidActionPass}},
+	}
+	for _, tt := range tests {
+		if got := script.DecideBid(skat.NewHand(), tt.ctx); got != tt.want {
+			t.Errorf("DecideBid(%+v) = %+v, want %+v", tt.ctx, got, tt.want)
+		}
+	}
+}
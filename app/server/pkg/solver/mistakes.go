@@ -0,0 +1,121 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solver
+
+import (
+	"fmt"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Mistake is a card play that gave away points against best (double-dummy) play.
+type Mistake struct {
+	// Move is the 1-based index of the action in the game record
+	Move int
+	// Player is the player who played the card
+	Player skat.Player
+	// Card is the played card
+	Card skat.Card
+	// Best is a card with the best value
+	Best skat.Card
+	// Loss are the card points the player's side lost by the move (0 in Null games)
+	Loss int
+	// GameLost is true if the move decided a Null game against the player's side
+	GameLost bool
+}
+
+// String returns the mistake as one line, e.g. "move 14: Middlehand played HT, SA was better (21 card points)".
+func (m Mistake) String() string {
+	return fmt.Sprintf("move %d: %s played %s, %s was better (%s)", m.Move, m.Player, m.Card.Code(), m.Best.Code(), m.lossText())
+}
+
+// lossText describes the loss of the mistake.
+func (m Mistake) lossText() string {
+	if m.GameLost {
+		return "loses the game"
+	}
+	return fmt.Sprintf("%d card points", m.Loss)
+}
+
+// Analyze replays a game record and returns the card plays that lost at least minLoss
+// card points against best play. In Null games every card play that turns a won game
+// into a lost one (or a lost game into a won one for the defenders) is a mistake.
+//
+// Every decision is judged with all hands visible, so a move can be a mistake
+// although the player could not know better. Bidding and discards are not analyzed.
+// Ramsch games return ErrNotSupported.
+func Analyze(record *skat.GameRecord, minLoss int) ([]Mistake, error) {
+	var (
+		solver   *Solver
+		mistakes []Mistake
+		failure  error
+	)
+	move := 0
+	_, err := record.Replay(func(game *skat.Game, action skat.Action) {
+		move++
+		if failure != nil || action.Type != skat.ActionPlayCard {
+			return
+		}
+		if game.Contract.GameType.IsRamsch() {
+			failure = ErrNotSupported
+			return
+		}
+		if solver == nil {
+			if solver, failure = New(game); failure != nil {
+				return
+			}
+		}
+
+		played, best, err := solver.Compare(game, action.Cards[0])
+		if err != nil {
+			failure = err
+			return
+		}
+
+		// The declarer maximizes the value, the defenders minimize it
+		mistake := Mistake{Move: move, Player: action.Player, Card: played.Card, Best: best.Card, Loss: best.Value - played.Value}
+		if action.Player != solver.declarer {
+			mistake.Loss = -mistake.Loss
+		}
+		if game.Contract.GameType.IsNull() {
+			// The values are 0 (won) and -1 (lost)
+			mistake.GameLost, mistake.Loss = mistake.Loss > 0, 0
+		}
+		if mistake.GameLost || mistake.Loss >= max(minLoss, 1) {
+			mistakes = append(mistakes, mistake)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	return mistakes, nil
+}
+
+// Report returns the mistakes of one player as text lines, or a single line if there are none.
+func Report(mistakes []Mistake, player skat.Player) []string {
+	var lines []string
+	for _, m := range mistakes {
+		if m.Player == player {
+			lines = append(lines, m.String())
+		}
+	}
+	if len(lines) == 0 {
+		return []string{fmt.Sprintf("no clear mistakes of %s", player)}
+	}
+	return lines
+}
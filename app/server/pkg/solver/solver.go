@@ -0,0 +1,418 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package solver provides a double-dummy solver for the trick playing of a game
+// and a post-game mistake analysis based on it.
+//
+// Double dummy means that all hands are known: the solver finds the best play
+// for both sides as if every player could see all cards.
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// ErrNotSupported is returned for games the solver cannot evaluate (Ramsch, no contract).
+var ErrNotSupported = errors.New("game cannot be solved")
+
+// nullLost is the value of a Null game the declarer loses.
+const nullLost = -1
+
+// trumpGroup is the follow group of the trump cards.
+const trumpGroup = 4
+
+// bound is a transposition table entry: the value of a position lies in [lower, upper].
+type bound struct {
+	lower, upper int
+	// move is the best lead found so far (-1 if unknown), tried first when searching again
+	move int
+}
+
+// Solver evaluates the positions of one game. Positions are cached, so a solver
+// should be reused for all positions of the same game.
+type Solver struct {
+	gameType skat.GameType
+	declarer skat.Player
+
+	// owner is the player holding each card at the start of the trick playing (by card index)
+	owner [32]skat.Player
+	// group is the follow group of each card (suit index or trumpGroup)
+	group [32]int
+	// strength orders the cards of a group (higher wins)
+	strength [32]int
+	// points are the card points of each card
+	points [32]int
+	// groupMask are the cards of each follow group
+	groupMask [5]uint32
+	// ordered are all card indices in search order: trumps and strong cards first
+	ordered [32]int
+
+	table map[uint64]bound
+}
+
+// New creates a solver for a game in trick playing (Null, suit or Grand).
+func New(game *skat.Game) (*Solver, error) {
+	if game.Contract == nil || game.Declarer == nil || game.Contract.GameType.IsRamsch() {
+		return nil, ErrNotSupported
+	}
+	if game.State != skat.StateTrickPlaying && game.State != skat.StateGameOver {
+		return nil, fmt.Errorf("game is not in trick playing (current: %s)", game.State)
+	}
+
+	s := &Solver{
+		gameType: game.Contract.GameType,
+		declarer: *game.Declarer,
+		table:    make(map[uint64]bound),
+	}
+	for i := range 32 {
+		card := cardAt(i)
+		s.points[i] = card.Points()
+		if card.IsTrump(s.gameType) {
+			s.group[i] = trumpGroup
+			s.strength[i] = card.TrumpOrder(s.gameType)
+		} else {
+			s.group[i] = int(card.Suit)
+			s.strength[i] = card.SuitOrder(s.gameType)
+		}
+		s.groupMask[s.group[i]] |= 1 << i
+		s.ordered[i] = i
+	}
+	sort.Slice(s.ordered[:], func(i, j int) bool {
+		a, b := s.ordered[i], s.ordered[j]
+		if s.group[a] != s.group[b] {
+			return s.group[a] > s.group[b]
+		}
+		return s.strength[a] > s.strength[b]
+	})
+
+	for _, p := range skat.AllPlayers {
+		for _, c := range game.Hands[p].Cards {
+			s.owner[index(c)] = p
+		}
+	}
+	for _, t := range game.Tricks {
+		for _, tc := range t.Cards {
+			s.owner[index(tc.Card)] = tc.Player
+		}
+	}
+	if game.Trick != nil {
+		for _, tc := range game.Trick.Cards {
+			s.owner[index(tc.Card)] = tc.Player
+		}
+	}
+	return s, nil
+}
+
+// Value returns the value of the position with best play of both sides: the card
+// points the declarer takes in the remaining tricks including the current one, or for
+// Null games 0 if the declarer still wins and -1 if the game is lost.
+func (s *Solver) Value(game *skat.Game) (int, error) {
+	p, err := s.position(game)
+	if err != nil {
+		return 0, err
+	}
+	value, _ := s.play(p.hands, p.leader, p.played, p.n, -1000, 1000, -1)
+	return value, nil
+}
+
+// ClaimHolds returns true if the declarer wins the rest of the game with best play of
+// both sides, as claimed or conceded: all remaining card points, or in Null games no
+// further trick.
+func ClaimHolds(game *skat.Game) (bool, error) {
+	s, err := New(game)
+	if err != nil {
+		return false, err
+	}
+	value, err := s.Value(game)
+	if err != nil {
+		return false, err
+	}
+	if s.gameType.IsNull() {
+		return value == 0, nil
+	}
+	remaining := game.Trick.Points()
+	for _, p := range skat.AllPlayers {
+		for _, c := range game.Hands[p].Cards {
+			remaining += c.Points()
+		}
+	}
+	return value == remaining, nil
+}
+
+// MoveValue is the value of a card of the active player.
+type MoveValue struct {
+	// Card is the card to play
+	Card skat.Card
+	// Value is the position value after playing the card (see Solver.Value)
+	Value int
+}
+
+// Compare returns the value of playing the card and a card with the best value for the
+// active player (the highest for the declarer, the lowest for the defenders).
+func (s *Solver) Compare(game *skat.Game, card skat.Card) (played, best MoveValue, err error) {
+	p, err := s.position(game)
+	if err != nil {
+		return played, best, err
+	}
+	player := (p.leader + p.n) % 3
+	c := index(card)
+	if s.legal(p.hands[player], p.played, p.n)&(1<<c) == 0 {
+		return played, best, fmt.Errorf("illegal card play: %s", card.Code())
+	}
+
+	hands, trick := p.hands, p.played
+	hands[player] &^= 1 << c
+	trick[p.n] = c
+	played = MoveValue{Card: card, Value: s.after(hands, p.leader, trick, p.n+1, -1000, 1000)}
+
+	// Searching the played card first makes the other cards cheap to refute
+	value, bestCard := s.play(p.hands, p.leader, p.played, p.n, -1000, 1000, c)
+	best = MoveValue{Card: cardAt(bestCard), Value: value}
+	if best.Value == played.Value {
+		best.Card = card
+	}
+	return played, best, nil
+}
+
+// position is a position of the search: the hands, the leader and the first n cards of the trick.
+type position struct {
+	hands  [3]uint32
+	leader int
+	played [3]int
+	n      int
+}
+
+// position converts the game to a search position.
+func (s *Solver) position(game *skat.Game) (position, error) {
+	var p position
+	if game.State != skat.StateTrickPlaying {
+		return p, fmt.Errorf("game is not in trick playing (current: %s)", game.State)
+	}
+	if !s.matches(game) {
+		return p, errors.New("game does not match the solver")
+	}
+
+	for _, player := range skat.AllPlayers {
+		p.hands[player.Index()] = mask(game.Hands[player].Cards)
+	}
+	for i, tc := range game.Trick.Cards {
+		p.played[i] = index(tc.Card)
+	}
+	p.leader = game.Trick.Forehand.Index()
+	p.n = len(game.Trick.Cards)
+	return p, nil
+}
+
+// matches returns true if the game is the game the solver was created for.
+func (s *Solver) matches(game *skat.Game) bool {
+	if game.Contract == nil || game.Contract.GameType != s.gameType || game.Declarer == nil || *game.Declarer != s.declarer {
+		return false
+	}
+	for _, p := range skat.AllPlayers {
+		for _, c := range game.Hands[p].Cards {
+			if s.owner[index(c)] != p {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// after returns the value after n cards of the current trick have been played.
+func (s *Solver) after(hands [3]uint32, leader int, played [3]int, n int, alpha, beta int) int {
+	if n < 3 {
+		value, _ := s.play(hands, leader, played, n, alpha, beta, -1)
+		return value
+	}
+
+	winner := s.winner(leader, played)
+	points := 0
+	if winner == s.declarer.Index() {
+		if s.gameType.IsNull() {
+			return nullLost
+		}
+		points = s.points[played[0]] + s.points[played[1]] + s.points[played[2]]
+	}
+	return points + s.search(hands, winner, alpha-points, beta-points)
+}
+
+// search returns the value of the remaining tricks, led by leader.
+func (s *Solver) search(hands [3]uint32, leader int, alpha, beta int) int {
+	if hands[0]|hands[1]|hands[2] == 0 {
+		return 0
+	}
+
+	// The owners of the cards are fixed, so the remaining cards identify the position
+	key := uint64(hands[0]|hands[1]|hands[2]) | uint64(leader)<<32
+	entry, ok := s.table[key]
+	if !ok {
+		entry = bound{lower: -1000, upper: 1000, move: -1}
+	}
+	if entry.lower >= beta {
+		return entry.lower
+	}
+	if entry.upper <= alpha || entry.lower == entry.upper {
+		return entry.upper
+	}
+	a, b := max(alpha, entry.lower), min(beta, entry.upper)
+
+	value, move := s.play(hands, leader, [3]int{}, 0, a, b, entry.move)
+	entry.move = move
+	switch {
+	case value <= a:
+		entry.upper = value
+	case value >= b:
+		entry.lower = value
+	default:
+		entry.lower, entry.upper = value, value
+	}
+	s.table[key] = entry
+	return value
+}
+
+// play tries every legal card of the next player of the trick (alpha-beta search),
+// starting with the card first if it is legal. It returns the value and the best card.
+func (s *Solver) play(hands [3]uint32, leader int, played [3]int, n int, alpha, beta int, first int) (int, int) {
+	player := (leader + n) % 3
+	maximize := player == s.declarer.Index()
+
+	best, bestCard := 1000, -1
+	if maximize {
+		best = -1000
+	}
+	legal := s.reduce(s.legal(hands[player], played, n), hands, played, n)
+	if first >= 0 && legal&(1<<first) == 0 {
+		first = -1
+	}
+	for i := -1; i < len(s.ordered); i++ {
+		c := first
+		if i >= 0 {
+			c = s.ordered[i]
+			if legal&(1<<c) == 0 || c == first {
+				continue
+			}
+		} else if c < 0 {
+			continue
+		}
+
+		hands[player] &^= 1 << c
+		played[n] = c
+		value := s.after(hands, leader, played, n+1, alpha, beta)
+		hands[player] |= 1 << c
+
+		if maximize && value > best || !maximize && value < best {
+			best, bestCard = value, c
+		}
+		if maximize {
+			alpha = max(alpha, value)
+		} else {
+			beta = min(beta, value)
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return best, bestCard
+}
+
+// legal returns the cards of the hand that may be played on the first n cards of the trick.
+func (s *Solver) legal(hand uint32, played [3]int, n int) uint32 {
+	if n == 0 {
+		return hand
+	}
+	if follow := hand & s.groupMask[s.group[played[0]]]; follow != 0 {
+		return follow
+	}
+	return hand
+}
+
+// reduce removes cards that are equivalent to a stronger card of the same hand:
+// same group and points (any points in Null), and no unplayed card of another
+// player in between.
+func (s *Solver) reduce(legal uint32, hands [3]uint32, played [3]int, n int) uint32 {
+	var others uint32
+	for i := range n {
+		others |= 1 << played[i]
+	}
+	all := hands[0] | hands[1] | hands[2] | others
+
+	result := legal
+	for m := legal; m != 0; m &= m - 1 {
+		c := bits.TrailingZeros32(m)
+		for o := legal &^ (1 << c); o != 0; o &= o - 1 {
+			d := bits.TrailingZeros32(o)
+			if s.group[d] != s.group[c] || !s.gameType.IsNull() && s.points[d] != s.points[c] || s.strength[d] <= s.strength[c] || result&(1<<d) == 0 {
+				continue
+			}
+			if !s.between(all&^legal, c, d) {
+				result &^= 1 << c
+				break
+			}
+		}
+	}
+	return result
+}
+
+// between returns true if one of the cards lies strictly between the cards low and high of a group.
+func (s *Solver) between(cards uint32, low, high int) bool {
+	for m := cards & s.groupMask[s.group[low]]; m != 0; m &= m - 1 {
+		c := bits.TrailingZeros32(m)
+		if s.strength[c] > s.strength[low] && s.strength[c] < s.strength[high] {
+			return true
+		}
+	}
+	return false
+}
+
+// winner returns the position winning a complete trick. The first card wins unless
+// a later card of the led group or a trump beats it.
+func (s *Solver) winner(leader int, played [3]int) int {
+	best := 0
+	for i := 1; i < 3; i++ {
+		c, w := played[i], played[best]
+		switch {
+		case s.group[c] == s.group[w]:
+			if s.strength[c] > s.strength[w] {
+				best = i
+			}
+		case s.group[c] == trumpGroup:
+			best = i
+		}
+	}
+	return (leader + best) % 3
+}
+
+// index returns the index of a card (0-31).
+func index(c skat.Card) int {
+	return int(c.Suit)*8 + int(c.Rank)
+}
+
+// cardAt returns the card with the given index.
+func cardAt(i int) skat.Card {
+	return skat.NewCard(skat.Suit(i/8), skat.Rank(i%8))
+}
+
+// mask returns the bit mask of the cards.
+func mask(cards []skat.Card) uint32 {
+	var m uint32
+	for _, c := range cards {
+		m |= 1 << index(c)
+	}
+	return m
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package solver
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/ai"
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// positionAt replays the record up to (not including) the action with the given 1-based index.
+func positionAt(t *testing.T, record *skat.GameRecord, move int) *skat.Game {
+	t.Helper()
+
+	short := *record
+	short.Actions = record.Actions[:move-1]
+	game, err := short.Replay(nil)
+	if err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	return game
+}
+
+// bruteForce returns the value of the position by trying all legal cards without pruning.
+func bruteForce(game *skat.Game) int {
+	if game.State != skat.StateTrickPlaying {
+		return 0
+	}
+	player := *game.ActivePlayer()
+	declarer := *game.Declarer
+	tricks := len(game.Tricks)
+
+	best := 0
+	for i, card := range game.LegalMoves() {
+		next := cloneGame(game)
+		if err := next.PlayCard(player, card); err != nil {
+			panic(err)
+		}
+
+		value := 0
+		if len(next.Tricks) > tricks {
+			trick := next.Tricks[len(next.Tricks)-1]
+			if *trick.Winner == declarer {
+				if game.Contract.GameType.IsNull() {
+					value = nullLost
+				} else {
+					value = trick.Points()
+				}
+			}
+		}
+		if value != nullLost {
+			value += bruteForce(next)
+		}
+
+		if i == 0 || player == declarer && value > best || player != declarer && value < best {
+			best = value
+		}
+	}
+	return best
+}
+
+// cloneGame copies the parts of a game in trick playing that PlayCard changes.
+func cloneGame(game *skat.Game) *skat.Game {
+	clone := *game
+	clone.Hands = make(map[skat.Player]*skat.Hand)
+	for p, h := range game.Hands {
+		clone.Hands[p] = skat.NewHandFromCards(append([]skat.Card(nil), h.Cards...))
+	}
+	trick := *game.Trick
+	trick.Cards = append([]skat.TrickCard(nil), game.Trick.Cards...)
+	clone.Trick = &trick
+	clone.Tricks = append([]*skat.Trick(nil), game.Tricks...)
+	return &clone
+}
+
+// cardPoints returns the card points of the cards.
+func cardPoints(cards []skat.Card) int {
+	points := 0
+	for _, c := range cards {
+		points += c.Points()
+	}
+	return points
+}
+
+// ============================================================================
+// Solver Tests
+// ============================================================================
+
+func TestSolverMatchesBruteForce(t *testing.T) {
+	tested := 0
+	for seed := int64(1); seed <= 40 && tested < 12; seed++ {
+		record := recordtest.PlayedWith(t, seed, ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(seed))))
+		game, err := record.Replay(nil)
+		if err != nil {
+			t.Fatalf("Replay() error: %v", err)
+		}
+		if game.Contract.GameType.IsRamsch() {
+			continue
+		}
+
+		// Positions with three or four tricks left, in the middle of a trick
+		for _, move := range []int{len(record.Actions) - 8, len(record.Actions) - 10} {
+			position := positionAt(t, record, move)
+			if position.State != skat.StateTrickPlaying {
+				continue
+			}
+			s, err := New(position)
+			if err != nil {
+				t.Fatalf("New() error: %v", err)
+			}
+			got, err := s.Value(position)
+			if err != nil {
+				t.Fatalf("Value() error: %v", err)
+			}
+			if want := bruteForce(position); got != want {
+				t.Errorf("seed %d move %d (%s): Value() = %d, brute force %d", seed, move, position.Contract.Code(), got, want)
+			}
+			tested++
+		}
+	}
+	if tested == 0 {
+		t.Fatal("no positions tested")
+	}
+}
+
+func TestClaimHolds(t *testing.T) {
+	tested := 0
+	for seed := int64(1); seed <= 20; seed++ {
+		record := recordtest.PlayedWith(t, seed, ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(seed))))
+		position := positionAt(t, record, len(record.Actions)-5)
+		if position.State != skat.StateTrickPlaying || position.Contract.GameType.IsRamsch() {
+			continue
+		}
+
+		// Two tricks left: the claim holds if the declarer gets everything (Null: nothing)
+		want := bruteForce(position) == 0
+		if !position.Contract.GameType.IsNull() {
+			remaining := 0
+			for _, p := range skat.AllPlayers {
+				remaining += cardPoints(position.Hands[p].Cards)
+			}
+			want = bruteForce(position) == remaining
+		}
+		got, err := ClaimHolds(position)
+		if err != nil {
+			t.Fatalf("ClaimHolds() error: %v", err)
+		}
+		if got != want {
+			t.Errorf("seed %d (%s): ClaimHolds() = %t, want %t", seed, position.Contract.Code(), got, want)
+		}
+		tested++
+	}
+	if tested == 0 {
+		t.Fatal("no positions tested")
+	}
+}
+
+func TestSolverCompare(t *testing.T) {
+	record := recordtest.PlayedWith(t, 4, ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(4))))
+	position := positionAt(t, record, len(record.Actions)-10)
+	if position.State != skat.StateTrickPlaying {
+		t.Fatalf("state = %s, want trick playing", position.State)
+	}
+	s, err := New(position)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	value, err := s.Value(position)
+	if err != nil {
+		t.Fatalf("Value() error: %v", err)
+	}
+
+	for _, card := range position.LegalMoves() {
+		played, best, err := s.Compare(position, card)
+		if err != nil {
+			t.Fatalf("Compare(%s) error: %v", card.Code(), err)
+		}
+		if best.Value != value {
+			t.Errorf("Compare(%s) best = %d, want %d", card.Code(), best.Value, value)
+		}
+
+		next := cloneGame(position)
+		if err := next.PlayCard(*position.ActivePlayer(), card); err != nil {
+			t.Fatalf("PlayCard() error: %v", err)
+		}
+		want := bruteForce(next)
+		if len(next.Tricks) > len(position.Tricks) && *next.Tricks[len(next.Tricks)-1].Winner == *position.Declarer {
+			want += next.Tricks[len(next.Tricks)-1].Points()
+		}
+		if played.Value != want {
+			t.Errorf("Compare(%s) played = %d, brute force %d", card.Code(), played.Value, want)
+		}
+	}
+
+	for _, card := range position.Hands[*position.ActivePlayer()].Cards {
+		if !card.CanPlay(position.Trick.LeadCard(), position.Hands[*position.ActivePlayer()], position.Contract.GameType) {
+			if _, _, err := s.Compare(position, card); err == nil {
+				t.Errorf("Compare(%s) accepted an illegal card", card.Code())
+			}
+		}
+	}
+}
+
+func TestSolverRejectsRamsch(t *testing.T) {
+	game := skat.NewGame()
+	game.Contract = skat.NewContract(skat.GameRamsch)
+	game.State = skat.StateTrickPlaying
+	if _, err := New(game); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("New() error = %v, want ErrNotSupported", err)
+	}
+}
+
+// ============================================================================
+// Mistake Analysis Tests
+// ============================================================================
+
+func TestAnalyzeFindsMistakes(t *testing.T) {
+	// Random play makes mistakes, the strong AI far fewer
+	found := false
+	for seed := int64(1); seed <= 10 && !found; seed++ {
+		record := recordtest.PlayedWith(t, seed, ai.NewRandomAI(rand.New(rand.NewSource(seed))))
+		mistakes, err := Analyze(record, 10)
+		if errors.Is(err, ErrNotSupported) {
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Analyze() error: %v", err)
+		}
+
+		for _, m := range mistakes {
+			found = true
+			action := record.Actions[m.Move-1]
+			if action.Type != skat.ActionPlayCard || action.Cards[0] != m.Card || action.Player != m.Player {
+				t.Errorf("mistake %+v does not match action %+v", m, action)
+			}
+			if !m.GameLost && m.Loss < 10 {
+				t.Errorf("mistake %+v below the minimum loss", m)
+			}
+			if m.Best == m.Card {
+				t.Errorf("mistake %+v names the played card as best", m)
+			}
+		}
+	}
+	if !found {
+		t.Error("no mistakes found in random games")
+	}
+}
+
+func TestAnalyzeAccountsForResult(t *testing.T) {
+	// The solver value of the first card plus the losses of the defenders minus the
+	// losses of the declarer must be the points the declarer actually took
+	for _, seed := range []int64{1, 4} {
+		record := recordtest.PlayedWith(t, seed, ai.New(ai.DifficultyStrong, rand.New(rand.NewSource(seed))))
+		game, err := record.Replay(nil)
+		if err != nil {
+			t.Fatalf("Replay() error: %v", err)
+		}
+		if game.Result == nil || game.Contract.GameType.IsNull() {
+			continue
+		}
+
+		first := 0
+		for i, action := range record.Actions {
+			if action.Type == skat.ActionPlayCard {
+				first = i + 1
+				break
+			}
+		}
+		position := positionAt(t, record, first)
+		s, err := New(position)
+		if err != nil {
+			t.Fatalf("New() error: %v", err)
+		}
+		points, err := s.Value(position)
+		if err != nil {
+			t.Fatalf("Value() error: %v", err)
+		}
+
+		mistakes, err := Analyze(record, 1)
+		if err != nil {
+			t.Fatalf("Analyze() error: %v", err)
+		}
+		for _, m := range mistakes {
+			if m.Player == *game.Declarer {
+				points -= m.Loss
+			} else {
+				points += m.Loss
+			}
+		}
+		// The solver counts the points of the tricks, without the skat
+		if points != game.Result.TrickPoints {
+			t.Errorf("seed %d: solver accounts for %d points, declarer took %d", seed, points, game.Result.TrickPoints)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	mistakes := []Mistake{
+		{Move: 14, Player: skat.Middlehand, Card: skat.NewCard(skat.Hearts, skat.Ten), Best: skat.NewCard(skat.Spades, skat.Ace), Loss: 21},
+		{Move: 20, Player: skat.Forehand, Card: skat.NewCard(skat.Clubs, skat.Seven), Best: skat.NewCard(skat.Clubs, skat.Eight), GameLost: true},
+	}
+
+	lines := Report(mistakes, skat.Middlehand)
+	if len(lines) != 1 || lines[0] != "move 14: Middlehand played HT, SA was better (21 card points)" {
+		t.Errorf("Report(Middlehand) = %q", lines)
+	}
+	lines = Report(mistakes, skat.Forehand)
+	if len(lines) != 1 || !strings.HasSuffix(lines[0], "(loses the game)") {
+		t.Errorf("Report(Forehand) = %q", lines)
+	}
+	lines = Report(mistakes, skat.Rearhand)
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "no clear mistakes") {
+		t.Errorf("Report(Rearhand) = %q", lines)
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stats aggregates per-player statistics over finished games.
+package stats
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// TypeStats are the declarer games of one game type.
+type TypeStats struct {
+	Games int `json:"games"`
+	Won   int `json:"won"`
+}
+
+// PlayerStats are the aggregated counters of a player.
+type PlayerStats struct {
+	// Games is the number of finished games
+	Games int `json:"games"`
+	// DeclarerGames and DeclarerWins count the games as declarer
+	DeclarerGames int `json:"declarerGames"`
+	DeclarerWins  int `json:"declarerWins"`
+	// ByGameType are the declarer games by game type name (e.g. "Grand")
+	ByGameType map[string]*TypeStats `json:"byGameType"`
+	// GameValueSum is the sum of the game values as declarer, without Kontra and Re
+	GameValueSum int `json:"gameValueSum"`
+	// KontraGames and ReGames count the declarer games doubled by Kontra and redoubled by Re
+	KontraGames int `json:"kontraGames"`
+	ReGames     int `json:"reGames"`
+	// Kontras is the number of Kontras announced as defender
+	Kontras int `json:"kontras"`
+	// Overbids is the number of overbid declarer games
+	Overbids int `json:"overbids"`
+	// BiddingGames is the number of games with at least one bid or hold
+	BiddingGames int `json:"biddingGames"`
+	// HighestBidSum is the sum of the highest value bid or held in these games
+	HighestBidSum int `json:"highestBidSum"`
+	// PassedGames is the number of games passed without any bid or hold
+	PassedGames int `json:"passedGames"`
+	// RamschGames and RamschLosses count the Ramsch games
+	RamschGames  int `json:"ramschGames"`
+	RamschLosses int `json:"ramschLosses"`
+}
+
+// Report are the statistics of a player as rates and averages.
+type Report struct {
+	Name             string             `json:"name"`
+	Games            int                `json:"games"`
+	DeclarerGames    int                `json:"declarerGames"`
+	DeclarerWinRate  float64            `json:"declarerWinRate"`
+	WinRateByType    map[string]float64 `json:"winRateByGameType"`
+	AverageGameValue float64            `json:"averageGameValue"`
+	AverageBid       float64            `json:"averageBid"`
+	PassRate         float64            `json:"passRate"`
+	Overbids         int                `json:"overbids"`
+	KontraGames      int                `json:"kontraGames"`
+	ReGames          int                `json:"reGames"`
+	Kontras          int                `json:"kontras"`
+	RamschGames      int                `json:"ramschGames"`
+	RamschLosses     int                `json:"ramschLosses"`
+}
+
+// Report returns the rates and averages of the counters.
+func (s *PlayerStats) Report(name string) Report {
+	r := Report{
+		Name:             name,
+		Games:            s.Games,
+		DeclarerGames:    s.DeclarerGames,
+		DeclarerWinRate:  ratio(s.DeclarerWins, s.DeclarerGames),
+		WinRateByType:    make(map[string]float64, len(s.ByGameType)),
+		AverageGameValue: ratio(s.GameValueSum, s.DeclarerGames),
+		AverageBid:       ratio(s.HighestBidSum, s.BiddingGames),
+		PassRate:         ratio(s.PassedGames, s.Games),
+		Overbids:         s.Overbids,
+		KontraGames:      s.KontraGames,
+		ReGames:          s.ReGames,
+		Kontras:          s.Kontras,
+		RamschGames:      s.RamschGames,
+		RamschLosses:     s.RamschLosses,
+	}
+	for name, t := range s.ByGameType {
+		r.WinRateByType[name] = ratio(t.Won, t.Games)
+	}
+	return r
+}
+
+// ratio returns n/d (0 if d is 0).
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// clone returns a deep copy of the counters.
+func (s *PlayerStats) clone() *PlayerStats {
+	c := *s
+	c.ByGameType = make(map[string]*TypeStats, len(s.ByGameType))
+	for name, t := range s.ByGameType {
+		copied := *t
+		c.ByGameType[name] = &copied
+	}
+	return &c
+}
+
+// Collector caches the statistics of all players and updates them game by game.
+// It is safe for concurrent use.
+type Collector struct {
+	mu      sync.RWMutex
+	players map[string]*PlayerStats
+	games   map[string]bool
+}
+
+// NewCollector creates an empty collector.
+func NewCollector() *Collector {
+	return &Collector{
+		players: make(map[string]*PlayerStats),
+		games:   make(map[string]bool),
+	}
+}
+
+// Add adds a game. Unfinished games and games already added (by ID) are ignored.
+func (c *Collector) Add(record *skat.GameRecord) error {
+	highestBids := make(map[skat.Player]int)
+	passed := make(map[skat.Player]bool)
+	var kontra *skat.Player
+	game, err := record.Replay(func(game *skat.Game, action skat.Action) {
+		switch action.Type {
+		case skat.ActionBid:
+			highestBids[action.Player] = max(highestBids[action.Player], action.Value)
+		case skat.ActionHold:
+			highestBids[action.Player] = max(highestBids[action.Player], game.Bidding.CurrentBid)
+		case skat.ActionPass:
+			passed[action.Player] = true
+		case skat.ActionKontra:
+			p := action.Player
+			kontra = &p
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if game.Result == nil && game.RamschResult == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if record.ID != "" {
+		if c.games[record.ID] {
+			return nil
+		}
+		c.games[record.ID] = true
+	}
+
+	for _, p := range skat.AllPlayers {
+		s := c.player(record.Players[p])
+		s.Games++
+		if bid := highestBids[p]; bid > 0 {
+			s.BiddingGames++
+			s.HighestBidSum += bid
+		} else if passed[p] {
+			s.PassedGames++
+		}
+
+		if kontra != nil && *kontra == p {
+			s.Kontras++
+		}
+		if result := game.RamschResult; result != nil {
+			s.RamschGames++
+			if result.IsLoser(p) {
+				s.RamschLosses++
+			}
+		}
+		if result := game.Result; result != nil && result.Declarer == p {
+			s.DeclarerGames++
+			s.GameValueSum += result.BaseGameValue()
+			if result.Kontra {
+				s.KontraGames++
+			}
+			if result.Re {
+				s.ReGames++
+			}
+			t := s.ByGameType[result.Contract.GameType.String()]
+			if t == nil {
+				t = &TypeStats{}
+				s.ByGameType[result.Contract.GameType.String()] = t
+			}
+			t.Games++
+			if result.DeclarerWon {
+				s.DeclarerWins++
+				t.Won++
+			}
+			if result.Overbid {
+				s.Overbids++
+			}
+		}
+	}
+	return nil
+}
+
+// player returns the counters of a player, creating them if needed.
+func (c *Collector) player(name string) *PlayerStats {
+	s := c.players[name]
+	if s == nil {
+		s = &PlayerStats{ByGameType: make(map[string]*TypeStats)}
+		c.players[name] = s
+	}
+	return s
+}
+
+// Player returns a copy of the counters of a player (false if the player has no games).
+func (c *Collector) Player(name string) (*PlayerStats, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.players[name]
+	if !ok {
+		return nil, false
+	}
+	return s.clone(), true
+}
+
+// Players returns the names of all players in sorted order.
+func (c *Collector) Players() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.players))
+	for name := range c.players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Reports returns the reports of all players in sorted order.
+func (c *Collector) Reports() []Report {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	reports := make([]Report, 0, len(c.players))
+	for name, s := range c.players {
+		reports = append(reports, s.Report(name))
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Name < reports[j].Name
+	})
+	return reports
+}
+
+// WriteCSV writes reports as CSV, one row per player.
+func WriteCSV(w io.Writer, reports []Report) error {
+	cw := csv.NewWriter(w)
+	header := []string{"player", "games", "declarer", "declarerWinRate", "averageGameValue", "averageBid", "passRate", "overbids", "kontraGames", "reGames", "kontras", "ramschGames", "ramschLosses"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		row := []string{
+			r.Name,
+			strconv.Itoa(r.Games),
+			strconv.Itoa(r.DeclarerGames),
+			formatRate(r.DeclarerWinRate),
+			formatRate(r.AverageGameValue),
+			formatRate(r.AverageBid),
+			formatRate(r.PassRate),
+			strconv.Itoa(r.Overbids),
+			strconv.Itoa(r.KontraGames),
+			strconv.Itoa(r.ReGames),
+			strconv.Itoa(r.Kontras),
+			strconv.Itoa(r.RamschGames),
+			strconv.Itoa(r.RamschLosses),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatRate formats a rate or average with up to 4 decimals.
+func formatRate(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10000)/10000, 'f', -1, 64)
+}
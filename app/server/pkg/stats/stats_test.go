@@ -0,0 +1,160 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stats
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// ============================================================================
+// Collector Tests
+// ============================================================================
+
+func TestCollectorTotals(t *testing.T) {
+	c := NewCollector()
+	games := 30
+	for seed := int64(1); seed <= int64(games); seed++ {
+		if err := c.Add(recordtest.Played(t, seed)); err != nil {
+			t.Fatalf("Add() error: %v", err)
+		}
+	}
+	// Games are only counted once
+	if err := c.Add(recordtest.Played(t, 1)); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	declarerGames, ramschLosses := 0, 0
+	for _, name := range c.Players() {
+		s, _ := c.Player(name)
+		if s.Games != games {
+			t.Errorf("Player(%s).Games = %d, want %d", name, s.Games, games)
+		}
+		typeGames := 0
+		for _, ts := range s.ByGameType {
+			typeGames += ts.Games
+		}
+		if typeGames != s.DeclarerGames {
+			t.Errorf("Player(%s) game types sum to %d, want %d", name, typeGames, s.DeclarerGames)
+		}
+		if s.BiddingGames+s.PassedGames > s.Games {
+			t.Errorf("Player(%s) bidding %d + passed %d > games %d", name, s.BiddingGames, s.PassedGames, s.Games)
+		}
+		declarerGames += s.DeclarerGames
+		ramschLosses += s.RamschLosses
+	}
+	if declarerGames+ramschLosses != games {
+		t.Errorf("declarer games %d + Ramsch losses %d = %d, want %d", declarerGames, ramschLosses, declarerGames+ramschLosses, games)
+	}
+}
+
+func TestCollectorBidding(t *testing.T) {
+	record := &skat.GameRecord{
+		ID:      "bidding",
+		Players: map[skat.Player]string{skat.Forehand: "anna", skat.Middlehand: "ben", skat.Rearhand: "carl"},
+	}
+	var err error
+	hands := map[skat.Player]string{
+		skat.Forehand:   "CJ.SJ.HJ.DJ.CA.CT.CK.CQ.C9.C8",
+		skat.Middlehand: "SA.ST.SK.SQ.S9.S8.S7.HA.HT.HK",
+		skat.Rearhand:   "HQ.H9.H8.H7.DA.DT.DK.DQ.D9.D8",
+	}
+	record.Hands = make(map[skat.Player]*skat.Hand)
+	for p, code := range hands {
+		if record.Hands[p], err = skat.HandFromCode(code); err != nil {
+			t.Fatalf("HandFromCode() error: %v", err)
+		}
+	}
+	if record.Skat, err = skat.HandFromCode("C7.D7"); err != nil {
+		t.Fatalf("HandFromCode() error: %v", err)
+	}
+
+	// Middlehand bids 18 and 20, Forehand holds both, Rearhand passes
+	record.Actions = []skat.Action{
+		{Player: skat.Middlehand, Type: skat.ActionBid, Value: 18},
+		{Player: skat.Forehand, Type: skat.ActionHold},
+		{Player: skat.Middlehand, Type: skat.ActionBid, Value: 20},
+		{Player: skat.Forehand, Type: skat.ActionHold},
+		{Player: skat.Middlehand, Type: skat.ActionPass},
+		{Player: skat.Rearhand, Type: skat.ActionPass},
+		{Player: skat.Forehand, Type: skat.ActionAnnounce, Contract: &skat.Contract{GameType: skat.GameClubs, Hand: true}},
+	}
+	for trick := 0; trick < 10; trick++ {
+		for _, p := range []skat.Player{skat.Forehand, skat.Middlehand, skat.Rearhand} {
+			record.Actions = append(record.Actions, skat.Action{Player: p, Type: skat.ActionPlayCard, Cards: []skat.Card{record.Hands[p].Cards[trick]}})
+		}
+	}
+
+	c := NewCollector()
+	if err := c.Add(record); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	anna, _ := c.Player("anna")
+	if anna.HighestBidSum != 20 || anna.DeclarerWins != 1 || anna.ByGameType["Clubs"].Won != 1 {
+		t.Errorf("Player(anna) = bid %d, wins %d, want 20, 1", anna.HighestBidSum, anna.DeclarerWins)
+	}
+	ben, _ := c.Player("ben")
+	if ben.HighestBidSum != 20 || ben.PassedGames != 0 {
+		t.Errorf("Player(ben) = bid %d, passed %d, want 20, 0", ben.HighestBidSum, ben.PassedGames)
+	}
+	carl, _ := c.Player("carl")
+	if carl.PassedGames != 1 || carl.Report("carl").PassRate != 1 {
+		t.Errorf("Player(carl) = passed %d, want 1", carl.PassedGames)
+	}
+
+	// The same game with Kontra and Re counts with its value without them
+	value := anna.GameValueSum
+	doubled := *record
+	doubled.ID = "kontra"
+	doubled.Actions = append(append(append([]skat.Action(nil), record.Actions[:7]...),
+		skat.Action{Player: skat.Middlehand, Type: skat.ActionKontra},
+		skat.Action{Player: skat.Forehand, Type: skat.ActionRe}), record.Actions[7:]...)
+	if err := c.Add(&doubled); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	anna, _ = c.Player("anna")
+	if anna.KontraGames != 1 || anna.ReGames != 1 || anna.GameValueSum != 2*value {
+		t.Errorf("Player(anna) = kontra %d, re %d, value sum %d, want 1, 1, %d", anna.KontraGames, anna.ReGames, anna.GameValueSum, 2*value)
+	}
+	if ben, _ = c.Player("ben"); ben.Kontras != 1 {
+		t.Errorf("Player(ben) = %d kontras, want 1", ben.Kontras)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	c := NewCollector()
+	for seed := int64(1); seed <= 5; seed++ {
+		if err := c.Add(recordtest.Played(t, seed)); err != nil {
+			t.Fatalf("Add() error: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, c.Reports()); err != nil {
+		t.Fatalf("WriteCSV() error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	if len(rows) != 4 || rows[1][0] != "anna" || rows[1][1] != "5" {
+		t.Errorf("WriteCSV() = %v", rows)
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package training exports archived games as machine learning training data.
+//
+// Every player decision becomes one JSON line (see docs/TRAINING-DATA.md).
+package training
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// Decision is a single player decision with everything the player could see.
+type Decision struct {
+	GameID     string    `json:"gameId"`
+	Date       time.Time `json:"date"`
+	Index      int       `json:"index"`
+	Player     string    `json:"player"`
+	Position   int       `json:"position"`
+	Phase      string    `json:"phase"`
+	Hand       []string  `json:"hand"`
+	CurrentBid int       `json:"currentBid"`
+	Declarer   *int      `json:"declarer"`
+	Contract   string    `json:"contract,omitempty"`
+	Skat       []string  `json:"skat"`
+	Played     []string  `json:"played"`
+	Trick      []string  `json:"trick"`
+	Hidden     []string  `json:"hidden"`
+	LegalMoves []string  `json:"legalMoves"`
+	Move       string    `json:"move"`
+	Outcome    Outcome   `json:"outcome"`
+}
+
+// Outcome is the final outcome of the game from the deciding player's point of view.
+type Outcome struct {
+	// GameType is the game type that was played
+	GameType string `json:"gameType"`
+	// DeclarerWon is true if the declarer won (false in Ramsch)
+	DeclarerWon bool `json:"declarerWon"`
+	// Won is true if the deciding player's side won
+	Won bool `json:"won"`
+	// Score is the score of the deciding player
+	Score int `json:"score"`
+}
+
+// Filter selects the games and players to export.
+type Filter struct {
+	// From is the earliest game start to export (zero = no limit)
+	From time.Time
+	// To is the exclusive latest game start to export (zero = no limit)
+	To time.Time
+	// Players restricts the export to games with and decisions by these players (empty = all)
+	Players []string
+}
+
+// matchesGame returns true if the record is selected by the filter.
+func (f Filter) matchesGame(record *skat.GameRecord) bool {
+	if !f.From.IsZero() && record.StartedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !record.StartedAt.Before(f.To) {
+		return false
+	}
+	if len(f.Players) == 0 {
+		return true
+	}
+	for _, p := range skat.AllPlayers {
+		if f.matchesPlayer(record.Players[p]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPlayer returns true if decisions of the named player are selected.
+func (f Filter) matchesPlayer(name string) bool {
+	if len(f.Players) == 0 {
+		return true
+	}
+	for _, p := range f.Players {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Export writes the decisions of all selected records as JSON lines and
+// returns the number of decisions written.
+func Export(w io.Writer, records []*skat.GameRecord, filter Filter) (int, error) {
+	encoder := json.NewEncoder(w)
+	count := 0
+
+	for _, record := range records {
+		if !filter.matchesGame(record) {
+			continue
+		}
+
+		decisions, err := Decisions(record)
+		if err != nil {
+			return count, fmt.Errorf("game %s: %w", record.ID, err)
+		}
+		for _, d := range decisions {
+			if !filter.matchesPlayer(d.Player) {
+				continue
+			}
+			if err := encoder.Encode(d); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Decisions replays a record and returns one decision per player action.
+func Decisions(record *skat.GameRecord) ([]Decision, error) {
+	decisions := make([]Decision, 0, len(record.Actions))
+
+	game, err := record.Replay(func(game *skat.Game, action skat.Action) {
+		decisions = append(decisions, newDecision(record, game, action, len(decisions)))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range decisions {
+		decisions[i].Outcome = outcome(game, skat.Player(decisions[i].Position))
+	}
+	return decisions, nil
+}
+
+// newDecision creates the decision record for an action taken in the given game state.
+func newDecision(record *skat.GameRecord, game *skat.Game, action skat.Action, index int) Decision {
+	player := action.Player
+	d := Decision{
+		GameID:     record.ID,
+		Date:       record.StartedAt,
+		Index:      index,
+		Player:     record.Players[player],
+		Position:   player.Index(),
+		Phase:      game.State.String(),
+		Hand:       codes(game.Hands[player].Cards),
+		CurrentBid: game.Bidding.CurrentBid,
+		Skat:       []string{},
+		Played:     []string{},
+		Trick:      []string{},
+		LegalMoves: legalMoves(game),
+		Move:       moveCode(action),
+	}
+
+	if game.Declarer != nil {
+		declarer := game.Declarer.Index()
+		d.Declarer = &declarer
+	}
+	if game.Contract != nil {
+		d.Contract = game.Contract.Code()
+	}
+
+	visible := make(map[skat.Card]bool)
+	for _, c := range game.Hands[player].Cards {
+		visible[c] = true
+	}
+	for _, t := range game.Tricks {
+		for _, tc := range t.Cards {
+			d.Played = append(d.Played, tc.Card.Code())
+			visible[tc.Card] = true
+		}
+	}
+	if game.Trick != nil {
+		for _, tc := range game.Trick.Cards {
+			d.Trick = append(d.Trick, tc.Card.Code())
+			visible[tc.Card] = true
+		}
+	}
+	// The declarer knows the skat after picking it up
+	if game.SkatPickedUp && game.Declarer != nil && *game.Declarer == player {
+		d.Skat = codes(game.Skat.Cards)
+		for _, c := range game.Skat.Cards {
+			visible[c] = true
+		}
+	}
+	// Open hands are visible to everybody
+	if game.Contract != nil && game.Contract.Ouvert && game.Declarer != nil {
+		for _, c := range game.Hands[*game.Declarer].Cards {
+			visible[c] = true
+		}
+	}
+
+	d.Hidden = []string{}
+	for _, c := range skat.NewDeck().Cards {
+		if !visible[c] {
+			d.Hidden = append(d.Hidden, c.Code())
+		}
+	}
+	return d
+}
+
+// legalMoves returns the codes of all moves the active player may choose from.
+func legalMoves(game *skat.Game) []string {
+	switch game.State {
+	case skat.StateBidding:
+		if !game.Bidding.IsActiveBidding {
+			return []string{"y", "p"}
+		}
+		moves := []string{"p"}
+		for _, v := range skat.BidOrder {
+			if v >= game.Bidding.MinimumBid() {
+				moves = append(moves, strconv.Itoa(v))
+			}
+		}
+		return moves
+	case skat.StatePickingUpSkat:
+		return append([]string{"s"}, contractCodes(true)...)
+	case skat.StateDiscarding:
+		cards := game.Hands[*game.Declarer].Cards
+		moves := make([]string, 0, len(cards)*(len(cards)-1)/2)
+		for i := range cards {
+			for j := i + 1; j < len(cards); j++ {
+				moves = append(moves, cards[i].Code()+"."+cards[j].Code())
+			}
+		}
+		return moves
+	case skat.StateDeclaring:
+		return contractCodes(false)
+	case skat.StateTrickPlaying:
+		return codes(game.LegalMoves())
+	default:
+		return []string{}
+	}
+}
+
+// contractCodes returns the codes of all contracts that can be announced.
+func contractCodes(hand bool) []string {
+	codes := make([]string, 0, 24)
+	for _, gameType := range skat.AllGameTypes {
+		contract := skat.NewContract(gameType)
+		contract.Hand = hand
+		codes = append(codes, contract.Code())
+
+		if gameType.IsNull() {
+			contract.Ouvert = true
+			codes = append(codes, contract.Code())
+			continue
+		}
+		if !hand {
+			continue
+		}
+		contract.Schneider = true
+		codes = append(codes, contract.Code())
+		contract.Schwarz = true
+		codes = append(codes, contract.Code())
+		contract.Ouvert = true
+		codes = append(codes, contract.Code())
+	}
+	return codes
+}
+
+// moveCode returns the code of the chosen move.
+func moveCode(action skat.Action) string {
+	switch action.Type {
+	case skat.ActionBid:
+		return strconv.Itoa(action.Value)
+	case skat.ActionHold:
+		return "y"
+	case skat.ActionPass:
+		return "p"
+	case skat.ActionPickUpSkat:
+		return "s"
+	case skat.ActionAnnounce:
+		return action.Contract.Code()
+	default:
+		return skat.NewHandFromCards(action.Cards).Code()
+	}
+}
+
+// outcome returns the outcome of a finished game for the given player.
+func outcome(game *skat.Game, player skat.Player) Outcome {
+	o := Outcome{}
+	if game.Contract != nil {
+		o.GameType = game.Contract.GameType.String()
+	}
+
+	switch {
+	case game.Result != nil:
+		o.DeclarerWon = game.Result.DeclarerWon
+		if *game.Declarer == player {
+			o.Won = o.DeclarerWon
+			o.Score = game.Result.Score
+		} else {
+			o.Won = !o.DeclarerWon
+		}
+	case game.RamschResult != nil && game.RamschResult.Durchmarsch:
+		o.Won = *game.RamschResult.DurchmarschPlayer == player
+		o.Score = game.RamschResult.Score(player)
+	case game.RamschResult != nil:
+		o.Won = !game.RamschResult.IsLoser(player)
+		o.Score = game.RamschResult.Score(player)
+	}
+	return o
+}
+
+// codes returns the codes of the given cards.
+func codes(cards []skat.Card) []string {
+	result := make([]string, len(cards))
+	for i, c := range cards {
+		result[i] = c.Code()
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package training
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/freeskat-server/pkg/recordtest"
+	"github.com/mkloubert/freeskat-server/pkg/skat"
+)
+
+// ============================================================================
+// Decision Tests
+// ============================================================================
+
+func TestDecisions(t *testing.T) {
+	record := recordtest.Grand(t, "g1", time.Now(), false)
+
+	decisions, err := Decisions(record)
+	if err != nil {
+		t.Fatalf("Decisions() error: %v", err)
+	}
+	if len(decisions) != len(record.Actions) {
+		t.Fatalf("len(Decisions()) = %d, want %d", len(decisions), len(record.Actions))
+	}
+
+	for _, d := range decisions {
+		found := false
+		for _, m := range d.LegalMoves {
+			found = found || m == d.Move
+		}
+		if !found {
+			t.Errorf("decision %d: move %s not in legal moves %v", d.Index, d.Move, d.LegalMoves)
+		}
+
+		// Every card is either visible to the player or hidden
+		visible := len(d.Hand) + len(d.Skat) + len(d.Played) + len(d.Trick)
+		if visible+len(d.Hidden) != 32 {
+			t.Errorf("decision %d: %d visible + %d hidden cards, want 32", d.Index, visible, len(d.Hidden))
+		}
+	}
+
+	last := decisions[len(decisions)-1]
+	if last.Outcome.GameType != skat.GameGrand.String() {
+		t.Errorf("Outcome.GameType = %s, want %s", last.Outcome.GameType, skat.GameGrand)
+	}
+}
+
+// ============================================================================
+// Export Tests
+// ============================================================================
+
+func TestExportFilter(t *testing.T) {
+	day := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	records := []*skat.GameRecord{
+		recordtest.Grand(t, "g1", day, false),
+		recordtest.Grand(t, "g2", day.AddDate(0, 0, 7), false),
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"all", Filter{}, 2 * len(records[0].Actions)},
+		{"date range", Filter{From: day, To: day.AddDate(0, 0, 1)}, len(records[0].Actions)},
+		{"unknown player", Filter{Players: []string{"dora"}}, 0},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		count, err := Export(&buf, records, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: Export() error: %v", tt.name, err)
+		}
+		if count != tt.want {
+			t.Errorf("%s: Export() = %d, want %d", tt.name, count, tt.want)
+		}
+	}
+
+	// Only decisions of the selected player are written
+	var buf bytes.Buffer
+	if _, err := Export(&buf, records[:1], Filter{Players: []string{"ben"}}); err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var d Decision
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			t.Fatalf("invalid JSON line: %v", err)
+		}
+		if d.Player != "ben" {
+			t.Errorf("Player = %s, want ben", d.Player)
+		}
+	}
+}